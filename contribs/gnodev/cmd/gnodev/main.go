@@ -31,7 +31,10 @@ const (
 	AccountsLogName    = "Accounts"
 )
 
-var ErrConflictingFileArgs = errors.New("cannot specify `balances-file` or `txs-file` along with `genesis-file`")
+var (
+	ErrConflictingFileArgs = errors.New("cannot specify `balances-file` or `txs-file` along with `genesis-file`")
+	ErrConflictingForkArgs = errors.New("cannot specify `genesis-file` along with `fork-remote`")
+)
 
 var (
 	DefaultDeployerName    = integration.DefaultAccount_Name
@@ -60,6 +63,10 @@ type devCfg struct {
 	webListenerAddr     string
 	webRemoteHelperAddr string
 
+	// Fork Configuration
+	forkRemote string
+	forkHeight int64
+
 	// Node Configuration
 	minimal    bool
 	verbose    bool
@@ -182,6 +189,20 @@ func (c *devCfg) RegisterFlags(fs *flag.FlagSet) {
 		"do not load packages from the examples directory",
 	)
 
+	fs.StringVar(
+		&c.forkRemote,
+		"fork-remote",
+		defaultDevOptions.forkRemote,
+		"fork the chain served by the given RPC remote, fetching its packages and balances when first needed (implies -minimal)",
+	)
+
+	fs.Int64Var(
+		&c.forkHeight,
+		"fork-height",
+		defaultDevOptions.forkHeight,
+		"height of the remote state to fork, 0 for the latest height",
+	)
+
 	fs.BoolVar(
 		&c.serverMode,
 		"server-mode",
@@ -237,6 +258,10 @@ func (c *devCfg) validateConfigFlags() error {
 		return ErrConflictingFileArgs
 	}
 
+	if c.forkRemote != "" && c.genesisFile != "" {
+		return ErrConflictingForkArgs
+	}
+
 	return nil
 }
 
@@ -542,8 +567,9 @@ func resolvePackagesPathFromArgs(cfg *devCfg, bk *address.Book, args []string) (
 		paths = append(paths, path)
 	}
 
-	// Add examples folder if minimal is set to false
-	if !cfg.minimal {
+	// Add examples folder if minimal is set to false; when forking,
+	// the examples are fetched from the remote instead.
+	if !cfg.minimal && cfg.forkRemote == "" {
 		paths = append(paths, gnodev.PackagePath{
 			Path:    filepath.Join(cfg.root, "examples"),
 			Creator: defaultKey,
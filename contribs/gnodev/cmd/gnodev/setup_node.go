@@ -10,6 +10,7 @@ import (
 	gnodev "github.com/gnolang/gno/contribs/gnodev/pkg/dev"
 	"github.com/gnolang/gno/contribs/gnodev/pkg/emitter"
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 )
 
@@ -40,6 +41,16 @@ func setupDevNode(
 		logger.Info("genesis file loaded", "path", devCfg.genesisFile, "txs", len(nodeConfig.InitialTxs))
 	}
 
+	if devCfg.forkRemote != "" { // Fork remote chain
+		cli, err := client.NewHTTPClient(devCfg.forkRemote)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create fork remote client: %w", err)
+		}
+
+		nodeConfig.Fork = gnodev.NewFork(logger, cli, devCfg.forkHeight)
+		logger.Info("forking remote", "remote", devCfg.forkRemote, "height", devCfg.forkHeight)
+	}
+
 	return gnodev.NewDevNode(ctx, nodeConfig)
 }
 
@@ -0,0 +1,197 @@
+package dev
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"strings"
+	"sync"
+
+	vmm "github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	forkQueryFile    = "vm/qfile"
+	forkQueryPkgInfo = "vm/qpkginfo"
+	forkQueryBalance = "bank/balances/"
+)
+
+type forkPackage struct {
+	memPkg  *std.MemPackage
+	creator crypto.Address
+}
+
+// Fork fetches the packages and the balances missing from the state of a
+// dev node from a remote chain, at a given height. The results are cached,
+// so that each package and balance is queried at most once.
+type Fork struct {
+	logger *slog.Logger
+	client client.Client
+	height int64 // 0 for the latest height
+
+	mu    sync.Mutex
+	pkgs  map[string]forkPackage
+	coins map[crypto.Address]std.Coins
+}
+
+// NewFork returns a Fork fetching the state at height from the chain
+// served by cli, or its latest state if height is 0.
+func NewFork(logger *slog.Logger, cli client.Client, height int64) *Fork {
+	return &Fork{
+		logger: logger,
+		client: cli,
+		height: height,
+		pkgs:   make(map[string]forkPackage),
+		coins:  make(map[crypto.Address]std.Coins),
+	}
+}
+
+// Height returns the height of the remote state, 0 for the latest one.
+func (f *Fork) Height() int64 {
+	return f.height
+}
+
+// FetchPackage returns the remote package at pkgPath and its creator,
+// or a nil package if it does not exist remotely.
+func (f *Fork) FetchPackage(pkgPath string) (*std.MemPackage, crypto.Address, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if pkg, ok := f.pkgs[pkgPath]; ok {
+		return pkg.memPkg, pkg.creator, nil
+	}
+
+	memPkg, err := f.fetchMemPackage(pkgPath)
+	if err != nil {
+		return nil, crypto.Address{}, err
+	}
+
+	var creator crypto.Address
+	if memPkg != nil {
+		// Packages added before the package metadata was recorded have
+		// no creator, leave it empty.
+		bz, found, err := f.query(forkQueryPkgInfo, []byte(pkgPath))
+		if err != nil {
+			return nil, crypto.Address{}, err
+		}
+		var info vmm.PackageInfo
+		if found && amino.UnmarshalJSON(bz, &info) == nil {
+			creator = info.Creator
+		}
+		f.logger.Info("fetched remote package", "path", pkgPath, "files", len(memPkg.Files))
+	}
+
+	f.pkgs[pkgPath] = forkPackage{memPkg: memPkg, creator: creator}
+	return memPkg, creator, nil
+}
+
+// FetchCoins returns the remote balance of addr.
+func (f *Fork) FetchCoins(addr crypto.Address) (std.Coins, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if coins, ok := f.coins[addr]; ok {
+		return coins, nil
+	}
+
+	bz, found, err := f.query(forkQueryBalance+addr.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var coins std.Coins
+	if found {
+		if err := amino.UnmarshalJSON(bz, &coins); err != nil {
+			return nil, fmt.Errorf("unable to decode balance of %s: %w", addr, err)
+		}
+		f.logger.Info("fetched remote balance", "address", addr, "coins", coins.String())
+	}
+
+	f.coins[addr] = coins
+	return coins, nil
+}
+
+func (f *Fork) fetchMemPackage(pkgPath string) (*std.MemPackage, error) {
+	bz, found, err := f.query(forkQueryFile, []byte(pkgPath))
+	if err != nil || !found || len(bz) == 0 {
+		return nil, err
+	}
+
+	memPkg := &std.MemPackage{Path: pkgPath}
+	for _, name := range strings.Split(string(bz), "\n") {
+		body, found, err := f.query(forkQueryFile, []byte(pkgPath+"/"+name))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("file %q of remote package %q not found", name, pkgPath)
+		}
+		memPkg.Files = append(memPkg.Files, &std.MemFile{Name: name, Body: string(body)})
+	}
+
+	memPkg.Name = packageName(memPkg)
+	if memPkg.Name == "" {
+		return nil, fmt.Errorf("unable to find the name of remote package %q", pkgPath)
+	}
+	return memPkg, nil
+}
+
+// query runs an ABCI query on the remote chain at the fork height. A query
+// returning an error is reported as not found.
+func (f *Fork) query(path string, data []byte) ([]byte, bool, error) {
+	res, err := f.client.ABCIQueryWithOptions(path, data, client.ABCIQueryOptions{Height: f.height})
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to query %q on remote: %w", path, err)
+	}
+	if res.Response.Error != nil {
+		return nil, false, nil
+	}
+	return res.Response.Data, true, nil
+}
+
+// packageName returns the name declared by the non-test files of memPkg.
+func packageName(memPkg *std.MemPackage) string {
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), mfile.Name, mfile.Body, parser.PackageClauseOnly)
+		if err == nil {
+			return f.Name.Name
+		}
+	}
+	return ""
+}
+
+// workspaceFetcher fetches the workspace packages before the remote ones, so
+// that the workspace packages override the remote ones even when they are
+// imported by a remote package, before being added from the genesis.
+type workspaceFetcher struct {
+	*Fork
+	pkgs map[string]Package // module path -> package
+}
+
+func newWorkspaceFetcher(fork *Fork, pm PackagesMap) *workspaceFetcher {
+	pkgs := make(map[string]Package, len(pm))
+	for _, pkg := range pm {
+		if !pkg.Draft {
+			pkgs[pkg.Name] = pkg
+		}
+	}
+	return &workspaceFetcher{Fork: fork, pkgs: pkgs}
+}
+
+func (wf *workspaceFetcher) FetchPackage(pkgPath string) (*std.MemPackage, crypto.Address, error) {
+	if pkg, ok := wf.pkgs[pkgPath]; ok {
+		return gno.ReadMemPackage(pkg.Dir, pkg.Name), pkg.Creator, nil
+	}
+	return wf.Fork.FetchPackage(pkgPath)
+}
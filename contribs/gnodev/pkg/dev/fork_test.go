@@ -0,0 +1,225 @@
+package dev
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRemote serves the packages and the balances of a remote chain.
+type mockRemote struct {
+	client.Client // unimplemented methods panic
+
+	pkgs     map[string]map[string]string // path -> file name -> body
+	creators map[string]crypto.Address
+	balances map[crypto.Address]std.Coins
+
+	mu      sync.Mutex
+	queries []string
+	heights []int64
+}
+
+func (r *mockRemote) ABCIQueryWithOptions(path string, data []byte, opts client.ABCIQueryOptions) (*core_types.ResultABCIQuery, error) {
+	r.mu.Lock()
+	r.queries = append(r.queries, path+":"+string(data))
+	r.heights = append(r.heights, opts.Height)
+	r.mu.Unlock()
+
+	notFound := &core_types.ResultABCIQuery{Response: abci.ResponseQuery{
+		ResponseBase: abci.ResponseBase{Error: abci.StringError("not found")},
+	}}
+	found := func(bz []byte) *core_types.ResultABCIQuery {
+		return &core_types.ResultABCIQuery{Response: abci.ResponseQuery{
+			ResponseBase: abci.ResponseBase{Data: bz},
+		}}
+	}
+
+	switch {
+	case path == forkQueryFile:
+		dir, file := std.SplitFilepath(string(data))
+		files, ok := r.pkgs[dir]
+		if !ok {
+			return notFound, nil
+		}
+		if file == "" {
+			names := make([]string, 0, len(files))
+			for name := range files {
+				names = append(names, name)
+			}
+			return found([]byte(strings.Join(names, "\n"))), nil
+		}
+		body, ok := files[file]
+		if !ok {
+			return notFound, nil
+		}
+		return found([]byte(body)), nil
+	case path == forkQueryPkgInfo:
+		creator, ok := r.creators[string(data)]
+		if !ok {
+			return notFound, nil
+		}
+		info := vm.PackageInfo{Path: string(data), Creator: creator}
+		return found([]byte(info.JSON())), nil
+	case strings.HasPrefix(path, forkQueryBalance):
+		addr := crypto.MustAddressFromString(strings.TrimPrefix(path, forkQueryBalance))
+		return found(amino.MustMarshalJSON(r.balances[addr])), nil
+	}
+	return notFound, nil
+}
+
+func newMockRemote() *mockRemote {
+	return &mockRemote{
+		pkgs: map[string]map[string]string{
+			"gno.land/p/remote/greet": {
+				"greet.gno": "package greet\n\nfunc Hello(name string) string { return \"hello \" + name }\n",
+			},
+			"gno.land/r/remote/counter": {
+				"counter.gno": `package counter
+
+import "gno.land/p/remote/greet"
+
+var count int
+
+func Incr() { count++ }
+
+func Render(_ string) string { return greet.Hello("remote") + " " + string(rune('0'+count)) }
+`,
+				"counter_test.gno": "package counter\n",
+			},
+			"gno.land/r/dev/foo": {
+				"foo.gno": "package foo\n\nfunc Render(_ string) string { return \"remote foo\" }\n",
+			},
+		},
+		creators: map[string]crypto.Address{
+			"gno.land/r/remote/counter": crypto.AddressFromPreimage([]byte("remote creator")),
+		},
+		balances: map[crypto.Address]std.Coins{
+			crypto.AddressFromPreimage([]byte("remote account")): std.NewCoins(std.NewCoin(ugnot.Denom, 42)),
+		},
+	}
+}
+
+func TestFork_FetchPackage(t *testing.T) {
+	remote := newMockRemote()
+	fork := NewFork(log.NewTestingLogger(t), remote, 42)
+
+	memPkg, creator, err := fork.FetchPackage("gno.land/r/remote/counter")
+	require.NoError(t, err)
+	require.NotNil(t, memPkg)
+	assert.Equal(t, "counter", memPkg.Name)
+	assert.Equal(t, "gno.land/r/remote/counter", memPkg.Path)
+	assert.Len(t, memPkg.Files, 2)
+	assert.Equal(t, remote.creators["gno.land/r/remote/counter"], creator)
+
+	// Package without metadata.
+	memPkg, creator, err = fork.FetchPackage("gno.land/p/remote/greet")
+	require.NoError(t, err)
+	require.NotNil(t, memPkg)
+	assert.True(t, creator.IsZero())
+
+	// Missing package.
+	memPkg, _, err = fork.FetchPackage("gno.land/r/remote/missing")
+	require.NoError(t, err)
+	assert.Nil(t, memPkg)
+
+	// Results are cached, and queried at the fork height.
+	nqueries := len(remote.queries)
+	for _, path := range []string{"gno.land/r/remote/counter", "gno.land/r/remote/missing"} {
+		_, _, err = fork.FetchPackage(path)
+		require.NoError(t, err)
+	}
+	assert.Len(t, remote.queries, nqueries)
+	for _, height := range remote.heights {
+		assert.Equal(t, int64(42), height)
+	}
+}
+
+func TestFork_FetchCoins(t *testing.T) {
+	remote := newMockRemote()
+	fork := NewFork(log.NewTestingLogger(t), remote, 0)
+
+	addr := crypto.AddressFromPreimage([]byte("remote account"))
+	coins, err := fork.FetchCoins(addr)
+	require.NoError(t, err)
+	assert.Equal(t, remote.balances[addr], coins)
+
+	coins, err = fork.FetchCoins(crypto.AddressFromPreimage([]byte("unknown")))
+	require.NoError(t, err)
+	assert.True(t, coins.IsZero())
+
+	_, err = fork.FetchCoins(addr)
+	require.NoError(t, err)
+	assert.Len(t, remote.queries, 2)
+}
+
+func TestNodeFork(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const (
+		// local package overriding the remote one, and importing a remote realm
+		fooGnoMod = "module gno.land/r/dev/foo\n\nrequire gno.land/r/remote/counter v0.0.0-latest\n"
+		fooFile   = `package foo
+
+import "gno.land/r/remote/counter"
+
+func Render(path string) string { return "local foo, " + counter.Render(path) }
+`
+	)
+
+	remote := newMockRemote()
+	foopkg := generateTestingPackage(t, "gno.mod", fooGnoMod, "foo.gno", fooFile)
+
+	cfg := DefaultNodeConfig(gnoenv.RootDir())
+	cfg.PackagesPathList = []PackagePath{foopkg}
+	cfg.Logger = log.NewTestingLogger(t)
+	cfg.Fork = NewFork(cfg.Logger, remote, 0)
+	node, err := NewDevNode(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { node.Close() })
+
+	// The local package overrides the remote one, and its remote
+	// dependencies are fetched.
+	render, err := testingRenderRealm(t, node, "gno.land/r/dev/foo")
+	require.NoError(t, err)
+	assert.Equal(t, "local foo, hello remote 0", render)
+
+	// Calls to remote realms are executed locally.
+	_, err = testingCallRealm(t, node, vm.MsgCall{
+		PkgPath: "gno.land/r/remote/counter",
+		Func:    "Incr",
+	})
+	require.NoError(t, err)
+	render, err = testingRenderRealm(t, node, "gno.land/r/remote/counter")
+	require.NoError(t, err)
+	assert.Equal(t, "hello remote 1", render)
+
+	// The local state is kept on reload.
+	require.NoError(t, node.Reload(ctx))
+	render, err = testingRenderRealm(t, node, "gno.land/r/remote/counter")
+	require.NoError(t, err)
+	assert.Equal(t, "hello remote 1", render)
+
+	// Remote balances are fetched.
+	addr := crypto.AddressFromPreimage([]byte("remote account"))
+	res, err := node.Client().ABCIQuery(forkQueryBalance+addr.String(), nil)
+	require.NoError(t, err)
+	require.NoError(t, res.Response.Error)
+	var coins std.Coins
+	require.NoError(t, amino.UnmarshalJSON(res.Response.Data, &coins))
+	assert.Equal(t, remote.balances[addr], coins)
+}
@@ -2,6 +2,7 @@ package dev
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gno.land/pkg/integration"
+	vmm "github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/gnovm/pkg/gnomod"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	tmcfg "github.com/gnolang/gno/tm2/pkg/bft/config"
@@ -41,6 +43,11 @@ type NodeConfig struct {
 	NoReplay              bool
 	MaxGasPerBlock        int64
 	ChainID               string
+
+	// Fork, if set, fetches the packages and the balances missing from
+	// the local state from a remote chain. The workspace packages and the
+	// listed balances override the remote ones.
+	Fork *Fork
 }
 
 func DefaultNodeConfig(rootdir string) *NodeConfig {
@@ -96,7 +103,7 @@ func NewDevNode(ctx context.Context, cfg *NodeConfig) (*Node, error) {
 		return nil, fmt.Errorf("unable map pkgs list: %w", err)
 	}
 
-	pkgsTxs, err := mpkgs.Load(DefaultFee)
+	pkgsTxs, err := mpkgs.load(DefaultFee, cfg.Fork != nil)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load genesis packages: %w", err)
 	}
@@ -263,7 +270,7 @@ func (n *Node) Reset(ctx context.Context) error {
 	}
 
 	// Generate a new genesis state based on the current packages
-	pkgsTxs, err := n.pkgs.Load(DefaultFee)
+	pkgsTxs, err := n.loadPackages()
 	if err != nil {
 		return fmt.Errorf("unable to load pkgs: %w", err)
 	}
@@ -388,7 +395,7 @@ func (n *Node) rebuildNodeFromState(ctx context.Context) error {
 		// If NoReplay is true, simply reset the node to its initial state
 		n.logger.Warn("replay disabled")
 
-		txs, err := n.pkgs.Load(DefaultFee)
+		txs, err := n.loadPackages()
 		if err != nil {
 			return fmt.Errorf("unable to load pkgs: %w", err)
 		}
@@ -404,7 +411,7 @@ func (n *Node) rebuildNodeFromState(ctx context.Context) error {
 	}
 
 	// Load genesis packages
-	pkgsTxs, err := n.pkgs.Load(DefaultFee)
+	pkgsTxs, err := n.loadPackages()
 	if err != nil {
 		return fmt.Errorf("unable to load pkgs: %w", err)
 	}
@@ -426,6 +433,12 @@ func (n *Node) rebuildNodeFromState(ctx context.Context) error {
 	return nil
 }
 
+// loadPackages returns the transactions adding the current packages. When
+// forked, the packages may require packages which are fetched remotely.
+func (n *Node) loadPackages() ([]std.Tx, error) {
+	return n.pkgs.load(DefaultFee, n.config.Fork != nil)
+}
+
 func (n *Node) handleEventTX(evt tm2events.Event) {
 	switch data := evt.(type) {
 	case bft.EventTx:
@@ -473,6 +486,10 @@ func (n *Node) rebuildNode(ctx context.Context, genesis gnoland.GnoGenesisState)
 	// Speed up stdlib loading after first start (saves about 2-3 seconds on each reload).
 	nodeConfig.CacheStdlibLoad = true
 	nodeConfig.Genesis.ConsensusParams.Block.MaxGas = n.config.MaxGasPerBlock
+	if fork := n.config.Fork; fork != nil {
+		nodeConfig.PackageFetcher = newWorkspaceFetcher(fork, n.pkgs)
+		nodeConfig.AccountFetcher = fork
+	}
 
 	// recoverFromError handles panics and converts them to errors.
 	recoverFromError := func() {
@@ -519,6 +536,13 @@ func (n *Node) genesisTxResultHandler(ctx sdk.Context, tx std.Tx, res sdk.Result
 		return
 	}
 
+	// When forked, a workspace package imported by a remote package may
+	// have been added before its own transaction.
+	if n.config.Fork != nil && errors.Is(res.Error, vmm.PkgExistError{}) {
+		n.logger.Debug("package already added", "log", res.Log)
+		return
+	}
+
 	// XXX: for now, this is only way to catch the error
 	before, after, found := strings.Cut(res.Log, "\n")
 	if !found {
@@ -118,8 +118,40 @@ func (pm PackagesMap) toList() gnomod.PkgList {
 	return list
 }
 
+// withoutMissingRequires returns a copy of pkgs where the required packages
+// which are not in pkgs are removed from the requirements.
+func withoutMissingRequires(pkgs gnomod.PkgList) gnomod.PkgList {
+	known := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		known[pkg.Name] = true
+	}
+
+	list := make(gnomod.PkgList, len(pkgs))
+	for i, pkg := range pkgs {
+		var reqs []string
+		for _, req := range pkg.Requires {
+			if known[req] {
+				reqs = append(reqs, req)
+			}
+		}
+		pkg.Requires = reqs
+		list[i] = pkg
+	}
+	return list
+}
+
 func (pm PackagesMap) Load(fee std.Fee) ([]std.Tx, error) {
+	return pm.load(fee, false)
+}
+
+// load is like Load, but if skipMissing is set, the required packages
+// which are not in pm are ignored instead of failing, as when they are
+// fetched from a forked chain.
+func (pm PackagesMap) load(fee std.Fee, skipMissing bool) ([]std.Tx, error) {
 	pkgs := pm.toList()
+	if skipMissing {
+		pkgs = withoutMissingRequires(pkgs)
+	}
 
 	sorted, err := pkgs.Sort()
 	if err != nil {
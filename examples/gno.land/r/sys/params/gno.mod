@@ -0,0 +1,7 @@
+module gno.land/r/sys/params
+
+require (
+	gno.land/p/demo/uassert v0.0.0-latest
+	gno.land/p/demo/ufmt v0.0.0-latest
+	gno.land/p/gov/proposal v0.0.0-latest
+)
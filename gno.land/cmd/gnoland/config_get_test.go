@@ -624,6 +624,30 @@ func TestConfig_Get_P2P(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"address book path",
+			"p2p.addr_book_file",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.P2P.AddrBook, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"address book path, raw",
+			"p2p.addr_book_file",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.P2P.AddrBook, escapeNewline(value))
+			},
+			true,
+		},
+		{
+			"strict address book toggle",
+			"p2p.addr_book_strict",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.P2P.AddrBookStrict, unmarshalJSONCommon[bool](t, value))
+			},
+			false,
+		},
 		{
 			"max inbound peers",
 			"p2p.max_num_inbound_peers",
@@ -970,6 +994,30 @@ func TestConfig_Get_Mempool(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"type",
+			"mempool.type",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.Type, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"TTL num blocks",
+			"mempool.ttl_num_blocks",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.TTLNumBlocks, unmarshalJSONCommon[int64](t, value))
+			},
+			false,
+		},
+		{
+			"TTL duration",
+			"mempool.ttl_duration",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.TTLDuration, unmarshalJSONCommon[time.Duration](t, value))
+			},
+			false,
+		},
 	}
 
 	verifyGetTestTableCommon(t, testTable)
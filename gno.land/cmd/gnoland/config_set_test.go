@@ -518,6 +518,29 @@ func TestConfig_Set_P2P(t *testing.T) {
 				assert.Equal(t, boolVal, loadedCfg.P2P.UPNP)
 			},
 		},
+		{
+			"address book path updated",
+			[]string{
+				"p2p.addr_book_file",
+				"example path",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.P2P.AddrBook)
+			},
+		},
+		{
+			"strict address book toggle updated",
+			[]string{
+				"p2p.addr_book_strict",
+				"false",
+			},
+			func(loadedCfg *config.Config, value string) {
+				boolVal, err := strconv.ParseBool(value)
+				require.NoError(t, err)
+
+				assert.Equal(t, boolVal, loadedCfg.P2P.AddrBookStrict)
+			},
+		},
 		{
 			"max inbound peers updated",
 			[]string{
@@ -884,6 +907,36 @@ func TestConfig_Set_Mempool(t *testing.T) {
 				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Mempool.CacheSize))
 			},
 		},
+		{
+			"type updated",
+			[]string{
+				"mempool.type",
+				"priority",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.Mempool.Type)
+			},
+		},
+		{
+			"TTL num blocks updated",
+			[]string{
+				"mempool.ttl_num_blocks",
+				"100",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Mempool.TTLNumBlocks))
+			},
+		},
+		{
+			"TTL duration updated",
+			[]string{
+				"mempool.ttl_duration",
+				"1m0s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.Mempool.TTLDuration.String())
+			},
+		},
 	}
 
 	verifySetTestTableCommon(t, testTable)
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/gnolang/gno/gno.land/pkg/gnogrpc"
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/gno.land/pkg/log"
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
@@ -20,11 +22,13 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/node"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
+	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/telemetry"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -52,8 +56,11 @@ type startCfg struct {
 	chainID               string
 	dataDir               string
 	genesisMaxVMCycles    int64
+	minGasPrices          string
+	pruning               string
 	config                string
 	lazyInit              bool
+	grpcListenAddr        string
 
 	logLevel  string
 	logFormat string
@@ -144,6 +151,20 @@ func (c *startCfg) RegisterFlags(fs *flag.FlagSet) {
 		"set maximum allowed vm cycles per operation. Zero means no limit.",
 	)
 
+	fs.StringVar(
+		&c.minGasPrices,
+		"min-gas-prices",
+		"",
+		"minimum gas prices accepted for transactions by the node, eg. 1ugnot/1000gas (optional)",
+	)
+
+	fs.StringVar(
+		&c.pruning,
+		"pruning",
+		"syncable",
+		"past states to prune: syncable (keep the last 100 and every 10000th, as needed to serve light clients), everything (keep only the latest state) or nothing",
+	)
+
 	fs.StringVar(
 		&c.config,
 		flagConfigFlag,
@@ -171,9 +192,22 @@ func (c *startCfg) RegisterFlags(fs *flag.FlagSet) {
 		false,
 		"flag indicating if lazy init is enabled. Generates the node secrets, configuration, and genesis.json",
 	)
+
+	fs.StringVar(
+		&c.grpcListenAddr,
+		"grpc-laddr",
+		"",
+		"listen address of the gRPC query and broadcast server, eg. 127.0.0.1:9090 (disabled if empty)",
+	)
 }
 
 func execStart(ctx context.Context, c *startCfg, io commands.IO) error {
+	switch c.pruning {
+	case "everything", "syncable", "nothing":
+	default:
+		return fmt.Errorf("invalid pruning strategy %q", c.pruning)
+	}
+
 	// Get the absolute path to the node's data directory
 	nodeDir, err := filepath.Abs(c.dataDir)
 	if err != nil {
@@ -244,7 +278,15 @@ func execStart(ctx context.Context, c *startCfg, io commands.IO) error {
 	evsw := events.NewEventSwitch()
 
 	// Create application and node
-	cfg.LocalApp, err = gnoland.NewApp(nodeDir, c.skipFailingGenesisTxs, evsw, logger)
+	cfg.LocalApp, err = gnoland.NewApp(
+		nodeDir,
+		c.skipFailingGenesisTxs,
+		c.minGasPrices,
+		store.NewPruningOptionsFromString(c.pruning),
+		cfg.StateSync,
+		evsw,
+		logger,
+	)
 	if err != nil {
 		return fmt.Errorf("unable to create the Gnoland app, %w", err)
 	}
@@ -260,6 +302,25 @@ func execStart(ctx context.Context, c *startCfg, io commands.IO) error {
 		return fmt.Errorf("unable to start the Gnoland node, %w", err)
 	}
 
+	// Start the gRPC server, if enabled
+	if c.grpcListenAddr != "" {
+		listener, err := net.Listen("tcp", c.grpcListenAddr)
+		if err != nil {
+			return fmt.Errorf("unable to listen on the gRPC address, %w", err)
+		}
+
+		grpcServer := gnogrpc.NewServer(rpcclient.NewLocal())
+		defer grpcServer.GracefulStop()
+
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Error("gRPC server stopped", "err", err)
+			}
+		}()
+
+		logger.Info("Started gRPC server", "addr", listener.Addr().String())
+	}
+
 	// Set up the wait context
 	nodeCtx, _ := signal.NotifyContext(
 		ctx,
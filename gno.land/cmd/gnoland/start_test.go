@@ -3,12 +3,18 @@ package main
 import (
 	"bytes"
 	"context"
+	"net"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
@@ -162,3 +168,113 @@ func TestStart_Lazy(t *testing.T) {
 	assert.FileExists(t, validatorStatePath)
 	assert.FileExists(t, nodeKeyPath)
 }
+
+func TestStart_InvalidPruning(t *testing.T) {
+	t.Parallel()
+
+	args := []string{
+		"start",
+		"--pruning",
+		"invalid",
+		"--data-dir",
+		t.TempDir(),
+	}
+
+	err := newRootCmd(commands.NewTestIO()).ParseAndRun(context.Background(), args)
+	assert.ErrorContains(t, err, `invalid pruning strategy "invalid"`)
+}
+
+func TestStart_LightClientQuery(t *testing.T) {
+	// Not parallel: starting two nodes at once slows TestStart_Lazy down.
+
+	var (
+		nodeDir     = t.TempDir()
+		genesisFile = filepath.Join(nodeDir, "test_genesis.json")
+	)
+
+	// Prepare the config, with a known RPC listen address
+	prepareNodeRPC(t, nodeDir)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	rpcAddr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	setNodeConfig(t, nodeDir, "rpc.laddr", "tcp://"+rpcAddr)
+	setNodeConfig(t, nodeDir, "p2p.laddr", "tcp://127.0.0.1:0")
+
+	// Start the node with the default flags
+	args := []string{
+		"start",
+		"--lazy",
+		"--skip-failing-genesis-txs",
+		"--data-dir",
+		nodeDir,
+		"--genesis",
+		genesisFile,
+	}
+
+	io := commands.NewTestIO()
+	io.SetOut(commands.WriteNopCloser(new(bytes.Buffer)))
+	io.SetErr(commands.WriteNopCloser(new(bytes.Buffer)))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelFn()
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return newRootCmd(io).ParseAndRun(gCtx, args)
+	})
+
+	// Wait for a few blocks to be committed
+	rpcClient, err := rpcclient.NewHTTPClient("tcp://" + rpcAddr)
+	require.NoError(t, err)
+
+	require.NoError(t, retryUntilTimeout(ctx, func() bool {
+		status, err := rpcClient.Status()
+		return err != nil || status.SyncInfo.LatestBlockHeight < 3
+	}))
+
+	// Trust the first block, and make a verified query of a genesis account
+	height := int64(1)
+	commit, err := rpcClient.Commit(&height)
+	require.NoError(t, err)
+	chainID := commit.ChainID
+	lc, err := light.NewClient(
+		chainID,
+		light.TrustOptions{Period: time.Hour, Height: height, Hash: commit.Hash()},
+		light.NewRPCProvider(chainID, rpcClient),
+		nil,
+		light.NewStore(memdb.NewMemDB()),
+	)
+	require.NoError(t, err)
+
+	addr := crypto.MustAddressFromString("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5") // test1
+	res, err := light.NewVerifyingClient(rpcClient, lc).ABCIQuery(".store/main/key", auth.AddressStoreKey(addr))
+	require.NoError(t, err)
+	require.NoError(t, res.Response.Error)
+	assert.NotEmpty(t, res.Response.Value)
+
+	cancelFn() // stop the node
+	require.NoError(t, g.Wait())
+}
+
+// setNodeConfig sets the value of key in the node's configuration
+func setNodeConfig(t *testing.T, nodeDir, key, value string) {
+	t.Helper()
+
+	args := []string{
+		"config",
+		"set",
+		"--config-path",
+		constructConfigPath(nodeDir),
+		key,
+		value,
+	}
+
+	io := commands.NewTestIO()
+	io.SetOut(commands.WriteNopCloser(new(bytes.Buffer)))
+	io.SetErr(commands.WriteNopCloser(new(bytes.Buffer)))
+
+	require.NoError(t, newRootCmd(io).ParseAndRun(context.Background(), args))
+}
@@ -3,16 +3,22 @@ package gnoclient
 import (
 	"fmt"
 
+	_ "github.com/gnolang/gno/gno.land/pkg/gnoland" // registers the account type
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 var ErrInvalidBlockHeight = errors.New("invalid block height provided")
 
+// accountStorePath is the query path of the store of the auth keeper.
+const accountStorePath = ".store/main/key"
+
 // QueryCfg contains configuration options for performing ABCI queries.
 type QueryCfg struct {
 	Path                       string // Query path
@@ -38,10 +44,17 @@ func (c *Client) Query(cfg QueryCfg) (*ctypes.ResultABCIQuery, error) {
 }
 
 // QueryAccount retrieves account information for a given address.
+//
+// If the RPC client is a light client's VerifyingClient, the account is read
+// from the store of the auth keeper with a proof, as the responses of the
+// auth query handler can't be verified.
 func (c *Client) QueryAccount(addr crypto.Address) (*std.BaseAccount, *ctypes.ResultABCIQuery, error) {
 	if err := c.validateRPCClient(); err != nil {
 		return nil, nil, err
 	}
+	if _, ok := c.RPCClient.(*light.VerifyingClient); ok {
+		return c.queryAccountStore(addr)
+	}
 
 	path := fmt.Sprintf("auth/accounts/%s", crypto.AddressToBech32(addr))
 	data := []byte{}
@@ -63,6 +76,34 @@ func (c *Client) QueryAccount(addr crypto.Address) (*std.BaseAccount, *ctypes.Re
 	return &qret.BaseAccount, qres, nil
 }
 
+// queryAccountStore retrieves account information for a given address from
+// the store of the auth keeper.
+func (c *Client) queryAccountStore(addr crypto.Address) (*std.BaseAccount, *ctypes.ResultABCIQuery, error) {
+	qres, err := c.RPCClient.ABCIQuery(accountStorePath, auth.AddressStoreKey(addr))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query account")
+	}
+	if qres.Response.Error != nil {
+		return nil, nil, qres.Response.Error
+	}
+	if len(qres.Response.Value) == 0 {
+		return nil, nil, std.ErrUnknownAddress("unknown address: " + crypto.AddressToBech32(addr))
+	}
+
+	var acc std.Account
+	if err := amino.Unmarshal(qres.Response.Value, &acc); err != nil {
+		return nil, nil, err
+	}
+
+	return &std.BaseAccount{
+		Address:       acc.GetAddress(),
+		Coins:         acc.GetCoins(),
+		PubKey:        acc.GetPubKey(),
+		AccountNumber: acc.GetAccountNumber(),
+		Sequence:      acc.GetSequence(),
+	}, qres, nil
+}
+
 // QueryAppVersion retrieves information about the app version
 func (c *Client) QueryAppVersion() (string, *ctypes.ResultABCIQuery, error) {
 	if err := c.validateRPCClient(); err != nil {
@@ -81,6 +122,33 @@ func (c *Client) QueryAppVersion() (string, *ctypes.ResultABCIQuery, error) {
 	return version, qres, nil
 }
 
+// QueryMinGasPrices retrieves the minimum gas prices of the node,
+// which are empty if it accepts transactions without fees
+func (c *Client) QueryMinGasPrices() ([]std.GasPrice, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, nil, err
+	}
+
+	path := ".app/min_gas_prices"
+	data := []byte{}
+
+	qres, err := c.RPCClient.ABCIQuery(path, data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query min gas prices")
+	}
+
+	if len(qres.Response.Value) == 0 {
+		return nil, qres, nil
+	}
+
+	gasPrices, err := std.ParseGasPrices(string(qres.Response.Value))
+	if err != nil {
+		return nil, qres, err
+	}
+
+	return gasPrices, qres, nil
+}
+
 // Render calls the Render function for pkgPath with optional args. The pkgPath should
 // include the prefix like "gno.land/". This is similar to using a browser URL
 // <testnet>/<pkgPath>:<args> where <pkgPath> doesn't have the prefix like "gno.land/".
@@ -126,6 +194,26 @@ func (c *Client) QEval(pkgPath string, expression string) (string, *ctypes.Resul
 	return string(qres.Response.Data), qres, nil
 }
 
+// QEvalJSON is like QEval, but returns the results as a JSON array.
+func (c *Client) QEvalJSON(pkgPath string, expression string) (string, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return "", nil, err
+	}
+
+	path := "vm/qevaljson"
+	data := []byte(fmt.Sprintf("%s.%s", pkgPath, expression))
+
+	qres, err := c.RPCClient.ABCIQuery(path, data)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "query qevaljson")
+	}
+	if qres.Response.Error != nil {
+		return "", nil, errors.Wrap(qres.Response.Error, "QEvalJSON failed: log:%s", qres.Response.Log)
+	}
+
+	return string(qres.Response.Data), qres, nil
+}
+
 // Block gets the latest block at height, if any
 // Height must be larger than 0
 func (c *Client) Block(height int64) (*ctypes.ResultBlock, error) {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
@@ -1163,6 +1164,109 @@ func TestAddPackageErrors(t *testing.T) {
 }
 
 // Block tests
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+
+	caller, _ := crypto.AddressFromBech32("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	tx, err := NewSendTx(
+		BaseTxCfg{GasWanted: 1, GasFee: testGasFee},
+		bank.MsgSend{
+			FromAddress: caller,
+			ToAddress:   caller,
+			Amount:      std.NewCoins(std.NewCoin("ugnot", 1)),
+		},
+	)
+	require.NoError(t, err)
+
+	newClient := func(result abci.ResponseDeliverTx) *Client {
+		return &Client{
+			Signer: &mockSigner{},
+			RPCClient: &mockRPCClient{
+				abciQuery: func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+					require.Equal(t, ".app/simulate", path)
+
+					// The unsigned tx is simulated with empty signatures
+					var simTx std.Tx
+					require.NoError(t, amino.Unmarshal(data, &simTx))
+					require.Len(t, simTx.Signatures, 1)
+
+					return &ctypes.ResultABCIQuery{
+						Response: abci.ResponseQuery{Value: amino.MustMarshal(result)},
+					}, nil
+				},
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(abci.ResponseDeliverTx{GasUsed: 42_000})
+
+		res, err := client.Simulate(*tx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42_000), res.GasUsed)
+
+		gas, err := client.EstimateGas(*tx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42_000), gas)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Error: std.InsufficientFundsError{},
+				Log:   "insufficient funds",
+			},
+		})
+
+		_, err := client.EstimateGas(*tx)
+		assert.ErrorContains(t, err, "insufficient funds")
+	})
+
+	t.Run("missing rpc client", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{Signer: &mockSigner{}}
+
+		_, err := client.Simulate(*tx)
+		assert.ErrorIs(t, err, ErrMissingRPCClient)
+	})
+}
+
+func TestQueryMinGasPrices(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(value string) *Client {
+		return &Client{
+			Signer: &mockSigner{},
+			RPCClient: &mockRPCClient{
+				abciQuery: func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+					require.Equal(t, ".app/min_gas_prices", path)
+
+					return &ctypes.ResultABCIQuery{
+						Response: abci.ResponseQuery{Value: []byte(value)},
+					}, nil
+				},
+			},
+		}
+	}
+
+	gasPrices, _, err := newClient("").QueryMinGasPrices()
+	require.NoError(t, err)
+	assert.Empty(t, gasPrices)
+
+	gasPrices, _, err = newClient("1ugnot/1000gas").QueryMinGasPrices()
+	require.NoError(t, err)
+	require.Len(t, gasPrices, 1)
+	assert.Equal(t, std.NewCoin("ugnot", 1), gasPrices[0].Fee(1000))
+
+	_, _, err = newClient("invalid").QueryMinGasPrices()
+	assert.Error(t, err)
+}
+
 func TestBlock(t *testing.T) {
 	t.Parallel()
 
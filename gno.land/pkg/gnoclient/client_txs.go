@@ -3,7 +3,9 @@ package gnoclient
 import (
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	keysclient "github.com/gnolang/gno/tm2/pkg/crypto/keys/client"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -292,4 +294,26 @@ func (c *Client) BroadcastTxCommit(signedTx *std.Tx) (*ctypes.ResultBroadcastTxC
 	return bres, nil
 }
 
+// Simulate executes the transaction on the node without committing it, and
+// returns the result. If the result has an error, then return a wrapped error.
+// As signatures are not verified in simulation, tx may be unsigned.
+func (c *Client) Simulate(tx std.Tx) (*abci.ResponseDeliverTx, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, err
+	}
+
+	return keysclient.SimulateStdTx(c.RPCClient, tx)
+}
+
+// EstimateGas returns the gas used by simulating the transaction.
+// As the gas used may change with the state by the time the transaction is
+// committed, the gas wanted should be set with a safety margin above it.
+func (c *Client) EstimateGas(tx std.Tx) (int64, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return 0, err
+	}
+
+	return keysclient.EstimateGas(c.RPCClient, tx)
+}
+
 // TODO: Add more functionality, examples, and unit tests.
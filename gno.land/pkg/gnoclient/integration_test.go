@@ -2,6 +2,7 @@ package gnoclient
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
 
@@ -12,9 +13,11 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/integration"
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -359,7 +362,7 @@ func TestRunMultiple_Integration(t *testing.T) {
 	// Make Tx config
 	baseCfg := BaseTxCfg{
 		GasFee:         ugnot.ValueString(10000),
-		GasWanted:      8000000,
+		GasWanted:      10000000,
 		AccountNumber:  0,
 		SequenceNumber: 0,
 		Memo:           "",
@@ -649,6 +652,82 @@ func Hello(str string) string {
 	assert.Contains(t, string(query.Response.Data), "gno.mod")
 }
 
+func TestSendSingle_Verified_Integration(t *testing.T) {
+	// Set up in-memory node
+	config, _ := integration.TestingNodeConfig(t, gnoenv.RootDir())
+	node, remoteAddr := integration.TestingInMemoryNode(t, log.NewNoopLogger(), config)
+	defer node.Stop()
+
+	// Init Signer & verifying RPCClient, trusting the first block.
+	// The in-memory node commits empty blocks as fast as it can, with block
+	// times running ahead of the clock.
+	signer := newInMemorySigner(t, "tendermint_test")
+	rpcClient, err := rpcclient.NewHTTPClient(remoteAddr)
+	require.NoError(t, err)
+
+	height := int64(1)
+	commit, err := rpcClient.Commit(&height)
+	require.NoError(t, err)
+	chainID := commit.ChainID
+	lc, err := light.NewClient(
+		chainID,
+		light.TrustOptions{Period: time.Hour, Height: height, Hash: commit.Hash()},
+		light.NewRPCProvider(chainID, rpcClient),
+		nil,
+		light.NewStore(memdb.NewMemDB()),
+		light.WithMaxClockDrift(time.Hour),
+	)
+	require.NoError(t, err)
+
+	// Setup Client
+	client := Client{
+		Signer:    signer,
+		RPCClient: light.NewVerifyingClient(rpcClient, lc),
+	}
+
+	caller, err := client.Signer.Info()
+	require.NoError(t, err)
+
+	// The account is read from the store, with a proof
+	account, _, err := client.QueryAccount(caller.GetAddress())
+	require.NoError(t, err)
+	assert.Equal(t, caller.GetAddress(), account.GetAddress())
+	assert.False(t, account.GetCoins().IsZero())
+
+	// Make Send config for a new address on the blockchain
+	toAddress, _ := crypto.AddressFromBech32("g14a0y9a64dugh3l7hneshdxr4w0rfkkww9ls35p")
+	_, _, err = client.QueryAccount(toAddress)
+	assert.ErrorContains(t, err, "unknown address")
+
+	amount := 10
+	msg := bank.MsgSend{
+		FromAddress: caller.GetAddress(),
+		ToAddress:   toAddress,
+		Amount:      std.Coins{{Denom: ugnot.Denom, Amount: int64(amount)}},
+	}
+	baseCfg := BaseTxCfg{
+		GasFee:         ugnot.ValueString(10000),
+		GasWanted:      8000000,
+		AccountNumber:  account.GetAccountNumber(),
+		SequenceNumber: account.GetSequence(),
+	}
+
+	// Execute send
+	_, err = client.Send(baseCfg, msg)
+	require.NoError(t, err)
+
+	// Get the new account balance, once the next block is committed
+	require.Eventually(t, func() bool {
+		account, _, err = client.QueryAccount(toAddress)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, std.Coins{{Denom: ugnot.Denom, Amount: int64(amount)}}, account.GetCoins())
+
+	// Queries without proofs can't be verified
+	_, _, err = client.Render("gno.land/r/demo/deep/very/deep", "")
+	assert.ErrorIs(t, err, light.ErrUnverifiableQuery)
+}
+
 // todo add more integration tests:
 // MsgCall with Send field populated (single/multiple)
 // MsgRun with Send field populated (single/multiple)
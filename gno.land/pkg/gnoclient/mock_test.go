@@ -119,6 +119,8 @@ type (
 	mockUnconfirmedTxs       func(limit int) (*ctypes.ResultUnconfirmedTxs, error)
 	mockNumUnconfirmedTxs    func() (*ctypes.ResultUnconfirmedTxs, error)
 	mockTx                   func(hash []byte) (*ctypes.ResultTx, error)
+	mockTxSearch             func(query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error)
+	mockBlockSearch          func(query string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error)
 )
 
 type mockRPCClient struct {
@@ -143,6 +145,8 @@ type mockRPCClient struct {
 	unconfirmedTxs       mockUnconfirmedTxs
 	numUnconfirmedTxs    mockNumUnconfirmedTxs
 	tx                   mockTx
+	txSearch             mockTxSearch
+	blockSearch          mockBlockSearch
 }
 
 func (m *mockRPCClient) BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
@@ -292,3 +296,19 @@ func (m *mockRPCClient) Tx(hash []byte) (*ctypes.ResultTx, error) {
 
 	return nil, nil
 }
+
+func (m *mockRPCClient) TxSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	if m.txSearch != nil {
+		return m.txSearch(query, page, perPage, orderBy)
+	}
+
+	return nil, nil
+}
+
+func (m *mockRPCClient) BlockSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	if m.blockSearch != nil {
+		return m.blockSearch(query, page, perPage, orderBy)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,23 @@
+package gnogrpc
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+// Codec is the gRPC codec of the gnogrpc services.  It encodes messages with
+// amino, whose binary encoding is compatible with the proto3 schemas
+// generated by genproto, so clients generated from gnogrpc.proto can talk to
+// the server.  It is named "proto" to match the content-type of such clients.
+type Codec struct{}
+
+func (Codec) Marshal(v any) ([]byte, error) {
+	return amino.Marshal(v)
+}
+
+func (Codec) Unmarshal(data []byte, v any) error {
+	return amino.Unmarshal(data, v)
+}
+
+func (Codec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,44 @@
+package gnogrpc
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/gno.land/pkg/gnogrpc",
+	"gnogrpc",
+	amino.GetCallersDirname(),
+).WithDependencies(
+	abci.Package,
+	types.Package,
+	std.Package,
+).WithTypes(
+	// VMService
+	RenderRequest{}, "RenderRequest",
+	RenderResponse{}, "RenderResponse",
+	QEvalRequest{}, "QEvalRequest",
+	QEvalResponse{}, "QEvalResponse",
+	QFuncsRequest{}, "QFuncsRequest",
+	QFuncsResponse{}, "QFuncsResponse",
+	FunctionSignature{}, "FunctionSignature",
+	NamedType{}, "NamedType",
+	QFileRequest{}, "QFileRequest",
+	QFileResponse{}, "QFileResponse",
+
+	// AccountService
+	AccountRequest{}, "AccountRequest",
+	AccountResponse{}, "AccountResponse",
+	BalancesRequest{}, "BalancesRequest",
+	BalancesResponse{}, "BalancesResponse",
+
+	// ChainService
+	BlockRequest{}, "BlockRequest",
+	BlockResponse{}, "BlockResponse",
+	TxRequest{}, "TxRequest",
+	TxResponse{}, "TxResponse",
+	BroadcastTxRequest{}, "BroadcastTxRequest",
+	BroadcastTxResponse{}, "BroadcastTxResponse",
+))
@@ -0,0 +1,219 @@
+package gnogrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Client is the subset of the node RPC client used by the services, which
+// is implemented by rpcclient.Local and rpcclient.RPCClient.
+type Client interface {
+	ABCIQuery(path string, data []byte) (*ctypes.ResultABCIQuery, error)
+	BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error)
+	Block(height *int64) (*ctypes.ResultBlock, error)
+	Tx(hash []byte) (*ctypes.ResultTx, error)
+}
+
+// NewServer returns a gRPC server serving the gnogrpc services over client.
+// The server encodes messages with Codec, regardless of opts.
+func NewServer(client Client, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ForceServerCodec(Codec{}))
+	gs := grpc.NewServer(opts...)
+
+	srv := &server{client: client}
+	for _, iface := range Services {
+		gs.RegisterService(newServiceDesc(iface), srv)
+	}
+	return gs
+}
+
+// server implements all of the gnogrpc services.
+type server struct {
+	client Client
+}
+
+var (
+	_ VMService      = (*server)(nil)
+	_ AccountService = (*server)(nil)
+	_ ChainService   = (*server)(nil)
+)
+
+// ----------------------------------------
+// VMService
+
+func (s *server) Render(_ context.Context, req *RenderRequest) (*RenderResponse, error) {
+	data, err := s.query("vm/"+vm.QueryRender, req.PkgPath+":"+req.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &RenderResponse{Output: string(data)}, nil
+}
+
+func (s *server) QEval(_ context.Context, req *QEvalRequest) (*QEvalResponse, error) {
+	data, err := s.query("vm/"+vm.QueryEval, req.PkgPath+"."+req.Expr)
+	if err != nil {
+		return nil, err
+	}
+	return &QEvalResponse{Result: string(data)}, nil
+}
+
+func (s *server) QFuncs(_ context.Context, req *QFuncsRequest) (*QFuncsResponse, error) {
+	data, err := s.query("vm/"+vm.QueryFuncs, req.PkgPath)
+	if err != nil {
+		return nil, err
+	}
+	var fsigs vm.FunctionSignatures
+	if err := amino.UnmarshalJSON(data, &fsigs); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to decode functions: %v", err)
+	}
+	return &QFuncsResponse{Functions: newFunctionSignatures(fsigs)}, nil
+}
+
+func (s *server) QFile(_ context.Context, req *QFileRequest) (*QFileResponse, error) {
+	data, err := s.query("vm/"+vm.QueryFile, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &QFileResponse{Content: string(data)}, nil
+}
+
+// ----------------------------------------
+// AccountService
+
+func (s *server) Account(_ context.Context, req *AccountRequest) (*AccountResponse, error) {
+	b32addr := crypto.AddressToBech32(req.Address)
+	data, err := s.query("auth/accounts/"+b32addr, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return nil, status.Errorf(codes.NotFound, "unknown address %s", b32addr)
+	}
+	var acc struct{ BaseAccount std.BaseAccount }
+	if err := amino.UnmarshalJSON(data, &acc); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to decode account: %v", err)
+	}
+	return &AccountResponse{Account: acc.BaseAccount}, nil
+}
+
+func (s *server) Balances(_ context.Context, req *BalancesRequest) (*BalancesResponse, error) {
+	data, err := s.query("bank/balances/"+crypto.AddressToBech32(req.Address), "")
+	if err != nil {
+		return nil, err
+	}
+	var coins std.Coins
+	if err := amino.UnmarshalJSON(data, &coins); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to decode balances: %v", err)
+	}
+	return &BalancesResponse{Coins: coins}, nil
+}
+
+// ----------------------------------------
+// ChainService
+
+func (s *server) Block(_ context.Context, req *BlockRequest) (*BlockResponse, error) {
+	var height *int64
+	switch {
+	case req.Height < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid height %d", req.Height)
+	case req.Height > 0:
+		height = &req.Height
+	}
+	res, err := s.client.Block(height)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if res.Block == nil || res.BlockMeta == nil {
+		return nil, status.Errorf(codes.NotFound, "block %d not found", req.Height)
+	}
+	return &BlockResponse{
+		BlockID: res.BlockMeta.BlockID,
+		Block:   res.Block,
+	}, nil
+}
+
+func (s *server) Tx(_ context.Context, req *TxRequest) (*TxResponse, error) {
+	res, err := s.client.Tx(req.Hash)
+	if err != nil {
+		if errors.As(err, &sm.NoTxResultForHashError{}) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &TxResponse{
+		Hash: res.Hash,
+		Result: types.TxResult{
+			Height:   res.Height,
+			Index:    res.Index,
+			Tx:       res.Tx,
+			Response: res.TxResult,
+		},
+	}, nil
+}
+
+func (s *server) BroadcastTx(_ context.Context, req *BroadcastTxRequest) (*BroadcastTxResponse, error) {
+	if len(req.Tx) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty tx")
+	}
+	res, err := s.client.BroadcastTxSync(req.Tx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &BroadcastTxResponse{
+		Hash:  res.Hash,
+		Error: res.Error,
+		Data:  res.Data,
+		Log:   res.Log,
+	}, nil
+}
+
+// ----------------------------------------
+// misc
+
+// query runs an ABCI query, and converts its error to a gRPC status.
+func (s *server) query(path, data string) ([]byte, error) {
+	res, err := s.client.ABCIQuery(path, []byte(data))
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if res.Response.Error != nil {
+		return nil, queryError(res.Response)
+	}
+	return res.Response.Data, nil
+}
+
+func queryError(res abci.ResponseQuery) error {
+	code := codes.Unknown
+	switch res.Error.(type) {
+	case std.UnknownAddressError:
+		code = codes.NotFound
+	case std.InvalidAddressError,
+		std.UnknownRequestError,
+		vm.InvalidPkgPathError,
+		vm.InvalidExprError,
+		vm.InvalidStmtError,
+		vm.TypeCheckError:
+		code = codes.InvalidArgument
+	case std.OutOfGasError:
+		code = codes.ResourceExhausted
+	}
+	msg := res.Error.Error()
+	if res.Log != "" {
+		msg = fmt.Sprintf("%s: %s", msg, res.Log)
+	}
+	return status.Error(code, msg)
+}
@@ -0,0 +1,353 @@
+package gnogrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type mockClient struct {
+	abciQuery       func(path string, data []byte) (*ctypes.ResultABCIQuery, error)
+	broadcastTxSync func(tx types.Tx) (*ctypes.ResultBroadcastTx, error)
+	block           func(height *int64) (*ctypes.ResultBlock, error)
+	tx              func(hash []byte) (*ctypes.ResultTx, error)
+}
+
+func (m *mockClient) ABCIQuery(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+	return m.abciQuery(path, data)
+}
+
+func (m *mockClient) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return m.broadcastTxSync(tx)
+}
+
+func (m *mockClient) Block(height *int64) (*ctypes.ResultBlock, error) {
+	return m.block(height)
+}
+
+func (m *mockClient) Tx(hash []byte) (*ctypes.ResultTx, error) {
+	return m.tx(hash)
+}
+
+// queryResult returns an ABCIQuery result with the given data
+func queryResult(data []byte) *ctypes.ResultABCIQuery {
+	return &ctypes.ResultABCIQuery{
+		Response: abci.ResponseQuery{
+			ResponseBase: abci.ResponseBase{Data: data},
+		},
+	}
+}
+
+// newTestConn serves the services over an in-memory listener, and returns
+// a connection to it
+func newTestConn(t *testing.T, client Client) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	gs := NewServer(client)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(Codec{})),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestCodecProto3Compatible(t *testing.T) {
+	t.Parallel()
+
+	req := &RenderRequest{PkgPath: "gno.land/r/demo/boards", Path: "hello"}
+
+	var expected []byte
+	expected = protowire.AppendTag(expected, 1, protowire.BytesType)
+	expected = protowire.AppendString(expected, req.PkgPath)
+	expected = protowire.AppendTag(expected, 2, protowire.BytesType)
+	expected = protowire.AppendString(expected, req.Path)
+
+	bz, err := Codec{}.Marshal(req)
+	require.NoError(t, err)
+	assert.Equal(t, expected, bz)
+
+	var decoded RenderRequest
+	require.NoError(t, Codec{}.Unmarshal(expected, &decoded))
+	assert.Equal(t, *req, decoded)
+}
+
+func TestVMService(t *testing.T) {
+	t.Parallel()
+
+	client := &mockClient{
+		abciQuery: func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+			switch path {
+			case "vm/qrender":
+				return queryResult([]byte("rendered " + string(data))), nil
+			case "vm/qeval":
+				if string(data) == "gno.land/r/demo/foo.Bad(" {
+					return &ctypes.ResultABCIQuery{
+						Response: abci.ResponseQuery{
+							ResponseBase: abci.ResponseBase{Error: vm.InvalidExprError{}, Log: "bad expr"},
+						},
+					}, nil
+				}
+				return queryResult([]byte("(42 int)")), nil
+			case "vm/qfuncs":
+				fsigs := vm.FunctionSignatures{{
+					FuncName: "Hello",
+					Params:   []vm.NamedType{{Name: "name", Type: "string"}},
+					Results:  []vm.NamedType{{Name: "#0", Type: "string"}},
+				}}
+				return queryResult([]byte(fsigs.JSON())), nil
+			case "vm/qfile":
+				return queryResult([]byte("package foo")), nil
+			}
+			t.Fatalf("unexpected query path %q", path)
+			return nil, nil
+		},
+	}
+	conn := newTestConn(t, client)
+	ctx := context.Background()
+
+	t.Run("Render", func(t *testing.T) {
+		t.Parallel()
+
+		var res RenderResponse
+		err := conn.Invoke(ctx, FullMethodName("VMService", "Render"),
+			&RenderRequest{PkgPath: "gno.land/r/demo/foo", Path: "bar"}, &res)
+		require.NoError(t, err)
+		assert.Equal(t, "rendered gno.land/r/demo/foo:bar", res.Output)
+	})
+
+	t.Run("QEval", func(t *testing.T) {
+		t.Parallel()
+
+		var res QEvalResponse
+		err := conn.Invoke(ctx, FullMethodName("VMService", "QEval"),
+			&QEvalRequest{PkgPath: "gno.land/r/demo/foo", Expr: "Answer()"}, &res)
+		require.NoError(t, err)
+		assert.Equal(t, "(42 int)", res.Result)
+
+		err = conn.Invoke(ctx, FullMethodName("VMService", "QEval"),
+			&QEvalRequest{PkgPath: "gno.land/r/demo/foo", Expr: "Bad("}, &res)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), "bad expr")
+	})
+
+	t.Run("QFuncs", func(t *testing.T) {
+		t.Parallel()
+
+		var res QFuncsResponse
+		err := conn.Invoke(ctx, FullMethodName("VMService", "QFuncs"),
+			&QFuncsRequest{PkgPath: "gno.land/r/demo/foo"}, &res)
+		require.NoError(t, err)
+		require.Len(t, res.Functions, 1)
+		assert.Equal(t, "Hello", res.Functions[0].FuncName)
+		assert.Equal(t, "name", res.Functions[0].Params[0].Name)
+	})
+
+	t.Run("QFile", func(t *testing.T) {
+		t.Parallel()
+
+		var res QFileResponse
+		err := conn.Invoke(ctx, FullMethodName("VMService", "QFile"),
+			&QFileRequest{Path: "gno.land/r/demo/foo/foo.gno"}, &res)
+		require.NoError(t, err)
+		assert.Equal(t, "package foo", res.Content)
+	})
+}
+
+func TestAccountService(t *testing.T) {
+	t.Parallel()
+
+	var (
+		known   = crypto.AddressFromPreimage([]byte("known"))
+		unknown = crypto.AddressFromPreimage([]byte("unknown"))
+		coins   = std.NewCoins(std.NewCoin("ugnot", 1000))
+	)
+
+	client := &mockClient{
+		abciQuery: func(path string, _ []byte) (*ctypes.ResultABCIQuery, error) {
+			switch path {
+			case "auth/accounts/" + known.String():
+				acc := struct{ BaseAccount std.BaseAccount }{
+					BaseAccount: std.BaseAccount{Address: known, Coins: coins, AccountNumber: 1, Sequence: 2},
+				}
+				return queryResult(amino.MustMarshalJSON(acc)), nil
+			case "auth/accounts/" + unknown.String():
+				return queryResult([]byte("null")), nil
+			case "bank/balances/" + known.String():
+				return queryResult(amino.MustMarshalJSON(coins)), nil
+			}
+			t.Fatalf("unexpected query path %q", path)
+			return nil, nil
+		},
+	}
+	conn := newTestConn(t, client)
+	ctx := context.Background()
+
+	var accRes AccountResponse
+	err := conn.Invoke(ctx, FullMethodName("AccountService", "Account"), &AccountRequest{Address: known}, &accRes)
+	require.NoError(t, err)
+	assert.Equal(t, known, accRes.Account.Address)
+	assert.Equal(t, uint64(2), accRes.Account.Sequence)
+
+	err = conn.Invoke(ctx, FullMethodName("AccountService", "Account"), &AccountRequest{Address: unknown}, &accRes)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	var balRes BalancesResponse
+	err = conn.Invoke(ctx, FullMethodName("AccountService", "Balances"), &BalancesRequest{Address: known}, &balRes)
+	require.NoError(t, err)
+	assert.Equal(t, coins, balRes.Coins)
+}
+
+func TestChainService(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tx     = types.Tx("tx")
+		height = int64(3)
+	)
+	block := &types.Block{
+		Header: types.Header{ChainID: "test", Height: height},
+		Data:   types.Data{Txs: types.Txs{tx}},
+	}
+
+	client := &mockClient{
+		block: func(h *int64) (*ctypes.ResultBlock, error) {
+			if h != nil && *h != height {
+				return nil, assert.AnError
+			}
+			return &ctypes.ResultBlock{
+				BlockMeta: types.NewBlockMeta(block, nil),
+				Block:     block,
+			}, nil
+		},
+		tx: func(hash []byte) (*ctypes.ResultTx, error) {
+			if string(hash) != string(tx.Hash()) {
+				return nil, sm.NoTxResultForHashError{}
+			}
+			return &ctypes.ResultTx{
+				Hash:   hash,
+				Height: height,
+				Tx:     tx,
+				TxResult: abci.ResponseDeliverTx{
+					ResponseBase: abci.ResponseBase{Log: "ok"},
+					GasUsed:      10,
+				},
+			}, nil
+		},
+		broadcastTxSync: func(btx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+			return &ctypes.ResultBroadcastTx{
+				Hash:  btx.Hash(),
+				Error: std.UnauthorizedError{},
+			}, nil
+		},
+	}
+	conn := newTestConn(t, client)
+	ctx := context.Background()
+
+	t.Run("Block", func(t *testing.T) {
+		t.Parallel()
+
+		for _, h := range []int64{0, height} {
+			var res BlockResponse
+			err := conn.Invoke(ctx, FullMethodName("ChainService", "Block"), &BlockRequest{Height: h}, &res)
+			require.NoError(t, err)
+			require.NotNil(t, res.Block)
+			assert.Equal(t, height, res.Block.Height)
+			assert.Equal(t, types.Txs{tx}, res.Block.Txs)
+		}
+
+		var res BlockResponse
+		err := conn.Invoke(ctx, FullMethodName("ChainService", "Block"), &BlockRequest{Height: -1}, &res)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Tx", func(t *testing.T) {
+		t.Parallel()
+
+		var res TxResponse
+		err := conn.Invoke(ctx, FullMethodName("ChainService", "Tx"), &TxRequest{Hash: tx.Hash()}, &res)
+		require.NoError(t, err)
+		assert.Equal(t, height, res.Result.Height)
+		assert.Equal(t, tx, res.Result.Tx)
+		assert.Equal(t, int64(10), res.Result.Response.GasUsed)
+
+		err = conn.Invoke(ctx, FullMethodName("ChainService", "Tx"), &TxRequest{Hash: []byte("nope")}, &res)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("BroadcastTx", func(t *testing.T) {
+		t.Parallel()
+
+		var res BroadcastTxResponse
+		err := conn.Invoke(ctx, FullMethodName("ChainService", "BroadcastTx"), &BroadcastTxRequest{Tx: tx}, &res)
+		require.NoError(t, err)
+		assert.Equal(t, tx.Hash(), res.Hash)
+		assert.IsType(t, std.UnauthorizedError{}, res.Error)
+	})
+}
+
+func TestServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	var methods []string
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		methods = append(methods, info.FullMethod)
+		return handler(ctx, req)
+	}
+
+	client := &mockClient{
+		abciQuery: func(string, []byte) (*ctypes.ResultABCIQuery, error) {
+			return queryResult([]byte("content")), nil
+		},
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	gs := NewServer(client, grpc.UnaryInterceptor(interceptor))
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var res QFileResponse
+	err = conn.Invoke(context.Background(), FullMethodName("VMService", "QFile"),
+		&QFileRequest{Path: "gno.land/p/demo/avl"}, &res, grpc.ForceCodec(Codec{}))
+	require.NoError(t, err)
+	assert.Equal(t, "content", res.Content)
+	assert.Equal(t, []string{"/gnogrpc.VMService/QFile"}, methods)
+}
@@ -0,0 +1,98 @@
+package gnogrpc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/grpc"
+)
+
+// The service interfaces below are the source of truth of both the gRPC
+// server, and the service definitions of gnogrpc.proto, generated with
+// misc/genproto.  Each method must be of the form
+// `func(context.Context, *Request) (*Response, error)`.
+
+// VMService queries the realms and packages of the VM.
+type VMService interface {
+	Render(context.Context, *RenderRequest) (*RenderResponse, error)
+	QEval(context.Context, *QEvalRequest) (*QEvalResponse, error)
+	QFuncs(context.Context, *QFuncsRequest) (*QFuncsResponse, error)
+	QFile(context.Context, *QFileRequest) (*QFileResponse, error)
+}
+
+// AccountService queries the auth and bank modules.
+type AccountService interface {
+	Account(context.Context, *AccountRequest) (*AccountResponse, error)
+	Balances(context.Context, *BalancesRequest) (*BalancesResponse, error)
+}
+
+// ChainService looks up blocks and txs, and broadcasts txs.
+type ChainService interface {
+	Block(context.Context, *BlockRequest) (*BlockResponse, error)
+	Tx(context.Context, *TxRequest) (*TxResponse, error)
+	BroadcastTx(context.Context, *BroadcastTxRequest) (*BroadcastTxResponse, error)
+}
+
+// Services lists the service interfaces, in the order of gnogrpc.proto.
+var Services = []reflect.Type{
+	reflect.TypeOf((*VMService)(nil)).Elem(),
+	reflect.TypeOf((*AccountService)(nil)).Elem(),
+	reflect.TypeOf((*ChainService)(nil)).Elem(),
+}
+
+// FullMethodName returns the gRPC method name of a service method, as
+// expected by grpc.ClientConn.Invoke, e.g. "/gnogrpc.VMService/Render".
+func FullMethodName(service, method string) string {
+	return fmt.Sprintf("/%s.%s/%s", Package.P3PkgName, service, method)
+}
+
+// newServiceDesc derives the gRPC service description of a service interface,
+// the same way genproto derives its proto3 service definition.
+func newServiceDesc(iface reflect.Type) *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: Package.P3PkgName + "." + iface.Name(),
+		HandlerType: reflect.Zero(reflect.PointerTo(iface)).Interface(),
+		Metadata:    Package.GoPkgName + ".proto",
+	}
+	for i := 0; i < iface.NumMethod(); i++ {
+		mtd := iface.Method(i)
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: mtd.Name,
+			Handler:    newMethodHandler(desc.ServiceName, mtd),
+		})
+	}
+	return desc
+}
+
+// methodHandler is the unexported type of grpc.MethodDesc.Handler.
+type methodHandler = func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error)
+
+func newMethodHandler(serviceName string, mtd reflect.Method) methodHandler {
+	reqType := mtd.Type.In(1).Elem()
+	fullMethod := "/" + serviceName + "/" + mtd.Name
+
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		req := reflect.New(reqType)
+		if err := dec(req.Interface()); err != nil {
+			return nil, err
+		}
+
+		handler := func(ctx context.Context, req any) (any, error) {
+			out := reflect.ValueOf(srv).MethodByName(mtd.Name).Call([]reflect.Value{
+				reflect.ValueOf(ctx),
+				reflect.ValueOf(req),
+			})
+			err, _ := out[1].Interface().(error)
+			return out[0].Interface(), err
+		}
+		if interceptor == nil {
+			return handler(ctx, req.Interface())
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fullMethod,
+		}
+		return interceptor(ctx, req.Interface(), info, handler)
+	}
+}
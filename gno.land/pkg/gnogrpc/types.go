@@ -0,0 +1,139 @@
+package gnogrpc
+
+import (
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// ----------------------------------------
+// VMService
+
+// Calls Render(Path) on the realm at PkgPath.
+type RenderRequest struct {
+	PkgPath string
+	Path    string
+}
+
+type RenderResponse struct {
+	Output string
+}
+
+// Evaluates Expr in readonly mode, in the package at PkgPath.
+type QEvalRequest struct {
+	PkgPath string
+	Expr    string
+}
+
+type QEvalResponse struct {
+	Result string
+}
+
+// Lists the exported functions of the package at PkgPath.
+type QFuncsRequest struct {
+	PkgPath string
+}
+
+type QFuncsResponse struct {
+	Functions []FunctionSignature
+}
+
+// Mirrors vm.FunctionSignature.
+type FunctionSignature struct {
+	FuncName string
+	Params   []NamedType
+	Results  []NamedType
+}
+
+// Mirrors vm.NamedType.
+type NamedType struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+func newFunctionSignatures(fsigs vm.FunctionSignatures) []FunctionSignature {
+	res := make([]FunctionSignature, len(fsigs))
+	for i, fsig := range fsigs {
+		res[i] = FunctionSignature{
+			FuncName: fsig.FuncName,
+			Params:   newNamedTypes(fsig.Params),
+			Results:  newNamedTypes(fsig.Results),
+		}
+	}
+	return res
+}
+
+func newNamedTypes(nts []vm.NamedType) []NamedType {
+	res := make([]NamedType, len(nts))
+	for i, nt := range nts {
+		res[i] = NamedType(nt)
+	}
+	return res
+}
+
+// Reads a package file, or lists the files of a package if Path is a package
+// path.
+type QFileRequest struct {
+	Path string
+}
+
+type QFileResponse struct {
+	Content string
+}
+
+// ----------------------------------------
+// AccountService
+
+type AccountRequest struct {
+	Address crypto.Address
+}
+
+type AccountResponse struct {
+	Account std.BaseAccount
+}
+
+type BalancesRequest struct {
+	Address crypto.Address
+}
+
+type BalancesResponse struct {
+	Coins std.Coins
+}
+
+// ----------------------------------------
+// ChainService
+
+// Fetches the block at Height, or the latest block if Height is 0.
+type BlockRequest struct {
+	Height int64
+}
+
+type BlockResponse struct {
+	BlockID types.BlockID
+	Block   *types.Block
+}
+
+// Fetches a committed tx by hash.
+type TxRequest struct {
+	Hash []byte
+}
+
+type TxResponse struct {
+	Hash   []byte
+	Result types.TxResult
+}
+
+// Broadcasts a signed, amino encoded std.Tx, and waits for it to pass CheckTx.
+type BroadcastTxRequest struct {
+	Tx []byte
+}
+
+type BroadcastTxResponse struct {
+	Hash  []byte
+	Error abci.Error
+	Data  []byte
+	Log   string
+}
@@ -8,10 +8,12 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/config"
+	statesync "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/events"
@@ -19,10 +21,12 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 
 	// Only goleveldb is supported for now.
 	_ "github.com/gnolang/gno/tm2/pkg/db/_tags"
@@ -31,11 +35,18 @@ import (
 
 // AppOptions contains the options to create the gno.land ABCI application.
 type AppOptions struct {
-	DB                dbm.DB             // required
-	Logger            *slog.Logger       // required
-	EventSwitch       events.EventSwitch // required
-	MaxCycles         int64              // hard limit for cycles in GnoVM
-	InitChainerConfig                    // options related to InitChainer
+	DB                dbm.DB               // required
+	Logger            *slog.Logger         // required
+	EventSwitch       events.EventSwitch   // required
+	MaxCycles         int64                // default hard limit for cycles in GnoVM, see the vm.max_cycles param
+	MinGasPrices      string               // minimum gas prices accepted in CheckTx, eg. "1ugnot/1000gas" (optional)
+	PruningOptions    store.PruningOptions // past states to keep to serve queries with proofs; only the latest one by default (store.PruneEverything)
+	SnapshotStore     *snapshots.Store     // store of the state sync snapshots, required to serve or restore them (optional)
+	SnapshotInterval  int64                // create a snapshot every SnapshotInterval blocks, 0 to disable
+	SnapshotKeep      int                  // number of recent snapshots to keep, 0 to keep all of them
+	PackageFetcher    vm.PackageFetcher    // fetcher of the packages missing from the state, e.g. from a forked chain (optional)
+	AccountFetcher    auth.AccountFetcher  // fetcher of the balances of the accounts missing from the state (optional)
+	InitChainerConfig                      // options related to InitChainer
 }
 
 // DefaultAppOptions provides a "ready" default [AppOptions] for use with
@@ -63,6 +74,18 @@ func (c AppOptions) validate() error {
 	case c.EventSwitch == nil:
 		return fmt.Errorf("no event switch provided")
 	}
+
+	if c.MinGasPrices != "" {
+		if _, err := std.ParseGasPrices(c.MinGasPrices); err != nil {
+			return fmt.Errorf("invalid minimum gas prices: %w", err)
+		}
+	}
+	if c.SnapshotInterval < 0 {
+		return fmt.Errorf("invalid snapshot interval: %d", c.SnapshotInterval)
+	}
+	if c.SnapshotKeep < 0 {
+		return fmt.Errorf("invalid number of snapshots to keep: %d", c.SnapshotKeep)
+	}
 	return nil
 }
 
@@ -78,7 +101,17 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 
 	// Create BaseApp.
 	// TODO: Add a consensus based min gas prices for the node, by default it does not check
-	baseApp := sdk.NewBaseApp("gnoland", cfg.Logger, cfg.DB, baseKey, mainKey)
+	var baseOptions []func(*sdk.BaseApp)
+	if cfg.MinGasPrices != "" {
+		baseOptions = append(baseOptions, sdk.SetMinGasPrices(cfg.MinGasPrices))
+	}
+	if cfg.PruningOptions != (store.PruningOptions{}) {
+		baseOptions = append(baseOptions, sdk.SetPruningOptions(cfg.PruningOptions))
+	}
+	if cfg.SnapshotStore != nil {
+		baseOptions = append(baseOptions, sdk.SetSnapshotStore(cfg.SnapshotStore, cfg.SnapshotInterval, cfg.SnapshotKeep))
+	}
+	baseApp := sdk.NewBaseApp("gnoland", cfg.Logger, cfg.DB, baseKey, mainKey, baseOptions...)
 	baseApp.SetAppVersion("dev")
 
 	// Set mounts for BaseApp's MultiStore.
@@ -86,19 +119,32 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	baseApp.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, cfg.DB)
 
 	// Construct keepers.
+	prmk := params.NewParamsKeeper(mainKey)
+	vmParams := vm.DefaultParams()
+	vmParams.MaxCycles = cfg.MaxCycles
+	prmk.Register(auth.ModuleName, auth.DefaultParams())
+	prmk.Register(bank.ModuleName, bank.DefaultParams())
+	prmk.Register(vm.ModuleName, vmParams)
 	acctKpr := auth.NewAccountKeeper(mainKey, ProtoGnoAccount)
+	if cfg.AccountFetcher != nil {
+		acctKpr = acctKpr.WithAccountFetcher(cfg.AccountFetcher)
+	}
 	bankKpr := bank.NewBankKeeper(acctKpr)
-	vmk := vm.NewVMKeeper(baseKey, mainKey, acctKpr, bankKpr, cfg.MaxCycles)
+	vmk := vm.NewVMKeeper(baseKey, mainKey, acctKpr, bankKpr, prmk)
+	if cfg.PackageFetcher != nil {
+		vmk.SetPackageFetcher(cfg.PackageFetcher)
+	}
 
 	// Set InitChainer
 	icc := cfg.InitChainerConfig
 	icc.baseApp = baseApp
-	icc.acctKpr, icc.bankKpr, icc.vmKpr = acctKpr, bankKpr, vmk
+	icc.acctKpr, icc.bankKpr, icc.vmKpr, icc.prmKpr = acctKpr, bankKpr, vmk, prmk
 	baseApp.SetInitChainer(icc.InitChainer)
 
 	// Set AnteHandler
 	authOptions := auth.AnteOptions{
 		VerifyGenesisSignatures: false, // for development
+		FeeDenom:                ugnot.Denom,
 	}
 	authAnteHandler := auth.NewAnteHandler(
 		acctKpr, bankKpr, auth.DefaultSigVerificationGasConsumer, authOptions)
@@ -107,9 +153,16 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 		func(ctx sdk.Context, tx std.Tx, simulate bool) (
 			newCtx sdk.Context, res sdk.Result, abort bool,
 		) {
-			// Override auth params.
+			// Override auth and bank params with the ones in the store.
+			var (
+				authParams auth.Params
+				bankParams bank.Params
+			)
+			prmk.GetParams(ctx, auth.ModuleName, &authParams)
+			prmk.GetParams(ctx, bank.ModuleName, &bankParams)
 			ctx = ctx.
-				WithValue(auth.AuthParamsContextKey{}, auth.DefaultParams())
+				WithValue(auth.AuthParamsContextKey{}, authParams).
+				WithValue(bank.BankParamsContextKey{}, bankParams)
 			// Continue on with default auth ante handler.
 			newCtx, res, abort = authAnteHandler(ctx, tx, simulate)
 			return
@@ -124,12 +177,22 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 		// Create Gno transaction store.
 		return vmk.MakeGnoTransactionStore(ctx)
 	})
+	// Parameter changes requested by successful transactions are collected
+	// here, and applied at the end of the block.
+	pc := newParamChangesCollector()
 	baseApp.SetEndTxHook(func(ctx sdk.Context, result sdk.Result) {
 		if result.IsOK() {
 			vmk.CommitGnoTransactionStore(ctx)
+			pc.updateWith(result)
 		}
 	})
 
+	// The Gno store caches are built from the state, so they have to be
+	// rebuilt when the state is restored from a state sync snapshot.
+	baseApp.SetRestoreHook(func(ms store.MultiStore) {
+		vmk.Reinitialize(cfg.Logger, ms)
+	})
+
 	// Set up the event collector
 	c := newCollector[validatorUpdate](
 		cfg.EventSwitch,      // global event switch filled by the node
@@ -140,6 +203,8 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	baseApp.SetEndBlocker(
 		EndBlocker(
 			c,
+			pc,
+			prmk,
 			vmk,
 			baseApp,
 		),
@@ -149,6 +214,7 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	baseApp.Router().AddRoute("auth", auth.NewHandler(acctKpr))
 	baseApp.Router().AddRoute("bank", bank.NewHandler(bankKpr))
 	baseApp.Router().AddRoute("vm", vm.NewHandler(vmk))
+	baseApp.Router().AddRoute("params", params.NewHandler(prmk))
 
 	// Load latest version.
 	if err := baseApp.LoadLatestVersion(); err != nil {
@@ -167,14 +233,19 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 func NewApp(
 	dataRootDir string,
 	skipFailingGenesisTxs bool,
+	minGasPrices string,
+	pruning store.PruningOptions,
+	stateSync *statesync.StateSyncConfig,
 	evsw events.EventSwitch,
 	logger *slog.Logger,
 ) (abci.Application, error) {
 	var err error
 
 	cfg := &AppOptions{
-		Logger:      logger,
-		EventSwitch: evsw,
+		Logger:         logger,
+		EventSwitch:    evsw,
+		MinGasPrices:   minGasPrices,
+		PruningOptions: pruning,
 		InitChainerConfig: InitChainerConfig{
 			GenesisTxResultHandler: PanicOnFailingTxResultHandler,
 			StdlibDir:              filepath.Join(gnoenv.RootDir(), "gnovm", "stdlibs"),
@@ -190,6 +261,22 @@ func NewApp(
 		return nil, fmt.Errorf("error initializing database %q using path %q: %w", dbm.GoLevelDBBackend, dataRootDir, err)
 	}
 
+	// Get the state sync snapshot store, which keeps the snapshot metadata
+	// in its own DB, next to the snapshot chunks.
+	if stateSync != nil {
+		snapshotDir := filepath.Join(dataRootDir, config.DefaultDBDir, "snapshots")
+		snapshotDB, err := dbm.NewDB("metadata", dbm.GoLevelDBBackend, snapshotDir)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing snapshot database using path %q: %w", snapshotDir, err)
+		}
+		cfg.SnapshotStore, err = snapshots.NewStore(snapshotDB, snapshotDir)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing snapshot store using path %q: %w", snapshotDir, err)
+		}
+		cfg.SnapshotInterval = stateSync.SnapshotInterval
+		cfg.SnapshotKeep = stateSync.SnapshotKeepRecent
+	}
+
 	return NewAppWithOptions(cfg)
 }
 
@@ -229,6 +316,7 @@ type InitChainerConfig struct {
 	vmKpr   vm.VMKeeperI
 	acctKpr auth.AccountKeeperI
 	bankKpr bank.BankKeeperI
+	prmKpr  params.ParamsKeeperI
 }
 
 // InitChainer is the function that can be used as a [sdk.InitChainer].
@@ -297,6 +385,13 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 		}
 	}
 
+	// Set genesis params, before the txs which may depend on them
+	for _, param := range state.Params {
+		if err := cfg.prmKpr.SetParam(ctx, param.Key, param.Value); err != nil {
+			return nil, fmt.Errorf("invalid genesis param %s: %w", param.Key, err)
+		}
+	}
+
 	txResponses := make([]abci.ResponseDeliverTx, 0, len(state.Txs))
 	// Run genesis txs
 	for _, tx := range state.Txs {
@@ -331,10 +426,13 @@ type endBlockerApp interface {
 }
 
 // EndBlocker defines the logic executed after every block.
-// Currently, it parses events that happened during execution to calculate
+// Currently, it applies the parameter changes requested during the block,
+// and parses events that happened during execution to calculate
 // validator set changes
 func EndBlocker(
 	collector *collector[validatorUpdate],
+	paramsCollector *collector[paramChange],
+	prmk params.ParamsKeeperI,
 	vmk vm.VMKeeperI,
 	app endBlockerApp,
 ) func(
@@ -342,6 +440,18 @@ func EndBlocker(
 	req abci.RequestEndBlock,
 ) abci.ResponseEndBlock {
 	return func(ctx sdk.Context, _ abci.RequestEndBlock) abci.ResponseEndBlock {
+		// Apply the parameter changes, in the order they were requested
+		for _, change := range paramsCollector.getEvents() {
+			if err := prmk.SetParam(ctx, change.key, change.value); err != nil {
+				app.Logger().Error(
+					"unable to apply param change",
+					"key", change.key,
+					"value", change.value,
+					"err", err,
+				)
+			}
+		}
+
 		// Check if there was a valset change
 		if len(collector.getEvents()) == 0 {
 			// No valset updates
@@ -18,6 +18,8 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/log"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
@@ -93,11 +95,104 @@ func TestNewAppWithOptions_ErrNoDB(t *testing.T) {
 	assert.ErrorContains(t, err, "no db provided")
 }
 
+func TestNewAppWithOptions_MinGasPrices(t *testing.T) {
+	t.Parallel()
+
+	opts := TestAppOptions(memdb.NewMemDB())
+	opts.MinGasPrices = "1ugnot"
+	_, err := NewAppWithOptions(opts)
+	assert.ErrorContains(t, err, "invalid minimum gas prices")
+
+	opts.MinGasPrices = "1ugnot/1000gas"
+	app, err := NewAppWithOptions(opts)
+	require.NoError(t, err)
+
+	res := app.Query(abci.RequestQuery{Path: ".app/min_gas_prices"})
+	require.True(t, res.IsOK())
+	assert.Equal(t, "1ugnot/1000gas", string(res.Value))
+}
+
+func TestNewAppWithOptions_Params(t *testing.T) {
+	t.Parallel()
+
+	app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+	require.NoError(t, err)
+	bapp := app.(*sdk.BaseApp)
+
+	addr := crypto.AddressFromPreimage([]byte("test1"))
+	resp := bapp.InitChain(abci.RequestInitChain{
+		Time:    time.Now(),
+		ChainID: "dev",
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		Validators: []abci.ValidatorUpdate{},
+		AppState: GnoGenesisState{
+			Balances: []Balance{
+				{
+					Address: addr,
+					Amount:  []std.Coin{{Amount: 1e15, Denom: "ugnot"}},
+				},
+			},
+			Params: []params.Param{
+				{Key: "bank.send_enabled", Value: "false"},
+				{Key: "vm.storage_price", Value: "10ugnot"},
+			},
+		},
+	})
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+
+	// Sends are disabled by the genesis params.
+	tx := amino.MustMarshal(std.Tx{
+		Msgs:       []std.Msg{bank.NewMsgSend(addr, crypto.AddressFromPreimage([]byte("test2")), std.NewCoins(std.NewCoin("ugnot", 10)))},
+		Fee:        std.Fee{GasWanted: 100_000, GasFee: std.Coin{Amount: 1_000_000, Denom: "ugnot"}},
+		Signatures: []std.Signature{{}}, // one empty signature
+	})
+	dtxResp := bapp.DeliverTx(abci.RequestDeliverTx{Tx: tx})
+	require.False(t, dtxResp.IsOK())
+	assert.IsType(t, bank.SendDisabledError{}, dtxResp.Error)
+	bapp.Commit()
+
+	res := bapp.Query(abci.RequestQuery{Path: "params/bank.send_enabled"})
+	require.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, "false", string(res.Data))
+
+	res = bapp.Query(abci.RequestQuery{Path: "params/vm"})
+	require.True(t, res.IsOK(), res.Log)
+	assert.Contains(t, string(res.Data), `"storage_price": "10ugnot"`)
+
+	res = bapp.Query(abci.RequestQuery{Path: "params/auth.tx_sig_limit"})
+	require.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, "7", string(res.Data))
+}
+
+func TestNewAppWithOptions_InvalidGenesisParams(t *testing.T) {
+	t.Parallel()
+
+	app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+	require.NoError(t, err)
+
+	resp := app.InitChain(abci.RequestInitChain{
+		Time:    time.Now(),
+		ChainID: "dev",
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		AppState: GnoGenesisState{
+			Params: []params.Param{
+				{Key: "auth.tx_sig_limit", Value: "0"},
+			},
+		},
+	})
+	require.False(t, resp.IsOK())
+	assert.Contains(t, resp.Error.Error(), "invalid genesis param auth.tx_sig_limit")
+}
+
 func TestNewApp(t *testing.T) {
 	// NewApp should have good defaults and manage to run InitChain.
 	td := t.TempDir()
 
-	app, err := NewApp(td, true, events.NewEventSwitch(), log.NewNoopLogger())
+	app, err := NewApp(td, true, "", store.PruneEverything, nil, events.NewEventSwitch(), log.NewNoopLogger())
 	require.NoError(t, err, "NewApp should be successful")
 
 	resp := app.InitChain(abci.RequestInitChain{
@@ -271,7 +366,7 @@ func TestEndBlocker(t *testing.T) {
 		c := newCollector[validatorUpdate](&mockEventSwitch{}, noFilter)
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, nil, &mockEndBlockerApp{})
+		eb := EndBlocker(c, newParamChangesCollector(), nil, nil, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -280,6 +375,65 @@ func TestEndBlocker(t *testing.T) {
 		assert.Equal(t, abci.ResponseEndBlock{}, res)
 	})
 
+	t.Run("param changes", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			applied    []paramChange
+			mockParams = &mockParamsKeeper{
+				setParamFn: func(_ sdk.Context, key, value string) error {
+					if key == "invalid" {
+						return errors.New("invalid param")
+					}
+
+					applied = append(applied, paramChange{key: key, value: value})
+
+					return nil
+				},
+			}
+		)
+
+		// Create the collector
+		pc := newParamChangesCollector()
+
+		// Feed it the results of the delivered txs
+		paramEvent := func(pkgPath, key, value string) gnostd.GnoEvent {
+			return gnostd.GnoEvent{
+				Type:    paramChangedEvent,
+				PkgPath: pkgPath,
+				Attributes: []gnostd.GnoEventAttribute{
+					{Key: paramKeyAttr, Value: key},
+					{Key: paramValueAttr, Value: value},
+				},
+			}
+		}
+		pc.updateWith(sdk.Result{
+			ResponseBase: abci.ResponseBase{
+				Events: []abci.Event{
+					paramEvent(paramsRealm, "auth.tx_sig_limit", "10"),
+					paramEvent("gno.land/r/malicious", "bank.send_enabled", "false"),
+					paramEvent(paramsRealm, "invalid", "1"),
+					paramEvent(paramsRealm, "vm.max_cycles", "1000"),
+				},
+			},
+		})
+
+		// Create the EndBlocker
+		c := newCollector[validatorUpdate](&mockEventSwitch{}, validatorEventFilter)
+		eb := EndBlocker(c, pc, mockParams, nil, &mockEndBlockerApp{})
+
+		// Run the EndBlocker
+		res := eb(sdk.Context{}, abci.RequestEndBlock{})
+		assert.Equal(t, abci.ResponseEndBlock{}, res)
+
+		// Verify only the changes from the params realm were applied, in order
+		assert.Equal(t, []paramChange{
+			{key: "auth.tx_sig_limit", value: "10"},
+			{key: "vm.max_cycles", value: "1000"},
+		}, applied)
+		assert.Empty(t, pc.getEvents())
+	})
+
 	t.Run("invalid VM call", func(t *testing.T) {
 		t.Parallel()
 
@@ -311,7 +465,7 @@ func TestEndBlocker(t *testing.T) {
 		mockEventSwitch.FireEvent(gnostd.GnoEvent{})
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, mockVMKeeper, &mockEndBlockerApp{})
+		eb := EndBlocker(c, newParamChangesCollector(), nil, mockVMKeeper, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -354,7 +508,7 @@ func TestEndBlocker(t *testing.T) {
 		mockEventSwitch.FireEvent(gnostd.GnoEvent{})
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, mockVMKeeper, &mockEndBlockerApp{})
+		eb := EndBlocker(c, newParamChangesCollector(), nil, mockVMKeeper, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -422,7 +576,7 @@ func TestEndBlocker(t *testing.T) {
 		mockEventSwitch.FireEvent(txEvent)
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, mockVMKeeper, &mockEndBlockerApp{})
+		eb := EndBlocker(c, newParamChangesCollector(), nil, mockVMKeeper, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
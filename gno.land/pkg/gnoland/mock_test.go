@@ -113,6 +113,28 @@ func (m *mockVMKeeper) CommitGnoTransactionStore(ctx sdk.Context) {
 	}
 }
 
+type mockParamsKeeper struct {
+	setParamFn func(sdk.Context, string, string) error
+}
+
+func (m *mockParamsKeeper) GetParams(_ sdk.Context, _ string, _ any) {}
+
+func (m *mockParamsKeeper) SetParams(_ sdk.Context, _ string, _ any) error {
+	return nil
+}
+
+func (m *mockParamsKeeper) GetParam(_ sdk.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (m *mockParamsKeeper) SetParam(ctx sdk.Context, key, value string) error {
+	if m.setParamFn != nil {
+		return m.setParamFn(ctx, key, value)
+	}
+
+	return nil
+}
+
 type (
 	lastBlockHeightDelegate func() int64
 	loggerDelegate          func() *slog.Logger
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	tmcfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/node"
@@ -16,7 +17,9 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
 type InMemoryNodeConfig struct {
@@ -26,6 +29,10 @@ type InMemoryNodeConfig struct {
 	GenesisMaxVMCycles int64
 	DB                 *memdb.MemDB // will be initialized if nil
 
+	// Optional fetchers of the state missing locally, e.g. from a forked chain.
+	PackageFetcher vm.PackageFetcher
+	AccountFetcher auth.AccountFetcher
+
 	// If StdlibDir not set, then it's filepath.Join(TMConfig.RootDir, "gnovm", "stdlibs")
 	InitChainerConfig
 }
@@ -62,7 +69,12 @@ func defaultBlockParams() *abci.BlockParams {
 func NewDefaultTMConfig(rootdir string) *tmcfg.Config {
 	// We use `TestConfig` here otherwise ChainID will be empty, and
 	// there is no other way to update it than using a config file
-	return tmcfg.TestConfig().SetRootDir(rootdir)
+	tmconfig := tmcfg.TestConfig().SetRootDir(rootdir)
+
+	// The in-memory node runs on its own, so there are no peers to
+	// exchange and no address book to keep.
+	tmconfig.P2P.PexReactor = false
+	return tmconfig
 }
 
 func (cfg *InMemoryNodeConfig) validate() error {
@@ -109,6 +121,9 @@ func NewInMemoryNode(logger *slog.Logger, cfg *InMemoryNodeConfig) (*node.Node,
 		MaxCycles:         cfg.GenesisMaxVMCycles,
 		DB:                cfg.DB,
 		EventSwitch:       evsw,
+		PruningOptions:    store.PruneSyncable,
+		PackageFetcher:    cfg.PackageFetcher,
+		AccountFetcher:    cfg.AccountFetcher,
 		InitChainerConfig: cfg.InitChainerConfig,
 	})
 	if err != nil {
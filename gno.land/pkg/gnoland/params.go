@@ -0,0 +1,76 @@
+package gnoland
+
+import (
+	gnovm "github.com/gnolang/gno/gnovm/stdlibs/std"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+)
+
+const (
+	paramsRealm = "gno.land/r/sys/params"
+
+	paramChangedEvent = "ParamChanged"
+
+	paramKeyAttr   = "key"
+	paramValueAttr = "value"
+)
+
+// paramChange is a parameter change requested by `r/sys/params`,
+// applied to the params keeper at the end of the block
+type paramChange struct {
+	key   string
+	value string
+}
+
+// newParamChangesCollector creates a collector for the parameter changes.
+// Unlike the validator updates, which are read from the VM at the end of the
+// block, parameter changes are applied from the events themselves; so the
+// collector is fed by the end-tx hook as transactions are delivered, instead
+// of the event switch
+func newParamChangesCollector() *collector[paramChange] {
+	return &collector[paramChange]{
+		events: make([]paramChange, 0),
+		filter: paramChangeFilter,
+	}
+}
+
+// paramChangeFilter extracts the parameter changes emitted by `r/sys/params`
+// from the given transaction result
+func paramChangeFilter(event events.Event) []paramChange {
+	result, ok := event.(sdk.Result)
+	if !ok {
+		return nil
+	}
+
+	var changes []paramChange
+	for _, ev := range result.Events {
+		// Make sure the event is a GnoVM event
+		gnoEv, ok := ev.(gnovm.GnoEvent)
+		if !ok {
+			continue
+		}
+
+		// Make sure the event is a change from `r/sys/params`
+		if gnoEv.PkgPath != paramsRealm || gnoEv.Type != paramChangedEvent {
+			continue
+		}
+
+		var (
+			change         paramChange
+			hasKey, hasVal bool
+		)
+		for _, attr := range gnoEv.Attributes {
+			switch attr.Key {
+			case paramKeyAttr:
+				change.key, hasKey = attr.Value, true
+			case paramValueAttr:
+				change.value, hasVal = attr.Value, true
+			}
+		}
+		if hasKey && hasVal {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,403 @@
+package gnoweb
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gorilla/mux"
+	"github.com/gotuna/gotuna"
+)
+
+// number of transactions listed on an account page, for each search.
+const accountTxsLimit = 20
+
+type blockView struct {
+	Height   int64
+	Hash     string
+	Time     string
+	NumTxs   int64
+	Proposer string
+	Txs      []txView
+
+	Prev, Next int64 // adjacent heights, 0 if none
+}
+
+type txView struct {
+	Hash      string
+	Height    int64
+	Index     int
+	Success   bool
+	Error     string
+	GasWanted int64
+	GasUsed   int64
+	Fee       string
+	Memo      string
+	Msgs      []msgView
+	Events    []string // JSON
+}
+
+type msgView struct {
+	Type   string
+	Fields []fieldView
+	Files  []fieldView // for messages with a package
+}
+
+// fieldView is a named value, linked to URL if it is not empty.
+type fieldView struct {
+	Name  string
+	Value string
+	URL   string
+}
+
+func handlerBlocks(logger *slog.Logger, app gotuna.App, cfg *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cli, err := client.NewHTTPClient(cfg.RemoteAddr)
+		if err != nil {
+			writeError(logger, w, fmt.Errorf("unable to create HTTP client, %w", err))
+			return
+		}
+
+		// Blocks are listed from the latest one, or from the one
+		// preceding the height given by ?before=.
+		var maxHeight int64
+		if before := r.URL.Query().Get("before"); before != "" {
+			height, err := strconv.ParseInt(before, 10, 64)
+			if err != nil || height < 2 {
+				handleNotFound(logger, app, cfg, r.RequestURI, w, r)
+				return
+			}
+			maxHeight = height - 1
+		}
+		info, err := cli.BlockchainInfo(1, maxHeight)
+		if err != nil {
+			writeError(logger, w, fmt.Errorf("unable to list blocks: %w", err))
+			return
+		}
+
+		blocks := make([]blockView, 0, len(info.BlockMetas))
+		for _, meta := range info.BlockMetas {
+			blocks = append(blocks, newBlockView(meta))
+		}
+		var older int64
+		if n := len(blocks); n > 0 && blocks[n-1].Height > 1 {
+			older = blocks[n-1].Height
+		}
+
+		tmpl := app.NewTemplatingEngine()
+		tmpl.Set("LastHeight", info.LastHeight)
+		tmpl.Set("Blocks", blocks)
+		tmpl.Set("Older", older)
+		tmpl.Set("Config", cfg)
+		tmpl.Render(w, r, "blocks.html", "explorer.html", "funcs.html")
+	})
+}
+
+func handlerBlock(logger *slog.Logger, app gotuna.App, cfg *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height, err := strconv.ParseInt(mux.Vars(r)["height"], 10, 64)
+		if err != nil || height < 1 {
+			handleNotFound(logger, app, cfg, r.RequestURI, w, r)
+			return
+		}
+
+		cli, err := client.NewHTTPClient(cfg.RemoteAddr)
+		if err != nil {
+			writeError(logger, w, fmt.Errorf("unable to create HTTP client, %w", err))
+			return
+		}
+		res, err := cli.Block(&height)
+		if err != nil {
+			logger.Error("unable to get block", "height", height, "error", err)
+			handleNotFound(logger, app, cfg, r.RequestURI, w, r)
+			return
+		}
+		results, err := cli.BlockResults(&height)
+		if err != nil {
+			writeError(logger, w, fmt.Errorf("unable to get block results: %w", err))
+			return
+		}
+
+		status, err := cli.Status()
+		if err != nil {
+			writeError(logger, w, fmt.Errorf("unable to get status: %w", err))
+			return
+		}
+
+		block := newBlockView(res.BlockMeta)
+		block.Prev = height - 1
+		if height < status.SyncInfo.LatestBlockHeight {
+			block.Next = height + 1
+		}
+		for i, tx := range res.Block.Data.Txs {
+			var result abci.ResponseDeliverTx
+			if i < len(results.Results.DeliverTxs) {
+				result = results.Results.DeliverTxs[i]
+			}
+			block.Txs = append(block.Txs, newTxView(tx, height, i, result))
+		}
+
+		tmpl := app.NewTemplatingEngine()
+		tmpl.Set("Block", block)
+		tmpl.Set("Config", cfg)
+		tmpl.Render(w, r, "block.html", "explorer.html", "funcs.html")
+	})
+}
+
+func handlerTx(logger *slog.Logger, app gotuna.App, cfg *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hash, err := hex.DecodeString(mux.Vars(r)["hash"])
+		if err != nil {
+			handleNotFound(logger, app, cfg, r.RequestURI, w, r)
+			return
+		}
+
+		cli, err := client.NewHTTPClient(cfg.RemoteAddr)
+		if err != nil {
+			writeError(logger, w, fmt.Errorf("unable to create HTTP client, %w", err))
+			return
+		}
+		res, err := cli.Tx(hash)
+		if err != nil {
+			logger.Error("unable to get tx", "hash", mux.Vars(r)["hash"], "error", err)
+			handleNotFound(logger, app, cfg, r.RequestURI, w, r)
+			return
+		}
+
+		tmpl := app.NewTemplatingEngine()
+		tmpl.Set("Tx", newTxView(res.Tx, res.Height, int(res.Index), res.TxResult))
+		tmpl.Set("Config", cfg)
+		tmpl.Render(w, r, "tx.html", "explorer.html", "funcs.html")
+	})
+}
+
+func handlerAccount(logger *slog.Logger, app gotuna.App, cfg *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, err := crypto.AddressFromBech32(mux.Vars(r)["address"])
+		if err != nil {
+			handleNotFound(logger, app, cfg, r.RequestURI, w, r)
+			return
+		}
+
+		res, err := makeRequest(logger, cfg, "bank/balances/"+addr.String(), nil)
+		if err != nil {
+			writeError(logger, w, err)
+			return
+		}
+		var coins std.Coins
+		if err := amino.UnmarshalJSON(res.Data, &coins); err != nil {
+			writeError(logger, w, fmt.Errorf("unable to decode balance: %w", err))
+			return
+		}
+
+		// The account is null until it receives coins or signs a tx.
+		res, err = makeRequest(logger, cfg, "auth/accounts/"+addr.String(), nil)
+		if err != nil {
+			writeError(logger, w, err)
+			return
+		}
+		var account struct {
+			BaseAccount struct {
+				AccountNumber string `json:"account_number"`
+				Sequence      string `json:"sequence"`
+			}
+		}
+		found := json.Unmarshal(res.Data, &account) == nil && account.BaseAccount.AccountNumber != ""
+
+		// The activity requires the node to index the transactions.
+		txs, err := searchAccountTxs(cfg, addr)
+		var activityErr string
+		if err != nil {
+			logger.Error("unable to search account txs", "address", addr, "error", err)
+			activityErr = err.Error()
+		}
+
+		tmpl := app.NewTemplatingEngine()
+		tmpl.Set("Address", addr.String())
+		tmpl.Set("Balance", coins.String())
+		tmpl.Set("Found", found)
+		tmpl.Set("AccountNumber", account.BaseAccount.AccountNumber)
+		tmpl.Set("Sequence", account.BaseAccount.Sequence)
+		tmpl.Set("Txs", txs)
+		tmpl.Set("ActivityError", activityErr)
+		tmpl.Set("Config", cfg)
+		tmpl.Render(w, r, "account.html", "explorer.html", "funcs.html")
+	})
+}
+
+// searchAccountTxs returns the latest transactions signed by addr, or
+// sending coins to addr, the most recent first.
+func searchAccountTxs(cfg *Config, addr crypto.Address) ([]txView, error) {
+	cli, err := client.NewHTTPClient(cfg.RemoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create HTTP client, %w", err)
+	}
+
+	var results []*ctypes.ResultTx
+	seen := map[string]bool{}
+	for _, key := range []string{"message.signer", "message.to_address"} {
+		query := fmt.Sprintf("%s = '%s'", key, addr)
+		res, err := cli.TxSearch(query, 1, accountTxsLimit, "desc")
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range res.Txs {
+			if hash := string(tx.Hash); !seen[hash] {
+				seen[hash] = true
+				results = append(results, tx)
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Height != results[j].Height {
+			return results[i].Height > results[j].Height
+		}
+		return results[i].Index > results[j].Index
+	})
+	if len(results) > accountTxsLimit {
+		results = results[:accountTxsLimit]
+	}
+
+	txs := make([]txView, len(results))
+	for i, res := range results {
+		txs[i] = newTxView(res.Tx, res.Height, int(res.Index), res.TxResult)
+	}
+	return txs, nil
+}
+
+func newBlockView(meta *bft.BlockMeta) blockView {
+	return blockView{
+		Height:   meta.Header.Height,
+		Hash:     fmt.Sprintf("%X", meta.BlockID.Hash),
+		Time:     meta.Header.Time.UTC().Format("2006-01-02 15:04:05 MST"),
+		NumTxs:   meta.Header.NumTxs,
+		Proposer: meta.Header.ProposerAddress.String(),
+	}
+}
+
+func newTxView(bz bft.Tx, height int64, index int, result abci.ResponseDeliverTx) txView {
+	view := txView{
+		Hash:      fmt.Sprintf("%X", bz.Hash()),
+		Height:    height,
+		Index:     index,
+		Success:   result.IsOK(),
+		GasWanted: result.GasWanted,
+		GasUsed:   result.GasUsed,
+	}
+	if result.Error != nil {
+		view.Error = result.Error.Error()
+	}
+	for _, ev := range result.Events {
+		view.Events = append(view.Events, string(amino.MustMarshalJSON(ev)))
+	}
+
+	var tx std.Tx
+	if err := amino.Unmarshal(bz, &tx); err != nil {
+		view.Msgs = []msgView{{
+			Type:   "unknown",
+			Fields: []fieldView{{Name: "error", Value: "unable to decode tx: " + err.Error()}},
+		}}
+		return view
+	}
+	view.Fee = fmt.Sprintf("%s (%d gas wanted)", tx.Fee.GasFee, tx.Fee.GasWanted)
+	view.Memo = tx.Memo
+	for _, msg := range tx.Msgs {
+		view.Msgs = append(view.Msgs, newMsgView(msg))
+	}
+	return view
+}
+
+func newMsgView(msg std.Msg) msgView {
+	view := msgView{Type: msg.Route() + "/" + msg.Type()}
+	switch msg := msg.(type) {
+	case vm.MsgCall:
+		view.Fields = []fieldView{
+			addressField("caller", msg.Caller),
+			{Name: "package", Value: msg.PkgPath, URL: pkgURL(msg.PkgPath)},
+			{Name: "func", Value: msg.Func, URL: funcURL(msg.PkgPath, msg.Func)},
+			{Name: "args", Value: formatArgs(msg.Args)},
+			{Name: "send", Value: msg.Send.String()},
+		}
+	case vm.MsgAddPackage:
+		view.Fields = []fieldView{
+			addressField("creator", msg.Creator),
+			{Name: "package", Value: msg.Package.Path, URL: pkgURL(msg.Package.Path)},
+			{Name: "deposit", Value: msg.Deposit.String()},
+		}
+		view.Files = pkgFiles(msg.Package, true)
+	case vm.MsgRun:
+		view.Fields = []fieldView{
+			addressField("caller", msg.Caller),
+			{Name: "send", Value: msg.Send.String()},
+		}
+		view.Files = pkgFiles(msg.Package, false)
+	case bank.MsgSend:
+		view.Fields = []fieldView{
+			addressField("from", msg.FromAddress),
+			addressField("to", msg.ToAddress),
+			{Name: "amount", Value: msg.Amount.String()},
+		}
+	default:
+		view.Fields = []fieldView{{Name: "json", Value: string(amino.MustMarshalJSON(msg))}}
+	}
+	return view
+}
+
+func addressField(name string, addr crypto.Address) fieldView {
+	return fieldView{Name: name, Value: addr.String(), URL: "/a/" + addr.String()}
+}
+
+// pkgFiles lists the files of memPkg, linked to their source when the
+// package is stored on chain.
+func pkgFiles(memPkg *std.MemPackage, linked bool) []fieldView {
+	if memPkg == nil {
+		return nil
+	}
+	files := make([]fieldView, len(memPkg.Files))
+	for i, file := range memPkg.Files {
+		files[i] = fieldView{Name: file.Name, Value: fmt.Sprintf("%d bytes", len(file.Body))}
+		if url := pkgURL(memPkg.Path); linked && url != "" {
+			files[i].URL = url + "/" + file.Name
+		}
+	}
+	return files
+}
+
+// pkgURL returns the URL of the view of the package at pkgPath, or "" if
+// it is not served by gnoweb.
+func pkgURL(pkgPath string) string {
+	if !strings.HasPrefix(pkgPath, "gno.land/r/") && !strings.HasPrefix(pkgPath, "gno.land/p/") {
+		return ""
+	}
+	return pathOf(pkgPath)
+}
+
+// funcURL returns the URL of the help of the realm function fn.
+func funcURL(pkgPath, fn string) string {
+	if !strings.HasPrefix(pkgPath, "gno.land/r/") {
+		return ""
+	}
+	return pathOf(pkgPath) + "?help&__func=" + fn
+}
+
+func formatArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = strconv.Quote(arg)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,109 @@
+package gnoweb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoclient"
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
+	"github.com/gnolang/gno/gno.land/pkg/integration"
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gotuna/gotuna/test/assert"
+)
+
+func TestExplorer(t *testing.T) {
+	rootdir := gnoenv.RootDir()
+	config, creator := integration.TestingNodeConfig(t, rootdir)
+	config.TMConfig.TxEventStore.EventStoreType = kv.EventStoreType
+	node, remoteAddr := integration.TestingInMemoryNode(t, log.NewTestingLogger(t), config)
+	defer node.Stop()
+
+	// Send some coins, and add a package.
+	kb := keys.NewInMemory()
+	_, err := kb.CreateAccount(integration.DefaultAccount_Name, integration.DefaultAccount_Seed, "", "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc, err := client.NewHTTPClient(remoteAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := gnoclient.Client{
+		Signer: &gnoclient.SignerFromKeybase{
+			Keybase: kb,
+			Account: integration.DefaultAccount_Name,
+			ChainID: config.Genesis.ChainID,
+		},
+		RPCClient: rpc,
+	}
+	txcfg := gnoclient.BaseTxCfg{
+		GasFee:    ugnot.ValueString(1000000),
+		GasWanted: 2_000_000,
+	}
+	recipient := crypto.AddressFromPreimage([]byte("explorer"))
+	sendRes, err := cli.Send(txcfg, bank.MsgSend{
+		FromAddress: creator,
+		ToAddress:   recipient,
+		Amount:      std.NewCoins(std.NewCoin(ugnot.Denom, 4242)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addRes, err := cli.AddPackage(txcfg, vm.MsgAddPackage{
+		Creator: creator,
+		Package: &std.MemPackage{
+			Name:  "explorer",
+			Path:  "gno.land/r/demo/explorer",
+			Files: []*std.MemFile{{Name: "explorer.gno", Body: "package explorer\n\nfunc Render(_ string) string { return \"explorer\" }\n"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := []struct {
+		route     string
+		status    int
+		substring string
+	}{
+		{"/blocks", http.StatusOK, "/block/"},
+		{fmt.Sprintf("/blocks?before=%d", addRes.Height+1), http.StatusOK, fmt.Sprintf("/block/%d", addRes.Height)},
+		{fmt.Sprintf("/block/%d", sendRes.Height), http.StatusOK, fmt.Sprintf("/tx/%X", sendRes.Hash)},
+		{fmt.Sprintf("/block/%d", sendRes.Height), http.StatusOK, "bank/send"},
+		{fmt.Sprintf("/tx/%X", sendRes.Hash), http.StatusOK, "/a/" + recipient.String()},
+		{fmt.Sprintf("/tx/%X", sendRes.Hash), http.StatusOK, "4242ugnot"},
+		{fmt.Sprintf("/tx/%X", addRes.Hash), http.StatusOK, `href="/r/demo/explorer/explorer.gno"`},
+		{fmt.Sprintf("/tx/%X", addRes.Hash), http.StatusOK, "vm/add_package"},
+		{"/a/" + recipient.String(), http.StatusOK, "4242ugnot"},
+		{"/a/" + recipient.String(), http.StatusOK, fmt.Sprintf("/tx/%X", sendRes.Hash)},
+		{"/a/" + creator.String(), http.StatusOK, fmt.Sprintf("/tx/%X", addRes.Hash)},
+		{"/a/" + creator.String(), http.StatusOK, "account number"},
+		{"/block/100000", http.StatusNotFound, "/block/100000"},
+		{"/tx/0123", http.StatusNotFound, "/tx/0123"},
+		{"/a/invalid", http.StatusNotFound, "/a/invalid"},
+	}
+
+	cfg := NewDefaultConfig()
+	cfg.RemoteAddr = remoteAddr
+	app := MakeApp(log.NewTestingLogger(t), cfg)
+
+	for _, r := range routes {
+		t.Run(fmt.Sprintf("test route %s", r.route), func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, r.route, nil)
+			response := httptest.NewRecorder()
+			app.Router.ServeHTTP(response, request)
+			assert.Equal(t, r.status, response.Code)
+			assert.Contains(t, response.Body.String(), r.substring)
+		})
+	}
+}
@@ -94,6 +94,12 @@ func MakeApp(logger *slog.Logger, cfg Config) gotuna.App {
 	app.Router.Handle("/r/{rlmname:[a-z][a-z0-9_]*(?:/[a-z][a-z0-9_]*)+}:{querystr:.*}", handlerRealmRender(logger, app, &cfg))
 	app.Router.Handle("/p/{filepath:.*}", handlerPackageFile(logger, app, &cfg))
 
+	// explorer
+	app.Router.Handle("/blocks", handlerBlocks(logger, app, &cfg))
+	app.Router.Handle("/block/{height:[0-9]+}", handlerBlock(logger, app, &cfg))
+	app.Router.Handle("/tx/{hash:[0-9a-fA-F]+}", handlerTx(logger, app, &cfg))
+	app.Router.Handle("/a/{address:[a-z0-9]+}", handlerAccount(logger, app, &cfg))
+
 	// other
 	app.Router.Handle("/faucet", handlerFaucet(logger, app, &cfg))
 	app.Router.Handle("/static/{path:.+}", handlerStaticFile(logger, app, &cfg))
@@ -476,7 +482,7 @@ func handleNotFound(logger *slog.Logger, app gotuna.App, cfg *Config, path strin
 	// decode path for non-ascii characters
 	decodedPath, err := url.PathUnescape(path)
 	if err != nil {
-		logger.Error("failed to decode path", err)
+		logger.Error("failed to decode path", "error", err)
 		decodedPath = path
 	}
 	w.WriteHeader(http.StatusNotFound)
@@ -169,5 +169,6 @@ func DefaultTestingTMConfig(gnoroot string) *tmcfg.Config {
 	tmconfig.Consensus.CreateEmptyBlocksInterval = time.Duration(0)
 	tmconfig.RPC.ListenAddress = defaultListner
 	tmconfig.P2P.ListenAddress = defaultListner
+	tmconfig.P2P.PexReactor = false
 	return tmconfig
 }
@@ -75,6 +75,12 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 	if len(args) != 1 {
 		return flag.ErrHelp
 	}
+	if cfg.RootCfg.GasWanted == 0 && !cfg.RootCfg.GasAuto {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.RootCfg.GasFee == "" && !cfg.RootCfg.GasAuto {
+		return errors.New("gas-fee not specified")
+	}
 
 	// read account pubkey.
 	nameOrBech32 := args[0]
@@ -101,12 +107,6 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 		panic(fmt.Sprintf("found an empty package %q", cfg.PkgPath))
 	}
 
-	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
-	if err != nil {
-		panic(err)
-	}
 	// construct msg & tx and marshal.
 	msg := vm.MsgAddPackage{
 		Creator: creator,
@@ -115,11 +115,16 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 	}
 	tx := std.Tx{
 		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
 		Signatures: nil,
 		Memo:       cfg.RootCfg.Memo,
 	}
 
+	// resolve gas wanted & fee.
+	tx.Fee, err = client.ResolveFee(cfg.RootCfg, tx)
+	if err != nil {
+		return err
+	}
+
 	if cfg.RootCfg.Broadcast {
 		err := client.ExecSignAndBroadcast(cfg.RootCfg, args, tx, io)
 		if err != nil {
@@ -20,6 +20,7 @@ type MakeCallCfg struct {
 	PkgPath  string
 	FuncName string
 	Args     commands.StringArr
+	JSON     bool
 }
 
 func NewMakeCallCmd(rootCfg *client.MakeTxCfg, io commands.IO) *commands.Command {
@@ -67,6 +68,13 @@ func (c *MakeCallCfg) RegisterFlags(fs *flag.FlagSet) {
 		"args",
 		"arguments to contract",
 	)
+
+	fs.BoolVar(
+		&c.JSON,
+		"json",
+		false,
+		"arguments are JSON encoded, results are returned as JSON",
+	)
 }
 
 func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
@@ -79,10 +87,10 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 	if len(args) != 1 {
 		return flag.ErrHelp
 	}
-	if cfg.RootCfg.GasWanted == 0 {
+	if cfg.RootCfg.GasWanted == 0 && !cfg.RootCfg.GasAuto {
 		return errors.New("gas-wanted not specified")
 	}
-	if cfg.RootCfg.GasFee == "" {
+	if cfg.RootCfg.GasFee == "" && !cfg.RootCfg.GasAuto {
 		return errors.New("gas-fee not specified")
 	}
 
@@ -108,13 +116,6 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 		return errors.Wrap(err, "parsing send coins")
 	}
 
-	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
-	if err != nil {
-		return errors.Wrap(err, "parsing gas fee coin")
-	}
-
 	// construct msg & tx and marshal.
 	msg := vm.MsgCall{
 		Caller:  caller,
@@ -122,14 +123,20 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 		PkgPath: cfg.PkgPath,
 		Func:    fnc,
 		Args:    cfg.Args,
+		JSON:    cfg.JSON,
 	}
 	tx := std.Tx{
 		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
 		Signatures: nil,
 		Memo:       cfg.RootCfg.Memo,
 	}
 
+	// resolve gas wanted & fee.
+	tx.Fee, err = client.ResolveFee(cfg.RootCfg, tx)
+	if err != nil {
+		return err
+	}
+
 	if cfg.RootCfg.Broadcast {
 		err := client.ExecSignAndBroadcast(cfg.RootCfg, args, tx, io)
 		if err != nil {
@@ -0,0 +1,109 @@
+package keyscli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys/client"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type MakeDeprecateCfg struct {
+	RootCfg *client.MakeTxCfg
+
+	PkgPath string
+	Notice  string
+}
+
+func NewMakeDeprecateCmd(rootCfg *client.MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeDeprecateCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "deprecate",
+			ShortUsage: "deprecate [flags] <key-name or address>",
+			ShortHelp:  "marks a package as deprecated",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeDeprecate(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeDeprecateCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.PkgPath,
+		"pkgpath",
+		"",
+		"package path (required)",
+	)
+
+	fs.StringVar(
+		&c.Notice,
+		"notice",
+		"",
+		"deprecation notice, eg. \"use gno.land/p/demo/foo/v2 instead.\" (required)",
+	)
+}
+
+func execMakeDeprecate(cfg *MakeDeprecateCfg, args []string, io commands.IO) error {
+	if cfg.PkgPath == "" {
+		return errors.New("pkgpath not specified")
+	}
+	if cfg.Notice == "" {
+		return errors.New("notice not specified")
+	}
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+	if cfg.RootCfg.GasWanted == 0 && !cfg.RootCfg.GasAuto {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.RootCfg.GasFee == "" && !cfg.RootCfg.GasAuto {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.RootCfg.Home)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	creator := info.GetAddress()
+
+	// construct msg & tx and marshal.
+	msg := vm.NewMsgDeprecate(creator, cfg.PkgPath, cfg.Notice)
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Signatures: nil,
+		Memo:       cfg.RootCfg.Memo,
+	}
+
+	// resolve gas wanted & fee.
+	tx.Fee, err = client.ResolveFee(cfg.RootCfg, tx)
+	if err != nil {
+		return err
+	}
+
+	if cfg.RootCfg.Broadcast {
+		err := client.ExecSignAndBroadcast(cfg.RootCfg, args, tx, io)
+		if err != nil {
+			return err
+		}
+	} else {
+		io.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
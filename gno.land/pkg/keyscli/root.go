@@ -35,6 +35,7 @@ func NewRootCmd(io commands.IO, base client.BaseOptions) *commands.Command {
 		client.NewImportCmd(cfg, io),
 		client.NewListCmd(cfg, io),
 		client.NewSignCmd(cfg, io),
+		client.NewMultisignCmd(cfg, io),
 		client.NewVerifyCmd(cfg, io),
 		client.NewQueryCmd(cfg, io),
 		client.NewBroadcastCmd(cfg, io),
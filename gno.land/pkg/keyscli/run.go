@@ -45,10 +45,10 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 	if len(args) != 2 {
 		return flag.ErrHelp
 	}
-	if cfg.RootCfg.GasWanted == 0 {
+	if cfg.RootCfg.GasWanted == 0 && !cfg.RootCfg.GasAuto {
 		return errors.New("gas-wanted not specified")
 	}
-	if cfg.RootCfg.GasFee == "" {
+	if cfg.RootCfg.GasFee == "" && !cfg.RootCfg.GasAuto {
 		return errors.New("gas-fee not specified")
 	}
 
@@ -66,13 +66,6 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 	}
 	caller := info.GetAddress()
 
-	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
-	if err != nil {
-		return errors.Wrap(err, "parsing gas fee coin")
-	}
-
 	memPkg := &std.MemPackage{}
 	if sourcePath == "-" { // stdin
 		data, err := io.ReadAll(cmdio.In())
@@ -120,11 +113,16 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 	}
 	tx := std.Tx{
 		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
 		Signatures: nil,
 		Memo:       cfg.RootCfg.Memo,
 	}
 
+	// resolve gas wanted & fee.
+	tx.Fee, err = client.ResolveFee(cfg.RootCfg, tx)
+	if err != nil {
+		return err
+	}
+
 	if cfg.RootCfg.Broadcast {
 		err := client.ExecSignAndBroadcast(cfg.RootCfg, args, tx, cmdio)
 		if err != nil {
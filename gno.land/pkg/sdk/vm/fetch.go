@@ -0,0 +1,169 @@
+package vm
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/stdlibs"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// PackageFetcher fetches the packages which are missing from the store,
+// for instance from a remote chain the local chain was forked from.
+type PackageFetcher interface {
+	// FetchPackage returns the package at pkgPath and the address of its
+	// creator, or a nil package if pkgPath does not exist.
+	FetchPackage(pkgPath string) (memPkg *std.MemPackage, creator crypto.Address, err error)
+}
+
+// SetPackageFetcher sets the fetcher of the packages missing from the store.
+// A missing package is fetched and added, along with the missing packages
+// it imports, the first time a message or a query needs it. The gas used
+// to add the fetched packages is charged to that message or query, but
+// not their storage deposit.
+func (vm *VMKeeper) SetPackageFetcher(pf PackageFetcher) {
+	vm.fetcher = pf
+}
+
+// fetchPackages adds the packages at pkgPaths which are missing from
+// gnostore, and the missing packages they import, if a fetcher is set.
+// Packages which are not found by the fetcher are left missing.
+func (vm *VMKeeper) fetchPackages(ctx sdk.Context, gnostore gno.Store, pkgPaths ...string) error {
+	if vm.fetcher == nil {
+		return nil
+	}
+	for _, pkgPath := range pkgPaths {
+		if err := vm.fetchPackage(ctx, gnostore, pkgPath, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vm *VMKeeper) fetchPackage(ctx sdk.Context, gnostore gno.Store, pkgPath string, importers []string) error {
+	if gno.IsStdlib(pkgPath) || gno.ReGnoRunPath.MatchString(pkgPath) {
+		return nil
+	}
+	if gnostore.GetMemPackage(pkgPath) != nil {
+		return nil
+	}
+	if slices.Contains(importers, pkgPath) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"import cycle in fetched package %s", pkgPath))
+	}
+	memPkg, creator, err := vm.fetcher.FetchPackage(pkgPath)
+	if err != nil {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"unable to fetch package %s: %v", pkgPath, err))
+	}
+	if memPkg == nil {
+		return nil // not found, reported by the caller as usual.
+	}
+	// Add the imported packages first.
+	importers = append(importers, pkgPath)
+	for _, imp := range packageImports(memPkg) {
+		if err := vm.fetchPackage(ctx, gnostore, imp, importers); err != nil {
+			return err
+		}
+	}
+	return vm.addFetchedPackage(ctx, gnostore, memPkg, creator)
+}
+
+// addFetchedPackage runs and saves memPkg as if creator had added it,
+// without type checking it nor taking a deposit.
+func (vm *VMKeeper) addFetchedPackage(ctx sdk.Context, gnostore gno.Store, memPkg *std.MemPackage, creator crypto.Address) (err error) {
+	pkgPath := memPkg.Path
+	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	msgCtx := stdlibs.ExecContext{
+		ChainID:       ctx.ChainID(),
+		Height:        ctx.BlockHeight(),
+		Timestamp:     ctx.BlockTime().Unix(),
+		OrigCaller:    creator.Bech32(),
+		OrigSendSpent: new(std.Coins),
+		OrigPkgAddr:   pkgAddr.Bech32(),
+		Banker:        NewSDKBanker(vm, ctx),
+		EventLogger:   ctx.EventLogger(),
+	}
+	m2 := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:   "",
+			Output:    os.Stdout, // XXX
+			Store:     gnostore,
+			Alloc:     gnostore.GetAllocator(),
+			Context:   msgCtx,
+			MaxCycles: vm.getParams(ctx).MaxCycles,
+			GasMeter:  ctx.GasMeter(),
+		})
+	defer m2.Release()
+	defer func() {
+		if r := recover(); r != nil {
+			switch r.(type) {
+			case store.OutOfGasException: // panic in consumeGas()
+				panic(r)
+			default:
+				err = errors.Wrap(fmt.Errorf("%v", r), "VM fetched addpkg panic: %v\n%s\n",
+					r, m2.String())
+				return
+			}
+		}
+	}()
+	m2.RunMemPackage(memPkg, true)
+
+	// The storage of the fetched realm is not paid for by the caller.
+	diffs := gnostore.RealmStorageDiffs()
+	if diff := diffs[pkgPath]; diff > 0 {
+		if pv := gnostore.GetPackage(pkgPath, false); pv != nil && pv.GetRealm() != nil {
+			rlm := pv.GetRealm()
+			rlm.Storage += uint64(diff)
+			gnostore.SetPackageRealm(rlm)
+		}
+	}
+	delete(diffs, pkgPath)
+
+	vm.setPackageInfo(ctx, &PackageInfo{
+		Path:       pkgPath,
+		Creator:    creator,
+		Deprecated: deprecationNotice(memPkg),
+	})
+	ctx.Logger().Info("fetched package", "path", pkgPath, "creator", creator)
+	return nil
+}
+
+// packageImports returns the sorted paths imported by the non-test files
+// of memPkg. Files which cannot be parsed are ignored.
+func packageImports(memPkg *std.MemPackage) []string {
+	seen := map[string]struct{}{}
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), mfile.Name, mfile.Body, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range f.Imports {
+			if path, err := strconv.Unquote(imp.Path.Value); err == nil {
+				seen[path] = struct{}{}
+			}
+		}
+	}
+	imports := make([]string, 0, len(seen))
+	for path := range seen {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	return imports
+}
@@ -0,0 +1,112 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPackageFetcher struct {
+	pkgs    map[string]*std.MemPackage
+	creator crypto.Address
+	fetched []string
+}
+
+func (f *mockPackageFetcher) FetchPackage(pkgPath string) (*std.MemPackage, crypto.Address, error) {
+	f.fetched = append(f.fetched, pkgPath)
+	return f.pkgs[pkgPath], f.creator, nil
+}
+
+func TestVMKeeperFetchPackages(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	fetcher := &mockPackageFetcher{
+		creator: crypto.AddressFromPreimage([]byte("remote")),
+		pkgs: map[string]*std.MemPackage{
+			"gno.land/p/demo/remote": {
+				Name: "remote",
+				Path: "gno.land/p/demo/remote",
+				Files: []*std.MemFile{
+					{Name: "remote.gno", Body: "package remote\n\nfunc Name() string { return \"remote\" }\n"},
+				},
+			},
+			"gno.land/r/demo/remote": {
+				Name: "remote",
+				Path: "gno.land/r/demo/remote",
+				Files: []*std.MemFile{
+					{Name: "remote.gno", Body: `package remote
+
+import "gno.land/p/demo/remote"
+
+var count int
+
+func Hello() string {
+	count++
+	return "hello " + remote.Name()
+}
+
+func Count() int { return count }
+`},
+					{Name: "remote_test.gno", Body: "package remote\n\nimport \"gno.land/p/demo/missing\"\n"},
+				},
+			},
+		},
+	}
+	env.vmk.SetPackageFetcher(fetcher)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// A local package importing a remote one.
+	files := []*std.MemFile{
+		{Name: "local.gno", Body: "package local\n\nimport \"gno.land/p/demo/remote\"\n\nfunc Name() string { return \"local \" + remote.Name() }\n"},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/demo/local", files))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gno.land/p/demo/remote"}, fetcher.fetched)
+	info := env.vmk.getPackageInfo(ctx, "gno.land/p/demo/remote")
+	require.NotNil(t, info)
+	assert.Equal(t, fetcher.creator, info.Creator)
+
+	// Calling a remote realm fetches it, but not its already fetched import.
+	fetcher.fetched = nil
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, "gno.land/r/demo/remote", "Hello", nil))
+	require.NoError(t, err)
+	assert.Equal(t, `("hello remote" string)`+"\n\n", res)
+	assert.Equal(t, []string{"gno.land/r/demo/remote"}, fetcher.fetched)
+
+	// The realm state is kept locally.
+	env.vmk.CommitGnoTransactionStore(ctx)
+	fetcher.fetched = nil
+	res, err = env.vmk.QueryEval(ctx, "gno.land/r/demo/remote", "Count()")
+	require.NoError(t, err)
+	assert.Equal(t, "(1 int)", res)
+	assert.Empty(t, fetcher.fetched)
+
+	// Missing packages are reported as usual.
+	_, err = env.vmk.QueryEval(ctx, "gno.land/r/demo/missing", "Count()")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"gno.land/r/demo/missing"}, fetcher.fetched)
+}
+
+func TestPackageImports(t *testing.T) {
+	t.Parallel()
+
+	memPkg := &std.MemPackage{
+		Name: "foo",
+		Path: "gno.land/p/demo/foo",
+		Files: []*std.MemFile{
+			{Name: "a.gno", Body: "package foo\n\nimport (\n\t\"std\"\n\t\"gno.land/p/demo/b\"\n)\n"},
+			{Name: "b.gno", Body: "package foo\n\nimport \"gno.land/p/demo/a\"\nimport \"std\"\n"},
+			{Name: "a_test.gno", Body: "package foo\n\nimport \"gno.land/p/demo/test\"\n"},
+			{Name: "gno.mod", Body: "module gno.land/p/demo/foo\n"},
+		},
+	}
+	assert.Equal(t, []string{"gno.land/p/demo/a", "gno.land/p/demo/b", "std"}, packageImports(memPkg))
+}
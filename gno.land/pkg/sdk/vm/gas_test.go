@@ -73,8 +73,8 @@ func TestAddPkgDeliverTx(t *testing.T) {
 
 	assert.True(t, res.IsOK())
 
-	// NOTE: let's try to keep this bellow 100_000 :)
-	assert.Equal(t, int64(92825), gasDeliver)
+	// NOTE: let's try to keep this bellow 150_000 :)
+	assert.Equal(t, int64(141095), gasDeliver)
 }
 
 // Enough gas for a failed transaction.
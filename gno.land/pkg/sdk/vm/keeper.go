@@ -10,12 +10,14 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/stdlibs"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/errors"
@@ -23,6 +25,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
@@ -59,11 +62,11 @@ type VMKeeper struct {
 	iavlKey store.StoreKey
 	acck    auth.AccountKeeper
 	bank    bank.BankKeeper
+	prmk    params.ParamsKeeperI // vm params are registered under ModuleName
+	fetcher PackageFetcher       // optional, see SetPackageFetcher
 
 	// cached, the DeliverTx persistent state.
 	gnoStore gno.Store
-
-	maxCycles int64 // max allowed cylces on VM executions
 }
 
 // NewVMKeeper returns a new VMKeeper.
@@ -72,15 +75,15 @@ func NewVMKeeper(
 	iavlKey store.StoreKey,
 	acck auth.AccountKeeper,
 	bank bank.BankKeeper,
-	maxCycles int64,
+	prmk params.ParamsKeeperI,
 ) *VMKeeper {
 	// TODO: create an Options struct to avoid too many constructor parameters
 	vmk := &VMKeeper{
-		baseKey:   baseKey,
-		iavlKey:   iavlKey,
-		acck:      acck,
-		bank:      bank,
-		maxCycles: maxCycles,
+		baseKey: baseKey,
+		iavlKey: iavlKey,
+		acck:    acck,
+		bank:    bank,
+		prmk:    prmk,
 	}
 	return vmk
 }
@@ -121,6 +124,17 @@ func (vm *VMKeeper) Initialize(
 	}
 }
 
+// Reinitialize initializes the VMKeeper again, dropping the Gno store and its
+// caches, after the stores it was initialized with have been replaced; for
+// instance, when the state was restored from a state sync snapshot.
+func (vm *VMKeeper) Reinitialize(
+	logger *slog.Logger,
+	ms store.MultiStore,
+) {
+	vm.gnoStore = nil
+	vm.Initialize(logger, ms)
+}
+
 type stdlibCache struct {
 	dir  string
 	base store.Store
@@ -206,7 +220,7 @@ func (vm *VMKeeper) newGnoTransactionStore(ctx sdk.Context) gno.TransactionStore
 	base := ctx.Store(vm.baseKey)
 	iavl := ctx.Store(vm.iavlKey)
 
-	return vm.gnoStore.BeginTransaction(base, iavl)
+	return vm.gnoStore.BeginTransaction(base, iavl, ctx.GasMeter())
 }
 
 func (vm *VMKeeper) MakeGnoTransactionStore(ctx sdk.Context) sdk.Context {
@@ -272,7 +286,7 @@ func (vm *VMKeeper) checkNamespacePermission(ctx sdk.Context, creator crypto.Add
 			Store:     store,
 			Context:   msgCtx,
 			Alloc:     store.GetAllocator(),
-			MaxCycles: vm.maxCycles,
+			MaxCycles: vm.getParams(ctx).MaxCycles,
 			GasMeter:  ctx.GasMeter(),
 		})
 	defer m.Release()
@@ -312,6 +326,8 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	memPkg := msg.Package
 	deposit := msg.Deposit
 	gnostore := vm.getGnoTransactionStore(ctx)
+	params := vm.getParams(ctx)
+	gnostore.SetGasConfig(params.gasConfig())
 
 	// Validate arguments.
 	if creator.IsZero() {
@@ -330,6 +346,9 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	if gno.ReGnoRunPath.MatchString(pkgPath) {
 		return ErrInvalidPkgPath("reserved package name: " + pkgPath)
 	}
+	if err := vm.fetchPackages(ctx, gnostore, packageImports(memPkg)...); err != nil {
+		return err
+	}
 
 	// Validate Gno syntax and type check.
 	format := true
@@ -347,6 +366,21 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 		return err
 	}
 
+	// A new version of a pure package may only be published
+	// by the creator of the previous version.
+	prevPath, isNewVersion := previousVersion(pkgPath)
+	var prevInfo *PackageInfo
+	if isNewVersion {
+		prevInfo = vm.getPackageInfo(ctx, prevPath)
+		if prevInfo == nil {
+			return ErrInvalidPkgPath("previous version not found: " + prevPath)
+		}
+		if prevInfo.Creator != creator {
+			return ErrUnauthorizedUser(fmt.Sprintf(
+				"%s is not the creator of %s", creator, prevPath))
+		}
+	}
+
 	err = vm.bank.SendCoins(ctx, creator, pkgAddr, deposit)
 	if err != nil {
 		return err
@@ -373,7 +407,7 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 			Store:     gnostore,
 			Alloc:     gnostore.GetAllocator(),
 			Context:   msgCtx,
-			MaxCycles: vm.maxCycles,
+			MaxCycles: params.MaxCycles,
 			GasMeter:  ctx.GasMeter(),
 		})
 	defer m2.Release()
@@ -391,6 +425,22 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	}()
 	m2.RunMemPackage(memPkg, true)
 
+	// Lock the storage deposit for the new realm state.
+	if err := vm.processStorageDeposit(ctx, creator, gnostore, params); err != nil {
+		return err
+	}
+
+	// Record the package metadata, and link the previous version to it.
+	vm.setPackageInfo(ctx, &PackageInfo{
+		Path:       pkgPath,
+		Creator:    creator,
+		Deprecated: deprecationNotice(memPkg),
+	})
+	if prevInfo != nil {
+		prevInfo.Successor = pkgPath
+		vm.setPackageInfo(ctx, prevInfo)
+	}
+
 	// Log the telemetry
 	logTelemetry(
 		m2.GasMeter.GasConsumed(),
@@ -409,6 +459,11 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	pkgPath := msg.PkgPath // to import
 	fnc := msg.Func
 	gnostore := vm.getGnoTransactionStore(ctx)
+	params := vm.getParams(ctx)
+	gnostore.SetGasConfig(params.gasConfig())
+	if err := vm.fetchPackages(ctx, gnostore, pkgPath); err != nil {
+		return "", err
+	}
 	// Get the package and function type.
 	pv := gnostore.GetPackage(pkgPath, false)
 	pl := gno.PackageNodeLocation(pkgPath)
@@ -446,7 +501,12 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	}
 	for i, arg := range msg.Args {
 		argType := ft.Params[i].Type
-		atv := convertArgToGno(arg, argType)
+		var atv gno.TypedValue
+		if msg.JSON {
+			atv = gno.UnmarshalJSONValue(gnostore.GetAllocator(), gnostore, []byte(arg), argType)
+		} else {
+			atv = convertArgToGno(arg, argType)
+		}
 		cx.Args[i] = &gno.ConstExpr{
 			TypedValue: atv,
 		}
@@ -474,7 +534,7 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 			Store:     gnostore,
 			Context:   msgCtx,
 			Alloc:     gnostore.GetAllocator(),
-			MaxCycles: vm.maxCycles,
+			MaxCycles: params.MaxCycles,
 			GasMeter:  ctx.GasMeter(),
 		})
 	defer m.Release()
@@ -495,13 +555,22 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 		}
 	}()
 	rtvs := m.Eval(xn)
-	for i, rtv := range rtvs {
-		res = res + rtv.String()
-		if i < len(rtvs)-1 {
-			res += "\n"
+	if msg.JSON {
+		res = string(gno.MarshalJSONValues(gnostore, rtvs))
+	} else {
+		for i, rtv := range rtvs {
+			res = res + rtv.String()
+			if i < len(rtvs)-1 {
+				res += "\n"
+			}
 		}
 	}
 
+	// Lock or refund storage deposits of modified realms.
+	if err := vm.processStorageDeposit(ctx, caller, gnostore, params); err != nil {
+		return "", err
+	}
+
 	// Log the telemetry
 	logTelemetry(
 		m.GasMeter.GasConsumed(),
@@ -511,6 +580,7 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 			Value: attribute.StringValue("m_call"),
 		},
 	)
+	logRealmCallTelemetry(pkgPath)
 
 	res += "\n\n" // use `\n\n` as separator to separate results for single tx with multi msgs
 
@@ -523,6 +593,8 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 	caller := msg.Caller
 	pkgAddr := caller
 	gnostore := vm.getGnoTransactionStore(ctx)
+	params := vm.getParams(ctx)
+	gnostore.SetGasConfig(params.gasConfig())
 	send := msg.Send
 	memPkg := msg.Package
 
@@ -539,6 +611,9 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 	if err := msg.Package.Validate(); err != nil {
 		return "", ErrInvalidPkgPath(err.Error())
 	}
+	if err := vm.fetchPackages(ctx, gnostore, packageImports(memPkg)...); err != nil {
+		return "", err
+	}
 
 	// Validate Gno syntax and type check.
 	format := false
@@ -574,7 +649,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 			Store:     gnostore,
 			Alloc:     gnostore.GetAllocator(),
 			Context:   msgCtx,
-			MaxCycles: vm.maxCycles,
+			MaxCycles: params.MaxCycles,
 			GasMeter:  ctx.GasMeter(),
 		})
 	// XXX MsgRun does not have pkgPath. How do we find it on chain?
@@ -601,7 +676,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 			Store:     gnostore,
 			Alloc:     gnostore.GetAllocator(),
 			Context:   msgCtx,
-			MaxCycles: vm.maxCycles,
+			MaxCycles: params.MaxCycles,
 			GasMeter:  ctx.GasMeter(),
 		})
 	defer m2.Release()
@@ -621,6 +696,11 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 	m2.RunMain()
 	res = buf.String()
 
+	// Lock or refund storage deposits of modified realms.
+	if err := vm.processStorageDeposit(ctx, caller, gnostore, params); err != nil {
+		return "", err
+	}
+
 	// Log the telemetry
 	logTelemetry(
 		m2.GasMeter.GasConsumed(),
@@ -634,6 +714,95 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 	return res, nil
 }
 
+// processStorageDeposit locks a deposit from caller for every byte of
+// storage that a realm grew by since the last call, and refunds caller
+// a proportional share of its own deposit for the realm for every byte
+// released, up to the storage it paid for.
+func (vm *VMKeeper) processStorageDeposit(ctx sdk.Context, caller crypto.Address, gnostore gno.Store, params Params) error {
+	diffs := gnostore.RealmStorageDiffs()
+	defer clear(diffs)
+	if len(diffs) == 0 {
+		return nil
+	}
+	price := std.MustParseCoin(params.StoragePrice)
+	rlmPaths := make([]string, 0, len(diffs))
+	for rlmPath := range diffs {
+		rlmPaths = append(rlmPaths, rlmPath)
+	}
+	sort.Strings(rlmPaths) // for determinism.
+	for _, rlmPath := range rlmPaths {
+		diff := diffs[rlmPath]
+		pv := gnostore.GetPackage(rlmPath, false)
+		if pv == nil || pv.GetRealm() == nil {
+			// not persisted, e.g. MsgRun's package.
+			continue
+		}
+		rlm := pv.GetRealm()
+		depAddr := gno.DeriveStorageDepositAddr(rlmPath)
+		dep := vm.getStorageDeposit(ctx, rlmPath, caller)
+		if diff > 0 {
+			deposit := uint64(diff) * uint64(price.Amount)
+			if deposit > 0 {
+				coins := std.Coins{std.NewCoin(price.Denom, int64(deposit))}
+				if err := vm.bank.SendCoins(ctx, caller, depAddr, coins); err != nil {
+					return errors.Wrap(err, "storage deposit of %d bytes for %s", diff, rlmPath)
+				}
+			}
+			dep.Deposit += deposit
+			dep.Storage += uint64(diff)
+			rlm.Deposit += deposit
+			rlm.Storage += uint64(diff)
+		} else if diff < 0 {
+			// storage paid for by others, or that predates
+			// deposits, is not refunded.
+			released := uint64(-diff)
+			refund := dep.Deposit
+			if released < dep.Storage {
+				refund = dep.Deposit * released / dep.Storage
+			} else {
+				released = dep.Storage
+			}
+			if refund > 0 {
+				coins := std.Coins{std.NewCoin(price.Denom, int64(refund))}
+				if err := vm.bank.SendCoins(ctx, depAddr, caller, coins); err != nil {
+					return errors.Wrap(err, "storage refund of %d bytes for %s", released, rlmPath)
+				}
+			}
+			dep.Deposit -= refund
+			dep.Storage -= released
+			rlm.Deposit -= refund
+			rlm.Storage -= released
+		}
+		vm.setStorageDeposit(ctx, rlmPath, caller, dep)
+		gnostore.SetPackageRealm(rlm)
+	}
+	return nil
+}
+
+func storageDepositKey(rlmPath string, depositor crypto.Address) []byte {
+	return []byte("deposit:" + rlmPath + ":" + depositor.String())
+}
+
+// getStorageDeposit returns the storage deposit locked by depositor
+// for the realm at rlmPath.
+func (vm *VMKeeper) getStorageDeposit(ctx sdk.Context, rlmPath string, depositor crypto.Address) StorageDeposit {
+	var dep StorageDeposit
+	bz := ctx.Store(vm.iavlKey).Get(storageDepositKey(rlmPath, depositor))
+	if bz != nil {
+		amino.MustUnmarshal(bz, &dep)
+	}
+	return dep
+}
+
+func (vm *VMKeeper) setStorageDeposit(ctx sdk.Context, rlmPath string, depositor crypto.Address, dep StorageDeposit) {
+	key := storageDepositKey(rlmPath, depositor)
+	if dep.Storage == 0 && dep.Deposit == 0 {
+		ctx.Store(vm.iavlKey).Delete(key)
+		return
+	}
+	ctx.Store(vm.iavlKey).Set(key, amino.MustMarshal(dep))
+}
+
 // QueryFuncs returns public facing function signatures.
 func (vm *VMKeeper) QueryFuncs(ctx sdk.Context, pkgPath string) (fsigs FunctionSignatures, err error) {
 	store := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
@@ -643,6 +812,9 @@ func (vm *VMKeeper) QueryFuncs(ctx sdk.Context, pkgPath string) (fsigs FunctionS
 			"package is not realm: %s", pkgPath))
 		return nil, err
 	}
+	if err := vm.fetchPackages(ctx, store, pkgPath); err != nil {
+		return nil, err
+	}
 	// Get Package.
 	pv := store.GetPackage(pkgPath, false)
 	if pv == nil {
@@ -698,9 +870,21 @@ func (vm *VMKeeper) QueryFuncs(ctx sdk.Context, pkgPath string) (fsigs FunctionS
 // TODO: modify query protocol to allow MsgEval.
 // TODO: then, rename to "Eval".
 func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res string, err error) {
+	return vm.queryEval(ctx, pkgPath, expr, false)
+}
+
+// QueryEvalJSON is like QueryEval, but returns the results as a JSON array.
+func (vm *VMKeeper) QueryEvalJSON(ctx sdk.Context, pkgPath string, expr string) (res string, err error) {
+	return vm.queryEval(ctx, pkgPath, expr, true)
+}
+
+func (vm *VMKeeper) queryEval(ctx sdk.Context, pkgPath string, expr string, asJSON bool) (res string, err error) {
 	alloc := gno.NewAllocator(maxAllocQuery)
 	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
 	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	if err := vm.fetchPackages(ctx, gnostore, pkgPath); err != nil {
+		return "", err
+	}
 	// Get Package.
 	pv := gnostore.GetPackage(pkgPath, false)
 	if pv == nil {
@@ -733,7 +917,7 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 			Store:     gnostore,
 			Context:   msgCtx,
 			Alloc:     alloc,
-			MaxCycles: vm.maxCycles,
+			MaxCycles: vm.getParams(ctx).MaxCycles,
 			GasMeter:  ctx.GasMeter(),
 		})
 	defer m.Release()
@@ -750,6 +934,9 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 		}
 	}()
 	rtvs := m.Eval(xx)
+	if asJSON {
+		return string(gno.MarshalJSONValues(gnostore, rtvs)), nil
+	}
 	res = ""
 	for i, rtv := range rtvs {
 		res += rtv.String()
@@ -768,6 +955,9 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 	alloc := gno.NewAllocator(maxAllocQuery)
 	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
 	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	if err := vm.fetchPackages(ctx, gnostore, pkgPath); err != nil {
+		return "", err
+	}
 	// Get Package.
 	pv := gnostore.GetPackage(pkgPath, false)
 	if pv == nil {
@@ -800,7 +990,7 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 			Store:     gnostore,
 			Context:   msgCtx,
 			Alloc:     alloc,
-			MaxCycles: vm.maxCycles,
+			MaxCycles: vm.getParams(ctx).MaxCycles,
 			GasMeter:  ctx.GasMeter(),
 		})
 	defer m.Release()
@@ -829,6 +1019,9 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 func (vm *VMKeeper) QueryFile(ctx sdk.Context, filepath string) (res string, err error) {
 	store := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
 	dirpath, filename := std.SplitFilepath(filepath)
+	if err := vm.fetchPackages(ctx, store, dirpath); err != nil {
+		return "", err
+	}
 	if filename != "" {
 		memFile := store.GetMemFile(dirpath, filename)
 		if memFile == nil {
@@ -881,3 +1074,22 @@ func logTelemetry(
 		metric.WithAttributes(attributes...),
 	)
 }
+
+// logRealmCallTelemetry logs a call to a realm. The called function is not
+// recorded, to bound the number of metric series to the number of realms
+func logRealmCallTelemetry(pkgPath string) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	metrics.VMRealmCalls.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(
+			attribute.KeyValue{
+				Key:   "pkg_path",
+				Value: attribute.StringValue(pkgPath),
+			},
+		),
+	)
+}
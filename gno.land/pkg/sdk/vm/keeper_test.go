@@ -23,6 +23,9 @@ import (
 
 var coinsString = ugnot.ValueString(10000000)
 
+// enough to send coinsString and pay the storage deposit.
+var fundsString = ugnot.ValueString(20000000)
+
 func TestVMKeeperAddPackage(t *testing.T) {
 	env := setupTestEnv()
 	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
@@ -76,8 +79,8 @@ func TestVMKeeperOrigSend1(t *testing.T) {
 	addr := crypto.AddressFromPreimage([]byte("addr1"))
 	acc := env.acck.NewAccountWithAddress(ctx, addr)
 	env.acck.SetAccount(ctx, acc)
-	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
-	assert.True(t, env.bank.GetCoins(ctx, addr).IsEqual(std.MustParseCoins(coinsString)))
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(fundsString))
+	assert.True(t, env.bank.GetCoins(ctx, addr).IsEqual(std.MustParseCoins(fundsString)))
 
 	// Create test package.
 	files := []*std.MemFile{
@@ -219,8 +222,8 @@ func TestVMKeeperRealmSend1(t *testing.T) {
 	addr := crypto.AddressFromPreimage([]byte("addr1"))
 	acc := env.acck.NewAccountWithAddress(ctx, addr)
 	env.acck.SetAccount(ctx, acc)
-	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
-	assert.True(t, env.bank.GetCoins(ctx, addr).IsEqual(std.MustParseCoins(coinsString)))
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(fundsString))
+	assert.True(t, env.bank.GetCoins(ctx, addr).IsEqual(std.MustParseCoins(fundsString)))
 
 	// Create test package.
 	files := []*std.MemFile{
@@ -353,6 +356,387 @@ func GetAdmin() string {
 }
 
 // Call Run without imports, without variables.
+// Storage growth locks a deposit, released storage refunds it.
+func TestVMKeeperStorageDeposit(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+var items []string
+
+func Add(s string) {
+	items = append(items, s)
+}
+
+func Clear() {
+	items = nil
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	depAddr := gnolang.DeriveStorageDepositAddr(pkgPath)
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+	deposit0 := env.bank.GetCoins(ctx, depAddr).AmountOf("ugnot")
+	assert.True(t, deposit0 > 0)
+	assert.Equal(t, deposit0, 10000000-env.bank.GetCoins(ctx, addr).AmountOf("ugnot"))
+
+	// Grow the realm.
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)})
+	_, err = env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	deposit1 := env.bank.GetCoins(ctx, depAddr).AmountOf("ugnot")
+	assert.True(t, deposit1 > deposit0+1000*100)
+	rlm := env.vmk.getGnoTransactionStore(ctx).GetPackageRealm(pkgPath)
+	assert.Equal(t, uint64(deposit1), rlm.Deposit)
+
+	// Shrink it back.
+	msg3 := NewMsgCall(addr, nil, pkgPath, "Clear", []string{})
+	_, err = env.vmk.Call(ctx, msg3)
+	require.NoError(t, err)
+	deposit2 := env.bank.GetCoins(ctx, depAddr).AmountOf("ugnot")
+	assert.True(t, deposit2 < deposit1)
+	assert.Equal(t, 10000000-deposit2, env.bank.GetCoins(ctx, addr).AmountOf("ugnot"))
+}
+
+// Released storage only refunds the deposit of the caller.
+func TestVMKeeperStorageDepositRefundCaller(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" and "addr2" some gnots.
+	addr1 := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	for _, addr := range []crypto.Address{addr1, addr2} {
+		acc := env.acck.NewAccountWithAddress(ctx, addr)
+		env.acck.SetAccount(ctx, acc)
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+	}
+
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+var items []string
+
+func Add(s string) {
+	items = append(items, s)
+}
+
+func Clear() {
+	items = nil
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	depAddr := gnolang.DeriveStorageDepositAddr(pkgPath)
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, pkgPath, files))
+	require.NoError(t, err)
+
+	// "addr1" grows the realm, "addr2" a little.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr1, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)}))
+	require.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr2, nil, pkgPath, "Add", []string{"y"}))
+	require.NoError(t, err)
+	paid2 := 10000000 - env.bank.GetCoins(ctx, addr2).AmountOf("ugnot")
+	require.True(t, paid2 > 0)
+	deposit1 := env.bank.GetCoins(ctx, depAddr).AmountOf("ugnot")
+
+	// "addr2" releases all of it, and only gets its own deposit back.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr2, nil, pkgPath, "Clear", []string{}))
+	require.NoError(t, err)
+	assert.Equal(t, std.MustParseCoins(coinsString), env.bank.GetCoins(ctx, addr2))
+	assert.Equal(t, deposit1-paid2, env.bank.GetCoins(ctx, depAddr).AmountOf("ugnot"))
+	assert.Equal(t, StorageDeposit{}, env.vmk.getStorageDeposit(ctx, pkgPath, addr2))
+	dep1 := env.vmk.getStorageDeposit(ctx, pkgPath, addr1)
+	assert.Equal(t, uint64(deposit1-paid2), dep1.Deposit)
+	rlm := env.vmk.getGnoTransactionStore(ctx).GetPackageRealm(pkgPath)
+	assert.Equal(t, dep1.Deposit, rlm.Deposit)
+	assert.Equal(t, dep1.Storage, rlm.Storage)
+}
+
+// The storage price and the cycles limit are read from the params keeper.
+func TestVMKeeperParams(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Make storage free.
+	require.NoError(t, env.prmk.SetParam(ctx, "vm.storage_price", "0ugnot"))
+
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+func Loop() int {
+	n := 0
+	for i := 0; i < 100000; i++ {
+		n += i
+	}
+	return n
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	assert.True(t, env.bank.GetCoins(ctx, gnolang.DeriveStorageDepositAddr(pkgPath)).IsZero())
+	assert.Equal(t, std.MustParseCoins(coinsString), env.bank.GetCoins(ctx, addr))
+
+	// Lower the cycles limit below what Loop needs.
+	require.NoError(t, env.prmk.SetParam(ctx, "vm.max_cycles", "1000"))
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Loop", []string{}))
+	assert.ErrorContains(t, err, "CPU cycle overrun")
+
+	// Invalid values are rejected.
+	assert.Error(t, env.prmk.SetParam(ctx, "vm.max_cycles", "-1"))
+	assert.Error(t, env.prmk.SetParam(ctx, "vm.storage_price", "foo"))
+	assert.Error(t, env.prmk.SetParam(ctx, "vm.storage_gas_per_byte", "-1"))
+}
+
+// The gas charged per byte of storage is read from the params keeper.
+func TestVMKeeperStorageGasParam(t *testing.T) {
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+var items []string
+
+func Add(s string) {
+	items = append(items, s)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+
+	addGas := func(gasPerByte string) int64 {
+		env := setupTestEnv()
+		ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+		addr := crypto.AddressFromPreimage([]byte("addr1"))
+		acc := env.acck.NewAccountWithAddress(ctx, addr)
+		env.acck.SetAccount(ctx, acc)
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+		require.NoError(t, env.prmk.SetParam(ctx, "vm.storage_gas_per_byte", gasPerByte))
+		require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+		env.vmk.CommitGnoTransactionStore(ctx)
+
+		ctx = env.vmk.MakeGnoTransactionStore(env.ctx.WithGasMeter(types.NewInfiniteGasMeter()))
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)}))
+		require.NoError(t, err)
+		return ctx.GasMeter().GasConsumed()
+	}
+
+	// Writing over 1000 bytes costs at least 1000 gas per unit of the param.
+	gas0, gas10 := addGas("0"), addGas("10")
+	assert.GreaterOrEqual(t, gas10-gas0, int64(10*1000))
+}
+
+// Add new versions of a pure package.
+func TestVMKeeperAddPackageVersions(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" and "addr2" some gnots.
+	addr1 := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	for _, addr := range []crypto.Address{addr1, addr2} {
+		acc := env.acck.NewAccountWithAddress(ctx, addr)
+		env.acck.SetAccount(ctx, acc)
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+	}
+
+	files := func(doc string) []*std.MemFile {
+		return []*std.MemFile{
+			{"foo.gno", doc + `
+package foo
+
+func Hello() string { return "hello" }`},
+		}
+	}
+	const pkgPath = "gno.land/p/demo/foo"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, pkgPath, files("")))
+	require.NoError(t, err)
+
+	// Only the creator of the previous version may add a new one.
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, pkgPath+"/v2", files("")))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, UnauthorizedUserError{}))
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, pkgPath+"/v3", files("")))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, InvalidPkgPathError{}))
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, pkgPath+"/v2", files("")))
+	require.NoError(t, err)
+
+	info, err := env.vmk.QueryPackageInfo(ctx, pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, &PackageInfo{
+		Path:      pkgPath,
+		Creator:   addr1,
+		Successor: pkgPath + "/v2",
+		Latest:    pkgPath + "/v2",
+	}, info)
+
+	// Deprecated versions are skipped.
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, pkgPath+"/v3", files("// Deprecated: broken.")))
+	require.NoError(t, err)
+	info, err = env.vmk.QueryPackageInfo(ctx, pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, pkgPath+"/v2", info.Latest)
+	info, err = env.vmk.QueryPackageInfo(ctx, pkgPath+"/v3")
+	require.NoError(t, err)
+	assert.Equal(t, "broken.", info.Deprecated)
+	assert.Equal(t, "", info.Latest)
+
+	_, err = env.vmk.QueryPackageInfo(ctx, "gno.land/p/demo/bar")
+	assert.True(t, errors.Is(err, InvalidPkgPathError{}))
+}
+
+// Deprecate published versions.
+func TestVMKeeperDeprecate(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" and "addr2" some gnots.
+	addr1 := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	for _, addr := range []crypto.Address{addr1, addr2} {
+		acc := env.acck.NewAccountWithAddress(ctx, addr)
+		env.acck.SetAccount(ctx, acc)
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+	}
+
+	files := []*std.MemFile{
+		{"foo.gno", `
+package foo
+
+func Hello() string { return "hello" }`},
+	}
+	const pkgPath = "gno.land/p/demo/foo"
+	for _, path := range []string{pkgPath, pkgPath + "/v2"} {
+		err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, path, files))
+		require.NoError(t, err)
+	}
+
+	// Only the creator of a package may deprecate it.
+	msg := NewMsgDeprecate(addr2, pkgPath+"/v2", "broken.")
+	require.NoError(t, msg.ValidateBasic())
+	err := env.vmk.Deprecate(ctx, msg)
+	assert.True(t, errors.Is(err, UnauthorizedUserError{}))
+	err = env.vmk.Deprecate(ctx, NewMsgDeprecate(addr1, pkgPath+"/v3", "broken."))
+	assert.True(t, errors.Is(err, InvalidPkgPathError{}))
+	assert.Error(t, NewMsgDeprecate(addr1, pkgPath+"/v2", " ").ValidateBasic())
+
+	// Deprecated versions are skipped.
+	err = env.vmk.Deprecate(ctx, NewMsgDeprecate(addr1, pkgPath+"/v2", "broken,\n  use v1."))
+	require.NoError(t, err)
+	info, err := env.vmk.QueryPackageInfo(ctx, pkgPath+"/v2")
+	require.NoError(t, err)
+	assert.Equal(t, "broken, use v1.", info.Deprecated)
+	info, err = env.vmk.QueryPackageInfo(ctx, pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, pkgPath, info.Latest)
+
+	// The notice is replaced.
+	err = env.vmk.Deprecate(ctx, NewMsgDeprecate(addr1, pkgPath+"/v2", "use v1."))
+	require.NoError(t, err)
+	info, err = env.vmk.QueryPackageInfo(ctx, pkgPath+"/v2")
+	require.NoError(t, err)
+	assert.Equal(t, "use v1.", info.Deprecated)
+
+	// No version is left.
+	err = env.vmk.Deprecate(ctx, NewMsgDeprecate(addr1, pkgPath, "unmaintained."))
+	require.NoError(t, err)
+	info, err = env.vmk.QueryPackageInfo(ctx, pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, "unmaintained.", info.Deprecated)
+	assert.Equal(t, "", info.Latest)
+}
+
+// Call with JSON encoded arguments and results.
+func TestVMKeeperCallJSON(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+import "std"
+
+type Item struct {
+	Name  string ` + "`json:\"name\"`" + `
+	Tags  []string
+	Price std.Coins
+	Owner std.Address
+	Next  *Item
+	count int
+}
+
+var items []Item
+
+func Add(it Item, counts map[string]int) (int, Item) {
+	it.count = counts[it.Name]
+	items = append(items, it)
+	return len(items), it
+}
+
+func Get(i int) *Item {
+	if i >= len(items) {
+		return nil
+	}
+	return &items[i]
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+
+	// Call Add with a struct and a map.
+	owner := "g1wymu47drhr0kuq2098m792lytgtj2nyx77yrsm"
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Add", []string{
+		`{"name":"foo","Tags":["a","b"],"Price":"10ugnot","Owner":"` + owner + `","Next":{"name":"bar"}}`,
+		`{"foo":3}`,
+	})
+	msg2.JSON = true
+	res, err := env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	assert.Equal(t, `[1,{"name":"foo","Tags":["a","b"],"Price":[{"denom":"ugnot","amount":10}],"Owner":"`+owner+`","Next":{"name":"bar","Tags":null,"Price":null,"Owner":"","Next":null}}]`+"\n\n", res)
+
+	// Unknown fields are rejected.
+	msg3 := NewMsgCall(addr, nil, pkgPath, "Add", []string{`{"count":1}`, `{}`})
+	msg3.JSON = true
+	assert.Panics(t, func() { env.vmk.Call(ctx, msg3) })
+
+	// Query the results as JSON.
+	env.vmk.CommitGnoTransactionStore(ctx)
+	res, err = env.vmk.QueryEvalJSON(ctx, pkgPath, "Get(0)")
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"foo","Tags":["a","b"],"Price":[{"denom":"ugnot","amount":10}],"Owner":"`+owner+`","Next":{"name":"bar","Tags":null,"Price":null,"Owner":"","Next":null}}]`, res)
+	res, err = env.vmk.QueryEvalJSON(ctx, pkgPath, "Get(1)")
+	require.NoError(t, err)
+	assert.Equal(t, `[null]`, res)
+}
+
 func TestVMKeeperRunSimple(t *testing.T) {
 	env := setupTestEnv()
 	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
@@ -504,6 +888,262 @@ func Echo(msg string) string {
 	assert.Equal(t, `("echo:hello world" string)`+"\n\n", res)
 }
 
+// The gas used by removing from a realm's doubly-linked list, which makes
+// the rest of the list a candidate of cycle collection, must not grow with
+// the size of the list.
+func TestVMKeeperCycleCollectionGas(t *testing.T) {
+	files := []*std.MemFile{
+		{"list.gno", `
+package list
+
+type node struct {
+	prev, next *node
+}
+
+var head, tail *node
+
+func Push(n int) {
+	for i := 0; i < n; i++ {
+		nd := &node{prev: tail}
+		if tail != nil {
+			tail.next = nd
+		} else {
+			head = nd
+		}
+		tail = nd
+	}
+}
+
+func Pop() {
+	head = head.next
+	head.prev = nil
+}`},
+	}
+	pkgPath := "gno.land/r/list"
+
+	popGas := func(size int) int64 {
+		env := setupTestEnv()
+		ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+		addr := crypto.AddressFromPreimage([]byte("addr1"))
+		acc := env.acck.NewAccountWithAddress(ctx, addr)
+		env.acck.SetAccount(ctx, acc)
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins(ugnot.ValueString(1_000_000_000_000)))
+		require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Push", []string{fmt.Sprint(size)}))
+		require.NoError(t, err)
+		env.vmk.CommitGnoTransactionStore(ctx)
+
+		ctx = env.vmk.MakeGnoTransactionStore(env.ctx.WithGasMeter(types.NewInfiniteGasMeter()))
+		_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Pop", nil))
+		require.NoError(t, err)
+		return ctx.GasMeter().GasConsumed()
+	}
+
+	gas200, gas2000 := popGas(200), popGas(2000)
+	assert.InEpsilon(t, gas200, gas2000, 0.01)
+}
+
+// Cycle candidates that don't fit in the budget of a transaction are
+// collected by the next transaction of the realm.
+func TestVMKeeperCycleCollectionCarryOver(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(fundsString))
+
+	files := []*std.MemFile{
+		{"rings.gno", `
+package rings
+
+type node struct {
+	prev, next *node
+}
+
+var (
+	rings []*node
+	count int
+)
+
+func Add(n int) {
+	head := &node{}
+	head.prev, head.next = head, head
+	for i := 1; i < n; i++ {
+		nd := &node{prev: head.prev, next: head}
+		head.prev.next = nd
+		head.prev = nd
+	}
+	rings = append(rings, head)
+}
+
+func Drop() {
+	rings = nil
+}
+
+func GrowAndDrop() {
+	head := rings[len(rings)-1]
+	nd := &node{prev: head, next: head.next}
+	head.next.prev = nd
+	head.next = nd
+	rings = nil
+}
+
+func Touch() {
+	count++
+}`},
+	}
+	pkgPath := "gno.land/r/rings"
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+	call := func(fn string, args ...string) {
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, fn, args))
+		require.NoError(t, err)
+	}
+	realm := func() *gnolang.Realm {
+		return env.vmk.getGnoTransactionStore(ctx).GetPackageRealm(pkgPath)
+	}
+	call("Touch")
+	storage0 := realm().Storage
+
+	// Each ring fits in the budget, but not both.
+	call("Add", "30")
+	call("Add", "30")
+	call("Drop")
+	rlm := realm()
+	assert.Len(t, rlm.CycleCandidates, 1)
+	assert.Greater(t, rlm.Storage, storage0)
+
+	call("Touch")
+	rlm = realm()
+	assert.Empty(t, rlm.CycleCandidates)
+	assert.Equal(t, storage0, rlm.Storage)
+}
+
+// Garbage cycles too large for a transaction are collected over several
+// ones, while live ones are kept, even if modified during the trial.
+func TestVMKeeperCycleCollectionTrial(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(ugnot.ValueString(1_000_000_000_000)))
+
+	files := []*std.MemFile{
+		{"rings.gno", `
+package rings
+
+type node struct {
+	prev, next *node
+	n          int
+}
+
+var (
+	rings []*node
+	kept  *node
+	count int
+)
+
+func Add(n int) {
+	head := &node{}
+	head.prev, head.next = head, head
+	for i := 1; i < n; i++ {
+		nd := &node{prev: head.prev, next: head, n: i}
+		head.prev.next = nd
+		head.prev = nd
+	}
+	rings = append(rings, head)
+}
+
+func Keep() {
+	kept = rings[len(rings)-1]
+}
+
+func Drop() {
+	rings = nil
+}
+
+func Bump() {
+	kept.next.next.n++
+}
+
+func Len() int {
+	n := 1
+	for nd := kept.next; nd != kept; nd = nd.next {
+		n++
+	}
+	return n
+}
+
+func GrowAndDrop() {
+	head := rings[len(rings)-1]
+	nd := &node{prev: head, next: head.next}
+	head.next.prev = nd
+	head.next = nd
+	rings = nil
+}
+
+func Touch() {
+	count++
+}`},
+	}
+	pkgPath := "gno.land/r/rings"
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+	call := func(fn string, args ...string) string {
+		res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, fn, args))
+		require.NoError(t, err)
+		return res
+	}
+	realm := func() *gnolang.Realm {
+		return env.vmk.getGnoTransactionStore(ctx).GetPackageRealm(pkgPath)
+	}
+	call("Touch")
+	storage0 := realm().Storage
+
+	// A garbage ring larger than the budget.
+	call("Add", "300")
+	call("Drop")
+	rlm := realm()
+	require.NotNil(t, rlm.CycleTrial)
+	require.Len(t, rlm.CycleTrial.Roots, 1)
+	assert.Greater(t, rlm.Storage, storage0)
+	txs := 1
+	for ; realm().CycleTrial != nil && txs < 100; txs++ {
+		call("Touch")
+	}
+	assert.Greater(t, txs, 2)
+	assert.Nil(t, realm().CycleTrial)
+	// give or take the varints of later modification times.
+	assert.InDelta(t, storage0, realm().Storage, 8)
+
+	// A live ring, modified during the trial.
+	call("Add", "300")
+	call("Keep")
+	call("Drop")
+	require.NotNil(t, realm().CycleTrial)
+	require.Len(t, realm().CycleTrial.Roots, 1)
+	call("Touch")
+	call("Bump")
+	for txs = 0; realm().CycleTrial != nil && txs < 100; txs++ {
+		call("Touch")
+	}
+	assert.Nil(t, realm().CycleTrial)
+	assert.Equal(t, "(300 int)\n\n", call("Len"))
+
+	// A garbage ring, with objects not saved yet when the trial starts.
+	storage0 = realm().Storage
+	call("Add", "300")
+	call("GrowAndDrop")
+	require.NotNil(t, realm().CycleTrial)
+	for txs = 0; realm().CycleTrial != nil && txs < 100; txs++ {
+		call("Touch")
+	}
+	assert.Nil(t, realm().CycleTrial)
+	assert.InDelta(t, storage0, realm().Storage, 8)
+}
+
 func Test_loadStdlibPackage(t *testing.T) {
 	mdb := memdb.NewMemDB()
 	cs := dbadapter.StoreConstructor(mdb, types.StoreOptions{})
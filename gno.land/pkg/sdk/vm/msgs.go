@@ -88,6 +88,8 @@ type MsgCall struct {
 	PkgPath string         `json:"pkg_path" yaml:"pkg_path"`
 	Func    string         `json:"func" yaml:"func"`
 	Args    []string       `json:"args" yaml:"args"`
+	// If true, Args are JSON encoded and results are returned as JSON.
+	JSON bool `json:"json,omitempty" yaml:"json"`
 }
 
 var _ std.Msg = MsgCall{}
@@ -207,3 +209,55 @@ func (msg MsgRun) GetSigners() []crypto.Address {
 func (msg MsgRun) GetReceived() std.Coins {
 	return msg.Send
 }
+
+//----------------------------------------
+// MsgDeprecate
+
+// MsgDeprecate - marks a package as deprecated.
+type MsgDeprecate struct {
+	Creator crypto.Address `json:"creator" yaml:"creator"`
+	PkgPath string         `json:"pkg_path" yaml:"pkg_path"`
+	Notice  string         `json:"notice" yaml:"notice"`
+}
+
+var _ std.Msg = MsgDeprecate{}
+
+// NewMsgDeprecate - deprecate the package at pkgPath, with a notice
+// such as "use gno.land/p/demo/foo/v2 instead."
+func NewMsgDeprecate(creator crypto.Address, pkgPath, notice string) MsgDeprecate {
+	return MsgDeprecate{
+		Creator: creator,
+		PkgPath: pkgPath,
+		Notice:  notice,
+	}
+}
+
+// Implements Msg.
+func (msg MsgDeprecate) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgDeprecate) Type() string { return "deprecate" }
+
+// Implements Msg.
+func (msg MsgDeprecate) ValidateBasic() error {
+	if msg.Creator.IsZero() {
+		return std.ErrInvalidAddress("missing creator address")
+	}
+	if msg.PkgPath == "" {
+		return ErrInvalidPkgPath("missing package path")
+	}
+	if strings.TrimSpace(msg.Notice) == "" {
+		return std.ErrUnknownRequest("missing deprecation notice")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgDeprecate) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgDeprecate) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Creator}
+}
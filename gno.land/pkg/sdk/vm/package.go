@@ -15,6 +15,9 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	MsgCall{}, "m_call",
 	MsgRun{}, "m_run",
 	MsgAddPackage{}, "m_addpkg", // TODO rename both to MsgAddPkg?
+	MsgDeprecate{}, "m_deprecate",
+	PackageInfo{}, "PackageInfo",
+	StorageDeposit{}, "StorageDeposit",
 
 	// errors
 	InvalidPkgPathError{}, "InvalidPkgPathError",
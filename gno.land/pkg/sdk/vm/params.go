@@ -0,0 +1,69 @@
+package vm
+
+import (
+	"fmt"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Default parameter values
+const (
+	DefaultMaxCycles int64 = 0 // no limit
+
+	// deposit locked per byte of realm storage;
+	// refunded when the storage is released.
+	DefaultStoragePrice = "100ugnot"
+
+	// gas charged per byte of realm storage written.
+	DefaultStorageGasPerByte int64 = 16
+)
+
+// Params defines the parameters for the vm module.
+type Params struct {
+	MaxCycles         int64  `json:"max_cycles" yaml:"max_cycles"`                     // max allowed cycles on VM executions, or 0 for no limit
+	StoragePrice      string `json:"storage_price" yaml:"storage_price"`               // deposit per byte of realm storage
+	StorageGasPerByte int64  `json:"storage_gas_per_byte" yaml:"storage_gas_per_byte"` // gas per byte of realm storage written
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{
+		MaxCycles:         DefaultMaxCycles,
+		StoragePrice:      DefaultStoragePrice,
+		StorageGasPerByte: DefaultStorageGasPerByte,
+	}
+}
+
+// Validate returns an error if any of the params is invalid.
+func (p Params) Validate() error {
+	if p.MaxCycles < 0 {
+		return fmt.Errorf("invalid max cycles: %d", p.MaxCycles)
+	}
+	price, err := std.ParseCoin(p.StoragePrice)
+	if err != nil {
+		return fmt.Errorf("invalid storage price: %w", err)
+	}
+	if price.IsNegative() {
+		return fmt.Errorf("invalid storage price: %s", p.StoragePrice)
+	}
+	if p.StorageGasPerByte < 0 {
+		return fmt.Errorf("invalid storage gas per byte: %d", p.StorageGasPerByte)
+	}
+	return nil
+}
+
+// gasConfig returns the gas config of the gno store for params.
+func (p Params) gasConfig() gno.GasConfig {
+	return gno.GasConfig{
+		GasSetObject: p.StorageGasPerByte,
+	}
+}
+
+// getParams returns the current vm params.
+func (vm *VMKeeper) getParams(ctx sdk.Context) Params {
+	var params Params
+	vm.prmk.GetParams(ctx, ModuleName, &params)
+	return params
+}
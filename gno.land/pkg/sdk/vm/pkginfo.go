@@ -0,0 +1,123 @@
+package vm
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Versioned pure package paths, e.g. gno.land/p/demo/avl/v2.
+var reVersionedPath = regexp.MustCompile(`^(.+)/v([1-9][0-9]*)$`)
+
+// previousVersion returns the path of the version preceding pkgPath,
+// or false if pkgPath is not the path of a new version of a pure package.
+// The version preceding "<path>/v2" is "<path>".
+func previousVersion(pkgPath string) (string, bool) {
+	if gno.IsRealmPath(pkgPath) {
+		return "", false
+	}
+	match := reVersionedPath.FindStringSubmatch(pkgPath)
+	if match == nil {
+		return "", false
+	}
+	n, err := strconv.Atoi(match[2])
+	switch {
+	case err != nil || n < 2:
+		return "", false
+	case n == 2:
+		return match[1], true
+	default:
+		return fmt.Sprintf("%s/v%d", match[1], n-1), true
+	}
+}
+
+// deprecationNotice returns the text of the "Deprecated: " paragraph of
+// the package doc comment, following the Go convention, or "" if none.
+func deprecationNotice(memPkg *std.MemPackage) string {
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), mfile.Name, mfile.Body,
+			parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil || f.Doc == nil {
+			continue
+		}
+		for _, para := range strings.Split(f.Doc.Text(), "\n\n") {
+			if rest, ok := strings.CutPrefix(para, "Deprecated: "); ok {
+				return strings.Join(strings.Fields(rest), " ")
+			}
+		}
+	}
+	return ""
+}
+
+func packageInfoKey(pkgPath string) []byte {
+	return []byte("pkginfo:" + pkgPath)
+}
+
+// getPackageInfo returns the metadata of the package at pkgPath,
+// or nil if it was not added with MsgAddPackage.
+func (vm *VMKeeper) getPackageInfo(ctx sdk.Context, pkgPath string) *PackageInfo {
+	bz := ctx.Store(vm.iavlKey).Get(packageInfoKey(pkgPath))
+	if bz == nil {
+		return nil
+	}
+	info := new(PackageInfo)
+	amino.MustUnmarshal(bz, info)
+	return info
+}
+
+func (vm *VMKeeper) setPackageInfo(ctx sdk.Context, info *PackageInfo) {
+	ctx.Store(vm.iavlKey).Set(packageInfoKey(info.Path), amino.MustMarshal(info))
+}
+
+// Deprecate marks the package at msg.PkgPath as deprecated, replacing its
+// deprecation notice if any. Only the creator of the package may do so.
+func (vm *VMKeeper) Deprecate(ctx sdk.Context, msg MsgDeprecate) error {
+	info := vm.getPackageInfo(ctx, msg.PkgPath)
+	if info == nil {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", msg.PkgPath))
+	}
+	if info.Creator != msg.Creator {
+		return ErrUnauthorizedUser(fmt.Sprintf(
+			"%s is not the creator of %s", msg.Creator, msg.PkgPath))
+	}
+	info.Deprecated = strings.Join(strings.Fields(msg.Notice), " ")
+	vm.setPackageInfo(ctx, info)
+	return nil
+}
+
+// QueryPackageInfo returns the metadata of the package at pkgPath,
+// along with the latest non-deprecated version of it.
+func (vm *VMKeeper) QueryPackageInfo(ctx sdk.Context, pkgPath string) (*PackageInfo, error) {
+	info := vm.getPackageInfo(ctx, pkgPath)
+	if info == nil {
+		// Packages loaded otherwise (e.g. stdlibs) have no metadata.
+		gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+		if gnostore.GetMemPackage(pkgPath) == nil {
+			return nil, ErrInvalidPkgPath(fmt.Sprintf(
+				"package not found: %s", pkgPath))
+		}
+		return &PackageInfo{Path: pkgPath, Latest: pkgPath}, nil
+	}
+	for next := info; next != nil; {
+		if next.Deprecated == "" {
+			info.Latest = next.Path
+		}
+		if next.Successor == "" {
+			break
+		}
+		next = vm.getPackageInfo(ctx, next.Successor)
+	}
+	return info, nil
+}
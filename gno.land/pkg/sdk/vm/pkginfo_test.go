@@ -0,0 +1,50 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		path, prev string
+		ok         bool
+	}{
+		{"gno.land/p/demo/avl", "", false},
+		{"gno.land/p/demo/avl/v1", "", false},
+		{"gno.land/p/demo/avl/v01", "", false},
+		{"gno.land/p/demo/avl/v2", "gno.land/p/demo/avl", true},
+		{"gno.land/p/demo/avl/v10", "gno.land/p/demo/avl/v9", true},
+		{"gno.land/r/demo/boards/v2", "", false},
+	}
+	for _, tc := range cases {
+		prev, ok := previousVersion(tc.path)
+		assert.Equal(t, tc.prev, prev, tc.path)
+		assert.Equal(t, tc.ok, ok, tc.path)
+	}
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		body, want string
+	}{
+		{"package foo", ""},
+		{"// Package foo does things.\npackage foo", ""},
+		{"// Package foo does things.\n//\n// Deprecated: use\n// bar instead.\npackage foo", "use bar instead."},
+		{"package foo\n\n// Deprecated: not a package doc.\nfunc F() {}", ""},
+	}
+	for _, tc := range cases {
+		memPkg := &std.MemPackage{
+			Name:  "foo",
+			Path:  "gno.land/p/demo/foo",
+			Files: []*std.MemFile{{Name: "foo.gno", Body: tc.body}},
+		}
+		assert.Equal(t, tc.want, deprecationNotice(memPkg), tc.body)
+	}
+}
@@ -1,6 +1,9 @@
 package vm
 
-import "github.com/gnolang/gno/tm2/pkg/amino"
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+)
 
 // Public facing function signatures.
 // See convertArgToGno() for supported types.
@@ -22,3 +25,29 @@ func (fsigs FunctionSignatures) JSON() string {
 	bz := amino.MustMarshalJSON(fsigs)
 	return string(bz)
 }
+
+// Metadata recorded for each package added with MsgAddPackage.
+type PackageInfo struct {
+	Path    string         `json:"path"`
+	Creator crypto.Address `json:"creator"`
+	// Path of the next version of the package, if any.
+	Successor string `json:"successor,omitempty"`
+	// Deprecation message from the "Deprecated: " paragraph of the package
+	// doc comment, or from the latest MsgDeprecate of the creator, if any.
+	Deprecated string `json:"deprecated,omitempty"`
+	// Latest non-deprecated version following Successor pointers,
+	// starting from Path. Only set by QueryPackageInfo.
+	Latest string `json:"latest,omitempty"`
+}
+
+func (info PackageInfo) JSON() string {
+	bz := amino.MustMarshalJSON(info)
+	return string(bz)
+}
+
+// Storage deposit locked by an account for a realm, refunded to it alone
+// when it releases storage of the realm.
+type StorageDeposit struct {
+	Deposit uint64 `json:"deposit"` // amount locked, in the denom of the storage price.
+	Storage uint64 `json:"storage"` // bytes of storage covered by the deposit.
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Cover modes supported by -covermode.
+const (
+	coverModeSet   = "set"
+	coverModeCount = "count"
+)
+
+// coverBlock is a basic block of a source file: a sequence of statements
+// which are always executed together, unless one of them panics. The blocks
+// match the ones of the Go cover tool, so that the resulting profiles can be
+// read by Go tooling.
+type coverBlock struct {
+	file                string
+	startLine, startCol int
+	endLine, endCol     int
+	numStmts            int
+	stmts               []token.Position // start of the statements of the block
+	count               int64
+}
+
+// pkgCoverage holds the coverage data of a package under test.
+type pkgCoverage struct {
+	pkgPath string
+	blocks  []*coverBlock
+}
+
+// newPkgCoverage splits the source files of memPkg into basic blocks; test
+// files are not part of the coverage.
+func newPkgCoverage(memPkg *std.MemPackage) (*pkgCoverage, error) {
+	pc := &pkgCoverage{pkgPath: memPkg.Path}
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, mfile.Name, mfile.Body, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", mfile.Name, err)
+		}
+		bv := &blockVisitor{fset: fset, file: mfile.Name}
+		ast.Inspect(f, bv.visit)
+		pc.blocks = append(pc.blocks, bv.blocks...)
+	}
+	return pc, nil
+}
+
+// update sets the count of each block from the statements recorded in c. A
+// block counts as executed as many times as its most executed statement.
+func (pc *pkgCoverage) update(c *gno.Coverage) {
+	for _, b := range pc.blocks {
+		for _, pos := range b.stmts {
+			count := c.Count(gno.Location{
+				PkgPath: pc.pkgPath,
+				File:    b.file,
+				Line:    pos.Line,
+				Column:  pos.Column,
+			})
+			b.count = max(b.count, count)
+		}
+	}
+}
+
+// percent returns the percentage of statements executed, and false if the
+// package has no statements.
+func (pc *pkgCoverage) percent() (float64, bool) {
+	var total, covered int
+	for _, b := range pc.blocks {
+		total += b.numStmts
+		if b.count > 0 {
+			covered += b.numStmts
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return 100 * float64(covered) / float64(total), true
+}
+
+// String returns the coverage summary printed after the test results.
+func (pc *pkgCoverage) String() string {
+	pct, ok := pc.percent()
+	if !ok {
+		return "coverage: [no statements]"
+	}
+	return fmt.Sprintf("coverage: %.1f%% of statements", pct)
+}
+
+// writeCoverProfileFile writes the coverage data of pkgs to the file at path.
+func writeCoverProfileFile(path, mode string, pkgs []*pkgCoverage) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeCoverProfile(f, mode, pkgs); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeCoverProfile writes the coverage data of pkgs to w, in the format of
+// Go cover profiles.
+func writeCoverProfile(w io.Writer, mode string, pkgs []*pkgCoverage) error {
+	if _, err := fmt.Fprintf(w, "mode: %s\n", mode); err != nil {
+		return err
+	}
+	for _, pc := range pkgs {
+		for _, b := range pc.blocks {
+			count := b.count
+			if mode == coverModeSet && count > 0 {
+				count = 1
+			}
+			_, err := fmt.Fprintf(w, "%s/%s:%d.%d,%d.%d %d %d\n",
+				pc.pkgPath, b.file,
+				b.startLine, b.startCol, b.endLine, b.endCol,
+				b.numStmts, count)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//----------------------------------------
+// basic blocks, adapted from cmd/cover.
+
+type blockVisitor struct {
+	fset   *token.FileSet
+	file   string
+	blocks []*coverBlock
+}
+
+func (bv *blockVisitor) visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.BlockStmt:
+		// the bodies of switch and select statements are lists of
+		// clauses, which are handled separately.
+		if len(n.List) > 0 {
+			switch n.List[0].(type) {
+			case *ast.CaseClause, *ast.CommClause:
+				return true
+			}
+		}
+		bv.addBlocks(n.Lbrace, n.Rbrace+1, n.List, true)
+	case *ast.CaseClause:
+		bv.addBlocks(n.Colon+1, n.End(), n.Body, false)
+	case *ast.CommClause:
+		bv.addBlocks(n.Colon+1, n.End(), n.Body, false)
+	}
+	return true
+}
+
+// addBlocks splits list, the statements of a block going from pos to
+// blockEnd, into basic blocks.
+func (bv *blockVisitor) addBlocks(pos, blockEnd token.Pos, list []ast.Stmt, extendToClosingBrace bool) {
+	for len(list) > 0 {
+		// Find the first statement that affects the flow of control; it
+		// is the last statement of this basic block.
+		var last int
+		end := blockEnd
+		for last = 0; last < len(list); last++ {
+			stmt := list[last]
+			end = statementBoundary(stmt)
+			if endsBasicBlock(stmt) {
+				last++
+				extendToClosingBrace = false // block is broken up now.
+				break
+			}
+		}
+		if extendToClosingBrace {
+			end = blockEnd
+		}
+		if pos != end {
+			bv.addBlock(pos, end, list[:last])
+		}
+		list = list[last:]
+		if len(list) > 0 {
+			pos = list[0].Pos()
+		}
+	}
+}
+
+func (bv *blockVisitor) addBlock(start, end token.Pos, stmts []ast.Stmt) {
+	s, e := bv.fset.Position(start), bv.fset.Position(end)
+	b := &coverBlock{
+		file:      bv.file,
+		startLine: s.Line,
+		startCol:  s.Column,
+		endLine:   e.Line,
+		endCol:    e.Column,
+		numStmts:  len(stmts),
+	}
+	for _, stmt := range stmts {
+		b.stmts = append(b.stmts, bv.fset.Position(stmt.Pos()))
+	}
+	bv.blocks = append(bv.blocks, b)
+}
+
+// statementBoundary returns the position of the end of the part of s which
+// belongs to the current basic block.
+func statementBoundary(s ast.Stmt) token.Pos {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		return s.Lbrace
+	case *ast.IfStmt:
+		if lit := firstFuncLit(s.Init, s.Cond); lit != nil {
+			return lit.Pos()
+		}
+		return s.Body.Lbrace
+	case *ast.ForStmt:
+		if lit := firstFuncLit(s.Init, s.Cond, s.Post); lit != nil {
+			return lit.Pos()
+		}
+		return s.Body.Lbrace
+	case *ast.LabeledStmt:
+		return statementBoundary(s.Stmt)
+	case *ast.RangeStmt:
+		if lit := firstFuncLit(s.X); lit != nil {
+			return lit.Pos()
+		}
+		return s.Body.Lbrace
+	case *ast.SwitchStmt:
+		if lit := firstFuncLit(s.Init, s.Tag); lit != nil {
+			return lit.Pos()
+		}
+		return s.Body.Lbrace
+	case *ast.SelectStmt:
+		return s.Body.Lbrace
+	case *ast.TypeSwitchStmt:
+		if lit := firstFuncLit(s.Init); lit != nil {
+			return lit.Pos()
+		}
+		return s.Body.Lbrace
+	}
+	if lit := firstFuncLit(s); lit != nil {
+		return lit.Pos()
+	}
+	return s.End()
+}
+
+// endsBasicBlock reports whether s changes the flow of control, ending the
+// current basic block.
+func endsBasicBlock(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.BlockStmt, *ast.BranchStmt, *ast.ForStmt, *ast.IfStmt,
+		*ast.LabeledStmt, *ast.RangeStmt, *ast.SwitchStmt,
+		*ast.SelectStmt, *ast.TypeSwitchStmt:
+		return true
+	case *ast.ExprStmt:
+		// Calls to panic change the flow.
+		if call, ok := s.X.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "panic" && len(call.Args) == 1 {
+				return true
+			}
+		}
+	}
+	return firstFuncLit(s) != nil
+}
+
+// firstFuncLit returns the first function literal found in nodes, if any.
+func firstFuncLit(nodes ...ast.Node) (lit *ast.FuncLit) {
+	for _, n := range nodes {
+		if n == nil || lit != nil {
+			continue
+		}
+		ast.Inspect(n, func(n ast.Node) bool {
+			if fl, ok := n.(*ast.FuncLit); ok && lit == nil {
+				lit = fl
+			}
+			return lit == nil
+		})
+	}
+	return lit
+}
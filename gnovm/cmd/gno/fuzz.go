@@ -0,0 +1,992 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// fuzzCorpusHeader is the first line of the files of a fuzz corpus. The
+// format of the files is the same as Go's, so that the corpus can be
+// inspected and edited with the same tools.
+const fuzzCorpusHeader = "go test fuzz v1"
+
+const (
+	// maximum length of the string and []byte values generated by the
+	// fuzzing engine.
+	fuzzMaxLen = 4096
+	// interval between the progress reports while fuzzing.
+	fuzzReportInterval = 3 * time.Second
+)
+
+// fuzzTest runs a fuzz test of the package loaded in m, through the functions
+// generated in the testmain file: fuzzprepare runs the body of the fuzz test,
+// then each input is passed to the fuzz target through a function generated
+// for its signature by fuzzTest.load.
+type fuzzTest struct {
+	m       *gno.Machine
+	name    string
+	pkgDir  string
+	pkgName string
+	id      int // unique id in the package, to name the generated functions
+
+	// f is the *testing.F of the fuzz test. It's kept here rather than in a
+	// package variable, as the package may be a realm.
+	f gno.TypedValue
+
+	types []gno.Type // types of the fuzzed arguments
+	seeds [][]any    // seed corpus registered with F.Add
+}
+
+// runFuzzTest runs the fuzz test name of the package in pkgDir as a test,
+// with its seed corpus and the corpus in testdata/fuzz/<name>. If fuzz is
+// true, it then generates new inputs until a failure is found, or the time
+// (or number of iterations) set by -fuzztime is elapsed.
+func runFuzzTest(m *gno.Machine, pkgDir, pkgName, name string, id int, fuzz bool, cfg *testCfg, io commands.IO) error {
+	ft := &fuzzTest{
+		m:       m,
+		name:    name,
+		pkgDir:  pkgDir,
+		pkgName: pkgName,
+		id:      id,
+	}
+
+	res := m.Eval(gno.Call("fuzzprepare", gno.Str(name)))
+	ft.f = res[0]
+	rep, err := parseReport(res[2].GetString())
+	if err != nil {
+		return err
+	}
+
+	fn := res[1]
+	if !rep.Failed && !rep.Skipped && fn.T != nil {
+		if err := ft.load(fn); err != nil {
+			ft.error(err.Error())
+		} else {
+			ft.run(cfg, fuzz, io)
+		}
+	}
+
+	res = m.Eval(gno.Call("fuzzfinish", ft.fArg()))
+	rep, err = parseReport(res[0].GetString())
+	if err != nil {
+		return err
+	}
+	if rep.Failed {
+		return errors.New("failed: %q", name)
+	}
+	return nil
+}
+
+// load checks the type of the fuzz target fn, generates the function used to
+// call it, and loads the seed corpus.
+func (ft *fuzzTest) load(fn gno.TypedValue) error {
+	ftyp, ok := fn.T.(*gno.FuncType)
+	if !ok {
+		return fmt.Errorf("testing: F.Fuzz expects a function, got %s", fn.T.String())
+	}
+	if len(ftyp.Params) == 0 || ftyp.Params[0].Type.String() != "*testing.T" {
+		return fmt.Errorf("testing: fuzz target must receive at least one argument, the first being *testing.T")
+	}
+	if len(ftyp.Results) != 0 {
+		return fmt.Errorf("testing: fuzz target must not return a value")
+	}
+	for _, param := range ftyp.Params[1:] {
+		if !isFuzzType(param.Type) {
+			return fmt.Errorf("testing: unsupported type for fuzzing %s", param.Type.String())
+		}
+		ft.types = append(ft.types, param.Type)
+	}
+
+	// Generate the function used to call the fuzz target with typed arguments.
+	var params, args, types []string
+	for i, t := range ft.types {
+		params = append(params, fmt.Sprintf("a%d %s", i, t.String()))
+		args = append(args, fmt.Sprintf("a%d", i))
+		types = append(types, t.String())
+	}
+	src := fmt.Sprintf(`package %s
+
+import "testing"
+
+func %s(f *testing.F, name string, verbose bool, %s) string {
+	fn := testing.FuzzFunc(f).(func(*testing.T, %s))
+	return testing.RunFuzzInput(f, name, verbose, func(t *testing.T) {
+		fn(t, %s)
+	})
+}
+`, ft.pkgName, ft.inputFunc(), strings.Join(params, ", "), strings.Join(types, ", "), strings.Join(args, ", "))
+	ft.m.RunFiles(gno.MustParseFile(fmt.Sprintf("fuzz_%s.gno", ft.name), src))
+
+	// Load the seed corpus.
+	res := ft.m.Eval(gno.Call("fuzzseeds", ft.fArg()))
+	for i, entry := range ft.listValues(res[0]) {
+		vals := ft.listValues(entry)
+		if len(vals) != len(ft.types) {
+			return fmt.Errorf("wrong number of values in seed corpus entry %d: %d, want %d", i, len(vals), len(ft.types))
+		}
+		seed := make([]any, len(vals))
+		for j, tv := range vals {
+			if tv.T == nil || tv.T.TypeID() != ft.types[j].TypeID() {
+				return fmt.Errorf("mismatched types in seed corpus entry %d: %s, want %s", i, typeString(tv.T), ft.types[j].String())
+			}
+			seed[j] = ft.goValue(tv)
+		}
+		ft.seeds = append(ft.seeds, seed)
+	}
+	return nil
+}
+
+func (ft *fuzzTest) inputFunc() string {
+	return fmt.Sprintf("fuzzinput%d", ft.id)
+}
+
+// run runs the corpus of the fuzz test, and fuzzes it if fuzz is true.
+func (ft *fuzzTest) run(cfg *testCfg, fuzz bool, io commands.IO) {
+	type entry struct {
+		name string
+		vals []any
+	}
+	var corpus []entry
+	for i, seed := range ft.seeds {
+		corpus = append(corpus, entry{fmt.Sprintf("seed#%d", i), seed})
+	}
+
+	dir := ft.corpusDir()
+	files, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		ft.error(err.Error())
+		return
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		vals, err := readFuzzCorpusFile(filepath.Join(dir, file.Name()), ft.types)
+		if err != nil {
+			ft.error(err.Error())
+			return
+		}
+		corpus = append(corpus, entry{file.Name(), vals})
+	}
+
+	// Run the corpus, gathering the baseline coverage if fuzzing.
+	filter := splitRegexp(cfg.run)
+	covered := make(map[gno.Location]struct{})
+	for _, e := range corpus {
+		if !fuzz && !shouldRun(filter, ft.name+"/"+e.name) {
+			continue
+		}
+		var cov *gno.Coverage
+		if fuzz {
+			cov = gno.NewCoverage()
+		}
+		if ft.runInput(e.name, cfg.verbose, e.vals, cov) {
+			// failing corpus entry, don't fuzz.
+			return
+		}
+		if cov != nil {
+			for _, loc := range cov.Locations() {
+				covered[loc] = struct{}{}
+			}
+		}
+	}
+	if !fuzz {
+		return
+	}
+
+	// Fuzz, starting from the corpus.
+	inputs := make([][]any, 0, len(corpus))
+	for _, e := range corpus {
+		inputs = append(inputs, e.vals)
+	}
+	if len(inputs) == 0 {
+		zero := make([]any, len(ft.types))
+		for i, t := range ft.types {
+			zero[i] = fuzzZeroValue(t)
+		}
+		inputs = append(inputs, zero)
+	}
+
+	var (
+		mu          = &fuzzMutator{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+		start       = time.Now()
+		lastReport  = start
+		execs       int
+		interesting int
+	)
+	report := func() {
+		elapsed := time.Since(start)
+		io.ErrPrintfln("fuzz: elapsed: %s, execs: %d (%.0f/sec), new interesting: %d (total: %d)",
+			elapsed.Round(time.Second), execs, float64(execs)/elapsed.Seconds(), interesting, len(inputs))
+	}
+	io.ErrPrintfln("fuzz: elapsed: 0s, gathering baseline coverage: %d/%d completed, now fuzzing", len(corpus), len(corpus))
+
+	for {
+		if cfg.fuzzTime.n > 0 && execs >= cfg.fuzzTime.n {
+			break
+		}
+		if cfg.fuzzTime.d > 0 && time.Since(start) >= cfg.fuzzTime.d {
+			break
+		}
+		if time.Since(lastReport) >= fuzzReportInterval {
+			report()
+			lastReport = time.Now()
+		}
+
+		vals := cloneFuzzValues(inputs[mu.r.Intn(len(inputs))])
+		for n := 1 + mu.r.Intn(3); n > 0; n-- {
+			mu.mutate(vals)
+		}
+
+		execs++
+		cov := gno.NewCoverage()
+		data := marshalFuzzCorpus(vals)
+		name := fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+		if ft.runCandidate(name, vals, data, cov, io) {
+			report()
+			msg, err := ft.saveCrasher(name, data)
+			if err != nil {
+				msg = fmt.Sprintf("failed to write failing input: %v", err)
+			}
+			ft.error(msg)
+			return
+		}
+
+		isNew := false
+		for _, loc := range cov.Locations() {
+			if _, ok := covered[loc]; !ok {
+				covered[loc] = struct{}{}
+				isNew = true
+			}
+		}
+		if isNew {
+			inputs = append(inputs, vals)
+			interesting++
+		}
+	}
+	report()
+}
+
+// runCandidate runs an input generated by the fuzzing engine, and reports
+// whether it failed. If the VM panics, the machine can't be used anymore: the
+// input is saved before propagating the panic.
+func (ft *fuzzTest) runCandidate(name string, vals []any, data []byte, cov *gno.Coverage, io commands.IO) bool {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, err := ft.saveCrasher(name, data)
+			if err != nil {
+				msg = fmt.Sprintf("failed to write failing input: %v", err)
+			}
+			io.ErrPrintfln("--- FAIL: %s/%s\n%s", ft.name, name, msg)
+			panic(r)
+		}
+	}()
+
+	return ft.runInput(name, false, vals, cov)
+}
+
+// runInput runs the fuzz target with vals, and reports whether it failed.
+// If cov is not nil, it records the coverage of the run.
+func (ft *fuzzTest) runInput(name string, verbose bool, vals []any, cov *gno.Coverage) (failed bool) {
+	args := []any{ft.fArg(), gno.Str(name), gno.Nx(strconv.FormatBool(verbose))}
+	for i, v := range vals {
+		args = append(args, &gno.ConstExpr{
+			TypedValue: fuzzValueToGno(ft.types[i], v),
+		})
+	}
+
+	if cov != nil {
+		prev := ft.m.Coverage
+		ft.m.Coverage = cov
+		defer func() { ft.m.Coverage = prev }()
+	}
+
+	res := ft.m.Eval(gno.Call(ft.inputFunc(), args...))
+	rep, err := parseReport(res[0].GetString())
+	if err != nil {
+		ft.error(err.Error())
+		return true
+	}
+	return rep.Failed
+}
+
+func (ft *fuzzTest) corpusDir() string {
+	return filepath.Join(ft.pkgDir, "testdata", "fuzz", ft.name)
+}
+
+// saveCrasher writes the failing input data to the corpus of the fuzz test,
+// so that it's run by the next invocations of "gno test". It returns a message
+// telling how to re-run it.
+func (ft *fuzzTest) saveCrasher(name string, data []byte) (string, error) {
+	dir := ft.corpusDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Failing input written to %s\nTo re-run:\ngno test -run=%s/%s %s",
+		filepath.Join("testdata", "fuzz", ft.name, name), ft.name, name, ft.pkgDir), nil
+}
+
+// error logs msg in the fuzz test, and marks it as failed.
+func (ft *fuzzTest) error(msg string) {
+	ft.m.Eval(gno.Call("fuzzerror", ft.fArg(), gno.Str(msg)))
+}
+
+// fArg returns the expression used to pass the *testing.F to the generated
+// functions.
+func (ft *fuzzTest) fArg() gno.Expr {
+	return &gno.ConstExpr{TypedValue: ft.f}
+}
+
+// listValues returns the values of the slice tv.
+func (ft *fuzzTest) listValues(tv gno.TypedValue) []gno.TypedValue {
+	sv, ok := tv.V.(*gno.SliceValue)
+	if !ok {
+		return nil
+	}
+	av := sv.GetBase(ft.m.Store)
+	return av.List[sv.Offset : sv.Offset+sv.Length]
+}
+
+// goValue converts tv, which is of one of the types supported for fuzzing, to
+// the corresponding Go value.
+func (ft *fuzzTest) goValue(tv gno.TypedValue) any {
+	switch tv.T {
+	case gno.StringType:
+		return tv.GetString()
+	case gno.BoolType:
+		return tv.GetBool()
+	case gno.IntType:
+		return tv.GetInt()
+	case gno.Int8Type:
+		return tv.GetInt8()
+	case gno.Int16Type:
+		return tv.GetInt16()
+	case gno.Int32Type:
+		return tv.GetInt32()
+	case gno.Int64Type:
+		return tv.GetInt64()
+	case gno.UintType:
+		return tv.GetUint()
+	case gno.Uint8Type:
+		return tv.GetUint8()
+	case gno.Uint16Type:
+		return tv.GetUint16()
+	case gno.Uint32Type:
+		return tv.GetUint32()
+	case gno.Uint64Type:
+		return tv.GetUint64()
+	case gno.Float32Type:
+		return tv.GetFloat32()
+	case gno.Float64Type:
+		return tv.GetFloat64()
+	}
+
+	// []byte
+	sv, ok := tv.V.(*gno.SliceValue)
+	if !ok {
+		return []byte(nil)
+	}
+	av := sv.GetBase(ft.m.Store)
+	if av.Data != nil {
+		return bytes.Clone(av.Data[sv.Offset : sv.Offset+sv.Length])
+	}
+	bz := make([]byte, sv.Length)
+	for i := range bz {
+		bz[i] = av.List[sv.Offset+i].GetUint8()
+	}
+	return bz
+}
+
+func parseReport(s string) (rep report, err error) {
+	if s == "" {
+		return rep, errors.New("internal gno testing error")
+	}
+	err = json.Unmarshal([]byte(s), &rep)
+	return rep, err
+}
+
+func typeString(t gno.Type) string {
+	if t == nil {
+		return "nil"
+	}
+	return t.String()
+}
+
+// ----------------------------------------
+// Values
+
+// isFuzzType reports whether t is a type supported by fuzz targets.
+func isFuzzType(t gno.Type) bool {
+	switch t := t.(type) {
+	case gno.PrimitiveType:
+		switch t {
+		case gno.StringType, gno.BoolType,
+			gno.IntType, gno.Int8Type, gno.Int16Type, gno.Int32Type, gno.Int64Type,
+			gno.UintType, gno.Uint8Type, gno.Uint16Type, gno.Uint32Type, gno.Uint64Type,
+			gno.Float32Type, gno.Float64Type:
+			return true
+		}
+	case *gno.SliceType:
+		return t.Elt == gno.Uint8Type && !t.Vrd
+	}
+	return false
+}
+
+// fuzzZeroValue returns the zero value of the Go type corresponding to t.
+func fuzzZeroValue(t gno.Type) any {
+	switch t {
+	case gno.StringType:
+		return ""
+	case gno.BoolType:
+		return false
+	case gno.IntType:
+		return int(0)
+	case gno.Int8Type:
+		return int8(0)
+	case gno.Int16Type:
+		return int16(0)
+	case gno.Int32Type:
+		return int32(0)
+	case gno.Int64Type:
+		return int64(0)
+	case gno.UintType:
+		return uint(0)
+	case gno.Uint8Type:
+		return uint8(0)
+	case gno.Uint16Type:
+		return uint16(0)
+	case gno.Uint32Type:
+		return uint32(0)
+	case gno.Uint64Type:
+		return uint64(0)
+	case gno.Float32Type:
+		return float32(0)
+	case gno.Float64Type:
+		return float64(0)
+	}
+	return []byte(nil)
+}
+
+// fuzzValueToGno converts v to a gno value of type t.
+func fuzzValueToGno(t gno.Type, v any) (tv gno.TypedValue) {
+	tv.T = t
+	switch v := v.(type) {
+	case string:
+		tv.SetString(gno.StringValue(v))
+	case bool:
+		tv.SetBool(v)
+	case int:
+		tv.SetInt(v)
+	case int8:
+		tv.SetInt8(v)
+	case int16:
+		tv.SetInt16(v)
+	case int32:
+		tv.SetInt32(v)
+	case int64:
+		tv.SetInt64(v)
+	case uint:
+		tv.SetUint(v)
+	case uint8:
+		tv.SetUint8(v)
+	case uint16:
+		tv.SetUint16(v)
+	case uint32:
+		tv.SetUint32(v)
+	case uint64:
+		tv.SetUint64(v)
+	case float32:
+		tv.SetFloat32(v)
+	case float64:
+		tv.SetFloat64(v)
+	case []byte:
+		if v != nil {
+			tv.V = &gno.SliceValue{
+				Base: &gno.ArrayValue{
+					Data: bytes.Clone(v),
+				},
+				Offset: 0,
+				Length: len(v),
+				Maxcap: len(v),
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unexpected fuzz value type %T", v))
+	}
+	return
+}
+
+func cloneFuzzValues(vals []any) []any {
+	clone := make([]any, len(vals))
+	for i, v := range vals {
+		if bz, ok := v.([]byte); ok {
+			v = bytes.Clone(bz)
+		}
+		clone[i] = v
+	}
+	return clone
+}
+
+// ----------------------------------------
+// Corpus files
+
+// marshalFuzzCorpus encodes vals in the format of the corpus files.
+func marshalFuzzCorpus(vals []any) []byte {
+	b := new(bytes.Buffer)
+	fmt.Fprintln(b, fuzzCorpusHeader)
+	for _, v := range vals {
+		switch v := v.(type) {
+		case int, int8, int16, int64, uint, uint16, uint32, uint64, bool:
+			fmt.Fprintf(b, "%T(%v)\n", v, v)
+		case float32:
+			if math.IsNaN(float64(v)) && math.Float32bits(v) != math.Float32bits(float32(math.NaN())) {
+				fmt.Fprintf(b, "math.Float32frombits(0x%x)\n", math.Float32bits(v))
+			} else {
+				fmt.Fprintf(b, "%T(%v)\n", v, v)
+			}
+		case float64:
+			if math.IsNaN(v) && math.Float64bits(v) != math.Float64bits(math.NaN()) {
+				fmt.Fprintf(b, "math.Float64frombits(0x%x)\n", math.Float64bits(v))
+			} else {
+				fmt.Fprintf(b, "%T(%v)\n", v, v)
+			}
+		case string:
+			fmt.Fprintf(b, "string(%q)\n", v)
+		case rune: // int32
+			if utf8.ValidRune(v) {
+				fmt.Fprintf(b, "rune(%q)\n", v)
+			} else {
+				fmt.Fprintf(b, "int32(%v)\n", v)
+			}
+		case byte: // uint8
+			fmt.Fprintf(b, "byte(%q)\n", v)
+		case []byte:
+			fmt.Fprintf(b, "[]byte(%q)\n", v)
+		default:
+			panic(fmt.Sprintf("unexpected fuzz value type %T", v))
+		}
+	}
+	return b.Bytes()
+}
+
+// readFuzzCorpusFile reads the corpus file at path, checking that its values
+// are of the given types.
+func readFuzzCorpusFile(path string, types []gno.Type) ([]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := unmarshalFuzzCorpus(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(vals) != len(types) {
+		return nil, fmt.Errorf("%s: wrong number of values: %d, want %d", path, len(vals), len(types))
+	}
+	for i, v := range vals {
+		if want := fuzzZeroValue(types[i]); fmt.Sprintf("%T", v) != fmt.Sprintf("%T", want) {
+			return nil, fmt.Errorf("%s: mismatched types: %T, want %s", path, v, types[i].String())
+		}
+	}
+	return vals, nil
+}
+
+// unmarshalFuzzCorpus decodes the values of a corpus file.
+func unmarshalFuzzCorpus(data []byte) ([]any, error) {
+	lines := bytes.Split(data, []byte("\n"))
+	if len(lines) < 2 || string(bytes.TrimSpace(lines[0])) != fuzzCorpusHeader {
+		return nil, fmt.Errorf("must include the version and at least one value")
+	}
+	var vals []any
+	for i, line := range lines[1:] {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		v, err := parseFuzzValue(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("malformed line %d: %w", i+2, err)
+		}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
+
+func parseFuzzValue(line string) (any, error) {
+	expr, err := parser.ParseExpr(line)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, fmt.Errorf("expected a call expression with one argument")
+	}
+	arg := call.Args[0]
+
+	if at, ok := call.Fun.(*ast.ArrayType); ok {
+		if elt, ok := at.Elt.(*ast.Ident); !ok || at.Len != nil || (elt.Name != "byte" && elt.Name != "uint8") {
+			return nil, fmt.Errorf("only []byte is supported")
+		}
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, fmt.Errorf("string literal required for type []byte")
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		pkg, ok := sel.X.(*ast.Ident)
+		lit, isLit := arg.(*ast.BasicLit)
+		if !ok || pkg.Name != "math" || !isLit || lit.Kind != token.INT {
+			return nil, fmt.Errorf("unsupported function call %s", line)
+		}
+		bits, err := strconv.ParseUint(lit.Value, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		switch sel.Sel.Name {
+		case "Float32frombits":
+			if bits > math.MaxUint32 {
+				return nil, fmt.Errorf("bits out of range for float32")
+			}
+			return math.Float32frombits(uint32(bits)), nil
+		case "Float64frombits":
+			return math.Float64frombits(bits), nil
+		}
+		return nil, fmt.Errorf("unsupported function call %s", line)
+	}
+
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("expected a type conversion")
+	}
+	typ := ident.Name
+
+	// The literal, with its sign if any.
+	var (
+		lit  string
+		kind token.Token
+	)
+	switch a := arg.(type) {
+	case *ast.BasicLit:
+		lit, kind = a.Value, a.Kind
+	case *ast.Ident:
+		// true, false, NaN, Inf
+		lit, kind = a.Name, token.IDENT
+	case *ast.UnaryExpr:
+		if a.Op != token.SUB && a.Op != token.ADD {
+			return nil, fmt.Errorf("unsupported operator %s", a.Op)
+		}
+		switch x := a.X.(type) {
+		case *ast.BasicLit:
+			lit, kind = a.Op.String()+x.Value, x.Kind
+		case *ast.Ident:
+			lit, kind = a.Op.String()+x.Name, token.IDENT
+		default:
+			return nil, fmt.Errorf("unsupported literal %s", line)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported literal %s", line)
+	}
+
+	switch typ {
+	case "string":
+		if kind != token.STRING {
+			return nil, fmt.Errorf("string literal required for type string")
+		}
+		return strconv.Unquote(lit)
+	case "bool":
+		if kind != token.IDENT {
+			return nil, fmt.Errorf("true or false required for type bool")
+		}
+		return strconv.ParseBool(lit)
+	case "float32":
+		f, err := strconv.ParseFloat(lit, 32)
+		return float32(f), err
+	case "float64":
+		return strconv.ParseFloat(lit, 64)
+	}
+
+	// Integers, possibly written as character literals.
+	if kind == token.CHAR {
+		r, _, _, err := strconv.UnquoteChar(lit[1:len(lit)-1], '\'')
+		if err != nil {
+			return nil, err
+		}
+		lit = strconv.Itoa(int(r))
+	} else if kind != token.INT {
+		return nil, fmt.Errorf("integer literal required for type %s", typ)
+	}
+	switch typ {
+	case "int":
+		n, err := strconv.ParseInt(lit, 0, 0)
+		return int(n), err
+	case "int8":
+		n, err := strconv.ParseInt(lit, 0, 8)
+		return int8(n), err
+	case "int16":
+		n, err := strconv.ParseInt(lit, 0, 16)
+		return int16(n), err
+	case "int32", "rune":
+		n, err := strconv.ParseInt(lit, 0, 32)
+		return int32(n), err
+	case "int64":
+		return strconv.ParseInt(lit, 0, 64)
+	case "uint":
+		n, err := strconv.ParseUint(lit, 0, 0)
+		return uint(n), err
+	case "uint8", "byte":
+		n, err := strconv.ParseUint(lit, 0, 8)
+		return uint8(n), err
+	case "uint16":
+		n, err := strconv.ParseUint(lit, 0, 16)
+		return uint16(n), err
+	case "uint32":
+		n, err := strconv.ParseUint(lit, 0, 32)
+		return uint32(n), err
+	case "uint64":
+		return strconv.ParseUint(lit, 0, 64)
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// ----------------------------------------
+// Mutator
+
+// fuzzMutator mutates the inputs of fuzz targets. The mutations are similar
+// to the ones of Go's fuzzing engine, though simpler.
+type fuzzMutator struct {
+	r *rand.Rand
+}
+
+var (
+	interestingInts = []int64{
+		-128, -1, 0, 1, 16, 32, 64, 100, 127,
+		-32768, -129, 128, 255, 256, 512, 1000, 1024, 4096, 32767,
+		-2147483648, -100663046, -32769, 32768, 65535, 65536, 100663045, 2147483647,
+	}
+	interestingFloats = []float64{
+		0, math.Copysign(0, -1), 1, -1, 0.5,
+		math.Inf(1), math.Inf(-1), math.NaN(),
+		math.MaxFloat64, math.SmallestNonzeroFloat64,
+		math.MaxFloat32, math.SmallestNonzeroFloat32,
+	}
+	// bytes that commonly delimit tokens in parsed inputs.
+	interestingBytes = []byte("\x00\xff\t\n \"'()*+,-./0:;<=>[\\]_`{|}")
+)
+
+// mutate mutates one of vals, in place.
+func (mu *fuzzMutator) mutate(vals []any) {
+	i := mu.r.Intn(len(vals))
+	switch v := vals[i].(type) {
+	case string:
+		vals[i] = string(mu.mutateBytes([]byte(v)))
+	case []byte:
+		vals[i] = mu.mutateBytes(v)
+	case bool:
+		vals[i] = !v
+	case int:
+		vals[i] = int(mu.mutateInt(uint64(v), strconv.IntSize))
+	case int8:
+		vals[i] = int8(mu.mutateInt(uint64(v), 8))
+	case int16:
+		vals[i] = int16(mu.mutateInt(uint64(v), 16))
+	case int32:
+		vals[i] = int32(mu.mutateInt(uint64(v), 32))
+	case int64:
+		vals[i] = int64(mu.mutateInt(uint64(v), 64))
+	case uint:
+		vals[i] = uint(mu.mutateInt(uint64(v), strconv.IntSize))
+	case uint8:
+		vals[i] = uint8(mu.mutateInt(uint64(v), 8))
+	case uint16:
+		vals[i] = uint16(mu.mutateInt(uint64(v), 16))
+	case uint32:
+		vals[i] = uint32(mu.mutateInt(uint64(v), 32))
+	case uint64:
+		vals[i] = mu.mutateInt(v, 64)
+	case float32:
+		vals[i] = float32(mu.mutateFloat(float64(v)))
+	case float64:
+		vals[i] = mu.mutateFloat(v)
+	}
+}
+
+// mutateInt mutates the integer v, of the given size in bits. The result is
+// truncated by the caller.
+func (mu *fuzzMutator) mutateInt(v uint64, bits int) uint64 {
+	switch mu.r.Intn(5) {
+	case 0:
+		return v + uint64(1+mu.r.Intn(35))
+	case 1:
+		return v - uint64(1+mu.r.Intn(35))
+	case 2:
+		return v ^ 1<<uint(mu.r.Intn(bits))
+	case 3:
+		return uint64(interestingInts[mu.r.Intn(len(interestingInts))])
+	default:
+		return mu.r.Uint64()
+	}
+}
+
+func (mu *fuzzMutator) mutateFloat(v float64) float64 {
+	switch mu.r.Intn(5) {
+	case 0:
+		return v + float64(1+mu.r.Intn(35))
+	case 1:
+		return v - float64(1+mu.r.Intn(35))
+	case 2:
+		if mu.r.Intn(2) == 0 {
+			return v * float64(1+mu.r.Intn(35))
+		}
+		return v / float64(1+mu.r.Intn(35))
+	case 3:
+		return interestingFloats[mu.r.Intn(len(interestingFloats))]
+	default:
+		return math.Float64frombits(mu.mutateInt(math.Float64bits(v), 64))
+	}
+}
+
+// mutateBytes returns a mutation of b, which may be modified in place.
+func (mu *fuzzMutator) mutateBytes(b []byte) []byte {
+	for {
+		switch mu.r.Intn(10) {
+		case 0: // remove a range
+			if len(b) == 0 {
+				continue
+			}
+			pos, n := mu.chooseRange(len(b))
+			return append(b[:pos], b[pos+n:]...)
+		case 1: // insert random bytes
+			if len(b) >= fuzzMaxLen {
+				continue
+			}
+			n := 1 + mu.r.Intn(min(8, fuzzMaxLen-len(b)))
+			pos := mu.r.Intn(len(b) + 1)
+			ins := make([]byte, n)
+			for i := range ins {
+				ins[i] = mu.randByte()
+			}
+			return append(b[:pos], append(ins, b[pos:]...)...)
+		case 2: // duplicate a range
+			if len(b) == 0 || len(b) >= fuzzMaxLen {
+				continue
+			}
+			src, n := mu.chooseRange(len(b))
+			n = min(n, fuzzMaxLen-len(b))
+			dup := bytes.Clone(b[src : src+n])
+			pos := mu.r.Intn(len(b) + 1)
+			return append(b[:pos], append(dup, b[pos:]...)...)
+		case 3: // overwrite a range with another one
+			if len(b) < 2 {
+				continue
+			}
+			src, n := mu.chooseRange(len(b))
+			dst := mu.r.Intn(len(b) - n + 1)
+			copy(b[dst:], b[src:src+n])
+			return b
+		case 4: // flip a bit
+			if len(b) == 0 {
+				continue
+			}
+			b[mu.r.Intn(len(b))] ^= 1 << uint(mu.r.Intn(8))
+			return b
+		case 5: // set a random byte
+			if len(b) == 0 {
+				continue
+			}
+			b[mu.r.Intn(len(b))] = mu.randByte()
+			return b
+		case 6: // swap two bytes
+			if len(b) < 2 {
+				continue
+			}
+			i, j := mu.r.Intn(len(b)), mu.r.Intn(len(b))
+			b[i], b[j] = b[j], b[i]
+			return b
+		case 7: // add to or subtract from a byte
+			if len(b) == 0 {
+				continue
+			}
+			i := mu.r.Intn(len(b))
+			if mu.r.Intn(2) == 0 {
+				b[i] += byte(1 + mu.r.Intn(35))
+			} else {
+				b[i] -= byte(1 + mu.r.Intn(35))
+			}
+			return b
+		case 8: // set an interesting byte
+			if len(b) == 0 {
+				continue
+			}
+			b[mu.r.Intn(len(b))] = interestingBytes[mu.r.Intn(len(interestingBytes))]
+			return b
+		default: // insert an interesting byte
+			if len(b) >= fuzzMaxLen {
+				continue
+			}
+			pos := mu.r.Intn(len(b) + 1)
+			c := interestingBytes[mu.r.Intn(len(interestingBytes))]
+			return append(b[:pos], append([]byte{c}, b[pos:]...)...)
+		}
+	}
+}
+
+// randByte returns a random byte, which is printable half of the time, as
+// the inputs of gno programs are often text.
+func (mu *fuzzMutator) randByte() byte {
+	if mu.r.Intn(2) == 0 {
+		return byte(' ' + mu.r.Intn('~'-' '+1))
+	}
+	return byte(mu.r.Intn(256))
+}
+
+// chooseRange returns the start and length of a random range of a slice of
+// length n > 0.
+func (mu *fuzzMutator) chooseRange(n int) (pos, length int) {
+	length = 1 + mu.r.Intn(min(n, 16))
+	pos = mu.r.Intn(n - length + 1)
+	return pos, length
+}
+
+// matchFuzzTests returns the names of the fuzz tests matching the -fuzz
+// regexp.
+func matchFuzzTests(pattern string, tests []testFunc) ([]string, error) {
+	var names []string
+	for _, test := range tests {
+		ok, err := matchString(pattern, test.Name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -fuzz: %w", err)
+		}
+		if ok {
+			names = append(names, test.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
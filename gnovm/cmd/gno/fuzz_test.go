@@ -0,0 +1,134 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzCorpusRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	vals := []any{
+		"hello\n\"world\"",
+		[]byte("\x00\xffbytes"),
+		true,
+		int(-42),
+		int8(math.MinInt8),
+		int16(1000),
+		int32('é'),
+		int32(-1),
+		int64(math.MaxInt64),
+		uint(42),
+		uint8('\n'),
+		uint16(math.MaxUint16),
+		uint32(7),
+		uint64(math.MaxUint64),
+		float32(1.5),
+		float64(-2.25e10),
+		math.Inf(-1),
+	}
+
+	data := marshalFuzzCorpus(vals)
+	got, err := unmarshalFuzzCorpus(data)
+	require.NoError(t, err)
+	assert.Equal(t, vals, got)
+
+	// NaN is never equal to itself.
+	got, err = unmarshalFuzzCorpus(marshalFuzzCorpus([]any{math.NaN()}))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.True(t, math.IsNaN(got[0].(float64)))
+}
+
+func TestUnmarshalFuzzCorpus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     string
+		expected []any
+		err      string
+	}{
+		{
+			name:     "go format",
+			data:     "go test fuzz v1\nstring(\"abc\")\nbyte('a')\nrune('b')\nint(0x10)\n\n",
+			expected: []any{"abc", uint8('a'), int32('b'), int(16)},
+		},
+		{
+			name:     "float bits",
+			data:     "go test fuzz v1\nmath.Float64frombits(0x3ff0000000000000)\nfloat32(+Inf)",
+			expected: []any{float64(1), float32(math.Inf(1))},
+		},
+		{
+			name: "no header",
+			data: "string(\"abc\")\n",
+			err:  "must include the version",
+		},
+		{
+			name: "no values",
+			data: "go test fuzz v1",
+			err:  "must include the version",
+		},
+		{
+			name: "overflow",
+			data: "go test fuzz v1\nint8(128)",
+			err:  "malformed line 2",
+		},
+		{
+			name: "unsupported type",
+			data: "go test fuzz v1\ncomplex128(1)",
+			err:  "unsupported type complex128",
+		},
+		{
+			name: "unsupported slice",
+			data: "go test fuzz v1\n[]int(\"a\")",
+			err:  "only []byte is supported",
+		},
+		{
+			name: "wrong literal",
+			data: "go test fuzz v1\nstring(1)",
+			err:  "string literal required",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := unmarshalFuzzCorpus([]byte(tc.data))
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestFuzzMutator(t *testing.T) {
+	t.Parallel()
+
+	mu := &fuzzMutator{r: rand.New(rand.NewSource(1))}
+	vals := []any{"seed", []byte(nil), int8(0), uint(0), float32(0), true}
+	for i := 0; i < 10000; i++ {
+		mu.mutate(vals)
+
+		// the types are preserved, and the values are kept short.
+		require.IsType(t, "", vals[0])
+		require.IsType(t, []byte(nil), vals[1])
+		require.IsType(t, int8(0), vals[2])
+		require.IsType(t, uint(0), vals[3])
+		require.IsType(t, float32(0), vals[4])
+		require.IsType(t, false, vals[5])
+		require.LessOrEqual(t, len(vals[0].(string)), fuzzMaxLen)
+		require.LessOrEqual(t, len(vals[1].([]byte)), fuzzMaxLen)
+	}
+	assert.NotEqual(t, "seed", vals[0])
+	assert.NotEmpty(t, vals[1])
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/gnovm/pkg/lsp"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type lspCfg struct {
+	verbose bool
+	rootDir string
+}
+
+func newLspCmd(io commands.IO) *commands.Command {
+	cfg := &lspCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "lsp",
+			ShortUsage: "lsp [flags]",
+			ShortHelp:  "runs the language server for gno files",
+			LongHelp: `Runs gnopls, a language server for gno files, which communicates with the editor
+through stdin and stdout using the Language Server Protocol.
+
+The language server provides diagnostics from the gno parser and preprocessor,
+in the same way as 'gno lint', hover documentation, go-to-definition across the
+standard libraries, the examples and the gno.mod requirements, completion for
+package members and formatting, in the same way as 'gno fmt'.
+
+Diagnostics are updated when a file is opened or saved.`,
+		},
+		cfg,
+		func(ctx context.Context, args []string) error {
+			return execLsp(ctx, cfg, args, io)
+		},
+	)
+}
+
+func (c *lspCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolVar(
+		&c.verbose,
+		"v",
+		false,
+		"log the handled requests to stderr",
+	)
+
+	fs.StringVar(
+		&c.rootDir,
+		"root-dir",
+		"",
+		"clone location of github.com/gnolang/gno (gno tries to guess it)",
+	)
+}
+
+func execLsp(ctx context.Context, cfg *lspCfg, args []string, io commands.IO) error {
+	if len(args) > 0 {
+		return flag.ErrHelp
+	}
+
+	if cfg.rootDir == "" {
+		cfg.rootDir = gnoenv.RootDir()
+	}
+
+	level := slog.LevelWarn
+	if cfg.verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(io.Err(), &slog.HandlerOptions{Level: level}))
+
+	srv := lsp.NewServer(lsp.Config{
+		RootDir: cfg.rootDir,
+		Logger:  logger,
+	})
+	return srv.Serve(ctx, io.In(), io.Out())
+}
@@ -35,6 +35,7 @@ func newGnocliCmd(io commands.IO) *commands.Command {
 		newEnvCmd(io),
 		newBugCmd(io),
 		newFmtCmd(io),
+		newLspCmd(io),
 		// graph
 		// vendor -- download deps from the chain in vendor/
 		// list -- list packages
@@ -125,6 +125,7 @@ For example:
 type modDownloadCfg struct {
 	remote  string
 	verbose bool
+	latest  bool
 }
 
 func (c *modDownloadCfg) RegisterFlags(fs *flag.FlagSet) {
@@ -141,6 +142,13 @@ func (c *modDownloadCfg) RegisterFlags(fs *flag.FlagSet) {
 		false,
 		"verbose output when running",
 	)
+
+	fs.BoolVar(
+		&c.latest,
+		"latest",
+		false,
+		"replace requirements with their latest non-deprecated version",
+	)
 }
 
 func execModDownload(cfg *modDownloadCfg, args []string, io commands.IO) error {
@@ -176,6 +184,16 @@ func execModDownload(cfg *modDownloadCfg, args []string, io commands.IO) error {
 		return fmt.Errorf("validate: %w", err)
 	}
 
+	// resolve latest versions, and record them in gno.mod
+	if cfg.latest {
+		if err := gnoMod.ResolveLatest(cfg.remote, cfg.verbose); err != nil {
+			return fmt.Errorf("resolve: %w", err)
+		}
+		if err := gnoMod.Write(modPath); err != nil {
+			return fmt.Errorf("write gno.mod file: %w", err)
+		}
+	}
+
 	// fetch dependencies
 	if err := gnoMod.FetchDeps(gnomod.GetGnoModPath(), cfg.remote, cfg.verbose); err != nil {
 		return fmt.Errorf("fetch: %w", err)
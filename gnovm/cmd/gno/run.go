@@ -22,6 +22,7 @@ type runCfg struct {
 	expr      string
 	debug     bool
 	debugAddr string
+	debugDAP  string
 }
 
 func newRunCmd(io commands.IO) *commands.Command {
@@ -75,6 +76,13 @@ func (c *runCfg) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"enable interactive debugger using tcp address in the form [host]:port",
 	)
+
+	fs.StringVar(
+		&c.debugDAP,
+		"debug-dap",
+		"",
+		"enable Debug Adapter Protocol server using tcp address in the form [host]:port",
+	)
 }
 
 func execRun(cfg *runCfg, args []string, io commands.IO) error {
@@ -129,6 +137,17 @@ func execRun(cfg *runCfg, args []string, io commands.IO) error {
 		}
 	}
 
+	// If the DAP address is set, the debugger waits for a DAP client, such as an editor, to connect to it.
+	if cfg.debugDAP != "" {
+		sess, err := gno.ListenDAP(cfg.debugDAP)
+		if err != nil {
+			return err
+		}
+		defer sess.Close()
+		sess.SetRootDir(cfg.rootDir)
+		m.Debugger.EnableDAP(sess)
+	}
+
 	// run files
 	m.RunFiles(files...)
 	runExpr(m, cfg.expr)
@@ -7,10 +7,12 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"runtime/debug"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -25,6 +27,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/random"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/testutils"
 )
 
@@ -36,10 +39,20 @@ type testCfg struct {
 	updateGoldenTests   bool
 	printRuntimeMetrics bool
 	withNativeFallback  bool
+	cover               bool
+	coverProfile        string
+	coverMode           string
+	bench               string
+	benchTime           benchTimeFlag
+	fuzz                string
+	fuzzTime            benchTimeFlag
+	debugDAP            string
 }
 
 func newTestCmd(io commands.IO) *commands.Command {
-	cfg := &testCfg{}
+	cfg := &testCfg{
+		benchTime: benchTimeFlag{d: time.Second},
+	}
 
 	return commands.NewCommand(
 		commands.Metadata{
@@ -53,9 +66,9 @@ file pattern "*_test.gno" or "*_filetest.gno".
 
 The <package> can be directory or file path (relative or absolute).
 
-- "*_test.gno" files work like "*_test.go" files, but they contain only test
-functions. Benchmark and fuzz functions aren't supported yet. Similarly, only
-tests that belong to the same package are supported for now (no "xxx_test").
+- "*_test.gno" files work like "*_test.go" files, but they contain only test,
+benchmark and fuzz functions. Similarly, only tests that belong to the same
+package are supported for now (no "xxx_test").
 
 The package path used to execute the "*_test.gno" file is fetched from the
 module name found in 'gno.mod', or else it is randomly generated like
@@ -91,6 +104,31 @@ List of available instructions that can be used in "*_filetest.gno" files:
 
 (*) The 'update-golden-tests' flag can be set to fill out the content of the
 instruction with the actual content of the test instead of failing.
+
+The 'cover' flag enables coverage analysis: the statements of the package
+executed by its tests and filetests are recorded, and the percentage of
+statements covered is printed for each package. The 'coverprofile' flag writes
+the coverage data of all the packages to a file, in the format of Go cover
+profiles. Only the files of the package under test are analyzed, and the
+init functions of packages imported by filetests are not recorded.
+
+The 'bench' flag runs the benchmarks matching the given regular expression,
+after the tests. For each benchmark, the wall time, the VM cpu cycles and the
+gas consumed are reported per operation, as well as the bytes allocated if the
+benchmark calls b.ReportAllocs. Unlike the wall time, the cycles, gas and
+allocations only depend on the executed code: running the benchmarks with a
+fixed number of iterations (for instance, '-benchtime 100x') produces results
+that can be compared across runs to detect regressions.
+
+Fuzz tests are run as tests, with their seed corpus and the inputs stored in
+the "testdata/fuzz/<FuzzTestName>" directory of the package. The 'fuzz' flag
+fuzzes the fuzz test matching the given regular expression, which must match
+exactly one fuzz test of a single package: new inputs are generated by
+mutating the corpus, and the ones executing new statements of the package are
+added to it. When an input makes the fuzz test fail, it's saved in the
+"testdata/fuzz" directory, so that it is run by the next invocations of
+'gno test'. Fuzzing runs until a failure is found, or for the time (or number
+of inputs, as 'Nx') set by the 'fuzztime' flag.
 `,
 		},
 		cfg,
@@ -149,6 +187,60 @@ func (c *testCfg) RegisterFlags(fs *flag.FlagSet) {
 		false,
 		"print runtime metrics (gas, memory, cpu cycles)",
 	)
+
+	fs.BoolVar(
+		&c.cover,
+		"cover",
+		false,
+		"enable coverage analysis",
+	)
+
+	fs.StringVar(
+		&c.coverProfile,
+		"coverprofile",
+		"",
+		"write a coverage profile to the file (sets -cover)",
+	)
+
+	fs.StringVar(
+		&c.coverMode,
+		"covermode",
+		"",
+		"set the mode for coverage analysis: set, count (sets -cover; default: set)",
+	)
+
+	fs.StringVar(
+		&c.bench,
+		"bench",
+		"",
+		"run only the benchmarks matching the regular expression",
+	)
+
+	fs.Var(
+		&c.benchTime,
+		"benchtime",
+		"run each benchmark for the duration, or N times if specified as Nx",
+	)
+
+	fs.StringVar(
+		&c.fuzz,
+		"fuzz",
+		"",
+		"run the fuzz test matching the regular expression, generating new inputs",
+	)
+
+	fs.Var(
+		&c.fuzzTime,
+		"fuzztime",
+		"time spent fuzzing, or number of inputs if specified as Nx (default: run until a failure is found)",
+	)
+
+	fs.StringVar(
+		&c.debugDAP,
+		"debug-dap",
+		"",
+		"enable Debug Adapter Protocol server for unit tests using tcp address in the form [host]:port",
+	)
 }
 
 func execTest(cfg *testCfg, args []string, io commands.IO) error {
@@ -161,6 +253,17 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 		cfg.rootDir = gnoenv.RootDir()
 	}
 
+	if cfg.coverProfile != "" || cfg.coverMode != "" {
+		cfg.cover = true
+	}
+	switch cfg.coverMode {
+	case "":
+		cfg.coverMode = coverModeSet
+	case coverModeSet, coverModeCount:
+	default:
+		return fmt.Errorf("invalid -covermode %q, should be %s or %s", cfg.coverMode, coverModeSet, coverModeCount)
+	}
+
 	paths, err := targetsFromPatterns(args)
 	if err != nil {
 		return fmt.Errorf("list targets from patterns: %w", err)
@@ -181,9 +284,23 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 	if err != nil {
 		return fmt.Errorf("list sub packages: %w", err)
 	}
+	if cfg.fuzz != "" && len(subPkgs) > 1 {
+		return fmt.Errorf("cannot use -fuzz flag with multiple packages")
+	}
+
+	// If the DAP address is set, the tests wait for a DAP client, such as an editor, to connect to it.
+	var dap *gno.DAPSession
+	if cfg.debugDAP != "" {
+		if dap, err = gno.ListenDAP(cfg.debugDAP); err != nil {
+			return err
+		}
+		defer dap.Close()
+		dap.SetRootDir(cfg.rootDir)
+	}
 
 	buildErrCount := 0
 	testErrCount := 0
+	var coverages []*pkgCoverage
 	for _, pkg := range subPkgs {
 		if len(pkg.TestGnoFiles) == 0 && len(pkg.FiletestGnoFiles) == 0 {
 			io.ErrPrintfln("?       %s \t[no test files]", pkg.Dir)
@@ -194,9 +311,12 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 		sort.Strings(pkg.FiletestGnoFiles)
 
 		startedAt := time.Now()
-		err = gnoTestPkg(pkg.Dir, pkg.TestGnoFiles, pkg.FiletestGnoFiles, cfg, io)
+		pc, err := gnoTestPkg(pkg.Dir, pkg.TestGnoFiles, pkg.FiletestGnoFiles, cfg, dap, io)
 		duration := time.Since(startedAt)
 		dstr := fmtDuration(duration)
+		if pc != nil {
+			coverages = append(coverages, pc)
+		}
 
 		if err != nil {
 			io.ErrPrintfln("%s: test pkg: %v", pkg.Dir, err)
@@ -204,10 +324,19 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 			io.ErrPrintfln("FAIL    %s \t%s", pkg.Dir, dstr)
 			io.ErrPrintfln("FAIL")
 			testErrCount++
+		} else if pc != nil {
+			io.ErrPrintfln("ok      %s \t%s\t%s", pkg.Dir, dstr, pc)
 		} else {
 			io.ErrPrintfln("ok      %s \t%s", pkg.Dir, dstr)
 		}
 	}
+
+	if cfg.coverProfile != "" {
+		if err := writeCoverProfileFile(cfg.coverProfile, cfg.coverMode, coverages); err != nil {
+			return fmt.Errorf("write cover profile: %w", err)
+		}
+	}
+
 	if testErrCount > 0 || buildErrCount > 0 {
 		io.ErrPrintfln("FAIL")
 		return fmt.Errorf("FAIL: %d build errors, %d test errors", buildErrCount, testErrCount)
@@ -221,8 +350,9 @@ func gnoTestPkg(
 	unittestFiles,
 	filetestFiles []string,
 	cfg *testCfg,
+	dap *gno.DAPSession,
 	io commands.IO,
-) error {
+) (*pkgCoverage, error) {
 	var (
 		verbose             = cfg.verbose
 		rootDir             = cfg.rootDir
@@ -246,21 +376,43 @@ func gnoTestPkg(
 		stdout = commands.WriteNopCloser(mockOut)
 	}
 
-	// testing with *_test.gno
-	if len(unittestFiles) > 0 {
-		// Determine gnoPkgPath by reading gno.mod
-		var gnoPkgPath string
-		modfile, err := gnomod.ParseAt(pkgPath)
-		if err == nil {
-			gnoPkgPath = modfile.Module.Mod.Path
-		} else {
-			gnoPkgPath = pkgPathFromRootDir(pkgPath, rootDir)
-			if gnoPkgPath == "" {
-				// unable to read pkgPath from gno.mod, generate a random realm path
+	// Determine gnoPkgPath by reading gno.mod
+	var gnoPkgPath string
+	modfile, err := gnomod.ParseAt(pkgPath)
+	if err == nil {
+		gnoPkgPath = modfile.Module.Mod.Path
+	} else {
+		gnoPkgPath = pkgPathFromRootDir(pkgPath, rootDir)
+		if gnoPkgPath == "" {
+			// unable to read pkgPath from gno.mod, generate a random realm path
+			if len(unittestFiles) > 0 {
 				io.ErrPrintfln("--- WARNING: unable to read package path from gno.mod or gno root directory; try creating a gno.mod file")
-				gnoPkgPath = gno.RealmPathPrefix + random.RandStr(8)
 			}
+			gnoPkgPath = gno.RealmPathPrefix + random.RandStr(8)
+		}
+	}
+
+	var (
+		pc       *pkgCoverage
+		coverage *gno.Coverage
+	)
+	if cfg.cover {
+		pc, err = newPkgCoverage(gno.ReadMemPackage(pkgPath, gnoPkgPath))
+		if err != nil {
+			return nil, err
 		}
+		coverage = gno.NewCoverage()
+		defer pc.update(coverage)
+	}
+
+	if dap != nil {
+		// Test packages are run from memory, locate their sources for the DAP client.
+		dap.SetPackageDir(gnoPkgPath, pkgPath)
+		dap.SetPackageDir(gnoPkgPath+"_test", pkgPath)
+	}
+
+	// testing with *_test.gno
+	if len(unittestFiles) > 0 {
 		memPkg := gno.ReadMemPackage(pkgPath, gnoPkgPath)
 
 		// tfiles, ifiles := gno.ParseMemPackageTests(memPkg)
@@ -271,7 +423,7 @@ func gnoTestPkg(
 		})
 
 		if hasError {
-			return commands.ExitCodeError(1)
+			return pc, commands.ExitCodeError(1)
 		}
 		testPkgName := getPkgNameFromFileset(ifiles)
 
@@ -287,6 +439,8 @@ func gnoTestPkg(
 			}
 
 			m := tests.TestMachine(testStore, stdout, gnoPkgPath)
+			m.Coverage = coverage
+			m.Debugger.EnableDAP(dap)
 			if printRuntimeMetrics {
 				// from tm2/pkg/sdk/vm/keeper.go
 				// XXX: make maxAllocTx configurable.
@@ -294,8 +448,11 @@ func gnoTestPkg(
 
 				m.Alloc = gno.NewAllocator(maxAllocTx)
 			}
+			if cfg.bench != "" {
+				setupBenchMachine(m)
+			}
 			m.RunMemPackage(memPkg, true)
-			err := runTestFiles(m, tfiles, memPkg.Name, verbose, printRuntimeMetrics, runFlag, io)
+			err := runTestFiles(m, tfiles, pkgPath, memPkg.Name, cfg, io)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -313,6 +470,11 @@ func gnoTestPkg(
 			}
 
 			m := tests.TestMachine(testStore, stdout, testPkgName)
+			m.Coverage = coverage
+			m.Debugger.EnableDAP(dap)
+			if cfg.bench != "" {
+				setupBenchMachine(m)
+			}
 
 			memFiles := make([]*std.MemFile, 0, len(ifiles.FileNames())+1)
 			for _, f := range memPkg.Files {
@@ -329,7 +491,7 @@ func gnoTestPkg(
 			memPkg.Path = memPkg.Path + "_test"
 			m.RunMemPackage(memPkg, true)
 
-			err := runTestFiles(m, ifiles, testPkgName, verbose, printRuntimeMetrics, runFlag, io)
+			err := runTestFiles(m, ifiles, pkgPath, testPkgName, cfg, io)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -357,7 +519,10 @@ func gnoTestPkg(
 			}
 
 			testFilePath := filepath.Join(pkgPath, testFileName)
-			err := tests.RunFileTest(rootDir, testFilePath, tests.WithSyncWanted(cfg.updateGoldenTests))
+			err := tests.RunFileTest(rootDir, testFilePath,
+				tests.WithSyncWanted(cfg.updateGoldenTests),
+				tests.WithCoverage(coverage),
+			)
 			duration := time.Since(startedAt)
 			dstr := fmtDuration(duration)
 
@@ -381,7 +546,7 @@ func gnoTestPkg(
 		}
 	}
 
-	return errs
+	return pc, errs
 }
 
 // attempts to determine the full gno pkg path by analyzing the directory.
@@ -416,10 +581,9 @@ func pkgPathFromRootDir(pkgPath, rootDir string) string {
 func runTestFiles(
 	m *gno.Machine,
 	files *gno.FileSet,
+	pkgDir string,
 	pkgName string,
-	verbose bool,
-	printRuntimeMetrics bool,
-	runFlag string,
+	cfg *testCfg,
 	io commands.IO,
 ) (errs error) {
 	defer func() {
@@ -430,8 +594,11 @@ func runTestFiles(
 
 	testFuncs := &testFuncs{
 		PackageName: pkgName,
-		Verbose:     verbose,
-		RunFlag:     runFlag,
+		Verbose:     cfg.verbose,
+		RunFlag:     cfg.run,
+		BenchFlag:   cfg.bench,
+		BenchTime:   cfg.benchTime.d.Nanoseconds(),
+		BenchN:      cfg.benchTime.n,
 	}
 	loadTestFuncs(pkgName, testFuncs, files)
 
@@ -474,7 +641,7 @@ func runTestFiles(
 			errs = multierr.Append(errs, err)
 		}
 
-		if printRuntimeMetrics {
+		if cfg.printRuntimeMetrics {
 			imports := m.Store.NumMemPackages() - numPackagesBefore - 1
 			// XXX: store changes
 			// XXX: max mem consumption
@@ -494,9 +661,66 @@ func runTestFiles(
 		}
 	}
 
+	var fuzzName string
+	if cfg.fuzz != "" {
+		names, err := matchFuzzTests(cfg.fuzz, testFuncs.FuzzTests)
+		if err != nil {
+			return multierr.Append(errs, err)
+		}
+		if len(names) > 1 {
+			return multierr.Append(errs, fmt.Errorf("will not fuzz, -fuzz matches more than one fuzz test: %v", names))
+		}
+		if len(names) == 1 {
+			fuzzName = names[0]
+		}
+	}
+	runFilter := splitRegexp(cfg.run)
+	for i, fuzzTest := range testFuncs.FuzzTests {
+		fuzz := fuzzTest.Name == fuzzName
+		if !fuzz && !shouldRun(runFilter, fuzzTest.Name) {
+			continue
+		}
+		err := runFuzzTest(m, pkgDir, pkgName, fuzzTest.Name, i, fuzz, cfg, io)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
+	if cfg.bench == "" {
+		return errs
+	}
+	for _, bench := range testFuncs.Benchmarks {
+		benchFuncStr := fmt.Sprintf("%q", bench.Name)
+
+		eval := m.Eval(gno.Call("runbench", benchFuncStr))
+
+		ret := eval[0].GetString()
+		var rep report
+		if err := json.Unmarshal([]byte(ret), &rep); err != nil {
+			errs = multierr.Append(errs, err)
+			io.ErrPrintfln("--- FAIL: %s [internal gno testing error]", bench.Name)
+			continue
+		}
+
+		if rep.Failed {
+			err := errors.New("failed: %q", bench.Name)
+			errs = multierr.Append(errs, err)
+		}
+	}
+
 	return errs
 }
 
+// setupBenchMachine enables the metering of allocations and gas on m, so that
+// they can be reported by benchmarks.
+func setupBenchMachine(m *gno.Machine) {
+	if m.Alloc == nil {
+		// allocations are not freed, so don't put a limit on them.
+		m.Alloc = gno.NewAllocator(math.MaxInt64)
+	}
+	m.GasMeter = store.NewInfiniteGasMeter()
+}
+
 // mirror of stdlibs/testing.Report
 type report struct {
 	Failed  bool
@@ -525,13 +749,63 @@ func runtest(name string) (report string) {
 	panic("no such test: " + name)
 	return ""
 }
+
+var benchmarks = []testing.InternalBenchmark{
+{{range .Benchmarks}}
+    {"{{.Name}}", {{.Name}}},
+{{end}}
+}
+
+func runbench(name string) (report string) {
+	for _, bench := range benchmarks {
+		if bench.Name == name {
+			return testing.RunBenchmark({{printf "%q" .BenchFlag}}, {{.BenchTime}}, {{.BenchN}}, {{.Verbose}}, bench)
+		}
+	}
+	panic("no such benchmark: " + name)
+	return ""
+}
+
+var fuzzTests = []testing.InternalFuzzTarget{
+{{range .FuzzTests}}
+    {"{{.Name}}", {{.Name}}},
+{{end}}
+}
+
+func fuzzprepare(name string) (f *testing.F, fn interface{}, report string) {
+	for _, test := range fuzzTests {
+		if test.Name == name {
+			f, report = testing.PrepareFuzz({{.Verbose}}, test)
+			return f, testing.FuzzFunc(f), report
+		}
+	}
+	panic("no such fuzz test: " + name)
+	return nil, nil, ""
+}
+
+func fuzzseeds(f *testing.F) [][]interface{} {
+	return testing.FuzzSeeds(f)
+}
+
+func fuzzerror(f *testing.F, msg string) {
+	f.Error(msg)
+}
+
+func fuzzfinish(f *testing.F) (report string) {
+	return testing.FinishFuzz(f)
+}
 `))
 
 type testFuncs struct {
 	Tests       []testFunc
+	Benchmarks  []testFunc
+	FuzzTests   []testFunc
 	PackageName string
 	Verbose     bool
 	RunFlag     string
+	BenchFlag   string
+	BenchTime   int64
+	BenchN      int
 }
 
 type testFunc struct {
@@ -559,12 +833,17 @@ func loadTestFuncs(pkgName string, t *testFuncs, tfiles *gno.FileSet) *testFuncs
 		for _, d := range tf.Decls {
 			if fd, ok := d.(*gno.FuncDecl); ok {
 				fname := string(fd.Name)
-				if strings.HasPrefix(fname, "Test") {
-					tf := testFunc{
-						Package: pkgName,
-						Name:    fname,
-					}
+				tf := testFunc{
+					Package: pkgName,
+					Name:    fname,
+				}
+				switch {
+				case strings.HasPrefix(fname, "Test"):
 					t.Tests = append(t.Tests, tf)
+				case strings.HasPrefix(fname, "Benchmark"):
+					t.Benchmarks = append(t.Benchmarks, tf)
+				case strings.HasPrefix(fname, "Fuzz"):
+					t.FuzzTests = append(t.FuzzTests, tf)
 				}
 			}
 		}
@@ -617,3 +896,34 @@ func shouldRun(filter filterMatch, path string) bool {
 	ok, _ := filter.matches(elem, matchString)
 	return ok
 }
+
+// benchTimeFlag is the value of the -benchtime flag: either a duration, or a
+// number of iterations in the form "Nx".
+type benchTimeFlag struct {
+	d time.Duration
+	n int
+}
+
+func (f *benchTimeFlag) String() string {
+	if f.n > 0 {
+		return fmt.Sprintf("%dx", f.n)
+	}
+	return f.d.String()
+}
+
+func (f *benchTimeFlag) Set(s string) error {
+	if strings.HasSuffix(s, "x") {
+		n, err := strconv.ParseInt(s[:len(s)-1], 10, 0)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid count %q", s)
+		}
+		*f = benchTimeFlag{n: int(n)}
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	*f = benchTimeFlag{d: d}
+	return nil
+}
@@ -26,11 +26,13 @@ const (
 	_allocSliceValue       = 40
 	_allocFuncValue        = 136
 	_allocMapValue         = 144
+	_allocChanValue        = 96
 	_allocBoundMethodValue = 176
 	_allocBlock            = 464
 	_allocNativeValue      = 48
 	_allocTypeValue        = 16
 	_allocTypedValue       = 40
+	_allocGoroutine        = 512 // XXX
 	_allocBigint           = 200 // XXX
 	_allocBigdec           = 200 // XXX
 	_allocType             = 200 // XXX
@@ -53,6 +55,8 @@ const (
 	allocFunc        = _allocBase + _allocPointer + _allocFuncValue
 	allocMap         = _allocBase + _allocPointer + _allocMapValue
 	allocMapItem     = _allocTypedValue * 3 // XXX
+	allocChan        = _allocBase + _allocPointer + _allocChanValue
+	allocChanItem    = _allocTypedValue
 	allocBoundMethod = _allocBase + _allocPointer + _allocBoundMethodValue
 	allocBlock       = _allocBase + _allocPointer + _allocBlock
 	allocBlockItem   = _allocTypedValue
@@ -63,6 +67,7 @@ const (
 	allocAmino     = _allocBase + _allocPointer + _allocAny
 	allocAminoByte = 10 // XXX
 	allocHeapItem  = _allocBase + _allocPointer + _allocTypedValue
+	allocGoroutine = _allocBase + _allocPointer + _allocGoroutine
 )
 
 func NewAllocator(maxBytes int64) *Allocator {
@@ -149,6 +154,10 @@ func (alloc *Allocator) AllocateMapItem() {
 	alloc.Allocate(allocMapItem)
 }
 
+func (alloc *Allocator) AllocateChan(size int64) {
+	alloc.Allocate(allocChan + allocChanItem*size)
+}
+
 func (alloc *Allocator) AllocateBoundMethod() {
 	alloc.Allocate(allocBoundMethod)
 }
@@ -185,6 +194,10 @@ func (alloc *Allocator) AllocateHeapItem() {
 	alloc.Allocate(allocHeapItem)
 }
 
+func (alloc *Allocator) AllocateGoroutine() {
+	alloc.Allocate(allocGoroutine)
+}
+
 //----------------------------------------
 // constructor utilities.
 
@@ -287,6 +300,13 @@ func (alloc *Allocator) NewMap(size int) *MapValue {
 	return mv
 }
 
+func (alloc *Allocator) NewChan(size int) *ChanValue {
+	alloc.AllocateChan(int64(size))
+	return &ChanValue{
+		Cap: size,
+	}
+}
+
 func (alloc *Allocator) NewBlock(source BlockNode, parent *Block) *Block {
 	alloc.AllocateBlock(int64(source.GetNumNames()))
 	return NewBlock(source, parent)
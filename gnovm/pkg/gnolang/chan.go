@@ -0,0 +1,342 @@
+package gnolang
+
+// A chanWaiter is a goroutine blocked on a channel, either in a send, a
+// receive, or one of the cases of a select statement.
+type chanWaiter struct {
+	g      *goroutine
+	ch     *ChanValue
+	index  int        // select case index, or -1
+	value  TypedValue // value to send, or value received
+	closed bool       // woken up by close
+}
+
+func removeChanWaiter(q []*chanWaiter, w *chanWaiter) []*chanWaiter {
+	for i, qw := range q {
+		if qw == w {
+			return append(q[:i], q[i+1:]...)
+		}
+	}
+	return q
+}
+
+func popChanWaiter(q *[]*chanWaiter) *chanWaiter {
+	w := (*q)[0]
+	(*q)[0] = nil
+	*q = (*q)[1:]
+	return w
+}
+
+// Registers a waiter for the running goroutine on queue q.  If no go
+// statement was executed, nothing is registered, as no other goroutine
+// could ever wake up the running one (see parkGoroutine).
+func (m *Machine) addChanWaiter(q *[]*chanWaiter, w *chanWaiter) {
+	g := m.currentGoroutine()
+	if g == nil {
+		return
+	}
+	w.g = g
+	*q = append(*q, w)
+	g.waiters = append(g.waiters, w)
+}
+
+// Wakes up the goroutine of w, which must already be dequeued.
+// Other waiters of the same goroutine (i.e. the other cases of a select)
+// are dequeued as well.
+func (m *Machine) wakeChanWaiter(w *chanWaiter) {
+	g := w.g
+	for _, gw := range g.waiters {
+		if gw != w {
+			gw.ch.recvq = removeChanWaiter(gw.ch.recvq, gw)
+			gw.ch.sendq = removeChanWaiter(gw.ch.sendq, gw)
+		}
+	}
+	g.waiters = nil
+	g.wait = w
+	m.readyGoroutine(g)
+}
+
+// Returns the waiter which woke up the running goroutine, if any.
+func (m *Machine) takeChanWait() *chanWaiter {
+	g := m.currentGoroutine()
+	if g == nil || g.wait == nil {
+		return nil
+	}
+	w := g.wait
+	g.wait = nil
+	return w
+}
+
+// Receives from cv without blocking.  If ready is false, the receive
+// would block.  et is the element type, for receiving from a closed
+// channel.
+func (m *Machine) tryRecvChan(cv *ChanValue, et Type) (rv TypedValue, ok bool, ready bool) {
+	if cv == nil {
+		// receiving from a nil channel blocks forever.
+		return
+	}
+	if len(cv.Buffer) > 0 {
+		rv = cv.Buffer[0]
+		cv.Buffer[0] = TypedValue{}
+		cv.Buffer = cv.Buffer[1:]
+		if len(cv.sendq) > 0 {
+			// make room for a blocked sender.
+			w := popChanWaiter(&cv.sendq)
+			cv.Buffer = append(cv.Buffer, w.value)
+			m.wakeChanWaiter(w)
+		}
+		return rv, true, true
+	}
+	if len(cv.sendq) > 0 {
+		w := popChanWaiter(&cv.sendq)
+		rv = w.value
+		m.wakeChanWaiter(w)
+		return rv, true, true
+	}
+	if cv.Closed {
+		return defaultTypedValue(m.Alloc, et), false, true
+	}
+	return
+}
+
+// Sends tv to cv without blocking.  If ready is false, the send would
+// block.  cv must not be closed.
+func (m *Machine) trySendChan(cv *ChanValue, tv TypedValue) (ready bool) {
+	if cv == nil {
+		// sending to a nil channel blocks forever.
+		return false
+	}
+	if len(cv.recvq) > 0 {
+		w := popChanWaiter(&cv.recvq)
+		w.value = tv
+		m.wakeChanWaiter(w)
+		return true
+	}
+	if len(cv.Buffer) < cv.Cap {
+		cv.Buffer = append(cv.Buffer, tv)
+		return true
+	}
+	return false
+}
+
+// Receives from the channel xv.  If the receive cannot proceed, the
+// running goroutine is registered as waiting on the channel and ready is
+// false; the caller must then re-queue its operation and park, to retry
+// once woken up.
+func (m *Machine) recvChan(xv *TypedValue) (rv TypedValue, ok bool, ready bool) {
+	et := baseOf(xv.T).(*ChanType).Elt
+	if w := m.takeChanWait(); w != nil {
+		if w.closed {
+			return defaultTypedValue(m.Alloc, et), false, true
+		}
+		return w.value, true, true
+	}
+	cv, _ := xv.V.(*ChanValue)
+	rv, ok, ready = m.tryRecvChan(cv, et)
+	if !ready && cv != nil {
+		m.addChanWaiter(&cv.recvq, &chanWaiter{ch: cv, index: -1})
+	}
+	return
+}
+
+// Like recvChan, but for sending.  Returns false without registering a
+// waiter if the channel is closed; see doOpSend.
+func (m *Machine) sendChan(xv *TypedValue, tv TypedValue) (ready bool, closed bool) {
+	if w := m.takeChanWait(); w != nil {
+		return true, w.closed
+	}
+	cv, _ := xv.V.(*ChanValue)
+	if cv != nil && cv.Closed {
+		return false, true
+	}
+	ready = m.trySendChan(cv, tv)
+	if !ready && cv != nil {
+		m.addChanWaiter(&cv.sendq, &chanWaiter{ch: cv, index: -1, value: tv})
+	}
+	return ready, false
+}
+
+func (m *Machine) closeChan(cv *ChanValue) {
+	if cv == nil {
+		m.Panic(typedString("close of nil channel"))
+		return
+	}
+	if cv.Closed {
+		m.Panic(typedString("close of closed channel"))
+		return
+	}
+	cv.Closed = true
+	// wake up all blocked receivers and senders, in order.
+	for len(cv.recvq) > 0 {
+		w := popChanWaiter(&cv.recvq)
+		w.closed = true
+		m.wakeChanWaiter(w)
+	}
+	for len(cv.sendq) > 0 {
+		w := popChanWaiter(&cv.sendq)
+		w.closed = true
+		m.wakeChanWaiter(w)
+	}
+}
+
+func (m *Machine) doOpSend() {
+	xv := m.PeekValue(2) // channel
+	vv := m.PeekValue(1) // value to send
+	ready, closed := m.sendChan(xv, vv.Copy(m.Alloc))
+	if closed {
+		m.PopValues(2)
+		m.Panic(typedString("send on closed channel"))
+		return
+	}
+	if !ready {
+		// retry once woken up.
+		m.PushOp(OpSend)
+		m.parkGoroutine()
+		return
+	}
+	m.PopValues(2)
+}
+
+// Returns the number of values evaluated for the communication of each
+// case of ss; see doOpExec:SelectStmt.
+func selectNumValues(ss *SelectStmt) int {
+	n := 0
+	for _, cs := range ss.Cases {
+		switch cs.Comm.(type) {
+		case nil: // default
+		case *SendStmt:
+			n += 2 // channel and value
+		default:
+			n += 1 // channel
+		}
+	}
+	return n
+}
+
+// NOTE: when more than one case is ready, the first one in source order is
+// selected, rather than a random one as in Go, to keep execution
+// deterministic.
+func (m *Machine) doOpSelect() {
+	ss := m.PeekStmt1().(*SelectStmt)
+	numValues := selectNumValues(ss)
+	cvs := m.Values[m.NumValues-numValues : m.NumValues]
+	// the index in cvs of the channel of each case.
+	offsets := make([]int, len(ss.Cases))
+	defaultIndex := -1
+	for i, vi := 0, 0; i < len(ss.Cases); i++ {
+		offsets[i] = vi
+		switch ss.Cases[i].Comm.(type) {
+		case nil:
+			defaultIndex = i
+		case *SendStmt:
+			vi += 2
+		default:
+			vi += 1
+		}
+	}
+
+	index := -1
+	var rv TypedValue
+	var ok bool
+	if w := m.takeChanWait(); w != nil {
+		// woken up by the channel of case w.index.
+		index = w.index
+		if _, isSend := ss.Cases[index].Comm.(*SendStmt); isSend {
+			if w.closed {
+				m.PopValues(numValues)
+				m.Panic(typedString("send on closed channel"))
+				return
+			}
+		} else if w.closed {
+			et := baseOf(cvs[offsets[index]].T).(*ChanType).Elt
+			rv, ok = defaultTypedValue(m.Alloc, et), false
+		} else {
+			rv, ok = w.value, true
+		}
+	} else {
+		for i := 0; i < len(ss.Cases) && index < 0; i++ {
+			if i == defaultIndex {
+				continue // see below.
+			}
+			xv := &cvs[offsets[i]]
+			cv, _ := xv.V.(*ChanValue)
+			switch ss.Cases[i].Comm.(type) {
+			case *SendStmt:
+				if cv != nil && cv.Closed {
+					m.PopValues(numValues)
+					m.Panic(typedString("send on closed channel"))
+					return
+				}
+				if m.trySendChan(cv, cvs[offsets[i]+1].Copy(m.Alloc)) {
+					index = i
+				}
+			default:
+				et := baseOf(xv.T).(*ChanType).Elt
+				var ready bool
+				if rv, ok, ready = m.tryRecvChan(cv, et); ready {
+					index = i
+				}
+			}
+		}
+		if index < 0 {
+			if defaultIndex < 0 {
+				// block on all cases, and retry once woken up.
+				for i := range ss.Cases {
+					xv := &cvs[offsets[i]]
+					cv, _ := xv.V.(*ChanValue)
+					if cv == nil {
+						continue
+					}
+					switch ss.Cases[i].Comm.(type) {
+					case *SendStmt:
+						tv := cvs[offsets[i]+1].Copy(m.Alloc)
+						m.addChanWaiter(&cv.sendq, &chanWaiter{ch: cv, index: i, value: tv})
+					default:
+						m.addChanWaiter(&cv.recvq, &chanWaiter{ch: cv, index: i})
+					}
+				}
+				m.PushOp(OpSelect)
+				m.parkGoroutine()
+				return
+			}
+			index = defaultIndex
+		}
+	}
+	m.PopValues(numValues)
+	m.PopStmt()
+
+	// exec the selected case.
+	cs := &ss.Cases[index]
+	b := m.Alloc.NewBlock(cs, m.LastBlock())
+	b.bodyStmt = bodyStmt{
+		Body:          cs.Body,
+		BodyLen:       len(cs.Body),
+		NextBodyIndex: -2,
+	}
+	m.PushBlock(b)
+	m.PushOp(OpPopBlock)
+	m.PushOp(OpBody)
+	m.PushStmt(b.GetBodyStmt())
+	// assign received value(s), e.g. `case v, ok := <-ch:`.
+	if as, isAssign := cs.Comm.(*AssignStmt); isAssign {
+		switch as.Op {
+		case DEFINE:
+			m.PushOp(OpDefine)
+		case ASSIGN:
+			m.PushOp(OpAssign)
+		default:
+			panic("should not happen")
+		}
+		m.PushStmt(as)
+		if len(as.Lhs) == 2 {
+			m.PushExpr(&ConstExpr{TypedValue: untypedBool(ok)})
+			m.PushOp(OpEval)
+		}
+		m.PushExpr(&ConstExpr{TypedValue: rv})
+		m.PushOp(OpEval)
+		if as.Op == ASSIGN {
+			for i := len(as.Lhs) - 1; 0 <= i; i-- {
+				m.PushForPointer(as.Lhs[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package gnolang
+
+// Coverage records how many times each statement was executed by the
+// machines it is attached to, through [MachineOptions.Coverage].
+//
+// Statements are identified by their [Location], that is by the position at
+// which they start in their source file. A Coverage may be shared by several
+// machines, but not concurrently.
+type Coverage struct {
+	counts map[Location]int64
+}
+
+// NewCoverage returns a new, empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{counts: make(map[Location]int64)}
+}
+
+// recordStmt records an execution of s, which is about to be executed in the
+// last block of m.
+func (c *Coverage) recordStmt(m *Machine, s Stmt) {
+	line := s.GetLine()
+	if line == 0 {
+		// statements injected during preprocessing have no location.
+		return
+	}
+	loc := m.LastBlock().Source.GetLocation()
+	loc.Line = line
+	loc.Column = s.GetColumn()
+	c.counts[loc]++
+}
+
+// Count returns the number of times the statement starting at loc was
+// executed.
+func (c *Coverage) Count(loc Location) int64 {
+	return c.counts[loc]
+}
+
+// Locations returns the locations of the statements that were executed at
+// least once, in no particular order.
+func (c *Coverage) Locations() []Location {
+	locs := make([]Location, 0, len(c.counts))
+	for loc := range c.counts {
+		locs = append(locs, loc)
+	}
+	return locs
+}
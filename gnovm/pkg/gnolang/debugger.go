@@ -22,10 +22,10 @@ import (
 // or each gnoVM instruction while in step mode:
 // - DebugAtInit -> DebugAtCmd: initial debugger setup is performed
 // - DebugAtCmd  -> DebugAtCmd: when command is for inspecting or setting a breakpoint
-// - DebugAtCmd  -> DebuAtRun:  when command is 'continue', 'step' or 'stepi'
+// - DebugAtCmd  -> DebuAtRun:  when command is 'continue', 'next', 'step', 'stepi' or 'stepout'
 // - DebugAtCmd  -> DebugAtExit: when command is 'quit' or 'resume'
 // - DebugAtRun  -> DebugAtRun: when current machine instruction doesn't match a breakpoint
-// - DebugAtRun  -> DebugAtCmd: when current machine instruction matches a breakpoint
+// - DebugAtRun  -> DebugAtCmd: when current machine instruction matches a breakpoint, or ends a step
 // - DebugAtRun  -> DebugAtExit: when the program terminates
 type DebugState int
 
@@ -48,10 +48,20 @@ type Debugger struct {
 	lastArg     string              // last debugger command arguments
 	loc         Location            // source location of the current machine instruction
 	prevLoc     Location            // source location of the previous machine instruction
-	breakpoints []Location          // list of breakpoints set by user, as source locations
+	breakpoints []debugBreakpoint   // list of breakpoints set by user
 	call        []Location          // for function tracking, ideally should be provided by machine frame
 	frameLevel  int                 // frame level of the current machine instruction
+	stepLoc     Location            // source location at the start of 'next'
+	stepDepth   int                 // call depth at the start of 'next' or 'stepout'
 	getSrc      func(string) string // helper to access source from repl or others
+	dap         *DAPSession         // when set, the debugger is driven by a DAP client
+}
+
+// debugBreakpoint is a breakpoint at a source location. If cond is set, the
+// program stops only if the condition evaluates to true.
+type debugBreakpoint struct {
+	loc  Location
+	cond string
 }
 
 // Enable makes the debugger d active, using in as input reader, out as output writer and f as a source helper.
@@ -90,13 +100,15 @@ func init() {
 		"exit":        {debugExit, exitUsage, exitShort, ""},
 		"help":        {debugHelp, helpUsage, helpShort, ""},
 		"list":        {debugList, listUsage, listShort, listLong},
+		"next":        {debugNext, nextUsage, nextShort, ""},
 		"print":       {debugPrint, printUsage, printShort, ""},
 		"stack":       {debugStack, stackUsage, stackShort, ""},
-		// NOTE: the difference between continue, step and stepi is handled within
-		// the main Debug() loop.
-		"step":  {debugContinue, stepUsage, stepShort, ""},
-		"stepi": {debugContinue, stepiUsage, stepiShort, ""},
-		"up":    {debugUp, upUsage, upShort, ""},
+		// NOTE: the difference between continue, next, step, stepi and stepout
+		// is handled within the main Debug() loop.
+		"step":    {debugContinue, stepUsage, stepShort, ""},
+		"stepi":   {debugContinue, stepiUsage, stepiShort, ""},
+		"stepout": {debugNext, stepoutUsage, stepoutShort, ""},
+		"up":      {debugUp, upUsage, upShort, ""},
 	}
 
 	// Sort command names for help.
@@ -113,11 +125,13 @@ func init() {
 	debugCmds["c"] = debugCmds["continue"]
 	debugCmds["h"] = debugCmds["help"]
 	debugCmds["l"] = debugCmds["list"]
+	debugCmds["n"] = debugCmds["next"]
 	debugCmds["p"] = debugCmds["print"]
 	debugCmds["quit"] = debugCmds["exit"]
 	debugCmds["q"] = debugCmds["exit"]
 	debugCmds["s"] = debugCmds["step"]
 	debugCmds["si"] = debugCmds["stepi"]
+	debugCmds["so"] = debugCmds["stepout"]
 }
 
 // Debug is the debug callback invoked at each VM execution step. It implements the DebugState FSA.
@@ -127,30 +141,49 @@ loop:
 		switch m.Debugger.state {
 		case DebugAtInit:
 			debugUpdateLocation(m)
+			m.Debugger.state = DebugAtCmd
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.start(m)
+				continue loop
+			}
 			fmt.Fprintln(m.Debugger.out, "Welcome to the Gnovm debugger. Type 'help' for list of commands.")
 			m.Debugger.scanner = bufio.NewScanner(m.Debugger.in)
-			m.Debugger.state = DebugAtCmd
 		case DebugAtCmd:
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.serve(m)
+				continue loop
+			}
 			if err := debugCmd(m); err != nil {
 				fmt.Fprintln(m.Debugger.out, "Command failed:", err)
 			}
 		case DebugAtRun:
+			if m.Debugger.dap != nil {
+				// Serve the requests received while running, e.g. pause.
+				if m.Debugger.dap.poll(m); m.Debugger.state != DebugAtRun {
+					continue loop
+				}
+			}
 			switch m.Debugger.lastCmd {
 			case "si", "stepi":
 				m.Debugger.state = DebugAtCmd
 				debugLineInfo(m)
 			case "s", "step":
 				if m.Debugger.loc != m.Debugger.prevLoc && m.Debugger.loc.File != "" {
-					m.Debugger.state = DebugAtCmd
-					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStop(m, "step")
+					continue loop
+				}
+			case "n", "next", "so", "stepout":
+				if atStepEnd(m) {
+					debugStop(m, "step")
+					continue loop
+				}
+				if !atStepLine(m) && atBreak(m) {
+					debugStop(m, "breakpoint")
 					continue loop
 				}
 			default:
 				if atBreak(m) {
-					m.Debugger.state = DebugAtCmd
-					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStop(m, "breakpoint")
 					continue loop
 				}
 			}
@@ -172,20 +205,86 @@ loop:
 	}
 }
 
-// atBreak returns true if current machine location matches a breakpoint, false otherwise.
+// debugStop stops the program at the current machine location, for the given
+// reason, and waits for the next debugger command.
+func debugStop(m *Machine, reason string) {
+	m.Debugger.state = DebugAtCmd
+	m.Debugger.prevLoc = m.Debugger.loc
+	if m.Debugger.dap != nil {
+		m.Debugger.dap.stopped(m, reason)
+		return
+	}
+	debugList(m, "")
+}
+
+// atBreak returns true if current machine location matches a breakpoint
+// whose condition, if any, holds, false otherwise.
 func atBreak(m *Machine) bool {
 	loc := m.Debugger.loc
 	if loc == m.Debugger.prevLoc {
 		return false
 	}
+	file := loc.File
+	if m.Debugger.dap != nil {
+		// DAP breakpoints are set on the paths of the source files.
+		file = m.Debugger.dap.sourcePath(m, loc)
+	}
 	for _, b := range m.Debugger.breakpoints {
-		if loc.File == b.File && loc.Line == b.Line {
+		if file != b.loc.File || loc.Line != b.loc.Line {
+			continue
+		}
+		if b.cond == "" {
+			return true
+		}
+		ok, err := debugEvalCond(m, b.cond)
+		if err != nil {
+			// Stop, so that the condition can be fixed.
+			debugOutput(m, fmt.Sprintf("Breakpoint condition %q failed: %v\n", b.cond, err))
+			return true
+		}
+		if ok {
 			return true
 		}
 	}
 	return false
 }
 
+// atStepEnd returns true if the current machine location ends the 'next' or
+// 'stepout' command in progress, false otherwise.
+func atStepEnd(m *Machine) bool {
+	loc := m.Debugger.loc
+	if loc == m.Debugger.prevLoc || loc.File == "" {
+		return false
+	}
+	depth := len(m.Debugger.call)
+	switch m.Debugger.lastCmd {
+	case "n", "next":
+		// Stop at the next line of the current function, or in its caller.
+		return depth < m.Debugger.stepDepth ||
+			depth == m.Debugger.stepDepth && (loc.File != m.Debugger.stepLoc.File || loc.Line != m.Debugger.stepLoc.Line)
+	default:
+		// Stop in the caller of the current function.
+		return depth < m.Debugger.stepDepth
+	}
+}
+
+// atStepLine returns true if the current machine location is still on the
+// line where the 'next' or 'stepout' command in progress started.
+func atStepLine(m *Machine) bool {
+	loc := m.Debugger.loc
+	return len(m.Debugger.call) == m.Debugger.stepDepth &&
+		loc.File == m.Debugger.stepLoc.File && loc.Line == m.Debugger.stepLoc.Line
+}
+
+// debugOutput writes a message of the debugger to its user.
+func debugOutput(m *Machine, msg string) {
+	if m.Debugger.dap != nil {
+		m.Debugger.dap.output(msg)
+		return
+	}
+	fmt.Fprint(m.Debugger.out, msg)
+}
+
 // debugCmd processes a debugger REPL command. It displays a prompt, then
 // reads and parses a command from the debugger input stream, then executes
 // the corresponding function or returns an error.
@@ -276,7 +375,7 @@ func debugUpdateLocation(m *Machine) {
 
 // ---------------------------------------
 const (
-	breakUsage = `break|b [locspec]`
+	breakUsage = `break|b [locspec] [if <condition>]`
 	breakShort = `Set a breakpoint.`
 	breakLong  = `
 The syntax accepted for locspec is:
@@ -284,22 +383,47 @@ The syntax accepted for locspec is:
 - <line> specifies the line in the current source file.
 - +<offset> specifies the line offset lines after the current one.
 - -<offset> specifies the line offset lines before the current one.
+If locspec is omitted, the breakpoint is set at the current line.
+
+If a condition is given, the program stops at the breakpoint only if the
+condition evaluates to true. The condition is a boolean expression, such
+as 'i == 3 && s.name != "foo"'.
 `
 )
 
-func debugBreak(m *Machine, arg string) error {
-	loc, err := parseLocSpec(m, arg)
-	if err != nil {
-		return err
+func debugBreak(m *Machine, arg string) (err error) {
+	spec, cond := arg, ""
+	if c, ok := strings.CutPrefix(arg, "if "); ok {
+		spec, cond = "", c
+	} else if s, c, ok := strings.Cut(arg, " if "); ok {
+		spec, cond = s, c
+	}
+	cond = strings.TrimSpace(cond)
+	if cond != "" {
+		if _, err := parser.ParseExpr(cond); err != nil {
+			return err
+		}
+	}
+	loc := m.Debugger.loc
+	if spec = strings.TrimSpace(spec); spec != "" {
+		if loc, err = parseLocSpec(m, spec); err != nil {
+			return err
+		}
+	} else if loc.File == "" {
+		return errors.New("unknown source file")
 	}
-	m.Debugger.breakpoints = append(m.Debugger.breakpoints, loc)
+	m.Debugger.breakpoints = append(m.Debugger.breakpoints, debugBreakpoint{loc: loc, cond: cond})
 	printBreakpoint(m, len(m.Debugger.breakpoints)-1)
 	return nil
 }
 
 func printBreakpoint(m *Machine, i int) {
 	b := m.Debugger.breakpoints[i]
-	fmt.Fprintf(m.Debugger.out, "Breakpoint %d at %s %s\n", i, b.PkgPath, b)
+	if b.cond != "" {
+		fmt.Fprintf(m.Debugger.out, "Breakpoint %d at %s %s if %s\n", i, b.loc.PkgPath, b.loc, b.cond)
+		return
+	}
+	fmt.Fprintf(m.Debugger.out, "Breakpoint %d at %s %s\n", i, b.loc.PkgPath, b.loc)
 }
 
 func parseLocSpec(m *Machine, arg string) (loc Location, err error) {
@@ -395,6 +519,23 @@ func debugContinue(m *Machine, arg string) error {
 	return nil
 }
 
+// ---------------------------------------
+const (
+	nextUsage = `next|n`
+	nextShort = `Step over to next source line.`
+)
+
+const (
+	stepoutUsage = `stepout|so`
+	stepoutShort = `Step out of the current function.`
+)
+
+func debugNext(m *Machine, arg string) error {
+	m.Debugger.stepLoc = m.Debugger.loc
+	m.Debugger.stepDepth = len(m.Debugger.call)
+	return debugContinue(m, arg)
+}
+
 // ---------------------------------------
 const (
 	detachUsage = `detach`
@@ -539,7 +680,10 @@ func isMemPackage(st Store, pkgPath string) bool {
 
 func fileContent(st Store, pkgPath, name string) (string, error) {
 	if isMemPackage(st, pkgPath) {
-		return st.GetMemFile(pkgPath, name).Body, nil
+		if mf := st.GetMemFile(pkgPath, name); mf != nil {
+			return mf.Body, nil
+		}
+		return "", fmt.Errorf("%s: source not found in package %s", name, pkgPath)
 	}
 	buf, err := os.ReadFile(name)
 	return string(buf), err
@@ -581,10 +725,12 @@ func debugPrint(m *Machine, arg string) (err error) {
 // debugEvalExpr evaluates a Go expression in the context of the VM and returns
 // the corresponding typed value, or an error.
 // The supported expression syntax is a small subset of Go expressions:
-// basic literals, identifiers, selectors, index expressions, or a combination
-// of those are supported, but none of function calls, arithmetic, logic or
-// assign operations, type assertions of convertions.
-// This is sufficient for a debugger to perform 'print (*f).S[x][y]' for example.
+// basic literals, identifiers, selectors, index expressions, comparisons,
+// logic operations, or a combination of those are supported, but none of
+// function calls, arithmetic or assign operations, type assertions of
+// convertions.
+// This is sufficient for a debugger to perform 'print (*f).S[x][y]', or to
+// evaluate a breakpoint condition such as 'i > 2 && s.name == "foo"'.
 func debugEvalExpr(m *Machine, node ast.Node) (tv TypedValue, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -616,6 +762,12 @@ func debugEvalExpr(m *Machine, node ast.Node) (tv TypedValue, err error) {
 		}
 		return tv, fmt.Errorf("invalid basic literal value: %s", n.Value)
 	case *ast.Ident:
+		switch n.Name {
+		case "nil":
+			return tv, nil
+		case "true", "false":
+			return untypedBool(n.Name == "true"), nil
+		}
 		if tv, ok := debugLookup(m, n.Name); ok {
 			return tv, nil
 		}
@@ -661,16 +813,150 @@ func debugEvalExpr(m *Machine, node ast.Node) (tv TypedValue, err error) {
 			return tv, err
 		}
 		return x.GetPointerAtIndex(m.Alloc, m.Store, &index).Deref(), nil
+	case *ast.UnaryExpr:
+		if n.Op != token.NOT {
+			return tv, fmt.Errorf("expression not supported: operator %s", n.Op)
+		}
+		x, err := debugEvalBool(m, n.X)
+		if err != nil {
+			return tv, err
+		}
+		return untypedBool(!x), nil
+	case *ast.BinaryExpr:
+		return debugEvalBinary(m, n)
 	default:
 		err = fmt.Errorf("expression not supported: %v", n)
 	}
 	return tv, err
 }
 
+// debugEvalBinary evaluates a comparison or logic binary expression.
+func debugEvalBinary(m *Machine, n *ast.BinaryExpr) (tv TypedValue, err error) {
+	switch n.Op {
+	case token.LAND, token.LOR:
+		x, err := debugEvalBool(m, n.X)
+		if err != nil {
+			return tv, err
+		}
+		if x == (n.Op == token.LOR) {
+			return untypedBool(x), nil
+		}
+		y, err := debugEvalBool(m, n.Y)
+		if err != nil {
+			return tv, err
+		}
+		return untypedBool(y), nil
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+	default:
+		return tv, fmt.Errorf("expression not supported: operator %s", n.Op)
+	}
+	x, err := debugEvalExpr(m, n.X)
+	if err != nil {
+		return tv, err
+	}
+	y, err := debugEvalExpr(m, n.Y)
+	if err != nil {
+		return tv, err
+	}
+	if isNilIdent(n.X) || isNilIdent(n.Y) {
+		if n.Op != token.EQL && n.Op != token.NEQ {
+			return tv, fmt.Errorf("invalid operation: operator %s not defined on nil", n.Op)
+		}
+		if isNilIdent(n.X) {
+			x, y = y, x
+		}
+		return untypedBool((x.T == nil || x.V == nil) == (n.Op == token.EQL)), nil
+	}
+	// Untyped constants take the type of the other operand.
+	if x.T != nil && y.T != nil && x.T.TypeID() != y.T.TypeID() {
+		if _, ok := n.Y.(*ast.BasicLit); ok {
+			ConvertTo(m.Alloc, m.Store, &y, x.T)
+		} else if _, ok := n.X.(*ast.BasicLit); ok {
+			ConvertTo(m.Alloc, m.Store, &x, y.T)
+		} else {
+			return tv, fmt.Errorf("mismatched types %v and %v", x.T, y.T)
+		}
+	}
+	switch n.Op {
+	case token.EQL:
+		return untypedBool(isEql(m.Store, &x, &y)), nil
+	case token.NEQ:
+		return untypedBool(!isEql(m.Store, &x, &y)), nil
+	case token.LSS:
+		return untypedBool(isLss(&x, &y)), nil
+	case token.LEQ:
+		return untypedBool(isLeq(&x, &y)), nil
+	case token.GTR:
+		return untypedBool(isGtr(&x, &y)), nil
+	default:
+		return untypedBool(isGeq(&x, &y)), nil
+	}
+}
+
+// debugEvalBool evaluates a Go expression which must return a boolean value.
+func debugEvalBool(m *Machine, node ast.Node) (bool, error) {
+	tv, err := debugEvalExpr(m, node)
+	if err != nil {
+		return false, err
+	}
+	if tv.T == nil || tv.T.Kind() != BoolKind {
+		return false, fmt.Errorf("not a boolean value: %v", tv)
+	}
+	return tv.GetBool(), nil
+}
+
+// debugEvalCond evaluates a breakpoint condition in the current frame.
+func debugEvalCond(m *Machine, cond string) (bool, error) {
+	node, err := parser.ParseExpr(cond)
+	if err != nil {
+		return false, err
+	}
+	level := m.Debugger.frameLevel
+	m.Debugger.frameLevel = 0
+	defer func() { m.Debugger.frameLevel = level }()
+	return debugEvalBool(m, node)
+}
+
+func isNilIdent(x ast.Expr) bool {
+	id, ok := x.(*ast.Ident)
+	return ok && id.Name == "nil"
+}
+
 // debugLookup returns the current VM value corresponding to name ident in
 // the current function call frame, or the global frame if not found.
 // Note: the commands 'up' and 'down' change the frame level to start from.
 func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
+	sblocks := debugFrameBlocks(m, m.Debugger.frameLevel)
+	if len(sblocks) == 0 {
+		return tv, false
+	}
+
+	// Search value in current frame level blocks, or main scope.
+	for _, b := range sblocks {
+		switch t := b.Source.(type) {
+		case *IfStmt:
+			for i, s := range ifBody(m, t, m.Debugger.frameLevel).Source.GetBlockNames() {
+				if string(s) == name {
+					return b.Values[i], true
+				}
+			}
+		}
+		for i, s := range b.Source.GetBlockNames() {
+			if string(s) == name {
+				return b.Values[i], true
+			}
+		}
+	}
+	// Fallback: search a global value.
+	if v := sblocks[0].Source.GetValueRef(m.Store, Name(name), true); v != nil {
+		return *v, true
+	}
+	return tv, false
+}
+
+// debugFrameBlocks returns the blocks of the function call frame at the given
+// level, from the innermost to the outermost, followed by the global block.
+func debugFrameBlocks(m *Machine, level int) []*Block {
 	// Position to the right frame.
 	ncall := 0
 	var i int
@@ -680,7 +966,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		if m.Frames[i].Func != nil {
 			funBlock = m.Frames[i].Func.Source
 		}
-		if ncall == m.Debugger.frameLevel {
+		if ncall == level {
 			break
 		}
 		if m.Frames[i].Func != nil {
@@ -689,7 +975,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// Position to the right block, i.e the first after the last fblock (if any).
@@ -702,7 +988,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// get SourceBlocks in the same frame level.
@@ -716,33 +1002,13 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 	if i > 0 {
 		sblocks = append(sblocks, m.Blocks[0]) // Add global block
 	}
-
-	// Search value in current frame level blocks, or main scope.
-	for _, b := range sblocks {
-		switch t := b.Source.(type) {
-		case *IfStmt:
-			for i, s := range ifBody(m, t).Source.GetBlockNames() {
-				if string(s) == name {
-					return b.Values[i], true
-				}
-			}
-		}
-		for i, s := range b.Source.GetBlockNames() {
-			if string(s) == name {
-				return b.Values[i], true
-			}
-		}
-	}
-	// Fallback: search a global value.
-	if v := sblocks[0].Source.GetValueRef(m.Store, Name(name), true); v != nil {
-		return *v, true
-	}
-	return tv, false
+	return sblocks
 }
 
-// ifBody returns the Then or Else body corresponding to the current location.
-func ifBody(m *Machine, ifStmt *IfStmt) IfCaseStmt {
-	if l := ifStmt.Else.GetLocation().Line; l > 0 && debugFrameLoc(m, m.Debugger.frameLevel).Line > l {
+// ifBody returns the Then or Else body corresponding to the location in the
+// frame at the given level.
+func ifBody(m *Machine, ifStmt *IfStmt, level int) IfCaseStmt {
+	if l := ifStmt.Else.GetLocation().Line; l > 0 && debugFrameLoc(m, level).Line > l {
 		return ifStmt.Else
 	}
 	return ifStmt.Then
@@ -762,18 +1028,23 @@ func debugStack(m *Machine, arg string) error {
 		if ff == nil {
 			break
 		}
-		var fname string
-		if ff.IsMethod {
-			fname = fmt.Sprintf("%v.(%v).%v", ff.PkgPath, ff.Type.(*FuncType).Params[0].Type, ff.Name)
-		} else {
-			fname = fmt.Sprintf("%v.%v", ff.PkgPath, ff.Name)
-		}
-		fmt.Fprintf(m.Debugger.out, "%d\tin %s\n\tat %s\n", i, fname, loc)
+		fmt.Fprintf(m.Debugger.out, "%d\tin %s\n\tat %s\n", i, debugFuncName(ff), loc)
 		i++
 	}
 	return nil
 }
 
+func debugFuncName(ff *FuncValue) string {
+	name := ff.Name
+	if name == "" {
+		name = "func" // function literal.
+	}
+	if ff.IsMethod {
+		return fmt.Sprintf("%v.(%v).%v", ff.PkgPath, ff.Type.(*FuncType).Params[0].Type, name)
+	}
+	return fmt.Sprintf("%v.%v", ff.PkgPath, name)
+}
+
 func debugFrameFunc(m *Machine, n int) *FuncValue {
 	for ncall, i := 0, len(m.Frames)-1; i >= 0; i-- {
 		f := m.Frames[i]
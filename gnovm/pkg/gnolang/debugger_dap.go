@@ -0,0 +1,833 @@
+package gnolang
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DAPSession is a Debug Adapter Protocol session between the debugger and a
+// client, such as an editor. The requests of the client are mapped onto the
+// debugger commands and frame inspection, and served from Machine.Debug.
+// A session can be shared by the debuggers of machines run one after the
+// other, as for the tests of a package.
+// See https://microsoft.github.io/debug-adapter-protocol/specification.
+type DAPSession struct {
+	conn io.ReadWriteCloser
+	reqs chan *dapRequest // requests received from the client, closed on error
+
+	mu  sync.Mutex // guards conn writes and seq
+	seq int
+
+	configured  bool                         // configurationDone was received
+	stopOnEntry bool                         // stop at the first instruction
+	closed      bool                         // session was closed or detached
+	bpID        int                          // last breakpoint id
+	breakpoints map[string][]debugBreakpoint // breakpoints per source path
+	rootDir     string                       // gno root directory, for stdlibs and examples
+	dirs        map[string]string            // package source directory per package path
+	paths       map[dapFile]string           // source path per location file
+	sources     []dapFile                    // sources referenced by number, when not on disk
+	vars        []dapVar                     // variables referenced by number, while stopped
+}
+
+// dapFile identifies a source file of a package.
+type dapFile struct {
+	pkgPath, name string
+}
+
+// dapVar is a variable container referenced by the client: either the
+// locals or the globals of a frame, or the elements of a value.
+type dapVar struct {
+	scope string // "locals", "globals" or "" for a value
+	level int    // frame level of scope
+	tv    TypedValue
+}
+
+// dapThreadID is the only thread reported to the client.
+const dapThreadID = 1
+
+// dapMaxChildren is the maximum number of elements of a value sent to the client.
+const dapMaxChildren = 100
+
+// ListenDAP waits for a DAP client to connect at tcp address addr and
+// returns the corresponding session.
+func ListenDAP(addr string) (*DAPSession, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+	print("Waiting for DAP client to connect at ", addr)
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	println(" connected!")
+	return NewDAPSession(conn), nil
+}
+
+// NewDAPSession returns a session serving the DAP client connected to conn.
+func NewDAPSession(conn io.ReadWriteCloser) *DAPSession {
+	s := &DAPSession{
+		conn:        conn,
+		reqs:        make(chan *dapRequest),
+		breakpoints: map[string][]debugBreakpoint{},
+		dirs:        map[string]string{},
+		paths:       map[dapFile]string{},
+	}
+	go s.read()
+	return s
+}
+
+// SetPackageDir sets the directory of the source files of package pkgPath,
+// for the packages which are run from memory.
+func (s *DAPSession) SetPackageDir(pkgPath, dir string) {
+	s.dirs[pkgPath] = dir
+}
+
+// SetRootDir sets the gno root directory, to locate the source files of the
+// standard libraries and of the examples packages.
+func (s *DAPSession) SetRootDir(dir string) {
+	s.rootDir = dir
+}
+
+// Close terminates the session. It must be called when the program ends.
+func (s *DAPSession) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.event("terminated", nil)
+	return s.conn.Close()
+}
+
+// EnableDAP makes the debugger d active, driven by the DAP session s.
+// It does nothing if the session is closed.
+func (d *Debugger) EnableDAP(s *DAPSession) {
+	if s == nil || s.closed {
+		return
+	}
+	d.in, d.out = nil, io.Discard
+	d.dap = s
+	d.enabled = true
+	d.state = DebugAtInit
+}
+
+// ---------------------------------------
+// Protocol messages.
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+func (r *dapRequest) args(v any) error {
+	if len(r.Arguments) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Arguments, v)
+}
+
+type dapResponse struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name            string `json:"name,omitempty"`
+	Path            string `json:"path,omitempty"`
+	SourceReference int    `json:"sourceReference,omitempty"`
+}
+
+type dapBreakpoint struct {
+	ID       int        `json:"id"`
+	Verified bool       `json:"verified"`
+	Message  string     `json:"message,omitempty"`
+	Line     int        `json:"line,omitempty"`
+	Source   *dapSource `json:"source,omitempty"`
+}
+
+type dapStackFrame struct {
+	ID     int        `json:"id"`
+	Name   string     `json:"name"`
+	Source *dapSource `json:"source,omitempty"`
+	Line   int        `json:"line"`
+	Column int        `json:"column"`
+}
+
+type dapScope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// read reads the requests of the client until the connection fails.
+func (s *DAPSession) read() {
+	defer close(s.reqs)
+	r := bufio.NewReader(s.conn)
+	for {
+		tp := textproto.NewReader(r)
+		header, err := tp.ReadMIMEHeader()
+		if err != nil {
+			return
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+		if err != nil || length < 0 {
+			return
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return
+		}
+		var req dapRequest
+		if err := json.Unmarshal(body, &req); err != nil || req.Type != "request" {
+			continue
+		}
+		s.reqs <- &req
+	}
+}
+
+func (s *DAPSession) write(msg any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	switch m := msg.(type) {
+	case *dapResponse:
+		m.Seq = s.seq
+	case *dapEvent:
+		m.Seq = s.seq
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	// Write errors are detected by the reader, as the connection is lost.
+	fmt.Fprintf(s.conn, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func (s *DAPSession) reply(req *dapRequest, body any, err error) {
+	res := &dapResponse{Type: "response", RequestSeq: req.Seq, Command: req.Command, Success: err == nil, Body: body}
+	if err != nil {
+		res.Message = err.Error()
+	}
+	s.write(res)
+}
+
+func (s *DAPSession) event(event string, body any) {
+	s.write(&dapEvent{Type: "event", Event: event, Body: body})
+}
+
+// output sends a message of the debugger to the client console.
+func (s *DAPSession) output(msg string) {
+	s.event("output", map[string]any{"category": "console", "output": msg})
+}
+
+// stopped notifies the client that the program stopped for the given reason.
+func (s *DAPSession) stopped(m *Machine, reason string) {
+	s.vars = nil
+	s.event("stopped", map[string]any{"reason": reason, "threadId": dapThreadID, "allThreadsStopped": true})
+}
+
+// ---------------------------------------
+// Interaction with the debugger state machine.
+
+// start starts debugging a new machine. The first machine of the session
+// waits for the client configuration, the next ones run to the first
+// breakpoint.
+func (s *DAPSession) start(m *Machine) {
+	m.Debugger.breakpoints = s.breakpointList()
+	if s.configured {
+		s.resume(m, "continue")
+	}
+}
+
+// serve waits for a request of the client and serves it.
+func (s *DAPSession) serve(m *Machine) {
+	req, ok := <-s.reqs
+	if !ok {
+		s.detach(m)
+		return
+	}
+	s.handle(m, req)
+}
+
+// poll serves the pending request of the client, if any, without waiting.
+func (s *DAPSession) poll(m *Machine) {
+	select {
+	case req, ok := <-s.reqs:
+		if !ok {
+			s.detach(m)
+			return
+		}
+		s.handle(m, req)
+	default:
+	}
+}
+
+// resume resumes the program with the given debugger command.
+func (s *DAPSession) resume(m *Machine, cmd string) {
+	s.vars = nil
+	m.Debugger.lastCmd, m.Debugger.lastArg = cmd, ""
+	switch cmd {
+	case "next", "stepout":
+		debugNext(m, "")
+	default:
+		debugContinue(m, "")
+	}
+}
+
+// detach closes the session and lets the program run without debugger.
+func (s *DAPSession) detach(m *Machine) {
+	if !s.closed {
+		s.closed = true
+		s.conn.Close()
+	}
+	m.Debugger.enabled = false
+	m.Debugger.dap = nil
+	m.Debugger.breakpoints = nil
+	m.Debugger.lastCmd = "continue"
+	m.Debugger.state = DebugAtRun
+}
+
+// breakpointList returns the breakpoints of all source files.
+func (s *DAPSession) breakpointList() (bps []debugBreakpoint) {
+	paths := make([]string, 0, len(s.breakpoints))
+	for path := range s.breakpoints {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		bps = append(bps, s.breakpoints[path]...)
+	}
+	return bps
+}
+
+// sourcePath returns the absolute path of the source file of loc, or an
+// empty string if the file is not on disk.
+func (s *DAPSession) sourcePath(m *Machine, loc Location) string {
+	f := dapFile{loc.PkgPath, loc.File}
+	if path, ok := s.paths[f]; ok {
+		return path
+	}
+	var names []string
+	if dir, ok := s.dirs[loc.PkgPath]; ok {
+		names = append(names, filepath.Join(dir, filepath.Base(loc.File)))
+	} else if !isMemPackage(m.Store, loc.PkgPath) {
+		names = append(names, loc.File)
+	}
+	if s.rootDir != "" {
+		names = append(names,
+			filepath.Join(s.rootDir, "gnovm", "stdlibs", loc.PkgPath, loc.File),
+			filepath.Join(s.rootDir, "examples", loc.PkgPath, loc.File))
+	}
+	path := ""
+	for _, name := range names {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(abs); err == nil {
+			path = abs
+			break
+		}
+	}
+	s.paths[f] = path
+	return path
+}
+
+// source returns the client source of loc. The content of a source which is
+// not on disk is provided by reference.
+func (s *DAPSession) source(m *Machine, loc Location) *dapSource {
+	if loc.File == "" {
+		return nil
+	}
+	if path := s.sourcePath(m, loc); path != "" {
+		return &dapSource{Name: filepath.Base(path), Path: path}
+	}
+	f := dapFile{loc.PkgPath, loc.File}
+	ref := 0
+	for i, sf := range s.sources {
+		if sf == f {
+			ref = i + 1
+			break
+		}
+	}
+	if ref == 0 {
+		s.sources = append(s.sources, f)
+		ref = len(s.sources)
+	}
+	return &dapSource{Name: loc.PkgPath + "/" + filepath.Base(loc.File), SourceReference: ref}
+}
+
+// ---------------------------------------
+// Requests.
+
+var dapHandlers = map[string]func(*DAPSession, *Machine, *dapRequest) error{
+	"attach":                  (*DAPSession).launch,
+	"configurationDone":       (*DAPSession).configurationDone,
+	"continue":                (*DAPSession).cont,
+	"disconnect":              (*DAPSession).disconnect,
+	"evaluate":                (*DAPSession).evaluate,
+	"initialize":              (*DAPSession).initialize,
+	"launch":                  (*DAPSession).launch,
+	"next":                    (*DAPSession).step,
+	"pause":                   (*DAPSession).pause,
+	"scopes":                  (*DAPSession).scopes,
+	"setBreakpoints":          (*DAPSession).setBreakpoints,
+	"setExceptionBreakpoints": (*DAPSession).setExceptionBreakpoints,
+	"source":                  (*DAPSession).sourceContent,
+	"stackTrace":              (*DAPSession).stackTrace,
+	"stepIn":                  (*DAPSession).step,
+	"stepOut":                 (*DAPSession).step,
+	"threads":                 (*DAPSession).threads,
+	"variables":               (*DAPSession).variables,
+}
+
+func (s *DAPSession) handle(m *Machine, req *dapRequest) {
+	h, ok := dapHandlers[req.Command]
+	if !ok {
+		s.reply(req, nil, fmt.Errorf("unsupported request: %s", req.Command))
+		return
+	}
+	if err := s.call(h, m, req); err != nil {
+		s.reply(req, nil, err)
+	}
+}
+
+// call calls the request handler h, and recovers from VM panics while
+// inspecting values.
+func (s *DAPSession) call(h func(*DAPSession, *Machine, *dapRequest) error, m *Machine, req *dapRequest) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return h(s, m, req)
+}
+
+func (s *DAPSession) initialize(m *Machine, req *dapRequest) error {
+	s.reply(req, map[string]any{
+		"supportsConfigurationDoneRequest": true,
+		"supportsConditionalBreakpoints":   true,
+		"supportsEvaluateForHovers":        true,
+		"supportTerminateDebuggee":         true,
+	}, nil)
+	s.event("initialized", nil)
+	return nil
+}
+
+func (s *DAPSession) launch(m *Machine, req *dapRequest) error {
+	var args struct {
+		StopOnEntry bool `json:"stopOnEntry"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	s.stopOnEntry = args.StopOnEntry
+	s.reply(req, nil, nil)
+	return nil
+}
+
+func (s *DAPSession) setBreakpoints(m *Machine, req *dapRequest) error {
+	var args struct {
+		Source      dapSource `json:"source"`
+		Breakpoints []struct {
+			Line      int    `json:"line"`
+			Condition string `json:"condition"`
+		} `json:"breakpoints"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	path := args.Source.Path
+	if path == "" {
+		return errors.New("breakpoints are only supported in source files on disk")
+	}
+	bps := []debugBreakpoint{}
+	res := []dapBreakpoint{}
+	for _, b := range args.Breakpoints {
+		s.bpID++
+		rb := dapBreakpoint{ID: s.bpID, Verified: true, Line: b.Line, Source: &dapSource{Name: filepath.Base(path), Path: path}}
+		cond := strings.TrimSpace(b.Condition)
+		if cond != "" {
+			if _, err := parser.ParseExpr(cond); err != nil {
+				rb.Verified, rb.Message = false, err.Error()
+				res = append(res, rb)
+				continue
+			}
+		}
+		bps = append(bps, debugBreakpoint{loc: Location{File: path, Line: b.Line}, cond: cond})
+		res = append(res, rb)
+	}
+	s.breakpoints[path] = bps
+	m.Debugger.breakpoints = s.breakpointList()
+	s.reply(req, map[string]any{"breakpoints": res}, nil)
+	return nil
+}
+
+func (s *DAPSession) setExceptionBreakpoints(m *Machine, req *dapRequest) error {
+	s.reply(req, nil, nil)
+	return nil
+}
+
+func (s *DAPSession) configurationDone(m *Machine, req *dapRequest) error {
+	s.reply(req, nil, nil)
+	if s.configured {
+		return nil
+	}
+	s.configured = true
+	if s.stopOnEntry {
+		debugStop(m, "entry")
+		return nil
+	}
+	s.resume(m, "continue")
+	return nil
+}
+
+func (s *DAPSession) threads(m *Machine, req *dapRequest) error {
+	s.reply(req, map[string]any{"threads": []map[string]any{{"id": dapThreadID, "name": "main"}}}, nil)
+	return nil
+}
+
+func (s *DAPSession) stackTrace(m *Machine, req *dapRequest) error {
+	var args struct {
+		StartFrame int `json:"startFrame"`
+		Levels     int `json:"levels"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	frames := []dapStackFrame{}
+	n := 0
+	for ; debugFrameFunc(m, n) != nil; n++ {
+		if n < args.StartFrame || args.Levels > 0 && len(frames) >= args.Levels {
+			continue
+		}
+		loc := debugFrameLoc(m, n)
+		frames = append(frames, dapStackFrame{
+			ID:     n + 1,
+			Name:   debugFuncName(debugFrameFunc(m, n)),
+			Source: s.source(m, loc),
+			Line:   loc.Line,
+			Column: loc.Column,
+		})
+	}
+	s.reply(req, map[string]any{"stackFrames": frames, "totalFrames": n}, nil)
+	return nil
+}
+
+func (s *DAPSession) scopes(m *Machine, req *dapRequest) error {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	level := max(args.FrameID-1, 0)
+	scopes := []dapScope{
+		{Name: "Locals", VariablesReference: s.ref(dapVar{scope: "locals", level: level})},
+		{Name: "Globals", VariablesReference: s.ref(dapVar{scope: "globals", level: level}), Expensive: true},
+	}
+	s.reply(req, map[string]any{"scopes": scopes}, nil)
+	return nil
+}
+
+func (s *DAPSession) variables(m *Machine, req *dapRequest) error {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	if args.VariablesReference < 1 || args.VariablesReference > len(s.vars) {
+		return fmt.Errorf("invalid variables reference: %d", args.VariablesReference)
+	}
+	v := s.vars[args.VariablesReference-1]
+	var vars []dapVariable
+	switch v.scope {
+	case "locals":
+		vars = s.frameLocals(m, v.level)
+	case "globals":
+		vars = s.frameGlobals(m, v.level)
+	default:
+		vars = s.children(m, v.tv)
+	}
+	if vars == nil {
+		vars = []dapVariable{}
+	}
+	s.reply(req, map[string]any{"variables": vars}, nil)
+	return nil
+}
+
+func (s *DAPSession) evaluate(m *Machine, req *dapRequest) error {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	node, err := parser.ParseExpr(args.Expression)
+	if err != nil {
+		return err
+	}
+	level := m.Debugger.frameLevel
+	m.Debugger.frameLevel = max(args.FrameID-1, 0)
+	defer func() { m.Debugger.frameLevel = level }()
+	tv, err := debugEvalExpr(m, node)
+	if err != nil {
+		return err
+	}
+	v := s.variable(m, "", tv)
+	s.reply(req, map[string]any{"result": v.Value, "type": v.Type, "variablesReference": v.VariablesReference}, nil)
+	return nil
+}
+
+func (s *DAPSession) cont(m *Machine, req *dapRequest) error {
+	s.reply(req, map[string]any{"allThreadsContinued": true}, nil)
+	s.resume(m, "continue")
+	return nil
+}
+
+func (s *DAPSession) step(m *Machine, req *dapRequest) error {
+	s.reply(req, nil, nil)
+	switch req.Command {
+	case "next":
+		s.resume(m, "next")
+	case "stepIn":
+		s.resume(m, "step")
+	default:
+		s.resume(m, "stepout")
+	}
+	return nil
+}
+
+func (s *DAPSession) pause(m *Machine, req *dapRequest) error {
+	s.reply(req, nil, nil)
+	if m.Debugger.state == DebugAtRun {
+		debugStop(m, "pause")
+	}
+	return nil
+}
+
+func (s *DAPSession) sourceContent(m *Machine, req *dapRequest) error {
+	var args struct {
+		Source          dapSource `json:"source"`
+		SourceReference int       `json:"sourceReference"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	ref := args.SourceReference
+	if args.Source.SourceReference > 0 {
+		ref = args.Source.SourceReference
+	}
+	if ref < 1 || ref > len(s.sources) {
+		return fmt.Errorf("invalid source reference: %d", ref)
+	}
+	f := s.sources[ref-1]
+	content, err := fileContent(m.Store, f.pkgPath, f.name)
+	if err != nil {
+		return err
+	}
+	s.reply(req, map[string]any{"content": content}, nil)
+	return nil
+}
+
+func (s *DAPSession) disconnect(m *Machine, req *dapRequest) error {
+	var args struct {
+		TerminateDebuggee bool `json:"terminateDebuggee"`
+	}
+	if err := req.args(&args); err != nil {
+		return err
+	}
+	s.reply(req, nil, nil)
+	if args.TerminateDebuggee {
+		s.Close()
+		m.Debugger.state = DebugAtExit
+		return nil
+	}
+	s.detach(m)
+	return nil
+}
+
+// ---------------------------------------
+// Variables.
+
+// ref returns a new variables reference to v, valid until the program resumes.
+func (s *DAPSession) ref(v dapVar) int {
+	s.vars = append(s.vars, v)
+	return len(s.vars)
+}
+
+// frameLocals returns the local variables of the frame at the given level,
+// the innermost first.
+func (s *DAPSession) frameLocals(m *Machine, level int) (vars []dapVariable) {
+	blocks := debugFrameBlocks(m, level)
+	seen := map[Name]bool{}
+	for _, b := range blocks {
+		if b == m.Blocks[0] {
+			continue // global block.
+		}
+		names := b.Source.GetBlockNames()
+		if t, ok := b.Source.(*IfStmt); ok {
+			names = ifBody(m, t, level).Source.GetBlockNames()
+		}
+		for i, n := range names {
+			if i >= len(b.Values) || n == blankIdentifier || seen[n] {
+				continue
+			}
+			seen[n] = true
+			vars = append(vars, s.variable(m, string(n), b.GetPointerToInt(m.Store, i).Deref()))
+		}
+	}
+	return vars
+}
+
+// frameGlobals returns the global variables of the package of the function
+// of the frame at the given level.
+func (s *DAPSession) frameGlobals(m *Machine, level int) (vars []dapVariable) {
+	ff := debugFrameFunc(m, level)
+	if ff == nil {
+		return nil
+	}
+	b := ff.GetPackage(m.Store).GetBlock(m.Store)
+	for i, n := range b.Source.GetBlockNames() {
+		if i >= len(b.Values) {
+			break
+		}
+		tv := b.GetPointerToInt(m.Store, i).Deref()
+		if tv.T != nil && (tv.T.Kind() == TypeKind || tv.T.Kind() == FuncKind && tv.V != nil) {
+			continue // type and function declarations.
+		}
+		vars = append(vars, s.variable(m, string(n), tv))
+	}
+	return vars
+}
+
+// variable returns the client variable of a value. Composite values are
+// referenced, so that the client can request their elements.
+func (s *DAPSession) variable(m *Machine, name string, tv TypedValue) dapVariable {
+	fillValueTV(m.Store, &tv)
+	v := dapVariable{Name: name, Value: dapValueString(tv)}
+	if tv.T == nil {
+		return v
+	}
+	v.Type = tv.T.String()
+	if dapHasChildren(tv) {
+		v.VariablesReference = s.ref(dapVar{tv: tv})
+	}
+	return v
+}
+
+// children returns the elements of a composite value.
+func (s *DAPSession) children(m *Machine, tv TypedValue) (vars []dapVariable) {
+	switch bt := baseOf(tv.T).(type) {
+	case *PointerType:
+		ev := tv.V.(PointerValue).Deref()
+		fillValueTV(m.Store, &ev)
+		if dapHasChildren(ev) {
+			return s.children(m, ev)
+		}
+		return []dapVariable{s.variable(m, "*", ev)}
+	case *StructType:
+		sv := tv.V.(*StructValue)
+		for i, f := range bt.Fields {
+			vars = append(vars, s.variable(m, string(f.Name), sv.GetPointerToInt(m.Store, i).Deref()))
+		}
+	case *ArrayType:
+		av := tv.V.(*ArrayValue)
+		for i := 0; i < av.GetLength() && i < dapMaxChildren; i++ {
+			vars = append(vars, s.variable(m, fmt.Sprintf("[%d]", i), av.GetPointerAtIndexInt2(m.Store, i, bt.Elt).Deref()))
+		}
+	case *SliceType:
+		sv := tv.V.(*SliceValue)
+		for i := 0; i < sv.GetLength() && i < dapMaxChildren; i++ {
+			vars = append(vars, s.variable(m, fmt.Sprintf("[%d]", i), sv.GetPointerAtIndexInt2(m.Store, i, bt.Elt).Deref()))
+		}
+	case *MapType:
+		mv := tv.V.(*MapValue)
+		for item := mv.List.Head; item != nil && len(vars) < dapMaxChildren; item = item.Next {
+			key := item.Key
+			fillValueTV(m.Store, &key)
+			vars = append(vars, s.variable(m, "["+dapValueString(key)+"]", item.Value))
+		}
+	}
+	return vars
+}
+
+// dapHasChildren returns true if tv is a non nil composite value.
+func dapHasChildren(tv TypedValue) bool {
+	if tv.T == nil || tv.V == nil {
+		return false
+	}
+	switch baseOf(tv.T).(type) {
+	case *PointerType, *StructType, *ArrayType, *SliceType, *MapType:
+		return true
+	}
+	return false
+}
+
+// dapValueString returns the representation of a value for the client.
+// Composite values are summarized, their elements are sent on demand.
+func dapValueString(tv TypedValue) string {
+	if tv.T == nil {
+		return "nil"
+	}
+	switch tv.T.Kind() {
+	case StringKind:
+		return strconv.Quote(tv.GetString())
+	case PointerKind, SliceKind, MapKind, FuncKind, InterfaceKind:
+		if tv.V == nil {
+			return "nil"
+		}
+	}
+	switch v := tv.V.(type) {
+	case PointerValue:
+		if v.TV != nil && v.TV.T != nil && v.TV.T.Kind() == StructKind {
+			return "&" + dapValueString(*v.TV)
+		}
+	case *StructValue:
+		return tv.T.String() + "{...}"
+	case *ArrayValue:
+		return fmt.Sprintf("%s len: %d", tv.T, v.GetLength())
+	case *SliceValue:
+		return fmt.Sprintf("%s len: %d", tv.T, v.GetLength())
+	case *MapValue:
+		return fmt.Sprintf("%s len: %d", tv.T, v.GetLength())
+	}
+	return tv.ProtectedSprint(newSeenValues(), false)
+}
@@ -0,0 +1,291 @@
+package gnolang_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evalDAPTest runs the main function of file, debugged by the DAP client
+// connected to conn, and returns the program output.
+func evalDAPTest(conn io.ReadWriteCloser, file string) string {
+	bout := bytes.NewBufferString("")
+	stdout := writeNopCloser{bout}
+	testStore := tests.TestStore(gnoenv.RootDir(), "../../tests/files", nil, stdout, stdout, tests.ImportModeStdlibsPreferred)
+	f := gnolang.MustReadFile(file)
+	m := gnolang.NewMachineWithOptions(gnolang.MachineOptions{
+		PkgPath: string(f.PkgName),
+		Output:  stdout,
+		Store:   testStore,
+		Context: tests.TestContext(string(f.PkgName), nil),
+	})
+	defer m.Release()
+
+	sess := gnolang.NewDAPSession(conn)
+	defer sess.Close()
+	m.Debugger.EnableDAP(sess)
+	m.RunFiles(f)
+	ex, _ := gnolang.ParseExpr("main()")
+	m.Eval(ex)
+	return bout.String()
+}
+
+type dapMessage struct {
+	Type    string          `json:"type"`
+	Command string          `json:"command"`
+	Event   string          `json:"event"`
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Body    json.RawMessage `json:"body"`
+}
+
+type dapClient struct {
+	t      *testing.T
+	conn   net.Conn
+	r      *bufio.Reader
+	seq    int
+	events []dapMessage
+}
+
+func (c *dapClient) read() dapMessage {
+	c.t.Helper()
+	header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	require.NoError(c.t, err)
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	require.NoError(c.t, err)
+	body := make([]byte, length)
+	_, err = io.ReadFull(c.r, body)
+	require.NoError(c.t, err)
+	var msg dapMessage
+	require.NoError(c.t, json.Unmarshal(body, &msg))
+	return msg
+}
+
+// request sends a request and returns its response. The received events are
+// queued.
+func (c *dapClient) request(command string, args any) dapMessage {
+	c.t.Helper()
+	c.seq++
+	body, err := json.Marshal(map[string]any{"seq": c.seq, "type": "request", "command": command, "arguments": args})
+	require.NoError(c.t, err)
+	_, err = fmt.Fprintf(c.conn, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	require.NoError(c.t, err)
+	for {
+		msg := c.read()
+		if msg.Type == "event" {
+			c.events = append(c.events, msg)
+			continue
+		}
+		require.Equal(c.t, command, msg.Command)
+		return msg
+	}
+}
+
+// event returns the next event with the given name.
+func (c *dapClient) event(name string) dapMessage {
+	c.t.Helper()
+	for {
+		var msg dapMessage
+		if len(c.events) > 0 {
+			msg, c.events = c.events[0], c.events[1:]
+		} else {
+			msg = c.read()
+		}
+		if msg.Type == "event" && msg.Event == name {
+			return msg
+		}
+	}
+}
+
+// body sends a request which must succeed and decodes its response body into v.
+func (c *dapClient) body(command string, args, v any) {
+	c.t.Helper()
+	msg := c.request(command, args)
+	require.True(c.t, msg.Success, msg.Message)
+	if v != nil {
+		require.NoError(c.t, json.Unmarshal(msg.Body, v))
+	}
+}
+
+type dapTestVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+type dapTestFrame struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Line   int    `json:"line"`
+	Source struct {
+		Path string `json:"path"`
+	} `json:"source"`
+}
+
+func (c *dapClient) stopped(reason string) {
+	c.t.Helper()
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	require.NoError(c.t, json.Unmarshal(c.event("stopped").Body, &body))
+	assert.Equal(c.t, reason, body.Reason)
+}
+
+func (c *dapClient) stack() []dapTestFrame {
+	c.t.Helper()
+	var body struct {
+		StackFrames []dapTestFrame `json:"stackFrames"`
+	}
+	c.body("stackTrace", map[string]any{"threadId": 1}, &body)
+	return body.StackFrames
+}
+
+func (c *dapClient) variables(ref int) map[string]dapTestVariable {
+	c.t.Helper()
+	var body struct {
+		Variables []dapTestVariable `json:"variables"`
+	}
+	c.body("variables", map[string]any{"variablesReference": ref}, &body)
+	vars := map[string]dapTestVariable{}
+	for _, v := range body.Variables {
+		vars[v.Name] = v
+	}
+	return vars
+}
+
+func (c *dapClient) locals(frameID int) map[string]dapTestVariable {
+	c.t.Helper()
+	return c.scope(frameID, "Locals")
+}
+
+func (c *dapClient) scope(frameID int, name string) map[string]dapTestVariable {
+	c.t.Helper()
+	var body struct {
+		Scopes []struct {
+			Name               string `json:"name"`
+			VariablesReference int    `json:"variablesReference"`
+		} `json:"scopes"`
+	}
+	c.body("scopes", map[string]any{"frameId": frameID}, &body)
+	for _, s := range body.Scopes {
+		if s.Name == name {
+			return c.variables(s.VariablesReference)
+		}
+	}
+	c.t.Fatalf("scope %s not found", name)
+	return nil
+}
+
+func (c *dapClient) evaluate(expr string, frameID int) string {
+	c.t.Helper()
+	var body struct {
+		Result string `json:"result"`
+	}
+	c.body("evaluate", map[string]any{"expression": expr, "frameId": frameID}, &body)
+	return body.Result
+}
+
+func TestDAPDebug(t *testing.T) {
+	server, client := net.Pipe()
+	done := make(chan string)
+	go func() { done <- evalDAPTest(server, debugTarget) }()
+	c := &dapClient{t: t, conn: client, r: bufio.NewReader(client)}
+	defer client.Close()
+
+	path, err := filepath.Abs(debugTarget)
+	require.NoError(t, err)
+
+	c.body("initialize", map[string]any{"adapterID": "gno"}, nil)
+	c.event("initialized")
+	c.body("launch", map[string]any{}, nil)
+	var bps struct {
+		Breakpoints []struct {
+			Verified bool `json:"verified"`
+		} `json:"breakpoints"`
+	}
+	c.body("setBreakpoints", map[string]any{
+		"source": map[string]any{"path": path},
+		"breakpoints": []map[string]any{
+			{"line": 7},
+			{"line": 43, "condition": "i == 3"},
+			{"line": 27, "condition": "i =="},
+		},
+	}, &bps)
+	require.Len(t, bps.Breakpoints, 3)
+	assert.True(t, bps.Breakpoints[0].Verified)
+	assert.True(t, bps.Breakpoints[1].Verified)
+	assert.False(t, bps.Breakpoints[2].Verified)
+	c.body("configurationDone", nil, nil)
+
+	// Breakpoint in f, called from g, called from main.
+	c.stopped("breakpoint")
+	frames := c.stack()
+	require.Len(t, frames, 3)
+	assert.Equal(t, "main.f", frames[0].Name)
+	assert.Equal(t, 7, frames[0].Line)
+	assert.Equal(t, path, frames[0].Source.Path)
+	assert.Equal(t, "main.g", frames[1].Name)
+	assert.Equal(t, 11, frames[1].Line)
+	assert.Equal(t, "main.main", frames[2].Name)
+	locals := c.locals(frames[0].ID)
+	assert.Equal(t, `"hello"`, locals["name"].Value)
+	assert.Equal(t, "3", locals["i"].Value)
+	assert.Equal(t, "3", c.locals(frames[2].ID)["b"].Value)
+	globals := c.scope(frames[0].ID, "Globals")
+	assert.Equal(t, `"test"`, globals["global"].Value)
+	assert.NotContains(t, globals, "main")
+	assert.Equal(t, "true", c.evaluate(`i == 3 && name == "hello"`, frames[0].ID))
+	assert.Equal(t, `"hello"`, c.evaluate("s", frames[1].ID))
+	msg := c.request("evaluate", map[string]any{"expression": "foo", "frameId": frames[0].ID})
+	assert.False(t, msg.Success)
+	assert.Contains(t, msg.Message, "could not find symbol value for foo")
+
+	// Step out of f, and of g which has nothing left to run.
+	c.body("stepOut", map[string]any{"threadId": 1}, nil)
+	c.stopped("step")
+	frames = c.stack()
+	require.Len(t, frames, 1)
+	assert.Equal(t, 39, frames[0].Line)
+
+	// Step over a line.
+	c.body("next", map[string]any{"threadId": 1}, nil)
+	c.stopped("step")
+	assert.Equal(t, 40, c.stack()[0].Line)
+
+	// Conditional breakpoint in the loop.
+	c.body("continue", map[string]any{"threadId": 1}, nil)
+	c.stopped("breakpoint")
+	frames = c.stack()
+	assert.Equal(t, 43, frames[0].Line)
+	locals = c.locals(frames[0].ID)
+	assert.Equal(t, "3", locals["i"].Value)
+	assert.Equal(t, "2", locals["x"].Value)
+
+	// Composite values are expanded.
+	tv := locals["t"]
+	require.NotZero(t, tv.VariablesReference)
+	av := c.variables(tv.VariablesReference)["A"]
+	require.NotZero(t, av.VariablesReference)
+	elems := c.variables(av.VariablesReference)
+	assert.Len(t, elems, 3)
+	assert.Equal(t, "2", elems["[1]"].Value)
+
+	// Detach and let the program terminate.
+	c.body("disconnect", map[string]any{"terminateDebuggee": false}, nil)
+	out := <-done
+	assert.Contains(t, out, "hello 3")
+	assert.Contains(t, out, "bye 4")
+}
@@ -160,6 +160,20 @@ func TestDebug(t *testing.T) {
 		{in: "b 27\nc\np b\n", out: `("!zero" string)`},
 		{in: "b 22\nc\np t.A[3]\n", out: "Command failed: slice index out of bounds: 3 (len=3)"},
 		{in: "b 43\nc\nc\nc\np i\ndetach\n", out: "(1 int)"},
+		{in: "b 7 if i == 3\nbp\n", out: "sample.gno:7:5 if i == 3"},
+		{in: "b 7 if i == 3\nc\n", out: "=>    7: 	println(name, i)"},
+		{in: "b 43 if i == 3\nc\np i\n", out: "(3 int)"},
+		{in: "b 7 if j\nc\n", out: `Breakpoint condition "j" failed: could not find symbol value for j`},
+		{in: "b 7 if (\n", out: "Command failed: 1:2: expected operand"},
+		{in: "b 37\nc\nn\n", out: "=>   39: 	t := T{A: []int{1, 2, 3} }"},
+		{in: "b 42\nc\nn\n", out: "=>   43: 		x = i"},
+		{in: "b 21\nc\nso\n", out: "=>   40: 	println(t.get(1))"},
+		{in: "p 1 < 2\n", out: "(true <untyped> bool)"},
+		{in: "p !true\n", out: "(false <untyped> bool)"},
+		{in: cont + "p i == 3 && name == \"hello\"\n", out: "(true <untyped> bool)"},
+		{in: cont + "p name != \"hello\" || i > 3\n", out: "(false <untyped> bool)"},
+		{in: cont2 + "p t == nil\n", out: "(false <untyped> bool)"},
+		{in: "p 1 + 2\n", out: "Command failed: expression not supported: operator +"},
 	})
 
 	runDebugTest(t, "../../tests/files/a1.gno", []dtest{
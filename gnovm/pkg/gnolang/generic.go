@@ -0,0 +1,980 @@
+package gnolang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ----------------------------------------
+// genericType
+
+// genericType is the static type of a generic function or generic type
+// declaration. It only exists during preprocessing: every use of a generic
+// name must be instantiated with type arguments, explicitly or through
+// inference, and each instance is declared separately from a copy of the
+// generic declaration where the type parameters are substituted with the
+// type arguments.
+type genericType struct {
+	Decl    Decl      // *FuncDecl or *TypeDecl with type parameters
+	File    *FileNode // file of Decl
+	PkgPath string
+
+	instances map[string]*genericInstance
+	pending   []*genericInstance // bodies not yet preprocessed
+	typeid    TypeID
+}
+
+// genericInstance is the result of instantiating a genericType.
+type genericInstance struct {
+	TypeArgs []Type
+	Type     *DeclaredType // if generic type
+	Func     *FuncValue    // if generic function
+
+	decls []instanceDecl // instantiated funcs and methods
+	done  bool           // whether fully declared
+}
+
+type instanceDecl struct {
+	file *FileNode
+	fd   *FuncDecl
+}
+
+func (gt *genericType) Kind() Kind {
+	return GenericKind
+}
+
+func (gt *genericType) TypeID() TypeID {
+	if gt.typeid.IsZero() {
+		gt.typeid = typeid("%s.%s[%s]", gt.PkgPath, gt.name(), gt.typeParams().String())
+	}
+	return gt.typeid
+}
+
+func (gt *genericType) String() string {
+	return fmt.Sprintf("%s.%s[%s]", gt.PkgPath, gt.name(), gt.typeParams().String())
+}
+
+func (gt *genericType) Elem() Type {
+	panic("genericType has no elem type")
+}
+
+func (gt *genericType) GetPkgPath() string {
+	return gt.PkgPath
+}
+
+func (gt *genericType) IsNamed() bool {
+	panic("genericType has no property called named")
+}
+
+func (gt *genericType) name() Name {
+	switch d := gt.Decl.(type) {
+	case *FuncDecl:
+		return d.Name
+	case *TypeDecl:
+		return d.Name
+	default:
+		panic("should not happen")
+	}
+}
+
+func (gt *genericType) typeParams() FieldTypeExprs {
+	switch d := gt.Decl.(type) {
+	case *FuncDecl:
+		return d.TypeParams
+	case *TypeDecl:
+		return d.TypeParams
+	default:
+		panic("should not happen")
+	}
+}
+
+func (gt *genericType) typeParamNames() []Name {
+	tparams := gt.typeParams()
+	names := make([]Name, len(tparams))
+	for i, tp := range tparams {
+		names[i] = tp.Name
+	}
+	return names
+}
+
+// Returns the key of the type arguments, as used for naming instances.
+func typeArgsKey(targs []Type) string {
+	ids := make([]string, len(targs))
+	for i, t := range targs {
+		ids[i] = string(t.TypeID())
+	}
+	return strings.Join(ids, ",")
+}
+
+// Instances are located in a pseudo-file named after the generic
+// declaration's file, so that their block nodes do not collide with
+// those of the declaration nor of other instances.
+func instanceFileName(fn *FileNode, key string) string {
+	return string(fn.Name) + "[" + key + "]"
+}
+
+// Returns the receiver base type name and the receiver type parameter
+// names of a method declared on a generic type, e.g. "List" and ["T"]
+// for `func (l *List[T]) Push(v T)`.
+func genericReceiver(fd *FuncDecl) (base Name, tparams []Name, ok bool) {
+	if !fd.IsMethod || fd.Recv.Type == nil {
+		return
+	}
+	rx := fd.Recv.Type
+	if sx, ok := rx.(*StarExpr); ok {
+		rx = sx.X
+	}
+	var gx Expr
+	var ixs Exprs
+	switch cx := rx.(type) {
+	case *IndexExpr:
+		gx, ixs = cx.X, Exprs{cx.Index}
+	case *IndexListExpr:
+		gx, ixs = cx.X, cx.Indices
+	default:
+		return
+	}
+	nx, isName := gx.(*NameExpr)
+	if !isName {
+		return
+	}
+	tparams = make([]Name, len(ixs))
+	for i, ix := range ixs {
+		inx, isName := ix.(*NameExpr)
+		if !isName {
+			return "", nil, false
+		}
+		tparams[i] = inx.Name
+	}
+	return nx.Name, tparams, true
+}
+
+// Returns true if d is a generic declaration, or a method declared on a
+// generic type. These are never preprocessed nor run themselves; only
+// their instances are.
+func isGenericTemplate(d Decl) bool {
+	switch d := d.(type) {
+	case *FuncDecl:
+		if len(d.TypeParams) > 0 {
+			return true
+		}
+		_, _, ok := genericReceiver(d)
+		return ok
+	case *TypeDecl:
+		return len(d.TypeParams) > 0
+	default:
+		return false
+	}
+}
+
+// ----------------------------------------
+// Substitution
+
+// Returns a copy of n where the names of the type parameters tparams
+// are substituted with the corresponding type arguments targs.
+// Node positions are preserved, as block node locations depend on them.
+func copyWithTypeArgs(n Node, tparams []Name, targs []Type) Node {
+	subst := make(map[Name]Type, len(tparams))
+	for i, tp := range tparams {
+		subst[tp] = targs[i]
+	}
+	cp := n.Copy()
+	copyPositions(n, cp)
+	return substituteTypeParams(cp, subst)
+}
+
+func copyPositions(src, dst Node) {
+	var srcs []Node
+	Transcribe(src, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			srcs = append(srcs, n)
+		}
+		return n, TRANS_CONTINUE
+	})
+	i := 0
+	Transcribe(dst, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER && i < len(srcs) {
+			n.SetLine(srcs[i].GetLine())
+			n.SetColumn(srcs[i].GetColumn())
+			n.SetLabel(srcs[i].GetLabel())
+			i++
+		}
+		return n, TRANS_CONTINUE
+	})
+	// type set terms are not transcribed.
+	if sit, ok := src.(*InterfaceTypeExpr); ok {
+		dit := dst.(*InterfaceTypeExpr)
+		for i := range sit.TypeSet {
+			copyPositions(sit.TypeSet[i], dit.TypeSet[i])
+		}
+	}
+}
+
+func substituteTypeParams(n Node, subst map[Name]Type) Node {
+	return Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		switch cn := n.(type) {
+		case *InterfaceTypeExpr:
+			for i := range cn.TypeSet {
+				cn.TypeSet[i] = substituteTypeParams(cn.TypeSet[i], subst).(Expr)
+			}
+			return n, TRANS_CONTINUE
+		case *NameExpr:
+			// skip names that are not type references.
+			switch ftype {
+			case TRANS_COMPOSITE_KEY:
+				return n, TRANS_CONTINUE
+			case TRANS_ASSIGN_LHS:
+				if ns[len(ns)-1].(*AssignStmt).Op == DEFINE {
+					return n, TRANS_CONTINUE
+				}
+			case TRANS_RANGE_KEY, TRANS_RANGE_VALUE:
+				if ns[len(ns)-1].(*RangeStmt).Op == DEFINE {
+					return n, TRANS_CONTINUE
+				}
+			}
+			t, ok := subst[cn.Name]
+			if !ok {
+				return n, TRANS_CONTINUE
+			}
+			ctx := constType(cn, t)
+			ctx.SetLine(cn.GetLine())
+			ctx.SetColumn(cn.GetColumn())
+			return ctx, TRANS_SKIP
+		default:
+			return n, TRANS_CONTINUE
+		}
+	})
+}
+
+// ----------------------------------------
+// Instantiation
+
+// Returns the instance of gt for the type arguments targs, declaring it
+// first if needed.
+func (gt *genericType) instantiate(store Store, targs []Type) *genericInstance {
+	tparams := gt.typeParams()
+	if len(targs) != len(tparams) {
+		panic(fmt.Sprintf(
+			"got %d type arguments but %s has %d type parameters",
+			len(targs), gt.name(), len(tparams)))
+	}
+	key := typeArgsKey(targs)
+	if inst, ok := gt.instances[key]; ok {
+		gt.ensureSaved(store, inst)
+		return inst
+	}
+	gt.assertSatisfied(store, targs)
+	if gt.instances == nil {
+		gt.instances = make(map[string]*genericInstance)
+	}
+	// register first, to support recursive references.
+	inst := &genericInstance{TypeArgs: targs}
+	gt.instances[key] = inst
+	defer func() {
+		if r := recover(); r != nil {
+			delete(gt.instances, key)
+			panic(r)
+		}
+	}()
+	switch gt.Decl.(type) {
+	case *FuncDecl:
+		gt.instantiateFunc(store, inst, key)
+	case *TypeDecl:
+		gt.instantiateType(store, inst, key)
+	default:
+		panic("should not happen")
+	}
+	inst.done = true
+	// bodies may refer to any other declaration of the package,
+	// so they are preprocessed once all are declared.
+	if isPackageDeclared(packageOf(gt.File)) {
+		gt.preprocessInstance(store, inst)
+	} else {
+		gt.pending = append(gt.pending, inst)
+	}
+	return inst
+}
+
+func (gt *genericType) instantiateFunc(store Store, inst *genericInstance, key string) {
+	pn := packageOf(gt.File)
+	tmpl := gt.Decl.(*FuncDecl)
+	if tmpl.Body == nil {
+		panic(fmt.Sprintf("generic function %s must have a body", tmpl.Name))
+	}
+	fd := copyWithTypeArgs(tmpl, gt.typeParamNames(), inst.TypeArgs).(*FuncDecl)
+	fd.TypeParams = nil
+	fd.Name = Name(fmt.Sprintf("%s[%s]", tmpl.Name, key))
+	fd.SetAttribute(ATTR_GENERIC_INSTANCE, true)
+	SetNodeLocations(pn.PkgPath, instanceFileName(gt.File, key), fd)
+	initStaticBlocks(store, gt.File, fd)
+	predefineTypeDeps(store, gt.File, &fd.Type)
+	fd.Type = *Preprocess(store, gt.File, &fd.Type).(*FuncTypeExpr)
+	ft := evalStaticType(store, gt.File, &fd.Type).(*FuncType)
+	fd.SetAttribute(ATTR_PREDEFINED, true)
+	inst.Func = &FuncValue{
+		Type:     ft,
+		IsMethod: false,
+		Source:   fd,
+		Name:     fd.Name,
+		Closure:  nil, // set lazily.
+		FileName: gt.File.Name,
+		PkgPath:  pn.PkgPath,
+		body:     nil, // set lazily from source.
+	}
+	inst.decls = append(inst.decls, instanceDecl{file: gt.File, fd: fd})
+	saveInstanceNodes(store, fd)
+}
+
+func (gt *genericType) instantiateType(store Store, inst *genericInstance, key string) {
+	pn := packageOf(gt.File)
+	td := gt.Decl.(*TypeDecl)
+	name := Name(fmt.Sprintf("%s[%s]", td.Name, key))
+	// if store has this type, use that.
+	var dt *DeclaredType
+	loaded := false
+	if store != nil {
+		if st := store.GetTypeSafe(DeclaredTypeID(pn.PkgPath, name)); st != nil {
+			dt = st.(*DeclaredType)
+			loaded = true
+		}
+	}
+	if !loaded {
+		// filled in below; recursive references
+		// refer to this type in the meantime.
+		dt = &DeclaredType{PkgPath: pn.PkgPath, Name: name}
+	}
+	inst.Type = dt
+	tx := copyWithTypeArgs(td.Type, gt.typeParamNames(), inst.TypeArgs).(Expr)
+	predefineTypeDeps(store, gt.File, tx)
+	tx = Preprocess(store, gt.File, tx).(Expr)
+	t := evalStaticType(store, gt.File, tx)
+	if !loaded {
+		*dt = *declareWith(pn.PkgPath, name, t)
+	}
+	// declare the methods of the instance.
+	if pn.FileSet != nil {
+		for _, fn := range pn.FileSet.Files {
+			for _, d := range fn.Decls {
+				md, ok := d.(*FuncDecl)
+				if !ok {
+					continue
+				}
+				base, rtparams, ok := genericReceiver(md)
+				if !ok || base != td.Name {
+					continue
+				}
+				if len(rtparams) != len(inst.TypeArgs) {
+					panic(fmt.Sprintf(
+						"got %d type parameters in receiver of method %s but %s has %d type parameters",
+						len(rtparams), md.Name, td.Name, len(inst.TypeArgs)))
+				}
+				imd := copyWithTypeArgs(md, rtparams, inst.TypeArgs).(*FuncDecl)
+				imd.SetAttribute(ATTR_GENERIC_INSTANCE, true)
+				SetNodeLocations(pn.PkgPath, instanceFileName(fn, key), imd)
+				initStaticBlocks(store, fn, imd)
+				predefineTypeDeps(store, fn, &imd.Type)
+				predefineNow(store, fn, imd)
+				inst.decls = append(inst.decls, instanceDecl{file: fn, fd: imd})
+				saveInstanceNodes(store, imd)
+			}
+		}
+	}
+	if !loaded {
+		dt.Seal()
+		if store != nil {
+			store.SetType(dt)
+		}
+	}
+}
+
+// Preprocesses the bodies of the functions and methods of inst.
+func (gt *genericType) preprocessInstance(store Store, inst *genericInstance) {
+	for _, id := range inst.decls {
+		Preprocess(store, id.file, id.fd)
+		if inst.Type != nil {
+			// The body may have been altered during preprocessing.
+			for _, mv := range inst.Type.Methods {
+				if fv, ok := mv.V.(*FuncValue); ok && fv.Source == id.fd {
+					fv.UpdateBodyFromSource()
+				}
+			}
+		}
+		saveInstanceNodes(store, id.fd)
+	}
+}
+
+// An instance may outlive the transaction which declared it, as it is
+// cached on the package node; make sure the store still has it.
+func (gt *genericType) ensureSaved(store Store, inst *genericInstance) {
+	if store == nil || !inst.done {
+		return
+	}
+	if inst.Type != nil && store.GetTypeSafe(inst.Type.TypeID()) == nil {
+		store.SetType(inst.Type)
+	}
+	for _, id := range inst.decls {
+		if store.GetBlockNodeSafe(id.fd.GetLocation()) == nil {
+			saveInstanceNodes(store, id.fd)
+		}
+	}
+}
+
+// Saves the block nodes of an instantiated declaration, which are not
+// reachable from any file node.
+func saveInstanceNodes(store Store, fd *FuncDecl) {
+	if store == nil {
+		return
+	}
+	Transcribe(fd, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if bn, ok := n.(BlockNode); ok && bn.IsInitialized() {
+			store.SetBlockNode(bn)
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// Returns true if all file-level declarations of pn have been declared,
+// i.e. their types and function signatures are known.
+func isPackageDeclared(pn *PackageNode) bool {
+	if pn.FileSet == nil {
+		return true
+	}
+	for _, fn := range pn.FileSet.Files {
+		for _, d := range fn.Decls {
+			switch d := d.(type) {
+			case *ImportDecl:
+				if d.GetAttribute(ATTR_PREDEFINED) != true {
+					return false
+				}
+			case *FuncDecl:
+				if d.GetAttribute(ATTR_PREPROCESSED) == true {
+					continue
+				}
+				if d.Type.GetAttribute(ATTR_TYPE_VALUE) == nil {
+					return false
+				}
+			default:
+				if d.GetAttribute(ATTR_PREPROCESSED) != true {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Preprocesses the bodies of the instances of the generics of pn which
+// were instantiated while pn was being predefined.
+func preprocessPendingInstances(store Store, pn *PackageNode) {
+	for {
+		found := false
+		for _, t := range pn.GetStaticBlock().Types {
+			gt, ok := t.(*genericType)
+			if !ok || len(gt.pending) == 0 {
+				continue
+			}
+			found = true
+			pending := gt.pending
+			gt.pending = nil
+			for _, inst := range pending {
+				gt.preprocessInstance(store, inst)
+			}
+		}
+		if !found {
+			return
+		}
+	}
+}
+
+// Predefines the package-level declarations x depends on which are not
+// yet defined.
+func predefineTypeDeps(store Store, last BlockNode, x Expr) {
+	pn := packageOf(last)
+	for {
+		un := findUndefined(store, last, x)
+		if un == "" {
+			return
+		}
+		if pn.FileSet == nil {
+			panic(fmt.Sprintf("name %s not declared", un))
+		}
+		file, decl := pn.FileSet.GetDeclFor(un)
+		*decl, _ = predefineNow(store, file, *decl)
+	}
+}
+
+// Instantiates gt with the type arguments txs of the index expression n,
+// and returns the expression replacing n.
+func instantiateExpr(store Store, last BlockNode, n Expr, gt *genericType, txs Exprs) Expr {
+	targs := make([]Type, len(txs))
+	for i, tx := range txs {
+		targs[i] = evalStaticType(store, last, tx)
+	}
+	inst := gt.instantiate(store, targs)
+	if inst.Type != nil {
+		n.SetAttribute(ATTR_TYPE_VALUE, inst.Type)
+		return constType(n, inst.Type)
+	}
+	return instanceFuncExpr(n, inst)
+}
+
+func instanceFuncExpr(source Expr, inst *genericInstance) *ConstExpr {
+	cx := &ConstExpr{Source: source}
+	cx.T = inst.Func.Type
+	cx.V = inst.Func
+	cx.SetAttribute(ATTR_PREPROCESSED, true)
+	setConstAttrs(cx)
+	return cx
+}
+
+// ----------------------------------------
+// Constraints
+
+// Panics if any of the type arguments does not satisfy the constraint of
+// its type parameter.
+func (gt *genericType) assertSatisfied(store Store, targs []Type) {
+	tparams := gt.typeParams()
+	names := gt.typeParamNames()
+	for i, tp := range tparams {
+		cx := copyWithTypeArgs(tp.Type, names, targs).(Expr)
+		if !satisfies(store, gt.File, targs[i], cx) {
+			panic(fmt.Sprintf(
+				"%s does not satisfy %s",
+				targs[i].String(), constraintString(tp.Type)))
+		}
+	}
+}
+
+// Returns the constraint as written, without the value paths that
+// NameExpr.String() would otherwise include.
+func constraintString(cx Expr) string {
+	if nx, ok := cx.(*NameExpr); ok {
+		return string(nx.Name)
+	}
+	return cx.String()
+}
+
+// Returns true if t satisfies the constraint cx.
+func satisfies(store Store, last BlockNode, t Type, cx Expr) bool {
+	switch cx := cx.(type) {
+	case *NameExpr:
+		switch cx.Name {
+		case "any":
+			return true
+		case "comparable":
+			return isComparable(t)
+		}
+	case *BinaryExpr:
+		if cx.Op == BOR {
+			return satisfies(store, last, t, cx.Left) ||
+				satisfies(store, last, t, cx.Right)
+		}
+	case *UnaryExpr:
+		if cx.Op == TILDE {
+			ut := evalConstraintType(store, last, cx.X)
+			return baseOf(t).TypeID() == baseOf(ut).TypeID()
+		}
+	case *InterfaceTypeExpr:
+		for _, term := range cx.TypeSet {
+			if !satisfies(store, last, t, term) {
+				return false
+			}
+		}
+	}
+	ct := evalConstraintType(store, last, cx)
+	return satisfiesType(store, t, ct)
+}
+
+func satisfiesType(store Store, t Type, ct Type) bool {
+	if ct.TypeID() == gComparableType.TypeID() {
+		return isComparable(t)
+	}
+	it, ok := baseOf(ct).(*InterfaceType)
+	if !ok {
+		return t.TypeID() == ct.TypeID()
+	}
+	// embedded constraints.
+	for _, im := range it.Methods {
+		if im.Type.Kind() == InterfaceKind {
+			if !satisfiesType(store, t, im.Type) {
+				return false
+			}
+		}
+	}
+	if err := it.VerifyImplementedBy(t); err != nil {
+		return false
+	}
+	if dt, ok := ct.(*DeclaredType); ok {
+		terms, last := declaredTypeSet(store, dt)
+		for _, term := range terms {
+			if !satisfies(store, last, t, term) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Returns the type set terms of the declared constraint interface dt,
+// along with the block node to evaluate them in.
+func declaredTypeSet(store Store, dt *DeclaredType) (Exprs, BlockNode) {
+	if dt.PkgPath == uversePkgPath || store == nil {
+		return nil, nil
+	}
+	pn, ok := store.GetBlockNodeSafe(PackageNodeLocation(dt.PkgPath)).(*PackageNode)
+	if !ok || pn.FileSet == nil {
+		return nil, nil
+	}
+	name := dt.Name
+	if i := strings.IndexByte(string(name), '['); i >= 0 {
+		name = name[:i]
+	}
+	fn, decl, ok := pn.FileSet.GetDeclForSafe(name)
+	if !ok {
+		return nil, nil
+	}
+	td, ok := (*decl).(*TypeDecl)
+	if !ok {
+		return nil, nil
+	}
+	tx := td.Type
+	if ctx, ok := tx.(*constTypeExpr); ok {
+		tx = ctx.Source
+	}
+	itx, ok := tx.(*InterfaceTypeExpr)
+	if !ok || len(itx.TypeSet) == 0 {
+		return nil, nil
+	}
+	if len(td.TypeParams) == 0 {
+		return itx.TypeSet, fn
+	}
+	// instance of a generic constraint.
+	gt, ok := pn.GetStaticTypeOf(store, name).(*genericType)
+	if !ok {
+		return nil, nil
+	}
+	for _, inst := range gt.instances {
+		if inst.Type == dt {
+			terms := make(Exprs, len(itx.TypeSet))
+			for i, term := range itx.TypeSet {
+				terms[i] = copyWithTypeArgs(term, gt.typeParamNames(), inst.TypeArgs).(Expr)
+			}
+			return terms, fn
+		}
+	}
+	return nil, nil
+}
+
+func evalConstraintType(store Store, last BlockNode, x Expr) Type {
+	if t, ok := x.GetAttribute(ATTR_TYPE_VALUE).(Type); ok {
+		return t
+	}
+	x = Preprocess(store, last, x).(Expr)
+	return evalStaticType(store, last, x)
+}
+
+func isComparable(t Type) (ok bool) {
+	switch baseOf(t).(type) {
+	case *SliceType, *FuncType, *MapType:
+		return false
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	assertComparable2(t)
+	return true
+}
+
+// ----------------------------------------
+// Inference
+
+// Infers the type arguments of the generic function gt from the
+// arguments of the call n, and returns the expression for the
+// corresponding instance.
+func inferGenericCall(store Store, last BlockNode, n *CallExpr, gt *genericType) Expr {
+	fd, ok := gt.Decl.(*FuncDecl)
+	if !ok {
+		panic(fmt.Sprintf(
+			"cannot use generic type %s without instantiation",
+			gt.name()))
+	}
+	tparams := gt.typeParamNames()
+	bound := make(map[Name]Type, len(tparams))
+	isParam := make(map[Name]bool, len(tparams))
+	for _, tp := range tparams {
+		isParam[tp] = true
+	}
+
+	// static types of the arguments.
+	var ats []Type
+	if len(n.Args) == 1 {
+		if tt, ok := evalStaticTypeOfRaw(store, last, n.Args[0]).(*tupleType); ok {
+			ats = tt.Elts
+		}
+	}
+	if ats == nil {
+		ats = make([]Type, len(n.Args))
+		for i, arg := range n.Args {
+			ats[i] = evalStaticTypeOf(store, last, arg)
+		}
+	}
+	// parameter type expression for each argument.
+	params := fd.Type.Params
+	pxs := make([]Expr, 0, len(ats))
+	for i := range ats {
+		switch {
+		case i < len(params)-1:
+			pxs = append(pxs, params[i].Type)
+		case len(params) == 0:
+			// too many arguments; checked later.
+		default:
+			px := params[len(params)-1].Type
+			if stx, ok := px.(*SliceTypeExpr); ok && stx.Vrd && !n.Varg {
+				px = stx.Elt
+			} else if i >= len(params) {
+				continue
+			}
+			pxs = append(pxs, px)
+		}
+	}
+
+	var unify func(x Expr, t Type)
+	unify = func(x Expr, t Type) {
+		if t == nil {
+			return
+		}
+		switch cx := x.(type) {
+		case *NameExpr:
+			if isParam[cx.Name] {
+				if _, ok := bound[cx.Name]; !ok {
+					bound[cx.Name] = t
+				}
+			}
+		case *StarExpr:
+			if pt, ok := baseOf(t).(*PointerType); ok {
+				unify(cx.X, pt.Elt)
+			}
+		case *SliceTypeExpr:
+			if st, ok := baseOf(t).(*SliceType); ok {
+				unify(cx.Elt, st.Elt)
+			}
+		case *ArrayTypeExpr:
+			if at, ok := baseOf(t).(*ArrayType); ok {
+				unify(cx.Elt, at.Elt)
+			}
+		case *MapTypeExpr:
+			if mt, ok := baseOf(t).(*MapType); ok {
+				unify(cx.Key, mt.Key)
+				unify(cx.Value, mt.Value)
+			}
+		case *ChanTypeExpr:
+			if ct, ok := baseOf(t).(*ChanType); ok {
+				unify(cx.Value, ct.Elt)
+			}
+		case *FuncTypeExpr:
+			if ft, ok := baseOf(t).(*FuncType); ok &&
+				len(ft.Params) == len(cx.Params) &&
+				len(ft.Results) == len(cx.Results) {
+				for i := range cx.Params {
+					unify(cx.Params[i].Type, ft.Params[i].Type)
+				}
+				for i := range cx.Results {
+					unify(cx.Results[i].Type, ft.Results[i].Type)
+				}
+			}
+		case *IndexExpr:
+			unifyInstance(store, gt.File, cx.X, Exprs{cx.Index}, t, unify)
+		case *IndexListExpr:
+			unifyInstance(store, gt.File, cx.X, cx.Indices, t, unify)
+		}
+	}
+
+	// typed arguments first, then untyped constants.
+	for i, px := range pxs {
+		if ats[i] != nil && !isUntyped(ats[i]) {
+			unify(px, ats[i])
+		}
+	}
+	for i, px := range pxs {
+		if ats[i] != nil && isUntyped(ats[i]) {
+			if nx, ok := px.(*NameExpr); ok && isParam[nx.Name] {
+				if _, ok := bound[nx.Name]; !ok {
+					bound[nx.Name] = defaultTypeOf(ats[i])
+				}
+			}
+		}
+	}
+	// infer from core type constraints, e.g. [S ~[]E, E any].
+	for progress := true; progress; {
+		progress = false
+		for _, tp := range fd.TypeParams {
+			core := coreTerm(tp.Type)
+			if core == nil {
+				continue
+			}
+			nb := len(bound)
+			if t, ok := bound[tp.Name]; ok {
+				unify(core, t)
+			} else if allBound(core, isParam, bound) {
+				names, targs := boundArgs(bound)
+				cx := copyWithTypeArgs(core, names, targs).(Expr)
+				bound[tp.Name] = evalConstraintType(store, gt.File, cx)
+			}
+			if len(bound) > nb {
+				progress = true
+			}
+		}
+	}
+
+	targs := make([]Type, len(tparams))
+	for i, tp := range tparams {
+		t, ok := bound[tp]
+		if !ok {
+			panic(fmt.Sprintf(
+				"in call to %s, cannot infer %s",
+				gt.name(), tp))
+		}
+		targs[i] = t
+	}
+	inst := gt.instantiate(store, targs)
+	return instanceFuncExpr(n.Func, inst)
+}
+
+// Unifies the type arguments of the instance t of the generic type x
+// with the expressions ixs.
+func unifyInstance(store Store, last BlockNode, x Expr, ixs Exprs, t Type, unify func(Expr, Type)) {
+	gt := lookupGeneric(store, last, x)
+	if gt == nil {
+		return
+	}
+	for _, inst := range gt.instances {
+		if inst.Type != nil && inst.Type.TypeID() == t.TypeID() {
+			for i := range ixs {
+				if i < len(inst.TypeArgs) {
+					unify(ixs[i], inst.TypeArgs[i])
+				}
+			}
+			return
+		}
+	}
+}
+
+// Returns the generic named by x in last, or nil.
+func lookupGeneric(store Store, last BlockNode, x Expr) *genericType {
+	switch cx := x.(type) {
+	case *NameExpr:
+		if tv := last.GetValueRef(store, cx.Name, true); tv == nil {
+			return nil
+		}
+		gt, _ := last.GetStaticTypeOf(store, cx.Name).(*genericType)
+		return gt
+	case *SelectorExpr:
+		px, ok := cx.X.(*NameExpr)
+		if !ok {
+			return nil
+		}
+		tv := last.GetValueRef(store, px.Name, true)
+		if tv == nil {
+			return nil
+		}
+		pv, ok := tv.V.(*PackageValue)
+		if !ok {
+			return nil
+		}
+		pn := pv.GetPackageNode(store)
+		if _, ok := pn.GetLocalIndex(cx.Sel); !ok {
+			return nil
+		}
+		gt, _ := pn.GetStaticTypeOf(store, cx.Sel).(*genericType)
+		return gt
+	default:
+		return nil
+	}
+}
+
+// Returns the core type term of a constraint, if it has a single one.
+func coreTerm(cx Expr) Expr {
+	switch cx := cx.(type) {
+	case *UnaryExpr:
+		if cx.Op == TILDE {
+			return cx.X
+		}
+	case *InterfaceTypeExpr:
+		if len(cx.Methods) == 0 && len(cx.TypeSet) == 1 {
+			return coreTerm(cx.TypeSet[0])
+		}
+	case *SliceTypeExpr, *ArrayTypeExpr, *MapTypeExpr, *ChanTypeExpr,
+		*FuncTypeExpr, *StarExpr, *StructTypeExpr:
+		return cx
+	}
+	return nil
+}
+
+// Returns true if all the type parameters referenced in x are bound.
+func allBound(x Expr, isParam map[Name]bool, bound map[Name]Type) bool {
+	ok := true
+	Transcribe(x, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if nx, isName := n.(*NameExpr); isName && stage == TRANS_ENTER && isParam[nx.Name] {
+			if _, isBound := bound[nx.Name]; !isBound {
+				ok = false
+				return n, TRANS_EXIT
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+	return ok
+}
+
+func boundArgs(bound map[Name]Type) ([]Name, []Type) {
+	names := make([]Name, 0, len(bound))
+	targs := make([]Type, 0, len(bound))
+	for name, t := range bound {
+		names = append(names, name)
+		targs = append(targs, t)
+	}
+	return names, targs
+}
+
+// Panics if a generic of type t is used without instantiation.
+func assertInstantiated(t Type, ftype TransField) {
+	gt, ok := t.(*genericType)
+	if !ok {
+		return
+	}
+	switch ftype {
+	case TRANS_INDEX_X, TRANS_INDEXLIST_X, TRANS_CALL_FUNC:
+		return
+	}
+	panic(fmt.Sprintf(
+		"cannot use generic %s without instantiation",
+		gt.name()))
+}
+
+// Defines the generic declaration d of the file last onto its package.
+func defineGeneric(last BlockNode, d Decl) {
+	gt := &genericType{Decl: d}
+	fn, ok := last.(*FileNode)
+	if !ok {
+		panic(fmt.Sprintf(
+			"cannot declare generic %s inside a function",
+			gt.name()))
+	}
+	if isUverseName(gt.name()) {
+		panic(fmt.Sprintf(
+			"builtin identifiers cannot be shadowed: %s", gt.name()))
+	}
+	pn := packageOf(fn)
+	gt.File = fn
+	gt.PkgPath = pn.PkgPath
+	pn.Define2(false, gt.name(), gt, TypedValue{})
+	d.SetAttribute(ATTR_PREDEFINED, true)
+	d.SetAttribute(ATTR_PREPROCESSED, true)
+}
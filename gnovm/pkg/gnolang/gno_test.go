@@ -81,6 +81,8 @@ func TestBuiltinIdentifiersShadowing(t *testing.T) {
 		"uint64",
 		"typeval",
 		"error",
+		"any",
+		"comparable",
 		"true",
 		"false",
 	}
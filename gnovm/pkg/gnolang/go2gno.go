@@ -185,6 +185,11 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			X:     toExpr(fs, gon.X),
 			Index: toExpr(fs, gon.Index),
 		}
+	case *ast.IndexListExpr:
+		return &IndexListExpr{
+			X:       toExpr(fs, gon.X),
+			Indices: toExprs(fs, gon.Indices),
+		}
 	case *ast.SelectorExpr:
 		return &SelectorExpr{
 			X:   toExpr(fs, gon.X),
@@ -277,8 +282,10 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			Vrd: true,
 		}
 	case *ast.InterfaceType:
+		methods, typeSet := toMethodsAndTypeSet(fs, gon.Methods)
 		return &InterfaceTypeExpr{
-			Methods: toFieldsFromList(fs, gon.Methods),
+			Methods: methods,
+			TypeSet: typeSet,
 		}
 	case *ast.ChanType:
 		var dir ChanDir
@@ -330,6 +337,20 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 		return &DeferStmt{
 			Call: *cx,
 		}
+	case *ast.GoStmt:
+		cx := toExpr(fs, gon.Call).(*CallExpr)
+		return &GoStmt{
+			Call: *cx,
+		}
+	case *ast.SendStmt:
+		return &SendStmt{
+			Chan:  toExpr(fs, gon.Chan),
+			Value: toExpr(fs, gon.Value),
+		}
+	case *ast.SelectStmt:
+		return &SelectStmt{
+			Cases: toSelectCases(fs, gon.Body.List),
+		}
 	case *ast.ExprStmt:
 		if cx, ok := gon.X.(*ast.CallExpr); ok {
 			if ix, ok := cx.Fun.(*ast.Ident); ok && ix.Name == "panic" {
@@ -448,11 +469,12 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			body = Go2Gno(fs, gon.Body).(*BlockStmt).Body
 		}
 		return &FuncDecl{
-			IsMethod: isMethod,
-			Recv:     recv,
-			NameExpr: NameExpr{Name: name},
-			Type:     *type_,
-			Body:     body,
+			IsMethod:   isMethod,
+			Recv:       recv,
+			NameExpr:   NameExpr{Name: name},
+			TypeParams: toFieldsFromList(fs, gon.Type.TypeParams),
+			Type:       *type_,
+			Body:       body,
 		}
 	case *ast.GenDecl:
 		panic("unexpected *ast.GenDecl; use toDecls(fs,) instead")
@@ -648,6 +670,7 @@ var token2word = map[token.Token]Word{
 	token.LEQ:            LEQ,
 	token.GEQ:            GEQ,
 	token.DEFINE:         DEFINE,
+	token.TILDE:          TILDE,
 	token.BREAK:          BREAK,
 	token.CASE:           CASE,
 	token.CHAN:           CHAN,
@@ -754,10 +777,14 @@ func toDecls(fs *token.FileSet, gd *ast.GenDecl) (ds Decls) {
 			name := toName(s.Name)
 			tipe := toExpr(fs, s.Type)
 			alias := s.Assign != 0
+			if alias && s.TypeParams != nil {
+				panic("generic type cannot be alias")
+			}
 			ds = append(ds, &TypeDecl{
-				NameExpr: NameExpr{Name: name},
-				Type:     tipe,
-				IsAlias:  alias,
+				NameExpr:   NameExpr{Name: name},
+				TypeParams: toFieldsFromList(fs, s.TypeParams),
+				Type:       tipe,
+				IsAlias:    alias,
 			})
 		case *ast.ValueSpec:
 			if gd.Tok == token.CONST {
@@ -874,6 +901,34 @@ func toFields(fs *token.FileSet, fields ...*ast.Field) (ftxs []FieldTypeExpr) {
 	return
 }
 
+// toMethodsAndTypeSet splits the elements of an interface into its
+// methods and embedded interfaces, and the union and ~T terms of its
+// type set, which are only allowed in type constraints.
+func toMethodsAndTypeSet(fs *token.FileSet, fl *ast.FieldList) (methods []FieldTypeExpr, typeSet Exprs) {
+	if fl == nil {
+		return nil, nil
+	}
+	for _, f := range fl.List {
+		if len(f.Names) == 0 && isTypeSetTerm(f.Type) {
+			typeSet = append(typeSet, toExpr(fs, f.Type))
+		} else {
+			methods = append(methods, toFields(fs, f)...)
+		}
+	}
+	return
+}
+
+func isTypeSetTerm(x ast.Expr) bool {
+	switch x := x.(type) {
+	case *ast.BinaryExpr:
+		return x.Op == token.OR
+	case *ast.UnaryExpr:
+		return x.Op == token.TILDE
+	default:
+		return false
+	}
+}
+
 func toKeyValueExprs(fs *token.FileSet, elts []ast.Expr) (kvxs KeyValueExprs) {
 	kvxs = make([]KeyValueExpr, len(elts))
 	for i, x := range elts {
@@ -913,6 +968,19 @@ func toClauses(fs *token.FileSet, csz []ast.Stmt) []SwitchClauseStmt {
 	return res
 }
 
+func toSelectCases(fs *token.FileSet, csz []ast.Stmt) []SelectCaseStmt {
+	res := make([]SelectCaseStmt, len(csz))
+	for i, cs := range csz {
+		cc := cs.(*ast.CommClause)
+		res[i] = SelectCaseStmt{
+			Comm: toStmt(fs, cc.Comm),
+			Body: toStmts(fs, cc.Body),
+		}
+		setLoc(fs, cc.Pos(), &res[i])
+	}
+	return res
+}
+
 func toSwitchClauseStmt(fs *token.FileSet, cc *ast.CaseClause) SwitchClauseStmt {
 	return SwitchClauseStmt{
 		Cases: toExprs(fs, cc.List),
@@ -0,0 +1,182 @@
+package gnolang
+
+// Goroutines are scheduled cooperatively and deterministically.  The
+// running goroutine keeps the machine until it blocks on a channel
+// operation or exits, at which point the goroutine at the head of the run
+// queue is resumed.  A goroutine that becomes ready again is appended to
+// the tail of the run queue, so given the same program and inputs, every
+// execution interleaves goroutines in the same order.
+//
+// A goroutine is just a saved copy of the machine's execution state
+// (ops, values, exprs, stmts, blocks, frames...).  Switching goroutines
+// swaps that state in and out of the machine; everything else, such as
+// the allocator, the store and the gas meter, is shared.
+
+// number of ops and values initially allocated for a new goroutine.
+const goroutineStackSize = 64
+
+type goroutine struct {
+	ID int
+
+	// saved machine state, while not running.
+	ops             []Op
+	numOps          int
+	values          []TypedValue
+	numValues       int
+	exprs           []Expr
+	stmts           []Stmt
+	blocks          []*Block
+	frames          []*Frame
+	pkg             *PackageValue
+	realm           *Realm
+	exceptions      []Exception
+	numResults      int
+	panicScope      uint
+	deferPanicScope uint
+
+	// wait is set to the channel waiter that woke up the goroutine;
+	// it is consumed by the channel operation upon resuming.
+	wait *chanWaiter
+	// waiters registered while blocked.
+	waiters []*chanWaiter
+}
+
+type scheduler struct {
+	current *goroutine   // running goroutine
+	runq    []*goroutine // ready goroutines, in FIFO order
+	nextID  int
+}
+
+func (m *Machine) saveGoroutine(g *goroutine) {
+	g.ops = m.Ops
+	g.numOps = m.NumOps
+	g.values = m.Values
+	g.numValues = m.NumValues
+	g.exprs = m.Exprs
+	g.stmts = m.Stmts
+	g.blocks = m.Blocks
+	g.frames = m.Frames
+	g.pkg = m.Package
+	g.realm = m.Realm
+	g.exceptions = m.Exceptions
+	g.numResults = m.NumResults
+	g.panicScope = m.PanicScope
+	g.deferPanicScope = m.DeferPanicScope
+}
+
+func (m *Machine) loadGoroutine(g *goroutine) {
+	m.Ops = g.ops
+	m.NumOps = g.numOps
+	m.Values = g.values
+	m.NumValues = g.numValues
+	m.Exprs = g.exprs
+	m.Stmts = g.stmts
+	m.Blocks = g.blocks
+	m.Frames = g.frames
+	m.Package = g.pkg
+	m.Realm = g.realm
+	m.Exceptions = g.exceptions
+	m.NumResults = g.numResults
+	m.PanicScope = g.panicScope
+	m.DeferPanicScope = g.deferPanicScope
+	// the machine owns the state while running.
+	g.ops = nil
+	g.values = nil
+	g.exprs = nil
+	g.stmts = nil
+	g.blocks = nil
+	g.frames = nil
+	g.exceptions = nil
+}
+
+// Returns the running goroutine, or nil if no go statement was executed
+// during the current Run().
+func (m *Machine) currentGoroutine() *goroutine {
+	if m.sched == nil {
+		return nil
+	}
+	return m.sched.current
+}
+
+// Starts a new goroutine calling fv with args.  The goroutine is queued
+// to run once the current one blocks or exits.
+func (m *Machine) startGoroutine(cx *CallExpr, fv TypedValue, args []TypedValue) {
+	if m.sched == nil {
+		// the running code becomes the first goroutine.
+		m.sched = &scheduler{
+			current: &goroutine{ID: 1},
+			nextID:  2,
+		}
+	}
+	m.Alloc.AllocateGoroutine()
+	g := &goroutine{
+		ID:     m.sched.nextID,
+		ops:    make([]Op, goroutineStackSize),
+		values: make([]TypedValue, goroutineStackSize),
+		exprs:  []Expr{cx},
+		blocks: []*Block{m.LastBlock()},
+		pkg:    m.Package,
+		realm:  m.Realm,
+	}
+	m.sched.nextID++
+	// the goroutine calls fv and discards its results,
+	// then exits (see doOpGoexit).
+	g.ops[0] = OpGoexit
+	g.ops[1] = OpPopResults
+	g.ops[2] = OpPrecall
+	g.numOps = 3
+	if len(args)+1 > len(g.values) {
+		g.values = make([]TypedValue, len(args)+1)
+	}
+	g.values[0] = fv
+	copy(g.values[1:], args)
+	g.numValues = len(args) + 1
+	m.sched.runq = append(m.sched.runq, g)
+}
+
+// Makes g runnable again after it was parked.
+func (m *Machine) readyGoroutine(g *goroutine) {
+	m.sched.runq = append(m.sched.runq, g)
+}
+
+// Parks the running goroutine, which must have re-queued the operation
+// it blocked on, and resumes the next runnable goroutine.
+func (m *Machine) parkGoroutine() {
+	if m.sched == nil {
+		// there is no other goroutine to wake us up.
+		panic("all goroutines are asleep - deadlock!")
+	}
+	m.saveGoroutine(m.sched.current)
+	m.runNextGoroutine()
+}
+
+// Resumes the goroutine at the head of the run queue.
+func (m *Machine) runNextGoroutine() {
+	s := m.sched
+	if len(s.runq) == 0 {
+		panic("all goroutines are asleep - deadlock!")
+	}
+	g := s.runq[0]
+	s.runq[0] = nil
+	s.runq = s.runq[1:]
+	s.current = g
+	m.loadGoroutine(g)
+}
+
+func (m *Machine) doOpGo() {
+	gs := m.PopStmt().(*GoStmt)
+	// Pop arguments
+	args := m.PopCopyValues(gs.Call.NumArgs)
+	// Pop func
+	ftv := m.PopValue()
+	if ftv.V == nil {
+		panic("go of nil func value")
+	}
+	m.startGoroutine(&gs.Call, *ftv, args)
+}
+
+func (m *Machine) doOpGoexit() {
+	// the exited goroutine is not saved; it is simply dropped.
+	m.sched.current = nil
+	m.runNextGoroutine()
+}
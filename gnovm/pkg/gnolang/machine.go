@@ -63,6 +63,7 @@ type Machine struct {
 	Cycles     int64 // number of "cpu" cycles
 
 	Debugger Debugger
+	Coverage *Coverage // records executed statements, if set
 
 	// Configuration
 	CheckTypes bool // not yet used
@@ -79,6 +80,10 @@ type Machine struct {
 	// it is executed. It is reset to zero after the defer functions in the current
 	// scope have finished executing.
 	DeferPanicScope uint
+
+	// Goroutine scheduler of the current Run(), created lazily upon
+	// the first go statement.
+	sched *scheduler
 }
 
 // NewMachine initializes a new gno virtual machine, acting as a shorthand
@@ -113,6 +118,7 @@ type MachineOptions struct {
 	MaxAllocBytes int64      // or 0 for no limit.
 	MaxCycles     int64      // or 0 for no limit.
 	GasMeter      store.GasMeter
+	Coverage      *Coverage // or nil to not record coverage.
 }
 
 // the machine constructor gets spammed
@@ -177,6 +183,7 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 	mm.Debugger.enabled = opts.Debug
 	mm.Debugger.in = opts.Input
 	mm.Debugger.out = output
+	mm.Coverage = opts.Coverage
 
 	if pv != nil {
 		mm.SetActivePackage(pv)
@@ -202,6 +209,13 @@ func (m *Machine) Release() {
 	m.NumOps = 0
 	m.NumValues = 0
 
+	if cap(m.Ops) < VMSliceSize || cap(m.Values) < VMSliceSize {
+		// the machine was left running a goroutine's stacks,
+		// e.g. after a panic; do not return it to the pool.
+		*m = Machine{}
+		return
+	}
+
 	ops, values := m.Ops[:VMSliceSize:VMSliceSize], m.Values[:VMSliceSize:VMSliceSize]
 	copy(ops, opZeroed[:])
 	copy(values, valueZeroed[:])
@@ -956,6 +970,10 @@ func (m *Machine) RunDeclaration(d Decl) {
 // package level, for which evaluations happen during
 // preprocessing).
 func (m *Machine) runDeclaration(d Decl) {
+	if isGenericTemplate(d) {
+		// only instances are declared.
+		return
+	}
 	switch d := d.(type) {
 	case *FuncDecl:
 		// nothing to do.
@@ -1008,6 +1026,7 @@ const (
 	OpPopFrameAndReset    Op = 0x15 // pop frame and reset.
 	OpPanic1              Op = 0x16 // pop exception and pop call frames.
 	OpPanic2              Op = 0x17 // pop call frames.
+	OpGoexit              Op = 0x18 // exit goroutine, run next.
 
 	/* Unary & binary operators */
 	OpUpos  Op = 0x20 // + (unary)
@@ -1090,6 +1109,7 @@ const (
 	OpDefine      Op = 0x8C // X... := Y...
 	OpInc         Op = 0x8D // X++
 	OpDec         Op = 0x8E // X--
+	OpSend        Op = 0x8F // X <- Y
 
 	/* Decl operators */
 	OpValueDecl Op = 0x90 // var/const ...
@@ -1104,6 +1124,7 @@ const (
 	OpRangeIterMap      Op = 0xD5
 	OpRangeIterArrayPtr Op = 0xD6
 	OpReturnCallDefers  Op = 0xD7 // TODO rename?
+	OpRangeIterChan     Op = 0xD8
 )
 
 const GasFactorCPU int64 = 1
@@ -1149,6 +1170,7 @@ const (
 	OpCPUPopFrameAndReset    = 1
 	OpCPUPanic1              = 1
 	OpCPUPanic2              = 1
+	OpCPUGoexit              = 1
 
 	/* Unary & binary operators */
 	OpCPUUpos  = 1
@@ -1231,6 +1253,7 @@ const (
 	OpCPUDefine      = 1
 	OpCPUInc         = 1
 	OpCPUDec         = 1
+	OpCPUSend        = 1
 
 	/* Decl operators */
 	OpCPUValueDecl = 1
@@ -1245,12 +1268,17 @@ const (
 	OpCPURangeIterMap      = 1
 	OpCPURangeIterArrayPtr = 1
 	OpCPUReturnCallDefers  = 1
+	OpCPURangeIterChan     = 1
 )
 
 //----------------------------------------
 // main run loop.
 
 func (m *Machine) Run() {
+	// Goroutines are scoped to the Run() that spawned them; any still
+	// pending when it halts are abandoned, like when main() returns.
+	sched := m.sched
+	m.sched = nil
 	for {
 		if m.Debugger.enabled {
 			m.Debug()
@@ -1261,6 +1289,7 @@ func (m *Machine) Run() {
 		/* Control operators */
 		case OpHalt:
 			m.incrCPU(OpCPUHalt)
+			m.sched = sched
 			return
 		case OpNoop:
 			m.incrCPU(OpCPUNoop)
@@ -1300,10 +1329,13 @@ func (m *Machine) Run() {
 			m.doOpCallDeferNativeBody()
 		case OpGo:
 			m.incrCPU(OpCPUGo)
-			panic("not yet implemented")
+			m.doOpGo()
+		case OpGoexit:
+			m.incrCPU(OpCPUGoexit)
+			m.doOpGoexit()
 		case OpSelect:
 			m.incrCPU(OpCPUSelect)
-			panic("not yet implemented")
+			m.doOpSelect()
 		case OpSwitchClause:
 			m.incrCPU(OpCPUSwitchClause)
 			m.doOpSwitchClause()
@@ -1547,6 +1579,9 @@ func (m *Machine) Run() {
 		case OpDec:
 			m.incrCPU(OpCPUDec)
 			m.doOpDec()
+		case OpSend:
+			m.incrCPU(OpCPUSend)
+			m.doOpSend()
 		/* Decl operators */
 		case OpValueDecl:
 			m.incrCPU(OpCPUValueDecl)
@@ -1573,6 +1608,9 @@ func (m *Machine) Run() {
 		case OpRangeIterMap:
 			m.incrCPU(OpCPURangeIterMap)
 			m.doOpExec(op)
+		case OpRangeIterChan:
+			m.incrCPU(OpCPURangeIterChan)
+			m.doOpExec(op)
 		case OpReturnCallDefers:
 			m.incrCPU(OpCPUReturnCallDefers)
 			m.doOpReturnCallDefers()
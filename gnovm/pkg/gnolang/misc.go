@@ -171,3 +171,8 @@ func DerivePkgAddr(pkgPath string) crypto.Address {
 	// NOTE: must not collide with pubkey addrs.
 	return crypto.AddressFromPreimage([]byte("pkgPath:" + pkgPath))
 }
+
+// For holding the storage deposit of a realm, apart from its own coins.
+func DeriveStorageDepositAddr(pkgPath string) crypto.Address {
+	return crypto.AddressFromPreimage([]byte("pkgPath:" + pkgPath + ".storageDeposit"))
+}
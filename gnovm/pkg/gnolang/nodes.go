@@ -76,6 +76,7 @@ const (
 	LEQ    // <=
 	GEQ    // >=
 	DEFINE // :=
+	TILDE  // ~
 
 	// Keywords
 	BREAK
@@ -216,6 +217,7 @@ func (x *BasicLitExpr) assertNode()        {}
 func (x *BinaryExpr) assertNode()          {}
 func (x *CallExpr) assertNode()            {}
 func (x *IndexExpr) assertNode()           {}
+func (x *IndexListExpr) assertNode()       {}
 func (x *SelectorExpr) assertNode()        {}
 func (x *SliceExpr) assertNode()           {}
 func (x *StarExpr) assertNode()            {}
@@ -270,6 +272,7 @@ var (
 	_ Node = &BinaryExpr{}
 	_ Node = &CallExpr{}
 	_ Node = &IndexExpr{}
+	_ Node = &IndexListExpr{}
 	_ Node = &SelectorExpr{}
 	_ Node = &SliceExpr{}
 	_ Node = &StarExpr{}
@@ -338,6 +341,7 @@ func (*BasicLitExpr) assertExpr()     {}
 func (*BinaryExpr) assertExpr()       {}
 func (*CallExpr) assertExpr()         {}
 func (*IndexExpr) assertExpr()        {}
+func (*IndexListExpr) assertExpr()    {}
 func (*SelectorExpr) assertExpr()     {}
 func (*SliceExpr) assertExpr()        {}
 func (*StarExpr) assertExpr()         {}
@@ -355,6 +359,7 @@ var (
 	_ Expr = &BinaryExpr{}
 	_ Expr = &CallExpr{}
 	_ Expr = &IndexExpr{}
+	_ Expr = &IndexListExpr{}
 	_ Expr = &SelectorExpr{}
 	_ Expr = &SliceExpr{}
 	_ Expr = &StarExpr{}
@@ -407,6 +412,12 @@ type IndexExpr struct { // X[Index]
 	HasOK bool // if true, is form: `value, ok := <X>[<Key>]
 }
 
+type IndexListExpr struct { // X[Indices...]
+	Attributes
+	X       Expr  // generic function or type
+	Indices Exprs // type arguments
+}
+
 type SelectorExpr struct { // X.Sel
 	Attributes
 	X    Expr      // expression
@@ -448,8 +459,9 @@ type TypeAssertExpr struct { // X.(Type)
 // (referencing) are represented with RefExpr nodes.
 type UnaryExpr struct { // (Op X)
 	Attributes
-	X  Expr // operand
-	Op Word // operator
+	X     Expr // operand
+	Op    Word // operator
+	HasOK bool // if true, is form: `value, ok := <-<X>`
 }
 
 // MyType{<key>:<value>} struct, array, slice, and map
@@ -607,6 +619,7 @@ type SliceTypeExpr struct {
 type InterfaceTypeExpr struct {
 	Attributes
 	Methods FieldTypeExprs // list of methods
+	TypeSet Exprs          // type set terms (constraints only); or nil
 	Generic Name           // for uverse generics
 }
 
@@ -825,6 +838,7 @@ type RangeStmt struct {
 	IsMap      bool // if X is map type
 	IsString   bool // if X is string type
 	IsArrayPtr bool // if X is array-pointer type
+	IsChan     bool // if X is chan type
 }
 
 type ReturnStmt struct {
@@ -983,10 +997,11 @@ type FuncDecl struct {
 	Attributes
 	StaticBlock
 	NameExpr
-	IsMethod bool
-	Recv     FieldTypeExpr // receiver (if method); or empty (if function)
-	Type     FuncTypeExpr  // function signature: parameters and results
-	Body                   // function body; or empty for external (non-Go) function
+	IsMethod   bool
+	Recv       FieldTypeExpr  // receiver (if method); or empty (if function)
+	TypeParams FieldTypeExprs // type parameters (if generic); or nil
+	Type       FuncTypeExpr   // function signature: parameters and results
+	Body                      // function body; or empty for external (non-Go) function
 }
 
 func (x *FuncDecl) GetDeclNames() []Name {
@@ -1034,8 +1049,9 @@ func (x *ValueDecl) GetDeclNames() []Name {
 type TypeDecl struct {
 	Attributes
 	NameExpr
-	Type    Expr // Name, SelectorExpr, StarExpr, or XxxTypes
-	IsAlias bool // type alias since Go 1.9
+	TypeParams FieldTypeExprs // type parameters (if generic); or nil
+	Type       Expr           // Name, SelectorExpr, StarExpr, or XxxTypes
+	IsAlias    bool           // type alias since Go 1.9
 }
 
 func (x *TypeDecl) GetDeclNames() []Name {
@@ -2112,13 +2128,14 @@ func (x *BasicLitExpr) GetInt() int {
 type GnoAttribute string
 
 const (
-	ATTR_PREPROCESSED GnoAttribute = "ATTR_PREPROCESSED"
-	ATTR_PREDEFINED   GnoAttribute = "ATTR_PREDEFINED"
-	ATTR_TYPE_VALUE   GnoAttribute = "ATTR_TYPE_VALUE"
-	ATTR_TYPEOF_VALUE GnoAttribute = "ATTR_TYPEOF_VALUE"
-	ATTR_IOTA         GnoAttribute = "ATTR_IOTA"
-	ATTR_LOCATIONED   GnoAttribute = "ATTR_LOCATIONED"
-	ATTR_INJECTED     GnoAttribute = "ATTR_INJECTED"
+	ATTR_PREPROCESSED     GnoAttribute = "ATTR_PREPROCESSED"
+	ATTR_PREDEFINED       GnoAttribute = "ATTR_PREDEFINED"
+	ATTR_TYPE_VALUE       GnoAttribute = "ATTR_TYPE_VALUE"
+	ATTR_TYPEOF_VALUE     GnoAttribute = "ATTR_TYPEOF_VALUE"
+	ATTR_IOTA             GnoAttribute = "ATTR_IOTA"
+	ATTR_LOCATIONED       GnoAttribute = "ATTR_LOCATIONED"
+	ATTR_INJECTED         GnoAttribute = "ATTR_INJECTED"
+	ATTR_GENERIC_INSTANCE GnoAttribute = "ATTR_GENERIC_INSTANCE"
 )
 
 var rePkgName = regexp.MustCompile(`^[a-z][a-z0-9_]+$`)
@@ -45,6 +45,7 @@ var wordTokenStrings = map[Word]string{
 	LEQ:             "<=",
 	GEQ:             ">=",
 	DEFINE:          ":=",
+	TILDE:           "~",
 
 	// Branch operations
 	BREAK:       "break",
@@ -122,6 +123,10 @@ func (x IndexExpr) String() string {
 	return fmt.Sprintf("%s[%s]", x.X, x.Index)
 }
 
+func (x IndexListExpr) String() string {
+	return fmt.Sprintf("%s[%s]", x.X, x.Indices.String())
+}
+
 func (x SelectorExpr) String() string {
 	// NOTE: for debugging selector issues:
 	// return fmt.Sprintf("%s.(%v).%s", n.X, n.Path.Type, n.Sel)
@@ -204,15 +209,18 @@ func (x SliceTypeExpr) String() string {
 }
 
 func (x InterfaceTypeExpr) String() string {
+	if len(x.TypeSet) > 0 {
+		return fmt.Sprintf("interface { %v; %v }", x.Methods, x.TypeSet)
+	}
 	return fmt.Sprintf("interface { %v }", x.Methods)
 }
 
 func (x ChanTypeExpr) String() string {
 	switch x.Dir {
 	case SEND:
-		return fmt.Sprintf("<-chan %s", x.Value)
-	case RECV:
 		return fmt.Sprintf("chan<- %s", x.Value)
+	case RECV:
+		return fmt.Sprintf("<-chan %s", x.Value)
 	case SEND | RECV:
 		return fmt.Sprintf("chan %s", x.Value)
 	default:
@@ -408,8 +416,12 @@ func (x FuncDecl) String() string {
 	if x.IsMethod {
 		recv = "(" + x.Recv.String() + ") "
 	}
-	return fmt.Sprintf("func %s%s%s { %s }",
-		recv, x.Name, x.Type.String()[4:], x.Body.String())
+	tparams := ""
+	if len(x.TypeParams) > 0 {
+		tparams = "[" + x.TypeParams.String() + "]"
+	}
+	return fmt.Sprintf("func %s%s%s%s { %s }",
+		recv, x.Name, tparams, x.Type.String()[4:], x.Body.String())
 }
 
 func (x ImportDecl) String() string {
@@ -437,10 +449,14 @@ func (x ValueDecl) String() string {
 }
 
 func (x TypeDecl) String() string {
+	tparams := ""
+	if len(x.TypeParams) > 0 {
+		tparams = "[" + x.TypeParams.String() + "]"
+	}
 	if x.IsAlias {
-		return fmt.Sprintf("type %s = %s", x.Name, x.Type.String())
+		return fmt.Sprintf("type %s%s = %s", x.Name, tparams, x.Type.String())
 	}
-	return fmt.Sprintf("type %s %s", x.Name, x.Type.String())
+	return fmt.Sprintf("type %s%s %s", x.Name, tparams, x.Type.String())
 }
 
 func (x FileNode) String() string {
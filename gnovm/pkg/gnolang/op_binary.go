@@ -452,6 +452,8 @@ func isEql(store Store, lv, rv *TypedValue) bool {
 			}
 		}
 		return lv.V == rv.V
+	case ChanKind:
+		return lv.V == rv.V
 	case FuncKind:
 		if debug {
 			if lv.V != nil && rv.V != nil {
@@ -29,6 +29,7 @@ RangeStmt ->
   OpRangeIterList +block
   OpRangeIterMap +block
   OpRangeIterString +block
+  OpRangeIterChan +block
 
 IfStmt ->
   OpIfCond -> +block
@@ -39,7 +40,13 @@ SwitchStmt -> +block
   OpTypeSwitch
 
 SelectStmt ->
-  OpSelectCase +block
+  OpSelect -> +block
+
+GoStmt ->
+  OpGo
+
+SendStmt ->
+  OpSend
 
 */
 
@@ -425,12 +432,84 @@ func (m *Machine) doOpExec(op Op) {
 				panic("should not happen")
 			}
 		}
+	case OpRangeIterChan:
+		bs := s.(*bodyStmt)
+		xv := m.PeekValue(1)
+		switch bs.NextBodyIndex {
+		case -2: // init.
+			bs.NumOps = m.NumOps
+			bs.NumValues = m.NumValues
+			bs.NumExprs = len(m.Exprs)
+			bs.NumStmts = len(m.Stmts)
+			bs.NextBodyIndex++
+			fallthrough
+		case -1: // receive and assign element.
+			ev, ok, ready := m.recvChan(xv)
+			if !ready {
+				// retry once woken up (sticky).
+				m.parkGoroutine()
+				return
+			}
+			if !ok {
+				// channel closed, done with range.
+				m.PopFrameAndReset()
+				return
+			}
+			if bs.Key != nil {
+				switch bs.Op {
+				case ASSIGN:
+					m.PopAsPointer(bs.Key).Assign2(m.Alloc, m.Store, m.Realm, ev, false)
+				case DEFINE:
+					knxp := bs.Key.(*NameExpr).Path
+					ptr := m.LastBlock().GetPointerTo(m.Store, knxp)
+					ptr.TV.Assign(m.Alloc, ev, false)
+				default:
+					panic("should not happen")
+				}
+			}
+			bs.NextBodyIndex++
+			fallthrough
+		default:
+			// NOTE: duplicated for OpRangeIter,
+			// but the length is unknown.
+			if bs.NextBodyIndex < bs.BodyLen {
+				next := bs.Body[bs.NextBodyIndex]
+				bs.NextBodyIndex++
+				// continue onto exec stmt.
+				bs.Active = next
+				s = next // switch on bs.Active
+				goto EXEC_SWITCH
+			} else if bs.NextBodyIndex == bs.BodyLen {
+				// set up next assign if needed.
+				switch bs.Op {
+				case ASSIGN:
+					if bs.Key != nil {
+						m.PushForPointer(bs.Key)
+					}
+				case DEFINE:
+					// do nothing
+				case ILLEGAL:
+					// do nothing, no assignment
+				default:
+					panic("should not happen")
+				}
+				bs.ListIndex++
+				bs.NextBodyIndex = -1
+				bs.Active = nil
+				return // redo doOpExec:*bodyStmt
+			} else {
+				panic("should not happen")
+			}
+		}
 	}
 
 EXEC_SWITCH:
 	if debug {
 		debug.Printf("EXEC: %v\n", s)
 	}
+	if m.Coverage != nil {
+		m.Coverage.recordStmt(m, s)
+	}
 	switch cs := s.(type) {
 	case *AssignStmt:
 		switch cs.Op {
@@ -596,6 +675,8 @@ EXEC_SWITCH:
 			m.PushOp(OpRangeIterString)
 		} else if cs.IsArrayPtr {
 			m.PushOp(OpRangeIterArrayPtr)
+		} else if cs.IsChan {
+			m.PushOp(OpRangeIterChan)
 		} else {
 			m.PushOp(OpRangeIter)
 		}
@@ -628,7 +709,7 @@ EXEC_SWITCH:
 			for {
 				fr := m.LastFrame()
 				switch fr.Source.(type) {
-				case *ForStmt, *RangeStmt, *SwitchStmt:
+				case *ForStmt, *RangeStmt, *SwitchStmt, *SelectStmt:
 					if cs.Label != "" && cs.Label != fr.Label {
 						m.PopFrame()
 					} else {
@@ -731,6 +812,58 @@ EXEC_SWITCH:
 		m.PushOp(OpTypeDecl)
 		m.PushExpr(cs.Type)
 		m.PushOp(OpEval)
+	case *GoStmt:
+		m.PushOp(OpGo)
+		// evaluate args
+		args := cs.Call.Args
+		for i := len(args) - 1; 0 <= i; i-- {
+			m.PushExpr(args[i])
+			m.PushOp(OpEval)
+		}
+		// evaluate func
+		m.PushExpr(cs.Call.Func)
+		m.PushOp(OpEval)
+	case *SendStmt:
+		m.PopStmt()
+		m.PushOp(OpSend)
+		// evaluate value
+		m.PushExpr(cs.Value)
+		m.PushOp(OpEval)
+		// evaluate chan
+		m.PushExpr(cs.Chan)
+		m.PushOp(OpEval)
+	case *SelectStmt:
+		m.PushFrameBasic(cs)
+		m.PushOp(OpPopFrameAndReset)
+		m.PushOp(OpSelect)
+		// evaluate the channel and value to send of each case, in
+		// source order. Each is evaluated in a block of its case, as
+		// their names were resolved (by the preprocessor) from there.
+		lb := m.LastBlock()
+		for i := len(cs.Cases) - 1; 0 <= i; i-- {
+			cc := &cs.Cases[i]
+			var xs []Expr
+			switch comm := cc.Comm.(type) {
+			case nil:
+				continue // default
+			case *SendStmt:
+				xs = []Expr{comm.Chan, comm.Value}
+			case *AssignStmt:
+				xs = []Expr{comm.Rhs[0].(*UnaryExpr).X}
+			case *ExprStmt:
+				xs = []Expr{comm.X.(*UnaryExpr).X}
+			default:
+				panic(fmt.Sprintf(
+					"unexpected select case %v",
+					cc.Comm))
+			}
+			m.PushBlock(m.Alloc.NewBlock(cc, lb))
+			m.PushOp(OpPopBlock)
+			for j := len(xs) - 1; 0 <= j; j-- {
+				m.PushExpr(xs[j])
+				m.PushOp(OpEval)
+			}
+		}
 	case *DeferStmt:
 		m.PushOp(OpDefer)
 		// evaluate args
@@ -508,8 +508,19 @@ func (m *Machine) doOpStaticTypeOf() {
 			m.PushOp(OpEval)
 		}
 	case *UnaryExpr:
-		m.PushExpr(x.X)
-		m.PushOp(OpStaticTypeOf)
+		if x.Op == ARROW {
+			// the type of a receive is the chan element type.
+			start := m.NumValues
+			m.PushOp(OpHalt)
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+			m.Run() // XXX replace
+			xt := m.ReapValues(start)[0].GetType()
+			m.PushValue(asValue(xt.Elem()))
+		} else {
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+		}
 	case *CompositeLitExpr:
 		m.PushExpr(x.Type)
 		m.PushOp(OpEval)
@@ -121,5 +121,21 @@ func (m *Machine) doOpUxor() {
 }
 
 func (m *Machine) doOpUrecv() {
-	panic("not yet implemented")
+	ux := m.PopExpr().(*UnaryExpr)
+	if debug {
+		debug.Printf("doOpUrecv(%v)\n", ux)
+	}
+	xv := m.PeekValue(1)
+	rv, ok, ready := m.recvChan(xv)
+	if !ready {
+		// retry once woken up.
+		m.PushExpr(ux)
+		m.PushOp(OpUrecv)
+		m.parkGoroutine()
+		return
+	}
+	*xv = rv
+	if ux.HasOK {
+		m.PushValue(untypedBool(ok))
+	}
 }
@@ -117,6 +117,8 @@ type Object interface {
 	GetIsNewDeleted() bool
 	SetIsNewDeleted(bool)
 	GetIsTransient() bool
+	GetLastObjectSize() int64
+	SetLastObjectSize(int64)
 
 	// Saves to realm along the way if owned, and also (dirty
 	// or new).
@@ -146,6 +148,9 @@ type ObjectInfo struct {
 	isNewEscaped bool
 	isNewDeleted bool
 
+	// size of the last persisted encoding, if any.
+	lastObjectSize int64
+
 	// XXX huh?
 	owner Object // mem reference to owner.
 }
@@ -165,6 +170,8 @@ func (oi *ObjectInfo) Copy() ObjectInfo {
 		isNewReal:    oi.isNewReal,
 		isNewEscaped: oi.isNewEscaped,
 		isNewDeleted: oi.isNewDeleted,
+
+		lastObjectSize: oi.lastObjectSize,
 	}
 }
 
@@ -325,6 +332,14 @@ func (oi *ObjectInfo) GetIsTransient() bool {
 	return false
 }
 
+func (oi *ObjectInfo) GetLastObjectSize() int64 {
+	return oi.lastObjectSize
+}
+
+func (oi *ObjectInfo) SetLastObjectSize(x int64) {
+	oi.lastObjectSize = x
+}
+
 func (tv *TypedValue) GetFirstObject(store Store) Object {
 	switch cv := tv.V.(type) {
 	case PointerValue:
@@ -132,6 +132,9 @@ func PredefineFileSet(store Store, pn *PackageNode, fset *FileSet) {
 			fn.Decls[i] = d2
 		}
 	}
+	// Finally, preprocess the bodies of generic instances
+	// declared while predefining.
+	preprocessPendingInstances(store, pn)
 }
 
 // Initialize static block info.
@@ -219,6 +222,12 @@ func initStaticBlocks(store Store, ctx BlockNode, bn BlockNode) {
 					last2.Predefine(n.Const, nx.Name)
 				}
 			case *TypeDecl:
+				if len(n.TypeParams) > 0 {
+					// generic types are only declared
+					// upon instantiation.
+					defineGeneric(last, n)
+					return n, TRANS_SKIP
+				}
 				last2 := skipFile(last)
 				last2.Predefine(false, n.Name)
 			case *FuncDecl:
@@ -228,6 +237,18 @@ func initStaticBlocks(store Store, ctx BlockNode, bn BlockNode) {
 						// NOTE: document somewhere.
 						n.Recv.Name = ".recv"
 					}
+					if _, _, ok := genericReceiver(n); ok {
+						// declared upon instantiation
+						// of the receiver type.
+						n.SetAttribute(ATTR_PREDEFINED, true)
+						n.SetAttribute(ATTR_PREPROCESSED, true)
+						return n, TRANS_SKIP
+					}
+				} else if len(n.TypeParams) > 0 {
+					// generic functions are only declared
+					// upon instantiation.
+					defineGeneric(last, n)
+					return n, TRANS_SKIP
 				} else {
 					pkg := skipFile(last).(*PackageNode)
 					// special case: if n.Name == "init", assign unique suffix.
@@ -238,8 +259,9 @@ func initStaticBlocks(store Store, ctx BlockNode, bn BlockNode) {
 						dname := Name(fmt.Sprintf("init.%d", idx))
 						n.Name = dname
 					}
-
-					pkg.Predefine(false, n.Name)
+					if n.GetAttribute(ATTR_GENERIC_INSTANCE) != true {
+						pkg.Predefine(false, n.Name)
+					}
 				}
 			case *FuncTypeExpr:
 				for i := range n.Params {
@@ -568,10 +590,29 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					}
 					xt = xt.Elem()
 					n.IsArrayPtr = true
+				case ChanKind:
+					ct := baseOf(xt).(*ChanType)
+					if ct.Dir == SEND {
+						panic(fmt.Sprintf(
+							"invalid operation: range %s receive from send-only channel %s",
+							n.X.String(), xt.String()))
+					}
+					if n.Value != nil {
+						panic(fmt.Sprintf(
+							"range over %s permits only one iteration variable",
+							n.X.String()))
+					}
+					n.IsChan = true
 				}
 				// key value if define.
 				if n.Op == DEFINE {
-					if xt.Kind() == MapKind {
+					if xt.Kind() == ChanKind {
+						if n.Key != nil {
+							et := xt.Elem()
+							kn := n.Key.(*NameExpr).Name
+							last.Define(kn, anyValue(et))
+						}
+					} else if xt.Kind() == MapKind {
 						if n.Key != nil {
 							kt := baseOf(xt).(*MapType).Key
 							kn := n.Key.(*NameExpr).Name
@@ -838,6 +879,9 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 							n.Decls[i] = d2
 						}
 					}
+					// Preprocess bodies of generic instances
+					// declared while predefining.
+					preprocessPendingInstances(store, packageOf(n))
 				}
 
 			// TRANS_BLOCK -----------------------
@@ -1000,6 +1044,7 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 								"package %s cannot only be referred to in a selector expression",
 								n.Name))
 						}
+						assertInstantiated(nt, ftype)
 					}
 				}
 
@@ -1254,6 +1299,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 				}
 			// TRANS_LEAVE -----------------------
 			case *CallExpr:
+				// Infer type arguments of generic function calls.
+				if gt, ok := evalStaticTypeOf(store, last, n.Func).(*genericType); ok {
+					n.Func = inferGenericCall(store, last, n, gt)
+				}
 				// Func type evaluation.
 				var ft *FuncType
 				ift := evalStaticTypeOf(store, last, n.Func)
@@ -1504,6 +1553,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 			// TRANS_LEAVE -----------------------
 			case *IndexExpr:
 				dt := evalStaticTypeOf(store, last, n.X)
+				if gt, ok := dt.(*genericType); ok {
+					// Instantiate generic with type argument.
+					return instantiateExpr(store, last, n, gt, Exprs{n.Index}), TRANS_CONTINUE
+				}
 				if dt.Kind() == PointerKind {
 					// if a is a pointer to an array,
 					// a[low : high : max] is shorthand
@@ -1526,6 +1579,17 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 						dt.String()))
 				}
 
+			// TRANS_LEAVE -----------------------
+			case *IndexListExpr:
+				gt, ok := evalStaticTypeOf(store, last, n.X).(*genericType)
+				if !ok {
+					panic(fmt.Sprintf(
+						"%s is not a generic function or type",
+						n.X.String()))
+				}
+				// Instantiate generic with type arguments.
+				return instantiateExpr(store, last, n, gt, n.Indices), TRANS_CONTINUE
+
 			// TRANS_LEAVE -----------------------
 			case *SliceExpr:
 				// Replace const L/H/M with int *ConstExpr,
@@ -1795,6 +1859,7 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					// packages may contain constant vars,
 					// so check and evaluate if so.
 					tt := pn.GetStaticTypeOfAt(store, n.Path)
+					assertInstantiated(tt, ftype)
 					if isUntyped(tt) {
 						cx := evalConst(store, last, n)
 						return cx, TRANS_CONTINUE
@@ -1913,6 +1978,14 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 							// re-definitions
 							last.Define(lhs0, anyValue(mt.Value))
 							last.Define(lhs1, anyValue(BoolType))
+						case *UnaryExpr:
+							// Receive case: a, ok := <-ch
+							lhs0 := n.Lhs[0].(*NameExpr).Name
+							lhs1 := n.Lhs[1].(*NameExpr).Name
+							et := evalStaticTypeOf(store, last, cx)
+							// re-definitions
+							last.Define(lhs0, anyValue(et))
+							last.Define(lhs1, anyValue(BoolType))
 						default:
 							panic("should not happen")
 						}
@@ -2042,11 +2115,11 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 			case *BranchStmt:
 				switch n.Op {
 				case BREAK:
-					if !isSwitchLabel(ns, n.Label) {
+					if !isSwitchLabel(ns, n.Label) && !isSelectLabel(ns, n.Label) {
 						findBranchLabel(last, n.Label)
 					}
 				case CONTINUE:
-					if isSwitchLabel(ns, n.Label) {
+					if isSwitchLabel(ns, n.Label) || isSelectLabel(ns, n.Label) {
 						panic(fmt.Sprintf("invalid continue label %q\n", n.Label))
 					}
 					findBranchLabel(last, n.Label)
@@ -2147,8 +2220,19 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 
 			// TRANS_LEAVE -----------------------
 			case *SendStmt:
-				// Value consts become default *ConstExprs.
-				checkOrConvertType(store, last, &n.Value, nil, false)
+				ct, ok := baseOf(evalStaticTypeOf(store, last, n.Chan)).(*ChanType)
+				if !ok {
+					panic(fmt.Sprintf(
+						"invalid operation: cannot send to non-channel %s",
+						n.Chan.String()))
+				}
+				if ct.Dir&SEND == 0 {
+					panic(fmt.Sprintf(
+						"invalid operation: cannot send to receive-only channel %s (variable of type %s)",
+						n.Chan.String(), ct.String()))
+				}
+				// Value consts become *ConstExprs of the element type.
+				checkOrConvertType(store, last, &n.Value, ct.Elt, false)
 
 			// TRANS_LEAVE -----------------------
 			case *SelectCaseStmt:
@@ -2207,6 +2291,7 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					// - `var a, b, c T = f()`
 					// - `var a, b = n.(T)`
 					// - `var a, b = n[i], where n is a map`
+					// - `var a, b = <-n`
 
 					var tuple *tupleType
 					valueExpr := n.Values[0]
@@ -2222,6 +2307,12 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 							break
 						}
 						expr.(*IndexExpr).HasOK = true
+					case *UnaryExpr:
+						if expr.Op != ARROW {
+							panic(fmt.Sprintf("unexpected ValueDecl value expression %s", expr.String()))
+						}
+						tuple = &tupleType{Elts: []Type{valueType, BoolType}}
+						expr.HasOK = true
 					default:
 						panic(fmt.Sprintf("unexpected ValueDecl value expression type %T", expr))
 					}
@@ -2362,6 +2453,10 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 				case *StructType:
 					*dst = *(tmp.(*StructType))
 				case *DeclaredType:
+					if n.IsAlias {
+						// the aliased type is already declared.
+						break
+					}
 					// if store has this type, use that.
 					tid := DeclaredTypeID(lastpn.PkgPath, n.Name)
 					exists := false
@@ -2428,6 +2523,18 @@ func isSwitchLabel(ns []Node, label Name) bool {
 	return false
 }
 
+func isSelectLabel(ns []Node, label Name) bool {
+	if label == "" {
+		return false
+	}
+	for i := len(ns) - 1; 0 <= i; i-- {
+		if ss, ok := ns[i].(*SelectStmt); ok && ss.GetLabel() == label {
+			return true
+		}
+	}
+	return false
+}
+
 // Idempotent.
 func pushInitBlock(bn BlockNode, last *BlockNode, stack *[]BlockNode) {
 	if !bn.IsInitialized() {
@@ -2485,7 +2592,7 @@ func evalStaticType(store Store, last BlockNode, x Expr) Type {
 	// See comment in evalStaticTypeOfRaw.
 	if store != nil && pn.PkgPath != uversePkgPath {
 		pv := pn.NewPackage() // temporary
-		store = store.BeginTransaction(nil, nil)
+		store = store.BeginTransaction(nil, nil, nil)
 		store.SetCachePackage(pv)
 	}
 	m := NewMachine(pn.PkgPath, store)
@@ -2559,7 +2666,7 @@ func evalStaticTypeOfRaw(store Store, last BlockNode, x Expr) (t Type) {
 		// yet predefined this time around.
 		if store != nil && pn.PkgPath != uversePkgPath {
 			pv := pn.NewPackage() // temporary
-			store = store.BeginTransaction(nil, nil)
+			store = store.BeginTransaction(nil, nil, nil)
 			store.SetCachePackage(pv)
 		}
 		m := NewMachine(pn.PkgPath, store)
@@ -3261,6 +3368,17 @@ func findUndefined2(store Store, last BlockNode, x Expr, t Type) (un Name) {
 		if un != "" {
 			return
 		}
+	case *IndexListExpr:
+		un = findUndefined(store, last, cx.X)
+		if un != "" {
+			return
+		}
+		for i := range cx.Indices {
+			un = findUndefined(store, last, cx.Indices[i])
+			if un != "" {
+				return
+			}
+		}
 	case *constTypeExpr:
 		return
 	case *ConstExpr:
@@ -3621,6 +3739,14 @@ func tryPredefine(store Store, last BlockNode, d Decl) (un Name) {
 				tx.Path = pn.GetPathForName(store, tx.Sel)
 				ptr := pv.GetBlock(store).GetPointerTo(store, tx.Path)
 				t = ptr.TV.GetType()
+			case *IndexExpr, *IndexListExpr:
+				// instance of generic type.
+				un = findUndefined(store, last, tx)
+				if un != "" {
+					return
+				}
+				d.Type = Preprocess(store, last, tx).(Expr)
+				t = getType(d.Type)
 			default:
 				panic(fmt.Sprintf(
 					"unexpected type declaration type %v",
@@ -4006,6 +4132,11 @@ func findDependentNames(n Node, dst map[Name]struct{}) {
 	case *IndexExpr:
 		findDependentNames(cn.X, dst)
 		findDependentNames(cn.Index, dst)
+	case *IndexListExpr:
+		findDependentNames(cn.X, dst)
+		for i := range cn.Indices {
+			findDependentNames(cn.Indices[i], dst)
+		}
 	case *FuncLitExpr:
 		findDependentNames(&cn.Type, dst)
 		for _, n := range cn.GetExternNames() {
@@ -4022,8 +4153,14 @@ func findDependentNames(n Node, dst map[Name]struct{}) {
 			findDependentNames(vx, dst)
 		}
 	case *TypeDecl:
+		if isGenericTemplate(cn) {
+			return
+		}
 		findDependentNames(cn.Type, dst)
 	case *FuncDecl:
+		if isGenericTemplate(cn) {
+			return
+		}
 		findDependentNames(&cn.Type, dst)
 		if cn.IsMethod {
 			findDependentNames(&cn.Recv, dst)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -95,6 +96,14 @@ type Realm struct {
 	Path string
 	Time uint64
 
+	Deposit uint64 // storage deposit held for the realm.
+	Storage uint64 // bytes of storage covered by the deposit.
+
+	// candidates of cycle collection left for the next transaction.
+	CycleCandidates []ObjectID
+	// trial deletion of larger cycles, resumed by the next transaction.
+	CycleTrial *CycleTrial // nil if none
+
 	newCreated []Object
 	newEscaped []Object
 	newDeleted []Object
@@ -103,6 +112,10 @@ type Realm struct {
 	updated []Object // real objects that were modified.
 	deleted []Object // real objects that became deleted.
 	escaped []Object // real objects with refcount > 1.
+
+	candidates []Object // real objects that may root a garbage cycle.
+
+	sumDiff int64 // change in stored bytes during finalization.
 }
 
 // Creates a blank new realm with counter 0.
@@ -169,7 +182,8 @@ func (rlm *Realm) DidUpdate(po, xo, co Object) {
 		co.IncRefCount()
 		if co.GetRefCount() > 1 {
 			if co.GetIsEscaped() {
-				// already escaped
+				// already escaped, persist ref-count.
+				rlm.markRefCountDirty(co)
 			} else {
 				rlm.MarkNewEscaped(co)
 			}
@@ -187,6 +201,13 @@ func (rlm *Realm) DidUpdate(po, xo, co Object) {
 			if xo.GetIsReal() {
 				rlm.MarkNewDeleted(xo)
 			}
+		} else if xo.GetIsReal() {
+			if xo.GetIsEscaped() {
+				// persist ref-count.
+				rlm.markRefCountDirty(xo)
+			}
+			// may still be referenced only by a cycle.
+			rlm.candidates = append(rlm.candidates, xo)
 		}
 	}
 }
@@ -225,6 +246,16 @@ func (rlm *Realm) MarkNewReal(oo Object) {
 	rlm.newCreated = append(rlm.newCreated, oo)
 }
 
+// markRefCountDirty marks the escaped object oo dirty, so that its ref-count
+// is persisted for cycle collection. Only objects of the realm are collected,
+// so the ref-counts of objects of other packages are not persisted.
+func (rlm *Realm) markRefCountDirty(oo Object) {
+	if oo.GetObjectID().PkgID != rlm.ID {
+		return
+	}
+	rlm.MarkDirty(oo)
+}
+
 func (rlm *Realm) MarkDirty(oo Object) {
 	if debug {
 		if !oo.GetIsReal() && !oo.GetIsNewReal() {
@@ -332,6 +363,9 @@ func (rlm *Realm) FinalizeRealmTransaction(readonly bool, store Store) {
 	// at this point, all ref-counts are final.
 	// demote any escaped if ref-count is 1.
 	rlm.processNewEscapedMarks(store)
+	// delete unreachable cycles among objects
+	// whose ref-count was decremented.
+	rlm.collectCycles(store)
 	// given created and updated objects,
 	// mark all owned-ancestors also as dirty.
 	rlm.markDirtyAncestors(store)
@@ -346,6 +380,10 @@ func (rlm *Realm) FinalizeRealmTransaction(readonly bool, store Store) {
 	rlm.saveUnsavedObjects(store)
 	// delete all deleted objects.
 	rlm.removeDeletedObjects(store)
+	// account for storage growth or shrinkage.
+	if rlm.sumDiff != 0 {
+		store.AddRealmStorageDiff(rlm.Path, rlm.sumDiff)
+	}
 	// reset realm state for new transaction.
 	rlm.clearMarks()
 }
@@ -437,7 +475,8 @@ func (rlm *Realm) incRefCreatedDescendants(store Store, oo Object) {
 			}
 		} else if rc > 1 {
 			if child.GetIsEscaped() {
-				// already escaped, do nothing.
+				// already escaped, persist ref-count.
+				rlm.markRefCountDirty(child)
 			} else {
 				// NOTE: do not unset owner here,
 				// may become unescaped later
@@ -509,7 +548,12 @@ func (rlm *Realm) decRefDeletedDescendants(store Store, oo Object) {
 		if rc == 0 {
 			rlm.decRefDeletedDescendants(store, child)
 		} else if rc > 0 {
-			// do nothing
+			if child.GetIsEscaped() {
+				// persist ref-count.
+				rlm.markRefCountDirty(child)
+			}
+			// may still be referenced only by a cycle.
+			rlm.candidates = append(rlm.candidates, child)
 		} else {
 			panic("deleted descendants should not have a reference count of less than zero")
 		}
@@ -553,9 +597,15 @@ func (rlm *Realm) processNewEscapedMarks(store Store) {
 
 			// add to escaped, and mark dirty previous owner.
 			po := getOwner(store, eo)
+			if !eo.GetIsNewReal() {
+				// persist ref-count.
+				rlm.markRefCountDirty(eo)
+			}
 			if po == nil {
 				// e.g. !eo.GetIsNewReal(),
 				// should have no parent.
+				// clear any unresolved owner id.
+				eo.SetOwner(nil)
 				continue
 			} else {
 				if po.GetRefCount() == 0 {
@@ -577,6 +627,576 @@ func (rlm *Realm) processNewEscapedMarks(store Store) {
 	rlm.escaped = escaped // XXX is this actually used?
 }
 
+//----------------------------------------
+// collectCycles
+
+type gcColor uint8
+
+const (
+	gcBlack gcColor = iota // in use, or not yet visited.
+	gcGray                 // visited; internal references subtracted.
+	gcWhite                // garbage, unless found to be reachable.
+	gcFreed                // deleted.
+)
+
+// Maximum number of child objects that collectCycles visits per
+// transaction, so that its cost doesn't grow with the size of the realm.
+const maxCycleCollectionVisits = 100
+
+// Maximum number of candidates left by collectCycles for the next
+// transaction of the realm.
+const maxCycleCandidates = 100
+
+// Deletes cycles of real objects that are no longer reachable, which
+// reference counting alone can never bring down to zero.
+//
+// This is a synchronous trial deletion (Bacon & Rajan, 2001) rooted at
+// .candidates, the objects whose ref-count was decremented without reaching
+// zero, after those left by the previous transaction.  For each candidate,
+// references internal to the subgraph reachable from it are subtracted;
+// objects still referenced from outside of it, and all objects reachable
+// from those, are restored.  What remains is garbage.
+//
+// At most maxCycleCollectionVisits children are visited.  A candidate
+// whose subgraph is not fully visited is restored, and left for the next
+// transaction, unless the whole budget was spent on it: its subgraph is
+// then left to a trial deletion spanning transactions, see
+// resumeCycleTrial().
+// Candidates and children are visited in order, so collection is
+// deterministic.
+// Must run *after* processNewEscapedMarks(), when all ref-counts are final.
+func (rlm *Realm) collectCycles(store Store) {
+	if len(rlm.candidates) == 0 && len(rlm.CycleCandidates) == 0 &&
+		rlm.CycleTrial == nil {
+		return
+	}
+	roots := make([]Object, 0, len(rlm.CycleCandidates)+len(rlm.candidates))
+	for _, oid := range rlm.CycleCandidates {
+		if oo := store.GetObjectSafe(oid); oo != nil {
+			roots = append(roots, oo)
+		}
+	}
+	roots = append(roots, rlm.candidates...)
+	left := rlm.CycleCandidates
+	rlm.CycleCandidates = nil
+
+	colors := make(map[ObjectID]gcColor)
+	visited := make(map[ObjectID]struct{}, len(roots))
+	budget := maxCycleCollectionVisits
+	for _, oo := range roots {
+		oid := oo.GetObjectID()
+		if oo.GetIsDeleted() || oo.GetRefCount() == 0 {
+			// deleted, or to be deleted.
+			continue
+		}
+		if oid.PkgID != rlm.ID {
+			// not ours to collect.
+			continue
+		}
+		if _, ok := visited[oid]; ok {
+			continue
+		}
+		visited[oid] = struct{}{}
+		full := budget == maxCycleCollectionVisits
+		if rlm.markGray(store, colors, &budget, oo) {
+			rlm.scanGray(store, colors, oo)
+			rlm.collectWhite(store, colors, oo)
+			continue
+		}
+		// out of budget, restore.
+		if colors[oid] == gcGray {
+			rlm.scanBlack(store, colors, oo)
+		}
+		if full {
+			if rlm.CycleTrial == nil {
+				rlm.CycleTrial = &CycleTrial{}
+			}
+			rlm.CycleTrial.addRoot(oid)
+		} else if len(rlm.CycleCandidates) < maxCycleCandidates {
+			rlm.CycleCandidates = append(rlm.CycleCandidates, oid)
+		}
+	}
+	trialing := rlm.CycleTrial != nil
+	if trialing {
+		numCandidates := len(rlm.candidates)
+		rlm.resumeCycleTrial(store)
+		// left by the trial, for the next transaction.
+		for _, oo := range rlm.candidates[numCandidates:] {
+			if len(rlm.CycleCandidates) < maxCycleCandidates {
+				rlm.CycleCandidates = append(rlm.CycleCandidates, oo.GetObjectID())
+			}
+		}
+		if len(rlm.CycleTrial.Roots) == 0 {
+			rlm.CycleTrial = nil
+		}
+	}
+	// collected objects must be neither saved
+	// nor used to mark ancestors dirty.
+	// NOTE: filter by id, as marks may hold
+	// other instances of collected objects.
+	rlm.created = withoutFreed(rlm.created, colors)
+	rlm.updated = withoutFreed(rlm.updated, colors)
+	rlm.escaped = withoutFreed(rlm.escaped, colors)
+	// save the candidates left for the next transaction,
+	// and the progress of the trial.
+	if len(left) > 0 || len(rlm.CycleCandidates) > 0 || trialing {
+		store.SetPackageRealm(rlm)
+	}
+}
+
+// Subtracts references from oo to its children, recursively, as long as
+// their number fits in the budget.  Returns false if the budget ran out
+// before the whole subgraph was visited.  The references from gray
+// objects to all their children are always subtracted, so that
+// scanBlack() restores them exactly.
+func (rlm *Realm) markGray(store Store, colors map[ObjectID]gcColor, budget *int, oo Object) bool {
+	oid := oo.GetObjectID()
+	if colors[oid] == gcGray {
+		return true
+	}
+	// count children before loading them.
+	numChildren := len(getChildObjects(oo, nil))
+	if numChildren > *budget {
+		*budget = 0
+		return false
+	}
+	*budget -= numChildren
+	colors[oid] = gcGray
+	ok := true
+	for _, child := range rlm.getOwnChildObjects(store, oo) {
+		child.DecRefCount()
+		if ok {
+			ok = rlm.markGray(store, colors, budget, child)
+		}
+	}
+	return ok
+}
+
+// Gray objects still referenced from outside the visited subgraph are
+// restored along with their descendants; the rest become white.
+func (rlm *Realm) scanGray(store Store, colors map[ObjectID]gcColor, oo Object) {
+	oid := oo.GetObjectID()
+	if colors[oid] != gcGray {
+		return
+	}
+	if oo.GetRefCount() > 0 {
+		rlm.scanBlack(store, colors, oo)
+		return
+	}
+	colors[oid] = gcWhite
+	for _, child := range rlm.getOwnChildObjects(store, oo) {
+		rlm.scanGray(store, colors, child)
+	}
+}
+
+// Restores references from oo to its children, recursively.
+func (rlm *Realm) scanBlack(store Store, colors map[ObjectID]gcColor, oo Object) {
+	colors[oo.GetObjectID()] = gcBlack
+	for _, child := range rlm.getOwnChildObjects(store, oo) {
+		child.IncRefCount()
+		if colors[child.GetObjectID()] != gcBlack {
+			rlm.scanBlack(store, colors, child)
+		}
+	}
+}
+
+// Deletes white objects reachable from oo.  References from deleted
+// objects to surviving ones were already subtracted by markGray().
+func (rlm *Realm) collectWhite(store Store, colors map[ObjectID]gcColor, oo Object) {
+	oid := oo.GetObjectID()
+	if colors[oid] != gcWhite {
+		return
+	}
+	colors[oid] = gcFreed
+	for _, child := range rlm.getOwnChildObjects(store, oo) {
+		switch colors[child.GetObjectID()] {
+		case gcWhite:
+			rlm.collectWhite(store, colors, child)
+		case gcBlack:
+			if po := getOwner(store, child); po != nil && po.GetObjectID() == oid {
+				// survives its owner.
+				child.SetOwner(nil)
+			}
+			// persist ref-count.
+			rlm.MarkDirty(child)
+		}
+	}
+	saved := !oo.GetIsNewReal()
+	oo.SetIsNewDeleted(false)
+	oo.SetIsNewReal(false)
+	oo.SetIsNewEscaped(false)
+	oo.SetIsDirty(false, 0)
+	oo.SetIsDeleted(true, rlm.Time)
+	if saved {
+		rlm.deleted = append(rlm.deleted, oo)
+	}
+}
+
+// Like getChildObjects2, but only returns (and loads) child objects of the
+// realm; package values and objects of other packages are never collected.
+func (rlm *Realm) getOwnChildObjects(store Store, oo Object) []Object {
+	chos := getChildObjects(oo, nil)
+	objs := make([]Object, 0, len(chos))
+	for _, child := range chos {
+		if ref, ok := child.(RefValue); ok {
+			if ref.ObjectID.PkgID != rlm.ID {
+				continue
+			}
+			objs = append(objs, store.GetObject(ref.ObjectID))
+		} else if co, ok := child.(Object); ok {
+			if _, ok := co.(*PackageValue); ok {
+				continue
+			}
+			if co.GetObjectID().PkgID != rlm.ID {
+				continue
+			}
+			objs = append(objs, co)
+		}
+	}
+	return objs
+}
+
+func withoutFreed(objs []Object, colors map[ObjectID]gcColor) []Object {
+	res := objs[:0]
+	for _, oo := range objs {
+		if oo.GetIsDeleted() || colors[oo.GetObjectID()] == gcFreed {
+			continue
+		}
+		res = append(res, oo)
+	}
+	return res
+}
+
+//----------------------------------------
+// resumeCycleTrial
+
+// Trial deletion of the subgraph reachable from Roots[0], spanning
+// transactions.  Nothing is subtracted from ref-counts: the state of each
+// visited object is kept in a CycleNode of the store instead, and the
+// visited objects are linked through them.
+type CycleTrial struct {
+	Roots    []ObjectID // roots to try, in order; Roots[0] is being tried.
+	Phase    uint8      // current phase, or cycleIdle if not started.
+	Next     ObjectID   // next object to visit in the phase.
+	Last     ObjectID   // last object found.
+	Excess   int64      // references to found objects from outside of them.
+	LiveNext ObjectID   // next live object to visit.
+	LiveLast ObjectID   // last live object found.
+}
+
+// State of an object in the trial deletion of a realm.
+type CycleNode struct {
+	RefCount int64    // ref-count of the object when found.
+	Internal int64    // number of references from found objects.
+	Live     bool     // referenced from outside of the found objects, or from a live one.
+	Next     ObjectID // next object found.
+	NextLive ObjectID // next live object found.
+}
+
+const (
+	cycleIdle  uint8 = iota // not started.
+	cycleFind               // finding objects reachable from the root.
+	cycleScan               // finding live objects.
+	cycleFree               // deleting the others.
+	cycleClear              // forgetting the state of the found objects.
+)
+
+func (ct *CycleTrial) addRoot(oid ObjectID) {
+	if len(ct.Roots) >= maxCycleCandidates || slices.Contains(ct.Roots, oid) {
+		return
+	}
+	ct.Roots = append(ct.Roots, oid)
+}
+
+// Resumes the trial deletion of .CycleTrial, visiting at most
+// maxCycleCollectionVisits children, or starts that of the next root.
+//
+// Objects reachable from the root are found first, counting the
+// references between them.  Found objects referenced more than that are
+// live, along with those reachable from them.  Others are garbage, and are
+// deleted.
+// Only found objects of the realm can be modified between transactions,
+// and only by the realm: the trial is cut short if the realm modifies any
+// of them before garbage is known, as the counts may no longer hold.
+// Garbage can't be modified, so that garbage is always collected.
+// Must run after other modifications of the transaction are marked.
+func (rlm *Realm) resumeCycleTrial(store Store) {
+	ct := rlm.CycleTrial
+	nodes := newCycleNodes(store, rlm.created)
+	defer nodes.flush()
+	if (ct.Phase == cycleFind || ct.Phase == cycleScan) && rlm.modifiedCycleNodes(nodes) {
+		// cut short.
+		ct.Phase, ct.Next = cycleClear, ct.Roots[0]
+	}
+	budget := maxCycleCollectionVisits
+	for budget > 0 {
+		switch ct.Phase {
+		case cycleIdle:
+			if len(ct.Roots) == 0 {
+				return
+			}
+			root := nodes.getObjectSafe(ct.Roots[0])
+			if root == nil || root.GetIsDeleted() || root.GetRefCount() == 0 {
+				ct.Roots = ct.Roots[1:]
+				continue
+			}
+			oid := root.GetObjectID()
+			nodes.set(oid, &CycleNode{RefCount: int64(root.GetRefCount())})
+			*ct = CycleTrial{
+				Roots:  ct.Roots,
+				Phase:  cycleFind,
+				Next:   oid,
+				Last:   oid,
+				Excess: int64(root.GetRefCount()),
+			}
+		case cycleFind:
+			if ct.Next.IsZero() {
+				// all found.
+				if ct.Excess == 0 {
+					ct.Phase = cycleFree
+				} else {
+					ct.Phase = cycleScan
+				}
+				ct.Next = ct.Roots[0]
+				continue
+			}
+			oo := nodes.getObject(ct.Next)
+			if !rlm.spendCycleBudget(&budget, len(getChildObjects(oo, nil))) {
+				return
+			}
+			for _, child := range rlm.getOwnChildObjects(store, oo) {
+				cid := child.GetObjectID()
+				cn := nodes.get(cid)
+				if cn == nil {
+					// found.
+					cn = &CycleNode{RefCount: int64(child.GetRefCount())}
+					last := nodes.get(ct.Last)
+					last.Next = cid
+					nodes.set(ct.Last, last)
+					ct.Last = cid
+					ct.Excess += cn.RefCount
+				}
+				cn.Internal++
+				nodes.set(cid, cn)
+				ct.Excess--
+			}
+			ct.Next = nodes.get(ct.Next).Next
+		case cycleScan:
+			if !ct.LiveNext.IsZero() {
+				// live objects make their children live.
+				oo := nodes.getObject(ct.LiveNext)
+				if !rlm.spendCycleBudget(&budget, len(getChildObjects(oo, nil))) {
+					return
+				}
+				for _, cid := range rlm.getOwnChildIDs(oo) {
+					rlm.markCycleLive(nodes, cid)
+				}
+				ct.LiveNext = nodes.get(ct.LiveNext).NextLive
+			} else if !ct.Next.IsZero() {
+				// objects referenced from outside are live.
+				budget--
+				cn := nodes.get(ct.Next)
+				if cn.RefCount > cn.Internal {
+					rlm.markCycleLive(nodes, ct.Next)
+				}
+				ct.Next = cn.Next
+			} else {
+				ct.Phase, ct.Next = cycleFree, ct.Roots[0]
+			}
+		case cycleFree:
+			if ct.Next.IsZero() {
+				ct.Phase, ct.Next = cycleClear, ct.Roots[0]
+				continue
+			}
+			cn := nodes.get(ct.Next)
+			if cn.Live {
+				budget--
+			} else {
+				oo := nodes.getObject(ct.Next)
+				if !rlm.spendCycleBudget(&budget, len(getChildObjects(oo, nil))) {
+					return
+				}
+				rlm.freeCycleObject(store, nodes, oo)
+			}
+			ct.Next = cn.Next
+		case cycleClear:
+			if ct.Next.IsZero() {
+				// done, try the next root.
+				*ct = CycleTrial{Roots: ct.Roots[1:]}
+				continue
+			}
+			budget--
+			next := nodes.get(ct.Next).Next
+			nodes.set(ct.Next, nil)
+			ct.Next = next
+		default:
+			panic("unexpected cycle trial phase")
+		}
+	}
+}
+
+// Returns true if the realm modified an object found by the trial.
+func (rlm *Realm) modifiedCycleNodes(nodes *cycleNodes) bool {
+	for _, objs := range [][]Object{rlm.updated, rlm.deleted} {
+		for _, oo := range objs {
+			if nodes.get(oo.GetObjectID()) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Spends n of the budget, unless it doesn't fit.  An object with more
+// children than the whole budget is visited at once.
+func (rlm *Realm) spendCycleBudget(budget *int, n int) bool {
+	if n > *budget && *budget < maxCycleCollectionVisits {
+		return false
+	}
+	*budget -= n
+	return true
+}
+
+// Marks the found object oid live, to be visited.
+func (rlm *Realm) markCycleLive(nodes *cycleNodes, oid ObjectID) {
+	ct := rlm.CycleTrial
+	cn := nodes.get(oid)
+	if cn.Live {
+		return
+	}
+	cn.Live = true
+	nodes.set(oid, cn)
+	if ct.LiveNext.IsZero() {
+		ct.LiveNext = oid
+	} else {
+		last := nodes.get(ct.LiveLast)
+		last.NextLive = oid
+		nodes.set(ct.LiveLast, last)
+	}
+	ct.LiveLast = oid
+}
+
+// Like collectWhite(), but for a garbage object of the trial, whose
+// garbage children may already be deleted.  Live children may have been
+// modified since they were found; those left referenced only by oo are
+// deleted, the others become candidates.
+func (rlm *Realm) freeCycleObject(store Store, nodes *cycleNodes, oo Object) {
+	oid := oo.GetObjectID()
+	for _, cid := range rlm.getOwnChildIDs(oo) {
+		if !nodes.get(cid).Live {
+			continue
+		}
+		child := nodes.getObject(cid)
+		child.DecRefCount()
+		if child.GetRefCount() == 0 {
+			rlm.decRefDeletedDescendants(store, child)
+			continue
+		}
+		if po := getOwner(store, child); po != nil && po.GetObjectID() == oid {
+			// survives its owner.
+			child.SetOwner(nil)
+		}
+		// persist ref-count.
+		rlm.MarkDirty(child)
+		rlm.candidates = append(rlm.candidates, child)
+	}
+	oo.SetIsNewDeleted(false)
+	oo.SetIsNewReal(false)
+	oo.SetIsNewEscaped(false)
+	oo.SetIsDirty(false, 0)
+	oo.SetIsDeleted(true, rlm.Time)
+	rlm.deleted = append(rlm.deleted, oo)
+}
+
+// Like getOwnChildObjects, but returns the object ids without loading.
+func (rlm *Realm) getOwnChildIDs(oo Object) []ObjectID {
+	chos := getChildObjects(oo, nil)
+	oids := make([]ObjectID, 0, len(chos))
+	for _, child := range chos {
+		var oid ObjectID
+		if ref, ok := child.(RefValue); ok {
+			oid = ref.ObjectID
+		} else if co, ok := child.(Object); ok {
+			if _, ok := co.(*PackageValue); ok {
+				continue
+			}
+			oid = co.GetObjectID()
+		}
+		if oid.PkgID != rlm.ID {
+			continue
+		}
+		oids = append(oids, oid)
+	}
+	return oids
+}
+
+// CycleNodes of the store, cached during a transaction, along with the
+// objects created by the transaction, which are not saved yet.
+type cycleNodes struct {
+	store   Store
+	nodes   map[ObjectID]*CycleNode
+	dirty   []ObjectID // in order, for determinism.
+	created map[ObjectID]Object
+}
+
+func newCycleNodes(store Store, created []Object) *cycleNodes {
+	cns := &cycleNodes{
+		store:   store,
+		nodes:   make(map[ObjectID]*CycleNode),
+		created: make(map[ObjectID]Object, len(created)),
+	}
+	for _, oo := range created {
+		cns.created[oo.GetObjectID()] = oo
+	}
+	return cns
+}
+
+// Returns the object oid, created by the transaction or from the store,
+// or nil if none.
+func (cns *cycleNodes) getObjectSafe(oid ObjectID) Object {
+	if oo, ok := cns.created[oid]; ok {
+		return oo
+	}
+	return cns.store.GetObjectSafe(oid)
+}
+
+// Like getObjectSafe, but panics if there is no such object.
+func (cns *cycleNodes) getObject(oid ObjectID) Object {
+	oo := cns.getObjectSafe(oid)
+	if oo == nil {
+		panic(fmt.Sprintf("unexpected object with id %s", oid.String()))
+	}
+	return oo
+}
+
+// Returns the state of oid, or nil if it was not found by the trial.
+func (cns *cycleNodes) get(oid ObjectID) *CycleNode {
+	if cn, ok := cns.nodes[oid]; ok {
+		return cn
+	}
+	cn := cns.store.GetCycleNode(oid)
+	cns.nodes[oid] = cn
+	return cn
+}
+
+// Sets the state of oid, or forgets it if cn is nil.
+func (cns *cycleNodes) set(oid ObjectID, cn *CycleNode) {
+	cns.dirty = append(cns.dirty, oid)
+	cns.nodes[oid] = cn
+}
+
+// Saves the states set, once each.
+func (cns *cycleNodes) flush() {
+	saved := make(map[ObjectID]struct{}, len(cns.dirty))
+	for _, oid := range cns.dirty {
+		if _, ok := saved[oid]; ok {
+			continue
+		}
+		saved[oid] = struct{}{}
+		cns.store.SetCycleNode(oid, cns.nodes[oid])
+	}
+}
+
 //----------------------------------------
 // markDirtyAncestors
 
@@ -744,7 +1364,7 @@ func (rlm *Realm) saveObject(store Store, oo Object) {
 	}
 	// set object to store.
 	// NOTE: also sets the hash to object.
-	store.SetObject(oo)
+	rlm.sumDiff += store.SetObject(oo)
 	// set index.
 	if oo.GetIsEscaped() {
 		// XXX save oid->hash to iavl.
@@ -756,7 +1376,7 @@ func (rlm *Realm) saveObject(store Store, oo Object) {
 
 func (rlm *Realm) removeDeletedObjects(store Store) {
 	for _, do := range rlm.deleted {
-		store.DelObject(do)
+		rlm.sumDiff -= store.DelObject(do)
 	}
 }
 
@@ -790,6 +1410,8 @@ func (rlm *Realm) clearMarks() {
 	rlm.updated = nil
 	rlm.deleted = nil
 	rlm.escaped = nil
+	rlm.candidates = nil
+	rlm.sumDiff = 0
 }
 
 //----------------------------------------
@@ -878,6 +1500,8 @@ func getChildObjects(val Value, more []Value) []Value {
 		return more
 	case *NativeValue:
 		panic("native values not supported")
+	case *ChanValue:
+		panic("channel values not supported")
 	default:
 		panic(fmt.Sprintf(
 			"unexpected type %v",
@@ -1223,6 +1847,8 @@ func copyValueWithRefs(val Value) Value {
 		return hiv
 	case *NativeValue:
 		panic("native values not supported")
+	case *ChanValue:
+		panic("channel values not supported")
 	default:
 		panic(fmt.Sprintf(
 			"unexpected type %v",
@@ -1390,6 +2016,8 @@ func fillTypesOfValue(store Store, val Value) Value {
 		return cv
 	case *NativeValue:
 		panic("native values not supported")
+	case *ChanValue:
+		panic("channel values not supported")
 	case RefValue: // do nothing
 		return cv
 	case *HeapItemValue:
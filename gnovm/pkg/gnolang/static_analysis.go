@@ -5,7 +5,7 @@ import (
 )
 
 type staticAnalysis struct {
-	// contexts for switch, for, functions, and lambdas
+	// contexts for switch, select, for, functions, and lambdas
 	contexts []interface{}
 
 	// here we accumulate errors
@@ -179,6 +179,23 @@ func (s *staticAnalysis) staticAnalysisStmt(stmt Stmt) bool {
 		hasNoBreaks := len(ctx.breakstmts) == 0
 		terminates := hasNoBreaks && hasDefault && casesTerm
 		return terminates
+	case *SelectStmt:
+		s.push(&SelectContext{selectStmt: n})
+
+		// the statement lists in each case,
+		// including the default if present,
+		// end in a terminating statement.
+		casesTerm := true
+		for _, cs := range n.Cases {
+			if !s.staticAnalysisBlockStmt(cs.Body) {
+				casesTerm = false
+			}
+		}
+		ctx := s.pop().(*SelectContext)
+		// there are no "break" statements referring to the "select" statement
+		hasNoBreaks := len(ctx.breakstmts) == 0
+		terminates := hasNoBreaks && casesTerm
+		return terminates
 	case *PanicStmt:
 		return true
 	}
@@ -228,3 +245,16 @@ func (sc *SwitchContext) label() string {
 func (sc *SwitchContext) pushBreak(breakstmt *BranchStmt) {
 	sc.breakstmts = append(sc.breakstmts, breakstmt)
 }
+
+type SelectContext struct {
+	selectStmt *SelectStmt
+	breakstmts []*BranchStmt
+}
+
+func (sc *SelectContext) label() string {
+	return string(sc.selectStmt.Label)
+}
+
+func (sc *SelectContext) pushBreak(breakstmt *BranchStmt) {
+	sc.breakstmts = append(sc.breakstmts, breakstmt)
+}
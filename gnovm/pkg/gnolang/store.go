@@ -36,16 +36,18 @@ type NativeStore func(pkgName string, name Name) func(m *Machine)
 // blockchain, or the file system.
 type Store interface {
 	// STABLE
-	BeginTransaction(baseStore, iavlStore store.Store) TransactionStore
+	BeginTransaction(baseStore, iavlStore store.Store, gasMeter store.GasMeter) TransactionStore
 	SetPackageGetter(PackageGetter)
 	GetPackage(pkgPath string, isImport bool) *PackageValue
 	SetCachePackage(*PackageValue)
 	GetPackageRealm(pkgPath string) *Realm
 	SetPackageRealm(*Realm)
+	GetCycleNode(oid ObjectID) *CycleNode     // nil if none
+	SetCycleNode(oid ObjectID, cn *CycleNode) // deletes if cn is nil
 	GetObject(oid ObjectID) Object
 	GetObjectSafe(oid ObjectID) Object
-	SetObject(Object)
-	DelObject(Object)
+	SetObject(Object) int64 // returns the change in stored size
+	DelObject(Object) int64 // returns the stored size removed
 	GetType(tid TypeID) Type
 	GetTypeSafe(tid TypeID) Type
 	SetCacheType(Type)
@@ -72,6 +74,9 @@ type Store interface {
 	SetLogStoreOps(enabled bool)
 	SprintStoreOps() string
 	LogSwitchRealm(rlmpath string) // to mark change of realm boundaries
+	SetGasConfig(GasConfig)        // for the rest of the transaction
+	AddRealmStorageDiff(rlmpath string, diff int64)
+	RealmStorageDiffs() map[string]int64 // by realm path, since the transaction began
 	ClearCache()
 	Print()
 }
@@ -87,10 +92,29 @@ type TransactionStore interface {
 	Write()
 }
 
+const (
+	GasSetObjectDesc = "SetObjectPerByte"
+)
+
+// GasConfig defines the gas charged by the store, in addition to that of
+// the underlying stores.
+type GasConfig struct {
+	GasSetObject int64 // per byte written
+}
+
+// DefaultGasConfig returns a default gas config for the store.
+func DefaultGasConfig() GasConfig {
+	return GasConfig{
+		GasSetObject: 16,
+	}
+}
+
 type defaultStore struct {
 	// underlying stores used to keep data
 	baseStore store.Store // for objects, types, nodes
 	iavlStore store.Store // for escaped object hashes
+	gasMeter  store.GasMeter
+	gasConfig GasConfig
 
 	// transaction-scoped
 	cacheObjects map[ObjectID]Object            // this is a real cache, reset with every transaction.
@@ -106,14 +130,16 @@ type defaultStore struct {
 	go2gnoStrict     bool                  // if true, native->gno type conversion must be registered.
 
 	// transient
-	opslog  []StoreOp // for debugging and testing.
-	current []string  // for detecting import cycles.
+	opslog            []StoreOp        // for debugging and testing.
+	current           []string         // for detecting import cycles.
+	realmStorageDiffs map[string]int64 // for storage deposits.
 }
 
 func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore {
 	ds := &defaultStore{
 		baseStore: baseStore,
 		iavlStore: iavlStore,
+		gasConfig: DefaultGasConfig(),
 		alloc:     alloc,
 
 		// cacheObjects is set; objects in the store will be copied over for any transaction.
@@ -133,7 +159,8 @@ func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore
 }
 
 // If nil baseStore and iavlStore, the baseStores are re-used.
-func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store) TransactionStore {
+// If gasMeter is nil, storage is not charged for.
+func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store, gasMeter store.GasMeter) TransactionStore {
 	if baseStore == nil {
 		baseStore = ds.baseStore
 	}
@@ -144,6 +171,8 @@ func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store) Trans
 		// underlying stores
 		baseStore: baseStore,
 		iavlStore: iavlStore,
+		gasMeter:  gasMeter,
+		gasConfig: ds.gasConfig,
 
 		// transaction-scoped
 		cacheObjects: make(map[ObjectID]Object),
@@ -159,8 +188,9 @@ func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store) Trans
 		go2gnoStrict:     ds.go2gnoStrict,
 
 		// transient
-		current: nil,
-		opslog:  nil,
+		current:           nil,
+		opslog:            nil,
+		realmStorageDiffs: make(map[string]int64),
 	}
 	ds2.SetCachePackage(Uverse())
 
@@ -369,6 +399,27 @@ func (ds *defaultStore) SetPackageRealm(rlm *Realm) {
 	ds.baseStore.Set([]byte(key), bz)
 }
 
+// Returns the state of oid in the trial deletion of its realm, if any.
+func (ds *defaultStore) GetCycleNode(oid ObjectID) *CycleNode {
+	key := backendCycleNodeKey(oid)
+	bz := ds.baseStore.Get([]byte(key))
+	if bz == nil {
+		return nil
+	}
+	cn := new(CycleNode)
+	amino.MustUnmarshal(bz, cn)
+	return cn
+}
+
+func (ds *defaultStore) SetCycleNode(oid ObjectID, cn *CycleNode) {
+	key := backendCycleNodeKey(oid)
+	if cn == nil {
+		ds.baseStore.Delete([]byte(key))
+		return
+	}
+	ds.baseStore.Set([]byte(key), amino.MustMarshal(cn))
+}
+
 // NOTE: does not consult the packageGetter, so instead
 // call GetPackage() for packages.
 // NOTE: current implementation behavior requires
@@ -419,6 +470,7 @@ func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
 			}
 		}
 		oo.SetHash(ValueHash{NewHashlet(hash)})
+		oo.SetLastObjectSize(int64(len(hashbz)))
 		ds.cacheObjects[oid] = oo
 		_ = fillTypesOfValue(ds, oo)
 		return oo
@@ -428,7 +480,7 @@ func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
 
 // NOTE: unlike GetObject(), SetObject() is also used to persist updated
 // package values.
-func (ds *defaultStore) SetObject(oo Object) {
+func (ds *defaultStore) SetObject(oo Object) int64 {
 	oid := oo.GetObjectID()
 	// replace children/fields with Ref.
 	o2 := copyValueWithRefs(oo)
@@ -441,13 +493,17 @@ func (ds *defaultStore) SetObject(oo Object) {
 	}
 	oo.SetHash(ValueHash{hash})
 	// save bytes to backend.
+	size := int64(len(hash) + len(bz))
+	diff := size - oo.GetLastObjectSize()
 	if ds.baseStore != nil {
 		key := backendObjectKey(oid)
-		hashbz := make([]byte, len(hash)+len(bz))
+		hashbz := make([]byte, size)
 		copy(hashbz, hash.Bytes())
 		copy(hashbz[HashSize:], bz)
+		ds.consumeGas(ds.gasConfig.GasSetObject*size, GasSetObjectDesc)
 		ds.baseStore.Set([]byte(key), hashbz)
 	}
+	oo.SetLastObjectSize(size)
 	// save object to cache.
 	if debug {
 		if oid.IsZero() {
@@ -480,10 +536,12 @@ func (ds *defaultStore) SetObject(oo Object) {
 		value = hash.Bytes()
 		ds.iavlStore.Set(key, value)
 	}
+	return diff
 }
 
-func (ds *defaultStore) DelObject(oo Object) {
+func (ds *defaultStore) DelObject(oo Object) int64 {
 	oid := oo.GetObjectID()
+	size := oo.GetLastObjectSize()
 	// delete from cache.
 	delete(ds.cacheObjects, oid)
 	// delete from backend.
@@ -496,6 +554,7 @@ func (ds *defaultStore) DelObject(oo Object) {
 		ds.opslog = append(ds.opslog,
 			StoreOp{Type: StoreOpDel, Object: oo})
 	}
+	return size
 }
 
 // NOTE: not used quite yet.
@@ -825,6 +884,28 @@ func (ds *defaultStore) LogSwitchRealm(rlmpath string) {
 		StoreOp{Type: StoreOpSwitchRealm, RlmPath: rlmpath})
 }
 
+func (ds *defaultStore) AddRealmStorageDiff(rlmpath string, diff int64) {
+	if ds.realmStorageDiffs == nil {
+		ds.realmStorageDiffs = make(map[string]int64)
+	}
+	ds.realmStorageDiffs[rlmpath] += diff
+}
+
+func (ds *defaultStore) RealmStorageDiffs() map[string]int64 {
+	return ds.realmStorageDiffs
+}
+
+func (ds *defaultStore) SetGasConfig(gasConfig GasConfig) {
+	ds.gasConfig = gasConfig
+}
+
+func (ds *defaultStore) consumeGas(gas int64, descriptor string) {
+	// In the tests, the defaultStore may not set the gas meter.
+	if ds.gasMeter != nil {
+		ds.gasMeter.ConsumeGas(gas, descriptor)
+	}
+}
+
 func (ds *defaultStore) ClearCache() {
 	ds.cacheObjects = make(map[ObjectID]Object)
 	ds.cacheTypes = txlog.GoMap[TypeID, Type](map[TypeID]Type{})
@@ -871,6 +952,11 @@ func backendRealmKey(oid ObjectID) string {
 	return "oid:" + oid.String() + "#realm"
 }
 
+// oid: object id found by the trial deletion of its realm.
+func backendCycleNodeKey(oid ObjectID) string {
+	return "oid:" + oid.String() + "#cycle"
+}
+
 func backendTypeKey(tid TypeID) string {
 	return "tid:" + tid.String()
 }
@@ -17,7 +17,7 @@ func TestTransactionStore(t *testing.T) {
 
 	st := NewStore(nil, tm2Store, tm2Store)
 	wrappedTm2Store := tm2Store.CacheWrap()
-	txSt := st.BeginTransaction(wrappedTm2Store, wrappedTm2Store)
+	txSt := st.BeginTransaction(wrappedTm2Store, wrappedTm2Store, nil)
 	m := NewMachineWithOptions(MachineOptions{
 		PkgPath: "hello",
 		Store:   txSt,
@@ -89,7 +89,7 @@ func TestCopyFromCachedStore(t *testing.T) {
 	d1s := dbadapter.StoreConstructor(d1, storetypes.StoreOptions{})
 	d2s := dbadapter.StoreConstructor(d2, storetypes.StoreOptions{})
 	destStore := NewStore(nil, d1s, d2s)
-	destStoreTx := destStore.BeginTransaction(nil, nil) // CopyFromCachedStore requires a tx store.
+	destStoreTx := destStore.BeginTransaction(nil, nil, nil) // CopyFromCachedStore requires a tx store.
 	CopyFromCachedStore(destStoreTx, cachedStore, c1s, c2s)
 	destStoreTx.Write()
 
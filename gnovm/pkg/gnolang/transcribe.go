@@ -33,6 +33,8 @@ const (
 	TRANS_CALL_ARG
 	TRANS_INDEX_X
 	TRANS_INDEX_INDEX
+	TRANS_INDEXLIST_X
+	TRANS_INDEXLIST_INDEX
 	TRANS_SELECTOR_X
 	TRANS_SLICE_X
 	TRANS_SLICE_LOW
@@ -180,6 +182,19 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		if isStopOrSkip(nc, c) {
 			return
 		}
+	case *IndexListExpr:
+		cnn.X = transcribe(t, nns, TRANS_INDEXLIST_X, 0, cnn.X, &c).(Expr)
+		if isStopOrSkip(nc, c) {
+			return
+		}
+		for idx := range cnn.Indices {
+			cnn.Indices[idx] = transcribe(t, nns, TRANS_INDEXLIST_INDEX, idx, cnn.Indices[idx], &c).(Expr)
+			if isBreak(c) {
+				break
+			} else if isStopOrSkip(nc, c) {
+				return
+			}
+		}
 	case *SelectorExpr:
 		cnn.X = transcribe(t, nns, TRANS_SELECTOR_X, 0, cnn.X, &c).(Expr)
 		if isStopOrSkip(nc, c) {
@@ -567,9 +582,11 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		} else {
 			cnn = cnn2.(*SelectCaseStmt)
 		}
-		cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
-		if isStopOrSkip(nc, c) {
-			return
+		if cnn.Comm != nil { // nil for default case
+			cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
+			if isStopOrSkip(nc, c) {
+				return
+			}
 		}
 		// iterate over Body; its length can change if a statement is decomposed.
 		for idx := 0; idx < len(cnn.Body); idx++ {
@@ -32,10 +32,11 @@ var (
 	}
 	// TODO: star, addressable
 	unaryChecker = map[Word]func(t Type) bool{
-		ADD: isNumeric,
-		SUB: isNumeric,
-		XOR: isIntNum,
-		NOT: isBoolean,
+		ADD:   isNumeric,
+		SUB:   isNumeric,
+		XOR:   isIntNum,
+		NOT:   isBoolean,
+		ARROW: isRecvChan,
 	}
 	IncDecStmtChecker = map[Word]func(t Type) bool{
 		INC: isNumeric,
@@ -126,6 +127,16 @@ func isIntNum(t Type) bool {
 	}
 }
 
+// chan types that can be received from.
+func isRecvChan(t Type) bool {
+	switch t := baseOf(t).(type) {
+	case *ChanType:
+		return t.Dir&RECV != 0
+	default:
+		return false
+	}
+}
+
 func isNumericOrString(t Type) bool {
 	switch t := baseOf(t).(type) {
 	case PrimitiveType:
@@ -163,13 +174,14 @@ func assertComparable2(dt Type) {
 	case *StructType:
 		for _, f := range cdt.Fields {
 			switch cft := baseOf(f.Type).(type) {
-			case PrimitiveType, *PointerType, *InterfaceType, *NativeType, *ArrayType, *StructType:
+			case PrimitiveType, *PointerType, *InterfaceType, *NativeType, *ArrayType, *StructType, *ChanType:
 				assertComparable2(cft)
 			default:
 				panic(fmt.Sprintf("%v is not comparable", dt))
 			}
 		}
 	case *PointerType: // &a == &b
+	case *ChanType: // made by the same make
 	case *InterfaceType:
 	case *SliceType, *FuncType, *MapType:
 	case *NativeType:
@@ -249,10 +261,10 @@ func checkValDefineMismatch(n Node) {
 	}
 
 	// Special case for single value.
-	// If the value is a call expression, type assertion, or index expression,
-	// it can be assigned to multiple variables.
+	// If the value is a call expression, type assertion, index expression,
+	// or receive expression, it can be assigned to multiple variables.
 	if numValues == 1 {
-		switch values[0].(type) {
+		switch vx := values[0].(type) {
 		case *CallExpr:
 			return
 		case *TypeAssertExpr:
@@ -265,6 +277,13 @@ func checkValDefineMismatch(n Node) {
 				panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
 			}
 			return
+		case *UnaryExpr:
+			if vx.Op == ARROW {
+				if numNames != 2 {
+					panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
+				}
+				return
+			}
 		}
 	}
 
@@ -550,7 +569,17 @@ func checkAssignableTo(xt, dt Type, autoNative bool) error {
 		return errors.New("should not happen")
 	case *DeclaredType:
 		panic("should not happen")
-	case *FuncType, *StructType, *PackageType, *ChanType, *TypeType:
+	case *ChanType:
+		if xt.TypeID() == cdt.TypeID() {
+			return nil // ok
+		}
+		// a bidirectional channel is assignable to a directional one.
+		if cxt, ok := xt.(*ChanType); ok && cxt.Dir == BOTH {
+			if cxt.Elt.TypeID() == cdt.Elt.TypeID() {
+				return nil // ok
+			}
+		}
+	case *FuncType, *StructType, *PackageType, *TypeType:
 		if xt.TypeID() == cdt.TypeID() {
 			return nil // ok
 		}
@@ -758,6 +787,8 @@ func (x *RangeStmt) AssertCompatible(store Store, last BlockNode) {
 		if vt != nil {
 			assertAssignableTo(cxt.Elt, vt, false)
 		}
+	case *ChanType:
+		assertAssignableTo(cxt.Elt, kt, false)
 	case PrimitiveType:
 		if cxt.Kind() == StringKind {
 			if kt != nil && kt.Kind() != IntKind {
@@ -852,6 +883,29 @@ func (x *AssignStmt) AssertCompatible(store Store, last BlockNode) {
 					}
 				}
 				cx.HasOK = true
+			case *UnaryExpr: // must be a receive when len(Lhs) > len(Rhs)
+				if cx.Op != ARROW {
+					panic(fmt.Sprintf("RHS should not be %v when len(Lhs) > len(Rhs)", cx))
+				}
+				if len(x.Lhs) != 2 {
+					panic("should not happen")
+				}
+				if x.Op == ASSIGN {
+					if !isBlankIdentifier(x.Lhs[0]) {
+						assertValidAssignLhs(store, last, x.Lhs[0])
+						lt := evalStaticTypeOf(store, last, x.Lhs[0])
+						rt := evalStaticTypeOf(store, last, cx)
+						assertAssignableTo(rt, lt, false)
+					}
+					if !isBlankIdentifier(x.Lhs[1]) {
+						assertValidAssignLhs(store, last, x.Lhs[1])
+						dt := evalStaticTypeOf(store, last, x.Lhs[1])
+						if dt != nil && dt.Kind() != BoolKind { // typed, not bool
+							panic(fmt.Sprintf("want bool type got %v", dt))
+						}
+					}
+				}
+				cx.HasOK = true
 			default:
 				panic(fmt.Sprintf("RHS should not be %v when len(Lhs) > len(Rhs)", cx))
 			}
@@ -70,6 +70,7 @@ func (*ChanType) assertType()       {}
 func (*NativeType) assertType()     {}
 func (blockType) assertType()       {}
 func (*tupleType) assertType()      {}
+func (*genericType) assertType()    {}
 func (RefType) assertType()         {}
 func (MaybeNativeType) assertType() {}
 
@@ -1094,9 +1095,9 @@ func (ct *ChanType) String() string {
 	case SEND | RECV:
 		return "chan " + ct.Elt.String()
 	case SEND:
-		return "<-chan " + ct.Elt.String()
-	case RECV:
 		return "chan<- " + ct.Elt.String()
+	case RECV:
+		return "<-chan " + ct.Elt.String()
 	default:
 		panic("should not happen")
 	}
@@ -2121,6 +2122,7 @@ const (
 	BlockKind   // not in go.
 	TupleKind   // not in go.
 	RefTypeKind // not in go.
+	GenericKind // not in go.
 )
 
 // This is generally slower than switching on baseOf(t).
@@ -2197,6 +2199,8 @@ func KindOf(t Type) Kind {
 		return TupleKind
 	case RefType:
 		return RefTypeKind
+	case *genericType:
+		return GenericKind
 	case MaybeNativeType:
 		return t.Kind()
 	default:
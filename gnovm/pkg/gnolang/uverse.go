@@ -32,6 +32,21 @@ var gErrorType = &DeclaredType{
 	sealed: true,
 }
 
+// any is an alias for interface{}.
+var gAnyType = &InterfaceType{
+	PkgPath: uversePkgPath,
+}
+
+// comparable is only usable as a type constraint.
+var gComparableType = &DeclaredType{
+	PkgPath: uversePkgPath,
+	Name:    "comparable",
+	Base: &InterfaceType{
+		PkgPath: uversePkgPath,
+	},
+	sealed: true,
+}
+
 var gStringerType = &DeclaredType{
 	PkgPath: uversePkgPath,
 	Name:    "stringer",
@@ -128,6 +143,8 @@ func UverseNode() *PackageNode {
 	// by a TypeValue.
 	def("typeval", asValue(gTypeType))
 	def("error", asValue(gErrorType))
+	def("any", asValue(gAnyType))
+	def("comparable", asValue(gComparableType))
 
 	// Values
 	def("true", untypedBool(true))
@@ -608,7 +625,29 @@ func UverseNode() *PackageNode {
 			return
 		},
 	)
-	def("close", undefined)
+	defNative("close",
+		Flds( // params
+			"c", AnyT(),
+		),
+		nil, // results
+		func(m *Machine) {
+			arg0 := m.LastBlock().GetParams1()
+			ct, ok := baseOf(arg0.TV.T).(*ChanType)
+			if !ok {
+				panic(fmt.Sprintf(
+					"invalid operation: non-chan argument to close (%s)",
+					arg0.TV.T.String()))
+			}
+			if ct.Dir == RECV {
+				panic(fmt.Sprintf(
+					"invalid operation: cannot close receive-only channel (%s)",
+					arg0.TV.T.String()))
+			}
+			cv, _ := arg0.TV.V.(*ChanValue)
+			m.closeChan(cv)
+			return
+		},
+	)
 	def("complex", undefined)
 	defNative("copy",
 		Flds( // params
@@ -867,10 +906,24 @@ func UverseNode() *PackageNode {
 					panic("make() of map type takes 1 or 2 arguments")
 				}
 			case *ChanType:
+				// NOTE: the type is not used.
 				if vargsl == 0 {
-					panic("not yet implemented")
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(0),
+					})
+					return
 				} else if vargsl == 1 {
-					panic("not yet implemented")
+					sv := vargs.TV.GetPointerAtIndexInt(m.Store, 0).Deref()
+					si := sv.ConvertGetInt()
+					if si < 0 {
+						panic("makechan: size out of range")
+					}
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(si),
+					})
+					return
 				} else {
 					panic("make() of chan type takes 1 or 2 arguments")
 				}
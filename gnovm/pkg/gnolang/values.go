@@ -35,6 +35,7 @@ func (*SliceValue) assertValue()       {}
 func (*StructValue) assertValue()      {}
 func (*FuncValue) assertValue()        {}
 func (*MapValue) assertValue()         {}
+func (*ChanValue) assertValue()        {}
 func (*BoundMethodValue) assertValue() {}
 func (TypeValue) assertValue()         {}
 func (*PackageValue) assertValue()     {}
@@ -59,6 +60,7 @@ var (
 	_ Value = &StructValue{}
 	_ Value = &FuncValue{}
 	_ Value = &MapValue{}
+	_ Value = &ChanValue{}
 	_ Value = &BoundMethodValue{}
 	_ Value = TypeValue{}
 	_ Value = &PackageValue{}
@@ -808,6 +810,29 @@ func (mv *MapValue) DeleteForKey(store Store, key *TypedValue) {
 	}
 }
 
+// ----------------------------------------
+// ChanValue
+
+// ChanValue is the runtime value of a channel created with make().
+// Channels only exist for the duration of a machine run; they are never
+// persisted, and goroutines blocked on them are tracked in recvq and sendq.
+type ChanValue struct {
+	Buffer []TypedValue
+	Cap    int
+	Closed bool
+
+	recvq []*chanWaiter // goroutines blocked receiving
+	sendq []*chanWaiter // goroutines blocked sending
+}
+
+func (cv *ChanValue) GetLength() int {
+	return len(cv.Buffer)
+}
+
+func (cv *ChanValue) GetCapacity() int {
+	return cv.Cap
+}
+
 // ----------------------------------------
 // TypeValue
 
@@ -1658,7 +1683,9 @@ func (tv *TypedValue) ComputeMapKey(store Store, omitType bool) MapKey {
 		pv := tv.V.(*PackageValue)
 		bz = append(bz, []byte(strconv.Quote(pv.PkgPath))...)
 	case *ChanType:
-		panic("not yet implemented")
+		// channels compare by identity.
+		cv, _ := tv.V.(*ChanValue)
+		bz = append(bz, []byte(fmt.Sprintf("%p", cv))...)
 	case *NativeType:
 		panic("not yet implemented")
 	default:
@@ -2132,6 +2159,8 @@ func (tv *TypedValue) GetLength() int {
 			return bt.Len
 		case *SliceType:
 			return 0
+		case *ChanType:
+			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
 				return at.Len
@@ -2152,6 +2181,8 @@ func (tv *TypedValue) GetLength() int {
 		return cv.GetLength()
 	case *MapValue:
 		return cv.GetLength()
+	case *ChanValue:
+		return cv.GetLength()
 	case *NativeValue:
 		return cv.Value.Len()
 	case PointerValue:
@@ -2174,6 +2205,8 @@ func (tv *TypedValue) GetCapacity() int {
 			return bt.Len
 		case *SliceType:
 			return 0
+		case *ChanType:
+			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
 				return at.Len
@@ -2188,6 +2221,8 @@ func (tv *TypedValue) GetCapacity() int {
 		return cv.GetCapacity()
 	case *SliceValue:
 		return cv.GetCapacity()
+	case *ChanValue:
+		return cv.GetCapacity()
 	case *NativeValue:
 		return cv.Value.Cap()
 	case PointerValue:
@@ -0,0 +1,517 @@
+package gnolang
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// ----------------------------------------
+// JSON values
+//
+// Values are mapped to JSON as follows:
+//
+//   - booleans, numbers and strings map to their JSON counterparts.
+//   - []byte and [N]byte map to base64 encoded strings.
+//   - arrays and slices map to JSON arrays, nil slices to null.
+//   - structs map to JSON objects of their exported fields; the key is
+//     the field's json tag name if any, otherwise the field name.
+//   - maps map to JSON objects; keys must be of a primitive type.
+//   - pointers map to the value they point to, nil pointers to null.
+//   - std.Address maps to its bech32 string, std.Coins may also be given
+//     as a coins string such as "10ugnot,5foo".
+//
+// Interfaces may only be encoded, since their concrete type cannot be
+// inferred from JSON.  Other kinds of values are not supported.
+
+// UnmarshalJSONValue decodes bz as a value of type t.
+// Only exported struct fields may be set.
+// Panics if bz is not a valid JSON encoding of a value of type t.
+func UnmarshalJSONValue(alloc *Allocator, store Store, bz []byte, t Type) TypedValue {
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var jv any
+	if err := dec.Decode(&jv); err != nil {
+		panic(fmt.Sprintf("invalid JSON value for %s: %v", t.String(), err))
+	}
+	if dec.More() {
+		panic(fmt.Sprintf("invalid JSON value for %s: trailing data", t.String()))
+	}
+	return jsonToTypedValue(alloc, store, jv, t)
+}
+
+func jsonToTypedValue(alloc *Allocator, store Store, jv any, t Type) (tv TypedValue) {
+	tv.T = t
+	if dt, ok := t.(*DeclaredType); ok && dt.PkgPath == "std" {
+		switch dt.Name {
+		case "Address":
+			s, ok := jv.(string)
+			if !ok {
+				panic(fmt.Sprintf("expected bech32 string for std.Address, got %s", jsonKind(jv)))
+			}
+			if _, err := crypto.AddressFromBech32(s); err != nil {
+				panic(fmt.Sprintf("invalid std.Address %q: %v", s, err))
+			}
+			tv.V = alloc.NewString(s)
+			return
+		case "Coins":
+			if s, ok := jv.(string); ok {
+				coins, err := std.ParseCoins(s)
+				if err != nil {
+					panic(fmt.Sprintf("invalid std.Coins %q: %v", s, err))
+				}
+				list := make([]any, len(coins))
+				for i, coin := range coins {
+					list[i] = map[string]any{
+						"denom":  coin.Denom,
+						"amount": json.Number(strconv.FormatInt(coin.Amount, 10)),
+					}
+				}
+				jv = list
+			}
+		}
+	}
+	switch bt := baseOf(t).(type) {
+	case PrimitiveType:
+		return jsonToPrimitive(alloc, jv, t, bt)
+	case *ArrayType:
+		if bt.Elt == Uint8Type {
+			bz := jsonBytes(jv, t)
+			if len(bz) != bt.Len {
+				panic(fmt.Sprintf("expected %d bytes for %s, got %d", bt.Len, t.String(), len(bz)))
+			}
+			av := alloc.NewDataArray(bt.Len)
+			copy(av.Data, bz)
+			tv.V = av
+			return
+		}
+		list, ok := jv.([]any)
+		if !ok {
+			panic(fmt.Sprintf("expected array for %s, got %s", t.String(), jsonKind(jv)))
+		}
+		if len(list) != bt.Len {
+			panic(fmt.Sprintf("expected %d elements for %s, got %d", bt.Len, t.String(), len(list)))
+		}
+		av := alloc.NewListArray(bt.Len)
+		for i, ejv := range list {
+			av.List[i] = jsonToTypedValue(alloc, store, ejv, bt.Elt)
+		}
+		tv.V = av
+		return
+	case *SliceType:
+		if jv == nil {
+			return // nil slice
+		}
+		if bt.Elt == Uint8Type {
+			tv.V = alloc.NewSliceFromData(jsonBytes(jv, t))
+			return
+		}
+		list, ok := jv.([]any)
+		if !ok {
+			panic(fmt.Sprintf("expected array for %s, got %s", t.String(), jsonKind(jv)))
+		}
+		elts := make([]TypedValue, len(list))
+		for i, ejv := range list {
+			elts[i] = jsonToTypedValue(alloc, store, ejv, bt.Elt)
+		}
+		tv.V = alloc.NewSliceFromList(elts)
+		return
+	case *StructType:
+		obj, ok := jv.(map[string]any)
+		if !ok {
+			panic(fmt.Sprintf("expected object for %s, got %s", t.String(), jsonKind(jv)))
+		}
+		sv := defaultStructValue(alloc, bt)
+		for key, fjv := range obj {
+			idx := jsonFieldIndex(bt, key)
+			if idx < 0 {
+				panic(fmt.Sprintf("unknown field %q for %s", key, t.String()))
+			}
+			sv.Fields[idx] = jsonToTypedValue(alloc, store, fjv, bt.Fields[idx].Type)
+		}
+		tv.V = sv
+		return
+	case *MapType:
+		if jv == nil {
+			return // nil map
+		}
+		obj, ok := jv.(map[string]any)
+		if !ok {
+			panic(fmt.Sprintf("expected object for %s, got %s", t.String(), jsonKind(jv)))
+		}
+		kbt, ok := baseOf(bt.Key).(PrimitiveType)
+		if !ok {
+			panic(fmt.Sprintf("unsupported key type for %s", t.String()))
+		}
+		mv := alloc.NewMap(len(obj))
+		for _, key := range sortedKeys(obj) {
+			var kjv any = key
+			if kbt != StringType {
+				if kbt == BoolType {
+					kjv = key == "true"
+					if key != "true" && key != "false" {
+						panic(fmt.Sprintf("unexpected bool key %q", key))
+					}
+				} else {
+					kjv = json.Number(key)
+				}
+			}
+			ktv := jsonToTypedValue(alloc, store, kjv, bt.Key)
+			ptr := mv.GetPointerForKey(alloc, store, &ktv)
+			*ptr.TV = jsonToTypedValue(alloc, store, obj[key], bt.Value)
+		}
+		tv.V = mv
+		return
+	case *PointerType:
+		if jv == nil {
+			return // nil pointer
+		}
+		etv := jsonToTypedValue(alloc, store, jv, bt.Elt)
+		hv := alloc.NewHeapItem(etv)
+		tv.V = PointerValue{
+			TV:    &hv.Value,
+			Base:  hv,
+			Index: 0,
+		}
+		return
+	default:
+		panic(fmt.Sprintf("unsupported type %s for JSON value", t.String()))
+	}
+}
+
+func jsonToPrimitive(alloc *Allocator, jv any, t Type, pt PrimitiveType) (tv TypedValue) {
+	tv.T = t
+	switch pt {
+	case BoolType:
+		b, ok := jv.(bool)
+		if !ok {
+			panic(fmt.Sprintf("expected bool for %s, got %s", t.String(), jsonKind(jv)))
+		}
+		tv.SetBool(b)
+		return
+	case StringType:
+		s, ok := jv.(string)
+		if !ok {
+			panic(fmt.Sprintf("expected string for %s, got %s", t.String(), jsonKind(jv)))
+		}
+		tv.V = alloc.NewString(s)
+		return
+	}
+	num, ok := jv.(json.Number)
+	if !ok {
+		panic(fmt.Sprintf("expected number for %s, got %s", t.String(), jsonKind(jv)))
+	}
+	s := string(num)
+	switch pt {
+	case IntType, Int8Type, Int16Type, Int32Type, Int64Type:
+		i, err := strconv.ParseInt(s, 10, jsonBitSize(pt))
+		if err != nil {
+			panic(fmt.Sprintf("error parsing %s %q: %v", t.String(), s, err))
+		}
+		switch pt {
+		case IntType:
+			tv.SetInt(int(i))
+		case Int8Type:
+			tv.SetInt8(int8(i))
+		case Int16Type:
+			tv.SetInt16(int16(i))
+		case Int32Type:
+			tv.SetInt32(int32(i))
+		case Int64Type:
+			tv.SetInt64(i)
+		}
+	case UintType, Uint8Type, Uint16Type, Uint32Type, Uint64Type:
+		u, err := strconv.ParseUint(s, 10, jsonBitSize(pt))
+		if err != nil {
+			panic(fmt.Sprintf("error parsing %s %q: %v", t.String(), s, err))
+		}
+		switch pt {
+		case UintType:
+			tv.SetUint(uint(u))
+		case Uint8Type:
+			tv.SetUint8(uint8(u))
+		case Uint16Type:
+			tv.SetUint16(uint16(u))
+		case Uint32Type:
+			tv.SetUint32(uint32(u))
+		case Uint64Type:
+			tv.SetUint64(u)
+		}
+	case Float32Type:
+		f, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			panic(fmt.Sprintf("error parsing %s %q: %v", t.String(), s, err))
+		}
+		tv.SetFloat32(float32(f))
+	case Float64Type:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			panic(fmt.Sprintf("error parsing %s %q: %v", t.String(), s, err))
+		}
+		tv.SetFloat64(f)
+	default:
+		panic(fmt.Sprintf("unsupported type %s for JSON value", t.String()))
+	}
+	return
+}
+
+func jsonBitSize(pt PrimitiveType) int {
+	switch pt {
+	case Int8Type, Uint8Type:
+		return 8
+	case Int16Type, Uint16Type:
+		return 16
+	case Int32Type, Uint32Type:
+		return 32
+	default:
+		return 64
+	}
+}
+
+func jsonBytes(jv any, t Type) []byte {
+	s, ok := jv.(string)
+	if !ok {
+		panic(fmt.Sprintf("expected base64 string for %s, got %s", t.String(), jsonKind(jv)))
+	}
+	bz, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		panic(fmt.Sprintf("error parsing %s %q: %v", t.String(), s, err))
+	}
+	return bz
+}
+
+// Returns the index of the exported field named key, or -1.
+func jsonFieldIndex(st *StructType, key string) int {
+	for i, f := range st.Fields {
+		if !isUpper(string(f.Name)) {
+			continue
+		}
+		if name := jsonFieldName(f); name != "" && name == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the JSON key of f, or "" if f is tagged `json:"-"`.
+func jsonFieldName(f FieldType) string {
+	tag := reflect.StructTag(f.Tag).Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return string(f.Name)
+}
+
+func jsonKind(jv any) string {
+	switch jv.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		panic("should not happen")
+	}
+}
+
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	// for determinism of map insertion order.
+	slices.Sort(keys)
+	return keys
+}
+
+// MarshalJSONValues encodes tvs as a JSON array.
+// Panics if any value cannot be represented in JSON.
+func MarshalJSONValues(store Store, tvs []TypedValue) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := range tvs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONValue(&buf, store, &tvs[i], nil)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+// seen holds the objects being encoded, to detect cycles.
+func writeJSONValue(buf *bytes.Buffer, store Store, tv *TypedValue, seen []Value) {
+	if tv.T == nil {
+		buf.WriteString("null")
+		return
+	}
+	fillValueTV(store, tv)
+	if obj, ok := tv.V.(Object); ok {
+		for _, v := range seen {
+			if v == obj {
+				panic(fmt.Sprintf("cannot encode cyclic value of type %s", tv.T.String()))
+			}
+		}
+		seen = append(seen, obj)
+	}
+	switch bt := baseOf(tv.T).(type) {
+	case PrimitiveType:
+		writeJSONPrimitive(buf, tv, bt)
+	case *ArrayType:
+		av := tv.V.(*ArrayValue)
+		if bt.Elt == Uint8Type {
+			writeJSONString(buf, base64.StdEncoding.EncodeToString(av.Data))
+			return
+		}
+		buf.WriteByte('[')
+		for i := 0; i < av.GetLength(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONValue(buf, store, &av.List[i], seen)
+		}
+		buf.WriteByte(']')
+	case *SliceType:
+		if tv.V == nil {
+			buf.WriteString("null")
+			return
+		}
+		sv := tv.V.(*SliceValue)
+		av := sv.GetBase(store)
+		if bt.Elt == Uint8Type {
+			data := av.Data[sv.Offset : sv.Offset+sv.Length]
+			writeJSONString(buf, base64.StdEncoding.EncodeToString(data))
+			return
+		}
+		buf.WriteByte('[')
+		for i := 0; i < sv.Length; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONValue(buf, store, &av.List[sv.Offset+i], seen)
+		}
+		buf.WriteByte(']')
+	case *StructType:
+		sv := tv.V.(*StructValue)
+		buf.WriteByte('{')
+		first := true
+		for i, f := range bt.Fields {
+			name := jsonFieldName(f)
+			if !isUpper(string(f.Name)) || name == "" {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			writeJSONString(buf, name)
+			buf.WriteByte(':')
+			writeJSONValue(buf, store, &sv.Fields[i], seen)
+		}
+		buf.WriteByte('}')
+	case *MapType:
+		if tv.V == nil {
+			buf.WriteString("null")
+			return
+		}
+		if _, ok := baseOf(bt.Key).(PrimitiveType); !ok {
+			panic(fmt.Sprintf("unsupported key type for %s", tv.T.String()))
+		}
+		mv := tv.V.(*MapValue)
+		buf.WriteByte('{')
+		for cur := mv.List.Head; cur != nil; cur = cur.Next {
+			if cur != mv.List.Head {
+				buf.WriteByte(',')
+			}
+			var kbuf bytes.Buffer
+			writeJSONPrimitive(&kbuf, &cur.Key, baseOf(bt.Key).(PrimitiveType))
+			key := kbuf.String()
+			if !strings.HasPrefix(key, `"`) {
+				key = strconv.Quote(key)
+			}
+			buf.WriteString(key)
+			buf.WriteByte(':')
+			writeJSONValue(buf, store, &cur.Value, seen)
+		}
+		buf.WriteByte('}')
+	case *PointerType:
+		if tv.V == nil {
+			buf.WriteString("null")
+			return
+		}
+		pv := tv.V.(PointerValue)
+		etv := pv.Deref()
+		writeJSONValue(buf, store, &etv, seen)
+	case *InterfaceType:
+		// tv.T is the concrete type unless nil.
+		buf.WriteString("null")
+	default:
+		panic(fmt.Sprintf("unsupported type %s for JSON value", tv.T.String()))
+	}
+}
+
+func writeJSONPrimitive(buf *bytes.Buffer, tv *TypedValue, pt PrimitiveType) {
+	switch pt {
+	case BoolType, UntypedBoolType:
+		buf.WriteString(strconv.FormatBool(tv.GetBool()))
+	case StringType, UntypedStringType:
+		writeJSONString(buf, tv.GetString())
+	case IntType:
+		buf.WriteString(strconv.FormatInt(int64(tv.GetInt()), 10))
+	case Int8Type:
+		buf.WriteString(strconv.FormatInt(int64(tv.GetInt8()), 10))
+	case Int16Type:
+		buf.WriteString(strconv.FormatInt(int64(tv.GetInt16()), 10))
+	case Int32Type, UntypedRuneType:
+		buf.WriteString(strconv.FormatInt(int64(tv.GetInt32()), 10))
+	case Int64Type:
+		buf.WriteString(strconv.FormatInt(tv.GetInt64(), 10))
+	case UintType:
+		buf.WriteString(strconv.FormatUint(uint64(tv.GetUint()), 10))
+	case Uint8Type:
+		buf.WriteString(strconv.FormatUint(uint64(tv.GetUint8()), 10))
+	case Uint16Type:
+		buf.WriteString(strconv.FormatUint(uint64(tv.GetUint16()), 10))
+	case Uint32Type:
+		buf.WriteString(strconv.FormatUint(uint64(tv.GetUint32()), 10))
+	case Uint64Type:
+		buf.WriteString(strconv.FormatUint(tv.GetUint64(), 10))
+	case Float32Type:
+		writeJSONFloat(buf, float64(tv.GetFloat32()), 32)
+	case Float64Type:
+		writeJSONFloat(buf, tv.GetFloat64(), 64)
+	default:
+		panic(fmt.Sprintf("unsupported type %s for JSON value", pt.String()))
+	}
+}
+
+func writeJSONFloat(buf *bytes.Buffer, f float64, bits int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("unsupported float value %v for JSON value", f))
+	}
+	buf.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	bz, _ := json.Marshal(s)
+	buf.Write(bz)
+}
@@ -0,0 +1,78 @@
+package gnolang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONValues(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(nil, nil, nil)
+	st := &StructType{
+		PkgPath: "main",
+		Fields: []FieldType{
+			{Name: "A", Type: IntType, Tag: `json:"a"`},
+			{Name: "B", Type: &SliceType{Elt: StringType}},
+			{Name: "C", Type: &MapType{Key: Int64Type, Value: BoolType}},
+			{Name: "D", Type: &PointerType{Elt: Float64Type}},
+			{Name: "E", Type: &SliceType{Elt: Uint8Type}},
+			{Name: "F", Type: IntType, Tag: `json:"-"`},
+			{Name: "g", Type: IntType},
+		},
+	}
+	cases := []struct {
+		typ      Type
+		in, want string
+	}{
+		{BoolType, `true`, `[true]`},
+		{Int8Type, `-12`, `[-12]`},
+		{Uint64Type, `18446744073709551615`, `[18446744073709551615]`},
+		{Float32Type, `1.5`, `[1.5]`},
+		{StringType, `"a\"b"`, `["a\"b"]`},
+		{&ArrayType{Len: 2, Elt: IntType}, `[1,2]`, `[[1,2]]`},
+		{&SliceType{Elt: IntType}, `null`, `[null]`},
+		{&MapType{Key: StringType, Value: IntType}, `{"b":2,"a":1}`, `[{"a":1,"b":2}]`},
+		{st, `{"a":1,"B":["x"],"C":{"3":true},"D":2.5,"E":"aGk="}`, `[{"a":1,"B":["x"],"C":{"3":true},"D":2.5,"E":"aGk="}]`},
+		{st, `{}`, `[{"a":0,"B":null,"C":null,"D":null,"E":null}]`},
+	}
+	for _, tc := range cases {
+		tv := UnmarshalJSONValue(nilAllocator, store, []byte(tc.in), tc.typ)
+		got := MarshalJSONValues(store, []TypedValue{tv})
+		assert.Equal(t, tc.want, string(got), "input %s", tc.in)
+	}
+}
+
+func TestJSONValuesInvalid(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(nil, nil, nil)
+	st := &StructType{
+		PkgPath: "main",
+		Fields: []FieldType{
+			{Name: "A", Type: IntType},
+			{Name: "b", Type: IntType},
+		},
+	}
+	cases := []struct {
+		typ Type
+		in  string
+	}{
+		{BoolType, `1`},
+		{Int8Type, `300`},
+		{UintType, `-1`},
+		{IntType, `1.5`},
+		{StringType, `"a" "b"`},
+		{&ArrayType{Len: 2, Elt: IntType}, `[1]`},
+		{&MapType{Key: &SliceType{Elt: IntType}, Value: IntType}, `{}`},
+		{st, `{"b":1}`},
+		{st, `{"C":1}`},
+		{&FuncType{}, `null`},
+	}
+	for _, tc := range cases {
+		assert.Panics(t, func() {
+			UnmarshalJSONValue(nilAllocator, store, []byte(tc.in), tc.typ)
+		}, "input %s", tc.in)
+	}
+}
@@ -1,6 +1,7 @@
 package gnomod
 
 import (
+	"encoding/json"
 	"fmt"
 
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
@@ -28,3 +29,22 @@ func queryChain(remote string, qpath string, data []byte) (res *abci.ResponseQue
 
 	return &qres.Response, nil
 }
+
+// pkgInfo holds the fields of a vm/qpkginfo response used by gno mod.
+type pkgInfo struct {
+	Path       string `json:"path"`
+	Deprecated string `json:"deprecated"`
+	Latest     string `json:"latest"`
+}
+
+func queryPkgInfo(remote string, pkgPath string) (*pkgInfo, error) {
+	res, err := queryChain(remote, queryPathPkgInfo, []byte(pkgPath))
+	if err != nil {
+		return nil, err
+	}
+	info := new(pkgInfo)
+	if err := json.Unmarshal(res.Data, info); err != nil {
+		return nil, fmt.Errorf("unmarshal package info: %w", err)
+	}
+	return info, nil
+}
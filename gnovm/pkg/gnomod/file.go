@@ -208,6 +208,35 @@ func (f *File) FetchDeps(path string, remote string, verbose bool) error {
 	return nil
 }
 
+// ResolveLatest replaces each requirement that has a newer version on the
+// remote chain with the latest non-deprecated version of it. Requirements
+// that are already replaced are left untouched.
+func (f *File) ResolveLatest(remote string, verbose bool) error {
+	for _, r := range f.Require {
+		if _, replaced := isReplaced(r.Mod, f.Replace); replaced {
+			continue
+		}
+		info, err := queryPkgInfo(remote, r.Mod.Path)
+		if err != nil {
+			return fmt.Errorf("query package info (%s): %w", r.Mod.Path, err)
+		}
+		if info.Deprecated != "" {
+			log.Printf("%s is deprecated: %s", r.Mod.Path, info.Deprecated)
+		}
+		if info.Latest == "" || info.Latest == r.Mod.Path {
+			continue
+		}
+		if verbose {
+			log.Println("resolved", r.Mod.Path, "=>", info.Latest)
+		}
+		if err := f.AddReplace(r.Mod.Path, r.Mod.Version, info.Latest, r.Mod.Version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // writes file to the given absolute file path
 func (f *File) Write(fname string) error {
 	f.Syntax.Cleanup()
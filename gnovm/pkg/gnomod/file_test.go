@@ -2,11 +2,18 @@ package gnomod
 
 import (
 	"bytes"
+	"encoding/json"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	"github.com/gnolang/gno/tm2/pkg/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -140,3 +147,52 @@ func TestFetchDeps(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveLatest(t *testing.T) {
+	infos := map[string]string{
+		"gno.land/p/demo/foo":     `{"path":"gno.land/p/demo/foo","successor":"gno.land/p/demo/foo/v2","latest":"gno.land/p/demo/foo/v2"}`,
+		"gno.land/p/demo/bar":     `{"path":"gno.land/p/demo/bar","latest":"gno.land/p/demo/bar"}`,
+		"gno.land/p/demo/baz":     `{"path":"gno.land/p/demo/baz","deprecated":"broken"}`,
+		"gno.land/p/demo/replace": `{"path":"gno.land/p/demo/replace","latest":"gno.land/p/demo/replace/v2"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req types.RPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var params struct {
+			Path string `json:"path"`
+			Data []byte `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params, &params))
+		require.Equal(t, queryPathPkgInfo, params.Path)
+
+		result, err := amino.MarshalJSON(&ctypes.ResultABCIQuery{
+			Response: abci.ResponseQuery{
+				ResponseBase: abci.ResponseBase{Data: []byte(infos[string(params.Data)])},
+			},
+		})
+		require.NoError(t, err)
+		resp, err := json.Marshal(types.RPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+		require.NoError(t, err)
+		w.Write(resp)
+	}))
+	defer srv.Close()
+
+	f, err := Parse("gno.mod", []byte(`module gno.land/p/demo/test
+
+require (
+	gno.land/p/demo/foo v0.0.0-latest
+	gno.land/p/demo/bar v0.0.0-latest
+	gno.land/p/demo/baz v0.0.0-latest
+	gno.land/p/demo/replace v0.0.0-latest
+)
+
+replace gno.land/p/demo/replace => ../replace
+`))
+	require.NoError(t, err)
+	require.NoError(t, f.ResolveLatest(srv.URL, false))
+
+	require.Len(t, f.Replace, 2)
+	assert.Equal(t, module.Version{Path: "gno.land/p/demo/foo", Version: "v0.0.0-latest"}, f.Replace[1].Old)
+	assert.Equal(t, module.Version{Path: "gno.land/p/demo/foo/v2", Version: "v0.0.0-latest"}, f.Replace[1].New)
+	assert.Equal(t, "../replace", f.Replace[0].New.Path)
+}
@@ -17,7 +17,10 @@ import (
 	"golang.org/x/mod/module"
 )
 
-const queryPathFile = "vm/qfile"
+const (
+	queryPathFile    = "vm/qfile"
+	queryPathPkgInfo = "vm/qpkginfo"
+)
 
 // GetGnoModPath returns the path for gno modules
 func GetGnoModPath() string {
@@ -107,7 +110,7 @@ func GnoToGoMod(f File) (*File, error) {
 			// Add dependency with a modified import path
 			f.AddRequire(transpiler.TranspileImportPath(path), f.Require[i].Mod.Version)
 		}
-		f.AddReplace(path, f.Require[i].Mod.Version, filepath.Join(gnoModPath, path), "")
+		f.AddReplace(path, f.Require[i].Mod.Version, filepath.Join(gnoModPath, mod.Path), "")
 		// Remove the old require since the new dependency was added above
 		f.DropRequire(path)
 	}
@@ -0,0 +1,182 @@
+package lsp
+
+import (
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/tests"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const diagnosticSource = "gno"
+
+// diagnose returns the diagnostics of the package in dir, by file path.
+// Every file of the package has an entry, so that fixed issues are cleared.
+//
+// Syntax errors are reported first; if there are none, the package and its
+// test files are preprocessed in the same way as "gno lint" does, which
+// reports the first type error of the package.
+func (s *Server) diagnose(dir string) map[string][]Diagnostic {
+	files := s.pkgFiles(dir, true)
+	diags := make(map[string][]Diagnostic, len(files))
+	texts := make(map[string]string, len(files))
+	for _, path := range files {
+		diags[path] = []Diagnostic{}
+		texts[path], _ = s.fileContent(path)
+	}
+	if len(files) == 0 {
+		return diags
+	}
+
+	add := func(path string, line, col int, severity DiagnosticSeverity, msg string) {
+		if _, ok := diags[path]; !ok {
+			path = files[0]
+			line, col = 0, 0
+		}
+		diags[path] = append(diags[path], diagnostic(texts[path], line, col, severity, msg))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "gno.mod")); os.IsNotExist(err) {
+		add(files[0], 0, 0, SeverityWarning, "missing 'gno.mod' file")
+	}
+
+	// Syntax errors.
+	fset := token.NewFileSet()
+	var hasSyntaxErrors bool
+	for _, path := range files {
+		_, err := parser.ParseFile(fset, path, texts[path], parser.AllErrors)
+		if errs, ok := err.(scanner.ErrorList); ok {
+			hasSyntaxErrors = true
+			for _, e := range errs {
+				add(path, e.Pos.Line, e.Pos.Column, SeverityError, e.Msg)
+			}
+		}
+	}
+	if hasSyntaxErrors {
+		return diags
+	}
+
+	// Preprocessing errors.
+	memPkg := &std.MemPackage{Path: s.pkgPath(dir)}
+	for _, path := range files {
+		memPkg.Files = append(memPkg.Files, &std.MemFile{
+			Name: filepath.Base(path),
+			Body: texts[path],
+		})
+	}
+	memPkg.Name = string(gno.PackageNameFromFileBody(memPkg.Files[0].Name, memPkg.Files[0].Body))
+	memPkg.Name = strings.TrimSuffix(memPkg.Name, "_test")
+
+	if err := s.preprocess(memPkg); err != nil {
+		file, line, col, msg := parseErrorLocation(err.Error())
+		add(filepath.Join(dir, file), line, col, SeverityError, msg)
+	}
+	return diags
+}
+
+// preprocess runs the package and its test files through the Gno
+// preprocessor, and returns the error it panicked with, if any.
+func (s *Server) preprocess(memPkg *std.MemPackage) (err error) {
+	defer func() {
+		r := recover()
+		switch verr := r.(type) {
+		case nil:
+		case *gno.PreprocessError:
+			err = verr.Unwrap()
+		case scanner.ErrorList:
+			err = verr.Err()
+		case error:
+			err = verr
+		default:
+			err = fmt.Errorf("%v", verr)
+		}
+	}()
+
+	store := tests.TestStore(s.rootDir, "", strings.NewReader(""), io.Discard, io.Discard, tests.ImportModeStdlibsOnly)
+	m := tests.TestMachine(store, io.Discard, memPkg.Name)
+	defer m.Release()
+
+	m.RunMemPackage(memPkg, true)
+
+	testFiles := &gno.FileSet{}
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, "_test.gno") {
+			continue
+		}
+		n, _ := gno.ParseFile(mfile.Name, mfile.Body)
+		// XXX: package ending with `_test` is not supported yet
+		if n != nil && !strings.HasSuffix(string(n.PkgName), "_test") {
+			testFiles.AddFiles(n)
+		}
+	}
+	m.RunFiles(testFiles.Files...)
+	return nil
+}
+
+// reErrorLocation extracts the file, line, column and message of an error
+// returned by the preprocessor, such as "foo.gno:12:3: undefined: x".
+var reErrorLocation = regexp.MustCompile(`^([^:\s]+\.gno):(\d+)(?::(\d+))?:? *((?s).*)$`)
+
+func parseErrorLocation(s string) (file string, line, col int, msg string) {
+	s = strings.TrimSpace(s)
+	matches := reErrorLocation.FindStringSubmatch(s)
+	if matches == nil {
+		return "", 0, 0, s
+	}
+	line, _ = strconv.Atoi(matches[2])
+	col, _ = strconv.Atoi(matches[3])
+	return filepath.Base(matches[1]), line, col, strings.TrimSpace(matches[4])
+}
+
+// diagnostic returns a diagnostic for the 1-based line and byte column of
+// text. A zero line or column highlights the whole line, or the whole first
+// line.
+func diagnostic(text string, line, col int, severity DiagnosticSeverity, msg string) Diagnostic {
+	line = max(line, 1)
+
+	lineStart := 0
+	for i := 1; i < line; i++ {
+		j := strings.IndexByte(text[lineStart:], '\n')
+		if j < 0 {
+			break
+		}
+		lineStart += j + 1
+	}
+	lineEnd := len(text)
+	if j := strings.IndexByte(text[lineStart:], '\n'); j >= 0 {
+		lineEnd = lineStart + j
+	}
+
+	start, end := lineStart, lineEnd
+	if col > 0 {
+		start = min(lineStart+col-1, lineEnd)
+		end = start
+		// Highlight the identifier at the position, if any.
+		for end < lineEnd && isIdentByte(text[end]) {
+			end++
+		}
+	}
+
+	return Diagnostic{
+		Range: Range{
+			Start: positionAt(text, start),
+			End:   positionAt(text, end),
+		},
+		Severity: severity,
+		Source:   diagnosticSource,
+		Message:  msg,
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
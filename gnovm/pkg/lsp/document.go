@@ -0,0 +1,116 @@
+package lsp
+
+import (
+	"fmt"
+	"go/token"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// document is a file opened in the editor. Its content takes precedence over
+// the content of the file on disk.
+type document struct {
+	uri     string
+	path    string // absolute path on the file system
+	version int
+	text    string
+}
+
+// uriToPath converts a file:// URI to a file system path.
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid uri %q: %w", uri, err)
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported uri scheme %q", u.Scheme)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+// pathToURI converts an absolute file system path to a file:// URI.
+func pathToURI(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
+
+// offsetAt returns the byte offset in text of the LSP position pos.
+// Positions past the end of a line, or of the text, are clamped.
+func offsetAt(text string, pos Position) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		i := strings.IndexByte(text[offset:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		offset += i + 1
+	}
+
+	for char := 0; char < pos.Character && offset < len(text); {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r == '\n' {
+			break
+		}
+		char += utf16Len(r)
+		offset += size
+	}
+	return offset
+}
+
+// positionAt returns the LSP position of the byte offset in text.
+func positionAt(text string, offset int) Position {
+	offset = min(max(offset, 0), len(text))
+
+	var pos Position
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	pos.Line = strings.Count(text[:lineStart], "\n")
+	for _, r := range text[lineStart:offset] {
+		pos.Character += utf16Len(r)
+	}
+	return pos
+}
+
+// fileContent returns the content of the file at path, using the opened
+// document if there is one.
+func (s *Server) fileContent(path string) (string, error) {
+	if doc, ok := s.docs[path]; ok {
+		return doc.text, nil
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
+// location returns the LSP location of the range [start, end) of fset.
+func (s *Server) location(fset *token.FileSet, start, end token.Pos) (Location, bool) {
+	file := fset.File(start)
+	if file == nil {
+		return Location{}, false
+	}
+	text, err := s.fileContent(file.Name())
+	if err != nil {
+		return Location{}, false
+	}
+
+	return Location{
+		URI: pathToURI(file.Name()),
+		Range: Range{
+			Start: positionAt(text, file.Offset(start)),
+			End:   positionAt(text, file.Offset(end)),
+		},
+	}, true
+}
+
+// utf16Len returns the number of UTF-16 code units needed to encode r.
+func utf16Len(r rune) int {
+	if r1, _ := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,118 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnofmt"
+	"github.com/gnolang/gno/gnovm/pkg/gnomod"
+)
+
+// formatting formats the document and fixes its imports with gnofmt, in the
+// same way as "gno fmt". The whole document is replaced by a single edit.
+func (s *Server) formatting(params json.RawMessage) (any, error) {
+	var p DocumentFormattingParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	doc, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(doc.path)
+	pkg := &overlayPackage{
+		s:     s,
+		path:  s.pkgPath(dir),
+		files: s.pkgFiles(dir, true),
+	}
+	for _, file := range pkg.files {
+		if !strings.HasSuffix(file, "_test.gno") {
+			text, _ := s.fileContent(file)
+			if f, _ := parser.ParseFile(token.NewFileSet(), file, text, parser.PackageClauseOnly); f != nil && f.Name != nil {
+				pkg.name = f.Name.Name
+			}
+			break
+		}
+	}
+
+	// The processor caches the parsed packages, so a new one is needed
+	// for each request; the resolver only indexes the package names.
+	processor := gnofmt.NewProcessor(s.fmtResolver(dir))
+	out, err := processor.FormatPackageFile(pkg, doc.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to format %s: %w", filepath.Base(doc.path), err)
+	}
+
+	formatted := string(out)
+	if formatted == doc.text {
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{
+		Range: Range{
+			Start: Position{},
+			End:   positionAt(doc.text, len(doc.text)),
+		},
+		NewText: formatted,
+	}}, nil
+}
+
+// fmtResolver returns the resolver used to fix the imports. It indexes the
+// standard libraries, the examples and the module of dir, the first time it
+// is used.
+func (s *Server) fmtResolver(dir string) *gnofmt.FSResolver {
+	if s.resolver == nil {
+		s.resolver = gnofmt.NewFSResolver()
+		handler := func(path string, err error) error {
+			if err != nil {
+				s.logger.Warn("unable to load package", "path", path, "err", err)
+			}
+			return nil
+		}
+		for _, root := range []string{
+			filepath.Join(s.rootDir, "gnovm", "stdlibs"),
+			filepath.Join(s.rootDir, "examples"),
+		} {
+			if err := s.resolver.LoadPackages(root, handler); err != nil {
+				s.logger.Warn("unable to load packages", "root", root, "err", err)
+			}
+		}
+	}
+
+	// Also index the module of dir; the resolver skips the directories which
+	// were already loaded.
+	if root, err := gnomod.FindRootDir(dir); err == nil {
+		if err := s.resolver.LoadPackages(root, nil); err != nil {
+			s.logger.Warn("unable to load packages", "root", root, "err", err)
+		}
+	}
+	return s.resolver
+}
+
+var _ gnofmt.Package = (*overlayPackage)(nil)
+
+// overlayPackage is a gnofmt.Package whose files are read from the opened
+// documents if there are some, and from the file system otherwise.
+type overlayPackage struct {
+	s     *Server
+	path  string
+	name  string
+	files []string // absolute paths
+}
+
+func (p *overlayPackage) Path() string    { return p.path }
+func (p *overlayPackage) Name() string    { return p.name }
+func (p *overlayPackage) Files() []string { return p.files }
+
+func (p *overlayPackage) Read(filename string) (io.ReadCloser, error) {
+	text, err := p.s.fileContent(filename)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(text)), nil
+}
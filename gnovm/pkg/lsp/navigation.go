@@ -0,0 +1,357 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// identAt returns the identifier of the document at pos, along with the
+// type-checked package of the document.
+func (s *Server) identAt(tdp TextDocumentPositionParams) (*checkedPackage, *ast.Ident, error) {
+	doc, err := s.document(tdp.TextDocument.URI)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dir := filepath.Dir(doc.path)
+	cp := s.checkPackage(dir, s.pkgPath(dir), true)
+	f := cp.file(s.fset, doc.path)
+	if f == nil {
+		return cp, nil, nil
+	}
+
+	pos := s.fset.File(f.Pos()).Pos(offsetAt(doc.text, tdp.Position))
+	var ident *ast.Ident
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil || ident != nil || pos < n.Pos() || pos > n.End() {
+			return false
+		}
+		if id, ok := n.(*ast.Ident); ok {
+			ident = id
+		}
+		return true
+	})
+	return cp, ident, nil
+}
+
+// objectOf returns the object defined or used by ident.
+func objectOf(cp *checkedPackage, ident *ast.Ident) types.Object {
+	if ident == nil {
+		return nil
+	}
+	if obj := cp.info.Uses[ident]; obj != nil {
+		return obj
+	}
+	return cp.info.Defs[ident]
+}
+
+func (s *Server) hover(params json.RawMessage) (any, error) {
+	var p TextDocumentPositionParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	cp, ident, err := s.identAt(p)
+	if err != nil {
+		return nil, err
+	}
+	obj := objectOf(cp, ident)
+	if obj == nil {
+		return nil, nil
+	}
+
+	value := fmt.Sprintf("```gno\n%s\n```", objectString(obj, cp.pkg))
+	if doc := s.objectDoc(cp, obj); doc != "" {
+		value += "\n\n" + doc
+	}
+
+	h := Hover{Contents: MarkupContent{Kind: "markdown", Value: value}}
+	if loc, ok := s.location(s.fset, ident.Pos(), ident.End()); ok {
+		h.Range = &loc.Range
+	}
+	return h, nil
+}
+
+func (s *Server) definition(params json.RawMessage) (any, error) {
+	var p TextDocumentPositionParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	cp, ident, err := s.identAt(p)
+	if err != nil {
+		return nil, err
+	}
+	obj := objectOf(cp, ident)
+	if obj == nil {
+		return nil, nil
+	}
+
+	if pkgName, ok := obj.(*types.PkgName); ok {
+		// Go to the package clause of the imported package.
+		imp := s.importedPackage(pkgName.Imported())
+		if imp == nil || len(imp.files) == 0 {
+			return nil, nil
+		}
+		f := imp.files[0]
+		for _, file := range imp.files {
+			if file.Doc != nil {
+				f = file
+				break
+			}
+		}
+		if loc, ok := s.location(s.fset, f.Name.Pos(), f.Name.End()); ok {
+			return []Location{loc}, nil
+		}
+		return nil, nil
+	}
+
+	// Objects of the universe block have no position.
+	if !obj.Pos().IsValid() {
+		return nil, nil
+	}
+	if loc, ok := s.location(s.fset, obj.Pos(), obj.Pos()+token.Pos(len(obj.Name()))); ok {
+		return []Location{loc}, nil
+	}
+	return nil, nil
+}
+
+// completion completes the members of a package, or the fields and methods
+// of a value, after a selector: "pkg.Fo" lists the exported members of pkg
+// starting with "Fo".
+func (s *Server) completion(params json.RawMessage) (any, error) {
+	var p TextDocumentPositionParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	doc, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	list := CompletionList{Items: []CompletionItem{}}
+
+	text := doc.text
+	offset := offsetAt(text, p.Position)
+	start := offset
+	for start > 0 && isIdentByte(text[start-1]) {
+		start--
+	}
+	prefix := text[start:offset]
+	if start == 0 || text[start-1] != '.' {
+		return list, nil
+	}
+	xEnd := start - 1
+	xStart := xEnd
+	for xStart > 0 && isIdentByte(text[xStart-1]) {
+		xStart--
+	}
+	if xStart == xEnd {
+		return list, nil
+	}
+
+	// Complete the selector with a placeholder, so that it can be parsed.
+	if prefix == "" {
+		doc.text = text[:offset] + "_" + text[offset:]
+		defer func() { doc.text = text }()
+	}
+	cp, ident, err := s.identAt(TextDocumentPositionParams{
+		TextDocument: p.TextDocument,
+		Position:     positionAt(text, xStart),
+	})
+	if err != nil {
+		return nil, err
+	}
+	obj := objectOf(cp, ident)
+	if obj == nil {
+		return list, nil
+	}
+
+	add := func(member types.Object) {
+		if !strings.HasPrefix(member.Name(), prefix) || member.Name() == "_" {
+			return
+		}
+		if !member.Exported() && member.Pkg() != cp.pkg {
+			return
+		}
+		item := CompletionItem{
+			Label:  member.Name(),
+			Kind:   completionKind(member),
+			Detail: objectString(member, cp.pkg),
+		}
+		if doc := s.objectDoc(cp, member); doc != "" {
+			item.Documentation = &MarkupContent{Kind: "markdown", Value: doc}
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	switch obj := obj.(type) {
+	case *types.PkgName:
+		scope := obj.Imported().Scope()
+		for _, name := range scope.Names() {
+			add(scope.Lookup(name))
+		}
+	case *types.Var, *types.Const:
+		typ := obj.Type()
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		if st, ok := typ.Underlying().(*types.Struct); ok {
+			for i := 0; i < st.NumFields(); i++ {
+				add(st.Field(i))
+			}
+		}
+		for _, sel := range typeutil.IntuitiveMethodSet(obj.Type(), nil) {
+			add(sel.Obj())
+		}
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Label < list.Items[j].Label
+	})
+	return list, nil
+}
+
+func completionKind(obj types.Object) CompletionItemKind {
+	switch obj := obj.(type) {
+	case *types.Func:
+		if obj.Type().(*types.Signature).Recv() != nil {
+			return CompletionMethod
+		}
+		return CompletionFunction
+	case *types.Var:
+		if obj.IsField() {
+			return CompletionField
+		}
+		return CompletionVariable
+	case *types.Const:
+		return CompletionConstant
+	case *types.TypeName:
+		switch obj.Type().Underlying().(type) {
+		case *types.Struct:
+			return CompletionStruct
+		case *types.Interface:
+			return CompletionInterface
+		}
+		return CompletionClass
+	case *types.PkgName:
+		return CompletionModule
+	}
+	return CompletionVariable
+}
+
+// objectString returns the declaration of obj, with the identifiers of other
+// packages qualified by their package name.
+func objectString(obj types.Object, from *types.Package) string {
+	if pkgName, ok := obj.(*types.PkgName); ok {
+		return fmt.Sprintf("package %s (%q)", pkgName.Imported().Name(), pkgName.Imported().Path())
+	}
+	return types.ObjectString(obj, func(pkg *types.Package) string {
+		if pkg == from {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// importedPackage returns the checked package of the imported pkg.
+func (s *Server) importedPackage(pkg *types.Package) *checkedPackage {
+	for _, cp := range s.imported {
+		if cp != nil && cp.pkg == pkg {
+			return cp
+		}
+	}
+	return nil
+}
+
+// astFile returns the parsed file containing pos, among the files of cp and
+// of the imported packages.
+func (s *Server) astFile(cp *checkedPackage, pos token.Pos) *ast.File {
+	tf := s.fset.File(pos)
+	if tf == nil {
+		return nil
+	}
+
+	pkgs := []*checkedPackage{cp, s.imported[filepath.Dir(tf.Name())]}
+	for _, pkg := range pkgs {
+		if pkg == nil {
+			continue
+		}
+		for _, f := range pkg.files {
+			if f.FileStart <= pos && pos <= f.FileEnd {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// objectDoc returns the documentation of obj, used from cp, formatted as
+// markdown.
+func (s *Server) objectDoc(cp *checkedPackage, obj types.Object) string {
+	var text string
+	if pkgName, ok := obj.(*types.PkgName); ok {
+		if imp := s.importedPackage(pkgName.Imported()); imp != nil {
+			for _, f := range imp.files {
+				if f.Doc != nil {
+					text = f.Doc.Text()
+					break
+				}
+			}
+		}
+	} else if f := s.astFile(cp, obj.Pos()); f != nil {
+		text = declDoc(f, obj.Pos())
+	}
+
+	if text == "" {
+		return ""
+	}
+	var p comment.Parser
+	var pr comment.Printer
+	return strings.TrimSpace(string(pr.Markdown(p.Parse(text))))
+}
+
+// declDoc returns the doc comment of the declaration of the identifier at pos.
+func declDoc(f *ast.File, pos token.Pos) string {
+	path, _ := astutil.PathEnclosingInterval(f, pos, pos)
+	for i, n := range path {
+		var doc, lineComment *ast.CommentGroup
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			doc = n.Doc
+		case *ast.Field:
+			doc, lineComment = n.Doc, n.Comment
+		case *ast.ValueSpec:
+			doc, lineComment = n.Doc, n.Comment
+		case *ast.TypeSpec:
+			doc, lineComment = n.Doc, n.Comment
+		case ast.Stmt, *ast.FuncLit:
+			// Local declarations are not documented.
+			return ""
+		default:
+			continue
+		}
+
+		switch {
+		case doc != nil:
+			return doc.Text()
+		case lineComment != nil:
+			return lineComment.Text()
+		}
+		// Fall back to the comment of the declaration group.
+		if i+1 < len(path) {
+			if gd, ok := path[i+1].(*ast.GenDecl); ok && gd.Doc != nil {
+				return gd.Doc.Text()
+			}
+		}
+		return ""
+	}
+	return ""
+}
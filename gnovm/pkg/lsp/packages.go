@@ -0,0 +1,240 @@
+package lsp
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnomod"
+	"golang.org/x/mod/modfile"
+)
+
+// pkgFiles returns the absolute paths of the .gno files of the package in
+// dir, including the opened documents which are not yet saved on disk.
+// Test files are only included if withTests is true; filetests never are.
+func (s *Server) pkgFiles(dir string, withTests bool) []string {
+	seen := map[string]bool{}
+	var files []string
+	add := func(path string) {
+		name := filepath.Base(path)
+		switch {
+		case seen[path],
+			!strings.HasSuffix(name, ".gno"),
+			strings.HasSuffix(name, "_filetest.gno"),
+			!withTests && strings.HasSuffix(name, "_test.gno"):
+			return
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			add(filepath.Join(dir, entry.Name()))
+		}
+	}
+	for path := range s.docs {
+		if filepath.Dir(path) == dir {
+			add(path)
+		}
+	}
+
+	sort.Strings(files)
+	return files
+}
+
+// pkgPath returns the import path of the package in dir, as defined by the
+// closest gno.mod file. It falls back to dir, like "gno lint".
+func (s *Server) pkgPath(dir string) string {
+	root, err := gnomod.FindRootDir(dir)
+	if err != nil {
+		return dir
+	}
+	gm, err := gnomod.ParseGnoMod(filepath.Join(root, "gno.mod"))
+	if err != nil || gm.Module == nil {
+		return dir
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return gm.Module.Mod.Path
+	}
+	return gm.Module.Mod.Path + "/" + filepath.ToSlash(rel)
+}
+
+// pkgDir resolves the import path of a package imported from the package in
+// fromDir to its directory. Import paths are looked up, in order, in the
+// standard libraries, in the module of fromDir and its gno.mod requirements,
+// and in the examples directory.
+func (s *Server) pkgDir(fromDir, path string) (string, bool) {
+	isPkgDir := func(dir string) bool {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return false
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".gno") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if dir := filepath.Join(s.rootDir, "gnovm", "stdlibs", filepath.FromSlash(path)); isPkgDir(dir) {
+		return dir, true
+	}
+
+	if root, err := gnomod.FindRootDir(fromDir); err == nil {
+		if dir, ok := modulePkgDir(root, path); ok && isPkgDir(dir) {
+			return dir, true
+		}
+	}
+
+	if dir := filepath.Join(s.rootDir, "examples", filepath.FromSlash(path)); isPkgDir(dir) {
+		return dir, true
+	}
+
+	return "", false
+}
+
+// modulePkgDir resolves path using the gno.mod file in root: either path is
+// part of the module itself, or it is part of one of its requirements, which
+// are downloaded by "gno mod download" or replaced with a local directory.
+func modulePkgDir(root, path string) (string, bool) {
+	gm, err := gnomod.ParseGnoMod(filepath.Join(root, "gno.mod"))
+	if err != nil || gm.Module == nil {
+		return "", false
+	}
+
+	if rel, ok := trimPathPrefix(path, gm.Module.Mod.Path); ok {
+		return filepath.Join(root, filepath.FromSlash(rel)), true
+	}
+
+	for _, req := range gm.Require {
+		rel, ok := trimPathPrefix(path, req.Mod.Path)
+		if !ok {
+			continue
+		}
+		mv := gm.Resolve(req)
+		if modfile.IsDirectoryPath(mv.Path) {
+			dir := mv.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(root, dir)
+			}
+			return filepath.Join(dir, filepath.FromSlash(rel)), true
+		}
+		return filepath.Join(gnomod.PackageDir("", mv), filepath.FromSlash(rel)), true
+	}
+
+	return "", false
+}
+
+// trimPathPrefix returns the path of path relative to prefix, if path is
+// prefix or one of its sub-packages.
+func trimPathPrefix(path, prefix string) (string, bool) {
+	if path == prefix {
+		return "", true
+	}
+	if strings.HasPrefix(path, prefix+"/") {
+		return path[len(prefix)+1:], true
+	}
+	return "", false
+}
+
+// checkedPackage is a package parsed and type-checked with go/types, used to
+// resolve identifiers for hover, definition and completion requests.
+type checkedPackage struct {
+	dir   string
+	files []*ast.File
+	pkg   *types.Package
+	info  *types.Info
+}
+
+// file returns the parsed file at path.
+func (p *checkedPackage) file(fset *token.FileSet, path string) *ast.File {
+	for _, f := range p.files {
+		if fset.File(f.Pos()).Name() == path {
+			return f
+		}
+	}
+	return nil
+}
+
+// checkPackage parses and type-checks the package in dir, whose import path
+// is pkgPath, including its test files if withTests is true.
+//
+// Type errors are ignored: go/types doesn't know about some of the Gno
+// builtins, and the diagnostics are instead provided by the Gno preprocessor.
+// The information collected is nonetheless complete enough to resolve
+// identifiers.
+func (s *Server) checkPackage(dir, pkgPath string, withTests bool) *checkedPackage {
+	cp := &checkedPackage{
+		dir: dir,
+		info: &types.Info{
+			Types:  map[ast.Expr]types.TypeAndValue{},
+			Defs:   map[*ast.Ident]types.Object{},
+			Uses:   map[*ast.Ident]types.Object{},
+			Scopes: map[ast.Node]*types.Scope{},
+		},
+	}
+
+	for _, path := range s.pkgFiles(dir, withTests) {
+		text, err := s.fileContent(path)
+		if err != nil {
+			continue
+		}
+		// Parse errors are reported by the diagnostics, keep the partial AST.
+		f, _ := parser.ParseFile(s.fset, path, text, parser.ParseComments|parser.AllErrors)
+		if f == nil || f.Name == nil {
+			continue
+		}
+		// Black-box test files belong to another package.
+		if strings.HasSuffix(f.Name.Name, "_test") {
+			continue
+		}
+		cp.files = append(cp.files, f)
+	}
+
+	conf := &types.Config{
+		Importer: &importer{s: s},
+		Error:    func(error) {},
+	}
+	cp.pkg, _ = conf.Check(pkgPath, s.fset, cp.files, cp.info)
+	return cp
+}
+
+// importer imports packages for go/types, using Server.pkgDir to find them.
+// Imported packages are cached by the server until a document is changed.
+type importer struct {
+	s *Server
+}
+
+var errImportNotFound = errors.New("import not found")
+
+func (imp *importer) Import(path string) (*types.Package, error) {
+	return imp.ImportFrom(path, "", 0)
+}
+
+func (imp *importer) ImportFrom(path, fromDir string, _ types.ImportMode) (*types.Package, error) {
+	dir, ok := imp.s.pkgDir(fromDir, path)
+	if !ok {
+		return nil, errImportNotFound
+	}
+	if cp, ok := imp.s.imported[dir]; ok {
+		if cp == nil {
+			return nil, errors.New("import cycle")
+		}
+		return cp.pkg, nil
+	}
+
+	imp.s.imported[dir] = nil // mark as in progress to detect cycles
+	cp := imp.s.checkPackage(dir, path, false)
+	imp.s.imported[dir] = cp
+	return cp.pkg, nil
+}
@@ -0,0 +1,341 @@
+// Package lsp implements a Language Server Protocol server for Gno, which
+// provides editors with diagnostics, hover documentation, go-to-definition,
+// completion of package members and formatting for .gno files.
+//
+// Diagnostics come from the Gno parser and preprocessor, in the same way as
+// "gno lint"; identifiers are resolved with go/types, and formatting uses
+// [gnofmt], which also fixes the imports.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sort"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnofmt"
+)
+
+// ServerName is the name reported by the server to the clients.
+const ServerName = "gnopls"
+
+// Config is the configuration of a [Server].
+type Config struct {
+	// RootDir is the clone location of github.com/gnolang/gno, where the
+	// standard libraries and the examples are looked up.
+	RootDir string
+	// Logger logs the activity of the server. If nil, nothing is logged.
+	Logger *slog.Logger
+}
+
+// Server is a language server for Gno. It serves a single client.
+type Server struct {
+	rootDir string
+	logger  *slog.Logger
+	conn    *conn
+
+	initialized bool
+	shutdown    bool
+
+	docs     map[string]*document       // opened documents, by file path
+	fset     *token.FileSet             // positions of the type-checked files
+	imported map[string]*checkedPackage // type-checked imports, by directory
+	resolver *gnofmt.FSResolver         // packages used to fix the imports; lazily loaded
+}
+
+// NewServer returns a new language server.
+func NewServer(cfg Config) *Server {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return &Server{
+		rootDir:  cfg.RootDir,
+		logger:   logger,
+		docs:     map[string]*document{},
+		fset:     token.NewFileSet(),
+		imported: map[string]*checkedPackage{},
+	}
+}
+
+// errExitWithoutShutdown is returned by Serve when the client asks the server
+// to exit without asking it to shut down first.
+var errExitWithoutShutdown = errors.New("exit notification received before shutdown request")
+
+// Serve reads requests from r and writes the responses to w until the
+// client asks the server to exit, the input is closed, or ctx is done.
+// Requests are handled sequentially.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+
+	msgs := make(chan *message)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(msgs)
+		for {
+			msg, err := s.conn.read()
+			if err != nil {
+				var rerr *rpcError
+				if errors.As(err, &rerr) {
+					// The message can't be answered, as its id is unknown.
+					s.logger.Error("invalid message", "err", err)
+					continue
+				}
+				errc <- err
+				return
+			}
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-msgs:
+			if !ok {
+				err := <-errc
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			if msg.Method == "exit" {
+				if !s.shutdown {
+					return errExitWithoutShutdown
+				}
+				return nil
+			}
+			if err := s.handle(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handlerFunc handles the params of a request or notification, and returns
+// the result of requests.
+type handlerFunc func(s *Server, params json.RawMessage) (any, error)
+
+var handlers = map[string]handlerFunc{
+	"initialize":              (*Server).initialize,
+	"initialized":             noop,
+	"shutdown":                (*Server).shutdownRequest,
+	"textDocument/didOpen":    (*Server).didOpen,
+	"textDocument/didChange":  (*Server).didChange,
+	"textDocument/didSave":    (*Server).didSave,
+	"textDocument/didClose":   (*Server).didClose,
+	"textDocument/hover":      (*Server).hover,
+	"textDocument/definition": (*Server).definition,
+	"textDocument/completion": (*Server).completion,
+	"textDocument/formatting": (*Server).formatting,
+}
+
+func noop(*Server, json.RawMessage) (any, error) { return nil, nil }
+
+// handle handles a single message. It only returns an error if the response
+// couldn't be written.
+func (s *Server) handle(msg *message) error {
+	s.logger.Debug("handling message", "method", msg.Method)
+
+	handler, ok := handlers[msg.Method]
+	var (
+		result any
+		err    error
+	)
+	switch {
+	case !ok:
+		// Notifications which are not handled, such as "$/cancelRequest",
+		// are ignored.
+		err = newRPCError(codeMethodNotFound, "method not found: %s", msg.Method)
+	case !s.initialized && msg.Method != "initialize":
+		err = newRPCError(codeServerNotInitialized, "server not initialized")
+	case s.shutdown:
+		err = newRPCError(codeInvalidRequest, "server is shutting down")
+	default:
+		result, err = s.call(handler, msg.Params)
+	}
+
+	if !msg.isRequest() {
+		if err != nil && ok {
+			s.logger.Error("notification failed", "method", msg.Method, "err", err)
+		}
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("request failed", "method", msg.Method, "err", err)
+	}
+	return s.conn.reply(msg.ID, result, err)
+}
+
+// call calls handler, turning panics into errors so that a bug in the
+// handling of a request doesn't bring the server down.
+func (s *Server) call(handler handlerFunc, params json.RawMessage) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newRPCError(codeInternalError, "internal error: %v", r)
+		}
+	}()
+	return handler(s, params)
+}
+
+func unmarshalParams(params json.RawMessage, v any) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return newRPCError(codeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var p InitializeParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	s.initialized = true
+
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    SyncFull,
+				Save:      SaveOptions{},
+			},
+			HoverProvider:      true,
+			DefinitionProvider: true,
+			CompletionProvider: CompletionOptions{
+				TriggerCharacters: []string{"."},
+			},
+			DocumentFormattingProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: ServerName},
+	}, nil
+}
+
+func (s *Server) shutdownRequest(json.RawMessage) (any, error) {
+	s.shutdown = true
+	return nil, nil
+}
+
+func (s *Server) didOpen(params json.RawMessage) (any, error) {
+	var p DidOpenTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	path, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	s.docs[path] = &document{
+		uri:     p.TextDocument.URI,
+		path:    path,
+		version: p.TextDocument.Version,
+		text:    p.TextDocument.Text,
+	}
+	return nil, s.publishDiagnostics(filepath.Dir(path))
+}
+
+// didChange updates the content of the document. The diagnostics are only
+// published again when the document is saved, as preprocessing a package
+// is too slow to be done on each keystroke.
+func (s *Server) didChange(params json.RawMessage) (any, error) {
+	var p DidChangeTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	doc, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.ContentChanges) == 0 {
+		return nil, nil
+	}
+
+	doc.version = p.TextDocument.Version
+	doc.text = p.ContentChanges[len(p.ContentChanges)-1].Text
+	s.invalidate(filepath.Dir(doc.path))
+	return nil, nil
+}
+
+func (s *Server) didSave(params json.RawMessage) (any, error) {
+	var p DidSaveTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	path, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(filepath.Dir(path))
+	return nil, s.publishDiagnostics(filepath.Dir(path))
+}
+
+func (s *Server) didClose(params json.RawMessage) (any, error) {
+	var p DidCloseTextDocumentParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	path, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.docs, path)
+	s.invalidate(filepath.Dir(path))
+	return nil, nil
+}
+
+// document returns the opened document with the given uri.
+func (s *Server) document(uri string) (*document, error) {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := s.docs[path]
+	if !ok {
+		return nil, newRPCError(codeInvalidParams, "document not opened: %s", uri)
+	}
+	return doc, nil
+}
+
+// invalidate drops the cached type information after a change in the
+// package in dir. As the packages importing it would have to be checked
+// again too, the whole cache is dropped if dir is one of the imports.
+func (s *Server) invalidate(dir string) {
+	if _, ok := s.imported[dir]; ok {
+		s.imported = map[string]*checkedPackage{}
+		s.fset = token.NewFileSet()
+	}
+}
+
+// publishDiagnostics sends the diagnostics of the package in dir.
+func (s *Server) publishDiagnostics(dir string) error {
+	diags := s.diagnose(dir)
+	paths := make([]string, 0, len(diags))
+	for path := range diags {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		err := s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         pathToURI(path),
+			Diagnostics: diags[path],
+		})
+		if err != nil {
+			return fmt.Errorf("unable to publish diagnostics: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,497 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient is a client of a Server running in the background.
+type testClient struct {
+	t      *testing.T
+	conn   *conn
+	nextID int
+
+	responses     chan *testResponse
+	notifications chan *testResponse
+	done          chan error
+}
+
+type testResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+func newTestClient(t *testing.T) *testClient {
+	t.Helper()
+
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	c := &testClient{
+		t:             t,
+		conn:          newConn(clientR, clientW),
+		responses:     make(chan *testResponse, 16),
+		notifications: make(chan *testResponse, 64),
+		done:          make(chan error, 1),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	srv := NewServer(Config{RootDir: gnoenv.RootDir()})
+	go func() {
+		c.done <- srv.Serve(ctx, serverR, serverW)
+		serverW.Close()
+	}()
+
+	go func() {
+		defer close(c.responses)
+		for {
+			body, err := c.conn.readBody()
+			if err != nil {
+				return
+			}
+			res := &testResponse{}
+			if err := json.Unmarshal(body, res); err != nil {
+				return
+			}
+			if res.Method != "" {
+				c.notifications <- res
+			} else {
+				c.responses <- res
+			}
+		}
+	}()
+
+	return c
+}
+
+// call sends a request, and returns its response.
+func (c *testClient) call(method string, params any) *testResponse {
+	c.t.Helper()
+
+	c.nextID++
+	id := json.RawMessage(mustMarshal(c.t, c.nextID))
+	require.NoError(c.t, c.conn.write(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	}))
+
+	select {
+	case res := <-c.responses:
+		require.NotNil(c.t, res, "connection closed")
+		require.Equal(c.t, string(id), string(res.ID))
+		return res
+	case <-time.After(30 * time.Second):
+		c.t.Fatalf("timeout waiting for the response to %s", method)
+		return nil
+	}
+}
+
+// result sends a request, and unmarshals its result in v.
+func (c *testClient) result(method string, params any, v any) {
+	c.t.Helper()
+
+	res := c.call(method, params)
+	require.Nil(c.t, res.Error, "%s failed", method)
+	require.NoError(c.t, json.Unmarshal(res.Result, v))
+}
+
+func (c *testClient) notify(method string, params any) {
+	c.t.Helper()
+	require.NoError(c.t, c.conn.notify(method, params))
+}
+
+// diagnostics waits for the diagnostics of uri.
+func (c *testClient) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+
+	for {
+		select {
+		case n := <-c.notifications:
+			if n.Method != "textDocument/publishDiagnostics" {
+				continue
+			}
+			var p PublishDiagnosticsParams
+			require.NoError(c.t, json.Unmarshal(n.Params, &p))
+			if p.URI == uri {
+				return p.Diagnostics
+			}
+		case <-time.After(30 * time.Second):
+			c.t.Fatalf("timeout waiting for the diagnostics of %s", uri)
+			return nil
+		}
+	}
+}
+
+func (c *testClient) initialize() {
+	c.t.Helper()
+
+	var res InitializeResult
+	c.result("initialize", InitializeParams{}, &res)
+	c.notify("initialized", struct{}{})
+}
+
+// open opens the file at path in the editor.
+func (c *testClient) open(path string) string {
+	c.t.Helper()
+
+	bz, err := os.ReadFile(path)
+	require.NoError(c.t, err)
+	uri := pathToURI(path)
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "gno", Version: 1, Text: string(bz)},
+	})
+	return uri
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+
+	bz, err := json.Marshal(v)
+	require.NoError(t, err)
+	return bz
+}
+
+// writePackage writes the files of a package in a temporary directory.
+func writePackage(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+// position returns the position of the first occurrence of substr in text,
+// plus offset bytes.
+func position(t *testing.T, text, substr string, offset int) Position {
+	t.Helper()
+
+	i := strings.Index(text, substr)
+	require.GreaterOrEqual(t, i, 0, "%q not found", substr)
+	return positionAt(text, i+offset)
+}
+
+func TestServer_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+
+	res := c.call("textDocument/hover", TextDocumentPositionParams{})
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codeServerNotInitialized, res.Error.Code)
+
+	var init InitializeResult
+	c.result("initialize", InitializeParams{}, &init)
+	assert.Equal(t, ServerName, init.ServerInfo.Name)
+	assert.True(t, init.Capabilities.HoverProvider)
+	assert.Equal(t, SyncFull, init.Capabilities.TextDocumentSync.Change)
+
+	res = c.call("unknown/method", nil)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codeMethodNotFound, res.Error.Code)
+
+	res = c.call("shutdown", nil)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	c.notify("exit", nil)
+	select {
+	case err := <-c.done:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	c.initialize()
+	c.notify("exit", nil)
+
+	select {
+	case err := <-c.done:
+		assert.ErrorIs(t, err, errExitWithoutShutdown)
+	case <-time.After(10 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	t.Parallel()
+
+	dir := writePackage(t, map[string]string{
+		"gno.mod": "module gno.land/p/demo/diag\n",
+		"a.gno": `package diag
+
+func A() int {
+	return undefinedVar
+}
+`,
+		"b.gno": `package diag
+
+func B() int {
+	return 1
+}
+`,
+	})
+
+	c := newTestClient(t)
+	c.initialize()
+
+	uriA := c.open(filepath.Join(dir, "a.gno"))
+	diags := c.diagnostics(uriA)
+	require.Len(t, diags, 1)
+	assert.Equal(t, SeverityError, diags[0].Severity)
+	assert.Contains(t, diags[0].Message, "undefinedVar")
+	assert.Equal(t, 3, diags[0].Range.Start.Line)
+
+	// Fix the error, then save.
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uriA, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "package diag\n\nfunc A() int {\n\treturn B()\n}\n"}},
+	})
+	c.notify("textDocument/didSave", DidSaveTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uriA}})
+	assert.Empty(t, c.diagnostics(uriA))
+
+	// Syntax errors.
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uriA, Version: 3},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "package diag\n\nfunc A() int {\n\treturn (\n}\n"}},
+	})
+	c.notify("textDocument/didSave", DidSaveTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uriA}})
+	diags = c.diagnostics(uriA)
+	require.NotEmpty(t, diags)
+	assert.Equal(t, 4, diags[0].Range.Start.Line)
+}
+
+func TestServer_DiagnosticsMissingGnoMod(t *testing.T) {
+	t.Parallel()
+
+	dir := writePackage(t, map[string]string{
+		"a.gno": "package nomod\n\nfunc A() {}\n",
+	})
+
+	c := newTestClient(t)
+	c.initialize()
+
+	diags := c.diagnostics(c.open(filepath.Join(dir, "a.gno")))
+	require.Len(t, diags, 1)
+	assert.Equal(t, SeverityWarning, diags[0].Severity)
+	assert.Equal(t, "missing 'gno.mod' file", diags[0].Message)
+}
+
+const navigationSource = `package nav
+
+import "strings"
+
+// Shout returns s in upper case.
+func Shout(s string) string {
+	return strings.ToUpper(s)
+}
+
+func main() {
+	_ = Shout("hello")
+	_ = helper
+}
+`
+
+func TestServer_Navigation(t *testing.T) {
+	t.Parallel()
+
+	dir := writePackage(t, map[string]string{
+		"gno.mod":   "module gno.land/r/demo/nav\n",
+		"nav.gno":   navigationSource,
+		"other.gno": "package nav\n\n// helper is declared in another file.\nvar helper = 1\n",
+	})
+
+	c := newTestClient(t)
+	c.initialize()
+	uri := c.open(filepath.Join(dir, "nav.gno"))
+	doc := TextDocumentIdentifier{URI: uri}
+
+	t.Run("hover stdlib", func(t *testing.T) {
+		var h Hover
+		c.result("textDocument/hover", TextDocumentPositionParams{
+			TextDocument: doc,
+			Position:     position(t, navigationSource, "ToUpper(s)", 2),
+		}, &h)
+		assert.Equal(t, "markdown", h.Contents.Kind)
+		assert.Contains(t, h.Contents.Value, "func strings.ToUpper(s string) string")
+		assert.Contains(t, h.Contents.Value, "ToUpper returns s with all Unicode letters mapped to their upper case.")
+		require.NotNil(t, h.Range)
+		assert.Equal(t, position(t, navigationSource, "ToUpper(s)", 0), h.Range.Start)
+	})
+
+	t.Run("hover local", func(t *testing.T) {
+		var h Hover
+		c.result("textDocument/hover", TextDocumentPositionParams{
+			TextDocument: doc,
+			Position:     position(t, navigationSource, `Shout("hello")`, 0),
+		}, &h)
+		assert.Equal(t, "```gno\nfunc Shout(s string) string\n```\n\nShout returns s in upper case.", h.Contents.Value)
+	})
+
+	t.Run("hover nothing", func(t *testing.T) {
+		res := c.call("textDocument/hover", TextDocumentPositionParams{
+			TextDocument: doc,
+			Position:     Position{Line: 1, Character: 0},
+		})
+		require.Nil(t, res.Error)
+		assert.Equal(t, "null", string(res.Result))
+	})
+
+	t.Run("definition other file", func(t *testing.T) {
+		var locs []Location
+		c.result("textDocument/definition", TextDocumentPositionParams{
+			TextDocument: doc,
+			Position:     position(t, navigationSource, "helper", 1),
+		}, &locs)
+		require.Len(t, locs, 1)
+		assert.Equal(t, pathToURI(filepath.Join(dir, "other.gno")), locs[0].URI)
+		assert.Equal(t, Range{Start: Position{3, 4}, End: Position{3, 10}}, locs[0].Range)
+	})
+
+	t.Run("definition stdlib", func(t *testing.T) {
+		var locs []Location
+		c.result("textDocument/definition", TextDocumentPositionParams{
+			TextDocument: doc,
+			Position:     position(t, navigationSource, "ToUpper(s)", 0),
+		}, &locs)
+		require.Len(t, locs, 1)
+		assert.Equal(t, pathToURI(filepath.Join(gnoenv.RootDir(), "gnovm", "stdlibs", "strings", "strings.gno")), locs[0].URI)
+	})
+
+	t.Run("definition package", func(t *testing.T) {
+		var locs []Location
+		c.result("textDocument/definition", TextDocumentPositionParams{
+			TextDocument: doc,
+			Position:     position(t, navigationSource, "strings.ToUpper", 1),
+		}, &locs)
+		require.Len(t, locs, 1)
+		assert.Contains(t, locs[0].URI, "/gnovm/stdlibs/strings/")
+	})
+}
+
+func TestServer_Completion(t *testing.T) {
+	t.Parallel()
+
+	const source = `package comp
+
+import "strings"
+
+type point struct {
+	X, Y int
+}
+
+func (p point) Sum() int { return p.X + p.Y }
+
+func main() {
+	var p point
+	_ = strings.ToU
+	_ = p.
+}
+`
+	dir := writePackage(t, map[string]string{
+		"gno.mod":  "module gno.land/p/demo/comp\n",
+		"comp.gno": source,
+	})
+
+	c := newTestClient(t)
+	c.initialize()
+	uri := c.open(filepath.Join(dir, "comp.gno"))
+
+	labels := func(list CompletionList) []string {
+		var labels []string
+		for _, item := range list.Items {
+			labels = append(labels, item.Label)
+		}
+		return labels
+	}
+
+	var list CompletionList
+	c.result("textDocument/completion", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     position(t, source, "strings.ToU", len("strings.ToU")),
+	}, &list)
+	assert.Equal(t, []string{"ToUpper", "ToUpperSpecial"}, labels(list))
+	assert.Equal(t, CompletionFunction, list.Items[0].Kind)
+	assert.Equal(t, "func strings.ToUpper(s string) string", list.Items[0].Detail)
+	require.NotNil(t, list.Items[0].Documentation)
+
+	c.result("textDocument/completion", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     position(t, source, "p.\n", len("p.")),
+	}, &list)
+	assert.Equal(t, []string{"Sum", "X", "Y"}, labels(list))
+
+	// Not after a selector.
+	c.result("textDocument/completion", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     position(t, source, "var p", 3),
+	}, &list)
+	assert.Empty(t, list.Items)
+}
+
+func TestServer_Formatting(t *testing.T) {
+	t.Parallel()
+
+	dir := writePackage(t, map[string]string{
+		"gno.mod": "module gno.land/p/demo/format\n",
+		"format.gno": `package format
+
+func Upper(s string) string {
+return strings.ToUpper(s)
+}
+`,
+	})
+
+	c := newTestClient(t)
+	c.initialize()
+	uri := c.open(filepath.Join(dir, "format.gno"))
+
+	var edits []TextEdit
+	c.result("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	}, &edits)
+	require.Len(t, edits, 1)
+	assert.Equal(t, Position{}, edits[0].Range.Start)
+	assert.Equal(t, Position{Line: 5}, edits[0].Range.End)
+	assert.Equal(t, `package format
+
+import "strings"
+
+func Upper(s string) string {
+	return strings.ToUpper(s)
+}
+`, edits[0].NewText)
+
+	// Already formatted.
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: edits[0].NewText}},
+	})
+	c.result("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	}, &edits)
+	assert.Empty(t, edits)
+}
@@ -78,6 +78,7 @@ type runFileTestOptions struct {
 	nativeLibs bool
 	logger     loggerFunc
 	syncWanted bool
+	coverage   *gno.Coverage
 }
 
 // RunFileTestOptions specify changing options in [RunFileTest], deviating
@@ -102,6 +103,11 @@ func WithSyncWanted(v bool) RunFileTestOption {
 	return func(r *runFileTestOptions) { r.syncWanted = v }
 }
 
+// WithCoverage records the statements executed by the filetest in c.
+func WithCoverage(c *gno.Coverage) RunFileTestOption {
+	return func(r *runFileTestOptions) { r.coverage = c }
+}
+
 // RunFileTest executes the filetest at the given path, using rootDir as
 // the directory where to find the "stdlibs" directory.
 func RunFileTest(rootDir string, path string, opts ...RunFileTestOption) error {
@@ -125,6 +131,7 @@ func RunFileTest(rootDir string, path string, opts ...RunFileTestOption) error {
 	store := TestStore(rootDir, "./files", stdin, stdout, stderr, mode)
 	store.SetLogStoreOps(true)
 	m := testMachineCustom(store, pkgPath, stdout, maxAlloc, send)
+	m.Coverage = f.coverage
 	checkMachineIsEmpty := true
 
 	// TODO support stdlib groups, but make testing safe;
@@ -1,7 +1,22 @@
 package testing
 
-import "time"
+import (
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
 
 func X_unixNano() int64 {
 	return time.Now().UnixNano()
 }
+
+func X_machineStats(m *gno.Machine) (cycles, allocBytes, gas int64) {
+	cycles = m.Cycles
+	if m.Alloc != nil {
+		_, allocBytes = m.Alloc.Status()
+	}
+	if m.GasMeter != nil {
+		gas = m.GasMeter.GasConsumed()
+	}
+	return
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"os"
+	"reflect"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/amino/genproto"
@@ -10,19 +11,23 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/commands"
 
 	// TODO: move these out.
+	"github.com/gnolang/gno/gno.land/pkg/gnogrpc"
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/blockchain"
 	"github.com/gnolang/gno/tm2/pkg/bft/consensus"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/consensus/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/evidence"
 	"github.com/gnolang/gno/tm2/pkg/bft/mempool"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	btypes "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/bitarray"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/crypto/hd"
 	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/p2p/pex"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -49,8 +54,11 @@ func execGen(_ context.Context, _ []string) error {
 		consensus.Package,
 		ctypes.Package,
 		mempool.Package,
+		evidence.Package,
 		ed25519.Package,
 		blockchain.Package,
+		statesync.Package,
+		pex.Package,
 		hd.Package,
 		multisig.Package,
 		std.Package,
@@ -58,11 +66,17 @@ func execGen(_ context.Context, _ []string) error {
 		bank.Package,
 		vm.Package,
 		gno.Package,
+		gnogrpc.Package,
 		tests.Package,
 	}
 
+	// Service definitions, by package.
+	services := map[*amino.Package][]reflect.Type{
+		gnogrpc.Package: gnogrpc.Services,
+	}
+
 	for _, pkg := range pkgs {
-		genproto.WriteProto3Schema(pkg)
+		genproto.WriteProto3SchemaWithServices(pkg, services[pkg]...)
 		genproto.WriteProtoBindings(pkg)
 		genproto.MakeProtoFolder(pkg, "proto")
 	}
@@ -4,6 +4,7 @@ package genproto
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -300,6 +301,56 @@ func (p3c *P3Context) GenerateProto3SchemaForTypes(pkg *amino.Package, rtz ...re
 	return p3doc
 }
 
+// Given an interface type whose methods are all of the form
+// `func(context.Context, *Request) (*Response, error)`, generate the Proto3
+// service schema.  Request and response types must be registered structs.
+// Imports are added to p3doc.
+func (p3c *P3Context) GenerateProto3Service(p3doc *P3Doc, rt reflect.Type) (p3svc P3Service) {
+	if p3doc.PackageName == "" {
+		panic(fmt.Sprintf("cannot generate services in the root package \"\"."))
+	}
+	if rt.Kind() != reflect.Interface {
+		panic(fmt.Errorf("can only generate proto3 services from interfaces, got rt %v", rt))
+	}
+
+	p3svc.Name = rt.Name()
+	for i := 0; i < rt.NumMethod(); i++ {
+		mtd := rt.Method(i)
+		mt := mtd.Type
+		if mt.NumIn() != 2 || mt.In(0) != contextType ||
+			mt.NumOut() != 2 || mt.Out(1) != errorType {
+			panic(fmt.Errorf("method %v.%v is not a unary rpc method", rt.Name(), mtd.Name))
+		}
+		p3svc.Methods = append(p3svc.Methods, P3Method{
+			Name:   mtd.Name,
+			Input:  p3c.serviceMessageType(p3doc, mt.In(1)),
+			Output: p3c.serviceMessageType(p3doc, mt.Out(0)),
+		})
+	}
+	return
+}
+
+// Returns the proto3 message type of an rpc method argument or result.
+func (p3c *P3Context) serviceMessageType(p3doc *P3Doc, rt reflect.Type) P3MessageType {
+	if rt.Kind() != reflect.Ptr || rt.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("rpc messages must be pointers to structs, got rt %v", rt))
+	}
+	info, err := p3c.cdc.GetTypeInfo(rt.Elem())
+	if err != nil {
+		panic(err)
+	}
+	if !info.Registered {
+		panic(fmt.Errorf("rpc message %v is not registered", rt))
+	}
+	p3mt := NewP3MessageType(info.Package.P3PkgName, info.Name)
+	if p3mt.GetPackageName() == p3doc.PackageName {
+		p3mt.SetOmitPackage()
+	} else {
+		p3doc.AddImport(p3c.GetP3ImportPath(p3mt, false))
+	}
+	return p3mt
+}
+
 // Convenience.
 func (p3c *P3Context) WriteProto3SchemaForTypes(filename string, pkg *amino.Package, rtz ...reflect.Type) {
 	fmt.Printf("writing proto3 schema to %v for package %v\n", filename, pkg)
@@ -313,6 +364,8 @@ func (p3c *P3Context) WriteProto3SchemaForTypes(filename string, pkg *amino.Pack
 var (
 	timeType     = reflect.TypeOf(time.Now())
 	durationType = reflect.TypeOf(time.Duration(0))
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
 )
 
 // If info.ReprType is a struct, the returned proto3 type is a P3MessageType.
@@ -419,11 +472,26 @@ func typeToP3Type(root *amino.Package, info *amino.TypeInfo, fopts amino.FieldOp
 
 // Writes in the same directory as the origin package.
 func WriteProto3Schema(pkg *amino.Package) {
+	WriteProto3SchemaWithServices(pkg)
+}
+
+// Like WriteProto3Schema, but also writes the service definitions of the given
+// interface types.  See GenerateProto3Service.
+func WriteProto3SchemaWithServices(pkg *amino.Package, services ...reflect.Type) {
 	p3c := NewP3Context()
 	p3c.RegisterPackage(pkg)
 	p3c.ValidateBasic()
 	filename := path.Join(pkg.DirName, pkg.GoPkgName+".proto")
-	p3c.WriteProto3SchemaForTypes(filename, pkg, pkg.ReflectTypes()...)
+	fmt.Printf("writing proto3 schema to %v for package %v\n", filename, pkg)
+	p3doc := p3c.GenerateProto3SchemaForTypes(pkg, pkg.ReflectTypes()...)
+	for _, rt := range services {
+		p3svc := p3c.GenerateProto3Service(&p3doc, rt)
+		p3doc.Services = append(p3doc.Services, p3svc)
+	}
+	err := os.WriteFile(filename, []byte(p3doc.Print()), 0o644)
+	if err != nil {
+		panic(err)
+	}
 }
 
 // Symlinks .proto files from pkg info to dirname, keeping the go path
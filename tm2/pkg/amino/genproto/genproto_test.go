@@ -1,10 +1,12 @@
 package genproto
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
 	sm1 "github.com/gnolang/gno/tm2/pkg/amino/genproto/example/submodule"
+	sm2 "github.com/gnolang/gno/tm2/pkg/amino/genproto/example/submodule2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,3 +47,39 @@ message StructSM {
 	submodule2.StructSM2 field_c = 3 [json_name = "FieldC"];
 }`, p3doc.Print())
 }
+
+type testService interface {
+	Get(context.Context, *sm1.StructSM) (*sm2.StructSM2, error)
+	Put(context.Context, *sm1.StructSM) (*sm1.StructSM, error)
+}
+
+type testInvalidService interface {
+	Get(*sm1.StructSM) (*sm1.StructSM, error)
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	p3c := NewP3Context()
+	p3c.RegisterPackage(sm1.Package)
+	p3doc := p3c.GenerateProto3SchemaForTypes(sm1.Package)
+	p3svc := p3c.GenerateProto3Service(&p3doc, reflect.TypeOf((*testService)(nil)).Elem())
+	p3doc.Services = append(p3doc.Services, p3svc)
+	assert.Equal(t, `syntax = "proto3";
+package submodule;
+
+option go_package = "github.com/gnolang/gno/tm2/pkg/amino/genproto/example/submodule/pb";
+
+// imports
+import "github.com/gnolang/gno/tm2/pkg/amino/genproto/example/submodule2/submodule2.proto";
+
+// services
+service testService {
+	rpc Get (StructSM) returns (submodule2.StructSM2);
+	rpc Put (StructSM) returns (StructSM);
+}`, p3doc.Print())
+
+	assert.Panics(t, func() {
+		p3c.GenerateProto3Service(&p3doc, reflect.TypeOf((*testInvalidService)(nil)).Elem())
+	})
+}
@@ -124,6 +124,7 @@ type P3Doc struct {
 	Comment     string
 	Imports     []P3Import
 	Messages    []P3Message
+	Services    []P3Service
 	// Enums []P3Enums // enums not supported, no need.
 }
 
@@ -147,6 +148,20 @@ type P3Message struct {
 	Fields  []P3Field
 }
 
+type P3Service struct {
+	Comment string
+	Name    string
+	Methods []P3Method
+}
+
+// NOTE: streaming methods are not supported.
+type P3Method struct {
+	Comment string
+	Name    string
+	Input   P3MessageType
+	Output  P3MessageType
+}
+
 type P3Field struct {
 	Comment  string
 	Repeated bool
@@ -198,9 +213,14 @@ func (doc P3Doc) PrintCode(p *press.Press) *press.Press {
 		}
 		msg.PrintCode(p)
 		p.Ln()
-		if i == len(doc.Messages)-1 {
-			p.Ln()
+	}
+	// Print service definitions, if any.
+	for i, svc := range doc.Services {
+		if i == 0 {
+			p.Pl("// services")
 		}
+		svc.PrintCode(p)
+		p.Ln()
 	}
 	return p
 }
@@ -225,6 +245,27 @@ func (msg P3Message) PrintCode(p *press.Press) *press.Press {
 	return p
 }
 
+func (svc P3Service) Print() string {
+	p := press.NewPress()
+	return svc.PrintCode(p).Print()
+}
+
+func (svc P3Service) PrintCode(p *press.Press) *press.Press {
+	printComments(p, svc.Comment)
+	p.Pl("service %v {", svc.Name).I(func(p *press.Press) {
+		for _, mtd := range svc.Methods {
+			mtd.PrintCode(p)
+		}
+	}).Pl("}")
+	return p
+}
+
+func (mtd P3Method) PrintCode(p *press.Press) *press.Press {
+	printComments(p, mtd.Comment)
+	p.Pl("rpc %v (%v) returns (%v);", mtd.Name, mtd.Input, mtd.Output)
+	return p
+}
+
 func (fld P3Field) PrintCode(p *press.Press) *press.Press {
 	fieldOptions := ""
 	if fld.JSONName != "" && fld.JSONName != fld.Name {
@@ -37,6 +37,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		RequestDeliverTx{},
 		RequestEndBlock{},
 		RequestCommit{},
+		RequestListSnapshots{},
+		RequestOfferSnapshot{},
+		RequestLoadSnapshotChunk{},
+		RequestApplySnapshotChunk{},
 
 		// response types
 		ResponseBase{},
@@ -52,6 +56,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		ResponseDeliverTx{},
 		ResponseEndBlock{},
 		ResponseCommit{},
+		ResponseListSnapshots{},
+		ResponseOfferSnapshot{},
+		ResponseLoadSnapshotChunk{},
+		ResponseApplySnapshotChunk{},
 
 		// error types
 		StringError(""),
@@ -60,11 +68,13 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		ConsensusParams{},
 		BlockParams{},
 		ValidatorParams{},
+		EvidenceParams{},
 		ValidatorUpdate{},
 		LastCommitInfo{},
 		VoteInfo{},
-		// Validator{},
-		// Violation{},
+		Validator{},
+		Violation{},
+		Snapshot{},
 
 		// events
 		EventString(""),
@@ -61,7 +61,7 @@ type RequestBeginBlock struct {
 	Hash           []byte
 	Header         Header
 	LastCommitInfo *LastCommitInfo
-	// Violations     []Violation
+	Violations     []Violation
 }
 
 type CheckTxType int
@@ -91,6 +91,30 @@ type RequestCommit struct {
 	RequestBase
 }
 
+type RequestListSnapshots struct {
+	RequestBase
+}
+
+type RequestOfferSnapshot struct {
+	RequestBase
+	Snapshot *Snapshot // snapshot offered by peers
+	AppHash  []byte    // trusted app hash at the snapshot height
+}
+
+type RequestLoadSnapshotChunk struct {
+	RequestBase
+	Height int64
+	Format uint32
+	Chunk  uint32
+}
+
+type RequestApplySnapshotChunk struct {
+	RequestBase
+	Index  uint32
+	Chunk  []byte
+	Sender string // ID of the peer which sent the chunk
+}
+
 // ----------------------------------------
 // Response types
 
@@ -178,6 +202,9 @@ type ResponseCheckTx struct {
 	ResponseBase
 	GasWanted int64 // nondeterministic
 	GasUsed   int64
+	Priority  int64          // mempool priority of the tx, higher first
+	Sender    crypto.Address // whose txs are kept in sequence order
+	Sequence  uint64         // sequence of the tx for its sender
 }
 
 type ResponseDeliverTx struct {
@@ -197,6 +224,48 @@ type ResponseCommit struct {
 	ResponseBase
 }
 
+type ResponseListSnapshots struct {
+	ResponseBase
+	Snapshots []*Snapshot
+}
+
+type OfferSnapshotResult int
+
+const (
+	OfferSnapshotUnknown      OfferSnapshotResult = iota // Unknown result, abort all snapshot restoration
+	OfferSnapshotAccept                                  // Snapshot accepted, apply chunks
+	OfferSnapshotAbort                                   // Abort all snapshot restoration
+	OfferSnapshotReject                                  // Reject this specific snapshot, try others
+	OfferSnapshotRejectFormat                            // Reject all snapshots of this format, try others
+)
+
+type ResponseOfferSnapshot struct {
+	ResponseBase
+	Result OfferSnapshotResult
+}
+
+type ResponseLoadSnapshotChunk struct {
+	ResponseBase
+	Chunk []byte
+}
+
+type ApplySnapshotChunkResult int
+
+const (
+	ApplySnapshotChunkUnknown        ApplySnapshotChunkResult = iota // Unknown result, abort all snapshot restoration
+	ApplySnapshotChunkAccept                                         // Chunk successfully accepted
+	ApplySnapshotChunkAbort                                          // Abort all snapshot restoration
+	ApplySnapshotChunkRetry                                          // Retry chunk (combine with refetch and reject)
+	ApplySnapshotChunkRejectSnapshot                                 // Reject this snapshot, try others
+)
+
+type ResponseApplySnapshotChunk struct {
+	ResponseBase
+	Result        ApplySnapshotChunkResult
+	RefetchChunks []uint32 // Chunks to refetch and reapply
+	RejectSenders []string // Chunk senders to reject and ban
+}
+
 // ----------------------------------------
 // Interface types
 
@@ -216,6 +285,10 @@ type Header interface {
 	AssertABCIHeader()
 }
 
+type Evidence interface {
+	AssertABCIEvidence()
+}
+
 // ----------------------------------------
 // Error types
 
@@ -245,6 +318,16 @@ func (err EventString) Event() string {
 type ConsensusParams struct {
 	Block     *BlockParams
 	Validator *ValidatorParams
+	Evidence  *EvidenceParams
+}
+
+// Snapshot describes a state snapshot of the application.
+type Snapshot struct {
+	Height   int64  // The height at which the snapshot was taken
+	Format   uint32 // The application-specific snapshot format
+	Chunks   uint32 // Number of chunks in the snapshot
+	Hash     []byte // Arbitrary snapshot hash, equal only if identical
+	Metadata []byte // Arbitrary application metadata
 }
 
 type BlockParams struct {
@@ -255,6 +338,10 @@ type BlockParams struct {
 	TimeIotaMS    int64 // must be > 0
 }
 
+type EvidenceParams struct {
+	MaxAge int64 // only accept new evidence more recent than this, in blocks
+}
+
 type ValidatorParams struct {
 	PubKeyTypeURLs []string
 }
@@ -277,7 +364,6 @@ type VoteInfo struct {
 	SignedLastBlock bool
 }
 
-/*
 // unstable
 type Validator struct {
 	Address crypto.Address
@@ -287,10 +373,9 @@ type Validator struct {
 
 // unstable
 type Violation struct {
-	Evidence
+	Evidence         Evidence
 	Validators       []Validator
 	Height           int64
 	Time             time.Time
 	TotalVotingPower int64
 }
-*/
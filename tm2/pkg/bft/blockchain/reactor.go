@@ -119,6 +119,29 @@ func (bcR *BlockchainReactor) OnStart() error {
 	return nil
 }
 
+// SwitchToFastSync is called by the state sync reactor when the node state
+// has been restored from a snapshot, to fast sync the blocks following it.
+// The reactor must have been created with fastSync disabled.
+func (bcR *BlockchainReactor) SwitchToFastSync(state sm.State) error {
+	if bcR.fastSync {
+		return errors.New("already fast syncing")
+	}
+	if state.LastBlockHeight != bcR.store.Height() {
+		return fmt.Errorf("state (%v) and store (%v) height mismatch", state.LastBlockHeight,
+			bcR.store.Height())
+	}
+
+	bcR.Logger.Info("Switching to fast sync", "height", state.LastBlockHeight)
+	bcR.fastSync = true
+	bcR.initialState = state
+	bcR.pool.height = state.LastBlockHeight + 1
+	if err := bcR.pool.Start(); err != nil {
+		return err
+	}
+	go bcR.poolRoutine()
+	return nil
+}
+
 // OnStop implements cmn.Service.
 func (bcR *BlockchainReactor) OnStop() {
 	bcR.pool.Stop()
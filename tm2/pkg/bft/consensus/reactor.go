@@ -825,13 +825,11 @@ func (conR *ConsensusReactor) peerStatsRoutine() {
 			switch msg.Msg.(type) {
 			case *VoteMessage:
 				if numVotes := ps.RecordVote(); numVotes%votesToContributeToBecomeGoodPeer == 0 {
-					// TODO: peer metrics.
-					// conR.Switch.MarkPeerAsGood(peer)
+					conR.Switch.MarkPeerAsGood(peer)
 				}
 			case *BlockPartMessage:
 				if numParts := ps.RecordBlockPart(); numParts%blocksToContributeToBecomeGoodPeer == 0 {
-					// TODO: peer metrics.
-					// conR.Switch.MarkPeerAsGood(peer)
+					conR.Switch.MarkPeerAsGood(peer)
 				}
 			}
 		case <-conR.conS.Quit():
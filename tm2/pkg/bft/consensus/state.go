@@ -82,6 +82,11 @@ type txNotifier interface {
 	TxsAvailable() <-chan struct{}
 }
 
+// interface to the evidence pool
+type evidencePool interface {
+	AddEvidence(types.Evidence) error
+}
+
 // ConsensusState handles execution of the consensus algorithm.
 // It processes votes and proposals, and upon reaching agreement,
 // commits blocks to the chain and executes them against the application.
@@ -102,6 +107,10 @@ type ConsensusState struct {
 	// notify us if txs are available
 	txNotifier txNotifier
 
+	// add evidence to the pool
+	// when it's detected
+	evpool evidencePool
+
 	// internal state
 	mtx sync.RWMutex
 	cstypes.RoundState
@@ -144,6 +153,11 @@ type ConsensusState struct {
 // StateOption sets an optional parameter on the ConsensusState.
 type StateOption func(*ConsensusState)
 
+// StateEvidencePool sets the pool conflicting votes are reported to.
+func StateEvidencePool(evpool evidencePool) StateOption {
+	return func(cs *ConsensusState) { cs.evpool = evpool }
+}
+
 // NewConsensusState returns a new ConsensusState.
 func NewConsensusState(
 	config *cnscfg.ConsensusConfig,
@@ -158,6 +172,7 @@ func NewConsensusState(
 		blockExec:        blockExec,
 		blockStore:       blockStore,
 		txNotifier:       txNotifier,
+		evpool:           sm.MockEvidencePool{},
 		peerMsgQueue:     make(chan msgInfo, msgQueueSize),
 		internalMsgQueue: make(chan msgInfo, msgQueueSize),
 		timeoutTicker:    NewTimeoutTicker(),
@@ -1379,6 +1394,9 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 
 	fail.Fail() // XXX
 
+	// The commit round is reset by the new height
+	commitRound := cs.CommitRound
+
 	// NewHeightStep!
 	cs.updateToState(stateCopy)
 
@@ -1394,7 +1412,7 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 	// * cs.StartTime is set to when we will start round0.
 
 	// Log the telemetry
-	cs.logTelemetry(block)
+	cs.logTelemetry(block, commitRound)
 }
 
 // -----------------------------------------------------------------------------
@@ -1522,17 +1540,15 @@ func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerID p2p.ID) (bool, err
 		// If it's otherwise invalid, punish peer.
 		if goerrors.Is(err, ErrVoteHeightMismatch) {
 			return added, err
-		} else if _, ok := err.(*types.VoteConflictingVotesError); ok {
-			/* XXX
-			addr := cs.privValidator.GetPubKey().Address()
-			if bytes.Equal(vote.ValidatorAddress, addr) {
+		} else if voteErr, ok := err.(*types.VoteConflictingVotesError); ok {
+			if cs.privValidator != nil && vote.ValidatorAddress == cs.privValidator.GetPubKey().Address() {
 				cs.Logger.Error("Found conflicting vote from ourselves. Did you unsafe_reset a validator?", "height", vote.Height, "round", vote.Round, "type", vote.Type)
 				return added, err
 			}
-			cs.evpool.AddEvidence(voteErr.DuplicateVoteEvidence)
+			if evErr := cs.evpool.AddEvidence(voteErr.DuplicateVoteEvidence); evErr != nil {
+				cs.Logger.Error("Error adding evidence of conflicting votes", "err", evErr)
+			}
 			return added, err
-			*/
-			panic("not yet implemented")
 		} else {
 			// Either
 			// 1) bad peer OR
@@ -1770,7 +1786,7 @@ func (cs *ConsensusState) signAddVote(type_ types.SignedMsgType, hash []byte, he
 }
 
 // logTelemetry logs the consensus state telemetry
-func (cs *ConsensusState) logTelemetry(block *types.Block) {
+func (cs *ConsensusState) logTelemetry(block *types.Block, commitRound int) {
 	if !telemetry.MetricsEnabled() {
 		return
 	}
@@ -1791,6 +1807,9 @@ func (cs *ConsensusState) logTelemetry(block *types.Block) {
 
 	metrics.BlockTxs.Record(context.Background(), block.TotalTxs)
 	metrics.BlockSizeBytes.Record(context.Background(), int64(block.Size()))
+
+	// Log the consensus telemetry, rounds start at 0
+	metrics.ConsensusRounds.Record(context.Background(), int64(commitRound)+1)
 }
 
 // ---------------------------------------------------------
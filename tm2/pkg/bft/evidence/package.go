@@ -0,0 +1,17 @@
+package evidence
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/evidence",
+	"tm",
+	amino.GetCallersDirname(),
+).WithDependencies(
+	types.Package,
+).WithTypes(
+	EvidenceInfo{},
+	&EvidenceListMessage{},
+))
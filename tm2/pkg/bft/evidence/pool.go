@@ -0,0 +1,171 @@
+package evidence
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/clist"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+// Pool maintains a pool of valid evidence in a Store.
+type Pool struct {
+	logger *slog.Logger
+
+	store        *Store
+	evidenceList *clist.CList // concurrent linked-list of evidence
+
+	// needed to load validators to verify evidence
+	stateDB dbm.DB
+
+	// latest state
+	mtx   sync.Mutex
+	state sm.State
+}
+
+var _ sm.EvidencePool = &Pool{}
+
+// NewPool returns a new Pool, verifying evidence against the state in stateDB
+// and persisting it in evidenceDB. Evidence still pending from a previous run
+// is gossiped again.
+func NewPool(stateDB, evidenceDB dbm.DB) *Pool {
+	evpool := &Pool{
+		stateDB:      stateDB,
+		state:        sm.LoadState(stateDB),
+		logger:       log.NewNoopLogger(),
+		store:        NewStore(evidenceDB),
+		evidenceList: clist.New(),
+	}
+	for _, ev := range evpool.store.PendingEvidence(-1) {
+		evpool.evidenceList.PushBack(ev)
+	}
+	return evpool
+}
+
+// EvidenceFront returns the first element of the evidence list.
+func (evpool *Pool) EvidenceFront() *clist.CElement {
+	return evpool.evidenceList.Front()
+}
+
+// EvidenceWaitChan returns a channel that is closed when the evidence list
+// is not empty.
+func (evpool *Pool) EvidenceWaitChan() <-chan struct{} {
+	return evpool.evidenceList.WaitChan()
+}
+
+// SetLogger sets the Logger.
+func (evpool *Pool) SetLogger(l *slog.Logger) {
+	evpool.logger = l
+}
+
+// PendingEvidence returns up to maxNum uncommitted evidence.
+// If maxNum is -1, all evidence is returned.
+func (evpool *Pool) PendingEvidence(maxNum int64) []types.Evidence {
+	return evpool.store.PendingEvidence(maxNum)
+}
+
+// State returns the current state of the evpool.
+func (evpool *Pool) State() sm.State {
+	evpool.mtx.Lock()
+	defer evpool.mtx.Unlock()
+	return evpool.state
+}
+
+// Update loads the latest state, and marks the evidence
+// included in the block as committed.
+func (evpool *Pool) Update(block *types.Block, state sm.State) {
+	// sanity check
+	if state.LastBlockHeight != block.Height {
+		panic(fmt.Sprintf("Failed EvidencePool.Update sanity check: got state.Height=%d with block.Height=%d",
+			state.LastBlockHeight, block.Height))
+	}
+
+	// update the state
+	evpool.mtx.Lock()
+	evpool.state = state
+	evpool.mtx.Unlock()
+
+	// remove evidence from pending and mark committed
+	evpool.MarkEvidenceAsCommitted(block.Height, block.Evidence.Evidence)
+}
+
+// AddEvidence checks the evidence is valid and adds it to the pool.
+// Adding evidence which is already known is a no-op.
+func (evpool *Pool) AddEvidence(evidence types.Evidence) error {
+	if err := evidence.ValidateBasic(); err != nil {
+		return err
+	}
+	if err := sm.VerifyEvidence(evpool.stateDB, evpool.State(), evidence); err != nil {
+		return err
+	}
+
+	if added := evpool.store.AddNewEvidence(evidence); !added {
+		// evidence already known, just ignore
+		return nil
+	}
+
+	evpool.logger.Info("Verified new evidence of byzantine behaviour", "evidence", evidence)
+
+	// add evidence to clist
+	evpool.evidenceList.PushBack(evidence)
+
+	return nil
+}
+
+// MarkEvidenceAsCommitted marks all the evidence as committed and removes it
+// from the queue, along with any evidence which became too old at height.
+func (evpool *Pool) MarkEvidenceAsCommitted(height int64, evidence []types.Evidence) {
+	// make a map of committed evidence to remove from the clist
+	blockEvidenceMap := make(map[string]struct{}, len(evidence))
+	for _, ev := range evidence {
+		evpool.store.MarkEvidenceAsCommitted(ev)
+		blockEvidenceMap[evMapKey(ev)] = struct{}{}
+	}
+
+	// remove committed evidence from the clist
+	evpool.removeEvidence(height, evpool.maxAge(), blockEvidenceMap)
+}
+
+// IsCommitted returns true if we have already seen this exact evidence and it is already marked as committed.
+func (evpool *Pool) IsCommitted(evidence types.Evidence) bool {
+	ei := evpool.store.getEvidenceInfo(evidence)
+	return ei.Evidence != nil && ei.Committed
+}
+
+func (evpool *Pool) removeEvidence(height int64, maxAge int64, blockEvidenceMap map[string]struct{}) {
+	for e := evpool.evidenceList.Front(); e != nil; e = e.Next() {
+		ev := e.Value.(types.Evidence)
+
+		// Remove the evidence if it's already in a block
+		// or if it's now too old.
+		_, committed := blockEvidenceMap[evMapKey(ev)]
+		expired := ev.Height() < height-maxAge
+		if !committed && !expired {
+			continue
+		}
+		if expired && !committed {
+			evpool.store.MarkEvidenceAsExpired(ev)
+		}
+
+		// remove from clist
+		evpool.evidenceList.Remove(e)
+		e.DetachPrev()
+	}
+}
+
+// maxAge returns the age, in blocks, after which evidence expires.
+func (evpool *Pool) maxAge() int64 {
+	params := evpool.State().ConsensusParams.Evidence
+	if params == nil {
+		params = types.DefaultEvidenceParams()
+	}
+	return params.MaxAge
+}
+
+func evMapKey(ev types.Evidence) string {
+	return string(ev.Hash())
+}
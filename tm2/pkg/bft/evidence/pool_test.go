@@ -0,0 +1,156 @@
+package evidence
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+// initializeValidatorState returns a state db where valAddr
+// is the only validator, for all heights up to height.
+func initializeValidatorState(valAddr crypto.Address, height int64) dbm.DB {
+	stateDB := memdb.NewMemDB()
+
+	// create validator set and state
+	valSet := &types.ValidatorSet{
+		Validators: []*types.Validator{
+			{Address: valAddr, PubKey: ed25519.GenPrivKey().PubKey(), VotingPower: 10},
+		},
+	}
+	state := sm.State{
+		LastBlockHeight:             0,
+		LastBlockTime:               tmtime.Now(),
+		Validators:                  valSet,
+		NextValidators:              valSet.CopyIncrementProposerPriority(1),
+		LastHeightValidatorsChanged: 1,
+		ConsensusParams: abci.ConsensusParams{
+			Block:     types.DefaultBlockParams(),
+			Validator: types.DefaultValidatorParams(),
+			Evidence: &abci.EvidenceParams{
+				MaxAge: 1000000,
+			},
+		},
+	}
+
+	// save all states up to height
+	for i := int64(0); i < height; i++ {
+		state.LastBlockHeight = i
+		sm.SaveState(stateDB, state)
+	}
+
+	return stateDB
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	valAddr := crypto.AddressFromPreimage([]byte("val1"))
+	height := int64(5)
+	stateDB := initializeValidatorState(valAddr, height)
+	pool := NewPool(stateDB, memdb.NewMemDB())
+
+	goodEvidence := types.NewMockGoodEvidence(height, 0, valAddr)
+	badEvidence := types.MockBadEvidence{MockGoodEvidence: goodEvidence}
+
+	// bad evidence
+	assert.Error(t, pool.AddEvidence(badEvidence))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		<-pool.EvidenceWaitChan()
+		wg.Done()
+	}()
+
+	require.NoError(t, pool.AddEvidence(goodEvidence))
+	wg.Wait()
+
+	assert.Equal(t, 1, pool.evidenceList.Len())
+
+	// if we send it again, it shouldnt change the size
+	require.NoError(t, pool.AddEvidence(goodEvidence))
+	assert.Equal(t, 1, pool.evidenceList.Len())
+}
+
+func TestPoolUnknownValidator(t *testing.T) {
+	t.Parallel()
+
+	valAddr := crypto.AddressFromPreimage([]byte("val1"))
+	height := int64(5)
+	stateDB := initializeValidatorState(valAddr, height)
+	pool := NewPool(stateDB, memdb.NewMemDB())
+
+	otherAddr := crypto.AddressFromPreimage([]byte("val2"))
+	assert.Error(t, pool.AddEvidence(types.NewMockGoodEvidence(height, 0, otherAddr)))
+	assert.Equal(t, 0, pool.evidenceList.Len())
+}
+
+func TestPoolIsCommitted(t *testing.T) {
+	t.Parallel()
+
+	// Initialization:
+	valAddr := crypto.AddressFromPreimage([]byte("validator_address"))
+	height := int64(42)
+	stateDB := initializeValidatorState(valAddr, height)
+	pool := NewPool(stateDB, memdb.NewMemDB())
+
+	// evidence not seen yet:
+	evidence := types.NewMockGoodEvidence(height, 0, valAddr)
+	assert.False(t, pool.IsCommitted(evidence))
+
+	// evidence seen but not yet committed:
+	require.NoError(t, pool.AddEvidence(evidence))
+	assert.False(t, pool.IsCommitted(evidence))
+	assert.Len(t, pool.PendingEvidence(-1), 1)
+
+	// evidence seen and committed:
+	pool.MarkEvidenceAsCommitted(height, []types.Evidence{evidence})
+	assert.True(t, pool.IsCommitted(evidence))
+	assert.Empty(t, pool.PendingEvidence(-1))
+	assert.Equal(t, 0, pool.evidenceList.Len())
+}
+
+func TestPoolExpiredEvidence(t *testing.T) {
+	t.Parallel()
+
+	valAddr := crypto.AddressFromPreimage([]byte("val1"))
+	height := int64(5)
+	stateDB := initializeValidatorState(valAddr, height)
+	pool := NewPool(stateDB, memdb.NewMemDB())
+
+	evidence := types.NewMockGoodEvidence(height, 0, valAddr)
+	require.NoError(t, pool.AddEvidence(evidence))
+
+	// once too old, the evidence is neither pending nor committed
+	pool.MarkEvidenceAsCommitted(height+pool.maxAge()+1, nil)
+	assert.Empty(t, pool.PendingEvidence(-1))
+	assert.Equal(t, 0, pool.evidenceList.Len())
+	assert.False(t, pool.IsCommitted(evidence))
+}
+
+func TestPoolReloadsPendingEvidence(t *testing.T) {
+	t.Parallel()
+
+	valAddr := crypto.AddressFromPreimage([]byte("val1"))
+	height := int64(5)
+	stateDB := initializeValidatorState(valAddr, height)
+	evidenceDB := memdb.NewMemDB()
+
+	pool := NewPool(stateDB, evidenceDB)
+	require.NoError(t, pool.AddEvidence(types.NewMockGoodEvidence(height, 0, valAddr)))
+
+	// a restarted pool gossips the evidence again
+	pool = NewPool(stateDB, evidenceDB)
+	assert.Equal(t, 1, pool.evidenceList.Len())
+}
@@ -0,0 +1,229 @@
+package evidence
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pcfg "github.com/gnolang/gno/tm2/pkg/p2p/config"
+)
+
+// evidenceTimeout is the time to wait for evidence to be gossiped
+const evidenceTimeout = 10 * time.Second
+
+type peerState struct {
+	height int64
+}
+
+func (ps peerState) GetHeight() int64 {
+	return ps.height
+}
+
+// connect N evidence reactors through N switches
+func makeAndConnectReactors(t *testing.T, stateDBs []dbm.DB) ([]*Reactor, []*p2p.Switch) {
+	t.Helper()
+
+	n := len(stateDBs)
+	reactors := make([]*Reactor, n)
+	logger := log.NewNoopLogger()
+	for i := 0; i < n; i++ {
+		pool := NewPool(stateDBs[i], memdb.NewMemDB())
+		reactors[i] = NewReactor(pool)
+		reactors[i].SetLogger(logger.With("validator", i))
+	}
+
+	switches := p2p.MakeConnectedSwitches(p2pcfg.TestP2PConfig(), n, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("EVIDENCE", reactors[i])
+		return s
+	}, p2p.Connect2Switches)
+	t.Cleanup(func() {
+		for _, s := range switches {
+			s.Stop()
+		}
+	})
+	return reactors, switches
+}
+
+// wait for all evidence on all reactors
+func waitForEvidence(t *testing.T, evs types.EvidenceList, reactors []*Reactor) {
+	t.Helper()
+
+	wg := new(sync.WaitGroup)
+	for _, reactor := range reactors {
+		wg.Add(1)
+		go func(r *Reactor) {
+			defer wg.Done()
+			for len(r.evpool.PendingEvidence(-1)) != len(evs) {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}(reactor)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-time.After(evidenceTimeout):
+		t.Fatal("Timed out waiting for evidence")
+	case <-done:
+	}
+
+	for _, reactor := range reactors {
+		pending := reactor.evpool.PendingEvidence(-1)
+		for _, ev := range evs {
+			assert.True(t, types.EvidenceList(pending).Has(ev))
+		}
+	}
+}
+
+// set the peer height on each reactor, as the consensus reactor would do
+func setPeerHeights(switches []*p2p.Switch, height int64) {
+	for _, sw := range switches {
+		for _, peer := range sw.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{height})
+		}
+	}
+}
+
+func TestReactorBroadcastEvidence(t *testing.T) {
+	t.Parallel()
+
+	const n = 3
+
+	valAddr := crypto.AddressFromPreimage([]byte("myval"))
+	// we need validators saved for heights at least as high as we have evidence for
+	height := int64(5)
+
+	stateDBs := make([]dbm.DB, n)
+	for i := 0; i < n; i++ {
+		stateDBs[i] = initializeValidatorState(valAddr, height)
+	}
+
+	reactors, switches := makeAndConnectReactors(t, stateDBs)
+	setPeerHeights(switches, height)
+
+	// add evidence to the first reactor, and make sure it gets gossiped
+	evs := types.EvidenceList{
+		types.NewMockGoodEvidence(height-1, 0, valAddr),
+		types.NewMockGoodEvidence(height-2, 0, valAddr),
+	}
+	for _, ev := range evs {
+		require.NoError(t, reactors[0].evpool.AddEvidence(ev))
+	}
+
+	waitForEvidence(t, evs, reactors)
+}
+
+func TestReactorSelectiveBroadcast(t *testing.T) {
+	t.Parallel()
+
+	valAddr := crypto.AddressFromPreimage([]byte("myval"))
+	height1 := int64(10)
+	height2 := int64(5)
+
+	// DB1 is ahead of DB2
+	stateDB1 := initializeValidatorState(valAddr, height1)
+	stateDB2 := initializeValidatorState(valAddr, height2)
+
+	reactors, switches := makeAndConnectReactors(t, []dbm.DB{stateDB1, stateDB2})
+
+	// the second peer is only at height2
+	peer := switches[0].Peers().List()[0]
+	peer.Set(types.PeerStateKey, peerState{height2})
+
+	evLow := types.NewMockGoodEvidence(height2-1, 0, valAddr)
+	evHigh := types.NewMockGoodEvidence(height1-1, 0, valAddr)
+	require.NoError(t, reactors[0].evpool.AddEvidence(evLow))
+	require.NoError(t, reactors[0].evpool.AddEvidence(evHigh))
+
+	// only the evidence the peer can verify is received
+	waitForEvidence(t, types.EvidenceList{evLow}, reactors[1:])
+
+	assert.Len(t, reactors[0].evpool.PendingEvidence(-1), 2)
+}
+
+func TestReactorBadEvidence(t *testing.T) {
+	t.Parallel()
+
+	valAddr := crypto.AddressFromPreimage([]byte("myval"))
+	height := int64(5)
+
+	reactors, switches := makeAndConnectReactors(t, []dbm.DB{
+		initializeValidatorState(valAddr, height),
+		initializeValidatorState(valAddr, height),
+	})
+
+	// a peer sending evidence which doesn't verify is disconnected
+	peer := switches[0].Peers().List()[0]
+	bad := types.MockBadEvidence{MockGoodEvidence: types.NewMockGoodEvidence(height-1, 0, valAddr)}
+	reactors[0].Receive(EvidenceChannel, peer, amino.MustMarshalAny(&EvidenceListMessage{Evidence: []types.Evidence{bad}}))
+
+	assert.Eventually(t, func() bool {
+		return switches[0].Peers().Size() == 0
+	}, evidenceTimeout, 10*time.Millisecond)
+	assert.Empty(t, reactors[0].evpool.PendingEvidence(-1))
+}
+
+func TestEvidenceListMessageValidationBasic(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		testName          string
+		malleateEvListMsg func(*EvidenceListMessage)
+		expectErr         bool
+	}{
+		{"Good EvidenceListMessage", func(evList *EvidenceListMessage) {}, false},
+		{"Empty EvidenceListMessage", func(evList *EvidenceListMessage) {
+			evList.Evidence = nil
+		}, true},
+		{"Invalid EvidenceListMessage", func(evList *EvidenceListMessage) {
+			evList.Evidence = append(evList.Evidence,
+				&types.DuplicateVoteEvidence{PubKey: crypto.PubKey(nil)})
+		}, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			addr := crypto.AddressFromPreimage([]byte("myval"))
+			evListMsg := &EvidenceListMessage{}
+			n := 3
+			evListMsg.Evidence = make([]types.Evidence, n)
+			for i := 0; i < n; i++ {
+				evListMsg.Evidence[i] = types.NewMockGoodEvidence(int64(i+1), 0, addr)
+			}
+			tc.malleateEvListMsg(evListMsg)
+			assert.Equal(t, tc.expectErr, evListMsg.ValidateBasic() != nil, "Validate Basic had an unexpected result")
+		})
+	}
+}
+
+func TestEvidenceMessageSize(t *testing.T) {
+	t.Parallel()
+
+	msg := &EvidenceListMessage{
+		Evidence: []types.Evidence{types.NewMockGoodEvidence(1, 0, crypto.AddressFromPreimage([]byte("myval")))},
+	}
+	bz := amino.MustMarshalAny(msg)
+
+	decoded, err := decodeMsg(bz)
+	require.NoError(t, err)
+	assert.Equal(t, msg, decoded)
+
+	_, err = decodeMsg(make([]byte, maxMsgSize+1))
+	assert.Error(t, err)
+}
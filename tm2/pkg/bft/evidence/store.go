@@ -0,0 +1,157 @@
+package evidence
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+/*
+Requirements:
+	- Valid new evidence must be persisted immediately and never forgotten
+	- Uncommitted evidence must be continuously broadcast
+	- Committed evidence must be remembered, so it's not included twice
+
+Impl:
+	- First commit atomically in pending and lookup.
+	- Once committed (or expired), atomically remove from pending and update lookup.
+
+Schema for indexing evidence (note you need both height and hash to find a piece of evidence):
+
+"evidence-lookup"/<evidence-height>/<evidence-hash> -> EvidenceInfo
+"evidence-pending"/<evidence-height>/<evidence-hash> -> EvidenceInfo
+*/
+
+// EvidenceInfo is the stored representation of a piece of evidence.
+type EvidenceInfo struct {
+	Committed bool
+	Evidence  types.Evidence
+}
+
+const (
+	baseKeyLookup  = "evidence-lookup"  // all evidence
+	baseKeyPending = "evidence-pending" // not yet committed
+)
+
+func keyLookup(evidence types.Evidence) []byte {
+	return keyLookupFromHeightAndHash(evidence.Height(), evidence.Hash())
+}
+
+// big endian padded hex
+func bE(h int64) string {
+	return fmt.Sprintf("%0.16X", h)
+}
+
+func keyLookupFromHeightAndHash(height int64, hash []byte) []byte {
+	return _key("%s/%s/%X", baseKeyLookup, bE(height), hash)
+}
+
+func keyPending(evidence types.Evidence) []byte {
+	return _key("%s/%s/%X", baseKeyPending, bE(evidence.Height()), evidence.Hash())
+}
+
+func _key(format string, o ...interface{}) []byte {
+	return []byte(fmt.Sprintf(format, o...))
+}
+
+// Store is a store of all the evidence we've seen, including
+// evidence that has been committed and evidence that has been
+// verified but not yet committed.
+type Store struct {
+	db dbm.DB
+}
+
+// NewStore returns a new Store backed by the given db.
+func NewStore(db dbm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// PendingEvidence returns up to maxNum known, uncommitted evidence,
+// oldest first. If maxNum is -1, all evidence is returned.
+func (store *Store) PendingEvidence(maxNum int64) (evidence []types.Evidence) {
+	return store.listEvidence(baseKeyPending, maxNum)
+}
+
+// listEvidence lists up to maxNum pieces of evidence for the given prefix key.
+// If maxNum is -1, there's no cap on the size of returned evidence.
+func (store *Store) listEvidence(prefixKey string, maxNum int64) (evidence []types.Evidence) {
+	var count int64
+	iter := dbm.IteratePrefix(store.db, []byte(prefixKey))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if count == maxNum {
+			return evidence
+		}
+		count++
+
+		var ei EvidenceInfo
+		amino.MustUnmarshal(iter.Value(), &ei)
+		evidence = append(evidence, ei.Evidence)
+	}
+	return evidence
+}
+
+// GetEvidenceInfo fetches the EvidenceInfo with the given height and hash.
+// If not found, ei.Evidence is nil.
+func (store *Store) GetEvidenceInfo(height int64, hash []byte) EvidenceInfo {
+	key := keyLookupFromHeightAndHash(height, hash)
+	val := store.db.Get(key)
+	if len(val) == 0 {
+		return EvidenceInfo{}
+	}
+
+	var ei EvidenceInfo
+	amino.MustUnmarshal(val, &ei)
+	return ei
+}
+
+// AddNewEvidence adds the given evidence to the database.
+// It returns false if the evidence is already stored.
+func (store *Store) AddNewEvidence(evidence types.Evidence) bool {
+	// check if we already have seen it
+	ei := store.getEvidenceInfo(evidence)
+	if ei.Evidence != nil {
+		return false
+	}
+
+	ei = EvidenceInfo{
+		Committed: false,
+		Evidence:  evidence,
+	}
+	eiBytes := amino.MustMarshal(ei)
+
+	// add it to the store
+	store.db.Set(keyPending(evidence), eiBytes)
+	store.db.SetSync(keyLookup(evidence), eiBytes)
+
+	return true
+}
+
+// MarkEvidenceAsCommitted removes evidence from pending and sets the state to committed.
+func (store *Store) MarkEvidenceAsCommitted(evidence types.Evidence) {
+	store.db.Delete(keyPending(evidence))
+
+	ei := EvidenceInfo{
+		Committed: true,
+		Evidence:  evidence,
+	}
+	store.db.SetSync(keyLookup(evidence), amino.MustMarshal(ei))
+}
+
+// MarkEvidenceAsExpired removes evidence from pending, as it can no longer
+// be committed. It is still remembered, so it's not added again.
+func (store *Store) MarkEvidenceAsExpired(evidence types.Evidence) {
+	store.db.DeleteSync(keyPending(evidence))
+}
+
+// ----------------------------------------
+// utils
+
+// getEvidenceInfo is convenience for calling GetEvidenceInfo if we have the full evidence.
+func (store *Store) getEvidenceInfo(evidence types.Evidence) EvidenceInfo {
+	return store.GetEvidenceInfo(evidence.Height(), evidence.Hash())
+}
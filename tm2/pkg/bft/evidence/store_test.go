@@ -0,0 +1,90 @@
+package evidence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+func TestStoreAddDuplicate(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(memdb.NewMemDB())
+
+	ev := types.NewMockGoodEvidence(2, 1, crypto.AddressFromPreimage([]byte("val1")))
+	assert.True(t, store.AddNewEvidence(ev))
+
+	// cant add twice
+	assert.False(t, store.AddNewEvidence(ev))
+}
+
+func TestStoreCommitDuplicate(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(memdb.NewMemDB())
+
+	ev := types.NewMockGoodEvidence(2, 1, crypto.AddressFromPreimage([]byte("val1")))
+	store.MarkEvidenceAsCommitted(ev)
+
+	// committed evidence is still known
+	assert.False(t, store.AddNewEvidence(ev))
+	assert.True(t, store.getEvidenceInfo(ev).Committed)
+}
+
+func TestStoreMark(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(memdb.NewMemDB())
+
+	// before we do anything, pending is empty
+	assert.Empty(t, store.PendingEvidence(-1))
+
+	ev := types.NewMockGoodEvidence(2, 1, crypto.AddressFromPreimage([]byte("val1")))
+	assert.True(t, store.AddNewEvidence(ev))
+
+	// get the evidence. verify. should be uncommitted
+	ei := store.GetEvidenceInfo(ev.Height(), ev.Hash())
+	assert.Equal(t, ev, ei.Evidence)
+	assert.False(t, ei.Committed)
+
+	// new evidence should be returns in pending
+	assert.Len(t, store.PendingEvidence(-1), 1)
+
+	// mark the evidence as committed
+	store.MarkEvidenceAsCommitted(ev)
+
+	// pending should be empty
+	assert.Empty(t, store.PendingEvidence(-1))
+
+	// evidence should show committed
+	ei = store.GetEvidenceInfo(ev.Height(), ev.Hash())
+	assert.Equal(t, ev, ei.Evidence)
+	assert.True(t, ei.Committed)
+}
+
+func TestStorePendingOrder(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(memdb.NewMemDB())
+
+	addr := crypto.AddressFromPreimage([]byte("val1"))
+	evs := []types.Evidence{
+		types.NewMockGoodEvidence(10, 1, addr),
+		types.NewMockGoodEvidence(1, 1, addr),
+		types.NewMockGoodEvidence(5, 1, addr),
+	}
+	for _, ev := range evs {
+		assert.True(t, store.AddNewEvidence(ev))
+	}
+
+	// the oldest evidence comes first
+	pending := store.PendingEvidence(-1)
+	assert.Equal(t, []types.Evidence{evs[1], evs[2], evs[0]}, pending)
+
+	// and the number of evidence can be capped
+	assert.Equal(t, []types.Evidence{evs[1], evs[2]}, store.PendingEvidence(2))
+}
@@ -0,0 +1,528 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+const (
+	defaultMaxClockDrift = 10 * time.Second
+	defaultPruningSize   = 1000
+)
+
+// TrustOptions are the options of the header the light client is
+// initialized with, which is trusted out of band.
+type TrustOptions struct {
+	// Period is the trusting period: how long a trusted header can be used
+	// to verify other headers. It must be shorter than the period during
+	// which the validators can be punished for misbehaving.
+	Period time.Duration
+
+	// Height and Hash identify the trusted header.
+	Height int64
+	Hash   []byte
+}
+
+// ValidateBasic performs basic validation of the trust options.
+func (opts TrustOptions) ValidateBasic() error {
+	if opts.Period <= 0 {
+		return errors.New("trusting period must be positive")
+	}
+	if opts.Height <= 0 {
+		return errors.New("trusted height must be positive")
+	}
+	if len(opts.Hash) == 0 {
+		return errors.New("trusted hash is required")
+	}
+	return nil
+}
+
+// Option sets an optional parameter of the Client.
+type Option func(*Client)
+
+// WithTrustLevel sets the trust level used for skipping verification. It
+// must be within [1/3, 1]; the default is 1/3.
+func WithTrustLevel(lvl TrustLevel) Option {
+	return func(c *Client) {
+		c.trustLevel = lvl
+	}
+}
+
+// WithMaxClockDrift sets how far in the future the time of a header can be.
+func WithMaxClockDrift(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxClockDrift = d
+	}
+}
+
+// WithSequentialVerification makes the client verify all the headers
+// between a trusted header and a new one, instead of skipping them.
+func WithSequentialVerification() Option {
+	return func(c *Client) {
+		c.sequential = true
+	}
+}
+
+// WithPruningSize sets the number of trusted headers kept in the store.
+func WithPruningSize(size int) Option {
+	return func(c *Client) {
+		c.pruningSize = size
+	}
+}
+
+// WithLogger sets the logger of the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// Client is a light client, which verifies the headers of a chain fetched
+// from a primary provider, from the headers it trusts. See the package
+// documentation.
+type Client struct {
+	chainID        string
+	trustingPeriod time.Duration
+	trustLevel     TrustLevel
+	maxClockDrift  time.Duration
+	sequential     bool
+	pruningSize    int
+	logger         *slog.Logger
+
+	primary   Provider
+	witnesses []Provider
+	store     *Store
+
+	// mtx serializes the verifications, and guards the latest trusted
+	// header.
+	mtx          sync.Mutex
+	latestHeader *types.SignedHeader
+	latestVals   *types.ValidatorSet
+}
+
+// NewClient returns a light client of the given chain, verifying the headers
+// fetched from primary and checking them against the witnesses. The trusted
+// headers are persisted in store.
+//
+// If the store is empty, the client is initialized with the header described
+// by trustOptions, which is fetched from the primary. Otherwise, the client
+// resumes from the latest header in the store.
+func NewClient(
+	chainID string,
+	trustOptions TrustOptions,
+	primary Provider,
+	witnesses []Provider,
+	store *Store,
+	options ...Option,
+) (*Client, error) {
+	if err := trustOptions.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid trust options: %w", err)
+	}
+
+	c := &Client{
+		chainID:        chainID,
+		trustingPeriod: trustOptions.Period,
+		trustLevel:     DefaultTrustLevel,
+		maxClockDrift:  defaultMaxClockDrift,
+		pruningSize:    defaultPruningSize,
+		logger:         log.NewNoopLogger(),
+		primary:        primary,
+		witnesses:      witnesses,
+		store:          store,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	if err := c.trustLevel.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if c.pruningSize <= 0 {
+		return nil, errors.New("pruning size must be positive")
+	}
+	if primary.ChainID() != chainID {
+		return nil, fmt.Errorf("primary belongs to another chain %q", primary.ChainID())
+	}
+	for i, w := range witnesses {
+		if w.ChainID() != chainID {
+			return nil, fmt.Errorf("witness #%d belongs to another chain %q", i, w.ChainID())
+		}
+	}
+
+	if lastHeight := store.LastHeight(); lastHeight > 0 {
+		if err := c.restoreTrustedHeader(lastHeight, trustOptions); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	if err := c.initializeWithTrustOptions(trustOptions); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// restoreTrustedHeader resumes from the latest header in the store. If the
+// store has a header at the trusted height, it must match the trusted hash.
+func (c *Client) restoreTrustedHeader(lastHeight int64, opts TrustOptions) error {
+	if sh, err := c.store.SignedHeader(opts.Height); err == nil && !bytes.Equal(sh.Hash(), opts.Hash) {
+		return fmt.Errorf("trusted header at height %d in the store has hash %X, expected %X",
+			opts.Height, sh.Hash(), opts.Hash)
+	}
+
+	sh, err := c.store.SignedHeader(lastHeight)
+	if err != nil {
+		return err
+	}
+	vals, err := c.store.ValidatorSet(lastHeight)
+	if err != nil {
+		return err
+	}
+	c.latestHeader, c.latestVals = sh, vals
+	c.logger.Info("Restored trusted header from the store", "height", lastHeight)
+	return nil
+}
+
+// initializeWithTrustOptions fetches the header described by the trust
+// options from the primary, and trusts it once checked.
+func (c *Client) initializeWithTrustOptions(opts TrustOptions) error {
+	sh, vals, err := c.fetchLightBlock(opts.Height)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sh.Hash(), opts.Hash) {
+		return fmt.Errorf("header hash %X at height %d doesn't match the trusted hash %X",
+			sh.Hash(), opts.Height, opts.Hash)
+	}
+	if err := sh.ValidateBasic(c.chainID); err != nil {
+		return ErrInvalidHeader{err}
+	}
+	if !bytes.Equal(sh.ValidatorsHash, vals.Hash()) {
+		return ErrInvalidHeader{fmt.Errorf("validators hash %X doesn't match the validator set %X",
+			sh.ValidatorsHash, vals.Hash())}
+	}
+	if err := vals.VerifyCommit(c.chainID, sh.Commit.BlockID, sh.Height, sh.Commit); err != nil {
+		return ErrInvalidHeader{err}
+	}
+	if err := c.compareWithWitnesses(sh); err != nil {
+		return err
+	}
+
+	c.saveTrusted(sh, vals)
+	return nil
+}
+
+// ChainID returns the chain ID of the client.
+func (c *Client) ChainID() string {
+	return c.chainID
+}
+
+// TrustedHeader returns the trusted header at the given height, or the
+// latest trusted header if the height is 0. It doesn't fetch anything:
+// ErrHeaderNotFound is returned if the header isn't trusted yet.
+func (c *Client) TrustedHeader(height int64) (*types.SignedHeader, error) {
+	if height == 0 {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		return c.latestHeader, nil
+	}
+	return c.store.SignedHeader(height)
+}
+
+// TrustedValidatorSet returns the validator set which signed the trusted
+// header at the given height, or the latest trusted header if the height is
+// 0.
+func (c *Client) TrustedValidatorSet(height int64) (*types.ValidatorSet, error) {
+	if height == 0 {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		return c.latestVals, nil
+	}
+	return c.store.ValidatorSet(height)
+}
+
+// LastTrustedHeight returns the height of the latest trusted header.
+func (c *Client) LastTrustedHeight() int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.latestHeader.Height
+}
+
+// Update fetches the latest header from the primary, and verifies it if it
+// is newer than the latest trusted header. It returns the latest trusted
+// header.
+func (c *Client) Update(now time.Time) (*types.SignedHeader, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	latest, err := c.primary.SignedHeader(0)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Height <= c.latestHeader.Height {
+		return c.latestHeader, nil
+	}
+	vals, err := c.primary.ValidatorSet(latest.Height)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verifyHeader(latest, vals, now); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// VerifyHeaderAtHeight fetches the header at the given height from the
+// primary and verifies it, if it isn't trusted yet. It returns the trusted
+// header.
+func (c *Client) VerifyHeaderAtHeight(height int64, now time.Time) (*types.SignedHeader, error) {
+	if height <= 0 {
+		return nil, errors.New("height must be positive")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if sh, err := c.store.SignedHeader(height); err == nil {
+		return sh, nil
+	}
+	sh, vals, err := c.fetchLightBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verifyHeader(sh, vals, now); err != nil {
+		return nil, err
+	}
+	return sh, nil
+}
+
+// VerifyHeader verifies the given header, signed by the given validator
+// set, and trusts it. If a header is already trusted at the same height,
+// they must be the same.
+func (c *Client) VerifyHeader(sh *types.SignedHeader, vals *types.ValidatorSet, now time.Time) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if trusted, err := c.store.SignedHeader(sh.Height); err == nil {
+		if !bytes.Equal(trusted.Hash(), sh.Hash()) {
+			return fmt.Errorf("header hash %X at height %d doesn't match the trusted header hash %X",
+				sh.Hash(), sh.Height, trusted.Hash())
+		}
+		return nil
+	}
+	return c.verifyHeader(sh, vals, now)
+}
+
+// verifyHeader verifies a new header from the trusted headers, and saves
+// it.
+func (c *Client) verifyHeader(sh *types.SignedHeader, vals *types.ValidatorSet, now time.Time) error {
+	var err error
+	switch {
+	case sh.Height > c.latestHeader.Height:
+		err = c.verifyForward(c.latestHeader, c.latestVals, sh, vals, now)
+	case sh.Height < c.store.FirstHeight():
+		err = c.verifyBackwards(sh, vals)
+	default:
+		// Verify from the closest trusted header below.
+		var trusted *types.SignedHeader
+		var trustedVals *types.ValidatorSet
+		if trusted, trustedVals, err = c.closestTrustedBelow(sh.Height); err == nil {
+			err = c.verifyForward(trusted, trustedVals, sh, vals, now)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := c.compareWithWitnesses(sh); err != nil {
+		return err
+	}
+	c.saveTrusted(sh, vals)
+	c.logger.Info("Verified header", "height", sh.Height, "hash", fmt.Sprintf("%X", sh.Hash()))
+	return nil
+}
+
+// verifyForward verifies a header following a trusted header, either
+// sequentially or by skipping.
+func (c *Client) verifyForward(
+	trusted *types.SignedHeader,
+	trustedVals *types.ValidatorSet,
+	target *types.SignedHeader,
+	targetVals *types.ValidatorSet,
+	now time.Time,
+) error {
+	if c.sequential {
+		return c.verifySequential(trusted, target, targetVals, now)
+	}
+	return c.verifySkipping(trusted, trustedVals, target, targetVals, now)
+}
+
+// verifySequential verifies all the headers from the trusted header to the
+// target header, one height at a time.
+func (c *Client) verifySequential(
+	trusted *types.SignedHeader,
+	target *types.SignedHeader,
+	targetVals *types.ValidatorSet,
+	now time.Time,
+) error {
+	for height := trusted.Height + 1; height <= target.Height; height++ {
+		sh, vals := target, targetVals
+		if height < target.Height {
+			var err error
+			if sh, vals, err = c.fetchLightBlock(height); err != nil {
+				return err
+			}
+		}
+		if err := VerifyAdjacent(c.chainID, trusted, sh, vals, c.trustingPeriod, now, c.maxClockDrift); err != nil {
+			return fmt.Errorf("failed to verify header at height %d: %w", height, err)
+		}
+		trusted = sh
+	}
+	return nil
+}
+
+// verifySkipping verifies the target header from the trusted header,
+// bisecting the range between them as long as the trusted validators can't
+// verify the next header.
+func (c *Client) verifySkipping(
+	trusted *types.SignedHeader,
+	trustedVals *types.ValidatorSet,
+	target *types.SignedHeader,
+	targetVals *types.ValidatorSet,
+	now time.Time,
+) error {
+	type lightBlock struct {
+		header *types.SignedHeader
+		vals   *types.ValidatorSet
+	}
+
+	// pending is a stack of the headers to verify, the target at the bottom.
+	pending := []lightBlock{{target, targetVals}}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		err := Verify(c.chainID, trusted, trustedVals, next.header, next.vals,
+			c.trustingPeriod, now, c.maxClockDrift, c.trustLevel)
+		var cantTrust ErrNewValSetCantBeTrusted
+		switch {
+		case err == nil:
+			c.logger.Debug("Verified header", "height", next.header.Height, "from", trusted.Height)
+			trusted, trustedVals = next.header, next.vals
+			pending = pending[:len(pending)-1]
+			// The intermediate headers are trusted as well.
+			if len(pending) > 0 {
+				c.saveTrusted(trusted, trustedVals)
+			}
+
+		case errors.As(err, &cantTrust):
+			pivot := (trusted.Height + next.header.Height) / 2
+			c.logger.Debug("Can't trust header, verifying a pivot header first",
+				"height", next.header.Height, "pivot", pivot)
+			sh, vals, err := c.fetchLightBlock(pivot)
+			if err != nil {
+				return err
+			}
+			pending = append(pending, lightBlock{sh, vals})
+
+		default:
+			return fmt.Errorf("failed to verify header at height %d from height %d: %w",
+				next.header.Height, trusted.Height, err)
+		}
+	}
+	return nil
+}
+
+// verifyBackwards verifies a header preceding the earliest trusted header,
+// through the chain of block hashes.
+func (c *Client) verifyBackwards(target *types.SignedHeader, targetVals *types.ValidatorSet) error {
+	firstHeight := c.store.FirstHeight()
+	trusted, err := c.store.SignedHeader(firstHeight)
+	if err != nil {
+		return err
+	}
+	for height := firstHeight - 1; height > target.Height; height-- {
+		sh, err := c.primary.SignedHeader(height)
+		if err != nil {
+			return err
+		}
+		if err := VerifyBackwards(c.chainID, sh, trusted); err != nil {
+			return fmt.Errorf("failed to verify header at height %d: %w", height, err)
+		}
+		trusted = sh
+	}
+	if err := VerifyBackwards(c.chainID, target, trusted); err != nil {
+		return fmt.Errorf("failed to verify header at height %d: %w", target.Height, err)
+	}
+	if !bytes.Equal(target.ValidatorsHash, targetVals.Hash()) {
+		return ErrInvalidHeader{fmt.Errorf("validators hash %X doesn't match the validator set %X",
+			target.ValidatorsHash, targetVals.Hash())}
+	}
+	return nil
+}
+
+// closestTrustedBelow returns the trusted header closest to the given
+// height, below it.
+func (c *Client) closestTrustedBelow(height int64) (*types.SignedHeader, *types.ValidatorSet, error) {
+	heights := c.store.Heights()
+	for i := len(heights) - 1; i >= 0; i-- {
+		if heights[i] >= height {
+			continue
+		}
+		sh, err := c.store.SignedHeader(heights[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		vals, err := c.store.ValidatorSet(heights[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		return sh, vals, nil
+	}
+	return nil, nil, fmt.Errorf("%w: no trusted header below height %d", ErrHeaderNotFound, height)
+}
+
+// fetchLightBlock fetches the header and the validator set at the given
+// height from the primary.
+func (c *Client) fetchLightBlock(height int64) (*types.SignedHeader, *types.ValidatorSet, error) {
+	sh, err := c.primary.SignedHeader(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	vals, err := c.primary.ValidatorSet(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sh, vals, nil
+}
+
+// compareWithWitnesses checks that the witnesses have the same header as the
+// primary. Witnesses which fail to respond are skipped.
+func (c *Client) compareWithWitnesses(sh *types.SignedHeader) error {
+	for i, w := range c.witnesses {
+		other, err := w.SignedHeader(sh.Height)
+		if err != nil {
+			c.logger.Info("Failed to fetch header from witness", "witness", i, "height", sh.Height, "err", err)
+			continue
+		}
+		if !bytes.Equal(other.Hash(), sh.Hash()) {
+			return ErrConflictingHeaders{Height: sh.Height, Witness: i}
+		}
+	}
+	return nil
+}
+
+// saveTrusted saves a trusted header, and prunes the earliest ones.
+func (c *Client) saveTrusted(sh *types.SignedHeader, vals *types.ValidatorSet) {
+	c.store.Save(sh, vals)
+	if c.latestHeader == nil || sh.Height > c.latestHeader.Height {
+		c.latestHeader, c.latestVals = sh, vals
+	}
+	if pruned := c.store.Prune(c.pruningSize); pruned > 0 {
+		c.logger.Debug("Pruned trusted headers", "pruned", pruned)
+	}
+}
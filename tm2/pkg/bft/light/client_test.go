@@ -0,0 +1,176 @@
+package light
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+func newTestClient(t *testing.T, chain *testChain, trustHeight int64, witnesses []Provider, options ...Option) *Client {
+	t.Helper()
+
+	c, err := NewClient(
+		testChainID,
+		TrustOptions{Period: trustingPeriod, Height: trustHeight, Hash: chain.headers[trustHeight].Hash()},
+		&mockProvider{chain},
+		witnesses,
+		NewStore(memdb.NewMemDB()),
+		options...,
+	)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_VerifySkipping(t *testing.T) {
+	t.Parallel()
+
+	// The validator set is entirely replaced every 4 heights, so the client
+	// has to bisect.
+	chain := newTestChain(t, 20, 4, nil)
+	now := chain.headers[20].Time.Add(time.Minute)
+	c := newTestClient(t, chain, 1, []Provider{&mockProvider{chain}})
+
+	sh, err := c.VerifyHeaderAtHeight(20, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[20].Hash(), sh.Hash())
+	assert.Equal(t, int64(20), c.LastTrustedHeight())
+
+	// Some intermediate headers are trusted, but not all of them.
+	heights := c.store.Heights()
+	assert.Greater(t, len(heights), 2)
+	assert.Less(t, len(heights), 20)
+
+	// Headers in between are verified from the closest trusted header.
+	sh, err = c.VerifyHeaderAtHeight(10, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[10].Hash(), sh.Hash())
+}
+
+func TestClient_VerifySequential(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 4, nil)
+	now := chain.headers[10].Time.Add(time.Minute)
+	c := newTestClient(t, chain, 1, nil, WithSequentialVerification())
+
+	sh, err := c.VerifyHeaderAtHeight(10, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[10].Hash(), sh.Hash())
+
+	// Only the target header is saved.
+	assert.Equal(t, []int64{1, 10}, c.store.Heights())
+}
+
+func TestClient_VerifyBackwards(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 4, nil)
+	now := chain.headers[10].Time.Add(time.Minute)
+	c := newTestClient(t, chain, 8, nil)
+
+	sh, err := c.VerifyHeaderAtHeight(3, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[3].Hash(), sh.Hash())
+	assert.Equal(t, int64(8), c.LastTrustedHeight())
+}
+
+func TestClient_Update(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 3, nil)
+	now := chain.headers[10].Time.Add(time.Minute)
+	c := newTestClient(t, chain, 2, nil)
+
+	sh, err := c.Update(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), sh.Height)
+	assert.Equal(t, int64(10), c.LastTrustedHeight())
+
+	// Nothing new.
+	sh, err = c.Update(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), sh.Height)
+}
+
+func TestClient_ExpiredTrustedHeader(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 4, nil)
+	now := chain.headers[1].Time.Add(trustingPeriod)
+	c := newTestClient(t, chain, 1, nil)
+
+	_, err := c.VerifyHeaderAtHeight(10, now)
+	assert.ErrorAs(t, err, &ErrOldHeaderExpired{})
+	assert.Equal(t, int64(1), c.LastTrustedHeight())
+}
+
+func TestClient_ConflictingWitness(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 4, nil)
+	forked := newTestChain(t, 10, 4, nil)
+	for h := int64(1); h <= 5; h++ {
+		forked.headers[h], forked.vals[h] = chain.headers[h], chain.vals[h]
+	}
+	now := chain.headers[10].Time.Add(time.Minute)
+	c := newTestClient(t, chain, 1, []Provider{&mockProvider{chain}, &mockProvider{forked}})
+
+	_, err := c.VerifyHeaderAtHeight(4, now)
+	require.NoError(t, err)
+
+	_, err = c.VerifyHeaderAtHeight(10, now)
+	assert.Equal(t, ErrConflictingHeaders{Height: 10, Witness: 1}, err)
+	_, err = c.TrustedHeader(10)
+	assert.ErrorIs(t, err, ErrHeaderNotFound)
+}
+
+func TestClient_InvalidTrustOptions(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 3, 4, nil)
+	_, err := NewClient(
+		testChainID,
+		TrustOptions{Period: trustingPeriod, Height: 2, Hash: chain.headers[1].Hash()},
+		&mockProvider{chain},
+		nil,
+		NewStore(memdb.NewMemDB()),
+	)
+	assert.Error(t, err)
+
+	_, err = NewClient(
+		testChainID,
+		TrustOptions{Period: trustingPeriod, Height: 1, Hash: chain.headers[1].Hash()},
+		&mockProvider{chain},
+		nil,
+		NewStore(memdb.NewMemDB()),
+		WithTrustLevel(TrustLevel{1, 4}),
+	)
+	assert.Error(t, err)
+}
+
+func TestClient_RestoreFromStore(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 4, nil)
+	now := chain.headers[10].Time.Add(time.Minute)
+	db := memdb.NewMemDB()
+	opts := TrustOptions{Period: trustingPeriod, Height: 1, Hash: chain.headers[1].Hash()}
+
+	c, err := NewClient(testChainID, opts, &mockProvider{chain}, nil, NewStore(db))
+	require.NoError(t, err)
+	_, err = c.VerifyHeaderAtHeight(10, now)
+	require.NoError(t, err)
+
+	c, err = NewClient(testChainID, opts, &mockProvider{chain}, nil, NewStore(db))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), c.LastTrustedHeight())
+
+	// The trusted header must match the store.
+	opts.Hash = chain.headers[2].Hash()
+	_, err = NewClient(testChainID, opts, &mockProvider{chain}, nil, NewStore(db))
+	assert.Error(t, err)
+}
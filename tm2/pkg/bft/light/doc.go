@@ -0,0 +1,37 @@
+/*
+Package light implements a light client, which follows a chain by verifying
+its headers with the signatures of its validators, without executing its
+blocks.
+
+The client starts from a header trusted out of band, identified by its height
+and hash, and then verifies the headers it is asked for from the headers it
+already trusts:
+
+  - a header directly following a trusted header is verified with the
+    commit of the validators set by the trusted header (sequential
+    verification);
+  - a header further away is verified if more than the trust level (1/3 by
+    default) of the voting power of the validators of a trusted header signed
+    it, in which case the header can't be forged without some of these
+    validators misbehaving. Otherwise, the client verifies a header in the
+    middle first, and tries again from there (skipping verification, or
+    bisection);
+  - a header preceding a trusted header is verified through the chain of
+    block hashes.
+
+A trusted header can only be used to verify other headers within the trusting
+period, which must be shorter than the period during which the validators can
+be punished for misbehaving.
+
+The headers are fetched from a primary Provider, and checked against the
+headers of the witness providers, to detect a primary forking the chain.
+
+The VerifyingClient wraps an RPC client, and uses a light client to verify
+the responses of the RPC node: blocks, commits and validators are checked
+against the verified headers, and ABCI queries are checked with their Merkle
+proofs against the app hash of the verified headers.
+Queries are made at past heights, at least at the height preceding the latest
+verified header, so the RPC node must keep recent states (see the pruning
+strategy of gnoland start, keeping them by default).
+*/
+package light
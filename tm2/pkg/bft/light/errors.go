@@ -0,0 +1,69 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrHeaderNotFound is returned when a header or validator set isn't
+	// available at the requested height.
+	ErrHeaderNotFound = errors.New("header not found")
+
+	// ErrUnverifiableQuery is returned by the VerifyingClient for queries
+	// whose response can't be proven.
+	ErrUnverifiableQuery = errors.New("query response can't be verified")
+)
+
+// ErrOldHeaderExpired is returned when the trusted header used to verify a
+// new header is beyond the trusting period.
+type ErrOldHeaderExpired struct {
+	At  time.Time
+	Now time.Time
+}
+
+func (e ErrOldHeaderExpired) Error() string {
+	return fmt.Sprintf("old header has expired at %v (now: %v)", e.At, e.Now)
+}
+
+// ErrNewValSetCantBeTrusted is returned when not enough of the trusted
+// validators signed a new header. The header may still be valid: a header
+// between the trusted header and the new one must be verified first.
+type ErrNewValSetCantBeTrusted struct {
+	Reason error
+}
+
+func (e ErrNewValSetCantBeTrusted) Error() string {
+	return fmt.Sprintf("can't trust new validator set: %v", e.Reason)
+}
+
+func (e ErrNewValSetCantBeTrusted) Unwrap() error {
+	return e.Reason
+}
+
+// ErrInvalidHeader is returned when a header is malformed, or not signed by
+// its validators.
+type ErrInvalidHeader struct {
+	Reason error
+}
+
+func (e ErrInvalidHeader) Error() string {
+	return fmt.Sprintf("invalid header: %v", e.Reason)
+}
+
+func (e ErrInvalidHeader) Unwrap() error {
+	return e.Reason
+}
+
+// ErrConflictingHeaders is returned when a witness has a different header
+// than the primary at the same height, which means that one of them is
+// faulty or that the chain was forked.
+type ErrConflictingHeaders struct {
+	Height  int64
+	Witness int // index of the witness
+}
+
+func (e ErrConflictingHeaders) Error() string {
+	return fmt.Sprintf("header at height %d of witness #%d conflicts with the primary", e.Height, e.Witness)
+}
@@ -0,0 +1,107 @@
+package light
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+const testChainID = "test-chain"
+
+var genTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testChain is a chain of signed headers, with the validator sets signing
+// them.
+type testChain struct {
+	headers map[int64]*types.SignedHeader
+	vals    map[int64]*types.ValidatorSet
+	height  int64
+}
+
+// newTestChain generates a chain of the given height, whose validator set is
+// replaced every valsPeriod heights. The block times are one minute apart,
+// from genTime. The app hash of a height is taken from appHashes if set.
+func newTestChain(t *testing.T, height int64, valsPeriod int64, appHashes map[int64][]byte) *testChain {
+	t.Helper()
+
+	type valSet struct {
+		vals     *types.ValidatorSet
+		privVals []types.PrivValidator
+	}
+	sets := make(map[int64]valSet)
+	valsAt := func(height int64) valSet {
+		epoch := (height - 1) / valsPeriod
+		if _, ok := sets[epoch]; !ok {
+			vals, privVals := types.RandValidatorSet(4, 10)
+			sets[epoch] = valSet{vals, privVals}
+		}
+		return sets[epoch]
+	}
+
+	c := &testChain{
+		headers: make(map[int64]*types.SignedHeader),
+		vals:    make(map[int64]*types.ValidatorSet),
+		height:  height,
+	}
+	var lastBlockID types.BlockID
+	for h := int64(1); h <= height; h++ {
+		set := valsAt(h)
+		appHash, ok := appHashes[h]
+		if !ok {
+			appHash = []byte(fmt.Sprintf("app_hash_%d", h))
+		}
+		header := &types.Header{
+			ChainID:            testChainID,
+			Height:             h,
+			Time:               genTime.Add(time.Duration(h) * time.Minute),
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     set.vals.Hash(),
+			NextValidatorsHash: valsAt(h + 1).vals.Hash(),
+			AppHash:            appHash,
+		}
+		blockID := types.BlockID{Hash: header.Hash()}
+		voteSet := types.NewVoteSet(testChainID, h, 0, types.PrecommitType, set.vals)
+		commit, err := types.MakeCommit(blockID, h, 0, voteSet, set.privVals)
+		require.NoError(t, err)
+
+		c.headers[h] = &types.SignedHeader{Header: header, Commit: commit}
+		c.vals[h] = set.vals
+		lastBlockID = blockID
+	}
+	return c
+}
+
+// mockProvider is a Provider serving the headers of a testChain.
+type mockProvider struct {
+	chain *testChain
+}
+
+func (p *mockProvider) ChainID() string {
+	return testChainID
+}
+
+func (p *mockProvider) SignedHeader(height int64) (*types.SignedHeader, error) {
+	if height == 0 {
+		height = p.chain.height
+	}
+	sh, ok := p.chain.headers[height]
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+	return sh, nil
+}
+
+func (p *mockProvider) ValidatorSet(height int64) (*types.ValidatorSet, error) {
+	if height == 0 {
+		height = p.chain.height
+	}
+	vals, ok := p.chain.vals[height]
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+	return vals.Copy(), nil
+}
@@ -0,0 +1,106 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// Provider provides the signed headers and validator sets of a chain. They
+// are not trusted: the light client verifies them.
+type Provider interface {
+	// ChainID returns the chain ID of the provided headers.
+	ChainID() string
+
+	// SignedHeader returns the signed header at the given height, or the
+	// latest one if the height is 0. ErrHeaderNotFound is returned if the
+	// header isn't available.
+	SignedHeader(height int64) (*types.SignedHeader, error)
+
+	// ValidatorSet returns the validator set which signs the block at the
+	// given height, or the latest one if the height is 0.
+	// ErrHeaderNotFound is returned if the validator set isn't available.
+	ValidatorSet(height int64) (*types.ValidatorSet, error)
+}
+
+// rpcProvider is a Provider fetching the headers and validator sets from an
+// RPC node, with the commit and validators RPCs.
+type rpcProvider struct {
+	chainID string
+	client  client.SignClient
+}
+
+// NewRPCProvider returns a Provider fetching the headers of the given chain
+// from an RPC node.
+func NewRPCProvider(chainID string, c client.SignClient) Provider {
+	return &rpcProvider{
+		chainID: chainID,
+		client:  c,
+	}
+}
+
+// NewHTTPProvider returns a Provider fetching the headers of the given chain
+// from the RPC node at the given address.
+func NewHTTPProvider(chainID, remote string) (Provider, error) {
+	c, err := client.NewHTTPClient(remote)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create RPC client for %s: %w", remote, err)
+	}
+	return NewRPCProvider(chainID, c), nil
+}
+
+// ChainID implements Provider.
+func (p *rpcProvider) ChainID() string {
+	return p.chainID
+}
+
+// SignedHeader implements Provider.
+func (p *rpcProvider) SignedHeader(height int64) (*types.SignedHeader, error) {
+	res, err := p.client.Commit(heightPtr(height))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch header at height %d: %w", height, err)
+	}
+	header := res.SignedHeader
+	if header.Header == nil || header.Commit == nil {
+		return nil, fmt.Errorf("%w: height %d", ErrHeaderNotFound, height)
+	}
+	if height != 0 && header.Height != height {
+		return nil, fmt.Errorf("expected header at height %d, got height %d", height, header.Height)
+	}
+	if header.ChainID != p.chainID {
+		return nil, fmt.Errorf("header belongs to another chain %q, expected %q", header.ChainID, p.chainID)
+	}
+	return &header, nil
+}
+
+// ValidatorSet implements Provider.
+func (p *rpcProvider) ValidatorSet(height int64) (*types.ValidatorSet, error) {
+	res, err := p.client.Validators(heightPtr(height))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch validators at height %d: %w", height, err)
+	}
+	if len(res.Validators) == 0 {
+		return nil, fmt.Errorf("%w: no validators at height %d", ErrHeaderNotFound, height)
+	}
+	if height != 0 && res.BlockHeight != height {
+		return nil, fmt.Errorf("expected validators at height %d, got height %d", height, res.BlockHeight)
+	}
+	// The order of the validators must be kept, as the precommits of a
+	// commit are in the order of the validator set.
+	vals := &types.ValidatorSet{Validators: res.Validators}
+	for _, val := range vals.Validators {
+		if val == nil {
+			return nil, errors.New("nil validator in validator set")
+		}
+	}
+	return vals, nil
+}
+
+func heightPtr(height int64) *int64 {
+	if height == 0 {
+		return nil
+	}
+	return &height
+}
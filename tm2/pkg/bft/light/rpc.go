@@ -0,0 +1,236 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+)
+
+// VerifyingClient is an RPC client verifying the responses of an RPC node
+// with a light client:
+//
+//   - Block, Commit and Validators are checked against the verified headers;
+//   - ABCIQuery and ABCIQueryWithOptions are only supported for store queries
+//     (".store/<store>/key"), whose Merkle proofs are checked against the app
+//     hash of the verified headers. Other queries fail with
+//     ErrUnverifiableQuery.
+//
+// The other methods are not verified, and are passed through to the RPC
+// node: broadcasting a transaction doesn't require trusting the node, but
+// the status or the transactions returned by the node can't be trusted.
+type VerifyingClient struct {
+	client.Client
+
+	lc  *Client
+	prt *merkle.ProofRuntime
+	now func() time.Time
+}
+
+var _ client.Client = (*VerifyingClient)(nil)
+
+// NewVerifyingClient returns an RPC client verifying the responses of next
+// with the given light client.
+func NewVerifyingClient(next client.Client, lc *Client) *VerifyingClient {
+	return &VerifyingClient{
+		Client: next,
+		lc:     lc,
+		prt:    rootmulti.DefaultProofRuntime(),
+		now:    time.Now,
+	}
+}
+
+// LightClient returns the light client verifying the responses.
+func (c *VerifyingClient) LightClient() *Client {
+	return c.lc
+}
+
+// ABCIQuery implements client.Client. See ABCIQueryWithOptions.
+func (c *VerifyingClient) ABCIQuery(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+	return c.ABCIQueryWithOptions(path, data, client.DefaultABCIQueryOptions)
+}
+
+// ABCIQueryWithOptions implements client.Client. The query is made with a
+// proof, which is checked against the app hash of the verified header
+// following the query height. If no height is given, the query is made at
+// the height preceding the latest verified header.
+func (c *VerifyingClient) ABCIQueryWithOptions(path string, data []byte, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	storeName, ok := parseStoreKeyPath(path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a store key query", ErrUnverifiableQuery, path)
+	}
+
+	// The app hash of the state at a height is in the header of the next
+	// height.
+	var header *types.SignedHeader
+	if opts.Height == 0 {
+		latest, err := c.lc.Update(c.now())
+		if err != nil {
+			return nil, err
+		}
+		header = latest
+		opts.Height = latest.Height - 1
+	} else {
+		next, err := c.lc.VerifyHeaderAtHeight(opts.Height+1, c.now())
+		if err != nil {
+			return nil, err
+		}
+		header = next
+	}
+	opts.Prove = true
+
+	res, err := c.Client.ABCIQueryWithOptions(path, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := res.Response
+	if resp.IsErr() {
+		// Errors can't be proven: drop anything else from the response.
+		res.Response = abci.ResponseQuery{ResponseBase: resp.ResponseBase, Height: resp.Height}
+		return res, nil
+	}
+	if resp.Height != opts.Height {
+		return nil, fmt.Errorf("expected a response at height %d, got height %d", opts.Height, resp.Height)
+	}
+	if !bytes.Equal(resp.Key, data) {
+		return nil, fmt.Errorf("expected a response for key %X, got key %X", data, resp.Key)
+	}
+	if err := VerifyQuery(c.prt, storeName, resp.Key, resp.Value, resp.Proof, header.AppHash); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// VerifyQuery verifies the Merkle proof of the value of a key in a store
+// against the app hash. If the value is nil, the proof must prove the
+// absence of the key.
+func VerifyQuery(prt *merkle.ProofRuntime, storeName string, key, value []byte, proof *merkle.Proof, appHash []byte) error {
+	if proof == nil || len(proof.Ops) == 0 {
+		return errors.New("missing proof")
+	}
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingHex)
+
+	var err error
+	if value != nil {
+		err = prt.VerifyValue(proof, appHash, keyPath.String(), value)
+	} else {
+		err = prt.VerifyAbsence(proof, appHash, keyPath.String())
+	}
+	if err != nil {
+		return fmt.Errorf("invalid proof: %w", err)
+	}
+	return nil
+}
+
+// parseStoreKeyPath returns the store name of a ".store/<store>/key" query
+// path, which are the only ones returning proofs.
+func parseStoreKeyPath(path string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 3 || parts[0] != ".store" || parts[1] == "" || !rootmulti.RequireProof("/"+parts[2]) {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// Block implements client.Client. The block is checked against the
+// verified header at its height.
+func (c *VerifyingClient) Block(height *int64) (*ctypes.ResultBlock, error) {
+	res, err := c.Client.Block(height)
+	if err != nil {
+		return nil, err
+	}
+	if res.Block == nil || res.BlockMeta == nil {
+		return nil, errors.New("missing block")
+	}
+	if err := res.Block.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid block: %w", err)
+	}
+	if !bytes.Equal(res.BlockMeta.BlockID.Hash, res.Block.Hash()) {
+		return nil, fmt.Errorf("block meta hash %X doesn't match the block hash %X",
+			res.BlockMeta.BlockID.Hash, res.Block.Hash())
+	}
+
+	trusted, err := c.lc.VerifyHeaderAtHeight(res.Block.Height, c.now())
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(res.Block.Hash(), trusted.Hash()) {
+		return nil, fmt.Errorf("block hash %X at height %d doesn't match the verified header hash %X",
+			res.Block.Hash(), res.Block.Height, trusted.Hash())
+	}
+	return res, nil
+}
+
+// Commit implements client.Client. The header is checked against the
+// verified header at its height, and the commit against the validators
+// which signed it.
+func (c *VerifyingClient) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	res, err := c.Client.Commit(height)
+	if err != nil {
+		return nil, err
+	}
+	sh := res.SignedHeader
+	if sh.Header == nil || sh.Commit == nil {
+		return nil, errors.New("missing signed header")
+	}
+	if err := sh.ValidateBasic(c.lc.ChainID()); err != nil {
+		return nil, fmt.Errorf("invalid signed header: %w", err)
+	}
+
+	trusted, err := c.lc.VerifyHeaderAtHeight(sh.Height, c.now())
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(sh.Hash(), trusted.Hash()) {
+		return nil, fmt.Errorf("header hash %X at height %d doesn't match the verified header hash %X",
+			sh.Hash(), sh.Height, trusted.Hash())
+	}
+	vals, err := c.lc.TrustedValidatorSet(sh.Height)
+	if err != nil {
+		return nil, err
+	}
+	if err := vals.VerifyCommit(c.lc.ChainID(), sh.Commit.BlockID, sh.Height, sh.Commit); err != nil {
+		return nil, fmt.Errorf("invalid commit: %w", err)
+	}
+	return res, nil
+}
+
+// Validators implements client.Client. The validators are checked against
+// the verified header at their height. If no height is given, the
+// validators of the latest verified header are returned.
+func (c *VerifyingClient) Validators(height *int64) (*ctypes.ResultValidators, error) {
+	// The latest validators of a node sign the block which isn't committed
+	// yet, so they can't be verified.
+	if height == nil {
+		latest, err := c.lc.Update(c.now())
+		if err != nil {
+			return nil, err
+		}
+		height = &latest.Height
+	}
+
+	res, err := c.Client.Validators(height)
+	if err != nil {
+		return nil, err
+	}
+	trusted, err := c.lc.VerifyHeaderAtHeight(res.BlockHeight, c.now())
+	if err != nil {
+		return nil, err
+	}
+	vals := &types.ValidatorSet{Validators: res.Validators}
+	if !bytes.Equal(vals.Hash(), trusted.ValidatorsHash) {
+		return nil, fmt.Errorf("validators hash %X at height %d doesn't match the verified header %X",
+			vals.Hash(), res.BlockHeight, trusted.ValidatorsHash)
+	}
+	return res, nil
+}
@@ -0,0 +1,122 @@
+package light
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// mockRPCClient serves the headers of a testChain, and the queries of a
+// multistore.
+type mockRPCClient struct {
+	client.Client // unused methods panic
+
+	chain *testChain
+	ms    stypes.CommitMultiStore
+
+	tamper bool // tamper with the responses
+}
+
+func (c *mockRPCClient) ABCIQueryWithOptions(path string, data []byte, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	res := c.ms.(stypes.Queryable).Query(abci.RequestQuery{
+		Path:   strings.TrimPrefix(path, ".store"),
+		Data:   data,
+		Height: opts.Height,
+		Prove:  opts.Prove,
+	})
+	res.Height = opts.Height
+	if c.tamper {
+		res.Value = []byte("forged")
+	}
+	return &ctypes.ResultABCIQuery{Response: res}, nil
+}
+
+func (c *mockRPCClient) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	sh := *c.chain.headers[*height]
+	if c.tamper {
+		header := *sh.Header
+		header.AppHash = []byte("forged")
+		sh.Header = &header
+	}
+	return &ctypes.ResultCommit{SignedHeader: sh, CanonicalCommit: true}, nil
+}
+
+func (c *mockRPCClient) Validators(height *int64) (*ctypes.ResultValidators, error) {
+	vals := c.chain.vals[*height]
+	if c.tamper {
+		vals, _ = types.RandValidatorSet(4, 10)
+	}
+	return &ctypes.ResultValidators{BlockHeight: *height, Validators: vals.Validators}, nil
+}
+
+func TestVerifyingClient(t *testing.T) {
+	t.Parallel()
+
+	// Commit two versions of a multistore, whose hashes are the app hashes of
+	// the following headers.
+	ms := rootmulti.NewMultiStore(memdb.NewMemDB())
+	ms.SetStoreOptions(stypes.StoreOptions{PruningOptions: stypes.PruneNothing})
+	key := stypes.NewStoreKey("main")
+	ms.MountStoreWithDB(key, iavl.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+	ms.GetStore(key).Set([]byte("foo"), []byte("bar"))
+	cid1 := ms.Commit()
+	ms.GetStore(key).Set([]byte("foo"), []byte("baz"))
+	cid2 := ms.Commit()
+
+	chain := newTestChain(t, 3, 100, map[int64][]byte{2: cid1.Hash, 3: cid2.Hash})
+	now := chain.headers[3].Time.Add(time.Minute)
+	lc := newTestClient(t, chain, 1, nil)
+
+	next := &mockRPCClient{chain: chain, ms: ms}
+	c := NewVerifyingClient(next, lc)
+	c.now = func() time.Time { return now }
+
+	// Query at a given height.
+	res, err := c.ABCIQueryWithOptions(".store/main/key", []byte("foo"), client.ABCIQueryOptions{Height: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bar"), res.Response.Value)
+
+	// Query at the latest height.
+	res, err = c.ABCIQuery(".store/main/key", []byte("foo"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("baz"), res.Response.Value)
+	assert.Equal(t, int64(2), res.Response.Height)
+
+	// Absent key.
+	res, err = c.ABCIQuery(".store/main/key", []byte("missing"))
+	require.NoError(t, err)
+	assert.Nil(t, res.Response.Value)
+
+	// Unverifiable queries.
+	_, err = c.ABCIQuery("auth/accounts/g1foo", nil)
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+	_, err = c.ABCIQuery(".store/main/subspace", []byte("foo"))
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+
+	height := int64(2)
+	_, err = c.Commit(&height)
+	require.NoError(t, err)
+	_, err = c.Validators(&height)
+	require.NoError(t, err)
+
+	next.tamper = true
+	_, err = c.ABCIQueryWithOptions(".store/main/key", []byte("foo"), client.ABCIQueryOptions{Height: 1})
+	assert.Error(t, err)
+	_, err = c.Commit(&height)
+	assert.Error(t, err)
+	_, err = c.Validators(&height)
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package light
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+/*
+Schema of the trusted store:
+
+"sh/"<height> -> SignedHeader
+"vs/"<height> -> ValidatorSet
+
+The heights are big endian padded hex, so that they are iterated in order.
+*/
+
+const (
+	prefixSignedHeader = "sh/"
+	prefixValidatorSet = "vs/"
+)
+
+// Store persists the signed headers trusted by a light client, with the
+// validator sets which signed them.
+type Store struct {
+	mtx sync.Mutex
+	db  dbm.DB
+}
+
+// NewStore returns a new Store persisting the trusted headers in db.
+func NewStore(db dbm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Save saves a trusted signed header, and the validator set which signed
+// it.
+func (s *Store) Save(sh *types.SignedHeader, vals *types.ValidatorSet) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	batch.Set(keySignedHeader(sh.Height), amino.MustMarshal(sh))
+	batch.Set(keyValidatorSet(sh.Height), amino.MustMarshal(vals))
+	batch.WriteSync()
+}
+
+// SignedHeader returns the trusted signed header at the given height.
+func (s *Store) SignedHeader(height int64) (*types.SignedHeader, error) {
+	bz := s.db.Get(keySignedHeader(height))
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("%w: no trusted header at height %d", ErrHeaderNotFound, height)
+	}
+	sh := new(types.SignedHeader)
+	if err := amino.Unmarshal(bz, sh); err != nil {
+		return nil, fmt.Errorf("unable to decode trusted header at height %d: %w", height, err)
+	}
+	return sh, nil
+}
+
+// ValidatorSet returns the validator set which signed the trusted signed
+// header at the given height.
+func (s *Store) ValidatorSet(height int64) (*types.ValidatorSet, error) {
+	bz := s.db.Get(keyValidatorSet(height))
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("%w: no trusted validator set at height %d", ErrHeaderNotFound, height)
+	}
+	vals := new(types.ValidatorSet)
+	if err := amino.Unmarshal(bz, vals); err != nil {
+		return nil, fmt.Errorf("unable to decode trusted validator set at height %d: %w", height, err)
+	}
+	return vals, nil
+}
+
+// LastHeight returns the height of the latest trusted header, or 0 if the
+// store is empty.
+func (s *Store) LastHeight() int64 {
+	itr := s.db.ReverseIterator([]byte(prefixSignedHeader), prefixEnd(prefixSignedHeader))
+	defer itr.Close()
+	if !itr.Valid() {
+		return 0
+	}
+	return parseHeight(itr.Key())
+}
+
+// FirstHeight returns the height of the earliest trusted header, or 0 if the
+// store is empty.
+func (s *Store) FirstHeight() int64 {
+	itr := s.db.Iterator([]byte(prefixSignedHeader), prefixEnd(prefixSignedHeader))
+	defer itr.Close()
+	if !itr.Valid() {
+		return 0
+	}
+	return parseHeight(itr.Key())
+}
+
+// Heights returns the heights of the trusted headers, in ascending order.
+func (s *Store) Heights() []int64 {
+	itr := s.db.Iterator([]byte(prefixSignedHeader), prefixEnd(prefixSignedHeader))
+	defer itr.Close()
+
+	var heights []int64
+	for ; itr.Valid(); itr.Next() {
+		heights = append(heights, parseHeight(itr.Key()))
+	}
+	return heights
+}
+
+// Prune deletes the earliest trusted headers, so that at most size are
+// kept. It returns the number of deleted headers.
+func (s *Store) Prune(size int) int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	heights := s.Heights()
+	if len(heights) <= size {
+		return 0
+	}
+	pruned := heights[:len(heights)-size]
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	for _, height := range pruned {
+		batch.Delete(keySignedHeader(height))
+		batch.Delete(keyValidatorSet(height))
+	}
+	batch.WriteSync()
+	return len(pruned)
+}
+
+func keySignedHeader(height int64) []byte {
+	return []byte(fmt.Sprintf("%s%0.16X", prefixSignedHeader, height))
+}
+
+func keyValidatorSet(height int64) []byte {
+	return []byte(fmt.Sprintf("%s%0.16X", prefixValidatorSet, height))
+}
+
+func parseHeight(key []byte) int64 {
+	i := strings.IndexByte(string(key), '/')
+	height, err := strconv.ParseInt(string(key[i+1:]), 16, 64)
+	if err != nil {
+		panic(fmt.Sprintf("invalid trusted store key %q: %v", key, err))
+	}
+	return height
+}
+
+// prefixEnd returns the end of the domain of the keys with the given prefix,
+// which ends with a '/'.
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	end[len(end)-1]++
+	return end
+}
@@ -0,0 +1,44 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 5, 100, nil)
+	store := NewStore(memdb.NewMemDB())
+	assert.Equal(t, int64(0), store.FirstHeight())
+	assert.Equal(t, int64(0), store.LastHeight())
+
+	_, err := store.SignedHeader(1)
+	assert.ErrorIs(t, err, ErrHeaderNotFound)
+	_, err = store.ValidatorSet(1)
+	assert.ErrorIs(t, err, ErrHeaderNotFound)
+
+	for _, h := range []int64{4, 1, 2, 5} {
+		store.Save(chain.headers[h], chain.vals[h])
+	}
+	assert.Equal(t, int64(1), store.FirstHeight())
+	assert.Equal(t, int64(5), store.LastHeight())
+	assert.Equal(t, []int64{1, 2, 4, 5}, store.Heights())
+
+	sh, err := store.SignedHeader(4)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[4].Hash(), sh.Hash())
+	vals, err := store.ValidatorSet(4)
+	require.NoError(t, err)
+	assert.Equal(t, chain.vals[4].Hash(), vals.Hash())
+
+	assert.Equal(t, 0, store.Prune(4))
+	assert.Equal(t, 2, store.Prune(2))
+	assert.Equal(t, []int64{4, 5}, store.Heights())
+	_, err = store.ValidatorSet(2)
+	assert.ErrorIs(t, err, ErrHeaderNotFound)
+}
@@ -0,0 +1,191 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// TrustLevel is the fraction of the voting power of a trusted validator set
+// which must sign a non-adjacent header for it to be trusted.
+type TrustLevel struct {
+	Numerator   int64
+	Denominator int64
+}
+
+// DefaultTrustLevel is the trust level of the light client by default: as
+// more than 1/3 of the voting power is required, at least one honest
+// validator signed a trusted header.
+var DefaultTrustLevel = TrustLevel{Numerator: 1, Denominator: 3}
+
+func (lvl TrustLevel) String() string {
+	return fmt.Sprintf("%d/%d", lvl.Numerator, lvl.Denominator)
+}
+
+// ValidateBasic checks that the trust level is within [1/3, 1].
+func (lvl TrustLevel) ValidateBasic() error {
+	if lvl.Denominator <= 0 || lvl.Numerator*3 < lvl.Denominator || lvl.Numerator > lvl.Denominator {
+		return fmt.Errorf("trust level must be within [1/3, 1], got %v", lvl)
+	}
+	return nil
+}
+
+// VerifyAdjacent verifies a header directly following a trusted header: it
+// must be signed by more than 2/3 of the validators set by the trusted
+// header.
+func VerifyAdjacent(
+	chainID string,
+	trusted *types.SignedHeader,
+	untrusted *types.SignedHeader,
+	untrustedVals *types.ValidatorSet,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+) error {
+	if untrusted.Height != trusted.Height+1 {
+		return errors.New("headers must be adjacent in height")
+	}
+	if HeaderExpired(trusted, trustingPeriod, now) {
+		return ErrOldHeaderExpired{trusted.Time.Add(trustingPeriod), now}
+	}
+	if err := verifyNewHeaderAndVals(chainID, trusted, untrusted, untrustedVals, now, maxClockDrift); err != nil {
+		return ErrInvalidHeader{err}
+	}
+
+	if !bytes.Equal(untrusted.LastBlockID.Hash, trusted.Hash()) {
+		return ErrInvalidHeader{fmt.Errorf("last block hash %X doesn't match the trusted header hash %X",
+			untrusted.LastBlockID.Hash, trusted.Hash())}
+	}
+	if !bytes.Equal(untrusted.ValidatorsHash, trusted.NextValidatorsHash) {
+		return ErrInvalidHeader{fmt.Errorf("validators hash %X doesn't match the next validators hash %X of the trusted header",
+			untrusted.ValidatorsHash, trusted.NextValidatorsHash)}
+	}
+
+	if err := untrustedVals.VerifyCommit(chainID, untrusted.Commit.BlockID, untrusted.Height, untrusted.Commit); err != nil {
+		return ErrInvalidHeader{err}
+	}
+	return nil
+}
+
+// VerifyNonAdjacent verifies a header further away than the next height of
+// a trusted header: more than the trust level of the voting power of the
+// trusted validators, and more than 2/3 of the voting power of its own
+// validators must have signed it.
+//
+// ErrNewValSetCantBeTrusted is returned if not enough of the trusted
+// validators signed the header, in which case a header in between must be
+// verified first.
+func VerifyNonAdjacent(
+	chainID string,
+	trusted *types.SignedHeader,
+	trustedVals *types.ValidatorSet,
+	untrusted *types.SignedHeader,
+	untrustedVals *types.ValidatorSet,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+	trustLevel TrustLevel,
+) error {
+	if untrusted.Height == trusted.Height+1 {
+		return errors.New("headers must be non adjacent in height")
+	}
+	if HeaderExpired(trusted, trustingPeriod, now) {
+		return ErrOldHeaderExpired{trusted.Time.Add(trustingPeriod), now}
+	}
+	if err := verifyNewHeaderAndVals(chainID, trusted, untrusted, untrustedVals, now, maxClockDrift); err != nil {
+		return ErrInvalidHeader{err}
+	}
+
+	err := trustedVals.VerifyCommitTrusting(chainID, untrusted.Commit.BlockID, untrusted.Height,
+		untrusted.Commit, trustLevel.Numerator, trustLevel.Denominator)
+	if err != nil {
+		if types.IsErrTooMuchChange(err) {
+			return ErrNewValSetCantBeTrusted{err}
+		}
+		return ErrInvalidHeader{err}
+	}
+
+	if err := untrustedVals.VerifyCommit(chainID, untrusted.Commit.BlockID, untrusted.Height, untrusted.Commit); err != nil {
+		return ErrInvalidHeader{err}
+	}
+	return nil
+}
+
+// Verify verifies a header following a trusted header, with VerifyAdjacent
+// or VerifyNonAdjacent.
+func Verify(
+	chainID string,
+	trusted *types.SignedHeader,
+	trustedVals *types.ValidatorSet,
+	untrusted *types.SignedHeader,
+	untrustedVals *types.ValidatorSet,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+	trustLevel TrustLevel,
+) error {
+	if untrusted.Height == trusted.Height+1 {
+		return VerifyAdjacent(chainID, trusted, untrusted, untrustedVals, trustingPeriod, now, maxClockDrift)
+	}
+	return VerifyNonAdjacent(chainID, trusted, trustedVals, untrusted, untrustedVals,
+		trustingPeriod, now, maxClockDrift, trustLevel)
+}
+
+// VerifyBackwards verifies a header directly preceding a trusted header,
+// through the last block hash of the trusted header.
+func VerifyBackwards(chainID string, untrusted, trusted *types.SignedHeader) error {
+	if err := untrusted.ValidateBasic(chainID); err != nil {
+		return ErrInvalidHeader{err}
+	}
+	if untrusted.Height != trusted.Height-1 {
+		return errors.New("headers must be adjacent in height")
+	}
+	if !untrusted.Time.Before(trusted.Time) {
+		return ErrInvalidHeader{fmt.Errorf("header time %v must be before the trusted header time %v",
+			untrusted.Time, trusted.Time)}
+	}
+	if !bytes.Equal(untrusted.Hash(), trusted.LastBlockID.Hash) {
+		return ErrInvalidHeader{fmt.Errorf("header hash %X doesn't match the last block hash %X of the trusted header",
+			untrusted.Hash(), trusted.LastBlockID.Hash)}
+	}
+	return nil
+}
+
+// HeaderExpired returns whether the header is beyond the trusting period.
+func HeaderExpired(h *types.SignedHeader, trustingPeriod time.Duration, now time.Time) bool {
+	expirationTime := h.Time.Add(trustingPeriod)
+	return !expirationTime.After(now)
+}
+
+func verifyNewHeaderAndVals(
+	chainID string,
+	trusted *types.SignedHeader,
+	untrusted *types.SignedHeader,
+	untrustedVals *types.ValidatorSet,
+	now time.Time,
+	maxClockDrift time.Duration,
+) error {
+	if err := untrusted.ValidateBasic(chainID); err != nil {
+		return err
+	}
+	if untrusted.Height <= trusted.Height {
+		return fmt.Errorf("expected new header height %d to be greater than the trusted header height %d",
+			untrusted.Height, trusted.Height)
+	}
+	if !untrusted.Time.After(trusted.Time) {
+		return fmt.Errorf("expected new header time %v to be after the trusted header time %v",
+			untrusted.Time, trusted.Time)
+	}
+	if !untrusted.Time.Before(now.Add(maxClockDrift)) {
+		return fmt.Errorf("new header has a time from the future %v (now: %v, max clock drift: %v)",
+			untrusted.Time, now, maxClockDrift)
+	}
+	if !bytes.Equal(untrusted.ValidatorsHash, untrustedVals.Hash()) {
+		return fmt.Errorf("validators hash %X of the new header doesn't match the validator set %X",
+			untrusted.ValidatorsHash, untrustedVals.Hash())
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package light
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+const (
+	trustingPeriod = time.Hour
+	maxClockDrift  = 10 * time.Second
+)
+
+func TestTrustLevel_ValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		lvl   TrustLevel
+		valid bool
+	}{
+		{TrustLevel{1, 3}, true},
+		{TrustLevel{2, 3}, true},
+		{TrustLevel{1, 1}, true},
+		{TrustLevel{1, 4}, false},
+		{TrustLevel{4, 3}, false},
+		{TrustLevel{1, 0}, false},
+		{TrustLevel{0, 0}, false},
+	} {
+		err := tc.lvl.ValidateBasic()
+		if tc.valid {
+			assert.NoError(t, err, tc.lvl)
+		} else {
+			assert.Error(t, err, tc.lvl)
+		}
+	}
+}
+
+func TestVerifyAdjacent(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 3, 100, nil)
+	trusted, next := chain.headers[1], chain.headers[2]
+	now := next.Time.Add(time.Minute)
+
+	err := VerifyAdjacent(testChainID, trusted, next, chain.vals[2], trustingPeriod, now, maxClockDrift)
+	require.NoError(t, err)
+
+	// Not adjacent.
+	err = VerifyAdjacent(testChainID, trusted, chain.headers[3], chain.vals[3], trustingPeriod, now, maxClockDrift)
+	assert.Error(t, err)
+
+	// Expired trusted header.
+	err = VerifyAdjacent(testChainID, trusted, next, chain.vals[2], trustingPeriod, now.Add(trustingPeriod), maxClockDrift)
+	assert.ErrorAs(t, err, &ErrOldHeaderExpired{})
+
+	// Header from the future.
+	err = VerifyAdjacent(testChainID, trusted, next, chain.vals[2], trustingPeriod, next.Time.Add(-time.Minute), maxClockDrift)
+	assert.ErrorAs(t, err, &ErrInvalidHeader{})
+
+	// Wrong chain.
+	err = VerifyAdjacent("other-chain", trusted, next, chain.vals[2], trustingPeriod, now, maxClockDrift)
+	assert.ErrorAs(t, err, &ErrInvalidHeader{})
+
+	// Validator set not matching the header.
+	otherVals, _ := types.RandValidatorSet(4, 10)
+	err = VerifyAdjacent(testChainID, trusted, next, otherVals, trustingPeriod, now, maxClockDrift)
+	assert.ErrorAs(t, err, &ErrInvalidHeader{})
+
+	// Header from another chain history.
+	otherChain := newTestChain(t, 2, 100, nil)
+	err = VerifyAdjacent(testChainID, trusted, otherChain.headers[2], otherChain.vals[2], trustingPeriod, now, maxClockDrift)
+	assert.ErrorAs(t, err, &ErrInvalidHeader{})
+}
+
+func TestVerifyNonAdjacent(t *testing.T) {
+	t.Parallel()
+
+	// The validator set changes at height 6.
+	chain := newTestChain(t, 10, 5, nil)
+	now := chain.headers[10].Time.Add(time.Minute)
+
+	// Same validators.
+	err := VerifyNonAdjacent(testChainID, chain.headers[1], chain.vals[1], chain.headers[5], chain.vals[5],
+		trustingPeriod, now, maxClockDrift, DefaultTrustLevel)
+	require.NoError(t, err)
+
+	// New validators, none of them trusted.
+	err = VerifyNonAdjacent(testChainID, chain.headers[1], chain.vals[1], chain.headers[7], chain.vals[7],
+		trustingPeriod, now, maxClockDrift, DefaultTrustLevel)
+	var cantTrust ErrNewValSetCantBeTrusted
+	assert.ErrorAs(t, err, &cantTrust)
+
+	// Adjacent.
+	err = VerifyNonAdjacent(testChainID, chain.headers[1], chain.vals[1], chain.headers[2], chain.vals[2],
+		trustingPeriod, now, maxClockDrift, DefaultTrustLevel)
+	assert.Error(t, err)
+
+	// Expired trusted header.
+	err = VerifyNonAdjacent(testChainID, chain.headers[1], chain.vals[1], chain.headers[5], chain.vals[5],
+		trustingPeriod, now.Add(trustingPeriod), maxClockDrift, DefaultTrustLevel)
+	assert.ErrorAs(t, err, &ErrOldHeaderExpired{})
+}
+
+func TestVerifyBackwards(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 3, 100, nil)
+	require.NoError(t, VerifyBackwards(testChainID, chain.headers[2], chain.headers[3]))
+
+	err := VerifyBackwards(testChainID, chain.headers[1], chain.headers[3])
+	assert.Error(t, err)
+
+	otherChain := newTestChain(t, 2, 100, nil)
+	err = VerifyBackwards(testChainID, otherChain.headers[2], chain.headers[3])
+	var invalid ErrInvalidHeader
+	assert.True(t, errors.As(err, &invalid))
+}
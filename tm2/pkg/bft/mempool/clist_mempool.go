@@ -17,6 +17,7 @@ import (
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/clist"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/log"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
@@ -69,7 +70,7 @@ type CListMempool struct {
 	logger *slog.Logger
 }
 
-var _ Mempool = &CListMempool{}
+var _ ReactorMempool = &CListMempool{}
 
 // CListMempoolOption sets an optional parameter on the mempool.
 type CListMempoolOption func(*CListMempool)
@@ -628,6 +629,13 @@ type mempoolTx struct {
 	gasWanted int64    // amount of gas this tx states it will require
 	tx        types.Tx //
 
+	// ordering of the tx in the priority mempool, as returned on CheckTx
+	priority  int64
+	sender    crypto.Address
+	sequence  uint64
+	timestamp time.Time // when the tx was added
+	arrival   uint64    // order in which the tx was added
+
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
 	senders sync.Map
@@ -1,12 +1,26 @@
 package config
 
-import "github.com/gnolang/gno/tm2/pkg/errors"
+import (
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+const (
+	// TypeCList is the FIFO mempool, rejecting new txs when full
+	TypeCList = "clist"
+
+	// TypePriority is the mempool ordered by gas price, evicting the
+	// lowest-priority txs when full
+	TypePriority = "priority"
+)
 
 // -----------------------------------------------------------------------------
 // MempoolConfig
 
 // MempoolConfig defines the configuration options for the Tendermint mempool
 type MempoolConfig struct {
+	Type               string `json:"type" toml:"type" comment:"Mempool implementation, one of:\n - \"clist\": txs are reaped in arrival order, and rejected when the mempool is full\n - \"priority\": txs are reaped by gas price, and the lowest-priority txs are evicted when the mempool is full"`
 	RootDir            string `json:"home" toml:"home"`
 	Recheck            bool   `json:"recheck" toml:"recheck"`
 	Broadcast          bool   `json:"broadcast" toml:"broadcast"`
@@ -14,11 +28,16 @@ type MempoolConfig struct {
 	Size               int    `json:"size" toml:"size" comment:"Maximum number of transactions in the mempool"`
 	MaxPendingTxsBytes int64  `json:"max_pending_txs_bytes" toml:"max_pending_txs_bytes" comment:"Limit the total size of all txs in the mempool.\n This only accounts for raw transactions (e.g. given 1MB transactions and\n max_txs_bytes=5MB, mempool will only accept 5 transactions)."`
 	CacheSize          int    `json:"cache_size" toml:"cache_size" comment:"Size of the cache (used to filter transactions we saw earlier) in transactions"`
+
+	// Priority mempool expiry
+	TTLNumBlocks int64         `json:"ttl_num_blocks" toml:"ttl_num_blocks" comment:"Maximum number of blocks a tx can stay in the priority mempool (0 for no limit)"`
+	TTLDuration  time.Duration `json:"ttl_duration" toml:"ttl_duration" comment:"Maximum time a tx can stay in the priority mempool (0 for no limit)"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
 func DefaultMempoolConfig() *MempoolConfig {
 	return &MempoolConfig{
+		Type:      TypeCList,
 		Recheck:   true,
 		Broadcast: true,
 		WalPath:   "",
@@ -50,6 +69,11 @@ func (cfg *MempoolConfig) WalEnabled() bool {
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *MempoolConfig) ValidateBasic() error {
+	switch cfg.Type {
+	case "", TypeCList, TypePriority: // unset in older configs, FIFO
+	default:
+		return errors.New("type must be %q or %q", TypeCList, TypePriority)
+	}
 	if cfg.Size < 0 {
 		return errors.New("size can't be negative")
 	}
@@ -59,5 +83,11 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.CacheSize < 0 {
 		return errors.New("cache_size can't be negative")
 	}
+	if cfg.TTLNumBlocks < 0 {
+		return errors.New("ttl_num_blocks can't be negative")
+	}
+	if cfg.TTLDuration < 0 {
+		return errors.New("ttl_duration can't be negative")
+	}
 	return nil
 }
@@ -0,0 +1,861 @@
+package mempool
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	auto "github.com/gnolang/gno/tm2/pkg/autofile"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/clist"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+)
+
+// --------------------------------------------------------------------------------
+
+// PriorityMempool is an in-memory pool for transactions before they are
+// proposed in a consensus round, which reaps them by priority. The priority
+// of a transaction, along with its sender and sequence, is returned by the
+// application on CheckTx (e.g. the gas price of the transaction). The
+// transactions of a sender are always reaped in sequence order.
+//
+// When the mempool is full, the lowest-priority transactions are evicted to
+// make room for higher-priority ones. Transactions also expire after the
+// configured number of blocks or duration.
+//
+// Transactions are gossiped to peers in the order they were added.
+type PriorityMempool struct {
+	config *cfg.MempoolConfig
+
+	mtx          sync.Mutex
+	proxyAppConn appconn.Mempool
+	preCheck     PreCheckFunc
+	height       int64 // the last block Update()'d to
+	maxTxBytes   int64
+
+	// The txs are indexed by arrival (for gossip), hash and sender.
+	// They are protected by their own mutex, as the app responses
+	// are not handled while holding mtx.
+	txsMtx   sync.RWMutex
+	txs      *clist.CList                          // concurrent linked-list of good txs, in arrival order
+	txsMap   map[[sha256.Size]byte]*clist.CElement // txKey -> CElement
+	senders  map[crypto.Address]txQueue            // sender -> txs in sequence order
+	txsBytes int64                                 // total size of mempool, in bytes
+	arrivals uint64                                // number of txs added so far
+
+	// Track whether we're rechecking txs.
+	// These are not protected by a mutex and are expected to be mutated
+	// in serial (ie. by abci responses which are called in serial).
+	recheckTxs    []*mempoolTx // txs being rechecked, in request order
+	recheckCursor int          // next expected response
+	rechecking    int32        // for re-checking filtered txs on Update()
+
+	// notify listeners (ie. consensus) when txs are available
+	notifiedTxsAvailable bool
+	txsAvailable         chan struct{} // fires once for each height, when the mempool is not empty
+
+	// Keep a cache of already-seen txs.
+	// This reduces the pressure on the proxyApp.
+	cache txCache
+
+	// A log of mempool txs
+	wal *auto.AutoFile
+
+	logger *slog.Logger
+}
+
+var _ ReactorMempool = &PriorityMempool{}
+
+// PriorityMempoolOption sets an optional parameter on the mempool.
+type PriorityMempoolOption func(*PriorityMempool)
+
+// NewPriorityMempool returns a new priority mempool with the given
+// configuration and connection to an application.
+func NewPriorityMempool(
+	config *cfg.MempoolConfig,
+	proxyAppConn appconn.Mempool,
+	height int64,
+	maxTxBytes int64,
+	options ...PriorityMempoolOption,
+) *PriorityMempool {
+	if maxTxBytes <= 0 {
+		panic("maxTxBytes must be positive")
+	}
+	mempool := &PriorityMempool{
+		config:       config,
+		proxyAppConn: proxyAppConn,
+		txs:          clist.New(),
+		txsMap:       make(map[[sha256.Size]byte]*clist.CElement),
+		senders:      make(map[crypto.Address]txQueue),
+		height:       height,
+		maxTxBytes:   maxTxBytes,
+		logger:       log.NewNoopLogger(),
+	}
+	if config.CacheSize > 0 {
+		mempool.cache = newMapTxCache(config.CacheSize)
+	} else {
+		mempool.cache = nopTxCache{}
+	}
+	proxyAppConn.SetResponseCallback(mempool.globalCb)
+	for _, option := range options {
+		option(mempool)
+	}
+	return mempool
+}
+
+// NOTE: not thread safe - should only be called once, on startup
+func (mem *PriorityMempool) EnableTxsAvailable() {
+	mem.txsAvailable = make(chan struct{}, 1)
+}
+
+// SetLogger sets the Logger.
+func (mem *PriorityMempool) SetLogger(l *slog.Logger) {
+	mem.logger = l
+}
+
+// WithPriorityPreCheck sets a filter for the mempool to reject a tx if f(tx)
+// returns false. This is ran before CheckTx.
+func WithPriorityPreCheck(f PreCheckFunc) PriorityMempoolOption {
+	return func(mem *PriorityMempool) { mem.preCheck = f }
+}
+
+// *panics* if can't create directory or open file.
+// *not thread safe*
+func (mem *PriorityMempool) InitWAL() {
+	walDir := mem.config.WalDir()
+	err := osm.EnsureDir(walDir, 0o700)
+	if err != nil {
+		panic(errors.Wrap(err, "Error ensuring WAL dir"))
+	}
+	af, err := auto.OpenAutoFile(walDir + "/wal")
+	if err != nil {
+		panic(errors.Wrap(err, "Error opening WAL file"))
+	}
+	mem.wal = af
+}
+
+func (mem *PriorityMempool) CloseWAL() {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	if err := mem.wal.Close(); err != nil {
+		mem.logger.Error("Error closing WAL", "err", err)
+	}
+	mem.wal = nil
+}
+
+func (mem *PriorityMempool) Lock() {
+	mem.mtx.Lock()
+}
+
+func (mem *PriorityMempool) Unlock() {
+	mem.mtx.Unlock()
+}
+
+func (mem *PriorityMempool) Size() int {
+	return mem.txs.Len()
+}
+
+func (mem *PriorityMempool) MaxTxBytes() int64 {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+	return mem.maxTxBytes
+}
+
+func (mem *PriorityMempool) TxsBytes() int64 {
+	mem.txsMtx.RLock()
+	defer mem.txsMtx.RUnlock()
+	return mem.txsBytes
+}
+
+func (mem *PriorityMempool) FlushAppConn() error {
+	return mem.proxyAppConn.FlushSync()
+}
+
+func (mem *PriorityMempool) Flush() {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	mem.cache.Reset()
+
+	mem.txsMtx.Lock()
+	defer mem.txsMtx.Unlock()
+
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		mem.txs.Remove(e)
+		e.DetachPrev()
+	}
+
+	mem.txsMap = make(map[[sha256.Size]byte]*clist.CElement)
+	mem.senders = make(map[crypto.Address]txQueue)
+	mem.txsBytes = 0
+}
+
+// TxsFront returns the first transaction in arrival order for peer
+// goroutines to call .NextWait() on.
+func (mem *PriorityMempool) TxsFront() *clist.CElement {
+	return mem.txs.Front()
+}
+
+// TxsWaitChan returns a channel to wait on transactions. It will be closed
+// once the mempool is not empty (ie. the internal `mem.txs` has at least one
+// element)
+func (mem *PriorityMempool) TxsWaitChan() <-chan struct{} {
+	return mem.txs.WaitChan()
+}
+
+// It blocks if we're waiting on Update() or Reap().
+// cb: A callback from the CheckTx command.
+//
+//	It gets called from another goroutine.
+//
+// CONTRACT: Either cb will get called, or err returned.
+func (mem *PriorityMempool) CheckTx(tx types.Tx, cb func(abci.Response)) (err error) {
+	return mem.CheckTxWithInfo(tx, cb, TxInfo{SenderID: UnknownPeerID})
+}
+
+func (mem *PriorityMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), txInfo TxInfo) (err error) {
+	mem.mtx.Lock()
+	// use defer to unlock mutex because application (*local client*) might panic
+	defer mem.mtx.Unlock()
+
+	txSize := len(tx)
+
+	// Check the tx could fit in the mempool, if other txs were evicted.
+	// Whether it will is only known once the app returns its priority
+	if mem.config.Size == 0 || int64(txSize) > mem.config.MaxPendingTxsBytes {
+		return MempoolIsFullError{
+			mem.Size(), mem.config.Size,
+			mem.TxsBytes(), mem.config.MaxPendingTxsBytes,
+		}
+	}
+
+	// Check max tx bytes
+	if int64(txSize) > mem.maxTxBytes {
+		return TxTooLargeError{mem.maxTxBytes, int64(txSize)}
+	}
+
+	// Check custom preCheck function
+	if mem.preCheck != nil {
+		if err := mem.preCheck(tx); err != nil {
+			return err
+		}
+	}
+
+	// CACHE
+	if !mem.cache.Push(tx) {
+		// Record a new sender for a tx we've already seen,
+		// if it's still in the mempool
+		mem.txsMtx.RLock()
+		if e, ok := mem.txsMap[txKey(tx)]; ok {
+			memTx := e.Value.(*mempoolTx)
+			memTx.senders.LoadOrStore(txInfo.SenderID, true)
+		}
+		mem.txsMtx.RUnlock()
+
+		return ErrTxInCache
+	}
+	// END CACHE
+
+	// WAL
+	if mem.wal != nil {
+		// TODO: Notify administrators when WAL fails
+		_, err := mem.wal.Write([]byte(tx))
+		if err != nil {
+			mem.logger.Error("Error writing to WAL", "err", err)
+		}
+		_, err = mem.wal.Write([]byte("\n"))
+		if err != nil {
+			mem.logger.Error("Error writing to WAL", "err", err)
+		}
+	}
+	// END WAL
+
+	// NOTE: proxyAppConn may error if tx buffer is full
+	if err = mem.proxyAppConn.Error(); err != nil {
+		return err
+	}
+
+	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
+	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, cb))
+
+	return nil
+}
+
+// Global callback that will be called after every ABCI response.
+// When rechecking, we don't need the peerID, so the recheck callback happens
+// here. Otherwise, the request specific callback does the work.
+func (mem *PriorityMempool) globalCb(req abci.Request, res abci.Response) {
+	if mem.recheckTxs == nil {
+		return
+	} else {
+		mem.resCbRecheck(req, res)
+	}
+}
+
+// Request specific callback that should be set on individual reqRes objects
+// to incorporate local information when processing the response.
+// This allows us to track the peer that sent us this tx, so we can avoid
+// sending it back to them.
+//
+// External callers of CheckTx, like the RPC, can also pass an externalCb
+// through here that is called when all other response processing is complete.
+// If the tx was valid but couldn't make it into the full mempool, the
+// response they get holds the mempool error.
+func (mem *PriorityMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci.Response)) func(res abci.Response) {
+	return func(res abci.Response) {
+		if mem.recheckTxs != nil {
+			// this should never happen
+			panic("recheck txs are not nil in reqResCb")
+		}
+
+		res = mem.resCbFirstTime(tx, peerID, res)
+
+		// Passed in by the caller of CheckTx, eg. the RPC.
+		if externalCb != nil {
+			externalCb(res)
+		}
+	}
+}
+
+// callback, which is called after the app checked the tx for the first time.
+// It returns the response to pass on to the caller.
+//
+// The case where the app checks the tx for the second and subsequent times is
+// handled by the resCbRecheck callback.
+func (mem *PriorityMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) abci.Response {
+	switch r := res.(type) {
+	case abci.ResponseCheckTx:
+		if r.Error != nil {
+			// ignore bad transaction
+			mem.logger.Info("Rejected bad transaction", "tx", txID(tx), "res", r, "err", r.Error)
+			// remove from cache (it might be good later)
+			mem.cache.Remove(tx)
+
+			return res
+		}
+
+		memTx := &mempoolTx{
+			height:    mem.height,
+			gasWanted: r.GasWanted,
+			tx:        tx,
+			priority:  r.Priority,
+			sender:    r.Sender,
+			sequence:  r.Sequence,
+			timestamp: time.Now(),
+		}
+		memTx.senders.Store(peerID, true)
+
+		if err := mem.addTx(memTx); err != nil {
+			// there is no room for the tx, even by evicting others
+			mem.logger.Info("Rejected good transaction", "tx", txID(tx), "priority", r.Priority, "err", err)
+			// remove from cache (it might make it later)
+			mem.cache.Remove(tx)
+
+			r.Error = abci.StringError(err.Error())
+			return r
+		}
+
+		mem.logger.Info("Added good transaction",
+			"tx", txID(tx),
+			"res", r,
+			"height", memTx.height,
+			"total", mem.Size(),
+		)
+		mem.notifyTxsAvailable()
+	default:
+		// ignore other messages
+	}
+
+	return res
+}
+
+// callback, which is called after the app rechecked the tx.
+//
+// The case where the app checks the tx for the first time is handled by the
+// resCbFirstTime callback.
+func (mem *PriorityMempool) resCbRecheck(req abci.Request, res abci.Response) {
+	switch res := res.(type) {
+	case abci.ResponseCheckTx:
+		tx := req.(abci.RequestCheckTx).Tx
+		memTx := mem.recheckTxs[mem.recheckCursor]
+		if !bytes.Equal(tx, memTx.tx) {
+			panic(fmt.Sprintf(
+				"Unexpected tx response from proxy during recheck\nExpected %X, got %X",
+				memTx.tx,
+				tx))
+		}
+		if res.Error == nil {
+			// Good, nothing to do.
+		} else {
+			// Tx became invalidated due to newly committed block.
+			mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", res, "err", res.Error)
+			// NOTE: we remove tx from the cache because it might be good later
+			mem.txsMtx.Lock()
+			mem.removeTx(tx, true)
+			mem.txsMtx.Unlock()
+		}
+		mem.recheckCursor++
+		if mem.recheckCursor == len(mem.recheckTxs) {
+			// Done!
+			mem.recheckTxs = nil
+			atomic.StoreInt32(&mem.rechecking, 0)
+			mem.logger.Info("Done rechecking txs")
+
+			// incase the recheck removed all txs
+			if mem.Size() > 0 {
+				mem.notifyTxsAvailable()
+			}
+		}
+	default:
+		// ignore other messages
+	}
+}
+
+// addTx adds the tx to the mempool, first evicting lower-priority txs if
+// the mempool is full. It errors if there is no room for the tx.
+func (mem *PriorityMempool) addTx(memTx *mempoolTx) error {
+	mem.txsMtx.Lock()
+	defer mem.txsMtx.Unlock()
+
+	evicted, err := mem.evictionsFor(memTx)
+	if err != nil {
+		return err
+	}
+
+	for _, evictedTx := range evicted {
+		mem.logger.Info("Evicted transaction",
+			"tx", txID(evictedTx.tx),
+			"priority", evictedTx.priority,
+			"by", txID(memTx.tx),
+		)
+		// NOTE: we remove tx from the cache so it can be resubmitted
+		mem.removeTx(evictedTx.tx, true)
+	}
+
+	memTx.arrival = mem.arrivals
+	mem.arrivals++
+
+	e := mem.txs.PushBack(memTx)
+	mem.txsMap[txKey(memTx.tx)] = e
+	if !memTx.sender.IsZero() {
+		mem.senders[memTx.sender] = mem.senders[memTx.sender].insert(memTx)
+	}
+	mem.txsBytes += int64(len(memTx.tx))
+
+	// Update the telemetry
+	mem.logTelemetry()
+
+	return nil
+}
+
+// evictionsFor returns the txs to evict from the mempool to make room for the
+// given tx, which must all have a lower priority. Only the last tx of a sender
+// can be evicted, so its other txs remain valid.
+// CONTRACT: txsMtx is held
+func (mem *PriorityMempool) evictionsFor(memTx *mempoolTx) ([]*mempoolTx, error) {
+	var (
+		numTxs   = mem.txs.Len()
+		txsBytes = mem.txsBytes
+		txSize   = int64(len(memTx.tx))
+
+		queues  = mem.txQueues()
+		evicted []*mempoolTx
+	)
+
+	for numTxs >= mem.config.Size || txsBytes+txSize > mem.config.MaxPendingTxsBytes {
+		// Find the lowest-priority tx that can be evicted.
+		// The txs of the same sender can't be, as the tx would come after them
+		lowest := -1
+		for i, q := range queues {
+			if len(q) == 0 || (!memTx.sender.IsZero() && q[0].sender == memTx.sender) {
+				continue
+			}
+
+			if lowest == -1 || lowerPriority(q.last(), queues[lowest].last()) {
+				lowest = i
+			}
+		}
+
+		if lowest == -1 || queues[lowest].last().priority >= memTx.priority {
+			return nil, MempoolIsFullError{
+				mem.txs.Len(), mem.config.Size,
+				mem.txsBytes, mem.config.MaxPendingTxsBytes,
+			}
+		}
+
+		tail := queues[lowest].last()
+		queues[lowest] = queues[lowest][:len(queues[lowest])-1]
+
+		evicted = append(evicted, tail)
+		numTxs--
+		txsBytes -= int64(len(tail.tx))
+	}
+
+	return evicted, nil
+}
+
+// logTelemetry logs the mempool telemetry
+func (mem *PriorityMempool) logTelemetry() {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	// Log the total number of mempool transactions
+	metrics.NumMempoolTxs.Record(context.Background(), int64(mem.txs.Len()))
+
+	// Log the total number of the mempool cache transactions
+	metrics.NumCachedTxs.Record(context.Background(), int64(mem.cache.Len()))
+}
+
+// removeTx removes the tx from the mempool, if it's still there.
+// CONTRACT: txsMtx is held
+func (mem *PriorityMempool) removeTx(tx types.Tx, removeFromCache bool) {
+	key := txKey(tx)
+
+	e, ok := mem.txsMap[key]
+	if !ok {
+		return
+	}
+
+	memTx := e.Value.(*mempoolTx)
+
+	mem.txs.Remove(e)
+	e.DetachPrev()
+	delete(mem.txsMap, key)
+	if !memTx.sender.IsZero() {
+		if q := mem.senders[memTx.sender].remove(memTx); len(q) > 0 {
+			mem.senders[memTx.sender] = q
+		} else {
+			delete(mem.senders, memTx.sender)
+		}
+	}
+	mem.txsBytes -= int64(len(tx))
+
+	if removeFromCache {
+		mem.cache.Remove(tx)
+	}
+
+	// Update the telemetry
+	mem.logTelemetry()
+}
+
+// txQueues returns the mempool txs grouped by sender, in sequence order.
+// The txs without a sender are each in their own group.
+// CONTRACT: txsMtx is held
+func (mem *PriorityMempool) txQueues() []txQueue {
+	queues := make([]txQueue, 0, len(mem.senders))
+	for _, q := range mem.senders {
+		queues = append(queues, q)
+	}
+
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		if memTx := e.Value.(*mempoolTx); memTx.sender.IsZero() {
+			queues = append(queues, txQueue{memTx})
+		}
+	}
+
+	return queues
+}
+
+// orderedTxs returns the mempool txs in reap order: by decreasing priority,
+// the txs of a sender staying in sequence order.
+// CONTRACT: txsMtx is held
+func (mem *PriorityMempool) orderedTxs() []*mempoolTx {
+	h := txQueueHeap(mem.txQueues())
+	heap.Init(&h)
+
+	txs := make([]*mempoolTx, 0, mem.txs.Len())
+	for h.Len() > 0 {
+		q := h[0]
+		txs = append(txs, q[0])
+
+		// Move on to the next tx of the sender
+		if len(q) > 1 {
+			h[0] = q[1:]
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+
+	return txs
+}
+
+func (mem *PriorityMempool) TxsAvailable() <-chan struct{} {
+	return mem.txsAvailable
+}
+
+func (mem *PriorityMempool) notifyTxsAvailable() {
+	if mem.Size() == 0 {
+		panic("notified txs available but mempool is empty!")
+	}
+	if mem.txsAvailable != nil && !mem.notifiedTxsAvailable {
+		// channel cap is 1, so this will send once
+		mem.notifiedTxsAvailable = true
+		select {
+		case mem.txsAvailable <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (mem *PriorityMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Txs {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	if maxDataBytes == 0 {
+		panic("ReapMaxBytesMaxGas requires maxDataBytes > 0")
+	}
+
+	for atomic.LoadInt32(&mem.rechecking) > 0 {
+		// TODO: Something better?
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	mem.txsMtx.RLock()
+	defer mem.txsMtx.RUnlock()
+
+	var totalBytes int64
+	var totalGas int64
+
+	ordered := mem.orderedTxs()
+	txs := make([]types.Tx, 0, len(ordered))
+	for _, memTx := range ordered {
+		// Check total size requirement
+		if maxDataBytes > -1 && totalBytes+int64(len(memTx.tx)) > maxDataBytes {
+			return txs
+		}
+		totalBytes += int64(len(memTx.tx))
+		// Check total gas requirement.
+		// If maxGas is negative, skip this check.
+		newTotalGas := totalGas + memTx.gasWanted
+		if maxGas > -1 && newTotalGas > maxGas {
+			return txs
+		}
+		totalGas = newTotalGas
+		txs = append(txs, memTx.tx)
+	}
+	return txs
+}
+
+func (mem *PriorityMempool) ReapMaxTxs(max int) types.Txs {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	for atomic.LoadInt32(&mem.rechecking) > 0 {
+		// TODO: Something better?
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	mem.txsMtx.RLock()
+	defer mem.txsMtx.RUnlock()
+
+	ordered := mem.orderedTxs()
+	if max < 0 || max > len(ordered) {
+		max = len(ordered)
+	}
+
+	txs := make([]types.Tx, 0, max)
+	for _, memTx := range ordered[:max] {
+		txs = append(txs, memTx.tx)
+	}
+	return txs
+}
+
+func (mem *PriorityMempool) Update(
+	height int64,
+	txs types.Txs,
+	deliverTxResponses []abci.ResponseDeliverTx,
+	preCheck PreCheckFunc,
+	maxTxBytes int64,
+) error {
+	// Set height
+	mem.height = height
+	mem.notifiedTxsAvailable = false
+
+	if preCheck != nil {
+		mem.preCheck = preCheck
+	}
+	if maxTxBytes != 0 {
+		mem.maxTxBytes = maxTxBytes
+	}
+
+	mem.txsMtx.Lock()
+	for i, tx := range txs {
+		if deliverTxResponses[i].Error == nil {
+			// Add valid committed tx to the cache (if missing).
+			_ = mem.cache.Push(tx)
+		} else {
+			// Allow invalid transactions to be resubmitted.
+			mem.cache.Remove(tx)
+		}
+
+		// Remove committed tx from the mempool.
+		mem.removeTx(tx, false)
+	}
+
+	mem.expireTxs(height)
+	mem.txsMtx.Unlock()
+
+	// Either recheck non-committed txs to see if they became invalid
+	// or just notify there're some txs left.
+	if mem.Size() > 0 {
+		if mem.config.Recheck {
+			mem.logger.Info("Recheck txs", "numtxs", mem.Size(), "height", height)
+			mem.recheckMempoolTxs()
+			// At this point, mem.txs are being rechecked.
+			// Before mem.Reap(), we should wait for mem.rechecking to be 0.
+		} else {
+			mem.notifyTxsAvailable()
+		}
+	}
+
+	return nil
+}
+
+// expireTxs removes the txs which have been in the mempool for longer than
+// the configured number of blocks or duration, so they can be resubmitted.
+// CONTRACT: txsMtx is held
+func (mem *PriorityMempool) expireTxs(height int64) {
+	if mem.config.TTLNumBlocks == 0 && mem.config.TTLDuration == 0 {
+		return
+	}
+
+	now := time.Now()
+
+	for e := mem.txs.Front(); e != nil; {
+		memTx := e.Value.(*mempoolTx)
+		next := e.Next()
+
+		var (
+			blocksExpired = mem.config.TTLNumBlocks > 0 && height-memTx.Height() > mem.config.TTLNumBlocks
+			timeExpired   = mem.config.TTLDuration > 0 && now.Sub(memTx.timestamp) > mem.config.TTLDuration
+		)
+
+		if blocksExpired || timeExpired {
+			mem.logger.Info("Tx expired", "tx", txID(memTx.tx), "height", memTx.Height())
+			mem.removeTx(memTx.tx, true)
+		}
+
+		e = next
+	}
+}
+
+// recheckMempoolTxs rechecks the mempool txs against the app, in reap order
+// so the txs of a sender are rechecked in sequence order.
+func (mem *PriorityMempool) recheckMempoolTxs() {
+	mem.txsMtx.Lock()
+	recheckTxs := make([]*mempoolTx, 0, mem.txs.Len())
+	for _, memTx := range mem.orderedTxs() {
+		// check tx size
+		if int64(len(memTx.tx)) > mem.maxTxBytes {
+			mem.removeTx(memTx.tx, false)
+			continue
+		}
+		// run precheck
+		if mem.preCheck != nil {
+			if err := mem.preCheck(memTx.tx); err != nil {
+				mem.removeTx(memTx.tx, false)
+				continue
+			}
+		}
+		recheckTxs = append(recheckTxs, memTx)
+	}
+	mem.txsMtx.Unlock()
+
+	if len(recheckTxs) == 0 {
+		return
+	}
+
+	atomic.StoreInt32(&mem.rechecking, 1)
+	mem.recheckTxs = recheckTxs
+	mem.recheckCursor = 0
+
+	// Push txs to proxyAppConn
+	// NOTE: globalCb may be called concurrently.
+	for _, memTx := range recheckTxs {
+		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
+			Tx:   memTx.tx,
+			Type: abci.CheckTxTypeRecheck,
+		})
+	}
+
+	mem.proxyAppConn.FlushAsync()
+}
+
+// --------------------------------------------------------------------------------
+
+// lowerPriority returns true if a is reaped after b:
+// it has a lower priority, or the same one and arrived later
+func lowerPriority(a, b *mempoolTx) bool {
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+
+	return a.arrival > b.arrival
+}
+
+// txQueue is the txs of a sender, in sequence order
+type txQueue []*mempoolTx
+
+// insert adds the tx to the queue, after the txs with a lower or equal sequence
+func (q txQueue) insert(memTx *mempoolTx) txQueue {
+	i := sort.Search(len(q), func(i int) bool {
+		return q[i].sequence > memTx.sequence
+	})
+
+	return slices.Insert(q, i, memTx)
+}
+
+// remove removes the tx from the queue, if present
+func (q txQueue) remove(memTx *mempoolTx) txQueue {
+	i := slices.Index(q, memTx)
+	if i == -1 {
+		return q
+	}
+
+	return slices.Delete(q, i, i+1)
+}
+
+// last returns the last tx of the queue, in sequence order
+func (q txQueue) last() *mempoolTx {
+	return q[len(q)-1]
+}
+
+// txQueueHeap is a max-heap of sender queues,
+// by the priority of their first tx
+type txQueueHeap []txQueue
+
+func (h txQueueHeap) Len() int           { return len(h) }
+func (h txQueueHeap) Less(i, j int) bool { return lowerPriority(h[j][0], h[i][0]) }
+func (h txQueueHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *txQueueHeap) Push(x any) {
+	*h = append(*h, x.(txQueue))
+}
+
+func (h *txQueueHeap) Pop() any {
+	old := *h
+	n := len(old)
+	q := old[n-1]
+	*h = old[:n-1]
+
+	return q
+}
@@ -0,0 +1,258 @@
+package mempool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+// priorityApplication is an app whose txs are "sender/sequence/priority",
+// the sender being optional
+type priorityApplication struct {
+	abci.BaseApplication
+
+	invalid map[string]bool // txs rejected on recheck
+}
+
+func newPriorityApplication() *priorityApplication {
+	return &priorityApplication{
+		invalid: make(map[string]bool),
+	}
+}
+
+func (app *priorityApplication) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	if req.Type == abci.CheckTxTypeRecheck && app.invalid[string(req.Tx)] {
+		return abci.ResponseCheckTx{
+			ResponseBase: abci.ResponseBase{Error: abci.StringError("invalid tx")},
+		}
+	}
+
+	parts := strings.Split(string(req.Tx), "/")
+	if len(parts) != 3 {
+		return abci.ResponseCheckTx{
+			ResponseBase: abci.ResponseBase{Error: abci.StringError("invalid tx format")},
+		}
+	}
+
+	sequence, _ := strconv.ParseUint(parts[1], 10, 64)
+	priority, _ := strconv.ParseInt(parts[2], 10, 64)
+
+	res := abci.ResponseCheckTx{
+		GasWanted: 1,
+		Priority:  priority,
+		Sequence:  sequence,
+	}
+	if parts[0] != "" {
+		res.Sender = crypto.AddressFromPreimage([]byte(parts[0]))
+	}
+
+	return res
+}
+
+func priorityTx(sender string, sequence uint64, priority int64) types.Tx {
+	return types.Tx(fmt.Sprintf("%s/%d/%d", sender, sequence, priority))
+}
+
+func newPriorityMempoolWithAppAndConfig(
+	t *testing.T,
+	app abci.Application,
+	config *cfg.MempoolConfig,
+) *PriorityMempool {
+	t.Helper()
+
+	appConnMem, _ := proxy.NewLocalClientCreator(app).NewABCIClient()
+	appConnMem.SetLogger(log.NewNoopLogger().With("module", "abci-client", "connection", "mempool"))
+	require.NoError(t, appConnMem.Start())
+
+	mempool := NewPriorityMempool(config, appConnMem, 0, testMaxTxBytes)
+	mempool.SetLogger(log.NewNoopLogger())
+
+	return mempool
+}
+
+// checkPriorityTx adds the tx to the mempool, and returns the app response
+func checkPriorityTx(t *testing.T, mempool *PriorityMempool, tx types.Tx) abci.ResponseCheckTx {
+	t.Helper()
+
+	var res abci.ResponseCheckTx
+	require.NoError(t, mempool.CheckTx(tx, func(r abci.Response) {
+		res = r.(abci.ResponseCheckTx)
+	}))
+
+	return res
+}
+
+func TestPriorityMempoolReap(t *testing.T) {
+	t.Parallel()
+
+	mempool := newPriorityMempoolWithAppAndConfig(t, newPriorityApplication(), cfg.TestMempoolConfig())
+
+	var (
+		alice0 = priorityTx("alice", 0, 1)
+		alice1 = priorityTx("alice", 1, 10)
+		bob0   = priorityTx("bob", 0, 5)
+		bob1   = priorityTx("bob", 1, 4)
+		anon   = priorityTx("", 0, 3)
+	)
+
+	// Add alice's txs out of sequence order
+	for _, tx := range []types.Tx{alice1, bob0, alice0, anon, bob1} {
+		res := checkPriorityTx(t, mempool, tx)
+		require.Nil(t, res.Error)
+	}
+	require.Equal(t, 5, mempool.Size())
+
+	// The txs are reaped by priority, in sequence order for a sender
+	expected := types.Txs{bob0, bob1, anon, alice0, alice1}
+
+	assert.Equal(t, expected, mempool.ReapMaxTxs(-1))
+	assert.Equal(t, expected[:2], mempool.ReapMaxTxs(2))
+	assert.Equal(t, expected[:3], mempool.ReapMaxBytesMaxGas(-1, 3))
+	assert.Equal(t, expected[:2], mempool.ReapMaxBytesMaxGas(int64(len(bob0)+len(bob1)), -1))
+
+	// The txs are still gossiped in arrival order
+	assert.Equal(t, alice1, mempool.TxsFront().Value.(*mempoolTx).tx)
+}
+
+func TestPriorityMempoolEviction(t *testing.T) {
+	t.Parallel()
+
+	config := cfg.TestMempoolConfig()
+	config.Size = 3
+
+	mempool := newPriorityMempoolWithAppAndConfig(t, newPriorityApplication(), config)
+
+	var (
+		alice0 = priorityTx("alice", 0, 1)
+		alice1 = priorityTx("alice", 1, 9)
+		bob0   = priorityTx("bob", 0, 2)
+	)
+
+	for _, tx := range []types.Tx{alice0, alice1, bob0} {
+		res := checkPriorityTx(t, mempool, tx)
+		require.Nil(t, res.Error)
+	}
+
+	t.Run("lower priority tx rejected", func(t *testing.T) {
+		res := checkPriorityTx(t, mempool, priorityTx("carol", 0, 2))
+		require.NotNil(t, res.Error)
+		assert.Contains(t, res.Error.Error(), "mempool is full")
+
+		assert.Equal(t, types.Txs{bob0, alice0, alice1}, mempool.ReapMaxTxs(-1))
+	})
+
+	t.Run("lowest priority tx evicted", func(t *testing.T) {
+		// alice0 has the lowest priority, but evicting it would invalidate
+		// alice1, so bob0 is evicted
+		carol0 := priorityTx("carol", 0, 5)
+
+		res := checkPriorityTx(t, mempool, carol0)
+		require.Nil(t, res.Error)
+
+		assert.Equal(t, types.Txs{carol0, alice0, alice1}, mempool.ReapMaxTxs(-1))
+	})
+
+	t.Run("sender txs not evicted", func(t *testing.T) {
+		// carol0 is evicted, while alice's txs can't make room for its own
+		res := checkPriorityTx(t, mempool, priorityTx("alice", 2, 20))
+		require.Nil(t, res.Error)
+
+		assert.Equal(t, 3, mempool.Size())
+		assert.NotContains(t, mempool.ReapMaxTxs(-1), priorityTx("carol", 0, 5))
+
+		res = checkPriorityTx(t, mempool, priorityTx("alice", 3, 30))
+		require.NotNil(t, res.Error)
+	})
+
+	t.Run("evicted tx not cached", func(t *testing.T) {
+		// bob0 is checked again, but there's still no room for it
+		res := checkPriorityTx(t, mempool, bob0)
+		require.NotNil(t, res.Error)
+		assert.Contains(t, res.Error.Error(), "mempool is full")
+	})
+}
+
+func TestPriorityMempoolUpdate(t *testing.T) {
+	t.Parallel()
+
+	app := newPriorityApplication()
+	mempool := newPriorityMempoolWithAppAndConfig(t, app, cfg.TestMempoolConfig())
+
+	var (
+		alice0 = priorityTx("alice", 0, 1)
+		alice1 = priorityTx("alice", 1, 1)
+		bob0   = priorityTx("bob", 0, 1)
+	)
+
+	for _, tx := range []types.Tx{alice0, alice1, bob0} {
+		res := checkPriorityTx(t, mempool, tx)
+		require.Nil(t, res.Error)
+	}
+
+	// Commit alice0, while bob0 is no longer valid
+	app.invalid[string(bob0)] = true
+
+	mempool.Lock()
+	err := mempool.Update(1, types.Txs{alice0}, abciResponses(1, nil), nil, 0)
+	mempool.Unlock()
+	require.NoError(t, err)
+
+	assert.Equal(t, types.Txs{alice1}, mempool.ReapMaxTxs(-1))
+	assert.Equal(t, int64(len(alice1)), mempool.TxsBytes())
+
+	// The committed tx stays in the cache
+	assert.ErrorIs(t, mempool.CheckTx(alice0, nil), ErrTxInCache)
+}
+
+func TestPriorityMempoolExpiry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expired by height", func(t *testing.T) {
+		t.Parallel()
+
+		config := cfg.TestMempoolConfig()
+		config.TTLNumBlocks = 2
+
+		mempool := newPriorityMempoolWithAppAndConfig(t, newPriorityApplication(), config)
+
+		tx := priorityTx("alice", 0, 1)
+		checkPriorityTx(t, mempool, tx)
+
+		require.NoError(t, mempool.Update(2, nil, nil, nil, 0))
+		assert.Equal(t, 1, mempool.Size())
+
+		require.NoError(t, mempool.Update(3, nil, nil, nil, 0))
+		assert.Equal(t, 0, mempool.Size())
+
+		// The expired tx can be resubmitted
+		res := checkPriorityTx(t, mempool, tx)
+		assert.Nil(t, res.Error)
+	})
+
+	t.Run("expired by time", func(t *testing.T) {
+		t.Parallel()
+
+		config := cfg.TestMempoolConfig()
+		config.TTLDuration = time.Millisecond
+
+		mempool := newPriorityMempoolWithAppAndConfig(t, newPriorityApplication(), config)
+		checkPriorityTx(t, mempool, priorityTx("alice", 0, 1))
+
+		time.Sleep(10 * time.Millisecond)
+
+		require.NoError(t, mempool.Update(1, nil, nil, nil, 0))
+		assert.Equal(t, 0, mempool.Size())
+	})
+}
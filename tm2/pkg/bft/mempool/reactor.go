@@ -27,13 +27,30 @@ const (
 	maxActiveIDs = math.MaxUint16
 )
 
+// ReactorMempool is a mempool whose txs are broadcast by the Reactor,
+// in the order they were added.
+type ReactorMempool interface {
+	Mempool
+
+	// SetLogger sets the Logger.
+	SetLogger(l *slog.Logger)
+
+	// TxsFront returns the first transaction in the ordered list for peer
+	// goroutines to call .NextWait() on.
+	TxsFront() *clist.CElement
+
+	// TxsWaitChan returns a channel to wait on transactions. It will be closed
+	// once the mempool is not empty.
+	TxsWaitChan() <-chan struct{}
+}
+
 // Reactor handles mempool tx broadcasting amongst peers.
 // It maintains a map from peer ID to counter, to prevent gossiping txs to the
 // peers you received it from.
 type Reactor struct {
 	p2p.BaseReactor
 	config  *cfg.MempoolConfig
-	mempool *CListMempool
+	mempool ReactorMempool
 	ids     *mempoolIDs
 }
 
@@ -101,7 +118,7 @@ func newMempoolIDs() *mempoolIDs {
 }
 
 // NewReactor returns a new Reactor with the given config and mempool.
-func NewReactor(config *cfg.MempoolConfig, mempool *CListMempool) *Reactor {
+func NewReactor(config *cfg.MempoolConfig, mempool ReactorMempool) *Reactor {
 	memR := &Reactor{
 		config:  config,
 		mempool: mempool,
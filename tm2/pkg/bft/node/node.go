@@ -4,6 +4,7 @@ package node
 // is enabled by the user by setting a profiling address
 
 import (
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"net"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/file"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
 	"github.com/rs/cors"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -21,7 +23,9 @@ import (
 	bc "github.com/gnolang/gno/tm2/pkg/bft/blockchain"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	cs "github.com/gnolang/gno/tm2/pkg/bft/consensus"
+	"github.com/gnolang/gno/tm2/pkg/bft/evidence"
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
+	memplcfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
 	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
 	rpccore "github.com/gnolang/gno/tm2/pkg/bft/rpc/core"
@@ -30,6 +34,7 @@ import (
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	"github.com/gnolang/gno/tm2/pkg/bft/store"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
@@ -39,6 +44,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/p2p/pex"
 	"github.com/gnolang/gno/tm2/pkg/service"
 	verset "github.com/gnolang/gno/tm2/pkg/versionset"
 )
@@ -170,6 +176,12 @@ type Node struct {
 	mempool           mempl.Mempool
 	consensusState    *cs.ConsensusState   // latest consensus state
 	consensusReactor  *cs.ConsensusReactor // for participating in the consensus
+	evidencePool      *evidence.Pool       // tracking evidence
+	pexReactor        *pex.Reactor         // for exchanging peer addresses
+	stateSyncReactor  *statesync.Reactor   // for bootstrapping from snapshots
+	stateSync         bool                 // whether the node must state sync
+	stateSyncGenesis  sm.State             // genesis state, to verify state sync snapshots
+	addrBook          pex.AddrBook         // known peers
 	proxyApp          appconn.AppConns     // connection to the application
 	rpcListeners      []net.Listener       // rpc servers
 	txEventStore      eventstore.TxEventStore
@@ -204,6 +216,7 @@ func createAndStartProxyAppConns(clientCreator proxy.ClientCreator, logger *slog
 
 func createAndStartEventStoreService(
 	cfg *cfg.Config,
+	dbProvider DBProvider,
 	evsw events.EventSwitch,
 	logger *slog.Logger,
 ) (*eventstore.Service, eventstore.TxEventStore, error) {
@@ -220,6 +233,14 @@ func createAndStartEventStoreService(
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to create file tx event store, %w", err)
 		}
+	case kv.EventStoreType:
+		// Transaction and block events should be indexed in a database
+		db, err := dbProvider(&DBContext{"tx_index", cfg})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create kv tx event store, %w", err)
+		}
+
+		txEventStore = kv.NewTxEventStore(db)
 	default:
 		// Transaction event storing should be omitted
 		txEventStore = null.NewNullEventStore()
@@ -271,14 +292,28 @@ func onlyValidatorIsUs(state sm.State, privVal types.PrivValidator) bool {
 
 func createMempoolAndMempoolReactor(config *cfg.Config, proxyApp appconn.AppConns,
 	state sm.State, logger *slog.Logger,
-) (*mempl.Reactor, *mempl.CListMempool) {
-	mempool := mempl.NewCListMempool(
-		config.Mempool,
-		proxyApp.Mempool(),
-		state.LastBlockHeight,
-		state.ConsensusParams.Block.MaxTxBytes,
-		mempl.WithPreCheck(sm.TxPreCheck(state)),
-	)
+) (*mempl.Reactor, mempl.ReactorMempool) {
+	var mempool mempl.ReactorMempool
+
+	switch config.Mempool.Type {
+	case memplcfg.TypePriority:
+		mempool = mempl.NewPriorityMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			state.ConsensusParams.Block.MaxTxBytes,
+			mempl.WithPriorityPreCheck(sm.TxPreCheck(state)),
+		)
+	default:
+		mempool = mempl.NewCListMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			state.ConsensusParams.Block.MaxTxBytes,
+			mempl.WithPreCheck(sm.TxPreCheck(state)),
+		)
+	}
+
 	mempoolLogger := logger.With("module", "mempool")
 	mempoolReactor := mempl.NewReactor(config.Mempool, mempool)
 	mempoolReactor.SetLogger(mempoolLogger)
@@ -289,6 +324,21 @@ func createMempoolAndMempoolReactor(config *cfg.Config, proxyApp appconn.AppConn
 	return mempoolReactor, mempool
 }
 
+func createEvidenceReactor(config *cfg.Config, dbProvider DBProvider,
+	stateDB dbm.DB, logger *slog.Logger,
+) (*evidence.Reactor, *evidence.Pool, error) {
+	evidenceDB, err := dbProvider(&DBContext{"evidence", config})
+	if err != nil {
+		return nil, nil, err
+	}
+	evidenceLogger := logger.With("module", "evidence")
+	evidencePool := evidence.NewPool(stateDB, evidenceDB)
+	evidencePool.SetLogger(evidenceLogger)
+	evidenceReactor := evidence.NewReactor(evidencePool)
+	evidenceReactor.SetLogger(evidenceLogger)
+	return evidenceReactor, evidencePool, nil
+}
+
 func createBlockchainReactor(config *cfg.Config,
 	state sm.State,
 	blockExec *sm.BlockExecutor,
@@ -302,11 +352,18 @@ func createBlockchainReactor(config *cfg.Config,
 	return bcReactor, nil
 }
 
+func createStateSyncReactor(config *cfg.Config, proxyApp appconn.AppConns, logger *slog.Logger) *statesync.Reactor {
+	stateSyncReactor := statesync.NewReactor(config.StateSync, proxyApp.Snapshot(), proxyApp.Query())
+	stateSyncReactor.SetLogger(logger.With("module", "statesync"))
+	return stateSyncReactor
+}
+
 func createConsensusReactor(config *cfg.Config,
 	state sm.State,
 	blockExec *sm.BlockExecutor,
 	blockStore sm.BlockStore,
-	mempool *mempl.CListMempool,
+	mempool mempl.Mempool,
+	evidencePool *evidence.Pool,
 	privValidator types.PrivValidator,
 	fastSync bool,
 	evsw events.EventSwitch,
@@ -318,6 +375,7 @@ func createConsensusReactor(config *cfg.Config,
 		blockExec,
 		blockStore,
 		mempool,
+		cs.StateEvidencePool(evidencePool),
 	)
 	consensusState.SetLogger(consensusLogger)
 	if privValidator != nil {
@@ -393,6 +451,8 @@ func createSwitch(config *cfg.Config,
 	mempoolReactor *mempl.Reactor,
 	bcReactor p2p.Reactor,
 	consensusReactor *cs.ConsensusReactor,
+	evidenceReactor *evidence.Reactor,
+	stateSyncReactor *statesync.Reactor,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
 	p2pLogger *slog.Logger,
@@ -406,6 +466,8 @@ func createSwitch(config *cfg.Config,
 	sw.AddReactor("MEMPOOL", mempoolReactor)
 	sw.AddReactor("BLOCKCHAIN", bcReactor)
 	sw.AddReactor("CONSENSUS", consensusReactor)
+	sw.AddReactor("EVIDENCE", evidenceReactor)
+	sw.AddReactor("STATESYNC", stateSyncReactor)
 
 	sw.SetNodeInfo(nodeInfo)
 	sw.SetNodeKey(nodeKey)
@@ -414,6 +476,49 @@ func createSwitch(config *cfg.Config,
 	return sw
 }
 
+func createAddrBookAndSetOnSwitch(config *cfg.Config, sw *p2p.Switch,
+	p2pLogger *slog.Logger, nodeKey *p2p.NodeKey,
+) (pex.AddrBook, error) {
+	addrBook := pex.NewAddrBook(config.P2P.AddrBookFile(), config.P2P.AddrBookStrict)
+	addrBook.SetLogger(p2pLogger.With("book", config.P2P.AddrBookFile()))
+
+	// Add ourselves to addrbook to prevent dialing ourselves
+	if config.P2P.ExternalAddress != "" {
+		addr, err := p2p.NewNetAddressFromString(p2p.NetAddressString(nodeKey.ID(), config.P2P.ExternalAddress))
+		if err != nil {
+			return nil, errors.Wrap(err, "p2p.external_address is incorrect")
+		}
+		addrBook.AddOurAddress(addr)
+	}
+	if config.P2P.ListenAddress != "" {
+		addr, err := p2p.NewNetAddressFromString(p2p.NetAddressString(nodeKey.ID(), config.P2P.ListenAddress))
+		if err != nil {
+			return nil, errors.Wrap(err, "p2p.laddr is incorrect")
+		}
+		addrBook.AddOurAddress(addr)
+	}
+
+	sw.SetAddrBook(addrBook)
+
+	return addrBook, nil
+}
+
+func createPEXReactorAndAddToSwitch(addrBook pex.AddrBook, config *cfg.Config,
+	sw *p2p.Switch, logger *slog.Logger,
+) *pex.Reactor {
+	pexReactor := pex.NewReactor(addrBook,
+		&pex.ReactorConfig{
+			Seeds:    splitAndTrimEmpty(config.P2P.Seeds, ",", " "),
+			SeedMode: config.P2P.SeedMode,
+			// See consensus/reactor.go: blocksToContributeToBecomeGoodPeer 10000
+			// blocks assuming 10s blocks ~ 28 hours.
+			SeedDisconnectWaitPeriod: 28 * time.Hour,
+		})
+	pexReactor.SetLogger(logger.With("module", "pex"))
+	sw.AddReactor("PEX", pexReactor)
+	return pexReactor
+}
+
 // NewNode returns a new, ready to go, Tendermint Node.
 func NewNode(config *cfg.Config,
 	privValidator types.PrivValidator,
@@ -456,16 +561,25 @@ func NewNode(config *cfg.Config,
 	})
 
 	// Transaction event storing
-	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, evsw, logger)
+	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, dbProvider, evsw, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	// Decide whether to state sync or not.
+	// Only a node without any block can be bootstrapped from a snapshot.
+	stateSync := config.StateSync.Enable && state.LastBlockHeight == 0
+	stateSyncGenesis := state
+
 	// Create the handshaker, which calls RequestInfo, sets the AppVersion on the state,
 	// and replays any blocks as necessary to sync tendermint with the app.
+	// When state syncing, the app is restored from a snapshot instead of being
+	// initialized from genesis, so the handshake is skipped.
 	consensusLogger := logger.With("module", "consensus")
-	if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
-		return nil, err
+	if !stateSync {
+		if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
+			return nil, err
+		}
 	}
 
 	// Reload the state. It will have the Version.Consensus.App set by the
@@ -498,26 +612,38 @@ func NewNode(config *cfg.Config,
 	// Make MempoolReactor
 	mempoolReactor, mempool := createMempoolAndMempoolReactor(config, proxyApp, state, logger)
 
+	// Make Evidence Reactor
+	evidenceReactor, evidencePool, err := createEvidenceReactor(config, dbProvider, stateDB, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create evidence reactor")
+	}
+
 	// make block executor for consensus and blockchain reactors to execute blocks
 	blockExec := sm.NewBlockExecutor(
 		stateDB,
 		logger.With("module", "state"),
 		proxyApp.Consensus(),
 		mempool,
+		sm.WithEvidencePool(evidencePool),
 	)
 
-	// Make BlockchainReactor
-	bcReactor, err := createBlockchainReactor(config, state, blockExec, blockStore, fastSync, logger)
+	// Make BlockchainReactor. When state syncing, fast sync is started once
+	// the state is restored.
+	bcReactor, err := createBlockchainReactor(config, state, blockExec, blockStore, fastSync && !stateSync, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create blockchain reactor")
 	}
 
-	// Make ConsensusReactor
+	// Make ConsensusReactor. When state syncing, it waits for the state sync
+	// and fast sync to complete.
 	consensusReactor, consensusState := createConsensusReactor(
-		config, state, blockExec, blockStore, mempool,
-		privValidator, fastSync, evsw, consensusLogger,
+		config, state, blockExec, blockStore, mempool, evidencePool,
+		privValidator, fastSync || stateSync, evsw, consensusLogger,
 	)
 
+	// Make StateSyncReactor, which also serves the snapshots of the app.
+	stateSyncReactor := createStateSyncReactor(config, proxyApp, logger)
+
 	nodeInfo, err := makeNodeInfo(config, nodeKey, txEventStore, genDoc, state)
 	if err != nil {
 		return nil, errors.Wrap(err, "error making NodeInfo")
@@ -530,7 +656,7 @@ func NewNode(config *cfg.Config,
 	p2pLogger := logger.With("module", "p2p")
 	sw := createSwitch(
 		config, transport, peerFilters, mempoolReactor, bcReactor,
-		consensusReactor, nodeInfo, nodeKey, p2pLogger,
+		consensusReactor, evidenceReactor, stateSyncReactor, nodeInfo, nodeKey, p2pLogger,
 	)
 
 	err = sw.AddPersistentPeers(splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
@@ -538,6 +664,20 @@ func NewNode(config *cfg.Config,
 		return nil, errors.Wrap(err, "could not add peers from persistent_peers field")
 	}
 
+	addrBook, err := createAddrBookAndSetOnSwitch(config, sw, p2pLogger, nodeKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create addrbook")
+	}
+
+	addrBook.AddPrivateIDs(splitAndTrimEmpty(config.P2P.PrivatePeerIDs, ",", " "))
+
+	// Optionally, start the pex reactor, which takes care of dialing
+	// the seeds and of keeping the node connected to enough peers.
+	var pexReactor *pex.Reactor
+	if config.P2P.PexReactor {
+		pexReactor = createPEXReactorAndAddToSwitch(addrBook, config, sw, logger)
+	}
+
 	if config.ProfListenAddress != "" {
 		server := &http.Server{
 			Addr:              config.ProfListenAddress,
@@ -567,6 +707,12 @@ func NewNode(config *cfg.Config,
 		mempool:           mempool,
 		consensusState:    consensusState,
 		consensusReactor:  consensusReactor,
+		evidencePool:      evidencePool,
+		pexReactor:        pexReactor,
+		stateSyncReactor:  stateSyncReactor,
+		stateSync:         stateSync,
+		stateSyncGenesis:  stateSyncGenesis,
+		addrBook:          addrBook,
 		proxyApp:          proxyApp,
 		txEventStore:      txEventStore,
 		eventStoreService: eventStoreService,
@@ -644,6 +790,65 @@ func (n *Node) OnStart() error {
 		return errors.Wrap(err, "could not dial peers from persistent_peers field")
 	}
 
+	// Run state sync
+	if n.stateSync {
+		bcR, ok := n.bcReactor.(*bc.BlockchainReactor)
+		if !ok {
+			return fmt.Errorf("this blockchain reactor does not support switching from state sync")
+		}
+		if err := n.startStateSync(bcR); err != nil {
+			return errors.Wrap(err, "could not start state sync")
+		}
+	}
+
+	return nil
+}
+
+// startStateSync starts the state sync in the background. Once the app is
+// restored from a snapshot, the state and block store are bootstrapped at the
+// snapshot height, and the node switches to fast sync, or directly to
+// consensus if fast sync is disabled.
+func (n *Node) startStateSync(bcR *bc.BlockchainReactor) error {
+	trustHash, err := hex.DecodeString(n.config.StateSync.TrustHash)
+	if err != nil {
+		return fmt.Errorf("invalid trust hash: %w", err)
+	}
+	stateProvider, err := statesync.NewRPCStateProvider(
+		n.stateSyncGenesis,
+		n.config.StateSync.RPCServerList(),
+		n.config.StateSync.TrustHeight,
+		trustHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set up the state provider: %w", err)
+	}
+
+	fastSync := n.config.FastSyncMode
+	go func() {
+		state, commit, err := n.stateSyncReactor.Sync(stateProvider)
+		if err != nil {
+			n.Logger.Error("State sync failed", "err", err)
+			return
+		}
+		if err := sm.BootstrapState(n.stateDB, state); err != nil {
+			n.Logger.Error("Failed to bootstrap the state", "err", err)
+			return
+		}
+		if err := n.blockStore.Bootstrap(state.LastBlockHeight, commit); err != nil {
+			n.Logger.Error("Failed to bootstrap the block store", "err", err)
+			return
+		}
+
+		if fastSync {
+			if err := bcR.SwitchToFastSync(state); err != nil {
+				n.Logger.Error("Failed to switch to fast sync", "err", err)
+			}
+			return
+		}
+		// The WAL has no record of the restored state, so the restored
+		// height is reported as synced to skip the WAL catchup.
+		n.consensusReactor.SwitchToConsensus(state, 1)
+	}()
 	return nil
 }
 
@@ -701,6 +906,7 @@ func (n *Node) configureRPC() {
 	rpccore.SetGetFastSync(n.consensusReactor.FastSync)
 	rpccore.SetLogger(n.Logger.With("module", "rpc"))
 	rpccore.SetEventSwitch(n.evsw)
+	rpccore.SetTxEventStore(n.txEventStore)
 	rpccore.SetConfig(*n.config.RPC)
 }
 
@@ -726,10 +932,7 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		rpcLogger := n.Logger.With("module", "rpc-server")
 		wmLogger := rpcLogger.With("protocol", "websocket")
 		wm := rpcserver.NewWebsocketManager(rpccore.Routes,
-			rpcserver.OnDisconnect(func(remoteAddr string) {
-				// any cleanup...
-				// (we used to unsubscribe from all event subscriptions)
-			}),
+			rpcserver.OnDisconnect(rpccore.UnsubscribeClient),
 			rpcserver.ReadLimit(config.MaxBodyBytes),
 		)
 		wm.SetLogger(wmLogger)
@@ -815,6 +1018,16 @@ func (n *Node) ConsensusReactor() *cs.ConsensusReactor {
 	return n.consensusReactor
 }
 
+// EvidencePool returns the Node's EvidencePool.
+func (n *Node) EvidencePool() *evidence.Pool {
+	return n.evidencePool
+}
+
+// PEXReactor returns the Node's PEXReactor. It returns nil if PEX is disabled.
+func (n *Node) PEXReactor() *pex.Reactor {
+	return n.pexReactor
+}
+
 // MempoolReactor returns the Node's mempool reactor.
 func (n *Node) MempoolReactor() *mempl.Reactor {
 	return n.mempoolReactor
@@ -890,6 +1103,8 @@ func makeNodeInfo(
 			bcChannel,
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
 			mempl.MempoolChannel,
+			evidence.EvidenceChannel,
+			statesync.SnapshotChannel, statesync.ChunkChannel,
 		},
 		Moniker: config.Moniker,
 		Other: p2p.NodeInfoOther{
@@ -898,6 +1113,10 @@ func makeNodeInfo(
 		},
 	}
 
+	if config.P2P.PexReactor {
+		nodeInfo.Channels = append(nodeInfo.Channels, pex.PexChannel)
+	}
+
 	lAddr := config.P2P.ExternalAddress
 	if lAddr == "" {
 		lAddr = config.P2P.ListenAddress
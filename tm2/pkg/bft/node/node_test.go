@@ -15,6 +15,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
+	memplcfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
 	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
@@ -110,6 +111,19 @@ func TestNodeDelayedStart(t *testing.T) {
 	assert.Equal(t, true, startTime.After(n.GenesisDoc().GenesisTime))
 }
 
+func TestNodePriorityMempool(t *testing.T) {
+	config, genesisFile := cfg.ResetTestRoot("node_priority_mempool_test")
+	defer os.RemoveAll(config.RootDir)
+
+	config.Mempool.Type = memplcfg.TypePriority
+
+	// create node
+	n, err := DefaultNewNode(config, genesisFile, events.NewEventSwitch(), log.NewNoopLogger())
+	require.NoError(t, err)
+
+	assert.IsType(t, &mempl.PriorityMempool{}, n.Mempool())
+}
+
 func TestNodeReady(t *testing.T) {
 	config, genesisFile := cfg.ResetTestRoot("node_node_test")
 	defer os.RemoveAll(config.RootDir)
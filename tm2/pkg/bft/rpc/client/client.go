@@ -38,6 +38,8 @@ const (
 	blockResultsMethod       = "block_results"
 	commitMethod             = "commit"
 	txMethod                 = "tx"
+	txSearchMethod           = "tx_search"
+	blockSearchMethod        = "block_search"
 	validatorsMethod         = "validators"
 )
 
@@ -311,6 +313,34 @@ func (c *RPCClient) Tx(hash []byte) (*ctypes.ResultTx, error) {
 	)
 }
 
+func (c *RPCClient) TxSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	return sendRequestCommon[ctypes.ResultTxSearch](
+		c.caller,
+		c.requestTimeout,
+		txSearchMethod,
+		map[string]any{
+			"query":    query,
+			"page":     page,
+			"per_page": perPage,
+			"order_by": orderBy,
+		},
+	)
+}
+
+func (c *RPCClient) BlockSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	return sendRequestCommon[ctypes.ResultBlockSearch](
+		c.caller,
+		c.requestTimeout,
+		blockSearchMethod,
+		map[string]any{
+			"query":    query,
+			"page":     page,
+			"per_page": perPage,
+			"order_by": orderBy,
+		},
+	)
+}
+
 func (c *RPCClient) Validators(height *int64) (*ctypes.ResultValidators, error) {
 	params := map[string]any{}
 	if height != nil {
@@ -761,6 +761,101 @@ func TestRPCClient_Tx(t *testing.T) {
 	assert.Equal(t, expectedResult, result)
 }
 
+func TestRPCClient_TxSearch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		query   = "message.type = 'exec'"
+		page    = 2
+		perPage = 10
+		orderBy = "desc"
+
+		expectedResult = &ctypes.ResultTxSearch{
+			Txs: []*ctypes.ResultTx{
+				{
+					Hash:   []byte("tx hash"),
+					Height: 10,
+				},
+			},
+			HasMore: true,
+		}
+
+		verifyFn = func(t *testing.T, params map[string]any) {
+			t.Helper()
+
+			assert.Equal(t, query, params["query"])
+			assert.Equal(t, fmt.Sprintf("%d", page), params["page"])
+			assert.Equal(t, fmt.Sprintf("%d", perPage), params["per_page"])
+			assert.Equal(t, orderBy, params["order_by"])
+		}
+
+		mockClient = generateMockRequestClient(
+			t,
+			txSearchMethod,
+			verifyFn,
+			expectedResult,
+		)
+	)
+
+	// Create the client
+	c := NewRPCClient(mockClient)
+
+	// Get the result
+	result, err := c.TxSearch(query, page, perPage, orderBy)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedResult, result)
+}
+
+func TestRPCClient_BlockSearch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		query   = "block.height > 10"
+		page    = 1
+		perPage = 30
+		orderBy = "asc"
+
+		expectedResult = &ctypes.ResultBlockSearch{
+			Blocks: []*ctypes.ResultBlock{
+				{
+					BlockMeta: &bfttypes.BlockMeta{
+						Header: bfttypes.Header{
+							Height: 11,
+						},
+					},
+				},
+			},
+			HasMore: true,
+		}
+
+		verifyFn = func(t *testing.T, params map[string]any) {
+			t.Helper()
+
+			assert.Equal(t, query, params["query"])
+			assert.Equal(t, fmt.Sprintf("%d", page), params["page"])
+			assert.Equal(t, fmt.Sprintf("%d", perPage), params["per_page"])
+			assert.Equal(t, orderBy, params["order_by"])
+		}
+
+		mockClient = generateMockRequestClient(
+			t,
+			blockSearchMethod,
+			verifyFn,
+			expectedResult,
+		)
+	)
+
+	// Create the client
+	c := NewRPCClient(mockClient)
+
+	// Get the result
+	result, err := c.BlockSearch(query, page, perPage, orderBy)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedResult, result)
+}
+
 func TestRPCClient_Validators(t *testing.T) {
 	t.Parallel()
 
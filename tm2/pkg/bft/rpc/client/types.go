@@ -17,10 +17,9 @@ var DefaultABCIQueryOptions = ABCIQueryOptions{Height: 0, Prove: false}
 
 // Client wraps most important rpc calls a client would make.
 //
-// NOTE: Events cannot be subscribed to from the RPC APIs. For events
-// subscriptions and filters and queries, an external API must be used that
-// first synchronously consumes the events from the node's synchronous event
-// switch, or reads logged events from the filesystem.
+// NOTE: Events can only be subscribed to over a websocket connection, with
+// the subscribe, unsubscribe and unsubscribe_all methods; they are not part
+// of this interface.
 type Client interface {
 	ABCIClient
 	HistoryClient
@@ -85,6 +84,10 @@ type MempoolClient interface {
 	NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error)
 }
 
+// TxClient provides access to the transactions and blocks, by hash or
+// by searching the attributes of their events.
 type TxClient interface {
 	Tx(hash []byte) (*ctypes.ResultTx, error)
+	TxSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error)
+	BlockSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error)
 }
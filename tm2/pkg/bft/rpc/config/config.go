@@ -56,6 +56,14 @@ type RPCConfig struct {
 	// 1024 - 40 - 10 - 50 = 924 = ~900
 	MaxOpenConnections int `json:"max_open_connections" toml:"max_open_connections" comment:"Maximum number of simultaneous connections (including WebSocket).\n Does not include gRPC connections. See grpc_max_open_connections\n If you want to accept a larger number than the default, make sure\n you increase your OS limits.\n 0 - unlimited.\n Should be < {ulimit -Sn} - {MaxNumInboundPeers} - {MaxNumOutboundPeers} - {N of wal, db and other open files}\n 1024 - 40 - 10 - 50 = 924 = ~900"`
 
+	// Maximum number of unique clients that can /subscribe over a websocket.
+	// 0 - unlimited.
+	MaxSubscriptionClients int `json:"max_subscription_clients" toml:"max_subscription_clients" comment:"Maximum number of unique clients that can /subscribe over a websocket.\n 0 - unlimited."`
+
+	// Maximum number of unique queries a given client can /subscribe to.
+	// 0 - unlimited.
+	MaxSubscriptionsPerClient int `json:"max_subscriptions_per_client" toml:"max_subscriptions_per_client" comment:"Maximum number of unique queries a given client can /subscribe to.\n 0 - unlimited."`
+
 	// How long to wait for a tx to be committed during /broadcast_tx_commit
 	// WARNING: Using a value larger than 10s will result in increasing the
 	// global HTTP write timeout, which applies to all connections and endpoints.
@@ -98,6 +106,9 @@ func DefaultRPCConfig() *RPCConfig {
 		Unsafe:             false,
 		MaxOpenConnections: 900,
 
+		MaxSubscriptionClients:    100,
+		MaxSubscriptionsPerClient: 5,
+
 		TimeoutBroadcastTxCommit: 10 * time.Second,
 
 		MaxBodyBytes:   int64(1000000), // 1MB
@@ -126,6 +137,12 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxOpenConnections < 0 {
 		return errors.New("max_open_connections can't be negative")
 	}
+	if cfg.MaxSubscriptionClients < 0 {
+		return errors.New("max_subscription_clients can't be negative")
+	}
+	if cfg.MaxSubscriptionsPerClient < 0 {
+		return errors.New("max_subscriptions_per_client can't be negative")
+	}
 	if cfg.TimeoutBroadcastTxCommit < 0 {
 		return errors.New("timeout_broadcast_tx_commit can't be negative")
 	}
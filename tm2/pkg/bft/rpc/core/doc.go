@@ -63,6 +63,7 @@ JSONRPC requests can be POST'd to the root RPC endpoint via HTTP (e.g. `http://l
 ## JSONRPC/websockets
 
 JSONRPC requests can be made via websocket. The websocket endpoint is at `/websocket`, e.g. `localhost:26657/websocket`.
+Event subscriptions (`subscribe`, `unsubscribe` and `unsubscribe_all`) are only available over websockets.
 
 ## More Examples
 
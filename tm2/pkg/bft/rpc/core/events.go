@@ -0,0 +1,252 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+	"github.com/gnolang/gno/tm2/pkg/random"
+	"github.com/gnolang/gno/tm2/pkg/service"
+)
+
+// Subscribe for events via WebSocket.
+//
+// The query selects the events to receive, by their attributes:
+//
+//	tm.event = 'Tx' AND message.type = 'exec' AND gno.land/r/demo/boards.func = 'CreatePost'
+//
+// Every event has a tm.event attribute (e.g. "NewBlock", "NewBlockHeader",
+// "Tx", "Vote") and most have a block.height. Transactions also have the
+// attributes of their messages and of the events they emitted (see
+// types.TxResultAttributes). Conditions are joined by AND, and compare
+// an attribute with a 'string' or a number using =, !=, <, <=, >, >=,
+// CONTAINS or EXISTS. The empty query matches all events.
+//
+// Each matching event is sent as a JSON-RPC response with the ID
+// "<id>#event", where <id> is the ID of the subscribe request, until the
+// client unsubscribes or disconnects. Events are dropped if the client
+// does not keep up.
+//
+// ```shell
+// wscat -c ws://localhost:26657/websocket
+// > {"jsonrpc": "2.0", "method": "subscribe", "params": ["tm.event = 'Tx'"], "id": 1}
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"jsonrpc": "2.0",
+//		"id": 1,
+//		"result": {}
+//	}
+//
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description |
+// |-----------+--------+---------+----------+-------------|
+// | query     | string | ""      | false    | Event query |
+func Subscribe(ctx *rpctypes.Context, q string) (*ctypes.ResultSubscribe, error) {
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+	id := rpctypes.JSONRPCStringID(fmt.Sprintf("%v#event", ctx.JSONReq.ID))
+	if err := gEventDispatcher.subscribe(ctx.WSConn, parsed, id); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultSubscribe{}, nil
+}
+
+// Unsubscribe from events via WebSocket.
+//
+// The query must be equivalent to the one of an existing subscription.
+//
+// ```shell
+// wscat -c ws://localhost:26657/websocket
+// > {"jsonrpc": "2.0", "method": "unsubscribe", "params": ["tm.event = 'Tx'"], "id": 2}
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description |
+// |-----------+--------+---------+----------+-------------|
+// | query     | string | ""      | false    | Event query |
+func Unsubscribe(ctx *rpctypes.Context, q string) (*ctypes.ResultUnsubscribe, error) {
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := gEventDispatcher.unsubscribe(ctx.RemoteAddr(), parsed); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// Unsubscribe from all events via WebSocket.
+//
+// ```shell
+// wscat -c ws://localhost:26657/websocket
+// > {"jsonrpc": "2.0", "method": "unsubscribe_all", "params": [], "id": 3}
+// ```
+func UnsubscribeAll(ctx *rpctypes.Context) (*ctypes.ResultUnsubscribe, error) {
+	if !gEventDispatcher.unsubscribeAll(ctx.RemoteAddr()) {
+		return nil, errors.New("no subscriptions")
+	}
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// UnsubscribeClient removes the subscriptions of the client at remoteAddr.
+// It is meant to be called when the websocket connection is closed.
+func UnsubscribeClient(remoteAddr string) {
+	if gEventDispatcher != nil {
+		gEventDispatcher.unsubscribeAll(remoteAddr)
+	}
+}
+
+// ----------------------------------------
+// eventDispatcher
+
+// eventDispatcher sends the events of the event switch to the websocket
+// clients whose queries match them.
+type eventDispatcher struct {
+	service.BaseService
+	evsw       events.EventSwitch
+	listenerID string
+	sub        <-chan events.Event
+
+	mtx     sync.RWMutex
+	clients map[string]*eventClient // remote address -> *eventClient
+}
+
+type eventClient struct {
+	conn rpctypes.WSRPCConnection
+	subs map[string]eventSubscription // canonical query -> subscription
+}
+
+type eventSubscription struct {
+	query *query.Query
+	id    rpctypes.JSONRPCID
+}
+
+func newEventDispatcher(evsw events.EventSwitch) *eventDispatcher {
+	listenerID := fmt.Sprintf("eventDispatcher#%v", random.RandStr(6))
+	sub := events.Subscribe(evsw, listenerID)
+
+	ed := &eventDispatcher{
+		evsw:       evsw,
+		listenerID: listenerID,
+		sub:        sub,
+		clients:    make(map[string]*eventClient),
+	}
+	ed.BaseService = *service.NewBaseService(nil, "eventDispatcher", ed)
+	return ed
+}
+
+func (ed *eventDispatcher) OnStart() error {
+	go ed.listenRoutine()
+	return nil
+}
+
+func (ed *eventDispatcher) OnStop() {
+	ed.evsw.RemoveListener(ed.listenerID)
+}
+
+func (ed *eventDispatcher) listenRoutine() {
+	for {
+		select {
+		case event, ok := <-ed.sub:
+			if !ok {
+				// The event switch was stopped, along with the node.
+				ed.Stop()
+				return
+			}
+			ed.dispatch(event)
+		case <-ed.Quit():
+			return
+		}
+	}
+}
+
+func (ed *eventDispatcher) dispatch(event events.Event) {
+	ed.mtx.RLock()
+	defer ed.mtx.RUnlock()
+
+	if len(ed.clients) == 0 {
+		return // nothing to do
+	}
+	attrs := types.EventAttributes(event)
+	for addr, client := range ed.clients {
+		for _, sub := range client.subs {
+			if !sub.query.Matches(attrs) {
+				continue
+			}
+			res := &ctypes.ResultEvent{Query: sub.query.String(), Event: event}
+			resp := rpctypes.NewRPCSuccessResponse(sub.id, res)
+			if !client.conn.TryWriteRPCResponses(rpctypes.RPCResponses{resp}) {
+				logger.Info("Dropped event for slow websocket client", "remote", addr, "query", res.Query)
+			}
+		}
+	}
+}
+
+func (ed *eventDispatcher) subscribe(conn rpctypes.WSRPCConnection, q *query.Query, id rpctypes.JSONRPCID) error {
+	ed.mtx.Lock()
+	defer ed.mtx.Unlock()
+
+	addr := conn.GetRemoteAddr()
+	client, ok := ed.clients[addr]
+	if !ok {
+		if config.MaxSubscriptionClients > 0 && len(ed.clients) >= config.MaxSubscriptionClients {
+			return fmt.Errorf("max_subscription_clients %d reached", config.MaxSubscriptionClients)
+		}
+		client = &eventClient{conn: conn, subs: make(map[string]eventSubscription)}
+	}
+	key := q.String()
+	if _, ok := client.subs[key]; ok {
+		return errors.New("already subscribed")
+	}
+	if config.MaxSubscriptionsPerClient > 0 && len(client.subs) >= config.MaxSubscriptionsPerClient {
+		return fmt.Errorf("max_subscriptions_per_client %d reached", config.MaxSubscriptionsPerClient)
+	}
+	client.subs[key] = eventSubscription{query: q, id: id}
+	ed.clients[addr] = client
+	return nil
+}
+
+func (ed *eventDispatcher) unsubscribe(addr string, q *query.Query) error {
+	ed.mtx.Lock()
+	defer ed.mtx.Unlock()
+
+	client, ok := ed.clients[addr]
+	if !ok {
+		return errors.New("subscription not found")
+	}
+	key := q.String()
+	if _, ok := client.subs[key]; !ok {
+		return errors.New("subscription not found")
+	}
+	delete(client.subs, key)
+	if len(client.subs) == 0 {
+		delete(ed.clients, addr)
+	}
+	return nil
+}
+
+// unsubscribeAll returns false if the client had no subscriptions.
+func (ed *eventDispatcher) unsubscribeAll(addr string) bool {
+	ed.mtx.Lock()
+	defer ed.mtx.Unlock()
+
+	_, ok := ed.clients[addr]
+	delete(ed.clients, addr)
+	return ok
+}
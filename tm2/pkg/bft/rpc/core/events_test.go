@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWSConn struct {
+	remoteAddr string
+	responses  chan rpctypes.RPCResponse
+}
+
+func newMockWSConn(remoteAddr string) *mockWSConn {
+	return &mockWSConn{
+		remoteAddr: remoteAddr,
+		responses:  make(chan rpctypes.RPCResponse, 10),
+	}
+}
+
+func (c *mockWSConn) GetRemoteAddr() string { return c.remoteAddr }
+
+func (c *mockWSConn) WriteRPCResponses(resps rpctypes.RPCResponses) {
+	for _, resp := range resps {
+		c.responses <- resp
+	}
+}
+
+func (c *mockWSConn) TryWriteRPCResponses(resps rpctypes.RPCResponses) bool {
+	c.WriteRPCResponses(resps)
+	return true
+}
+
+func (c *mockWSConn) Context() context.Context { return context.Background() }
+
+// nextEvent returns the next event written to c, or fails after a timeout.
+func (c *mockWSConn) nextEvent(t *testing.T) (rpctypes.RPCResponse, *ctypes.ResultEvent) {
+	t.Helper()
+
+	select {
+	case resp := <-c.responses:
+		require.Nil(t, resp.Error)
+		var res ctypes.ResultEvent
+		require.NoError(t, amino.UnmarshalJSON(resp.Result, &res))
+		return resp, &res
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return rpctypes.RPCResponse{}, nil
+	}
+}
+
+func (c *mockWSConn) assertNoEvent(t *testing.T) {
+	t.Helper()
+
+	select {
+	case resp := <-c.responses:
+		t.Fatalf("unexpected event: %s", resp.Result)
+	default:
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+
+	rpcConfig := cfg.DefaultRPCConfig()
+	rpcConfig.MaxSubscriptionClients = 2
+	rpcConfig.MaxSubscriptionsPerClient = 2
+	SetConfig(*rpcConfig)
+	SetLogger(log.NewNoopLogger())
+	SetEventSwitch(evsw)
+	require.NoError(t, gEventDispatcher.Start())
+	defer gEventDispatcher.Stop()
+
+	newCtx := func(conn *mockWSConn, id int) *rpctypes.Context {
+		return &rpctypes.Context{
+			JSONReq: &rpctypes.RPCRequest{ID: rpctypes.JSONRPCIntID(id)},
+			WSConn:  conn,
+		}
+	}
+	header := func(height int64) types.EventNewBlockHeader {
+		return types.EventNewBlockHeader{Header: types.Header{Height: height}}
+	}
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := Subscribe(newCtx(newMockWSConn("invalid"), 1), "tm.event = NewBlockHeader")
+		assert.Error(t, err)
+	})
+
+	t.Run("matching events", func(t *testing.T) {
+		conn := newMockWSConn("matching")
+		defer UnsubscribeClient(conn.remoteAddr)
+
+		_, err := Subscribe(newCtx(conn, 1), "tm.event = 'NewBlockHeader' AND block.height >= 2")
+		require.NoError(t, err)
+
+		evsw.FireEvent(header(1))
+		evsw.FireEvent(types.EventString("ignored"))
+		evsw.FireEvent(header(2))
+
+		resp, res := conn.nextEvent(t)
+		assert.Equal(t, "1#event", resp.ID.String())
+		assert.Equal(t, "tm.event = 'NewBlockHeader' AND block.height >= 2", res.Query)
+		assert.Equal(t, header(2), res.Event)
+		conn.assertNoEvent(t)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		conn := newMockWSConn("unsubscribe")
+		ctx := newCtx(conn, 1)
+
+		_, err := Subscribe(ctx, "tm.event = 'NewBlockHeader'")
+		require.NoError(t, err)
+		_, err = Subscribe(ctx, "tm.event='NewBlockHeader'")
+		assert.Error(t, err, "already subscribed")
+		_, err = Unsubscribe(ctx, "tm.event = 'Tx'")
+		assert.Error(t, err, "not subscribed")
+
+		_, err = Unsubscribe(ctx, "tm.event='NewBlockHeader'")
+		require.NoError(t, err)
+		evsw.FireEvent(header(1))
+		_, err = Subscribe(ctx, "tm.event = 'String'")
+		require.NoError(t, err)
+		evsw.FireEvent(types.EventString("next"))
+		_, res := conn.nextEvent(t)
+		assert.Equal(t, types.EventString("next"), res.Event)
+
+		_, err = UnsubscribeAll(ctx)
+		require.NoError(t, err)
+		_, err = UnsubscribeAll(ctx)
+		assert.Error(t, err)
+		evsw.FireEvent(header(3))
+		conn.assertNoEvent(t)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		conn1 := newMockWSConn("limits1")
+		conn2 := newMockWSConn("limits2")
+		conn3 := newMockWSConn("limits3")
+		defer UnsubscribeClient(conn1.remoteAddr)
+		defer UnsubscribeClient(conn2.remoteAddr)
+
+		_, err := Subscribe(newCtx(conn1, 1), "a = 1")
+		require.NoError(t, err)
+		_, err = Subscribe(newCtx(conn1, 2), "a = 2")
+		require.NoError(t, err)
+		_, err = Subscribe(newCtx(conn1, 3), "a = 3")
+		assert.Error(t, err, "max subscriptions per client")
+
+		_, err = Subscribe(newCtx(conn2, 1), "a = 1")
+		require.NoError(t, err)
+		_, err = Subscribe(newCtx(conn3, 1), "a = 1")
+		assert.Error(t, err, "max subscription clients")
+	})
+	t.Run("unlimited", func(t *testing.T) {
+		unlimited := *rpcConfig
+		unlimited.MaxSubscriptionClients = 0
+		unlimited.MaxSubscriptionsPerClient = 0
+		SetConfig(unlimited)
+		defer SetConfig(*rpcConfig)
+
+		for i := 0; i < 3; i++ {
+			conn := newMockWSConn(fmt.Sprintf("unlimited%d", i))
+			defer UnsubscribeClient(conn.remoteAddr)
+			for j := 0; j < 3; j++ {
+				_, err := Subscribe(newCtx(conn, j), fmt.Sprintf("a = %d", j))
+				require.NoError(t, err)
+			}
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
@@ -64,12 +65,14 @@ var (
 	p2pTransport   transport
 
 	// objects
-	pubKey        crypto.PubKey
-	genDoc        *types.GenesisDoc // cache the genesis structure
-	evsw          events.EventSwitch
-	gTxDispatcher *txDispatcher
-	mempool       mempl.Mempool
-	getFastSync   func() bool // avoids dependency on consensus pkg
+	pubKey           crypto.PubKey
+	genDoc           *types.GenesisDoc // cache the genesis structure
+	evsw             events.EventSwitch
+	gTxDispatcher    *txDispatcher
+	gEventDispatcher *eventDispatcher
+	txEventStore     eventstore.TxEventStore
+	mempool          mempl.Mempool
+	getFastSync      func() bool // avoids dependency on consensus pkg
 
 	logger *slog.Logger
 
@@ -120,13 +123,19 @@ func SetLogger(l *slog.Logger) {
 	logger = l
 }
 
+func SetTxEventStore(store eventstore.TxEventStore) {
+	txEventStore = store
+}
+
 func SetEventSwitch(sw events.EventSwitch) {
 	evsw = sw
 	gTxDispatcher = newTxDispatcher(evsw)
+	gEventDispatcher = newEventDispatcher(evsw)
 }
 
 func Start() {
 	gTxDispatcher.Start()
+	gEventDispatcher.Start()
 }
 
 // SetConfig sets an RPCConfig.
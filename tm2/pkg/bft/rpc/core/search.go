@@ -0,0 +1,160 @@
+package core
+
+import (
+	"fmt"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+)
+
+var errSearchDisabled = errors.New("search is disabled: the tx event store is not indexed (see tx_event_store.event_store_type)")
+
+// TxSearch allows you to query for multiple transactions results, by the
+// attributes of their messages and of the events they emitted. See Subscribe
+// for the query syntax and the attributes of transactions.
+//
+// It requires the "kv" tx event store, which only indexes the transactions
+// executed after it was enabled.
+//
+// ```shell
+// curl "localhost:26657/tx_search?query=\"message.pkg_path='gno.land/r/demo/users'\"&page=1&per_page=30&order_by=\"desc\""
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                                    |
+// |-----------+--------+---------+----------+------------------------------------------------|
+// | query     | string | ""      | true     | Query                                          |
+// | page      | int    | 1       | false    | Page number (1-based)                          |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100)          |
+// | order_by  | string | "asc"   | false    | Order by height and index ("asc" or "desc")    |
+func TxSearch(_ *rpctypes.Context, q string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	searcher, ok := txEventStore.(eventstore.Searcher)
+	if !ok {
+		return nil, errSearchDisabled
+	}
+
+	parsed, desc, err := parseSearch(q, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	page, perPage, err = searchPage(page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	results, more, err := searcher.SearchTxs(parsed, desc, (page-1)*perPage, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := validatePage(page, perPage, (page-1)*perPage+len(results)); err != nil {
+		return nil, err
+	}
+
+	txs := make([]*ctypes.ResultTx, 0, len(results))
+	for _, result := range results {
+		txs = append(txs, &ctypes.ResultTx{
+			Hash:     result.Tx.Hash(),
+			Height:   result.Height,
+			Index:    result.Index,
+			TxResult: result.Response,
+			Tx:       result.Tx,
+		})
+	}
+
+	return &ctypes.ResultTxSearch{Txs: txs, HasMore: more}, nil
+}
+
+// BlockSearch allows you to query for multiple blocks, by their height and
+// the attributes of the events emitted when beginning and ending them.
+// See Subscribe for the query syntax.
+//
+// It requires the "kv" tx event store, which only indexes the blocks
+// committed after it was enabled.
+//
+// ```shell
+// curl "localhost:26657/block_search?query=\"block.height>10\"&page=1&per_page=30&order_by=\"desc\""
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                                    |
+// |-----------+--------+---------+----------+------------------------------------------------|
+// | query     | string | ""      | true     | Query                                          |
+// | page      | int    | 1       | false    | Page number (1-based)                          |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100)          |
+// | order_by  | string | "asc"   | false    | Order by height ("asc" or "desc")              |
+func BlockSearch(_ *rpctypes.Context, q string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	searcher, ok := txEventStore.(eventstore.Searcher)
+	if !ok {
+		return nil, errSearchDisabled
+	}
+
+	parsed, desc, err := parseSearch(q, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	page, perPage, err = searchPage(page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	heights, more, err := searcher.SearchBlocks(parsed, desc, (page-1)*perPage, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := validatePage(page, perPage, (page-1)*perPage+len(heights)); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*ctypes.ResultBlock, 0, len(heights))
+	for _, height := range heights {
+		block := blockStore.LoadBlock(height)
+		if block == nil {
+			continue // pruned, or not yet saved
+		}
+
+		blocks = append(blocks, &ctypes.ResultBlock{
+			BlockMeta: blockStore.LoadBlockMeta(height),
+			Block:     block,
+		})
+	}
+
+	return &ctypes.ResultBlockSearch{Blocks: blocks, HasMore: more}, nil
+}
+
+func parseSearch(q, orderBy string) (*query.Query, bool, error) {
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch orderBy {
+	case "", "asc":
+		return parsed, false, nil
+	case "desc":
+		return parsed, true, nil
+	default:
+		return nil, false, fmt.Errorf("order_by should be \"asc\" or \"desc\", given %q", orderBy)
+	}
+}
+
+// searchPage returns the page and perPage to search with,
+// as the total number of results is not known beforehand.
+func searchPage(page, perPage int) (int, int, error) {
+	if page < 0 {
+		return 0, 0, fmt.Errorf("page should be positive, given %d", page)
+	}
+	if page == 0 {
+		page = 1 // default
+	}
+
+	return page, validatePerPage(perPage), nil
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchHandlers(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables,
+	// that are not friendly with concurrent test runs (or anything, really)
+	t.Run("search disabled", func(t *testing.T) {
+		SetTxEventStore(null.NewNullEventStore())
+
+		_, err := TxSearch(nil, "", 0, 0, "")
+		assert.ErrorIs(t, err, errSearchDisabled)
+
+		_, err = BlockSearch(nil, "", 0, 0, "")
+		assert.ErrorIs(t, err, errSearchDisabled)
+	})
+
+	store := kv.NewTxEventStore(memdb.NewMemDB())
+	defer store.Stop()
+
+	SetTxEventStore(store)
+	SetBlockStore(&mockBlockStore{
+		loadBlockFn: func(height int64) *types.Block {
+			return &types.Block{Header: types.Header{Height: height}}
+		},
+		loadBlockMetaFn: func(height int64) *types.BlockMeta {
+			return &types.BlockMeta{Header: types.Header{Height: height}}
+		},
+	})
+
+	txs := make([]types.Tx, 3)
+	for i := range txs {
+		txRaw, err := amino.Marshal(std.Tx{Memo: string(rune('a' + i))})
+		require.NoError(t, err)
+
+		txs[i] = txRaw
+		require.NoError(t, store.Append(types.TxResult{Height: int64(i + 1), Tx: txRaw}))
+		require.NoError(t, store.AppendBlock(types.EventNewBlock{
+			Block: &types.Block{Header: types.Header{Height: int64(i + 1)}},
+		}))
+	}
+
+	t.Run("tx search", func(t *testing.T) {
+		result, err := TxSearch(nil, "tx.height >= 2", 1, 1, "desc")
+		require.NoError(t, err)
+
+		assert.True(t, result.HasMore)
+		require.Len(t, result.Txs, 1)
+		assert.Equal(t, int64(3), result.Txs[0].Height)
+		assert.Equal(t, txs[2], result.Txs[0].Tx)
+		assert.Equal(t, txs[2].Hash(), result.Txs[0].Hash)
+	})
+
+	t.Run("block search", func(t *testing.T) {
+		result, err := BlockSearch(nil, "block.height < 3", 0, 0, "")
+		require.NoError(t, err)
+
+		assert.False(t, result.HasMore)
+		require.Len(t, result.Blocks, 2)
+		assert.Equal(t, int64(1), result.Blocks[0].Block.Height)
+		assert.Equal(t, int64(2), result.Blocks[1].BlockMeta.Header.Height)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		_, err := TxSearch(nil, "tx.height >", 0, 0, "")
+		assert.Error(t, err)
+
+		_, err = TxSearch(nil, "", 0, 0, "random")
+		assert.Error(t, err)
+
+		_, err = TxSearch(nil, "", -1, 0, "")
+		assert.Error(t, err)
+
+		_, err = BlockSearch(nil, "", 3, 1, "")
+		assert.NoError(t, err)
+
+		_, err = BlockSearch(nil, "", 4, 1, "")
+		assert.Error(t, err, "page out of range")
+	})
+}
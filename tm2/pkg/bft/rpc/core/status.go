@@ -87,6 +87,10 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 	)
 	if latestHeight != 0 {
 		latestBlockMeta = blockStore.LoadBlockMeta(latestHeight)
+	}
+	// The block at the latest height may be missing if the block store was
+	// bootstrapped by state sync.
+	if latestBlockMeta != nil {
 		latestBlockHash = latestBlockMeta.BlockID.Hash
 		latestAppHash = latestBlockMeta.Header.AppHash
 		latestBlockTimeNano = latestBlockMeta.Header.Time.UnixNano()
@@ -172,8 +172,17 @@ type ResultTx struct {
 
 // Result of searching for txs
 type ResultTxSearch struct {
-	Txs        []*ResultTx `json:"txs"`
-	TotalCount int         `json:"total_count"`
+	Txs []*ResultTx `json:"txs"`
+	// True if more matching txs follow the page. The matches are not
+	// counted, so that a search does not scan the whole index
+	HasMore bool `json:"has_more"`
+}
+
+// Result of searching for blocks
+type ResultBlockSearch struct {
+	Blocks []*ResultBlock `json:"blocks"`
+	// True if more matching blocks follow the page, like for txs
+	HasMore bool `json:"has_more"`
 }
 
 // List of mempool txs
@@ -198,10 +207,13 @@ type ResultABCIQuery struct {
 type (
 	ResultUnsafeFlushMempool struct{}
 	ResultUnsafeProfile      struct{}
+	ResultSubscribe          struct{}
+	ResultUnsubscribe        struct{}
 	ResultHealth             struct{}
 )
 
 // Event data from a subscription
 type ResultEvent struct {
+	Query string        `json:"query"`
 	Event types.TMEvent `json:"event"`
 }
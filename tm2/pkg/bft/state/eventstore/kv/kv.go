@@ -0,0 +1,364 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+)
+
+var (
+	_ eventstore.TxEventStore    = (*TxEventStore)(nil)
+	_ eventstore.BlockEventStore = (*TxEventStore)(nil)
+	_ eventstore.Searcher        = (*TxEventStore)(nil)
+)
+
+const (
+	EventStoreType = "kv"
+)
+
+// Key layout:
+//
+//	tx/<height>/<index>                         -> amino(TxResult)
+//	txattr/<key>\x00<value>\x00<height>/<index> -> tx/<height>/<index>
+//	block/<height>                              -> json(attributes)
+//	blockattr/<key>\x00<value>\x00<height>      -> block/<height>
+//
+// Heights and indexes are zero-padded, so that keys sort by height and index.
+const (
+	txPrefix        = "tx/"
+	txAttrPrefix    = "txattr/"
+	blockPrefix     = "block/"
+	blockAttrPrefix = "blockattr/"
+)
+
+// TxEventStore is the implementation of a transaction event store
+// that indexes the attributes of transactions and blocks in a database,
+// so they can be searched with event queries
+type TxEventStore struct {
+	db dbm.DB
+}
+
+// NewTxEventStore creates a new database-backed tx event store
+func NewTxEventStore(db dbm.DB) *TxEventStore {
+	return &TxEventStore{
+		db: db,
+	}
+}
+
+// Start starts the kv transaction event store (noop)
+func (t *TxEventStore) Start() error {
+	return nil
+}
+
+// Stop stops the kv transaction event store, by closing the database
+func (t *TxEventStore) Stop() error {
+	t.db.Close()
+
+	return nil
+}
+
+// GetType returns the kv transaction event store type
+func (t *TxEventStore) GetType() string {
+	return EventStoreType
+}
+
+// Append stores the transaction result, and indexes its attributes
+func (t *TxEventStore) Append(result types.TxResult) error {
+	resultRaw, err := amino.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("unable to marshal transaction, %w", err)
+	}
+
+	key := txKey(result.Height, result.Index)
+	attrs := types.TxResultAttributes(result)
+
+	batch := t.db.NewBatch()
+	defer batch.Close()
+
+	batch.Set(key, resultRaw)
+	for attrKey, values := range attrs {
+		for _, value := range values {
+			batch.Set(attrIndexKey(txAttrPrefix, attrKey, value, key[len(txPrefix):]), key)
+		}
+	}
+	batch.WriteSync()
+
+	return nil
+}
+
+// AppendBlock stores and indexes the attributes of the block
+func (t *TxEventStore) AppendBlock(block types.EventNewBlock) error {
+	if block.Block == nil {
+		return nil
+	}
+
+	attrs := types.EventAttributes(block)
+	attrsRaw, err := json.Marshal(attrs)
+	if err != nil {
+		return fmt.Errorf("unable to marshal block attributes, %w", err)
+	}
+
+	key := blockKey(block.Block.Height)
+
+	batch := t.db.NewBatch()
+	defer batch.Close()
+
+	batch.Set(key, attrsRaw)
+	for attrKey, values := range attrs {
+		for _, value := range values {
+			batch.Set(attrIndexKey(blockAttrPrefix, attrKey, value, key[len(blockPrefix):]), key)
+		}
+	}
+	batch.WriteSync()
+
+	return nil
+}
+
+// SearchTxs returns the results of the transactions matching q.
+// The search stops at the first match past the requested page,
+// so that it does not scan the whole index
+func (t *TxEventStore) SearchTxs(q *query.Query, desc bool, offset, limit int) ([]types.TxResult, bool, error) {
+	var (
+		results []types.TxResult
+		skipped int
+		more    bool
+	)
+
+	minHeight, maxHeight := heightRange(q, types.TxHeightKey)
+	if minHeight > maxHeight {
+		return nil, false, nil
+	}
+
+	err := t.search(
+		q,
+		txAttrPrefix,
+		txKey(minHeight, 0),
+		txKey(maxHeight, math.MaxUint32),
+		desc,
+		func(value []byte) (bool, error) {
+			var result types.TxResult
+			if err := amino.Unmarshal(value, &result); err != nil {
+				return false, fmt.Errorf("unable to unmarshal transaction, %w", err)
+			}
+
+			if !q.Matches(types.TxResultAttributes(result)) {
+				return true, nil
+			}
+
+			switch {
+			case skipped < offset:
+				skipped++
+			case len(results) < limit:
+				results = append(results, result)
+			default:
+				more = true
+			}
+
+			return !more, nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return results, more, nil
+}
+
+// SearchBlocks returns the heights of the blocks matching q,
+// stopping like SearchTxs
+func (t *TxEventStore) SearchBlocks(q *query.Query, desc bool, offset, limit int) ([]int64, bool, error) {
+	var (
+		heights []int64
+		skipped int
+		more    bool
+	)
+
+	minHeight, maxHeight := heightRange(q, types.BlockHeightKey)
+	if minHeight > maxHeight {
+		return nil, false, nil
+	}
+
+	err := t.search(
+		q,
+		blockAttrPrefix,
+		blockKey(minHeight),
+		blockKey(maxHeight),
+		desc,
+		func(value []byte) (bool, error) {
+			var attrs query.Attributes
+			if err := json.Unmarshal(value, &attrs); err != nil {
+				return false, fmt.Errorf("unable to unmarshal block attributes, %w", err)
+			}
+
+			if !q.Matches(attrs) {
+				return true, nil
+			}
+
+			switch {
+			case skipped < offset:
+				skipped++
+			case len(heights) < limit:
+				height, err := strconv.ParseInt(attrs[types.BlockHeightKey][0], 10, 64)
+				if err != nil {
+					return false, fmt.Errorf("invalid block height, %w", err)
+				}
+
+				heights = append(heights, height)
+			default:
+				more = true
+			}
+
+			return !more, nil
+		},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return heights, more, nil
+}
+
+// search calls matchFn with the value of each candidate for q, in order,
+// until matchFn returns false or an error. If q has an equality condition
+// on a string, the candidates are looked up in the attribute index.
+// Otherwise, all the entries in [start, end] are scanned
+func (t *TxEventStore) search(
+	q *query.Query,
+	attrPrefix string,
+	start,
+	end []byte,
+	desc bool,
+	matchFn func(value []byte) (bool, error),
+) error {
+	for _, c := range q.Conditions {
+		if c.Op != query.OpEqual || c.Number {
+			continue
+		}
+
+		// Iterate over the index entries, which reference the candidates
+		it := iteratePrefix(t.db, attrIndexKey(attrPrefix, c.Key, c.Operand, nil), desc)
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			key := it.Value()
+			if bytes.Compare(key, start) < 0 || bytes.Compare(key, end) > 0 {
+				continue
+			}
+
+			value := t.db.Get(key)
+			if value == nil {
+				continue
+			}
+
+			if next, err := matchFn(value); err != nil || !next {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// No usable index, scan the height range
+	var it dbm.Iterator
+	if desc {
+		it = t.db.ReverseIterator(start, append(end, 0))
+	} else {
+		it = t.db.Iterator(start, append(end, 0))
+	}
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		if next, err := matchFn(it.Value()); err != nil || !next {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// heightRange returns the range of heights that the conditions
+// of q on the given height attribute (or block.height) allow
+func heightRange(q *query.Query, heightKey string) (int64, int64) {
+	var (
+		minHeight int64 = 0
+		maxHeight int64 = math.MaxInt64
+	)
+
+	for _, c := range q.Conditions {
+		if !c.Number || (c.Key != heightKey && c.Key != types.BlockHeightKey) {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(c.Operand, 64)
+		if err != nil || value < 0 || value > math.MaxInt64/2 {
+			continue
+		}
+
+		// Round towards the allowed heights
+		floor, ceil := int64(math.Floor(value)), int64(math.Ceil(value))
+		switch c.Op {
+		case query.OpEqual:
+			minHeight, maxHeight = max(minHeight, ceil), min(maxHeight, floor)
+		case query.OpGreater:
+			minHeight = max(minHeight, floor+1)
+		case query.OpGreaterEqual:
+			minHeight = max(minHeight, ceil)
+		case query.OpLess:
+			maxHeight = min(maxHeight, ceil-1)
+		case query.OpLessEqual:
+			maxHeight = min(maxHeight, floor)
+		}
+	}
+
+	return minHeight, maxHeight
+}
+
+func txKey(height int64, index uint32) []byte {
+	return []byte(fmt.Sprintf("%s%020d/%010d", txPrefix, height, index))
+}
+
+func blockKey(height int64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", blockPrefix, height))
+}
+
+// attrIndexKey returns the index key of an attribute value.
+// The separators may appear in keys and values, which can only cause
+// false candidates: they are filtered out by matching the query
+func attrIndexKey(prefix, key, value string, suffix []byte) []byte {
+	indexKey := []byte(prefix + key + "\x00" + value + "\x00")
+
+	return append(indexKey, suffix...)
+}
+
+func iteratePrefix(db dbm.DB, prefix []byte, desc bool) dbm.Iterator {
+	end := prefixEnd(prefix)
+	if desc {
+		return db.ReverseIterator(prefix, end)
+	}
+
+	return db.Iterator(prefix, end)
+}
+
+// prefixEnd returns the first key after all the keys starting with prefix
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
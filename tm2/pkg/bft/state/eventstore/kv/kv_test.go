@@ -0,0 +1,290 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEvent is shaped like the events emitted by Gno realms
+type testEvent struct {
+	Type    string          `json:"type"`
+	Attrs   []testEventAttr `json:"attrs"`
+	PkgPath string          `json:"pkg_path"`
+}
+
+type testEventAttr struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+var _ = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv",
+	"kv",
+	amino.GetCallersDirname(),
+).
+	WithDependencies(abci.Package).
+	WithTypes(
+		testEvent{},
+		testEventAttr{},
+	))
+
+var (
+	alice = crypto.AddressFromPreimage([]byte("alice"))
+	bob   = crypto.AddressFromPreimage([]byte("bob"))
+)
+
+// generateTxResult generates a transaction result at the given
+// height and index, sending coins from the given address
+func generateTxResult(t *testing.T, height int64, index uint32, from crypto.Address, pkgPath string) types.TxResult {
+	t.Helper()
+
+	tx := std.Tx{
+		Msgs: []std.Msg{bank.NewMsgSend(from, bob, std.NewCoins(std.NewCoin("ugnot", 10)))},
+	}
+
+	txRaw, err := amino.Marshal(tx)
+	require.NoError(t, err)
+
+	return types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     txRaw,
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: []abci.Event{
+					testEvent{
+						Type:    "Transfer",
+						Attrs:   []testEventAttr{{"to", bob.String()}},
+						PkgPath: pkgPath,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTxEventStore_SearchTxs(t *testing.T) {
+	t.Parallel()
+
+	store := NewTxEventStore(memdb.NewMemDB())
+	defer store.Stop()
+
+	results := []types.TxResult{
+		generateTxResult(t, 1, 0, alice, "gno.land/r/demo/foo20"),
+		generateTxResult(t, 1, 1, bob, "gno.land/r/demo/bar20"),
+		generateTxResult(t, 2, 0, alice, "gno.land/r/demo/bar20"),
+		generateTxResult(t, 10, 0, alice, "gno.land/r/demo/foo20"),
+	}
+
+	for _, result := range results {
+		require.NoError(t, store.Append(result))
+	}
+
+	testTable := []struct {
+		name     string
+		query    string
+		desc     bool
+		offset   int
+		limit    int
+		expected []types.TxResult
+		more     bool
+	}{
+		{
+			"all",
+			"",
+			false,
+			0,
+			10,
+			results,
+			false,
+		},
+		{
+			"all, descending",
+			"",
+			true,
+			0,
+			10,
+			[]types.TxResult{results[3], results[2], results[1], results[0]},
+			false,
+		},
+		{
+			"paginated",
+			"",
+			false,
+			1,
+			2,
+			results[1:3],
+			true,
+		},
+		{
+			"paginated, more matches",
+			"",
+			true,
+			0,
+			1,
+			results[3:],
+			true,
+		},
+		{
+			"by signer",
+			"message.signer = '" + alice.String() + "'",
+			false,
+			0,
+			10,
+			[]types.TxResult{results[0], results[2], results[3]},
+			false,
+		},
+		{
+			"by package path and signer",
+			"Transfer.pkg_path = 'gno.land/r/demo/bar20' AND message.signer = '" + alice.String() + "'",
+			false,
+			0,
+			10,
+			[]types.TxResult{results[2]},
+			false,
+		},
+		{
+			"by package path, descending",
+			"Transfer.pkg_path = 'gno.land/r/demo/foo20'",
+			true,
+			0,
+			10,
+			[]types.TxResult{results[3], results[0]},
+			false,
+		},
+		{
+			"by message type and height range",
+			"message.type = 'send' AND tx.height > 1 AND tx.height < 10",
+			false,
+			0,
+			10,
+			[]types.TxResult{results[2]},
+			false,
+		},
+		{
+			"by height",
+			"tx.height = 1",
+			false,
+			0,
+			10,
+			results[:2],
+			false,
+		},
+		{
+			"by event attribute",
+			"Transfer.to EXISTS AND tx.height >= 2",
+			false,
+			0,
+			10,
+			results[2:],
+			false,
+		},
+		{
+			"empty height range",
+			"tx.height > 2 AND tx.height < 3",
+			false,
+			0,
+			10,
+			nil,
+			false,
+		},
+		{
+			"no match",
+			"message.type = 'exec'",
+			false,
+			0,
+			10,
+			nil,
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			found, more, err := store.SearchTxs(
+				query.MustParse(testCase.query),
+				testCase.desc,
+				testCase.offset,
+				testCase.limit,
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.more, more)
+			assert.Equal(t, testCase.expected, found)
+		})
+	}
+}
+
+func TestTxEventStore_SearchBlocks(t *testing.T) {
+	t.Parallel()
+
+	store := NewTxEventStore(memdb.NewMemDB())
+	defer store.Stop()
+
+	for height := int64(1); height <= 5; height++ {
+		block := types.EventNewBlock{
+			Block: &types.Block{Header: types.Header{Height: height}},
+		}
+
+		if height%2 == 0 {
+			block.ResultEndBlock.Events = []abci.Event{
+				testEvent{Type: "Even", PkgPath: "gno.land/r/demo/even"},
+			}
+		}
+
+		require.NoError(t, store.AppendBlock(block))
+	}
+
+	testTable := []struct {
+		name     string
+		query    string
+		desc     bool
+		offset   int
+		limit    int
+		expected []int64
+		more     bool
+	}{
+		{"all", "", false, 0, 10, []int64{1, 2, 3, 4, 5}, false},
+		{"all, descending and paginated", "", true, 1, 2, []int64{4, 3}, true},
+		{"paginated, last page", "", false, 3, 2, []int64{4, 5}, false},
+		{"by height range", "block.height >= 2 AND block.height <= 3", false, 0, 10, []int64{2, 3}, false},
+		{"by event", "event.type = 'Even'", false, 0, 10, []int64{2, 4}, false},
+		{"by event and height", "Even.pkg_path = 'gno.land/r/demo/even' AND block.height > 2", true, 0, 10, []int64{4}, false},
+		{"no match", "tm.event = 'Tx'", false, 0, 10, nil, false},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			heights, more, err := store.SearchBlocks(
+				query.MustParse(testCase.query),
+				testCase.desc,
+				testCase.offset,
+				testCase.limit,
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.more, more)
+			assert.Equal(t, testCase.expected, heights)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package eventstore
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+)
 
 const (
 	StatusOn  = "on"
@@ -22,3 +25,24 @@ type TxEventStore interface {
 	// to the event store
 	Append(result types.TxResult) error
 }
+
+// BlockEventStore is implemented by the event stores
+// which also store the events of blocks
+type BlockEventStore interface {
+	// AppendBlock analyzes and appends a single block
+	// to the event store
+	AppendBlock(block types.EventNewBlock) error
+}
+
+// Searcher is implemented by the event stores which index
+// the events they store, and can be searched with event queries
+type Searcher interface {
+	// SearchTxs returns the results of the transactions matching q,
+	// ordered by height and index, and whether more matches follow them.
+	// The first offset matches are skipped, and at most limit are returned
+	SearchTxs(q *query.Query, desc bool, offset, limit int) ([]types.TxResult, bool, error)
+
+	// SearchBlocks returns the heights of the blocks matching q,
+	// and whether more matches follow them, like SearchTxs
+	SearchBlocks(q *query.Query, desc bool, offset, limit int) ([]int64, bool, error)
+}
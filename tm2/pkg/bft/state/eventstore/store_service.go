@@ -57,27 +57,40 @@ func (is *Service) OnStop() {
 }
 
 // monitorTxEvents acts as an intermediary feed service for the supplied
-// event store. It relays transaction events that come from the event stream
+// event store. It relays transaction events that come from the event stream,
+// along with block events if the event store stores them
 func (is *Service) monitorTxEvents(ctx context.Context) {
-	// Create a subscription for transaction events
-	subCh := events.SubscribeToEvent(is.evsw, "tx-event-store", types.EventTx{})
+	blockEventStore, storesBlocks := is.txEventStore.(BlockEventStore)
+
+	// Create a subscription for transaction (and block) events
+	subCh := events.SubscribeFiltered(is.evsw, "tx-event-store", func(event events.Event) bool {
+		switch event.(type) {
+		case types.EventTx:
+			return true
+		case types.EventNewBlock:
+			return storesBlocks
+		default:
+			return false
+		}
+	})
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case evRaw := <-subCh:
-			// Cast the event
-			ev, ok := evRaw.(types.EventTx)
-			if !ok {
-				is.Logger.Error("invalid transaction result type cast")
-
-				continue
-			}
-
-			// Alert the actual tx event store
-			if err := is.txEventStore.Append(ev.Result); err != nil {
-				is.Logger.Error("unable to store transaction", "err", err)
+			switch ev := evRaw.(type) {
+			case types.EventTx:
+				// Alert the actual tx event store
+				if err := is.txEventStore.Append(ev.Result); err != nil {
+					is.Logger.Error("unable to store transaction", "err", err)
+				}
+			case types.EventNewBlock:
+				if err := blockEventStore.AppendBlock(ev); err != nil {
+					is.Logger.Error("unable to store block", "err", err)
+				}
+			default:
+				is.Logger.Error("invalid event type cast")
 			}
 		}
 	}
@@ -7,7 +7,7 @@ type EventStoreParams map[string]any
 
 // Config defines the specific event store configuration
 type Config struct {
-	EventStoreType string           `json:"event_store_type" toml:"event_store_type" comment:"Type of event store"`
+	EventStoreType string           `json:"event_store_type" toml:"event_store_type" comment:"Type of event store: none, file (append-only log) or kv (indexed, enables /tx_search and /block_search)"`
 	Params         EventStoreParams `json:"event_store_params" toml:"event_store_params" comment:"Event store parameters"`
 }
 
@@ -36,12 +36,21 @@ type BlockExecutor struct {
 	// manage the mempool lock during commit
 	// and update both with block results after commit.
 	mempool mempl.Mempool
+	evpool  EvidencePool
 
 	logger *slog.Logger
 }
 
 type BlockExecutorOption func(executor *BlockExecutor)
 
+// WithEvidencePool sets the evidence pool the BlockExecutor pulls
+// evidence from when proposing blocks, and updates after committing them.
+func WithEvidencePool(evpool EvidencePool) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.evpool = evpool
+	}
+}
+
 // NewBlockExecutor returns a new BlockExecutor with a NopEventBus.
 // Call SetEventBus to provide one.
 func NewBlockExecutor(db dbm.DB, logger *slog.Logger, proxyApp appconn.Consensus, mempool mempl.Mempool, options ...BlockExecutorOption) *BlockExecutor {
@@ -50,6 +59,7 @@ func NewBlockExecutor(db dbm.DB, logger *slog.Logger, proxyApp appconn.Consensus
 		proxyApp: proxyApp,
 		evsw:     events.NilEventSwitch(),
 		mempool:  mempool,
+		evpool:   MockEvidencePool{},
 		logger:   logger,
 	}
 
@@ -68,7 +78,8 @@ func (blockExec *BlockExecutor) SetEventSwitch(evsw events.EventSwitch) {
 	blockExec.evsw = evsw
 }
 
-// CreateProposalBlock calls state.MakeBlock with txs from the mempool.
+// CreateProposalBlock calls state.MakeBlock with evidence from the evpool
+// and txs from the mempool.
 func (blockExec *BlockExecutor) CreateProposalBlock(
 	height int64,
 	state State, commit *types.Commit,
@@ -77,16 +88,25 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 	maxDataBytes := state.ConsensusParams.Block.MaxDataBytes
 	maxGas := state.ConsensusParams.Block.MaxGas
 
+	// Fetch a limited amount of valid evidence,
+	// and leave the room it may take out of the txs.
+	maxNumEvidence, maxEvidenceBytes := types.MaxEvidencePerBlock(maxDataBytes)
+	evidence := blockExec.evpool.PendingEvidence(maxNumEvidence)
+	if len(evidence) > 0 {
+		maxDataBytes -= maxEvidenceBytes
+	}
+
 	txs := blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas)
 
-	return state.MakeBlock(height, txs, commit, proposerAddr)
+	return state.MakeBlock(height, txs, commit, evidence, proposerAddr)
 }
 
 // ValidateBlock validates the given block against the given state.
 // If the block is invalid, it returns an error.
-// Validation does not mutate state, but does require historical information from the stateDB
+// Validation does not mutate state, but does require historical information from the stateDB,
+// ie. to verify evidence from a validator at an old height.
 func (blockExec *BlockExecutor) ValidateBlock(state State, block *types.Block) error {
-	return validateBlock(blockExec.db, state, block)
+	return validateBlock(blockExec.evpool, blockExec.db, state, block)
 }
 
 // ApplyBlock validates the block against the state, executes it against the app,
@@ -153,6 +173,11 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 
 	fail.Fail() // XXX
 
+	// Update evpool with the block and state.
+	blockExec.evpool.Update(block, state)
+
+	fail.Fail() // XXX
+
 	// Events are fired after everything else.
 	// NOTE: if we crash between Commit and Save, events wont be fired during replay
 	fireEvents(blockExec.evsw, block, abciResponses)
@@ -247,6 +272,7 @@ func execBlockOnProxyApp(
 	proxyAppConn.SetResponseCallback(proxyCb)
 
 	commitInfo := getBeginBlockLastCommitInfo(block, stateDB)
+	violations := getBeginBlockViolations(block, stateDB)
 
 	// Begin block
 	var err error
@@ -254,6 +280,7 @@ func execBlockOnProxyApp(
 		Hash:           block.Hash(),
 		Header:         block.Header.Copy(),
 		LastCommitInfo: &commitInfo,
+		Violations:     violations,
 	})
 	if err != nil {
 		logger.Error("Error in proxyAppConn.BeginBlock", "err", err)
@@ -324,6 +351,41 @@ func getBeginBlockLastCommitInfo(block *types.Block, stateDB dbm.DB) abci.LastCo
 	return commitInfo
 }
 
+// getBeginBlockViolations converts the evidence included in the block into
+// violations, naming the validators who misbehaved at the height of the evidence.
+func getBeginBlockViolations(block *types.Block, stateDB dbm.DB) []abci.Violation {
+	if len(block.Evidence.Evidence) == 0 {
+		return nil
+	}
+
+	violations := make([]abci.Violation, len(block.Evidence.Evidence))
+	for i, ev := range block.Evidence.Evidence {
+		// We need the validator set. We already did this in validateBlock.
+		valset, err := LoadValidators(stateDB, ev.Height())
+		if err != nil {
+			panic(err) // shouldn't happen
+		}
+
+		var validators []abci.Validator
+		if _, val := valset.GetByAddress(ev.Address()); val != nil {
+			validators = []abci.Validator{{
+				Address: val.Address,
+				PubKey:  val.PubKey,
+				Power:   val.VotingPower,
+			}}
+		}
+
+		violations[i] = abci.Violation{
+			Evidence:         ev,
+			Validators:       validators,
+			Height:           ev.Height(),
+			Time:             block.Time,
+			TotalVotingPower: valset.TotalVotingPower(),
+		}
+	}
+	return violations
+}
+
 func validateValidatorUpdates(abciUpdates []abci.ValidatorUpdate,
 	params abci.ValidatorParams,
 ) error {
@@ -89,7 +89,7 @@ func TestBeginBlockValidators(t *testing.T) {
 		lastCommit := types.NewCommit(prevBlockID, tc.lastCommitPrecommits)
 
 		// block for height 2
-		block, _ := state.MakeBlock(2, makeTxs(2), lastCommit, state.Validators.GetProposer().Address)
+		block, _ := state.MakeBlock(2, makeTxs(2), lastCommit, nil, state.Validators.GetProposer().Address)
 
 		_, err = sm.ExecCommitBlock(proxyApp.Consensus(), block, log.NewTestingLogger(t), stateDB)
 		require.Nil(t, err, tc.desc)
@@ -108,6 +108,71 @@ func TestBeginBlockValidators(t *testing.T) {
 	}
 }
 
+// TestBeginBlockViolations ensures we send byzantine validators list.
+func TestBeginBlockViolations(t *testing.T) {
+	t.Parallel()
+
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := appconn.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	state, stateDB, _ := makeState(2, 12)
+
+	prevHash := state.LastBlockID.Hash
+	prevParts := types.PartSetHeader{}
+	prevBlockID := types.BlockID{Hash: prevHash, PartsHeader: prevParts}
+
+	val1, val2 := state.Validators.Validators[0], state.Validators.Validators[1]
+	ev1 := types.NewMockGoodEvidence(8, 0, val1.Address)
+	ev2 := types.NewMockGoodEvidence(3, 1, val2.Address)
+
+	now := tmtime.Now()
+	totalPower := state.Validators.TotalVotingPower()
+	violation := func(ev types.Evidence, val *types.Validator) abci.Violation {
+		return abci.Violation{
+			Evidence: ev,
+			Validators: []abci.Validator{{
+				Address: val.Address,
+				PubKey:  val.PubKey,
+				Power:   val.VotingPower,
+			}},
+			Height:           ev.Height(),
+			Time:             now,
+			TotalVotingPower: totalPower,
+		}
+	}
+
+	testCases := []struct {
+		desc               string
+		evidence           []types.Evidence
+		expectedViolations []abci.Violation
+	}{
+		{"none byzantine", []types.Evidence{}, nil},
+		{"one byzantine", []types.Evidence{ev1}, []abci.Violation{violation(ev1, val1)}},
+		{"multiple byzantine", []types.Evidence{ev1, ev2}, []abci.Violation{
+			violation(ev1, val1),
+			violation(ev2, val2),
+		}},
+	}
+
+	commitSig0 := (&types.Vote{ValidatorIndex: 0, Timestamp: now, Type: types.PrecommitType}).CommitSig()
+	commitSig1 := (&types.Vote{ValidatorIndex: 1, Timestamp: now}).CommitSig()
+	lastCommit := types.NewCommit(prevBlockID, []*types.CommitSig{commitSig0, commitSig1})
+	for _, tc := range testCases {
+		block, _ := state.MakeBlock(10, makeTxs(2), lastCommit, tc.evidence, state.Validators.GetProposer().Address)
+		block.Time = now
+
+		_, err = sm.ExecCommitBlock(proxyApp.Consensus(), block, log.NewTestingLogger(t), stateDB)
+		require.Nil(t, err, tc.desc)
+
+		// -> app must receive an index of the byzantine validators
+		assert.Equal(t, tc.expectedViolations, app.Violations, tc.desc)
+	}
+}
+
 func TestValidateValidatorUpdates(t *testing.T) {
 	t.Parallel()
 
@@ -29,3 +29,24 @@ type BlockStore interface {
 	BlockStoreRPC
 	SaveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit)
 }
+
+//------------------------------------------------------
+// evidence pool
+
+// EvidencePool defines the EvidencePool interface used by the ConsensusState
+// and the BlockExecutor.
+type EvidencePool interface {
+	PendingEvidence(maxNum int64) []types.Evidence
+	AddEvidence(types.Evidence) error
+	Update(*types.Block, State)
+	// IsCommitted indicates if this evidence was already marked committed in another block.
+	IsCommitted(types.Evidence) bool
+}
+
+// MockEvidencePool is an empty implementation of an EvidencePool, useful for testing.
+type MockEvidencePool struct{}
+
+func (m MockEvidencePool) PendingEvidence(int64) []types.Evidence { return nil }
+func (m MockEvidencePool) AddEvidence(types.Evidence) error       { return nil }
+func (m MockEvidencePool) Update(*types.Block, State)             {}
+func (m MockEvidencePool) IsCommitted(types.Evidence) bool        { return false }
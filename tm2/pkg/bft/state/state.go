@@ -114,17 +114,18 @@ func (state State) IsEmpty() bool {
 // ------------------------------------------------------------------------
 // Create a block from the latest state
 
-// MakeBlock builds a block from the current state with the given txs and commit.
+// MakeBlock builds a block from the current state with the given txs, commit, and evidence.
 // Note it also takes a proposerAddress because the state does not
 // track rounds, and hence does not know the correct proposer. TODO: fix this!
 func (state State) MakeBlock(
 	height int64,
 	txs []types.Tx,
 	commit *types.Commit,
+	evidence []types.Evidence,
 	proposerAddress crypto.Address,
 ) (*types.Block, *types.PartSet) {
 	// Build base block with block data.
-	block := types.MakeBlock(height, txs, commit)
+	block := types.MakeBlock(height, txs, commit, evidence)
 
 	// Set time.
 	var timestamp time.Time
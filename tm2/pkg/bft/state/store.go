@@ -102,6 +102,35 @@ func SaveState(db dbm.DB, state State) {
 	saveState(db, state, stateKey)
 }
 
+// BootstrapState saves a state restored by state sync into a state database
+// without any block, along with the validator sets and consensus params needed to
+// validate the next blocks. The validator sets and consensus params must be
+// marked as changed at the heights they apply to, since the previous ones
+// are unknown.
+func BootstrapState(db dbm.DB, state State) error {
+	height := state.LastBlockHeight
+	switch {
+	case height <= 0:
+		return fmt.Errorf("cannot bootstrap the state at height %d", height)
+	case state.LastValidators == nil || state.Validators == nil || state.NextValidators == nil:
+		return errors.New("cannot bootstrap the state without validator sets")
+	case state.LastHeightValidatorsChanged != height+2:
+		return fmt.Errorf("validators must be changed at height %d, got %d",
+			height+2, state.LastHeightValidatorsChanged)
+	case state.LastHeightConsensusParamsChanged != height+1:
+		return fmt.Errorf("consensus params must be changed at height %d, got %d",
+			height+1, state.LastHeightConsensusParamsChanged)
+	}
+	if loaded := LoadState(db); loaded.LastBlockHeight != 0 {
+		return fmt.Errorf("cannot bootstrap a state already at height %d", loaded.LastBlockHeight)
+	}
+
+	saveValidatorsInfo(db, height, height, state.LastValidators)
+	saveValidatorsInfo(db, height+1, height+1, state.Validators)
+	saveState(db, state, stateKey)
+	return nil
+}
+
 func saveState(db dbm.DB, state State, key []byte) {
 	nextHeight := state.LastBlockHeight + 1
 	// If first block, save validators for block 1.
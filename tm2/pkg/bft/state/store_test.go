@@ -57,6 +57,63 @@ func TestStoreLoadValidators(t *testing.T) {
 	assert.NotZero(t, loadedVals.Size())
 }
 
+func TestBootstrapState(t *testing.T) {
+	t.Parallel()
+
+	genesis, _, _ := makeState(1, 1)
+	state := genesis.Copy()
+	state.LastBlockHeight = 100
+	state.LastValidators = genValSet(2)
+	state.Validators = genValSet(3)
+	state.NextValidators = genValSet(4)
+	state.LastHeightValidatorsChanged = 102
+	state.LastHeightConsensusParamsChanged = 101
+	state.AppHash = []byte("app_hash")
+
+	t.Run("invalid state", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := state.Copy()
+		invalid.LastHeightValidatorsChanged = 1
+		assert.Error(t, sm.BootstrapState(memdb.NewMemDB(), invalid))
+
+		invalid = state.Copy()
+		invalid.LastHeightConsensusParamsChanged = 1
+		assert.Error(t, sm.BootstrapState(memdb.NewMemDB(), invalid))
+
+		invalid = state.Copy()
+		invalid.LastBlockHeight = 0
+		assert.Error(t, sm.BootstrapState(memdb.NewMemDB(), invalid))
+	})
+
+	t.Run("bootstrap", func(t *testing.T) {
+		t.Parallel()
+
+		// The genesis state is saved before state sync starts.
+		stateDB := memdb.NewMemDB()
+		sm.SaveState(stateDB, genesis)
+		require.NoError(t, sm.BootstrapState(stateDB, state))
+
+		loaded := sm.LoadState(stateDB)
+		assert.Equal(t, state.Bytes(), loaded.Bytes())
+		for height, vals := range map[int64]*types.ValidatorSet{
+			100: state.LastValidators,
+			101: state.Validators,
+			102: state.NextValidators,
+		} {
+			loadedVals, err := sm.LoadValidators(stateDB, height)
+			require.NoError(t, err)
+			assert.Equal(t, vals.Hash(), loadedVals.Hash(), "height %d", height)
+		}
+		params, err := sm.LoadConsensusParams(stateDB, 101)
+		require.NoError(t, err)
+		assert.Equal(t, state.ConsensusParams, params)
+
+		// The state can only be bootstrapped once.
+		assert.Error(t, sm.BootstrapState(stateDB, state))
+	})
+}
+
 func BenchmarkLoadValidators(b *testing.B) {
 	const valSetSize = 100
 
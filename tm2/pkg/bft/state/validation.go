@@ -13,7 +13,7 @@ import (
 // -----------------------------------------------------
 // Validate block
 
-func validateBlock(stateDB dbm.DB, state State, block *types.Block) error {
+func validateBlock(evidencePool EvidencePool, stateDB dbm.DB, state State, block *types.Block) error {
 	// Validate internal consistency.
 	if err := block.ValidateBasic(); err != nil {
 		return err
@@ -135,6 +135,29 @@ func validateBlock(stateDB dbm.DB, state State, block *types.Block) error {
 		}
 	}
 
+	// Limit the amount of evidence
+	maxNumEvidence, _ := types.MaxEvidencePerBlock(state.ConsensusParams.Block.MaxDataBytes)
+	numEvidence := int64(len(block.Evidence.Evidence))
+	if numEvidence > maxNumEvidence {
+		return types.NewErrEvidenceOverflow(maxNumEvidence, numEvidence)
+	}
+
+	// Validate all evidence.
+	seen := make(map[string]struct{}, numEvidence)
+	for _, ev := range block.Evidence.Evidence {
+		if _, ok := seen[string(ev.Hash())]; ok {
+			return types.NewErrEvidenceInvalid(ev, errors.New("evidence is included twice"))
+		}
+		seen[string(ev.Hash())] = struct{}{}
+
+		if err := VerifyEvidence(stateDB, state, ev); err != nil {
+			return types.NewErrEvidenceInvalid(ev, err)
+		}
+		if evidencePool != nil && evidencePool.IsCommitted(ev) {
+			return types.NewErrEvidenceInvalid(ev, errors.New("evidence was already committed"))
+		}
+	}
+
 	// NOTE: We can't actually verify it's the right proposer because we dont
 	// know what round the block was first proposed. So just check that it's
 	// a legit address and a known validator.
@@ -148,7 +171,6 @@ func validateBlock(stateDB dbm.DB, state State, block *types.Block) error {
 	return nil
 }
 
-/*
 // VerifyEvidence verifies the evidence fully by checking:
 // - it is sufficiently recent (MaxAge)
 // - it is from a key who was a validator at the given height
@@ -158,7 +180,11 @@ func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error
 	height := state.LastBlockHeight
 
 	evidenceAge := height - evidence.Height()
-	maxAge := state.ConsensusParams.Evidence.MaxAge
+	evParams := state.ConsensusParams.Evidence
+	if evParams == nil {
+		evParams = types.DefaultEvidenceParams()
+	}
+	maxAge := evParams.MaxAge
 	if evidenceAge > maxAge {
 		return fmt.Errorf("Evidence from height %d is too old. Min height is %d",
 			evidence.Height(), height-maxAge)
@@ -189,4 +215,3 @@ func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error
 
 	return nil
 }
-*/
@@ -66,7 +66,7 @@ func TestValidateBlockHeader(t *testing.T) {
 			Invalid blocks don't pass
 		*/
 		for _, tc := range testCases {
-			block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, proposerAddr)
+			block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, nil, proposerAddr)
 			tc.malleateBlock(block)
 			err := blockExec.ValidateBlock(state, block)
 			require.Error(t, err, tc.name)
@@ -76,7 +76,7 @@ func TestValidateBlockHeader(t *testing.T) {
 			A good block passes
 		*/
 		var err error
-		state, _, lastCommit, err = makeAndCommitGoodBlock(state, height, lastCommit, proposerAddr, blockExec, privVals)
+		state, _, lastCommit, err = makeAndCommitGoodBlock(state, height, lastCommit, proposerAddr, blockExec, privVals, nil)
 		require.NoError(t, err, "height %d", height)
 	}
 }
@@ -104,7 +104,7 @@ func TestValidateBlockCommit(t *testing.T) {
 			wrongHeightVote, err := types.MakeVote(height, state.LastBlockID, state.Validators, privVals[proposerAddr.String()], chainID)
 			require.NoError(t, err, "height %d", height)
 			wrongHeightCommit := types.NewCommit(state.LastBlockID, []*types.CommitSig{wrongHeightVote.CommitSig()})
-			block, _ := state.MakeBlock(height, makeTxs(height), wrongHeightCommit, proposerAddr)
+			block, _ := state.MakeBlock(height, makeTxs(height), wrongHeightCommit, nil, proposerAddr)
 			err = blockExec.ValidateBlock(state, block)
 			_, isErrInvalidCommitHeight := err.(types.InvalidCommitHeightError)
 			require.True(t, isErrInvalidCommitHeight, "expected InvalidCommitHeightError at height %d but got: %v", height, err)
@@ -112,7 +112,7 @@ func TestValidateBlockCommit(t *testing.T) {
 			/*
 				#2589: test len(block.LastCommit.Precommits) == state.LastValidators.Size()
 			*/
-			block, _ = state.MakeBlock(height, makeTxs(height), wrongPrecommitsCommit, proposerAddr)
+			block, _ = state.MakeBlock(height, makeTxs(height), wrongPrecommitsCommit, nil, proposerAddr)
 			err = blockExec.ValidateBlock(state, block)
 			_, isErrInvalidCommitPrecommits := err.(types.InvalidCommitPrecommitsError)
 			require.True(t, isErrInvalidCommitPrecommits, "expected InvalidCommitPrecommitsError at height %d but got: %v", height, err)
@@ -123,7 +123,7 @@ func TestValidateBlockCommit(t *testing.T) {
 		*/
 		var err error
 		var blockID types.BlockID
-		state, blockID, lastCommit, err = makeAndCommitGoodBlock(state, height, lastCommit, proposerAddr, blockExec, privVals)
+		state, blockID, lastCommit, err = makeAndCommitGoodBlock(state, height, lastCommit, proposerAddr, blockExec, privVals, nil)
 		require.NoError(t, err, "height %d", height)
 
 		/*
@@ -145,3 +145,66 @@ func TestValidateBlockCommit(t *testing.T) {
 		wrongPrecommitsCommit = types.NewCommit(blockID, []*types.CommitSig{goodVote.CommitSig(), badVote.CommitSig()})
 	}
 }
+
+func TestValidateBlockEvidence(t *testing.T) {
+	t.Parallel()
+
+	proxyApp := newTestApp()
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop()
+
+	state, stateDB, privVals := makeState(3, 1)
+	blockExec := sm.NewBlockExecutor(stateDB, log.NewTestingLogger(t), proxyApp.Consensus(), mock.Mempool{})
+	lastCommit := types.NewCommit(types.BlockID{}, nil)
+
+	for height := int64(1); height < validationTestsStopHeight; height++ {
+		proposerAddr := state.Validators.GetProposer().Address
+		maxNumEvidence, _ := types.MaxEvidencePerBlock(state.ConsensusParams.Block.MaxDataBytes)
+		require.True(t, maxNumEvidence > 2)
+
+		makeEvidence := func(n int64) []types.Evidence {
+			evidence := make([]types.Evidence, 0, n)
+			for i := int64(0); i < n; i++ {
+				evidence = append(evidence, types.NewMockRandomGoodEvidence(height, proposerAddr, []byte{byte(i), byte(i >> 8)}))
+			}
+			return evidence
+		}
+
+		if height > 1 {
+			/*
+				A block with too much evidence fails
+			*/
+			// one more than the maximum allowed evidence
+			block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, makeEvidence(maxNumEvidence+1), proposerAddr)
+			err := blockExec.ValidateBlock(state, block)
+			_, ok := err.(*types.EvidenceOverflowError)
+			require.True(t, ok, "expected EvidenceOverflowError at height %d but got: %v", height, err)
+
+			/*
+				A block with the same evidence twice fails
+			*/
+			evidence := makeEvidence(1)
+			block, _ = state.MakeBlock(height, makeTxs(height), lastCommit, append(evidence, evidence[0]), proposerAddr)
+			err = blockExec.ValidateBlock(state, block)
+			_, ok = err.(*types.EvidenceInvalidError)
+			require.True(t, ok, "expected EvidenceInvalidError at height %d but got: %v", height, err)
+
+			/*
+				A block with evidence which doesn't verify fails
+			*/
+			bad := types.MockBadEvidence{MockGoodEvidence: types.NewMockGoodEvidence(height, 0, proposerAddr)}
+			block, _ = state.MakeBlock(height, makeTxs(height), lastCommit, []types.Evidence{bad}, proposerAddr)
+			err = blockExec.ValidateBlock(state, block)
+			_, ok = err.(*types.EvidenceInvalidError)
+			require.True(t, ok, "expected EvidenceInvalidError at height %d but got: %v", height, err)
+		}
+
+		/*
+			A good block with several pieces of good evidence passes
+		*/
+		// precisely the amount of allowed evidence
+		var err error
+		state, _, lastCommit, err = makeAndCommitGoodBlock(state, height, lastCommit, proposerAddr, blockExec, privVals, makeEvidence(maxNumEvidence))
+		require.NoError(t, err, "height %d", height)
+	}
+}
@@ -0,0 +1,203 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+// errDone is returned by chunkQueue when all the chunks have been handled, or
+// when the queue is closed.
+var errDone = errors.New("chunk queue is done")
+
+// chunk is a chunk of a snapshot, received from a peer.
+type chunk struct {
+	Height int64
+	Format uint32
+	Index  uint32
+	Chunk  []byte
+	Sender p2p.ID
+}
+
+// chunkQueue keeps track of the chunks of a snapshot being fetched. Chunk
+// indexes are allocated to fetchers, and the received chunks are kept in
+// memory until they are applied.
+type chunkQueue struct {
+	mtx       sync.Mutex
+	snapshot  *snapshot
+	chunks    map[uint32]*chunk        // received chunks, not applied yet
+	allocated map[uint32]chan struct{} // allocated indexes; closed when the chunk is received
+	next      uint32                   // index of the next chunk to apply
+	added     chan struct{}            // receives a value when a chunk is added
+	closed    bool
+}
+
+func newChunkQueue(s *snapshot) *chunkQueue {
+	return &chunkQueue{
+		snapshot:  s,
+		chunks:    make(map[uint32]*chunk),
+		allocated: make(map[uint32]chan struct{}),
+		added:     make(chan struct{}, 1),
+	}
+}
+
+// Allocate allocates the next chunk index to fetch, and returns a channel
+// which is closed once the chunk is received. It returns errDone once all the
+// chunks have been applied, and a nil channel if no index is available, as
+// the window chunks following the next chunk to apply are already allocated.
+func (q *chunkQueue) Allocate(window uint32) (index uint32, received <-chan struct{}, err error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.closed || q.next >= q.snapshot.Chunks {
+		return 0, nil, errDone
+	}
+	for i := q.next; i < q.snapshot.Chunks && i < q.next+window; i++ {
+		if _, ok := q.allocated[i]; ok {
+			continue
+		}
+		ch := make(chan struct{})
+		q.allocated[i] = ch
+		return i, ch, nil
+	}
+	return 0, nil, nil
+}
+
+// Deallocate releases a chunk index which wasn't received, so that it is
+// allocated again.
+func (q *chunkQueue) Deallocate(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if _, ok := q.chunks[index]; !ok {
+		delete(q.allocated, index)
+	}
+}
+
+// IsAllocated returns true if the chunk index is allocated.
+func (q *chunkQueue) IsAllocated(index uint32) bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	_, ok := q.allocated[index]
+	return ok
+}
+
+// Add adds a received chunk. It returns false if the chunk was already
+// received or applied.
+func (q *chunkQueue) Add(c *chunk) (bool, error) {
+	if c.Height != q.snapshot.Height || c.Format != q.snapshot.Format {
+		return false, fmt.Errorf("chunk %v/%v doesn't belong to snapshot %v/%v",
+			c.Height, c.Format, q.snapshot.Height, q.snapshot.Format)
+	}
+	if c.Index >= q.snapshot.Chunks {
+		return false, fmt.Errorf("chunk index %d out of range (%d chunks)", c.Index, q.snapshot.Chunks)
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.closed || c.Index < q.next {
+		return false, nil
+	}
+	if _, ok := q.chunks[c.Index]; ok {
+		return false, nil
+	}
+	q.chunks[c.Index] = c
+	if ch, ok := q.allocated[c.Index]; ok {
+		close(ch)
+	}
+	q.allocated[c.Index] = closedChan
+	select {
+	case q.added <- struct{}{}:
+	default:
+	}
+	return true, nil
+}
+
+// Next returns the next chunk to apply, waiting up to timeout for it to be
+// received. It returns errDone once all the chunks have been applied.
+func (q *chunkQueue) Next(timeout time.Duration) (*chunk, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		q.mtx.Lock()
+		if q.closed || q.next >= q.snapshot.Chunks {
+			q.mtx.Unlock()
+			return nil, errDone
+		}
+		c, ok := q.chunks[q.next]
+		q.mtx.Unlock()
+		if ok {
+			return c, nil
+		}
+
+		select {
+		case <-q.added:
+		case <-timer.C:
+			return nil, errTimeout
+		}
+	}
+}
+
+// Applied marks the next chunk as applied.
+func (q *chunkQueue) Applied(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if index != q.next {
+		return
+	}
+	delete(q.chunks, index)
+	delete(q.allocated, index)
+	q.next++
+}
+
+// Discard discards a chunk which wasn't applied yet, so that it is fetched
+// again.
+func (q *chunkQueue) Discard(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.discard(index)
+}
+
+// DiscardSender discards all the chunks received from a peer which weren't
+// applied yet.
+func (q *chunkQueue) DiscardSender(id p2p.ID) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	for index, c := range q.chunks {
+		if c.Sender == id {
+			q.discard(index)
+		}
+	}
+}
+
+func (q *chunkQueue) discard(index uint32) {
+	if index < q.next {
+		return
+	}
+	delete(q.chunks, index)
+	delete(q.allocated, index)
+}
+
+// Close closes the queue, which stops its fetchers.
+func (q *chunkQueue) Close() {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.closed = true
+	q.chunks = nil
+}
+
+// closedChan is the channel of the received chunks.
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
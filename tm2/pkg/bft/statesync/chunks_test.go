@@ -0,0 +1,98 @@
+package statesync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+func TestChunkQueue(t *testing.T) {
+	t.Parallel()
+
+	s := &snapshot{Height: 3, Format: 1, Chunks: 3, Hash: []byte{1}}
+	q := newChunkQueue(s)
+
+	// Indexes are allocated within the window.
+	index, received, err := q.Allocate(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), index)
+	index, _, err = q.Allocate(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), index)
+	_, received2, err := q.Allocate(2)
+	require.NoError(t, err)
+	assert.Nil(t, received2, "window is full")
+
+	// Chunks of other snapshots are rejected.
+	_, err = q.Add(&chunk{Height: 2, Format: 1, Index: 0, Chunk: []byte{0}})
+	assert.Error(t, err)
+	_, err = q.Add(&chunk{Height: 3, Format: 1, Index: 3, Chunk: []byte{0}})
+	assert.Error(t, err)
+
+	// Chunks are returned in order.
+	added, err := q.Add(&chunk{Height: 3, Format: 1, Index: 1, Chunk: []byte{1}, Sender: "a"})
+	require.NoError(t, err)
+	assert.True(t, added)
+	_, err = q.Next(10 * time.Millisecond)
+	assert.ErrorIs(t, err, errTimeout)
+
+	added, err = q.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{0}, Sender: "b"})
+	require.NoError(t, err)
+	assert.True(t, added)
+	<-received
+	added, err = q.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{0}, Sender: "b"})
+	require.NoError(t, err)
+	assert.False(t, added, "chunk was already received")
+
+	c, err := q.Next(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), c.Index)
+	q.Applied(0)
+
+	// Discarded chunks are allocated again.
+	q.DiscardSender(p2p.ID("a"))
+	assert.False(t, q.IsAllocated(1))
+	index, _, err = q.Allocate(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), index)
+	index, _, err = q.Allocate(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), index)
+
+	// Deallocated chunks too.
+	q.Deallocate(2)
+	assert.False(t, q.IsAllocated(2))
+
+	for i := uint32(1); i < 3; i++ {
+		_, err = q.Add(&chunk{Height: 3, Format: 1, Index: i, Chunk: []byte{byte(i)}})
+		require.NoError(t, err)
+		c, err = q.Next(time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, i, c.Index)
+		q.Applied(i)
+	}
+
+	_, err = q.Next(time.Second)
+	assert.ErrorIs(t, err, errDone)
+	_, _, err = q.Allocate(2)
+	assert.ErrorIs(t, err, errDone)
+}
+
+func TestChunkQueue_Close(t *testing.T) {
+	t.Parallel()
+
+	q := newChunkQueue(&snapshot{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1}})
+	q.Close()
+
+	_, _, err := q.Allocate(2)
+	assert.ErrorIs(t, err, errDone)
+	_, err = q.Next(time.Second)
+	assert.ErrorIs(t, err, errDone)
+	added, err := q.Add(&chunk{Height: 1, Format: 1, Index: 0, Chunk: []byte{0}})
+	require.NoError(t, err)
+	assert.False(t, added)
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// StateSyncConfig
+
+// StateSyncConfig defines the configuration for state sync, which bootstraps
+// a new node from a snapshot of the application state served by its peers,
+// instead of replaying all the blocks since genesis.
+type StateSyncConfig struct {
+	Enable              bool          `json:"enable" toml:"enable" comment:"Bootstrap a new node from a snapshot of the app state served by its peers.\n The snapshot is verified against the trusted header below, and the headers\n fetched from the RPC servers. Only applies to nodes without any block."`
+	RPCServers          string        `json:"rpc_servers" toml:"rpc_servers" comment:"Comma separated list of at least two RPC servers, used to fetch and cross-check\n the headers and validators needed to verify snapshots"`
+	TrustHeight         int64         `json:"trust_height" toml:"trust_height" comment:"Height and hash of a trusted header, obtained from a trusted source"`
+	TrustHash           string        `json:"trust_hash" toml:"trust_hash"`
+	DiscoveryTime       time.Duration `json:"discovery_time" toml:"discovery_time" comment:"Time spent discovering snapshots before offering them to the app"`
+	ChunkRequestTimeout time.Duration `json:"chunk_request_timeout" toml:"chunk_request_timeout" comment:"Timeout of a chunk request, before retrying it with another peer"`
+	ChunkFetchers       int           `json:"chunk_fetchers" toml:"chunk_fetchers" comment:"Number of concurrent chunk requests"`
+	SnapshotInterval    int64         `json:"snapshot_interval" toml:"snapshot_interval" comment:"Create a snapshot of the app state every snapshot_interval blocks,\n to serve it to state syncing nodes. 0 disables snapshots"`
+	SnapshotKeepRecent  int           `json:"snapshot_keep_recent" toml:"snapshot_keep_recent" comment:"Number of recent snapshots to keep. 0 keeps all of them"`
+}
+
+// DefaultStateSyncConfig returns a default configuration for state sync
+func DefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Enable:              false,
+		DiscoveryTime:       15 * time.Second,
+		ChunkRequestTimeout: 10 * time.Second,
+		ChunkFetchers:       4,
+		SnapshotInterval:    0,
+		SnapshotKeepRecent:  2,
+	}
+}
+
+// TestStateSyncConfig returns a configuration for testing state sync
+func TestStateSyncConfig() *StateSyncConfig {
+	cfg := DefaultStateSyncConfig()
+	cfg.DiscoveryTime = 100 * time.Millisecond
+	cfg.ChunkRequestTimeout = time.Second
+	return cfg
+}
+
+// RPCServerList returns the list of RPC servers.
+func (cfg *StateSyncConfig) RPCServerList() []string {
+	var servers []string
+	for _, server := range strings.Split(cfg.RPCServers, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *StateSyncConfig) ValidateBasic() error {
+	if cfg.DiscoveryTime < 0 {
+		return errors.New("discovery_time can't be negative")
+	}
+	if cfg.ChunkRequestTimeout < 0 {
+		return errors.New("chunk_request_timeout can't be negative")
+	}
+	if cfg.ChunkFetchers < 0 {
+		return errors.New("chunk_fetchers can't be negative")
+	}
+	if cfg.SnapshotInterval < 0 {
+		return errors.New("snapshot_interval can't be negative")
+	}
+	if cfg.SnapshotKeepRecent < 0 {
+		return errors.New("snapshot_keep_recent can't be negative")
+	}
+	if !cfg.Enable {
+		return nil
+	}
+
+	if len(cfg.RPCServerList()) < 2 {
+		return errors.New("at least two rpc_servers are required")
+	}
+	if cfg.TrustHeight <= 0 {
+		return errors.New("trust_height is required")
+	}
+	if _, err := hex.DecodeString(cfg.TrustHash); err != nil || cfg.TrustHash == "" {
+		return errors.New("trust_hash must be a hex-encoded hash")
+	}
+	if cfg.ChunkFetchers == 0 {
+		return errors.New("chunk_fetchers must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+const (
+	// snapshotMsgSize is the maximum size of a snapshot message.
+	snapshotMsgSize = 4 << 20 // 4MB
+	// chunkMsgSize is the maximum size of a chunk message, which must be
+	// greater than the chunk size of the application's snapshots.
+	chunkMsgSize = 16 << 20 // 16MB
+)
+
+// StateSyncMessage is a message sent or received by the Reactor.
+type StateSyncMessage interface {
+	ValidateBasic() error
+}
+
+func decodeMsg(bz []byte, maxSize int) (msg StateSyncMessage, err error) {
+	if len(bz) > maxSize {
+		return msg, fmt.Errorf("msg exceeds max size (%d > %d)", len(bz), maxSize)
+	}
+	err = amino.Unmarshal(bz, &msg)
+	return
+}
+
+// -------------------------------------
+
+// SnapshotsRequestMessage requests the recent snapshots of a peer.
+type SnapshotsRequestMessage struct{}
+
+// ValidateBasic performs basic validation.
+func (m *SnapshotsRequestMessage) ValidateBasic() error {
+	return nil
+}
+
+// String returns a string representation of the SnapshotsRequestMessage.
+func (m *SnapshotsRequestMessage) String() string {
+	return "[SnapshotsRequestMessage]"
+}
+
+// SnapshotsResponseMessage advertises a snapshot, in response to a
+// SnapshotsRequestMessage. A peer sends one message per snapshot.
+type SnapshotsResponseMessage struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+}
+
+// ValidateBasic performs basic validation.
+func (m *SnapshotsResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("height must be positive")
+	}
+	if m.Chunks == 0 {
+		return errors.New("snapshot has no chunks")
+	}
+	if len(m.Hash) == 0 {
+		return errors.New("snapshot has no hash")
+	}
+	return nil
+}
+
+// String returns a string representation of the SnapshotsResponseMessage.
+func (m *SnapshotsResponseMessage) String() string {
+	return fmt.Sprintf("[SnapshotsResponseMessage %v/%v %v chunks %X]", m.Height, m.Format, m.Chunks, m.Hash)
+}
+
+// ChunkRequestMessage requests a chunk of a snapshot.
+type ChunkRequestMessage struct {
+	Height int64
+	Format uint32
+	Index  uint32
+}
+
+// ValidateBasic performs basic validation.
+func (m *ChunkRequestMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("height must be positive")
+	}
+	return nil
+}
+
+// String returns a string representation of the ChunkRequestMessage.
+func (m *ChunkRequestMessage) String() string {
+	return fmt.Sprintf("[ChunkRequestMessage %v/%v #%v]", m.Height, m.Format, m.Index)
+}
+
+// ChunkResponseMessage contains a chunk of a snapshot, in response to a
+// ChunkRequestMessage. Missing is set if the peer doesn't have the chunk.
+type ChunkResponseMessage struct {
+	Height  int64
+	Format  uint32
+	Index   uint32
+	Chunk   []byte
+	Missing bool
+}
+
+// ValidateBasic performs basic validation.
+func (m *ChunkResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("height must be positive")
+	}
+	if m.Missing && len(m.Chunk) > 0 {
+		return errors.New("missing chunk cannot have contents")
+	}
+	if !m.Missing && len(m.Chunk) == 0 {
+		return errors.New("chunk cannot be empty")
+	}
+	return nil
+}
+
+// String returns a string representation of the ChunkResponseMessage.
+func (m *ChunkResponseMessage) String() string {
+	return fmt.Sprintf("[ChunkResponseMessage %v/%v #%v missing:%v]", m.Height, m.Format, m.Index, m.Missing)
+}
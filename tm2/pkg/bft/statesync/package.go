@@ -0,0 +1,16 @@
+package statesync
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync",
+	"tm",
+	amino.GetCallersDirname(),
+).WithDependencies().WithTypes(
+	&SnapshotsRequestMessage{},
+	&SnapshotsResponseMessage{},
+	&ChunkRequestMessage{},
+	&ChunkResponseMessage{},
+))
@@ -0,0 +1,258 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+const (
+	// SnapshotChannel exchanges snapshot metadata
+	SnapshotChannel = byte(0x60)
+	// ChunkChannel exchanges chunk contents
+	ChunkChannel = byte(0x61)
+
+	// recentSnapshots is the number of recent snapshots to advertise to peers.
+	recentSnapshots = 10
+)
+
+// Reactor handles state sync, both restoring the local app from the
+// snapshots of peers, and serving the snapshots of the local app to peers.
+type Reactor struct {
+	p2p.BaseReactor
+
+	cfg       *config.StateSyncConfig
+	conn      appconn.Snapshot
+	connQuery appconn.Query
+
+	// This will only be set while a state sync is in progress. It is used to
+	// feed the syncer with the snapshots and chunks received from peers.
+	mtx    sync.RWMutex
+	syncer *syncer
+}
+
+// NewReactor returns a new state sync reactor, serving the snapshots of the
+// app through the given connections.
+func NewReactor(cfg *config.StateSyncConfig, conn appconn.Snapshot, connQuery appconn.Query) *Reactor {
+	ssR := &Reactor{
+		cfg:       cfg,
+		conn:      conn,
+		connQuery: connQuery,
+	}
+	ssR.BaseReactor = *p2p.NewBaseReactor("StateSyncReactor", ssR)
+	return ssR
+}
+
+// GetChannels implements Reactor.
+func (ssR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  SnapshotChannel,
+			Priority:            5,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: snapshotMsgSize,
+		},
+		{
+			ID:                  ChunkChannel,
+			Priority:            3,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: chunkMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor. If a state sync is in progress, the snapshots
+// of the peer are requested.
+func (ssR *Reactor) AddPeer(peer p2p.Peer) {
+	ssR.mtx.RLock()
+	defer ssR.mtx.RUnlock()
+
+	if ssR.syncer != nil {
+		peer.Send(SnapshotChannel, amino.MustMarshalAny(&SnapshotsRequestMessage{}))
+	}
+}
+
+// RemovePeer implements Reactor.
+func (ssR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
+	ssR.mtx.RLock()
+	defer ssR.mtx.RUnlock()
+
+	if ssR.syncer != nil {
+		ssR.syncer.RemovePeer(peer)
+	}
+}
+
+// Receive implements Reactor.
+func (ssR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	if !ssR.IsRunning() {
+		return
+	}
+
+	maxSize := snapshotMsgSize
+	if chID == ChunkChannel {
+		maxSize = chunkMsgSize
+	}
+	msg, err := decodeMsg(msgBytes, maxSize)
+	if err != nil {
+		ssR.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err)
+		ssR.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	if err = msg.ValidateBasic(); err != nil {
+		ssR.Logger.Error("Peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
+		ssR.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	ssR.Logger.Debug("Receive", "src", src, "chId", chID, "msg", msg)
+
+	switch msg := msg.(type) {
+	case *SnapshotsRequestMessage:
+		ssR.respondSnapshots(src)
+
+	case *SnapshotsResponseMessage:
+		ssR.mtx.RLock()
+		defer ssR.mtx.RUnlock()
+		if ssR.syncer == nil {
+			ssR.Logger.Debug("Received unexpected snapshot, no state sync in progress")
+			return
+		}
+		ssR.syncer.AddSnapshot(src, &snapshot{
+			Height:   msg.Height,
+			Format:   msg.Format,
+			Chunks:   msg.Chunks,
+			Hash:     msg.Hash,
+			Metadata: msg.Metadata,
+		})
+
+	case *ChunkRequestMessage:
+		ssR.respondChunk(src, msg)
+
+	case *ChunkResponseMessage:
+		ssR.mtx.RLock()
+		defer ssR.mtx.RUnlock()
+		if ssR.syncer == nil {
+			ssR.Logger.Debug("Received unexpected chunk, no state sync in progress", "peer", src)
+			return
+		}
+		if msg.Missing {
+			ssR.Logger.Debug("Peer doesn't have the requested chunk", "peer", src,
+				"height", msg.Height, "format", msg.Format, "chunk", msg.Index)
+			return
+		}
+		_, err := ssR.syncer.AddChunk(&chunk{
+			Height: msg.Height,
+			Format: msg.Format,
+			Index:  msg.Index,
+			Chunk:  append([]byte(nil), msg.Chunk...),
+			Sender: src.ID(),
+		})
+		if err != nil {
+			ssR.Logger.Info("Failed to add chunk", "peer", src, "height", msg.Height,
+				"format", msg.Format, "chunk", msg.Index, "err", err)
+		}
+
+	default:
+		ssR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+	}
+}
+
+// respondSnapshots advertises the recent snapshots of the app to a peer.
+func (ssR *Reactor) respondSnapshots(src p2p.Peer) {
+	res, err := ssR.conn.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err != nil {
+		ssR.Logger.Error("Failed to list snapshots", "err", err)
+		return
+	}
+	if res.Error != nil {
+		ssR.Logger.Error("Failed to list snapshots", "err", res.Error)
+		return
+	}
+
+	snapshots := res.Snapshots
+	sort.Slice(snapshots, func(i, j int) bool {
+		a, b := snapshots[i], snapshots[j]
+		if a.Height != b.Height {
+			return a.Height > b.Height
+		}
+		return a.Format > b.Format
+	})
+	if len(snapshots) > recentSnapshots {
+		snapshots = snapshots[:recentSnapshots]
+	}
+	for _, s := range snapshots {
+		ssR.Logger.Debug("Advertising snapshot", "height", s.Height, "format", s.Format, "peer", src.ID())
+		src.Send(SnapshotChannel, amino.MustMarshalAny(&SnapshotsResponseMessage{
+			Height:   s.Height,
+			Format:   s.Format,
+			Chunks:   s.Chunks,
+			Hash:     s.Hash,
+			Metadata: s.Metadata,
+		}))
+	}
+}
+
+// respondChunk sends a chunk of a snapshot of the app to a peer.
+func (ssR *Reactor) respondChunk(src p2p.Peer, msg *ChunkRequestMessage) {
+	res, err := ssR.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err != nil {
+		ssR.Logger.Error("Failed to load chunk", "height", msg.Height, "format", msg.Format,
+			"chunk", msg.Index, "err", err)
+		return
+	}
+	if res.Error != nil {
+		ssR.Logger.Error("Failed to load chunk", "height", msg.Height, "format", msg.Format,
+			"chunk", msg.Index, "err", res.Error)
+		return
+	}
+
+	ssR.Logger.Debug("Sending chunk", "height", msg.Height, "format", msg.Format,
+		"chunk", msg.Index, "peer", src.ID())
+	src.Send(ChunkChannel, amino.MustMarshalAny(&ChunkResponseMessage{
+		Height:  msg.Height,
+		Format:  msg.Format,
+		Index:   msg.Index,
+		Chunk:   res.Chunk,
+		Missing: res.Chunk == nil,
+	}))
+}
+
+// Sync restores the app from the snapshots of peers, verified with the given
+// state provider, and returns the state and commit at the height of the
+// restored snapshot, to bootstrap the node with.
+func (ssR *Reactor) Sync(stateProvider StateProvider) (sm.State, *types.Commit, error) {
+	ssR.mtx.Lock()
+	if ssR.syncer != nil {
+		ssR.mtx.Unlock()
+		return sm.State{}, nil, errors.New("a state sync is already in progress")
+	}
+	syncer := newSyncer(ssR.Logger, stateProvider, ssR.conn, ssR.connQuery,
+		ssR.cfg.ChunkFetchers, ssR.cfg.ChunkRequestTimeout)
+	ssR.syncer = syncer
+	ssR.mtx.Unlock()
+	defer func() {
+		ssR.mtx.Lock()
+		ssR.syncer = nil
+		ssR.mtx.Unlock()
+	}()
+
+	requestSnapshots := func() {
+		ssR.Switch.Broadcast(SnapshotChannel, amino.MustMarshalAny(&SnapshotsRequestMessage{}))
+	}
+	return syncer.SyncAny(ssR.cfg.DiscoveryTime, requestSnapshots, ssR.Quit())
+}
@@ -0,0 +1,212 @@
+package statesync
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+// snapshotKey uniquely identifies a snapshot.
+type snapshotKey [sha256.Size]byte
+
+// snapshot is a snapshot advertised by peers.
+type snapshot struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+}
+
+// Key returns the key of the snapshot: the hash of all its fields, so that
+// snapshots which only differ by their metadata are distinct.
+func (s *snapshot) Key() snapshotKey {
+	hasher := sha256.New()
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[0:], uint64(s.Height))
+	binary.BigEndian.PutUint32(buf[8:], s.Format)
+	binary.BigEndian.PutUint32(buf[12:], s.Chunks)
+	hasher.Write(buf[:])
+	hasher.Write(s.Hash)
+	hasher.Write(s.Metadata)
+
+	var key snapshotKey
+	copy(key[:], hasher.Sum(nil))
+	return key
+}
+
+// snapshotPool keeps track of the snapshots advertised by peers, and of the
+// snapshots, formats and peers which were rejected during the sync.
+type snapshotPool struct {
+	mtx             sync.Mutex
+	snapshots       map[snapshotKey]*snapshot
+	snapshotPeers   map[snapshotKey]map[p2p.ID]p2p.Peer
+	peerSnapshots   map[p2p.ID]map[snapshotKey]struct{}
+	rejected        map[snapshotKey]struct{}
+	rejectedFormats map[uint32]struct{}
+	rejectedPeers   map[p2p.ID]struct{}
+}
+
+func newSnapshotPool() *snapshotPool {
+	return &snapshotPool{
+		snapshots:       make(map[snapshotKey]*snapshot),
+		snapshotPeers:   make(map[snapshotKey]map[p2p.ID]p2p.Peer),
+		peerSnapshots:   make(map[p2p.ID]map[snapshotKey]struct{}),
+		rejected:        make(map[snapshotKey]struct{}),
+		rejectedFormats: make(map[uint32]struct{}),
+		rejectedPeers:   make(map[p2p.ID]struct{}),
+	}
+}
+
+// Add adds a snapshot advertised by the given peer. It returns true if the
+// snapshot is new.
+func (p *snapshotPool) Add(peer p2p.Peer, s *snapshot) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	key := s.Key()
+	if _, ok := p.rejected[key]; ok {
+		return false
+	}
+	if _, ok := p.rejectedFormats[s.Format]; ok {
+		return false
+	}
+	if _, ok := p.rejectedPeers[peer.ID()]; ok {
+		return false
+	}
+
+	if p.snapshotPeers[key] == nil {
+		p.snapshotPeers[key] = make(map[p2p.ID]p2p.Peer)
+	}
+	p.snapshotPeers[key][peer.ID()] = peer
+	if p.peerSnapshots[peer.ID()] == nil {
+		p.peerSnapshots[peer.ID()] = make(map[snapshotKey]struct{})
+	}
+	p.peerSnapshots[peer.ID()][key] = struct{}{}
+
+	if _, ok := p.snapshots[key]; ok {
+		return false
+	}
+	p.snapshots[key] = s
+	return true
+}
+
+// Best returns the best snapshot to sync: the one with the highest height,
+// then the highest format, then the most peers. It returns nil if there is
+// no snapshot.
+func (p *snapshotPool) Best() *snapshot {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	snapshots := make([]*snapshot, 0, len(p.snapshots))
+	for _, s := range p.snapshots {
+		snapshots = append(snapshots, s)
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		a, b := snapshots[i], snapshots[j]
+		switch {
+		case a.Height != b.Height:
+			return a.Height > b.Height
+		case a.Format != b.Format:
+			return a.Format > b.Format
+		default:
+			return len(p.snapshotPeers[a.Key()]) > len(p.snapshotPeers[b.Key()])
+		}
+	})
+	return snapshots[0]
+}
+
+// GetPeer returns a random peer which has the given snapshot, or nil if there
+// is none.
+func (p *snapshotPool) GetPeer(s *snapshot) p2p.Peer {
+	peers := p.GetPeers(s)
+	if len(peers) == 0 {
+		return nil
+	}
+	return peers[rand.Intn(len(peers))] //nolint:gosec
+}
+
+// GetPeers returns the peers which have the given snapshot.
+func (p *snapshotPool) GetPeers(s *snapshot) []p2p.Peer {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	peers := make([]p2p.Peer, 0, len(p.snapshotPeers[s.Key()]))
+	for _, peer := range p.snapshotPeers[s.Key()] {
+		peers = append(peers, peer)
+	}
+	// Sort the peers, so that the result is deterministic for a given pool.
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID() < peers[j].ID()
+	})
+	return peers
+}
+
+// Reject rejects a snapshot, which won't be returned by Best anymore.
+func (p *snapshotPool) Reject(s *snapshot) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	key := s.Key()
+	p.rejected[key] = struct{}{}
+	p.removeSnapshot(key)
+}
+
+// RejectFormat rejects all the snapshots in the given format.
+func (p *snapshotPool) RejectFormat(format uint32) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.rejectedFormats[format] = struct{}{}
+	for key, s := range p.snapshots {
+		if s.Format == format {
+			p.removeSnapshot(key)
+		}
+	}
+}
+
+// RejectPeer rejects a peer, whose snapshots are ignored from now on.
+func (p *snapshotPool) RejectPeer(id p2p.ID) {
+	if id == "" {
+		return
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.rejectedPeers[id] = struct{}{}
+	p.removePeer(id)
+}
+
+// RemovePeer removes a peer, e.g. when it disconnects.
+func (p *snapshotPool) RemovePeer(id p2p.ID) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.removePeer(id)
+}
+
+func (p *snapshotPool) removePeer(id p2p.ID) {
+	for key := range p.peerSnapshots[id] {
+		delete(p.snapshotPeers[key], id)
+		// Snapshots without peers can't be fetched anymore.
+		if len(p.snapshotPeers[key]) == 0 {
+			p.removeSnapshot(key)
+		}
+	}
+	delete(p.peerSnapshots, id)
+}
+
+func (p *snapshotPool) removeSnapshot(key snapshotKey) {
+	for id := range p.snapshotPeers[key] {
+		delete(p.peerSnapshots[id], key)
+	}
+	delete(p.snapshots, key)
+	delete(p.snapshotPeers, key)
+}
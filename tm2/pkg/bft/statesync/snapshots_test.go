@@ -0,0 +1,108 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gnolang/gno/tm2/pkg/p2p/mock"
+)
+
+func TestSnapshotKey(t *testing.T) {
+	t.Parallel()
+
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}, Metadata: []byte{1}}
+	assert.Equal(t, s.Key(), (&snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}, Metadata: []byte{1}}).Key())
+
+	for _, other := range []*snapshot{
+		{Height: 2, Format: 1, Chunks: 1, Hash: []byte{1}, Metadata: []byte{1}},
+		{Height: 1, Format: 2, Chunks: 1, Hash: []byte{1}, Metadata: []byte{1}},
+		{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1}, Metadata: []byte{1}},
+		{Height: 1, Format: 1, Chunks: 1, Hash: []byte{2}, Metadata: []byte{1}},
+		{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}, Metadata: []byte{2}},
+	} {
+		assert.NotEqual(t, s.Key(), other.Key())
+	}
+}
+
+func TestSnapshotPool_Best(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pool   = newSnapshotPool()
+		peerA  = mock.NewPeer(nil)
+		peerB  = mock.NewPeer(nil)
+		low    = &snapshot{Height: 1, Format: 2, Chunks: 1, Hash: []byte{1}}
+		high   = &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+		highV2 = &snapshot{Height: 2, Format: 2, Chunks: 1, Hash: []byte{3}}
+	)
+	assert.Nil(t, pool.Best())
+
+	assert.True(t, pool.Add(peerA, low))
+	assert.False(t, pool.Add(peerB, low), "snapshot is already known")
+	assert.Equal(t, low, pool.Best())
+
+	assert.True(t, pool.Add(peerA, high))
+	assert.Equal(t, high, pool.Best(), "highest height first")
+
+	assert.True(t, pool.Add(peerB, highV2))
+	assert.Equal(t, highV2, pool.Best(), "highest format first")
+
+	assert.Len(t, pool.GetPeers(low), 2)
+	assert.Len(t, pool.GetPeers(highV2), 1)
+	assert.Equal(t, peerB.ID(), pool.GetPeer(highV2).ID())
+}
+
+func TestSnapshotPool_Reject(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pool  = newSnapshotPool()
+		peerA = mock.NewPeer(nil)
+		peerB = mock.NewPeer(nil)
+		s1    = &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+		s2    = &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+		s3    = &snapshot{Height: 3, Format: 2, Chunks: 1, Hash: []byte{3}}
+	)
+	pool.Add(peerA, s1)
+	pool.Add(peerA, s2)
+	pool.Add(peerB, s3)
+
+	pool.Reject(s3)
+	assert.Equal(t, s2, pool.Best())
+	assert.False(t, pool.Add(peerA, s3), "rejected snapshot")
+
+	pool.RejectFormat(1)
+	assert.Nil(t, pool.Best())
+	assert.False(t, pool.Add(peerA, &snapshot{Height: 4, Format: 1, Chunks: 1, Hash: []byte{4}}),
+		"rejected format")
+
+	s4 := &snapshot{Height: 4, Format: 3, Chunks: 1, Hash: []byte{4}}
+	assert.True(t, pool.Add(peerB, s4))
+	pool.RejectPeer(peerB.ID())
+	assert.Nil(t, pool.Best(), "snapshot without peers is removed")
+	assert.False(t, pool.Add(peerB, &snapshot{Height: 5, Format: 3, Chunks: 1, Hash: []byte{5}}),
+		"rejected peer")
+}
+
+func TestSnapshotPool_RemovePeer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pool  = newSnapshotPool()
+		peerA = mock.NewPeer(nil)
+		peerB = mock.NewPeer(nil)
+		s1    = &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+		s2    = &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+	)
+	pool.Add(peerA, s1)
+	pool.Add(peerB, s1)
+	pool.Add(peerB, s2)
+
+	pool.RemovePeer(peerB.ID())
+	assert.Equal(t, s1, pool.Best())
+	assert.Len(t, pool.GetPeers(s1), 1)
+
+	// Removed peers can advertise snapshots again, unlike rejected peers.
+	assert.True(t, pool.Add(peerB, s2))
+}
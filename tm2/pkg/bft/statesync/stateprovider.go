@@ -0,0 +1,313 @@
+package statesync
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// StateProvider provides the verified data needed to bootstrap a node from a
+// snapshot at a given height.
+type StateProvider interface {
+	// AppHash returns the app hash after the block at the given height was
+	// committed, which is the app hash of a snapshot at this height.
+	AppHash(height int64) ([]byte, error)
+	// Commit returns the commit of the block at the given height.
+	Commit(height int64) (*types.Commit, error)
+	// State returns the state after the block at the given height was
+	// committed.
+	State(height int64) (sm.State, error)
+}
+
+// rpcClient is the subset of the RPC client used by the rpcStateProvider.
+type rpcClient interface {
+	Commit(height *int64) (*ctypes.ResultCommit, error)
+	Validators(height *int64) (*ctypes.ResultValidators, error)
+	ConsensusParams(height *int64) (*ctypes.ResultConsensusParams, error)
+}
+
+// rpcStateProvider is a StateProvider which fetches its data from the RPC
+// servers of other nodes.
+//
+// Headers are verified from a trusted header: the headers preceding it are
+// verified through the chain of block hashes, and the headers following it
+// through the commits of their validators, one height at a time. The headers
+// are fetched from the primary server, and checked against all the witnesses.
+type rpcStateProvider struct {
+	mtx         sync.Mutex
+	genesis     sm.State
+	primary     rpcClient
+	witnesses   []rpcClient
+	trustHeight int64
+	trustHash   []byte
+
+	headers    map[int64]*types.SignedHeader // verified headers
+	validators map[int64]*types.ValidatorSet // verified validator sets
+}
+
+// NewRPCStateProvider returns a StateProvider which fetches the state from
+// the given RPC servers, the first one being the primary and the others the
+// witnesses. The headers are verified from the header at trustHeight, whose
+// hash is trustHash. The genesis state gives the chain ID and the versions of
+// the node.
+func NewRPCStateProvider(genesis sm.State, servers []string, trustHeight int64, trustHash []byte) (StateProvider, error) {
+	if len(servers) < 2 {
+		return nil, fmt.Errorf("at least 2 RPC servers are required, got %d", len(servers))
+	}
+	clients := make([]rpcClient, len(servers))
+	for i, server := range servers {
+		c, err := client.NewHTTPClient(server)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create RPC client for %s: %w", server, err)
+		}
+		clients[i] = c
+	}
+	return newRPCStateProvider(genesis, clients, trustHeight, trustHash), nil
+}
+
+func newRPCStateProvider(genesis sm.State, clients []rpcClient, trustHeight int64, trustHash []byte) *rpcStateProvider {
+	return &rpcStateProvider{
+		genesis:     genesis,
+		primary:     clients[0],
+		witnesses:   clients[1:],
+		trustHeight: trustHeight,
+		trustHash:   trustHash,
+		headers:     make(map[int64]*types.SignedHeader),
+		validators:  make(map[int64]*types.ValidatorSet),
+	}
+}
+
+// AppHash implements StateProvider.
+func (p *rpcStateProvider) AppHash(height int64) ([]byte, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	// The app hash of a block is in the header of the next block.
+	header, err := p.verifiedHeader(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	return header.AppHash, nil
+}
+
+// Commit implements StateProvider.
+func (p *rpcStateProvider) Commit(height int64) (*types.Commit, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	header, err := p.verifiedHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	return header.Commit, nil
+}
+
+// State implements StateProvider.
+func (p *rpcStateProvider) State(height int64) (sm.State, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	header, err := p.verifiedHeader(height)
+	if err != nil {
+		return sm.State{}, err
+	}
+	nextHeader, err := p.verifiedHeader(height + 1)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	lastVals, err := p.validatorSet(height, header.ValidatorsHash)
+	if err != nil {
+		return sm.State{}, err
+	}
+	vals, err := p.validatorSet(height+1, nextHeader.ValidatorsHash)
+	if err != nil {
+		return sm.State{}, err
+	}
+	nextVals, err := p.validatorSet(height+2, nextHeader.NextValidatorsHash)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	res, err := p.primary.ConsensusParams(&nextHeader.Height)
+	if err != nil {
+		return sm.State{}, fmt.Errorf("unable to fetch consensus params at height %d: %w", nextHeader.Height, err)
+	}
+	params := res.ConsensusParams
+	if !bytes.Equal(params.Hash(), nextHeader.ConsensusHash) {
+		return sm.State{}, fmt.Errorf("consensus params at height %d don't match the header", nextHeader.Height)
+	}
+
+	return sm.State{
+		SoftwareVersion:                  p.genesis.SoftwareVersion,
+		BlockVersion:                     p.genesis.BlockVersion,
+		AppVersion:                       nextHeader.AppVersion,
+		ChainID:                          p.genesis.ChainID,
+		LastBlockHeight:                  header.Height,
+		LastBlockTotalTx:                 header.TotalTxs,
+		LastBlockID:                      header.Commit.BlockID,
+		LastBlockTime:                    header.Time,
+		NextValidators:                   nextVals,
+		Validators:                       vals,
+		LastValidators:                   lastVals,
+		LastHeightValidatorsChanged:      height + 2,
+		ConsensusParams:                  params,
+		LastHeightConsensusParamsChanged: height + 1,
+		LastResultsHash:                  nextHeader.LastResultsHash,
+		AppHash:                          nextHeader.AppHash,
+	}, nil
+}
+
+// verifiedHeader returns the verified signed header at the given height.
+func (p *rpcStateProvider) verifiedHeader(height int64) (*types.SignedHeader, error) {
+	if header, ok := p.headers[height]; ok {
+		return header, nil
+	}
+
+	var (
+		header *types.SignedHeader
+		err    error
+	)
+	switch {
+	case height == p.trustHeight:
+		header, err = p.verifyTrusted()
+	case height < p.trustHeight:
+		header, err = p.verifyBackward(height)
+	default:
+		header, err = p.verifyForward(height)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkWitnesses(header); err != nil {
+		return nil, err
+	}
+	p.headers[height] = header
+	return header, nil
+}
+
+// verifyTrusted verifies the trusted header against the trusted hash.
+func (p *rpcStateProvider) verifyTrusted() (*types.SignedHeader, error) {
+	header, err := p.fetchHeader(p.trustHeight)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header.Hash(), p.trustHash) {
+		return nil, fmt.Errorf("header hash %X at trust height %d doesn't match the trust hash %X",
+			header.Hash(), p.trustHeight, p.trustHash)
+	}
+	return header, nil
+}
+
+// verifyBackward verifies a header preceding the trusted header, through the
+// hash of the block following it.
+func (p *rpcStateProvider) verifyBackward(height int64) (*types.SignedHeader, error) {
+	next, err := p.verifiedHeader(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	header, err := p.fetchHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header.Hash(), next.LastBlockID.Hash) {
+		return nil, fmt.Errorf("header hash %X at height %d doesn't match the last block hash %X of the next header",
+			header.Hash(), height, next.LastBlockID.Hash)
+	}
+	return header, nil
+}
+
+// verifyForward verifies a header following the trusted header, through the
+// commit of its validators, which are verified by the previous header.
+func (p *rpcStateProvider) verifyForward(height int64) (*types.SignedHeader, error) {
+	prev, err := p.verifiedHeader(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	header, err := p.fetchHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header.LastBlockID.Hash, prev.Hash()) {
+		return nil, fmt.Errorf("last block hash %X at height %d doesn't match the previous header hash %X",
+			header.LastBlockID.Hash, height, prev.Hash())
+	}
+	if !bytes.Equal(header.ValidatorsHash, prev.NextValidatorsHash) {
+		return nil, fmt.Errorf("validators hash %X at height %d doesn't match the next validators hash %X of the previous header",
+			header.ValidatorsHash, height, prev.NextValidatorsHash)
+	}
+	vals, err := p.validatorSet(height, header.ValidatorsHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := vals.VerifyCommit(header.ChainID, header.Commit.BlockID, height, header.Commit); err != nil {
+		return nil, fmt.Errorf("invalid commit at height %d: %w", height, err)
+	}
+	return header, nil
+}
+
+// fetchHeader fetches the signed header at the given height from the primary,
+// and checks that it is well-formed.
+func (p *rpcStateProvider) fetchHeader(height int64) (*types.SignedHeader, error) {
+	res, err := p.primary.Commit(&height)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch header at height %d: %w", height, err)
+	}
+	header := res.SignedHeader
+	if header.Header == nil {
+		return nil, fmt.Errorf("missing header at height %d", height)
+	}
+	if header.Height != height {
+		return nil, fmt.Errorf("expected header at height %d, got height %d", height, header.Height)
+	}
+	if err := header.ValidateBasic(p.genesis.ChainID); err != nil {
+		return nil, fmt.Errorf("invalid header at height %d: %w", height, err)
+	}
+	return &header, nil
+}
+
+// checkWitnesses checks that all the witnesses have the same header.
+func (p *rpcStateProvider) checkWitnesses(header *types.SignedHeader) error {
+	for i, witness := range p.witnesses {
+		res, err := witness.Commit(&header.Height)
+		if err != nil {
+			return fmt.Errorf("unable to fetch header at height %d from witness %d: %w", header.Height, i+1, err)
+		}
+		if res.SignedHeader.Header == nil || !bytes.Equal(res.SignedHeader.Hash(), header.Hash()) {
+			return fmt.Errorf("header at height %d of witness %d doesn't match the primary", header.Height, i+1)
+		}
+	}
+	return nil
+}
+
+// validatorSet fetches the validator set at the given height, and checks it
+// against the given hash. The proposer priorities of the validators are kept,
+// as they make the proposer of the following rounds.
+func (p *rpcStateProvider) validatorSet(height int64, hash []byte) (*types.ValidatorSet, error) {
+	if vals, ok := p.validators[height]; ok {
+		if !bytes.Equal(vals.Hash(), hash) {
+			return nil, fmt.Errorf("validators at height %d don't match the header", height)
+		}
+		return vals, nil
+	}
+
+	res, err := p.primary.Validators(&height)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch validators at height %d: %w", height, err)
+	}
+	if len(res.Validators) == 0 {
+		return nil, fmt.Errorf("no validators at height %d", height)
+	}
+	vals := &types.ValidatorSet{Validators: res.Validators}
+	if !bytes.Equal(vals.Hash(), hash) {
+		return nil, fmt.Errorf("validators at height %d don't match the header", height)
+	}
+	vals.GetProposer()
+	p.validators[height] = vals
+	return vals, nil
+}
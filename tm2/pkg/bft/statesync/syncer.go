@@ -0,0 +1,390 @@
+package statesync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+const (
+	// chunkTimeout is the time to wait for the next chunk to apply, before
+	// giving up on the snapshot.
+	chunkTimeout = 2 * time.Minute
+	// chunkWindow is the number of chunks which can be fetched ahead of the
+	// next chunk to apply.
+	chunkWindow = 16
+	// minimumDiscoveryTime is the minimum time to wait for snapshots.
+	minimumDiscoveryTime = 5 * time.Second
+	// chunkAllocateInterval is the time a fetcher waits before trying to
+	// allocate a chunk again, when the whole window is allocated.
+	chunkAllocateInterval = 100 * time.Millisecond
+)
+
+var (
+	// errAbort is returned when the app aborts the state sync.
+	errAbort = errors.New("state sync aborted")
+	// errRejectSnapshot is returned when the snapshot is rejected.
+	errRejectSnapshot = errors.New("snapshot was rejected")
+	// errRejectFormat is returned when the format of the snapshot is rejected.
+	errRejectFormat = errors.New("snapshot format was rejected")
+	// errVerifyFailed is returned when the restored app doesn't match the
+	// verified state.
+	errVerifyFailed = errors.New("verification of restored app failed")
+	// errTimeout is returned when a chunk isn't received in time.
+	errTimeout = errors.New("timed out waiting for chunk")
+	// errNoSnapshots is returned when there is no snapshot to sync.
+	errNoSnapshots = errors.New("no suitable snapshots found")
+)
+
+// syncer restores the app from the snapshots of peers: it offers them to the
+// app, fetches their chunks from the peers, applies them, and verifies the
+// restored app against the state given by the StateProvider.
+type syncer struct {
+	logger              *slog.Logger
+	stateProvider       StateProvider
+	conn                appconn.Snapshot
+	connQuery           appconn.Query
+	snapshots           *snapshotPool
+	chunkFetchers       int
+	chunkRequestTimeout time.Duration
+
+	mtx    sync.Mutex
+	chunks *chunkQueue // chunks of the snapshot being restored, if any
+}
+
+func newSyncer(
+	logger *slog.Logger,
+	stateProvider StateProvider,
+	conn appconn.Snapshot,
+	connQuery appconn.Query,
+	chunkFetchers int,
+	chunkRequestTimeout time.Duration,
+) *syncer {
+	return &syncer{
+		logger:              logger,
+		stateProvider:       stateProvider,
+		conn:                conn,
+		connQuery:           connQuery,
+		snapshots:           newSnapshotPool(),
+		chunkFetchers:       chunkFetchers,
+		chunkRequestTimeout: chunkRequestTimeout,
+	}
+}
+
+// AddSnapshot adds a snapshot advertised by a peer. It returns true if the
+// snapshot is new.
+func (s *syncer) AddSnapshot(peer p2p.Peer, snap *snapshot) bool {
+	added := s.snapshots.Add(peer, snap)
+	if added {
+		s.logger.Info("Discovered new snapshot", "height", snap.Height, "format", snap.Format, "hash", fmt.Sprintf("%X", snap.Hash))
+	}
+	return added
+}
+
+// AddChunk adds a chunk received from a peer to the snapshot being restored.
+// It returns false if the chunk was already received.
+func (s *syncer) AddChunk(c *chunk) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.chunks == nil {
+		return false, errors.New("no snapshot is being restored")
+	}
+	return s.chunks.Add(c)
+}
+
+// RemovePeer removes a peer from the syncer.
+func (s *syncer) RemovePeer(peer p2p.Peer) {
+	s.snapshots.RemovePeer(peer.ID())
+}
+
+// SyncAny restores the app from any of the snapshots advertised by peers,
+// trying the best ones first. requestSnapshots is called to discover
+// snapshots, and the syncer waits discoveryTime for them to be advertised. If
+// discoveryTime is 0, only the snapshots already known are tried.
+//
+// It returns the state and commit at the height of the restored snapshot.
+func (s *syncer) SyncAny(discoveryTime time.Duration, requestSnapshots func(), quit <-chan struct{}) (sm.State, *types.Commit, error) {
+	if discoveryTime != 0 && discoveryTime < minimumDiscoveryTime {
+		discoveryTime = minimumDiscoveryTime
+	}
+
+	discover := func() error {
+		requestSnapshots()
+		s.logger.Info("Discovering snapshots", "time", discoveryTime)
+		select {
+		case <-time.After(discoveryTime):
+			return nil
+		case <-quit:
+			return errAbort
+		}
+	}
+	if discoveryTime > 0 {
+		if err := discover(); err != nil {
+			return sm.State{}, nil, err
+		}
+	}
+
+	for {
+		snap := s.snapshots.Best()
+		if snap == nil {
+			if discoveryTime == 0 {
+				return sm.State{}, nil, errNoSnapshots
+			}
+			if err := discover(); err != nil {
+				return sm.State{}, nil, err
+			}
+			continue
+		}
+
+		state, commit, err := s.Sync(snap, newChunkQueue(snap))
+		switch {
+		case err == nil:
+			return state, commit, nil
+
+		case errors.Is(err, errAbort):
+			return sm.State{}, nil, err
+
+		case errors.Is(err, errTimeout):
+			s.snapshots.Reject(snap)
+			s.logger.Error("Timed out waiting for snapshot chunks, rejected snapshot",
+				"height", snap.Height, "format", snap.Format)
+
+		case errors.Is(err, errRejectSnapshot):
+			s.snapshots.Reject(snap)
+			s.logger.Info("Snapshot rejected", "height", snap.Height, "format", snap.Format)
+
+		case errors.Is(err, errRejectFormat):
+			s.snapshots.RejectFormat(snap.Format)
+			s.logger.Info("Snapshot format rejected", "format", snap.Format)
+
+		default:
+			return sm.State{}, nil, fmt.Errorf("snapshot restoration failed: %w", err)
+		}
+	}
+}
+
+// Sync restores the app from the given snapshot, whose chunks are fetched
+// into the given queue. The snapshot is offered to the app along with its
+// verified app hash, and the app is checked against it once restored.
+func (s *syncer) Sync(snap *snapshot, queue *chunkQueue) (sm.State, *types.Commit, error) {
+	s.mtx.Lock()
+	if s.chunks != nil {
+		s.mtx.Unlock()
+		return sm.State{}, nil, errors.New("a state sync is already in progress")
+	}
+	s.chunks = queue
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		s.chunks = nil
+		s.mtx.Unlock()
+		queue.Close()
+	}()
+
+	// Verify the snapshot height before offering it, so that the app only
+	// receives snapshots matching a trusted app hash.
+	appHash, err := s.stateProvider.AppHash(snap.Height)
+	if err != nil {
+		s.logger.Info("Failed to verify the app hash of the snapshot", "height", snap.Height, "err", err)
+		return sm.State{}, nil, errRejectSnapshot
+	}
+	if err := s.offerSnapshot(snap, appHash); err != nil {
+		return sm.State{}, nil, err
+	}
+
+	// Fetch the chunks concurrently, while they are applied in order.
+	stop := make(chan struct{})
+	defer close(stop)
+	for i := 0; i < s.chunkFetchers; i++ {
+		go s.fetchChunks(snap, queue, stop)
+	}
+
+	// Fetch the state and commit needed to bootstrap the node now, so that
+	// a snapshot whose state can't be verified isn't applied.
+	state, err := s.stateProvider.State(snap.Height)
+	if err != nil {
+		s.logger.Info("Failed to fetch the state of the snapshot", "height", snap.Height, "err", err)
+		return sm.State{}, nil, errRejectSnapshot
+	}
+	commit, err := s.stateProvider.Commit(snap.Height)
+	if err != nil {
+		s.logger.Info("Failed to fetch the commit of the snapshot", "height", snap.Height, "err", err)
+		return sm.State{}, nil, errRejectSnapshot
+	}
+
+	if err := s.applyChunks(queue); err != nil {
+		return sm.State{}, nil, err
+	}
+	if err := s.verifyApp(snap, appHash); err != nil {
+		return sm.State{}, nil, err
+	}
+
+	s.logger.Info("Snapshot restored", "height", snap.Height, "format", snap.Format, "hash", fmt.Sprintf("%X", snap.Hash))
+	return state, commit, nil
+}
+
+// offerSnapshot offers a snapshot to the app.
+func (s *syncer) offerSnapshot(snap *snapshot, appHash []byte) error {
+	s.logger.Info("Offering snapshot to the app", "height", snap.Height, "format", snap.Format, "hash", fmt.Sprintf("%X", snap.Hash))
+	res, err := s.conn.OfferSnapshotSync(abci.RequestOfferSnapshot{
+		Snapshot: &abci.Snapshot{
+			Height:   snap.Height,
+			Format:   snap.Format,
+			Chunks:   snap.Chunks,
+			Hash:     snap.Hash,
+			Metadata: snap.Metadata,
+		},
+		AppHash: appHash,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to offer snapshot: %w", err)
+	}
+
+	switch res.Result {
+	case abci.OfferSnapshotAccept:
+		s.logger.Info("Snapshot accepted, restoring", "height", snap.Height, "format", snap.Format)
+		return nil
+	case abci.OfferSnapshotAbort:
+		return errAbort
+	case abci.OfferSnapshotReject:
+		return errRejectSnapshot
+	case abci.OfferSnapshotRejectFormat:
+		return errRejectFormat
+	default:
+		return fmt.Errorf("unknown OfferSnapshot result %v", res.Result)
+	}
+}
+
+// applyChunks applies the chunks of the queue to the app, in order, as they
+// are received.
+func (s *syncer) applyChunks(queue *chunkQueue) error {
+	for {
+		c, err := queue.Next(chunkTimeout)
+		if errors.Is(err, errDone) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		res, err := s.conn.ApplySnapshotChunkSync(abci.RequestApplySnapshotChunk{
+			Index:  c.Index,
+			Chunk:  c.Chunk,
+			Sender: string(c.Sender),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply chunk %d: %w", c.Index, err)
+		}
+		s.logger.Info("Applied snapshot chunk", "height", c.Height, "format", c.Format,
+			"chunk", c.Index, "total", queue.snapshot.Chunks)
+
+		// Discard and refetch the chunks of the senders rejected by the app.
+		for _, sender := range res.RejectSenders {
+			if sender != "" {
+				id := p2p.ID(sender)
+				s.snapshots.RejectPeer(id)
+				queue.DiscardSender(id)
+			}
+		}
+		for _, index := range res.RefetchChunks {
+			queue.Discard(index)
+		}
+
+		switch res.Result {
+		case abci.ApplySnapshotChunkAccept:
+			queue.Applied(c.Index)
+		case abci.ApplySnapshotChunkAbort:
+			return errAbort
+		case abci.ApplySnapshotChunkRetry:
+			// The chunk is applied again, once refetched if requested.
+		case abci.ApplySnapshotChunkRejectSnapshot:
+			return errRejectSnapshot
+		default:
+			return fmt.Errorf("unknown ApplySnapshotChunk result %v", res.Result)
+		}
+	}
+}
+
+// fetchChunks requests the chunks allocated from the queue to the peers of
+// the snapshot, one at a time, until all chunks are received or stop is
+// closed. A chunk which isn't received in time is requested again, possibly
+// from another peer.
+func (s *syncer) fetchChunks(snap *snapshot, queue *chunkQueue, stop <-chan struct{}) {
+	for {
+		index, received, err := queue.Allocate(chunkWindow)
+		if errors.Is(err, errDone) {
+			return
+		}
+
+		// All the chunks of the window are allocated: wait for the
+		// next chunk to be applied.
+		if received == nil {
+			select {
+			case <-time.After(chunkAllocateInterval):
+				continue
+			case <-stop:
+				return
+			}
+		}
+
+		s.requestChunk(snap, index)
+		timer := time.NewTimer(s.chunkRequestTimeout)
+		select {
+		case <-received:
+		case <-timer.C:
+			queue.Deallocate(index)
+		case <-stop:
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+	}
+}
+
+// requestChunk requests a chunk from a random peer of the snapshot.
+func (s *syncer) requestChunk(snap *snapshot, index uint32) {
+	peer := s.snapshots.GetPeer(snap)
+	if peer == nil {
+		s.logger.Info("No peer found to request snapshot chunk from", "height", snap.Height,
+			"format", snap.Format, "chunk", index)
+		return
+	}
+	s.logger.Debug("Requesting snapshot chunk", "height", snap.Height, "format", snap.Format,
+		"chunk", index, "peer", peer.ID())
+	peer.Send(ChunkChannel, amino.MustMarshalAny(&ChunkRequestMessage{
+		Height: snap.Height,
+		Format: snap.Format,
+		Index:  index,
+	}))
+}
+
+// verifyApp checks that the restored app is at the height of the snapshot,
+// with the verified app hash.
+func (s *syncer) verifyApp(snap *snapshot, appHash []byte) error {
+	res, err := s.connQuery.InfoSync(abci.RequestInfo{})
+	if err != nil {
+		return fmt.Errorf("failed to query the app info: %w", err)
+	}
+	if res.LastBlockHeight != snap.Height {
+		s.logger.Error("Restored app height doesn't match the snapshot height",
+			"expected", snap.Height, "actual", res.LastBlockHeight)
+		return errVerifyFailed
+	}
+	if !bytes.Equal(res.LastBlockAppHash, appHash) {
+		s.logger.Error("Restored app hash doesn't match the verified app hash",
+			"expected", fmt.Sprintf("%X", appHash), "actual", fmt.Sprintf("%X", res.LastBlockAppHash))
+		return errVerifyFailed
+	}
+	return nil
+}
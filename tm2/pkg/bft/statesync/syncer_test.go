@@ -0,0 +1,222 @@
+package statesync
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p/mock"
+)
+
+// testApp is an app restoring snapshots whose chunks are the bytes of the
+// app hash, in order.
+type testApp struct {
+	mtx        sync.Mutex
+	offered    []*abci.Snapshot
+	offerRes   abci.OfferSnapshotResult
+	appHash    []byte
+	restoring  *abci.Snapshot
+	restored   []byte
+	height     int64
+	badChunks  map[uint32]bool // chunks to reject once
+	rejections int
+}
+
+func (app *testApp) Error() error { return nil }
+
+func (app *testApp) ListSnapshotsSync(abci.RequestListSnapshots) (abci.ResponseListSnapshots, error) {
+	return abci.ResponseListSnapshots{}, nil
+}
+
+func (app *testApp) OfferSnapshotSync(req abci.RequestOfferSnapshot) (abci.ResponseOfferSnapshot, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	app.offered = append(app.offered, req.Snapshot)
+	if app.offerRes == abci.OfferSnapshotAccept {
+		app.restoring = req.Snapshot
+		app.appHash = req.AppHash
+		app.restored = nil
+	}
+	return abci.ResponseOfferSnapshot{Result: app.offerRes}, nil
+}
+
+func (app *testApp) LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk) (abci.ResponseLoadSnapshotChunk, error) {
+	return abci.ResponseLoadSnapshotChunk{}, nil
+}
+
+func (app *testApp) ApplySnapshotChunkSync(req abci.RequestApplySnapshotChunk) (abci.ResponseApplySnapshotChunk, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	if app.badChunks[req.Index] {
+		delete(app.badChunks, req.Index)
+		app.rejections++
+		return abci.ResponseApplySnapshotChunk{
+			Result:        abci.ApplySnapshotChunkRetry,
+			RefetchChunks: []uint32{req.Index},
+		}, nil
+	}
+	app.restored = append(app.restored, req.Chunk...)
+	if uint32(len(app.restored)) == app.restoring.Chunks {
+		app.height = app.restoring.Height
+	}
+	return abci.ResponseApplySnapshotChunk{Result: abci.ApplySnapshotChunkAccept}, nil
+}
+
+func (app *testApp) EchoSync(string) (abci.ResponseEcho, error) {
+	return abci.ResponseEcho{}, nil
+}
+
+func (app *testApp) InfoSync(abci.RequestInfo) (abci.ResponseInfo, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	return abci.ResponseInfo{LastBlockHeight: app.height, LastBlockAppHash: app.restored}, nil
+}
+
+func (app *testApp) QuerySync(abci.RequestQuery) (abci.ResponseQuery, error) {
+	return abci.ResponseQuery{}, nil
+}
+
+// testStateProvider provides the app hashes of the given heights.
+type testStateProvider struct {
+	appHashes map[int64][]byte
+}
+
+func (p *testStateProvider) AppHash(height int64) ([]byte, error) {
+	appHash, ok := p.appHashes[height]
+	if !ok {
+		return nil, errors.New("no header at this height")
+	}
+	return appHash, nil
+}
+
+func (p *testStateProvider) Commit(height int64) (*types.Commit, error) {
+	return &types.Commit{}, nil
+}
+
+func (p *testStateProvider) State(height int64) (sm.State, error) {
+	return sm.State{LastBlockHeight: height, AppHash: p.appHashes[height]}, nil
+}
+
+// testPeer serves the chunks of a snapshot, whose chunks are the bytes of
+// the given app hash.
+type testPeer struct {
+	*mock.Peer
+	syncer  *syncer
+	appHash []byte
+}
+
+func (p *testPeer) Send(chID byte, msgBytes []byte) bool {
+	var msg StateSyncMessage
+	amino.MustUnmarshalAny(msgBytes, &msg)
+	req := msg.(*ChunkRequestMessage)
+	go p.syncer.AddChunk(&chunk{
+		Height: req.Height,
+		Format: req.Format,
+		Index:  req.Index,
+		Chunk:  []byte{p.appHash[req.Index]},
+		Sender: p.ID(),
+	})
+	return true
+}
+
+func newTestSyncer(app *testApp, stateProvider StateProvider) *syncer {
+	return newSyncer(log.NewNoopLogger(), stateProvider, app, app, 2, time.Second)
+}
+
+func TestSyncer_SyncAny(t *testing.T) {
+	t.Parallel()
+
+	appHash := []byte("app_hash")
+	app := &testApp{
+		offerRes:  abci.OfferSnapshotAccept,
+		badChunks: map[uint32]bool{3: true},
+	}
+	stateProvider := &testStateProvider{appHashes: map[int64][]byte{10: appHash}}
+	s := newTestSyncer(app, stateProvider)
+
+	var (
+		peerA = &testPeer{Peer: mock.NewPeer(nil), syncer: s, appHash: appHash}
+		peerB = &testPeer{Peer: mock.NewPeer(nil), syncer: s, appHash: appHash}
+		snap  = &snapshot{Height: 10, Format: 1, Chunks: uint32(len(appHash)), Hash: []byte{1}}
+		// This snapshot is rejected, as its app hash can't be verified.
+		unverified = &snapshot{Height: 20, Format: 1, Chunks: uint32(len(appHash)), Hash: []byte{2}}
+	)
+	s.AddSnapshot(peerA, snap)
+	s.AddSnapshot(peerB, snap)
+	s.AddSnapshot(peerA, unverified)
+
+	state, commit, err := s.SyncAny(0, func() {}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), state.LastBlockHeight)
+	assert.NotNil(t, commit)
+
+	assert.Len(t, app.offered, 1)
+	assert.Equal(t, appHash, app.appHash)
+	assert.Equal(t, appHash, app.restored)
+	assert.Equal(t, 1, app.rejections)
+}
+
+func TestSyncer_SyncAny_noSnapshots(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSyncer(&testApp{}, &testStateProvider{})
+	_, _, err := s.SyncAny(0, func() {}, nil)
+	assert.ErrorIs(t, err, errNoSnapshots)
+}
+
+func TestSyncer_SyncAny_offerResults(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		result  abci.OfferSnapshotResult
+		offered int
+		err     error
+	}{
+		{"abort", abci.OfferSnapshotAbort, 1, errAbort},
+		{"reject", abci.OfferSnapshotReject, 3, errNoSnapshots},
+		{"reject format", abci.OfferSnapshotRejectFormat, 2, errNoSnapshots},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := &testApp{offerRes: tc.result}
+			stateProvider := &testStateProvider{appHashes: map[int64][]byte{
+				1: {1}, 2: {2}, 3: {3},
+			}}
+			s := newTestSyncer(app, stateProvider)
+
+			peer := mock.NewPeer(nil)
+			s.AddSnapshot(peer, &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}})
+			s.AddSnapshot(peer, &snapshot{Height: 2, Format: 2, Chunks: 1, Hash: []byte{2}})
+			s.AddSnapshot(peer, &snapshot{Height: 3, Format: 2, Chunks: 1, Hash: []byte{3}})
+
+			_, _, err := s.SyncAny(0, func() {}, nil)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Len(t, app.offered, tc.offered)
+		})
+	}
+}
+
+func TestSyncer_verifyApp(t *testing.T) {
+	t.Parallel()
+
+	app := &testApp{height: 10, restored: []byte("app_hash")}
+	s := newTestSyncer(app, &testStateProvider{})
+
+	assert.NoError(t, s.verifyApp(&snapshot{Height: 10}, []byte("app_hash")))
+	assert.ErrorIs(t, s.verifyApp(&snapshot{Height: 11}, []byte("app_hash")), errVerifyFailed)
+	assert.ErrorIs(t, s.verifyApp(&snapshot{Height: 10}, []byte("other_hash")), errVerifyFailed)
+}
@@ -29,6 +29,7 @@ type BlockStore struct {
 	db dbm.DB
 
 	mtx    sync.RWMutex
+	base   int64
 	height int64
 }
 
@@ -37,11 +38,21 @@ type BlockStore struct {
 func NewBlockStore(db dbm.DB) *BlockStore {
 	bsjson := LoadBlockStoreStateJSON(db)
 	return &BlockStore{
+		base:   bsjson.Base,
 		height: bsjson.Height,
 		db:     db,
 	}
 }
 
+// Base returns the first known contiguous block height, or 0 for an empty
+// block store. It is greater than 1 for a block store bootstrapped by state
+// sync, and greater than Height until its first block is saved.
+func (bs *BlockStore) Base() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.base
+}
+
 // Height returns the last known contiguous block height.
 func (bs *BlockStore) Height() int64 {
 	bs.mtx.RLock()
@@ -176,17 +187,43 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
 
 	// Save new BlockStoreStateJSON descriptor
-	BlockStoreStateJSON{Height: height}.Save(bs.db)
-
-	// Done!
 	bs.mtx.Lock()
+	if bs.base == 0 {
+		bs.base = height
+	}
 	bs.height = height
+	BlockStoreStateJSON{Base: bs.base, Height: height}.Save(bs.db)
 	bs.mtx.Unlock()
 
 	// Flush
 	bs.db.SetSync(nil, nil)
 }
 
+// Bootstrap initializes an empty block store at the given height, for a node
+// whose state was restored by state sync. Only the seen commit of the block at
+// that height is saved, and the next saved block must be at height+1.
+func (bs *BlockStore) Bootstrap(height int64, seenCommit *types.Commit) error {
+	if height <= 0 {
+		return fmt.Errorf("cannot bootstrap the block store at height %d", height)
+	}
+	if seenCommit == nil {
+		return errors.New("cannot bootstrap the block store without a seen commit")
+	}
+
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+	if bs.height != 0 {
+		return fmt.Errorf("cannot bootstrap a non-empty block store, at height %d", bs.height)
+	}
+
+	seenCommitBytes := amino.MustMarshal(seenCommit)
+	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
+	bs.base = height + 1
+	bs.height = height
+	BlockStoreStateJSON{Base: bs.base, Height: bs.height}.Save(bs.db)
+	return nil
+}
+
 func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 	if height != bs.Height()+1 {
 		panic(fmt.Sprintf("BlockStore can only save contiguous blocks. Wanted %v, got %v", bs.Height()+1, height))
@@ -219,6 +256,7 @@ var blockStoreKey = []byte("blockStore")
 
 // BlockStoreStateJSON is the block store state JSON structure.
 type BlockStoreStateJSON struct {
+	Base   int64 `json:"base"`
 	Height int64 `json:"height"`
 }
 
@@ -245,5 +283,9 @@ func LoadBlockStoreStateJSON(db dbm.DB) BlockStoreStateJSON {
 	if err != nil {
 		panic(fmt.Sprintf("Could not unmarshal bytes: %X", bytes))
 	}
+	// Block stores saved without a base start at height 1.
+	if bsj.Base == 0 && bsj.Height > 0 {
+		bsj.Base = 1
+	}
 	return bsj
 }
@@ -41,7 +41,7 @@ func makeTxs(height int64) (txs []types.Tx) {
 }
 
 func makeBlock(height int64, state sm.State, lastCommit *types.Commit) *types.Block {
-	block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, state.Validators.GetProposer().Address)
+	block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, nil, state.Validators.GetProposer().Address)
 	return block
 }
 
@@ -63,7 +63,7 @@ func TestLoadBlockStoreStateJSON(t *testing.T) {
 
 	db := memdb.NewMemDB()
 
-	bsj := &BlockStoreStateJSON{Height: 1000}
+	bsj := &BlockStoreStateJSON{Base: 1, Height: 1000}
 	bsj.Save(db)
 
 	retrBSJ := LoadBlockStoreStateJSON(db)
@@ -421,6 +421,42 @@ func TestBlockFetchAtHeight(t *testing.T) {
 	require.Nil(t, blockAtHeightPlus2, "expecting an unsuccessful load of Height()+2")
 }
 
+func TestBlockStoreBootstrap(t *testing.T) {
+	t.Parallel()
+
+	state, bs, cleanup := makeStateAndBlockStore(log.NewNoopLogger())
+	defer cleanup()
+	assert.Equal(t, int64(0), bs.Base())
+
+	assert.Error(t, bs.Bootstrap(0, makeTestCommit(0, tmtime.Now())))
+	assert.Error(t, bs.Bootstrap(10, nil))
+
+	seenCommit := makeTestCommit(10, tmtime.Now())
+	require.NoError(t, bs.Bootstrap(10, seenCommit))
+	assert.Equal(t, int64(11), bs.Base())
+	assert.Equal(t, int64(10), bs.Height())
+	assert.Nil(t, bs.LoadBlock(10))
+	assert.Equal(t, seenCommit.Hash(), bs.LoadSeenCommit(10).Hash())
+
+	// A bootstrapped block store can't be bootstrapped again.
+	assert.Error(t, bs.Bootstrap(20, seenCommit))
+
+	// Blocks are saved from the bootstrap height.
+	assert.Panics(t, func() {
+		block := makeBlock(12, state, new(types.Commit))
+		bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(12, tmtime.Now()))
+	})
+	block := makeBlock(11, state, new(types.Commit))
+	bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(11, tmtime.Now()))
+	assert.Equal(t, int64(11), bs.Base())
+	assert.Equal(t, int64(11), bs.Height())
+
+	// The base is persisted.
+	bs = NewBlockStore(bs.db)
+	assert.Equal(t, int64(11), bs.Base())
+	assert.Equal(t, int64(11), bs.Height())
+}
+
 func doFn(fn func() (interface{}, error)) (res interface{}, err error, panicErr error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -33,6 +33,9 @@ func TestBlockValidateBasic(t *testing.T) {
 	commit, err := MakeCommit(lastID, h-1, 1, voteSet, vals)
 	require.NoError(t, err)
 
+	ev := NewMockGoodEvidence(h, 0, valSet.Validators[0].Address)
+	evList := []Evidence{ev}
+
 	testCases := []struct {
 		testName      string
 		malleateBlock func(*Block)
@@ -54,6 +57,13 @@ func TestBlockValidateBasic(t *testing.T) {
 		{"Tampered DataHash", func(blk *Block) {
 			blk.DataHash = random.RandBytes(len(blk.DataHash))
 		}, true},
+		{"Tampered EvidenceHash", func(blk *Block) {
+			blk.EvidenceHash = []byte("something else")
+		}, true},
+		{"Tampered Evidence", func(blk *Block) {
+			blk.Evidence.Evidence = nil
+			blk.Evidence.hash = nil // clear hash or change wont be noticed
+		}, true},
 	}
 	for i, tc := range testCases {
 		tc := tc
@@ -61,7 +71,7 @@ func TestBlockValidateBasic(t *testing.T) {
 		t.Run(tc.testName, func(t *testing.T) {
 			t.Parallel()
 
-			block := MakeBlock(h, txs, commit)
+			block := MakeBlock(h, txs, commit, evList)
 			block.ProposerAddress = valSet.GetProposer().Address
 			tc.malleateBlock(block)
 			err = block.ValidateBasic()
@@ -74,7 +84,7 @@ func TestBlockHash(t *testing.T) {
 	t.Parallel()
 
 	assert.Nil(t, (*Block)(nil).Hash())
-	assert.Nil(t, MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil).Hash())
+	assert.Nil(t, MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil).Hash())
 }
 
 func TestBlockMakePartSet(t *testing.T) {
@@ -82,7 +92,7 @@ func TestBlockMakePartSet(t *testing.T) {
 
 	assert.Nil(t, (*Block)(nil).MakePartSet(2))
 
-	partSet := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil).MakePartSet(1024)
+	partSet := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil).MakePartSet(1024)
 	assert.NotNil(t, partSet)
 	assert.Equal(t, 1, partSet.Total())
 }
@@ -98,7 +108,7 @@ func TestBlockHashesTo(t *testing.T) {
 	commit, err := MakeCommit(lastID, h-1, 1, voteSet, vals)
 	require.NoError(t, err)
 
-	block := MakeBlock(h, []Tx{Tx("Hello World")}, commit)
+	block := MakeBlock(h, []Tx{Tx("Hello World")}, commit, nil)
 	block.ValidatorsHash = valSet.Hash()
 	assert.False(t, block.HashesTo([]byte{}))
 	assert.False(t, block.HashesTo([]byte("something else")))
@@ -108,7 +118,7 @@ func TestBlockHashesTo(t *testing.T) {
 func TestBlockSize(t *testing.T) {
 	t.Parallel()
 
-	size := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil).Size()
+	size := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil).Size()
 	if size <= 0 {
 		t.Fatal("Size of the block is zero or negative")
 	}
@@ -121,7 +131,7 @@ func TestBlockString(t *testing.T) {
 	assert.Equal(t, "nil-Block", (*Block)(nil).StringIndented(""))
 	assert.Equal(t, "nil-Block", (*Block)(nil).StringShort())
 
-	block := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil)
+	block := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil)
 	assert.NotEqual(t, "nil-Block", block.String())
 	assert.NotEqual(t, "nil-Block", block.StringIndented(""))
 	assert.NotEqual(t, "nil-Block", block.StringShort())
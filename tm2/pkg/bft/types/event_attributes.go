@@ -0,0 +1,180 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Attribute keys of events, in addition to those derived from
+// the messages and the ABCI events of transactions.
+const (
+	EventTypeKey   = "tm.event"     // e.g. "NewBlock", "Tx"
+	BlockHeightKey = "block.height" // height of the block of the event
+	TxHeightKey    = "tx.height"
+	TxIndexKey     = "tx.index"
+	TxHashKey      = "tx.hash" // upper-case hex
+	TxSuccessKey   = "tx.success"
+)
+
+// EventAttributes returns the attributes of ev that event queries are
+// matched against. See TxResultAttributes for the attributes of EventTx;
+// those of the ABCI events of EventNewBlock are added the same way.
+func EventAttributes(ev events.Event) query.Attributes {
+	attrs := query.Attributes{}
+	switch ev := ev.(type) {
+	case EventNewBlock:
+		if ev.Block != nil {
+			attrs.Add(BlockHeightKey, strconv.FormatInt(ev.Block.Height, 10))
+		}
+		for _, abciEv := range ev.ResultBeginBlock.Events {
+			addABCIEventAttributes(attrs, abciEv)
+		}
+		for _, abciEv := range ev.ResultEndBlock.Events {
+			addABCIEventAttributes(attrs, abciEv)
+		}
+	case EventNewBlockHeader:
+		attrs.Add(BlockHeightKey, strconv.FormatInt(ev.Header.Height, 10))
+	case EventTx:
+		attrs = TxResultAttributes(ev.Result)
+	case EventVote:
+		if ev.Vote != nil {
+			attrs.Add(BlockHeightKey, strconv.FormatInt(ev.Vote.Height, 10))
+		}
+	}
+	attrs.Add(EventTypeKey, eventTypeName(ev))
+	return attrs
+}
+
+// TxResultAttributes returns the attributes of a transaction result:
+//
+//   - tx.height, tx.index, tx.hash and tx.success, along with block.height.
+//   - message.type, message.route and message.signer for each message,
+//     and message.<field> for each scalar field of its JSON encoding,
+//     with nested fields joined by dots (e.g. message.pkg_path).
+//   - <type>.<key> for each attribute of each ABCI event of the result,
+//     where <type> is the event's type, along with event.type and
+//     <type>.<field> for the other scalar fields of the event, such as
+//     the pkg_path and func of the events emitted with std.Emit.
+func TxResultAttributes(res TxResult) query.Attributes {
+	attrs := query.Attributes{}
+	height := strconv.FormatInt(res.Height, 10)
+	attrs.Add(BlockHeightKey, height)
+	attrs.Add(TxHeightKey, height)
+	attrs.Add(TxIndexKey, strconv.FormatUint(uint64(res.Index), 10))
+	attrs.Add(TxHashKey, fmt.Sprintf("%X", res.Tx.Hash()))
+	attrs.Add(TxSuccessKey, strconv.FormatBool(res.Response.IsOK()))
+
+	var tx std.Tx
+	if err := amino.Unmarshal(res.Tx, &tx); err == nil {
+		for _, msg := range tx.Msgs {
+			addMessageAttributes(attrs, msg)
+		}
+	}
+	for _, ev := range res.Response.Events {
+		addABCIEventAttributes(attrs, ev)
+	}
+	return attrs
+}
+
+func addMessageAttributes(attrs query.Attributes, msg std.Msg) {
+	attrs.Add("message.type", msg.Type())
+	attrs.Add("message.route", msg.Route())
+	for _, signer := range msg.GetSigners() {
+		attrs.Add("message.signer", signer.String())
+	}
+	fields, ok := jsonObject(msg)
+	if !ok {
+		return
+	}
+	addScalarFields(attrs, "message", fields)
+}
+
+func addABCIEventAttributes(attrs query.Attributes, ev abci.Event) {
+	fields, ok := jsonObject(ev)
+	if !ok {
+		return
+	}
+	typ, _ := fields["type"].(string)
+	if typ == "" {
+		return
+	}
+	attrs.Add("event.type", typ)
+	if list, ok := fields["attrs"].([]any); ok {
+		for _, item := range list {
+			attr, _ := item.(map[string]any)
+			key, _ := attr["key"].(string)
+			if value, ok := scalarString(attr["value"]); ok && key != "" {
+				attrs.Add(typ+"."+key, value)
+			}
+		}
+	}
+	delete(fields, "type")
+	delete(fields, "attrs")
+	addScalarFields(attrs, typ, fields)
+}
+
+// addScalarFields adds the scalar fields of obj, flattening nested objects.
+// Arrays and the amino type field are skipped.
+func addScalarFields(attrs query.Attributes, prefix string, obj map[string]any) {
+	for key, value := range obj {
+		if key == "@type" {
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			addScalarFields(attrs, prefix+"."+key, nested)
+		} else if s, ok := scalarString(value); ok {
+			attrs.Add(prefix+"."+key, s)
+		}
+	}
+}
+
+// jsonObject returns the amino JSON encoding of v as a map,
+// or false if v is not encoded as a JSON object.
+func jsonObject(v any) (map[string]any, bool) {
+	bz, err := amino.MarshalJSONAny(v)
+	if err != nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+func scalarString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// eventTypeName returns the name of the type of ev, without its "Event" prefix.
+func eventTypeName(ev events.Event) string {
+	rt := reflect.TypeOf(ev)
+	if rt == nil {
+		return ""
+	}
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return strings.TrimPrefix(rt.Name(), "Event")
+}
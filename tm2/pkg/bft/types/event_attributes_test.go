@@ -0,0 +1,134 @@
+package types_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEvent is shaped like the events emitted by Gno realms.
+type testEvent struct {
+	Type    string          `json:"type"`
+	Attrs   []testEventAttr `json:"attrs"`
+	PkgPath string          `json:"pkg_path"`
+}
+
+type testEventAttr struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+var _ = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/types_test",
+	"types_test",
+	amino.GetCallersDirname(),
+).
+	WithDependencies(abci.Package).
+	WithTypes(
+		testEvent{},
+		testEventAttr{},
+	))
+
+func TestEventAttributes(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		event    types.TMEvent
+		expected query.Attributes
+	}{
+		{
+			"new block",
+			types.EventNewBlock{Block: &types.Block{Header: types.Header{Height: 3}}},
+			query.Attributes{"tm.event": {"NewBlock"}, "block.height": {"3"}},
+		},
+		{
+			"new block header",
+			types.EventNewBlockHeader{Header: types.Header{Height: 4}},
+			query.Attributes{"tm.event": {"NewBlockHeader"}, "block.height": {"4"}},
+		},
+		{
+			"vote",
+			types.EventVote{Vote: &types.Vote{Height: 5}},
+			query.Attributes{"tm.event": {"Vote"}, "block.height": {"5"}},
+		},
+		{
+			"string",
+			types.EventString("hello"),
+			query.Attributes{"tm.event": {"String"}},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, types.EventAttributes(testCase.event))
+		})
+	}
+}
+
+func TestTxResultAttributes(t *testing.T) {
+	t.Parallel()
+
+	from := crypto.AddressFromPreimage([]byte("from"))
+	to := crypto.AddressFromPreimage([]byte("to"))
+	tx := std.Tx{
+		Msgs: []std.Msg{bank.NewMsgSend(from, to, std.NewCoins(std.NewCoin("ugnot", 10)))},
+	}
+	bz, err := amino.Marshal(tx)
+	require.NoError(t, err)
+
+	res := types.TxResult{
+		Height: 7,
+		Index:  1,
+		Tx:     bz,
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: []abci.Event{
+					testEvent{
+						Type:    "Transfer",
+						Attrs:   []testEventAttr{{"to", "g1abc"}, {"to", "g1def"}},
+						PkgPath: "gno.land/r/demo/foo20",
+					},
+					abci.EventString("ignored"),
+				},
+			},
+		},
+	}
+
+	attrs := types.EventAttributes(types.EventTx{Result: res})
+	assert.Equal(t, query.Attributes{
+		"tm.event":             {"Tx"},
+		"block.height":         {"7"},
+		"tx.height":            {"7"},
+		"tx.index":             {"1"},
+		"tx.hash":              {fmt.Sprintf("%X", types.Tx(bz).Hash())},
+		"tx.success":           {"true"},
+		"message.type":         {"send"},
+		"message.route":        {"bank"},
+		"message.signer":       {from.String()},
+		"message.from_address": {from.String()},
+		"message.to_address":   {to.String()},
+		"message.amount":       {"10ugnot"},
+		"event.type":           {"Transfer"},
+		"Transfer.to":          {"g1abc", "g1def"},
+		"Transfer.pkg_path":    {"gno.land/r/demo/foo20"},
+	}, attrs)
+
+	q := query.MustParse("tm.event = 'Tx' AND message.type = 'send' AND Transfer.to = 'g1def'")
+	assert.True(t, q.Matches(attrs))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
@@ -11,8 +12,9 @@ import (
 )
 
 const (
-	// MaxEvidenceBytes is a maximum size of any evidence (including amino overhead).
-	MaxEvidenceBytes int64 = 484
+	// MaxEvidenceBytes is a maximum size of any evidence (including amino overhead),
+	// once included in a block.
+	MaxEvidenceBytes int64 = 579
 )
 
 // EvidenceInvalidError wraps a piece of evidence and the error denoting how or why it is invalid.
@@ -51,6 +53,9 @@ func (err *EvidenceOverflowError) Error() string {
 
 // Evidence represents any provable malicious activity by a validator
 type Evidence interface {
+	abci.Evidence
+	Height() int64                                     // height of the equivocation
+	Address() crypto.Address                           // address of the equivocating validator
 	Bytes() []byte                                     // bytes which compromise the evidence
 	Hash() []byte                                      // hash of the evidence
 	Verify(chainID string, pubKey crypto.PubKey) error // verify the evidence
@@ -94,7 +99,17 @@ func (dve *DuplicateVoteEvidence) String() string {
 	return fmt.Sprintf("VoteA: %v; VoteB: %v", dve.VoteA, dve.VoteB)
 }
 
-// Hash returns the hash of the evidence.
+// Height returns the height this evidence refers to.
+func (dve *DuplicateVoteEvidence) Height() int64 {
+	return dve.VoteA.Height
+}
+
+// Address returns the address of the validator.
+func (dve *DuplicateVoteEvidence) Address() crypto.Address {
+	return dve.PubKey.Address()
+}
+
+// Bytes returns the amino encoded bytes of the evidence.
 func (dve *DuplicateVoteEvidence) Bytes() []byte {
 	return bytesOrNil(dve)
 }
@@ -161,7 +176,7 @@ func (dve *DuplicateVoteEvidence) Equal(ev Evidence) bool {
 
 // ValidateBasic performs basic validation.
 func (dve *DuplicateVoteEvidence) ValidateBasic() error {
-	if len(dve.PubKey.Bytes()) == 0 {
+	if dve.PubKey == nil || len(dve.PubKey.Bytes()) == 0 {
 		return errors.New("Empty PubKey")
 	}
 	if dve.VoteA == nil || dve.VoteB == nil {
@@ -196,13 +211,13 @@ func NewMockRandomGoodEvidence(height int64, address crypto.Address, randBytes [
 func (e MockRandomGoodEvidence) AssertABCIEvidence() {}
 
 func (e MockRandomGoodEvidence) Hash() []byte {
-	return []byte(fmt.Sprintf("%d-%x", e.Height, e.randBytes))
+	return []byte(fmt.Sprintf("%d-%x", e.EvidenceHeight, e.randBytes))
 }
 
 // UNSTABLE
 type MockGoodEvidence struct {
-	Height  int64
-	Address crypto.Address
+	EvidenceHeight  int64
+	EvidenceAddress crypto.Address
 }
 
 var _ Evidence = &MockGoodEvidence{}
@@ -212,22 +227,27 @@ func NewMockGoodEvidence(height int64, idx int, address crypto.Address) MockGood
 	return MockGoodEvidence{height, address}
 }
 
-func (e MockGoodEvidence) AssertABCIEvidence() {}
+func (e MockGoodEvidence) AssertABCIEvidence()     {}
+func (e MockGoodEvidence) Height() int64           { return e.EvidenceHeight }
+func (e MockGoodEvidence) Address() crypto.Address { return e.EvidenceAddress }
 func (e MockGoodEvidence) Hash() []byte {
-	return []byte(fmt.Sprintf("%d-%x", e.Height, e.Address))
+	return []byte(fmt.Sprintf("%d-%x", e.EvidenceHeight, e.EvidenceAddress))
 }
 
 func (e MockGoodEvidence) Bytes() []byte {
-	return []byte(fmt.Sprintf("%d-%x", e.Height, e.Address))
+	return []byte(fmt.Sprintf("%d-%x", e.EvidenceHeight, e.EvidenceAddress))
 }
 func (e MockGoodEvidence) Verify(chainID string, pubKey crypto.PubKey) error { return nil }
 func (e MockGoodEvidence) Equal(ev Evidence) bool {
-	e2 := ev.(MockGoodEvidence)
-	return e.Height == e2.Height && e.Address == e2.Address
+	e2, ok := ev.(MockGoodEvidence)
+	if !ok {
+		return false
+	}
+	return e.EvidenceHeight == e2.EvidenceHeight && e.EvidenceAddress == e2.EvidenceAddress
 }
 func (e MockGoodEvidence) ValidateBasic() error { return nil }
 func (e MockGoodEvidence) String() string {
-	return fmt.Sprintf("GoodEvidence: %d/%s", e.Height, e.Address)
+	return fmt.Sprintf("GoodEvidence: %d/%s", e.EvidenceHeight, e.EvidenceAddress)
 }
 
 // UNSTABLE
@@ -242,12 +262,15 @@ func (e MockBadEvidence) Verify(chainID string, pubKey crypto.PubKey) error {
 }
 
 func (e MockBadEvidence) Equal(ev Evidence) bool {
-	e2 := ev.(MockBadEvidence)
-	return e.Height == e2.Height && e.Address == e2.Address
+	e2, ok := ev.(MockBadEvidence)
+	if !ok {
+		return false
+	}
+	return e.EvidenceHeight == e2.EvidenceHeight && e.EvidenceAddress == e2.EvidenceAddress
 }
 func (e MockBadEvidence) ValidateBasic() error { return nil }
 func (e MockBadEvidence) String() string {
-	return fmt.Sprintf("BadEvidence: %d/%s", e.Height, e.Address)
+	return fmt.Sprintf("BadEvidence: %d/%s", e.EvidenceHeight, e.EvidenceAddress)
 }
 
 //-------------------------------------------
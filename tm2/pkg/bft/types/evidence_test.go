@@ -121,6 +121,12 @@ func TestEvidenceByteSize(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.EqualValues(t, 548, len(bz))
+
+	// Once in a block, the evidence is wrapped in an Any.
+	bz, err = amino.Marshal(EvidenceData{Evidence: EvidenceList{ev}})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, MaxEvidenceBytes, len(bz))
 }
 
 func randomDuplicatedVoteEvidence() *DuplicateVoteEvidence {
@@ -148,6 +154,7 @@ func TestDuplicateVoteEvidenceValidation(t *testing.T) {
 		expectErr        bool
 	}{
 		{"Good DuplicateVoteEvidence", func(ev *DuplicateVoteEvidence) {}, false},
+		{"Nil PubKey", func(ev *DuplicateVoteEvidence) { ev.PubKey = nil }, true},
 		{"Nil vote A", func(ev *DuplicateVoteEvidence) { ev.VoteA = nil }, true},
 		{"Nil vote B", func(ev *DuplicateVoteEvidence) { ev.VoteB = nil }, true},
 		{"Nil votes", func(ev *DuplicateVoteEvidence) {
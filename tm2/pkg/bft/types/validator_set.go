@@ -758,6 +758,67 @@ func (vals *ValidatorSet) VerifyFutureCommit(newSet *ValidatorSet, chainID strin
 	return nil
 }
 
+// VerifyCommitTrusting verifies that more than trustNum/trustDenom of the
+// voting power of the set signed the given commit.
+//
+// Unlike VerifyCommit, the set doesn't have to be the one which signed the
+// commit: it is a set trusted by a light client, at a lower height. The
+// precommits of the validators which aren't in the set are ignored.
+//
+// This only checks the signatures of the set: the commit must also be
+// verified against the set which signed it with VerifyCommit.
+func (vals *ValidatorSet) VerifyCommitTrusting(chainID string, blockID BlockID,
+	height int64, commit *Commit, trustNum, trustDenom int64,
+) error {
+	if trustNum <= 0 || trustDenom <= 0 || trustNum > trustDenom {
+		return fmt.Errorf("invalid trust level %d/%d", trustNum, trustDenom)
+	}
+	if err := commit.ValidateBasic(); err != nil {
+		return err
+	}
+	if height != commit.Height() {
+		return NewErrInvalidCommitHeight(height, commit.Height())
+	}
+	if !blockID.Equals(commit.BlockID) {
+		return fmt.Errorf("invalid commit -- wrong block id: want %v got %v",
+			blockID, commit.BlockID)
+	}
+
+	talliedVotingPower := int64(0)
+	seen := map[int]bool{}
+
+	for idx, precommit := range commit.Precommits {
+		if precommit == nil {
+			continue
+		}
+		// See if this validator is in the trusted set.
+		valIdx, val := vals.GetByAddress(precommit.ValidatorAddress)
+		if val == nil || seen[valIdx] {
+			continue // missing or double vote...
+		}
+		seen[valIdx] = true
+
+		// Validate signature.
+		precommitSignBytes := commit.VoteSignBytes(chainID, idx)
+		if !val.PubKey.VerifyBytes(precommitSignBytes, precommit.Signature) {
+			return fmt.Errorf("invalid commit -- invalid signature: %v", precommit)
+		}
+		// Good precommit!
+		if blockID.Equals(precommit.BlockID) {
+			talliedVotingPower += val.VotingPower
+		}
+	}
+
+	// The total voting power is at most MaxTotalVotingPower, so this can
+	// overflow for large trust levels: use big integers.
+	needed := new(big.Int).Mul(big.NewInt(vals.TotalVotingPower()), big.NewInt(trustNum))
+	needed.Quo(needed, big.NewInt(trustDenom))
+	if big.NewInt(talliedVotingPower).Cmp(needed) <= 0 {
+		return tooMuchChangeError{talliedVotingPower, needed.Int64() + 1}
+	}
+	return nil
+}
+
 // -----------------
 // ErrTooMuchChange
 
@@ -10,6 +10,7 @@ import (
 	"testing/quick"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
@@ -661,6 +662,59 @@ func TestValidatorSetVerifyCommit(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestValidatorSetVerifyCommitTrusting(t *testing.T) {
+	t.Parallel()
+
+	chainID := "mychainID"
+	height := int64(5)
+	blockID := BlockID{Hash: []byte("hello")}
+	vals, privVals := RandValidatorSet(4, 10)
+	voteSet := NewVoteSet(chainID, height, 0, PrecommitType, vals)
+	commit, err := MakeCommit(blockID, height, 0, voteSet, privVals)
+	require.NoError(t, err)
+
+	otherVals, _ := RandValidatorSet(4, 10)
+	partialVals := NewValidatorSet([]*Validator{
+		vals.Validators[0].Copy(),
+		vals.Validators[1].Copy(),
+		otherVals.Validators[0].Copy(),
+		otherVals.Validators[1].Copy(),
+	})
+
+	cases := []struct {
+		name       string
+		vals       *ValidatorSet
+		trustNum   int64
+		trustDenom int64
+		valid      bool
+	}{
+		{"same set", vals, 1, 3, true},
+		{"same set, full trust", vals, 1, 1, false},
+		{"partial set", partialVals, 1, 3, true},
+		{"partial set, 2/3", partialVals, 2, 3, false},
+		{"other set", otherVals, 1, 3, false},
+		{"invalid trust level", vals, 4, 3, false},
+		{"zero trust level", vals, 0, 3, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.vals.VerifyCommitTrusting(chainID, blockID, height, commit, c.trustNum, c.trustDenom)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	err = vals.VerifyCommitTrusting(chainID, blockID, height+1, commit, 1, 3)
+	assert.Error(t, err)
+	err = vals.VerifyCommitTrusting(chainID, BlockID{Hash: []byte("goodbye")}, height, commit, 1, 3)
+	assert.Error(t, err)
+}
+
 func TestEmptySet(t *testing.T) {
 	t.Parallel()
 
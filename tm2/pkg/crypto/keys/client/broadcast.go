@@ -139,6 +139,10 @@ func SimulateTx(cli client.ABCIClient, tx []byte) (*ctypes.ResultBroadcastTxComm
 		return nil, errors.Wrap(err, "simulate tx")
 	}
 
+	if bres.Response.IsErr() {
+		return nil, errors.Wrap(bres.Response.Error, "simulate tx: log:%s", bres.Response.Log)
+	}
+
 	var result abci.ResponseDeliverTx
 	err = amino.Unmarshal(bres.Response.Value, &result)
 	if err != nil {
@@ -149,3 +153,55 @@ func SimulateTx(cli client.ABCIClient, tx []byte) (*ctypes.ResultBroadcastTxComm
 		DeliverTx: result,
 	}, nil
 }
+
+// SimulateStdTx executes tx on the node without committing it, and returns
+// the result, along with an error if the result has one. As signatures are
+// not verified in simulation, tx may be unsigned.
+func SimulateStdTx(cli client.ABCIClient, tx std.Tx) (*abci.ResponseDeliverTx, error) {
+	if len(tx.Signatures) == 0 {
+		tx.Signatures = make([]std.Signature, len(tx.GetSigners()))
+	}
+
+	bz, err := amino.Marshal(tx)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling tx binary bytes")
+	}
+
+	res, err := SimulateTx(cli, bz)
+	if err != nil {
+		return nil, err
+	}
+	if res.DeliverTx.IsErr() {
+		return &res.DeliverTx, errors.Wrap(res.DeliverTx.Error, "simulating transaction failed: log:%s", res.DeliverTx.Log)
+	}
+
+	return &res.DeliverTx, nil
+}
+
+// EstimateGas returns the gas used by simulating tx, like SimulateStdTx.
+func EstimateGas(cli client.ABCIClient, tx std.Tx) (int64, error) {
+	res, err := SimulateStdTx(cli, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.GasUsed, nil
+}
+
+// QueryMinGasPrices returns the minimum gas prices of the node,
+// which are empty if it accepts transactions without fees.
+func QueryMinGasPrices(cli client.ABCIClient) ([]std.GasPrice, error) {
+	qres, err := cli.ABCIQuery(".app/min_gas_prices", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "query min gas prices")
+	}
+	if qres.Response.IsErr() {
+		return nil, errors.Wrap(qres.Response.Error, "query min gas prices: log:%s", qres.Response.Log)
+	}
+
+	if len(qres.Response.Value) == 0 {
+		return nil, nil
+	}
+
+	return std.ParseGasPrices(string(qres.Response.Value))
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/base64"
 	"flag"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
@@ -20,6 +23,11 @@ type MakeTxCfg struct {
 	GasFee    string
 	Memo      string
 
+	// GasAuto is set by --gas-wanted auto. GasWanted is then estimated
+	// by simulating the transaction, and multiplied by GasMultiplier.
+	GasAuto       bool
+	GasMultiplier float64
+
 	Broadcast bool
 	// Valid options are SimulateTest, SimulateSkip or SimulateOnly.
 	Simulate string
@@ -33,6 +41,12 @@ const (
 	SimulateOnly = "only"
 )
 
+// GasWantedAuto is the value of --gas-wanted to estimate the gas
+// by simulating the transaction.
+const GasWantedAuto = "auto"
+
+const defaultGasMultiplier = 1.3
+
 func (c *MakeTxCfg) Validate() error {
 	switch c.Simulate {
 	case SimulateTest, SimulateSkip, SimulateOnly:
@@ -65,18 +79,24 @@ func NewMakeTxCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
 }
 
 func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
-	fs.Int64Var(
-		&c.GasWanted,
+	fs.Var(
+		gasWantedValue{&c.GasWanted, &c.GasAuto},
 		"gas-wanted",
-		0,
-		"gas requested for tx",
+		`gas requested for tx, or "auto" to estimate it by simulating the tx`,
+	)
+
+	fs.Float64Var(
+		&c.GasMultiplier,
+		"gas-multiplier",
+		defaultGasMultiplier,
+		"safety multiplier applied to the estimated gas (only useful with --gas-wanted auto)",
 	)
 
 	fs.StringVar(
 		&c.GasFee,
 		"gas-fee",
 		"",
-		"gas payment fee",
+		"gas payment fee; with --gas-wanted auto, it defaults to the minimum gas price of the node",
 	)
 
 	fs.StringVar(
@@ -225,3 +245,103 @@ func ExecSignAndBroadcast(
 
 	return nil
 }
+
+// ResolveFee returns the fee of tx, as set by the flags of cfg.
+// With --gas-wanted auto, the gas wanted is estimated by simulating tx on
+// the remote node, and the gas fee is derived from its minimum gas price,
+// unless --gas-fee is set.
+func ResolveFee(cfg *MakeTxCfg, tx std.Tx) (std.Fee, error) {
+	if !cfg.GasAuto {
+		gasfee, err := std.ParseCoin(cfg.GasFee)
+		if err != nil {
+			return std.Fee{}, errors.Wrap(err, "parsing gas fee coin")
+		}
+
+		return std.NewFee(cfg.GasWanted, gasfee), nil
+	}
+
+	if cfg.GasMultiplier < 1 {
+		return std.Fee{}, fmt.Errorf("invalid gas multiplier: %v, should be at least 1", cfg.GasMultiplier)
+	}
+
+	remote := cfg.RootCfg.Remote
+	if remote == "" {
+		return std.Fee{}, errors.New("missing remote url")
+	}
+
+	cli, err := client.NewHTTPClient(remote)
+	if err != nil {
+		return std.Fee{}, err
+	}
+
+	var (
+		gasfee   std.Coin
+		gasPrice *std.GasPrice
+	)
+
+	if cfg.GasFee != "" {
+		gasfee, err = std.ParseCoin(cfg.GasFee)
+		if err != nil {
+			return std.Fee{}, errors.Wrap(err, "parsing gas fee coin")
+		}
+	} else {
+		gasPrices, err := QueryMinGasPrices(cli)
+		if err != nil {
+			return std.Fee{}, err
+		}
+		if len(gasPrices) == 0 {
+			return std.Fee{}, errors.New("gas-fee not specified, and the node has no minimum gas prices")
+		}
+
+		// The fee is not known before the simulation,
+		// which is run without paying for the gas
+		gasPrice = &gasPrices[0]
+		gasfee = std.Coin{Denom: gasPrice.Price.Denom}
+	}
+
+	tx.Fee = std.NewFee(0, gasfee)
+	gasUsed, err := EstimateGas(cli, tx)
+	if err != nil {
+		return std.Fee{}, err
+	}
+
+	gaswanted := int64(math.Ceil(float64(gasUsed) * cfg.GasMultiplier))
+	if gasPrice != nil {
+		gasfee = gasPrice.Fee(gaswanted)
+	}
+
+	return std.NewFee(gaswanted, gasfee), nil
+}
+
+// gasWantedValue is the flag.Value of --gas-wanted,
+// which is either an amount of gas or GasWantedAuto.
+type gasWantedValue struct {
+	gasWanted *int64
+	auto      *bool
+}
+
+func (v gasWantedValue) String() string {
+	switch {
+	case v.auto == nil:
+		return ""
+	case *v.auto:
+		return GasWantedAuto
+	default:
+		return strconv.FormatInt(*v.gasWanted, 10)
+	}
+}
+
+func (v gasWantedValue) Set(s string) error {
+	if s == GasWantedAuto {
+		*v.gasWanted, *v.auto = 0, true
+		return nil
+	}
+
+	gasWanted, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid gas wanted %q, should be an integer or %q", s, GasWantedAuto)
+	}
+
+	*v.gasWanted, *v.auto = gasWanted, false
+	return nil
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type MultisignCfg struct {
+	RootCfg *BaseCfg
+
+	TxPath        string
+	ChainID       string
+	AccountNumber uint64
+	Sequence      uint64
+	Signatures    commands.StringArr
+}
+
+// NewMultisignCmd creates a gnokey multisign command
+func NewMultisignCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
+	cfg := &MultisignCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "multisign",
+			ShortUsage: "multisign [flags] <multisig key-name or address>",
+			ShortHelp:  "merges partial signatures into the multisig signature of the tx",
+			LongHelp: "Merges the partial signatures generated with `sign --multisig` into the multisig " +
+				"signature of the given tx document, and saves it to disk. " +
+				"The partial signatures must meet the multisig threshold",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMultisign(cfg, args, io)
+		},
+	)
+}
+
+func (c *MultisignCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.TxPath,
+		"tx-path",
+		"",
+		"path to the Amino JSON-encoded tx (file) to sign",
+	)
+
+	fs.StringVar(
+		&c.ChainID,
+		"chainid",
+		"dev",
+		"the ID of the chain",
+	)
+
+	fs.Uint64Var(
+		&c.AccountNumber,
+		"account-number",
+		0,
+		"account number of the multisig account",
+	)
+
+	fs.Uint64Var(
+		&c.Sequence,
+		"account-sequence",
+		0,
+		"account sequence of the multisig account",
+	)
+
+	fs.Var(
+		&c.Signatures,
+		"signature",
+		"path to a partial signature (file) to merge; can be specified multiple times",
+	)
+}
+
+func execMultisign(cfg *MultisignCfg, args []string, io commands.IO) error {
+	// Make sure the multisig key name is provided
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	if len(cfg.Signatures) == 0 {
+		return errMissingSignatures
+	}
+
+	// Load the keybase
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
+	if err != nil {
+		return fmt.Errorf("unable to load keybase, %w", err)
+	}
+
+	// Fetch the multisig key from the keybase
+	info, err := kb.GetByNameOrAddress(args[0])
+	if err != nil {
+		return fmt.Errorf("unable to get key from keybase, %w", err)
+	}
+
+	multisigPub, err := getMultisigPubKey(info)
+	if err != nil {
+		return err
+	}
+
+	// Load the transaction
+	tx, err := loadTx(cfg.TxPath)
+	if err != nil {
+		return err
+	}
+
+	signBytes, err := tx.GetSignBytes(
+		cfg.ChainID,
+		cfg.AccountNumber,
+		cfg.Sequence,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to get signature bytes, %w", err)
+	}
+
+	// Merge the partial signatures, making sure they are valid
+	multisignature := multisig.NewMultisig(len(multisigPub.PubKeys))
+
+	for _, path := range cfg.Signatures {
+		sig, err := loadPartialSignature(path)
+		if err != nil {
+			return err
+		}
+
+		if !sig.PubKey.VerifyBytes(signBytes, sig.Signature) {
+			return fmt.Errorf("%w from %s in %s", errInvalidSignature, sig.PubKey.Address(), path)
+		}
+
+		if err := multisignature.AddSignatureFromPubKey(
+			sig.Signature,
+			sig.PubKey,
+			multisigPub.PubKeys,
+		); err != nil {
+			return fmt.Errorf("%w: %s, %w", errNotMultisigSigner, path, err)
+		}
+	}
+
+	// Make sure the multisig threshold is reached
+	if signed := len(multisignature.Sigs); signed < int(multisigPub.K) {
+		return fmt.Errorf(
+			"%w: %d of %d required signatures",
+			errThresholdNotReached,
+			signed,
+			multisigPub.K,
+		)
+	}
+
+	// Save the multisig signature
+	addSignature(&tx, std.Signature{
+		PubKey:    multisigPub,
+		Signature: multisignature.Marshal(),
+	})
+
+	// Validate the tx after signing
+	if err := tx.ValidateBasic(); err != nil {
+		return fmt.Errorf("unable to validate transaction, %w", err)
+	}
+
+	return saveTx(&tx, cfg.TxPath, io)
+}
+
+// loadPartialSignature loads the multisig partial signature
+// from the given path (Amino-encoded JSON)
+func loadPartialSignature(path string) (std.Signature, error) {
+	sigRaw, err := os.ReadFile(path)
+	if err != nil {
+		return std.Signature{}, fmt.Errorf("unable to read signature file %s, %w", path, err)
+	}
+
+	var sig std.Signature
+	if err := amino.UnmarshalJSON(sigRaw, &sig); err != nil {
+		return std.Signature{}, fmt.Errorf("unable to unmarshal signature %s, %w", path, err)
+	}
+
+	if sig.PubKey == nil || len(sig.Signature) == 0 {
+		return std.Signature{}, fmt.Errorf("%w: %s", errInvalidSignature, path)
+	}
+
+	return sig, nil
+}
@@ -0,0 +1,298 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	multisigKeyName  = "multisig-key"
+	multisigPassword = "encrypt"
+)
+
+// multisigTestEnv is a keybase with a 2-of-3 multisig key,
+// and a tx to be signed by it
+type multisigTestEnv struct {
+	kbHome      string
+	signerNames []string
+	multisigPub multisig.PubKeyMultisigThreshold
+	txPath      string
+}
+
+func newMultisigTestEnv(t *testing.T) *multisigTestEnv {
+	t.Helper()
+
+	env := &multisigTestEnv{
+		kbHome:      t.TempDir(),
+		signerNames: []string{"key-1", "key-2", "key-3"},
+	}
+
+	kb, err := keys.NewKeyBaseFromDir(env.kbHome)
+	require.NoError(t, err)
+
+	// Generate the signer keys
+	pubs := make([]crypto.PubKey, 0, len(env.signerNames))
+	for _, name := range env.signerNames {
+		info, err := kb.CreateAccount(name, generateTestMnemonic(t), "", multisigPassword, 0, 0)
+		require.NoError(t, err)
+
+		pubs = append(pubs, info.GetPubKey())
+	}
+
+	// Generate the multisig key
+	env.multisigPub = multisig.NewPubKeyMultisigThreshold(2, pubs).(multisig.PubKeyMultisigThreshold)
+	_, err = kb.CreateMulti(multisigKeyName, env.multisigPub)
+	require.NoError(t, err)
+
+	// Save a tx sent from the multisig account
+	tx := std.Tx{
+		Msgs: []std.Msg{
+			bank.MsgSend{
+				FromAddress: env.multisigPub.Address(),
+				ToAddress:   pubs[0].Address(),
+				Amount:      std.NewCoins(std.NewCoin("ugnot", 10)),
+			},
+		},
+		Fee: std.Fee{
+			GasWanted: 10,
+			GasFee:    std.NewCoin("ugnot", 10),
+		},
+	}
+
+	encodedTx, err := amino.MarshalJSON(tx)
+	require.NoError(t, err)
+
+	env.txPath = filepath.Join(t.TempDir(), "tx.json")
+	require.NoError(t, os.WriteFile(env.txPath, encodedTx, 0o644))
+
+	return env
+}
+
+// run runs the gnokey command with the given args and input
+func (env *multisigTestEnv) run(t *testing.T, input string, args ...string) (string, error) {
+	t.Helper()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+
+	var out strings.Builder
+
+	io := commands.NewTestIO()
+	io.SetIn(strings.NewReader(input))
+	io.SetOut(commands.WriteNopCloser(&out))
+
+	cmd := NewRootCmdWithBaseConfig(io, BaseOptions{
+		InsecurePasswordStdin: true,
+		Home:                  env.kbHome,
+		Quiet:                 true,
+	})
+
+	// The base flags follow the subcommand name
+	cmdArgs := append([]string{args[0], "--home", env.kbHome, "--insecure-password-stdin"}, args[1:]...)
+	err := cmd.ParseAndRun(ctx, cmdArgs)
+
+	return out.String(), err
+}
+
+// signPartial generates the partial signature of the given signer,
+// and returns its path
+func (env *multisigTestEnv) signPartial(t *testing.T, signerName string) string {
+	t.Helper()
+
+	sigPath := filepath.Join(t.TempDir(), signerName+".sig")
+
+	_, err := env.run(
+		t,
+		fmt.Sprintf("%s\n", multisigPassword),
+		"sign",
+		"--tx-path", env.txPath,
+		"--multisig", multisigKeyName,
+		"--output-document", sigPath,
+		signerName,
+	)
+	require.NoError(t, err)
+
+	return sigPath
+}
+
+func TestMultisign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid multisig signature", func(t *testing.T) {
+		t.Parallel()
+
+		env := newMultisigTestEnv(t)
+
+		// Generate the partial signatures,
+		// which don't modify the tx
+		sig1 := env.signPartial(t, env.signerNames[0])
+		sig3 := env.signPartial(t, env.signerNames[2])
+
+		tx, err := loadTx(env.txPath)
+		require.NoError(t, err)
+		assert.Empty(t, tx.Signatures)
+
+		// Merge the partial signatures
+		_, err = env.run(
+			t,
+			"",
+			"multisign",
+			"--tx-path", env.txPath,
+			"--signature", sig1,
+			"--signature", sig3,
+			multisigKeyName,
+		)
+		require.NoError(t, err)
+
+		// Make sure the tx is signed by the multisig key
+		tx, err = loadTx(env.txPath)
+		require.NoError(t, err)
+		require.Len(t, tx.Signatures, 1)
+		assert.True(t, tx.Signatures[0].PubKey.Equals(env.multisigPub))
+
+		signBytes, err := tx.GetSignBytes("dev", 0, 0)
+		require.NoError(t, err)
+		assert.True(t, env.multisigPub.VerifyBytes(signBytes, tx.Signatures[0].Signature))
+
+		// Verify the tx signature, and its signers
+		out, err := env.run(
+			t,
+			"",
+			"verify",
+			"--tx-path", env.txPath,
+			multisigKeyName,
+		)
+		require.NoError(t, err)
+
+		assert.Contains(t, out, "Multisig threshold: 2 of 3 signers")
+		assert.Contains(t, out, fmt.Sprintf("(%s): signed", env.signerNames[0]))
+		assert.Contains(t, out, fmt.Sprintf("(%s): not signed", env.signerNames[1]))
+		assert.Contains(t, out, fmt.Sprintf("(%s): signed", env.signerNames[2]))
+		assert.Contains(t, out, "2 of 2 required signatures")
+		assert.Contains(t, out, "Valid signature!")
+
+		// The signature is for the given account number
+		_, err = env.run(
+			t,
+			"",
+			"verify",
+			"--tx-path", env.txPath,
+			"--account-number", "1",
+			multisigKeyName,
+		)
+		assert.ErrorIs(t, err, errThresholdNotReached)
+	})
+
+	t.Run("threshold not reached", func(t *testing.T) {
+		t.Parallel()
+
+		env := newMultisigTestEnv(t)
+		sig1 := env.signPartial(t, env.signerNames[0])
+
+		_, err := env.run(
+			t,
+			"",
+			"multisign",
+			"--tx-path", env.txPath,
+			"--signature", sig1,
+			"--signature", sig1,
+			multisigKeyName,
+		)
+		assert.ErrorIs(t, err, errThresholdNotReached)
+	})
+
+	t.Run("no signatures", func(t *testing.T) {
+		t.Parallel()
+
+		env := newMultisigTestEnv(t)
+
+		_, err := env.run(
+			t,
+			"",
+			"multisign",
+			"--tx-path", env.txPath,
+			multisigKeyName,
+		)
+		assert.ErrorIs(t, err, errMissingSignatures)
+	})
+
+	t.Run("invalid partial signature", func(t *testing.T) {
+		t.Parallel()
+
+		env := newMultisigTestEnv(t)
+		sig1 := env.signPartial(t, env.signerNames[0])
+		sig2 := env.signPartial(t, env.signerNames[1])
+
+		// Change the tx after it was signed
+		tx, err := loadTx(env.txPath)
+		require.NoError(t, err)
+
+		tx.Fee.GasWanted = 20
+		require.NoError(t, saveTx(&tx, env.txPath, commands.NewTestIO()))
+
+		_, err = env.run(
+			t,
+			"",
+			"multisign",
+			"--tx-path", env.txPath,
+			"--signature", sig1,
+			"--signature", sig2,
+			multisigKeyName,
+		)
+		assert.ErrorIs(t, err, errInvalidSignature)
+	})
+
+	t.Run("not a multisig key", func(t *testing.T) {
+		t.Parallel()
+
+		env := newMultisigTestEnv(t)
+		sig1 := env.signPartial(t, env.signerNames[0])
+
+		_, err := env.run(
+			t,
+			"",
+			"multisign",
+			"--tx-path", env.txPath,
+			"--signature", sig1,
+			env.signerNames[0],
+		)
+		assert.ErrorIs(t, err, errNotMultisig)
+	})
+
+	t.Run("not a multisig signer", func(t *testing.T) {
+		t.Parallel()
+
+		env := newMultisigTestEnv(t)
+
+		kb, err := keys.NewKeyBaseFromDir(env.kbHome)
+		require.NoError(t, err)
+
+		_, err = kb.CreateAccount("other-key", generateTestMnemonic(t), "", multisigPassword, 0, 0)
+		require.NoError(t, err)
+
+		_, err = env.run(
+			t,
+			fmt.Sprintf("%s\n", multisigPassword),
+			"sign",
+			"--tx-path", env.txPath,
+			"--multisig", multisigKeyName,
+			"other-key",
+		)
+		assert.ErrorIs(t, err, errNotMultisigSigner)
+	})
+}
@@ -59,10 +59,10 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 		return flag.ErrHelp
 	}
 
-	if cfg.RootCfg.GasWanted == 0 {
+	if cfg.RootCfg.GasWanted == 0 && !cfg.RootCfg.GasAuto {
 		return errors.New("gas-wanted not specified")
 	}
-	if cfg.RootCfg.GasFee == "" {
+	if cfg.RootCfg.GasFee == "" && !cfg.RootCfg.GasAuto {
 		return errors.New("gas-fee not specified")
 	}
 	if cfg.Send == "" {
@@ -97,13 +97,6 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 		return errors.Wrap(err, "parsing send coins")
 	}
 
-	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
-	if err != nil {
-		return errors.Wrap(err, "parsing gas fee coin")
-	}
-
 	// construct msg & tx and marshal.
 	msg := bank.MsgSend{
 		FromAddress: fromAddr,
@@ -112,11 +105,16 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 	}
 	tx := std.Tx{
 		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
 		Signatures: nil,
 		Memo:       cfg.RootCfg.Memo,
 	}
 
+	// resolve gas wanted & fee.
+	tx.Fee, err = ResolveFee(cfg.RootCfg, tx)
+	if err != nil {
+		return err
+	}
+
 	if cfg.RootCfg.Broadcast {
 		err := ExecSignAndBroadcast(cfg.RootCfg, args, tx, io)
 		if err != nil {
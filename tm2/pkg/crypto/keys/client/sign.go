@@ -8,12 +8,21 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
-var errInvalidTxFile = errors.New("invalid transaction file")
+var (
+	errInvalidTxFile       = errors.New("invalid transaction file")
+	errNotMultisig         = errors.New("key is not a multisig key")
+	errNotMultisigSigner   = errors.New("key is not a signer of the multisig key")
+	errInvalidSignature    = errors.New("invalid signature")
+	errMissingSignatures   = errors.New("no signatures provided")
+	errThresholdNotReached = errors.New("multisig threshold not reached")
+)
 
 type signOpts struct {
 	chainID         string
@@ -34,6 +43,9 @@ type SignCfg struct {
 	AccountNumber uint64
 	Sequence      uint64
 	NameOrBech32  string
+
+	Multisig       string
+	OutputDocument string
 }
 
 func NewSignCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
@@ -82,6 +94,20 @@ func (c *SignCfg) RegisterFlags(fs *flag.FlagSet) {
 		0,
 		"account sequence to sign with",
 	)
+
+	fs.StringVar(
+		&c.Multisig,
+		"multisig",
+		"",
+		"name or address of the multisig key to sign for; outputs a partial signature instead of signing the tx",
+	)
+
+	fs.StringVar(
+		&c.OutputDocument,
+		"output-document",
+		"",
+		"path to save the partial multisig signature to (stdout if empty)",
+	)
 }
 
 func execSign(cfg *SignCfg, args []string, io commands.IO) error {
@@ -90,24 +116,6 @@ func execSign(cfg *SignCfg, args []string, io commands.IO) error {
 		return flag.ErrHelp
 	}
 
-	// saveTx saves the given transaction to the given path (Amino-encoded JSON)
-	saveTx := func(tx *std.Tx, path string) error {
-		// Encode the transaction
-		encodedTx, err := amino.MarshalJSON(tx)
-		if err != nil {
-			return fmt.Errorf("unable ot marshal tx to JSON, %w", err)
-		}
-
-		// Save the transaction
-		if err := os.WriteFile(path, encodedTx, 0o644); err != nil {
-			return fmt.Errorf("unable to write tx to %s, %w", path, err)
-		}
-
-		io.Printf("\nTx successfully signed and saved to %s\n", path)
-
-		return nil
-	}
-
 	// Load the keybase
 	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
 	if err != nil {
@@ -120,21 +128,27 @@ func execSign(cfg *SignCfg, args []string, io commands.IO) error {
 		return fmt.Errorf("unable to get key from keybase, %w", err)
 	}
 
-	// Get the transaction bytes
-	txRaw, err := os.ReadFile(cfg.TxPath)
+	// Load the transaction
+	tx, err := loadTx(cfg.TxPath)
 	if err != nil {
-		return fmt.Errorf("unable to read transaction file")
+		return err
 	}
 
-	// Make sure there is something to actually sign
-	if len(txRaw) == 0 {
-		return errInvalidTxFile
-	}
+	// Fetch the multisig key the signature is for, if any
+	var multisigPub multisig.PubKeyMultisigThreshold
+	if cfg.Multisig != "" {
+		multisigInfo, err := kb.GetByNameOrAddress(cfg.Multisig)
+		if err != nil {
+			return fmt.Errorf("unable to get multisig key from keybase, %w", err)
+		}
 
-	// Make sure the tx is valid Amino JSON
-	var tx std.Tx
-	if err := amino.UnmarshalJSON(txRaw, &tx); err != nil {
-		return fmt.Errorf("unable to unmarshal transaction, %w", err)
+		if multisigPub, err = getMultisigPubKey(multisigInfo); err != nil {
+			return err
+		}
+
+		if !isMultisigSigner(multisigPub, info.GetPubKey()) {
+			return errNotMultisigSigner
+		}
 	}
 
 	var password string
@@ -169,12 +183,109 @@ func execSign(cfg *SignCfg, args []string, io commands.IO) error {
 		decryptPass: password,
 	}
 
+	// Sign the transaction for the multisig, without modifying it
+	if cfg.Multisig != "" {
+		sig, err := generateSignature(&tx, kb, sOpts, kOpts)
+		if err != nil {
+			return fmt.Errorf("unable to sign transaction, %w", err)
+		}
+
+		return savePartialSignature(sig, cfg.OutputDocument, io)
+	}
+
 	// Sign the transaction
 	if err := signTx(&tx, kb, sOpts, kOpts); err != nil {
 		return fmt.Errorf("unable to sign transaction, %w", err)
 	}
 
-	return saveTx(&tx, cfg.TxPath)
+	return saveTx(&tx, cfg.TxPath, io)
+}
+
+// loadTx loads the transaction from the given path (Amino-encoded JSON)
+func loadTx(path string) (std.Tx, error) {
+	// Get the transaction bytes
+	txRaw, err := os.ReadFile(path)
+	if err != nil {
+		return std.Tx{}, fmt.Errorf("unable to read transaction file")
+	}
+
+	// Make sure there is something to actually sign
+	if len(txRaw) == 0 {
+		return std.Tx{}, errInvalidTxFile
+	}
+
+	// Make sure the tx is valid Amino JSON
+	var tx std.Tx
+	if err := amino.UnmarshalJSON(txRaw, &tx); err != nil {
+		return std.Tx{}, fmt.Errorf("unable to unmarshal transaction, %w", err)
+	}
+
+	return tx, nil
+}
+
+// saveTx saves the given transaction to the given path (Amino-encoded JSON)
+func saveTx(tx *std.Tx, path string, io commands.IO) error {
+	// Encode the transaction
+	encodedTx, err := amino.MarshalJSON(tx)
+	if err != nil {
+		return fmt.Errorf("unable ot marshal tx to JSON, %w", err)
+	}
+
+	// Save the transaction
+	if err := os.WriteFile(path, encodedTx, 0o644); err != nil {
+		return fmt.Errorf("unable to write tx to %s, %w", path, err)
+	}
+
+	io.Printf("\nTx successfully signed and saved to %s\n", path)
+
+	return nil
+}
+
+// savePartialSignature saves the given multisig partial signature to the
+// given path (Amino-encoded JSON), or prints it if the path is empty
+func savePartialSignature(sig std.Signature, path string, io commands.IO) error {
+	// Encode the signature
+	encodedSig, err := amino.MarshalJSON(sig)
+	if err != nil {
+		return fmt.Errorf("unable to marshal signature to JSON, %w", err)
+	}
+
+	if path == "" {
+		io.Println(string(encodedSig))
+
+		return nil
+	}
+
+	// Save the signature
+	if err := os.WriteFile(path, encodedSig, 0o644); err != nil {
+		return fmt.Errorf("unable to write signature to %s, %w", path, err)
+	}
+
+	io.Printf("\nPartial signature successfully saved to %s\n", path)
+
+	return nil
+}
+
+// getMultisigPubKey returns the multisig public key of the given key
+func getMultisigPubKey(info keys.Info) (multisig.PubKeyMultisigThreshold, error) {
+	pub, ok := info.GetPubKey().(multisig.PubKeyMultisigThreshold)
+	if !ok {
+		return multisig.PubKeyMultisigThreshold{}, fmt.Errorf("%w: %s", errNotMultisig, info.GetName())
+	}
+
+	return pub, nil
+}
+
+// isMultisigSigner returns true if the given key is one of
+// the multisig signers
+func isMultisigSigner(multisigPub multisig.PubKeyMultisigThreshold, pub crypto.PubKey) bool {
+	for _, signer := range multisigPub.PubKeys {
+		if signer.Equals(pub) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // signTx generates the transaction signature,
@@ -185,13 +296,39 @@ func signTx(
 	signOpts signOpts,
 	keyOpts keyOpts,
 ) error {
+	sig, err := generateSignature(tx, kb, signOpts, keyOpts)
+	if err != nil {
+		return err
+	}
+
+	// Save the signature
+	if overwritten := addSignature(tx, sig); overwritten {
+		return nil
+	}
+
+	// Validate the tx after signing
+	if err := tx.ValidateBasic(); err != nil {
+		return fmt.Errorf("unable to validate transaction, %w", err)
+	}
+
+	return nil
+}
+
+// generateSignature generates the transaction signature
+// with the given key, without saving it to the transaction
+func generateSignature(
+	tx *std.Tx,
+	kb keys.Keybase,
+	signOpts signOpts,
+	keyOpts keyOpts,
+) (std.Signature, error) {
 	signBytes, err := tx.GetSignBytes(
 		signOpts.chainID,
 		signOpts.accountNumber,
 		signOpts.accountSequence,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to get signature bytes, %w", err)
+		return std.Signature{}, fmt.Errorf("unable to get signature bytes, %w", err)
 	}
 
 	// Sign the transaction data
@@ -201,42 +338,38 @@ func signTx(
 		signBytes,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to sign transaction bytes, %w", err)
+		return std.Signature{}, fmt.Errorf("unable to sign transaction bytes, %w", err)
 	}
 
-	// Save the signature
+	return std.Signature{
+		PubKey:    pub,
+		Signature: sig,
+	}, nil
+}
+
+// addSignature saves the signature to the given transaction,
+// overwriting the existing signature of the same key, if any.
+// Returns true if a signature was overwritten
+func addSignature(tx *std.Tx, sig std.Signature) bool {
 	if tx.Signatures == nil {
 		tx.Signatures = make([]std.Signature, 0, 1)
 	}
 
 	// Check if the signature needs to be overwritten
 	for index, signature := range tx.Signatures {
-		if !signature.PubKey.Equals(pub) {
+		if !signature.PubKey.Equals(sig.PubKey) {
 			continue
 		}
 
 		// Save the signature
-		tx.Signatures[index] = std.Signature{
-			PubKey:    pub,
-			Signature: sig,
-		}
+		tx.Signatures[index] = sig
 
-		return nil
+		return true
 	}
 
 	// Append the signature, since it wasn't
 	// present before
-	tx.Signatures = append(
-		tx.Signatures, std.Signature{
-			PubKey:    pub,
-			Signature: sig,
-		},
-	)
+	tx.Signatures = append(tx.Signatures, sig)
 
-	// Validate the tx after signing
-	if err := tx.ValidateBasic(); err != nil {
-		return fmt.Errorf("unable to validate transaction, %w", err)
-	}
-
-	return nil
+	return false
 }
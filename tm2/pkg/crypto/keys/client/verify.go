@@ -4,16 +4,25 @@ import (
 	"context"
 	"encoding/hex"
 	"flag"
+	"fmt"
 	"os"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 type VerifyCfg struct {
 	RootCfg *BaseCfg
 
 	DocPath string
+
+	TxPath        string
+	ChainID       string
+	AccountNumber uint64
+	Sequence      uint64
 }
 
 func NewVerifyCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
@@ -26,6 +35,9 @@ func NewVerifyCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
 			Name:       "verify",
 			ShortUsage: "verify [flags] <key-name> <signature>",
 			ShortHelp:  "verifies the document signature",
+			LongHelp: "Verifies the signature of the given document. " +
+				"With --tx-path, verifies the signature of the key in the given tx document instead, " +
+				"and reports which signers have signed if the key is a multisig key",
 		},
 		cfg,
 		func(_ context.Context, args []string) error {
@@ -41,6 +53,34 @@ func (c *VerifyCfg) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"path of document file to verify",
 	)
+
+	fs.StringVar(
+		&c.TxPath,
+		"tx-path",
+		"",
+		"path to the Amino JSON-encoded tx (file) to verify",
+	)
+
+	fs.StringVar(
+		&c.ChainID,
+		"chainid",
+		"dev",
+		"the ID of the chain, when verifying a tx",
+	)
+
+	fs.Uint64Var(
+		&c.AccountNumber,
+		"account-number",
+		0,
+		"account number of the signer, when verifying a tx",
+	)
+
+	fs.Uint64Var(
+		&c.Sequence,
+		"account-sequence",
+		0,
+		"account sequence of the signer, when verifying a tx",
+	)
 }
 
 func execVerify(cfg *VerifyCfg, args []string, io commands.IO) error {
@@ -49,6 +89,10 @@ func execVerify(cfg *VerifyCfg, args []string, io commands.IO) error {
 		err error
 	)
 
+	if cfg.TxPath != "" {
+		return execVerifyTx(cfg, args, io)
+	}
+
 	if len(args) != 2 {
 		return flag.ErrHelp
 	}
@@ -92,6 +136,121 @@ func execVerify(cfg *VerifyCfg, args []string, io commands.IO) error {
 	return err
 }
 
+// execVerifyTx verifies the signature of the key in the tx
+func execVerifyTx(cfg *VerifyCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
+	if err != nil {
+		return err
+	}
+
+	info, err := kb.GetByNameOrAddress(args[0])
+	if err != nil {
+		return err
+	}
+
+	tx, err := loadTx(cfg.TxPath)
+	if err != nil {
+		return err
+	}
+
+	signBytes, err := tx.GetSignBytes(
+		cfg.ChainID,
+		cfg.AccountNumber,
+		cfg.Sequence,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to get signature bytes, %w", err)
+	}
+
+	// Find the signature of the key
+	var sig *std.Signature
+	for i := range tx.Signatures {
+		if tx.Signatures[i].PubKey != nil && tx.Signatures[i].PubKey.Equals(info.GetPubKey()) {
+			sig = &tx.Signatures[i]
+
+			break
+		}
+	}
+
+	if sig == nil {
+		return fmt.Errorf("no signature of %s in the tx", info.GetName())
+	}
+
+	// Report the multisig signers
+	if multisigPub, ok := info.GetPubKey().(multisig.PubKeyMultisigThreshold); ok {
+		if err := printMultisigSigners(kb, multisigPub, sig.Signature, signBytes, io); err != nil {
+			return err
+		}
+	}
+
+	if !info.GetPubKey().VerifyBytes(signBytes, sig.Signature) {
+		return errInvalidSignature
+	}
+
+	io.Println("Valid signature!")
+
+	return nil
+}
+
+// printMultisigSigners prints which signers of the multisig have signed,
+// and whether their signature is valid
+func printMultisigSigners(
+	kb keys.Keybase,
+	multisigPub multisig.PubKeyMultisigThreshold,
+	sigBytes []byte,
+	signBytes []byte,
+	io commands.IO,
+) error {
+	var multisignature multisig.Multisignature
+	if err := amino.Unmarshal(sigBytes, &multisignature); err != nil {
+		return fmt.Errorf("unable to unmarshal multisig signature, %w", err)
+	}
+
+	size := len(multisigPub.PubKeys)
+	if multisignature.BitArray == nil || multisignature.BitArray.Size() != size {
+		return fmt.Errorf("%w: expected %d signers", errInvalidSignature, size)
+	}
+
+	signed := 0
+	io.Printfln("Multisig threshold: %d of %d signers", multisigPub.K, size)
+
+	for i, pub := range multisigPub.PubKeys {
+		// Use the key name, if the signer is in the keybase
+		signer := pub.Address().String()
+		if signerInfo, err := kb.GetByAddress(pub.Address()); err == nil {
+			signer = fmt.Sprintf("%s (%s)", signer, signerInfo.GetName())
+		}
+
+		status := "not signed"
+		if multisignature.BitArray.GetIndex(i) {
+			sigIndex := multisignature.BitArray.NumTrueBitsBefore(i)
+
+			switch {
+			case sigIndex >= len(multisignature.Sigs),
+				!pub.VerifyBytes(signBytes, multisignature.Sigs[sigIndex]):
+				status = "invalid signature"
+			default:
+				status = "signed"
+				signed++
+			}
+		}
+
+		io.Printfln("  %s: %s", signer, status)
+	}
+
+	io.Printfln("%d of %d required signatures", signed, multisigPub.K)
+
+	if signed < int(multisigPub.K) {
+		return errThresholdNotReached
+	}
+
+	return nil
+}
+
 func parseSignature(sigstr string) ([]byte, error) {
 	sig, err := hex.DecodeString(sigstr)
 	if err != nil {
@@ -0,0 +1,297 @@
+// Package query implements a small query language to filter events by their
+// attributes.
+//
+// A query is a list of conditions joined by AND. Each condition compares the
+// values of an attribute with an operand, which is either a single-quoted
+// string or a number:
+//
+//	tm.event = 'Tx' AND tx.height >= 10 AND Transfer.to = 'g1...'
+//
+// The supported operators are =, !=, <, <=, >, >= (numbers only), CONTAINS
+// (strings only) and EXISTS, which takes no operand. An attribute may have
+// several values; a condition holds if any of them satisfies it, except for
+// != which holds if none of them is equal to the operand.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attributes maps attribute keys to their values.
+type Attributes map[string][]string
+
+// Add appends value to the values of key.
+func (attrs Attributes) Add(key, value string) {
+	attrs[key] = append(attrs[key], value)
+}
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpEqual        Op = "="
+	OpNotEqual     Op = "!="
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+	OpContains     Op = "CONTAINS"
+	OpExists       Op = "EXISTS"
+)
+
+// Condition is a single comparison of a query.
+type Condition struct {
+	Key     string
+	Op      Op
+	Operand string // unquoted
+	Number  bool   // whether Operand is a number
+}
+
+func (c Condition) String() string {
+	switch {
+	case c.Op == OpExists:
+		return c.Key + " " + string(c.Op)
+	case c.Number:
+		return c.Key + " " + string(c.Op) + " " + c.Operand
+	default:
+		quoted := "'" + strings.ReplaceAll(c.Operand, "'", `\'`) + "'"
+		return c.Key + " " + string(c.Op) + " " + quoted
+	}
+}
+
+// Query is a parsed query.
+type Query struct {
+	Conditions []Condition
+}
+
+// Parse parses a query string.
+// The empty query matches all events.
+func Parse(s string) (*Query, error) {
+	p := &parser{s: s}
+	q := new(Query)
+	p.skipSpace()
+	if p.eof() {
+		return q, nil
+	}
+	for {
+		c, err := p.condition()
+		if err != nil {
+			return nil, fmt.Errorf("invalid query %q: %w", s, err)
+		}
+		q.Conditions = append(q.Conditions, c)
+		p.skipSpace()
+		if p.eof() {
+			return q, nil
+		}
+		if !p.keyword("AND") {
+			return nil, fmt.Errorf("invalid query %q: expected AND at offset %d", s, p.pos)
+		}
+	}
+}
+
+// MustParse is like Parse, but panics on error.
+func MustParse(s string) *Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// String returns the canonical form of q.
+func (q *Query) String() string {
+	parts := make([]string, len(q.Conditions))
+	for i, c := range q.Conditions {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// Matches returns true if attrs satisfies all conditions of q.
+func (q *Query) Matches(attrs Attributes) bool {
+	for _, c := range q.Conditions {
+		if !c.Matches(attrs[c.Key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches returns true if values satisfies c.
+func (c Condition) Matches(values []string) bool {
+	switch c.Op {
+	case OpExists:
+		return len(values) > 0
+	case OpNotEqual:
+		for _, v := range values {
+			if c.equal(v) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range values {
+		if c.match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Condition) equal(v string) bool {
+	if c.Number {
+		x, ok := parseNumber(v)
+		y, _ := parseNumber(c.Operand)
+		return ok && x == y
+	}
+	return v == c.Operand
+}
+
+func (c Condition) match(v string) bool {
+	switch c.Op {
+	case OpEqual:
+		return c.equal(v)
+	case OpContains:
+		return strings.Contains(v, c.Operand)
+	}
+	x, ok := parseNumber(v)
+	if !ok {
+		return false
+	}
+	y, _ := parseNumber(c.Operand)
+	switch c.Op {
+	case OpLess:
+		return x < y
+	case OpLessEqual:
+		return x <= y
+	case OpGreater:
+		return x > y
+	case OpGreaterEqual:
+		return x >= y
+	default:
+		panic(fmt.Sprintf("unexpected operator %s", c.Op))
+	}
+}
+
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+// ----------------------------------------
+// parser
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// keyword consumes kw if it is the next word.
+func (p *parser) keyword(kw string) bool {
+	end := p.pos + len(kw)
+	if end > len(p.s) || !strings.EqualFold(p.s[p.pos:end], kw) {
+		return false
+	}
+	if end < len(p.s) && isKeyChar(p.s[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func isKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '.' || c == '_' || c == '-' || c == '/' || c == '@'
+}
+
+func (p *parser) condition() (c Condition, err error) {
+	p.skipSpace()
+	start := p.pos
+	for !p.eof() && isKeyChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return c, fmt.Errorf("expected attribute key at offset %d", start)
+	}
+	c.Key = p.s[start:p.pos]
+
+	p.skipSpace()
+	switch {
+	case p.keyword(string(OpExists)):
+		c.Op = OpExists
+		return c, nil
+	case p.keyword(string(OpContains)):
+		c.Op = OpContains
+	default:
+		for _, op := range []Op{OpNotEqual, OpLessEqual, OpGreaterEqual, OpEqual, OpLess, OpGreater} {
+			if strings.HasPrefix(p.s[p.pos:], string(op)) {
+				c.Op = op
+				p.pos += len(op)
+				break
+			}
+		}
+		if c.Op == "" {
+			return c, fmt.Errorf("expected operator at offset %d", p.pos)
+		}
+	}
+
+	p.skipSpace()
+	if !p.eof() && p.s[p.pos] == '\'' {
+		c.Operand, err = p.quoted()
+		if err != nil {
+			return c, err
+		}
+	} else {
+		start := p.pos
+		for !p.eof() && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		c.Operand = p.s[start:p.pos]
+		if _, ok := parseNumber(c.Operand); !ok {
+			return c, fmt.Errorf("expected quoted string or number at offset %d", start)
+		}
+		c.Number = true
+	}
+
+	switch {
+	case c.Op == OpContains && c.Number:
+		return c, fmt.Errorf("CONTAINS expects a string operand")
+	case c.Op != OpEqual && c.Op != OpNotEqual && c.Op != OpContains && !c.Number:
+		return c, fmt.Errorf("%s expects a number operand", c.Op)
+	}
+	return c, nil
+}
+
+// quoted reads a single-quoted string, where \' escapes a quote.
+func (p *parser) quoted() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+	var sb strings.Builder
+	for !p.eof() {
+		ch := p.s[p.pos]
+		switch {
+		case ch == '\\' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '\'':
+			sb.WriteByte('\'')
+			p.pos += 2
+		case ch == '\'':
+			p.pos++
+			return sb.String(), nil
+		default:
+			sb.WriteByte(ch)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string at offset %d", start)
+}
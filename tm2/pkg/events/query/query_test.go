@@ -0,0 +1,96 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		query  string
+		output string
+		err    bool
+	}{
+		{"empty", "  ", "", false},
+		{"equal string", "tm.event='Tx'", "tm.event = 'Tx'", false},
+		{"equal number", "tx.height = 10", "tx.height = 10", false},
+		{"and", "tm.event = 'Tx' and tx.height >= 1.5", "tm.event = 'Tx' AND tx.height >= 1.5", false},
+		{"contains", "Transfer.to CONTAINS 'g1'", "Transfer.to CONTAINS 'g1'", false},
+		{"exists", "Transfer.to EXISTS AND a != 'b'", "Transfer.to EXISTS AND a != 'b'", false},
+		{"escaped quote", `a = 'it\'s'`, `a = 'it\'s'`, false},
+		{"missing key", "= 'Tx'", "", true},
+		{"missing operator", "tm.event 'Tx'", "", true},
+		{"missing operand", "tm.event =", "", true},
+		{"unquoted string", "tm.event = Tx", "", true},
+		{"unterminated string", "tm.event = 'Tx", "", true},
+		{"ordering a string", "tm.event < 'Tx'", "", true},
+		{"contains a number", "a CONTAINS 1", "", true},
+		{"missing and", "a = 1 b = 2", "", true},
+		{"trailing and", "a = 1 AND", "", true},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse(testCase.query)
+			if testCase.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.output, q.String())
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	attrs := Attributes{
+		"tm.event":    {"Tx"},
+		"tx.height":   {"42"},
+		"Transfer.to": {"g1abc", "g1def"},
+	}
+
+	testTable := []struct {
+		query   string
+		matches bool
+	}{
+		{"", true},
+		{"tm.event = 'Tx'", true},
+		{"tm.event = 'NewBlock'", false},
+		{"tm.event != 'NewBlock'", true},
+		{"tx.height = 42", true},
+		{"tx.height = 42.0", true},
+		{"tx.height > 41 AND tx.height <= 42", true},
+		{"tx.height < 42", false},
+		{"tx.height >= 43", false},
+		{"tm.event > 1", false},
+		{"Transfer.to = 'g1def'", true},
+		{"Transfer.to != 'g1def'", false},
+		{"Transfer.to CONTAINS 'de'", true},
+		{"Transfer.to EXISTS", true},
+		{"Transfer.from EXISTS", false},
+		{"Transfer.from = 'g1abc'", false},
+		{"tm.event = 'Tx' AND Transfer.to = 'g1xyz'", false},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			q := MustParse(testCase.query)
+			assert.Equal(t, testCase.matches, q.Matches(attrs))
+		})
+	}
+}
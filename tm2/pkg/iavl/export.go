@@ -0,0 +1,140 @@
+package iavl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// importBatchSize is the number of imported nodes after which the node
+// batch is flushed to the database.
+const importBatchSize = 10000
+
+// ExportNode is a node of an exported tree. Leaf nodes have a height of 0,
+// and inner nodes have no value.
+type ExportNode struct {
+	Key     []byte
+	Value   []byte
+	Version int64
+	Height  int8
+}
+
+// Export calls fn for every node of the tree, in depth-first post-order
+// (left child, right child, parent). This allows an Importer to rebuild a
+// tree with the same structure, and thus the same hash.
+func (t *ImmutableTree) Export(fn func(ExportNode) error) error {
+	if t.root == nil {
+		return nil
+	}
+	return t.root.export(t, fn)
+}
+
+func (node *Node) export(t *ImmutableTree, fn func(ExportNode) error) error {
+	if !node.isLeaf() {
+		if err := node.getLeftNode(t).export(t, fn); err != nil {
+			return err
+		}
+		if err := node.getRightNode(t).export(t, fn); err != nil {
+			return err
+		}
+	}
+	return fn(ExportNode{
+		Key:     node.key,
+		Value:   node.value,
+		Version: node.version,
+		Height:  node.height,
+	})
+}
+
+// Importer rebuilds a tree from the nodes of an ImmutableTree.Export, and
+// saves it as a single version of an empty MutableTree.
+type Importer struct {
+	tree    *MutableTree
+	version int64
+	stack   []*Node
+	batched int
+}
+
+// Import returns an Importer which saves the imported tree at the given
+// version. The tree must be empty.
+func (tree *MutableTree) Import(version int64) (*Importer, error) {
+	if version <= 0 {
+		return nil, fmt.Errorf("imported version must be positive, got %d", version)
+	}
+	if latest := tree.ndb.getLatestVersion(); latest > 0 {
+		return nil, fmt.Errorf("found database at version %d, can only import into an empty tree", latest)
+	}
+	return &Importer{
+		tree:    tree,
+		version: version,
+	}, nil
+}
+
+// Add adds the next exported node to the tree. Nodes must be added in the
+// order they were exported.
+func (i *Importer) Add(exported ExportNode) error {
+	if exported.Version > i.version {
+		return fmt.Errorf("node version %d can't be greater than import version %d",
+			exported.Version, i.version)
+	}
+	if exported.Height < 0 {
+		return fmt.Errorf("node height can't be negative, got %d", exported.Height)
+	}
+
+	node := &Node{
+		key:     exported.Key,
+		value:   exported.Value,
+		version: exported.Version,
+		height:  exported.Height,
+		size:    1,
+	}
+
+	// An inner node comes right after its two children, which are the
+	// last two nodes on the stack.
+	if !node.isLeaf() {
+		n := len(i.stack)
+		if n < 2 {
+			return errors.New("inner node without children")
+		}
+		left, right := i.stack[n-2], i.stack[n-1]
+		if left.height >= node.height || right.height >= node.height {
+			return fmt.Errorf("inner node at height %d has children at heights %d and %d",
+				node.height, left.height, right.height)
+		}
+		node.leftHash = left.hash
+		node.rightHash = right.hash
+		node.size = left.size + right.size
+		i.stack = i.stack[:n-2]
+	} else if exported.Value == nil {
+		return errors.New("leaf node without value")
+	}
+
+	node._hash()
+	i.tree.ndb.SaveNode(node)
+	i.stack = append(i.stack, node)
+
+	i.batched++
+	if i.batched >= importBatchSize {
+		i.tree.ndb.Commit()
+		i.batched = 0
+	}
+	return nil
+}
+
+// Commit saves the imported tree as the importer's version, and loads it.
+func (i *Importer) Commit() error {
+	var hash []byte
+	switch len(i.stack) {
+	case 0:
+		hash = []byte{}
+	case 1:
+		hash = i.stack[0].hash
+	default:
+		return fmt.Errorf("invalid import: %d unconnected nodes remaining", len(i.stack))
+	}
+
+	i.tree.ndb.importRoot(hash, i.version)
+	i.tree.ndb.Commit()
+
+	_, err := i.tree.LoadVersion(i.version)
+	return err
+}
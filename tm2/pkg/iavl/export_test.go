@@ -0,0 +1,146 @@
+package iavl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+// setupExportTree returns a tree with a few versions, with keys being set,
+// updated and removed across versions.
+func setupExportTree(t *testing.T) *MutableTree {
+	t.Helper()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+	for version := 0; version < 3; version++ {
+		for i := 0; i < 100; i++ {
+			switch {
+			case i%7 == version:
+				tree.Remove(i2b(i))
+			default:
+				tree.Set(i2b(i), randBytes(8))
+			}
+		}
+		_, _, err := tree.SaveVersion()
+		require.NoError(t, err)
+	}
+	return tree
+}
+
+func exportTree(t *testing.T, tree *MutableTree, version int64) []ExportNode {
+	t.Helper()
+
+	itree, err := tree.GetImmutable(version)
+	require.NoError(t, err)
+
+	var nodes []ExportNode
+	err = itree.Export(func(node ExportNode) error {
+		nodes = append(nodes, node)
+		return nil
+	})
+	require.NoError(t, err)
+	return nodes
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	tree := setupExportTree(t)
+
+	for version := int64(1); version <= tree.Version(); version++ {
+		itree, err := tree.GetImmutable(version)
+		require.NoError(t, err)
+
+		nodes := exportTree(t, tree, version)
+		require.NotEmpty(t, nodes)
+
+		newTree := NewMutableTree(memdb.NewMemDB(), 0)
+		importer, err := newTree.Import(version)
+		require.NoError(t, err)
+		for _, node := range nodes {
+			require.NoError(t, importer.Add(node))
+		}
+		require.NoError(t, importer.Commit())
+
+		// The imported tree is the same as the exported one.
+		assert.Equal(t, version, newTree.Version())
+		assert.Equal(t, itree.Hash(), newTree.Hash())
+		assert.Equal(t, itree.Size(), newTree.Size())
+		itree.Iterate(func(key, value []byte) bool {
+			_, v := newTree.Get(key)
+			assert.Equal(t, value, v)
+			return false
+		})
+
+		// And can be further modified.
+		newTree.Set([]byte("new"), []byte("value"))
+		_, newVersion, err := newTree.SaveVersion()
+		require.NoError(t, err)
+		assert.Equal(t, version+1, newVersion)
+	}
+}
+
+func TestExportImportEmpty(t *testing.T) {
+	t.Parallel()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+	_, _, err := tree.SaveVersion()
+	require.NoError(t, err)
+	assert.Empty(t, exportTree(t, tree, 1))
+
+	newTree := NewMutableTree(memdb.NewMemDB(), 0)
+	importer, err := newTree.Import(1)
+	require.NoError(t, err)
+	require.NoError(t, importer.Commit())
+	assert.Equal(t, int64(1), newTree.Version())
+	assert.Equal(t, tree.Hash(), newTree.Hash())
+}
+
+func TestImportErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		tree := setupExportTree(t)
+		_, err := tree.Import(10)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewMutableTree(memdb.NewMemDB(), 0).Import(0)
+		assert.Error(t, err)
+	})
+
+	t.Run("node version too high", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(1)
+		require.NoError(t, err)
+		assert.Error(t, importer.Add(ExportNode{Key: []byte("a"), Value: []byte("b"), Version: 2}))
+	})
+
+	t.Run("inner node without children", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(1)
+		require.NoError(t, err)
+		require.NoError(t, importer.Add(ExportNode{Key: []byte("a"), Value: []byte("b"), Version: 1}))
+		assert.Error(t, importer.Add(ExportNode{Key: []byte("b"), Version: 1, Height: 1}))
+	})
+
+	t.Run("unconnected nodes", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(1)
+		require.NoError(t, err)
+		require.NoError(t, importer.Add(ExportNode{Key: []byte("a"), Value: []byte("b"), Version: 1}))
+		require.NoError(t, importer.Add(ExportNode{Key: []byte("b"), Value: []byte("c"), Version: 1}))
+		assert.Error(t, importer.Commit())
+	})
+}
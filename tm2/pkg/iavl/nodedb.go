@@ -385,6 +385,17 @@ func (ndb *nodeDB) saveRoot(hash []byte, version int64) error {
 	return nil
 }
 
+// importRoot saves the root of an imported tree. Unlike saveRoot, the version
+// doesn't need to follow the latest one, as the tree is restored from a
+// snapshot taken at an arbitrary version.
+func (ndb *nodeDB) importRoot(hash []byte, version int64) {
+	ndb.mtx.Lock()
+	defer ndb.mtx.Unlock()
+
+	ndb.batch.Set(ndb.rootKey(version), hash)
+	ndb.updateLatestVersion(version)
+}
+
 // ----------- Utility and test functions // -----------
 
 func (ndb *nodeDB) leafNodes() []*Node {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/errors"
@@ -16,6 +17,8 @@ const (
 	FuzzModeDelay
 )
 
+var defaultAddrBookPath = filepath.Join("config", "addrbook.json")
+
 // P2PConfig defines the configuration options for the Tendermint peer-to-peer networking layer
 type P2PConfig struct {
 	RootDir string `json:"rpc" toml:"home"`
@@ -35,6 +38,13 @@ type P2PConfig struct {
 	// UPNP port forwarding
 	UPNP bool `json:"upnp" toml:"upnp" comment:"UPNP port forwarding"`
 
+	// Path to the address book
+	AddrBook string `json:"addr_book_file" toml:"addr_book_file" comment:"Path to address book"`
+
+	// Set true for strict address routability rules
+	// Set false for private or local networks
+	AddrBookStrict bool `json:"addr_book_strict" toml:"addr_book_strict" comment:"Set true for strict address routability rules\n Set false for private or local networks"`
+
 	// Maximum number of inbound peers
 	MaxNumInboundPeers int `json:"max_num_inbound_peers" toml:"max_num_inbound_peers" comment:"Maximum number of inbound peers"`
 
@@ -87,6 +97,8 @@ func DefaultP2PConfig() *P2PConfig {
 		ListenAddress:           "tcp://0.0.0.0:26656",
 		ExternalAddress:         "",
 		UPNP:                    false,
+		AddrBook:                defaultAddrBookPath,
+		AddrBookStrict:          true,
 		MaxNumInboundPeers:      40,
 		MaxNumOutboundPeers:     10,
 		FlushThrottleTimeout:    100 * time.Millisecond,
@@ -110,9 +122,15 @@ func TestP2PConfig() *P2PConfig {
 	cfg.ListenAddress = "tcp://0.0.0.0:26656"
 	cfg.FlushThrottleTimeout = 10 * time.Millisecond
 	cfg.AllowDuplicateIP = true
+	cfg.AddrBookStrict = false
 	return cfg
 }
 
+// AddrBookFile returns the full path to the address book
+func (cfg *P2PConfig) AddrBookFile() string {
+	return filepath.Join(cfg.RootDir, cfg.AddrBook)
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *P2PConfig) ValidateBasic() error {
@@ -0,0 +1,483 @@
+package pex
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/random"
+)
+
+func newTestAddrBook(t *testing.T, routabilityStrict bool) (*addrBook, string) {
+	t.Helper()
+
+	fname := filepath.Join(t.TempDir(), "addrbook.json")
+	book := NewAddrBook(fname, routabilityStrict).(*addrBook)
+	book.SetLogger(log.NewNoopLogger())
+	return book, fname
+}
+
+func TestAddrBookPickAddress(t *testing.T) {
+	t.Parallel()
+
+	book, _ := newTestAddrBook(t, true)
+	assert.Zero(t, book.Size())
+
+	addr := book.PickAddress(50)
+	assert.Nil(t, addr, "expected no address")
+
+	randAddrs := randNetAddressPairs(t, 1)
+	addrSrc := randAddrs[0]
+	require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+
+	// pick an address when we only have new address
+	addr = book.PickAddress(0)
+	assert.NotNil(t, addr, "expected an address")
+	addr = book.PickAddress(50)
+	assert.NotNil(t, addr, "expected an address")
+	addr = book.PickAddress(100)
+	assert.NotNil(t, addr, "expected an address")
+
+	// pick an address when we only have old address
+	book.MarkGood(addrSrc.addr.ID)
+	addr = book.PickAddress(0)
+	assert.NotNil(t, addr, "expected an address")
+	addr = book.PickAddress(50)
+	assert.NotNil(t, addr, "expected an address")
+
+	// in this case, nNew==0 but we biased 100% to new, so we return nil
+	addr = book.PickAddress(100)
+	assert.Nil(t, addr, "did not expected an address")
+}
+
+func TestAddrBookSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	book, fname := newTestAddrBook(t, true)
+
+	// 0 addresses
+	book.Save()
+
+	book = NewAddrBook(fname, true).(*addrBook)
+	book.SetLogger(log.NewNoopLogger())
+	require.NoError(t, book.loadFromFile(fname))
+	assert.True(t, book.Empty())
+
+	// 100 addresses
+	randAddrs := randNetAddressPairs(t, 100)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+	book.MarkGood(randAddrs[0].addr.ID)
+
+	assert.Equal(t, 100, book.Size())
+	book.Save()
+
+	loaded := NewAddrBook(fname, true).(*addrBook)
+	loaded.SetLogger(log.NewNoopLogger())
+	require.NoError(t, loaded.loadFromFile(fname))
+
+	assert.Equal(t, 100, loaded.Size())
+	assert.Equal(t, book.key, loaded.key)
+	assert.Equal(t, book.nOld, loaded.nOld)
+	assert.Equal(t, book.nNew, loaded.nNew)
+	assert.True(t, loaded.IsGood(randAddrs[0].addr))
+	for _, addrSrc := range randAddrs {
+		assert.True(t, loaded.HasAddress(addrSrc.addr))
+	}
+}
+
+func TestAddrBookLoadInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", "{"},
+		{"nil address", `{"key": "", "addrs": [{"bucket_type": 1}]}`},
+		{
+			"bucket out of range",
+			`{"key": "", "addrs": [{"addr": {"id": "g1", "ip": "1.2.3.4", "port": 1}, "bucket_type": 1, "buckets": [1000]}]}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			book, fname := newTestAddrBook(t, true)
+			require.NoError(t, os.WriteFile(fname, []byte(testCase.content), 0o644))
+
+			assert.Error(t, book.Start())
+		})
+	}
+}
+
+func TestAddrBookLookup(t *testing.T) {
+	t.Parallel()
+
+	randAddrs := randNetAddressPairs(t, 100)
+
+	book, _ := newTestAddrBook(t, true)
+	for _, addrSrc := range randAddrs {
+		addr := addrSrc.addr
+		src := addrSrc.src
+		require.NoError(t, book.AddAddress(addr, src))
+
+		ka := book.addrLookup[addr.ID]
+		require.NotNil(t, ka, "Expected to find KnownAddress %v but wasn't there.", addr)
+
+		if !(ka.Addr.Equals(addr) && ka.Src.Equals(src)) {
+			t.Fatalf("KnownAddress doesn't match addr & src")
+		}
+	}
+}
+
+func TestAddrBookPromoteToOld(t *testing.T) {
+	t.Parallel()
+
+	randAddrs := randNetAddressPairs(t, 100)
+
+	book, _ := newTestAddrBook(t, true)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+
+	// Attempt all addresses.
+	for _, addrSrc := range randAddrs {
+		book.MarkAttempt(addrSrc.addr)
+	}
+
+	// Promote half of them
+	for i, addrSrc := range randAddrs {
+		if i%2 == 0 {
+			book.MarkGood(addrSrc.addr.ID)
+		}
+	}
+
+	selection := book.GetSelection()
+	assert.NotEmpty(t, selection)
+
+	assert.Equal(t, 50, book.nOld)
+	assert.Equal(t, 50, book.nNew)
+	assert.Equal(t, 100, book.Size())
+
+	for i, addrSrc := range randAddrs {
+		assert.Equal(t, i%2 == 0, book.IsGood(addrSrc.addr))
+	}
+}
+
+func TestAddrBookHandlesDuplicates(t *testing.T) {
+	t.Parallel()
+
+	book, _ := newTestAddrBook(t, true)
+
+	randAddrs := randNetAddressPairs(t, 100)
+
+	differentSrc := randIPv4Address(t)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))  // duplicate
+		require.NoError(t, book.AddAddress(addrSrc.addr, differentSrc)) // different src
+	}
+
+	assert.Equal(t, 100, book.Size())
+}
+
+func TestAddrBookRemoveAddress(t *testing.T) {
+	t.Parallel()
+
+	book, _ := newTestAddrBook(t, true)
+
+	addr := randIPv4Address(t)
+	require.NoError(t, book.AddAddress(addr, addr))
+	assert.Equal(t, 1, book.Size())
+
+	book.RemoveAddress(addr)
+	assert.Equal(t, 0, book.Size())
+
+	nonExistingAddr := randIPv4Address(t)
+	book.RemoveAddress(nonExistingAddr)
+	assert.Equal(t, 0, book.Size())
+}
+
+func TestAddrBookMarkBad(t *testing.T) {
+	t.Parallel()
+
+	book, _ := newTestAddrBook(t, true)
+
+	addr := randIPv4Address(t)
+	require.NoError(t, book.AddAddress(addr, addr))
+	book.MarkBad(addr)
+
+	assert.False(t, book.HasAddress(addr))
+}
+
+func TestAddrBookGetSelection(t *testing.T) {
+	t.Parallel()
+
+	book, _ := newTestAddrBook(t, true)
+
+	// 1) empty book
+	assert.Empty(t, book.GetSelection())
+
+	// 2) add one address
+	addr := randIPv4Address(t)
+	require.NoError(t, book.AddAddress(addr, addr))
+
+	assert.Equal(t, 1, len(book.GetSelection()))
+	assert.Equal(t, addr, book.GetSelection()[0])
+
+	// 3) add a bunch of addresses
+	randAddrs := randNetAddressPairs(t, 100)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+
+	// check there is no duplicates
+	addrs := make(map[string]*p2p.NetAddress)
+	selection := book.GetSelection()
+	for _, addr := range selection {
+		if dup, ok := addrs[addr.String()]; ok {
+			t.Fatalf("selection %v contains duplicates %v", selection, dup)
+		}
+		addrs[addr.String()] = addr
+	}
+
+	if len(selection) > book.Size() {
+		t.Errorf("selection %v could not be bigger than the book", selection)
+	}
+}
+
+func TestAddrBookGetSelectionWithBias(t *testing.T) {
+	t.Parallel()
+
+	const biasTowardsNewAddrs = 30
+
+	book, _ := newTestAddrBook(t, true)
+
+	// 1) empty book
+	selection := book.GetSelectionWithBias(biasTowardsNewAddrs)
+	assert.Empty(t, selection)
+
+	// 2) add one address
+	addr := randIPv4Address(t)
+	require.NoError(t, book.AddAddress(addr, addr))
+
+	selection = book.GetSelectionWithBias(biasTowardsNewAddrs)
+	assert.Equal(t, 1, len(selection))
+	assert.Equal(t, addr, selection[0])
+
+	// 3) add a bunch of addresses
+	randAddrs := randNetAddressPairs(t, 100)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+
+	// check there is no duplicates
+	addrs := make(map[string]*p2p.NetAddress)
+	selection = book.GetSelectionWithBias(biasTowardsNewAddrs)
+	for _, addr := range selection {
+		if dup, ok := addrs[addr.String()]; ok {
+			t.Fatalf("selection %v contains duplicates %v", selection, dup)
+		}
+		addrs[addr.String()] = addr
+	}
+
+	if len(selection) > book.Size() {
+		t.Fatalf("selection %v could not be bigger than the book", selection)
+	}
+
+	// 4) mark 80% of the addresses as good
+	randAddrsLen := len(randAddrs)
+	for i, addrSrc := range randAddrs {
+		if int((float64(i)/float64(randAddrsLen))*100) >= 20 {
+			book.MarkGood(addrSrc.addr.ID)
+		}
+	}
+
+	selection = book.GetSelectionWithBias(biasTowardsNewAddrs)
+
+	// check that ~70% of addresses returned are good
+	good := 0
+	for _, addr := range selection {
+		if book.IsGood(addr) {
+			good++
+		}
+	}
+
+	got, expected := int((float64(good)/float64(len(selection)))*100), 100-biasTowardsNewAddrs
+
+	// compute some slack to protect against small differences due to rounding:
+	slack := int(math.Round(float64(100) / float64(len(selection))))
+	if got > expected+slack {
+		t.Fatalf(
+			"got more good peers (%% got: %d, %% expected: %d, number of good addrs: %d, total: %d)",
+			got, expected, good, len(selection),
+		)
+	}
+	if got < expected-slack {
+		t.Fatalf(
+			"got fewer good peers (%% got: %d, %% expected: %d, number of good addrs: %d, total: %d)",
+			got, expected, good, len(selection),
+		)
+	}
+}
+
+func TestAddrBookRejectedAddresses(t *testing.T) {
+	t.Parallel()
+
+	t.Run("private ids", func(t *testing.T) {
+		t.Parallel()
+
+		book, _ := newTestAddrBook(t, true)
+
+		addrs := make([]*p2p.NetAddress, 10)
+		ids := make([]string, 0, len(addrs))
+		for i := range addrs {
+			addrs[i] = randIPv4Address(t)
+			ids = append(ids, addrs[i].ID.String())
+		}
+		book.AddPrivateIDs(ids)
+
+		for _, addr := range addrs {
+			err := book.AddAddress(addr, randIPv4Address(t))
+			assert.IsType(t, ErrAddrBookPrivate{}, err)
+
+			// peers coming from a private peer are rejected as well
+			err = book.AddAddress(randIPv4Address(t), addr)
+			assert.IsType(t, ErrAddrBookPrivateSrc{}, err)
+		}
+		assert.True(t, book.Empty())
+	})
+
+	t.Run("our address", func(t *testing.T) {
+		t.Parallel()
+
+		book, _ := newTestAddrBook(t, true)
+
+		addr := randIPv4Address(t)
+		book.AddOurAddress(addr)
+		assert.True(t, book.OurAddress(addr))
+
+		err := book.AddAddress(addr, addr)
+		assert.IsType(t, ErrAddrBookSelf{}, err)
+		assert.True(t, book.Empty())
+	})
+
+	t.Run("non-routable", func(t *testing.T) {
+		t.Parallel()
+
+		id := randID()
+		addr := p2p.NewNetAddressFromIPPort(id, net.ParseIP("127.0.0.1"), 26656)
+
+		strict, _ := newTestAddrBook(t, true)
+		assert.IsType(t, ErrAddrBookNonRoutable{}, strict.AddAddress(addr, addr))
+
+		lax, _ := newTestAddrBook(t, false)
+		assert.NoError(t, lax.AddAddress(addr, addr))
+	})
+
+	t.Run("nil address", func(t *testing.T) {
+		t.Parallel()
+
+		book, _ := newTestAddrBook(t, true)
+
+		assert.IsType(t, ErrAddrBookNilAddr{}, book.AddAddress(nil, randIPv4Address(t)))
+		assert.IsType(t, ErrAddrBookNilAddr{}, book.AddAddress(randIPv4Address(t), nil))
+	})
+}
+
+func TestAddrBookGroupKey(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		ip     string
+		expKey string
+	}{
+		// IPv4 normal.
+		{"ipv4 normal class a", "12.1.2.3", "12.1.0.0/16"},
+		{"ipv4 normal class b", "173.1.2.3", "173.1.0.0/16"},
+		{"ipv4 normal class c", "196.1.2.3", "196.1.0.0/16"},
+
+		// IPv6/IPv4 translations.
+		{"ipv6 rfc3964 with ipv4 encap", "2002:0c01:0203::", "12.1.0.0/16"},
+		{"ipv6 rfc4380 toredo ipv4", "2001:0:1234::f3fe:fdfc", "12.1.0.0/16"},
+		{"ipv6 rfc6052 well-known prefix with ipv4", "64:ff9b::0c01:0203", "12.1.0.0/16"},
+		{"ipv6 rfc6145 translated ipv4", "::ffff:0:0c01:0203", "12.1.0.0/16"},
+
+		// Tor.
+		{"ipv6 tor onioncat", "fd87:d87e:eb43:1234::5678", "fd87:d87e::/32"},
+
+		// IPv6 normal.
+		{"ipv6 normal", "2602:100::1", "2602:100::/32"},
+		{"ipv6 he.net", "2001:470:1f10:a1::2", "2001:470:1000::/36"},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			book, _ := newTestAddrBook(t, false)
+
+			nip := net.ParseIP(testCase.ip)
+			key := book.groupKey(p2p.NewNetAddressFromIPPort("", nip, 26656))
+			assert.Equal(t, testCase.expKey, key)
+		})
+	}
+}
+
+type netAddressPair struct {
+	addr *p2p.NetAddress
+	src  *p2p.NetAddress
+}
+
+func randNetAddressPairs(t *testing.T, n int) []netAddressPair {
+	t.Helper()
+
+	randAddrs := make([]netAddressPair, n)
+	for i := 0; i < n; i++ {
+		randAddrs[i] = netAddressPair{addr: randIPv4Address(t), src: randIPv4Address(t)}
+	}
+	return randAddrs
+}
+
+func randIPv4Address(t *testing.T) *p2p.NetAddress {
+	t.Helper()
+
+	for {
+		ip := fmt.Sprintf("%v.%v.%v.%v",
+			random.RandIntn(254)+1,
+			random.RandIntn(255),
+			random.RandIntn(255),
+			random.RandIntn(255),
+		)
+		port := random.RandIntn(65535-1) + 1
+		id := randID()
+		idAddr := p2p.NetAddressString(id, fmt.Sprintf("%v:%v", ip, port))
+		addr, err := p2p.NewNetAddressFromString(idAddr)
+		require.NoError(t, err)
+		if addr.Routable() {
+			return addr
+		}
+	}
+}
+
+func randID() p2p.ID {
+	return ed25519.GenPrivKey().PubKey().Address().ID()
+}
@@ -0,0 +1,87 @@
+package pex
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+var (
+	errNilAddress      = errors.New("nil address")
+	errUnexpectedAddrs = errors.New("received unsolicited pexAddrsMessage")
+)
+
+type ErrAddrBookNonRoutable struct {
+	Addr *p2p.NetAddress
+}
+
+func (err ErrAddrBookNonRoutable) Error() string {
+	return fmt.Sprintf("Cannot add non-routable address %v", err.Addr)
+}
+
+type ErrAddrBookSelf struct {
+	Addr *p2p.NetAddress
+}
+
+func (err ErrAddrBookSelf) Error() string {
+	return fmt.Sprintf("Cannot add ourselves with address %v", err.Addr)
+}
+
+type ErrAddrBookPrivate struct {
+	Addr *p2p.NetAddress
+}
+
+func (err ErrAddrBookPrivate) Error() string {
+	return fmt.Sprintf("Cannot add private peer with address %v", err.Addr)
+}
+
+func (err ErrAddrBookPrivate) PrivateAddr() bool {
+	return true
+}
+
+type ErrAddrBookPrivateSrc struct {
+	Src *p2p.NetAddress
+}
+
+func (err ErrAddrBookPrivateSrc) Error() string {
+	return fmt.Sprintf("Cannot add peer coming from private peer with address %v", err.Src)
+}
+
+func (err ErrAddrBookPrivateSrc) PrivateAddr() bool {
+	return true
+}
+
+type ErrAddrBookNilAddr struct {
+	Addr *p2p.NetAddress
+	Src  *p2p.NetAddress
+}
+
+func (err ErrAddrBookNilAddr) Error() string {
+	return fmt.Sprintf("Cannot add a nil address. Got (addr, src) = (%v, %v)", err.Addr, err.Src)
+}
+
+type ErrAddrBookInvalidAddr struct {
+	Addr    *p2p.NetAddress
+	AddrErr error
+}
+
+func (err ErrAddrBookInvalidAddr) Error() string {
+	return fmt.Sprintf("Cannot add invalid address %v: %v", err.Addr, err.AddrErr)
+}
+
+// ErrReceivedPEXRequestTooSoon is returned when a peer sends a pexRequestMessage
+// before the minimum interval between requests has elapsed.
+type ErrReceivedPEXRequestTooSoon struct {
+	Peer         p2p.ID
+	LastReceived string
+	Now          string
+	MinInterval  string
+}
+
+func (err ErrReceivedPEXRequestTooSoon) Error() string {
+	return fmt.Sprintf(
+		"peer (%v) sent next PEX request too soon. lastReceived: %v, now: %v, minInterval: %v. Disconnecting",
+		err.Peer, err.LastReceived, err.Now, err.MinInterval,
+	)
+}
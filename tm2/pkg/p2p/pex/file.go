@@ -0,0 +1,92 @@
+package pex
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+)
+
+// addrBookJSON is the on-disk representation of the address book.
+type addrBookJSON struct {
+	Key   string          `json:"key"`
+	Addrs []*knownAddress `json:"addrs"`
+}
+
+func (a *addrBook) saveToFile(filePath string) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.Logger.Info("Saving AddrBook to file", "size", a.size())
+
+	addrs := make([]*knownAddress, 0, len(a.addrLookup))
+	for _, ka := range a.addrLookup {
+		addrs = append(addrs, ka)
+	}
+	aJSON := &addrBookJSON{
+		Key:   a.key,
+		Addrs: addrs,
+	}
+
+	jsonBytes, err := json.MarshalIndent(aJSON, "", "\t")
+	if err != nil {
+		a.Logger.Error("Failed to save AddrBook to file", "err", err)
+		return
+	}
+	err = osm.WriteFileAtomic(filePath, jsonBytes, 0o644)
+	if err != nil {
+		a.Logger.Error("Failed to save AddrBook to file", "file", filePath, "err", err)
+	}
+}
+
+// loadFromFile restores the address book from filePath.
+// It is a no-op if the file does not exist.
+func (a *addrBook) loadFromFile(filePath string) error {
+	jsonBytes, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read address book %s: %w", filePath, err)
+	}
+
+	aJSON := &addrBookJSON{}
+	if err := json.Unmarshal(jsonBytes, aJSON); err != nil {
+		return fmt.Errorf("unable to decode address book %s: %w", filePath, err)
+	}
+
+	// Restore the key
+	a.key = aJSON.Key
+	// Restore .bucketsNew & .bucketsOld
+	for _, ka := range aJSON.Addrs {
+		if ka.Addr == nil {
+			return fmt.Errorf("invalid address book %s: %w", filePath, errNilAddress)
+		}
+		for _, bucketIndex := range ka.Buckets {
+			if !validBucket(ka.BucketType, bucketIndex) {
+				return fmt.Errorf("invalid address book %s: bad bucket %d for %v", filePath, bucketIndex, ka.Addr)
+			}
+			bucket := a.getBucket(ka.BucketType, bucketIndex)
+			bucket[ka.Addr.String()] = ka
+		}
+		a.addrLookup[ka.ID()] = ka
+		if ka.BucketType == bucketTypeNew {
+			a.nNew++
+		} else {
+			a.nOld++
+		}
+	}
+	return nil
+}
+
+func validBucket(bucketType byte, bucketIdx int) bool {
+	switch bucketType {
+	case bucketTypeNew:
+		return bucketIdx >= 0 && bucketIdx < newBucketCount
+	case bucketTypeOld:
+		return bucketIdx >= 0 && bucketIdx < oldBucketCount
+	default:
+		return false
+	}
+}
@@ -0,0 +1,14 @@
+package pex
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/p2p/pex",
+	"p2p",
+	amino.GetCallersDirname(),
+).WithDependencies().WithTypes(
+	&pexRequestMessage{}, "PexRequest",
+	&pexAddrsMessage{}, "PexAddrs",
+))
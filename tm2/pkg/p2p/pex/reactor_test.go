@@ -0,0 +1,250 @@
+package pex
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/p2p/config"
+	"github.com/gnolang/gno/tm2/pkg/p2p/mock"
+)
+
+func TestPEXReactorBasic(t *testing.T) {
+	t.Parallel()
+
+	r, _ := createReactor(t, &ReactorConfig{})
+
+	assert.NotNil(t, r)
+	assert.NotEmpty(t, r.GetChannels())
+	assert.Equal(t, PexChannel, r.GetChannels()[0].ID)
+}
+
+func TestPEXReactorAddRemovePeer(t *testing.T) {
+	t.Parallel()
+
+	r, book := createReactor(t, &ReactorConfig{})
+
+	size := book.Size()
+	peer := mock.NewPeer(nil)
+
+	// inbound peers are added to the book
+	r.AddPeer(peer)
+	assert.Equal(t, size+1, book.Size())
+
+	r.RemovePeer(peer, "peer not available")
+
+	// outbound peers are asked for more addresses
+	outboundPeer := mock.NewPeer(nil)
+	outboundPeer.Outbound = true
+
+	r.AddPeer(outboundPeer)
+	assert.Equal(t, size+1, book.Size(), "outbound peers should not be added to the address book")
+	assert.True(t, r.requestsSent.Has(string(outboundPeer.ID())))
+
+	r.RemovePeer(outboundPeer, "peer not available")
+	assert.False(t, r.requestsSent.Has(string(outboundPeer.ID())))
+}
+
+func TestPEXReactorRunning(t *testing.T) {
+	t.Parallel()
+
+	const n = 3
+	books := make([]AddrBook, n)
+
+	cfg := config.TestP2PConfig()
+
+	// create switches
+	switches := make([]*p2p.Switch, n)
+	for i := 0; i < n; i++ {
+		switches[i] = p2p.MakeSwitch(cfg, i, "testing", "123.123.123", func(i int, sw *p2p.Switch) *p2p.Switch {
+			books[i] = NewAddrBook(filepath.Join(t.TempDir(), "addrbook.json"), false)
+			books[i].SetLogger(log.NewNoopLogger())
+			sw.SetAddrBook(books[i])
+
+			r := NewReactor(books[i], &ReactorConfig{})
+			r.SetLogger(log.NewNoopLogger())
+			r.SetEnsurePeersPeriod(250 * time.Millisecond)
+			sw.AddReactor("pex", r)
+
+			return sw
+		})
+	}
+
+	// only the first switch is known by the others
+	for i := 1; i < n; i++ {
+		books[i].AddOurAddress(switches[i].NetAddress())
+		require.NoError(t, books[i].AddAddress(switches[0].NetAddress(), switches[0].NetAddress()))
+	}
+
+	require.NoError(t, p2p.StartSwitches(switches))
+	t.Cleanup(func() {
+		for i, sw := range switches {
+			sw.Stop()
+			// the book is saved when stopping, before removing its directory
+			books[i].(*addrBook).Wait()
+		}
+	})
+
+	// every switch eventually learns about, and connects to, all the others
+	assert.Eventually(t, func() bool {
+		for _, sw := range switches {
+			if sw.Peers().Size() != n-1 {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestPEXReactorReceive(t *testing.T) {
+	t.Parallel()
+
+	r, book := createReactor(t, &ReactorConfig{})
+	peer := mock.NewPeer(nil)
+	peer.Outbound = true
+
+	// we have to send a request to receive responses
+	r.RequestAddrs(peer)
+
+	size := book.Size()
+	addrs := []*p2p.NetAddress{peer.SocketAddr()}
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexAddrsMessage{Addrs: addrs}))
+	assert.Equal(t, size+1, book.Size())
+
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexRequestMessage{}))
+	assert.True(t, peer.IsRunning())
+}
+
+func TestPEXReactorUnsolicitedAddrs(t *testing.T) {
+	t.Parallel()
+
+	r, book := createReactor(t, &ReactorConfig{})
+	peer := mock.NewPeer(nil)
+
+	size := book.Size()
+	addrs := []*p2p.NetAddress{randIPv4Address(t)}
+
+	// receiving addresses without asking for them disconnects the peer
+	assert.ErrorIs(t, r.ReceiveAddrs(addrs, peer), errUnexpectedAddrs)
+
+	r.RequestAddrs(peer)
+	assert.NoError(t, r.ReceiveAddrs(addrs, peer))
+	assert.Equal(t, size+1, book.Size())
+
+	// the request has been answered already
+	assert.ErrorIs(t, r.ReceiveAddrs(addrs, peer), errUnexpectedAddrs)
+}
+
+func TestPEXReactorRequestMessageAbuse(t *testing.T) {
+	t.Parallel()
+
+	r, _ := createReactor(t, &ReactorConfig{})
+	peer := mock.NewPeer(nil)
+
+	// the first two requests are free
+	assert.NoError(t, r.receiveRequest(peer))
+	assert.NoError(t, r.receiveRequest(peer))
+
+	// the third one comes too soon
+	err := r.receiveRequest(peer)
+	assert.IsType(t, ErrReceivedPEXRequestTooSoon{}, err)
+
+	// a peer sending too many requests is disconnected
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexRequestMessage{}))
+	assert.False(t, peer.IsRunning())
+}
+
+func TestPEXReactorSeedMode(t *testing.T) {
+	t.Parallel()
+
+	r, book := createReactor(t, &ReactorConfig{SeedMode: true})
+	require.NoError(t, book.AddAddress(randIPv4Address(t), randIPv4Address(t)))
+
+	// a seed answers the request of an inbound peer, then disconnects it
+	peer := mock.NewPeer(nil)
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexRequestMessage{}))
+
+	assert.Eventually(t, func() bool {
+		return !peer.IsRunning()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPEXReactorDialBackoff(t *testing.T) {
+	t.Parallel()
+
+	r, _ := createReactor(t, &ReactorConfig{})
+
+	// nothing listens on this address
+	addr := p2p.NewNetAddressFromIPPort(randID(), net.ParseIP("127.0.0.1"), 1)
+
+	require.Error(t, r.dialPeer(addr))
+	assert.Equal(t, 1, r.AttemptsToDial(addr))
+
+	// dialing again right away is refused
+	err := r.dialPeer(addr)
+	assert.IsType(t, errTooEarlyToDial{}, err)
+	assert.Equal(t, 1, r.AttemptsToDial(addr))
+}
+
+func TestPEXReactorDoesNotDialBadSeeds(t *testing.T) {
+	t.Parallel()
+
+	book := NewAddrBook(filepath.Join(t.TempDir(), "addrbook.json"), true)
+	book.SetLogger(log.NewNoopLogger())
+
+	t.Cleanup(func() { book.Stop() })
+
+	r := NewReactor(book, &ReactorConfig{Seeds: []string{"not an address"}})
+	r.SetLogger(log.NewNoopLogger())
+
+	assert.Error(t, r.Start())
+}
+
+func TestPEXMessageSize(t *testing.T) {
+	t.Parallel()
+
+	addrs := make([]*p2p.NetAddress, maxGetSelection)
+	for i := range addrs {
+		addrs[i] = randIPv4Address(t)
+	}
+
+	bz := amino.MustMarshalAny(&pexAddrsMessage{Addrs: addrs})
+	assert.LessOrEqual(t, len(bz), maxMsgSize)
+
+	msg, err := decodeMsg(bz)
+	require.NoError(t, err)
+	require.IsType(t, &pexAddrsMessage{}, msg)
+	assert.Len(t, msg.(*pexAddrsMessage).Addrs, maxGetSelection)
+
+	_, err = decodeMsg(make([]byte, maxMsgSize+1))
+	assert.Error(t, err)
+}
+
+// createReactor returns a reactor, backed by a fresh address book,
+// and registered on a running switch.
+func createReactor(t *testing.T, conf *ReactorConfig) (*Reactor, AddrBook) {
+	t.Helper()
+
+	book := NewAddrBook(filepath.Join(t.TempDir(), "addrbook.json"), true)
+	book.SetLogger(log.NewNoopLogger())
+
+	r := NewReactor(book, conf)
+	r.SetLogger(log.NewNoopLogger())
+
+	sw := p2p.MakeSwitch(config.TestP2PConfig(), 0, "testing", "123.123.123", func(i int, sw *p2p.Switch) *p2p.Switch {
+		sw.SetAddrBook(book)
+		sw.AddReactor("pex", r)
+		return sw
+	})
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() { sw.Stop() })
+
+	return r, book
+}
@@ -44,6 +44,18 @@ func MConnConfig(cfg *config.P2PConfig) conn.MConnConfig {
 	return mConfig
 }
 
+// An AddrBook represents an address book from the pex package, which is used
+// to store peer addresses.
+type AddrBook interface {
+	AddAddress(addr *NetAddress, src *NetAddress) error
+	AddOurAddress(*NetAddress)
+	OurAddress(*NetAddress) bool
+	MarkGood(ID)
+	RemoveAddress(*NetAddress)
+	HasAddress(*NetAddress) bool
+	Save()
+}
+
 // PeerFilterFunc to be implemented by filter hooks after a new Peer has been
 // fully setup.
 type PeerFilterFunc func(IPeerSet, Peer) error
@@ -69,6 +81,8 @@ type Switch struct {
 	// peers addresses with whom we'll maintain constant connection
 	persistentPeersAddrs []*NetAddress
 
+	addrBook AddrBook
+
 	transport Transport
 
 	filterTimeout time.Duration
@@ -176,6 +190,19 @@ func (sw *Switch) Reactor(name string) Reactor {
 	return sw.reactors[name]
 }
 
+// SetAddrBook allows to set address book on Switch.
+func (sw *Switch) SetAddrBook(addrBook AddrBook) {
+	sw.addrBook = addrBook
+}
+
+// MarkPeerAsGood marks the given peer as good when it did something useful
+// like contributed to consensus.
+func (sw *Switch) MarkPeerAsGood(peer Peer) {
+	if sw.addrBook != nil {
+		sw.addrBook.MarkGood(peer.ID())
+	}
+}
+
 // SetNodeInfo sets the switch's NodeInfo for checking compatibility and handshaking with other nodes.
 // NOTE: Not goroutine safe.
 func (sw *Switch) SetNodeInfo(nodeInfo NodeInfo) {
@@ -219,7 +246,7 @@ func (sw *Switch) OnStop() {
 	if t, ok := sw.transport.(TransportLifecycle); ok {
 		err := t.Close()
 		if err != nil {
-			sw.Logger.Error("Error stopping transport on stop: ", err)
+			sw.Logger.Error("Error stopping transport on stop", "err", err)
 		}
 	}
 
@@ -305,6 +332,13 @@ func (sw *Switch) Peers() IPeerSet {
 // If the peer is persistent, it will attempt to reconnect.
 // TODO: make record depending on reason.
 func (sw *Switch) StopPeerForError(peer Peer, reason interface{}) {
+	if p := sw.peers.Get(peer.ID()); p != nil && p != peer {
+		// Replaced by another connection to the same node, see addPeer.
+		sw.Logger.Info("Stopping replaced peer", "peer", peer, "err", reason)
+		sw.transport.Cleanup(peer)
+		peer.Stop()
+		return
+	}
 	sw.Logger.Error("Stopping peer for error", "peer", peer, "err", reason)
 	sw.stopAndRemovePeer(peer, reason)
 
@@ -417,6 +451,23 @@ func (sw *Switch) DialPeersAsync(peers []string) error {
 		}
 		return err
 	}
+
+	if sw.addrBook != nil && len(netAddrs) > 0 {
+		// add peers to `addrBook`
+		ourAddr := sw.NetAddress()
+		for _, netAddr := range netAddrs {
+			// do not add our address or ID
+			if !netAddr.Same(ourAddr) {
+				if err := sw.addrBook.AddAddress(netAddr, ourAddr); err != nil {
+					sw.Logger.Error("Can't add peer's address to addrbook", "err", err)
+				}
+			}
+		}
+		// Persist some peers to disk right away.
+		// NOTE: integration tests depend on this
+		sw.addrBook.Save()
+	}
+
 	sw.dialPeersAsync(netAddrs)
 	return nil
 }
@@ -501,6 +552,11 @@ func (sw *Switch) AddPersistentPeers(addrs []string) error {
 	return nil
 }
 
+// IsPeerPersistent returns true if na is one of the persistent peers.
+func (sw *Switch) IsPeerPersistent(na *NetAddress) bool {
+	return sw.isPeerPersistentFn()(na)
+}
+
 func (sw *Switch) isPeerPersistentFn() func(*NetAddress) bool {
 	return func(na *NetAddress) bool {
 		for _, pa := range sw.persistentPeersAddrs {
@@ -524,7 +580,10 @@ func (sw *Switch) acceptRoutine() {
 			switch err := err.(type) {
 			case RejectedError:
 				if err.IsSelf() {
-					// TODO: warn?
+					// Remove the given address from the address book and add to our addresses
+					// to avoid dialing in the future.
+					addr := err.Addr()
+					sw.removeOurAddress(&addr)
 				}
 
 				sw.Logger.Info(
@@ -618,7 +677,9 @@ func (sw *Switch) addOutboundPeerWithConfig(
 	if err != nil {
 		if e, ok := err.(RejectedError); ok {
 			if e.IsSelf() {
-				// TODO: warn?
+				// Remove the given address from the address book and add to our addresses
+				// to avoid dialing in the future.
+				sw.removeOurAddress(addr)
 				return err
 			}
 		}
@@ -643,6 +704,16 @@ func (sw *Switch) addOutboundPeerWithConfig(
 	return nil
 }
 
+// removeOurAddress makes sure the address book never hands out addr again,
+// as it belongs to this node.
+func (sw *Switch) removeOurAddress(addr *NetAddress) {
+	if sw.addrBook == nil {
+		return
+	}
+	sw.addrBook.RemoveAddress(addr)
+	sw.addrBook.AddOurAddress(addr)
+}
+
 func (sw *Switch) filterPeer(p Peer) error {
 	// Avoid duplicate
 	if sw.peers.Has(p.ID()) {
@@ -674,6 +745,14 @@ func (sw *Switch) filterPeer(p Peer) error {
 // addPeer starts up the Peer and adds it to the Switch. Error is returned if
 // the peer is filtered out or failed to start or can't be added.
 func (sw *Switch) addPeer(p Peer) error {
+	// Two nodes dialing each other at the same time end up with two
+	// connections, and must both keep the same one, or both are dropped:
+	// the one dialed by the lower node ID is kept.
+	if existing := sw.peers.Get(p.ID()); existing != nil && sw.prefersConn(p, existing) {
+		sw.Logger.Info("Replacing duplicate peer", "peer", existing, "with", p)
+		sw.stopAndRemovePeer(existing, nil)
+	}
+
 	if err := sw.filterPeer(p); err != nil {
 		return err
 	}
@@ -723,6 +802,16 @@ func (sw *Switch) addPeer(p Peer) error {
 	return nil
 }
 
+// prefersConn returns true if the connection of p is to be kept over that
+// of existing, to the same node: only one of them is dialed by the node
+// with the lower ID.
+func (sw *Switch) prefersConn(p, existing Peer) bool {
+	if p.IsOutbound() == existing.IsOutbound() {
+		return false
+	}
+	return p.IsOutbound() == (sw.nodeInfo.ID() < p.ID())
+}
+
 // logTelemetry logs the switch telemetry data
 // to global metrics funnels
 func (sw *Switch) logTelemetry() {
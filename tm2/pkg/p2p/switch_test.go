@@ -479,6 +479,52 @@ func waitUntilSwitchHasAtLeastNPeers(sw *Switch, n int) {
 	}
 }
 
+func TestSwitchSimultaneousDial(t *testing.T) {
+	t.Parallel()
+
+	switches := make([]*Switch, 2)
+	for i := range switches {
+		switches[i] = MakeSwitch(cfg, i, "testing", "123.123.123", func(_ int, sw *Switch) *Switch { return sw })
+		require.NoError(t, switches[i].Start())
+	}
+	defer func() {
+		for _, sw := range switches {
+			sw.Stop()
+		}
+	}()
+
+	// both switches dial each other at the same time, and must keep the
+	// same connection: the one dialed by the lower node ID.
+	var wg sync.WaitGroup
+	for i, sw := range switches {
+		wg.Add(1)
+		go func(sw *Switch, addr *NetAddress) {
+			defer wg.Done()
+			sw.DialPeerWithAddress(addr)
+		}(sw, switches[1-i].NetAddress())
+	}
+	wg.Wait()
+
+	connected := func() bool {
+		for i, sw := range switches {
+			p := sw.Peers().Get(switches[1-i].NodeInfo().ID())
+			if p == nil || !p.IsRunning() {
+				return false
+			}
+		}
+		return true
+	}
+	require.Eventually(t, connected, 5*time.Second, 10*time.Millisecond)
+
+	// and keep it.
+	time.Sleep(100 * time.Millisecond)
+	require.True(t, connected())
+	for i, sw := range switches {
+		id, peerID := sw.NodeInfo().ID(), switches[1-i].NodeInfo().ID()
+		assert.Equal(t, id < peerID, sw.Peers().Get(peerID).IsOutbound())
+	}
+}
+
 func TestSwitchFullConnectivity(t *testing.T) {
 	t.Parallel()
 
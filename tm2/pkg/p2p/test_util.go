@@ -155,6 +155,11 @@ func MakeSwitch(
 		panic(err)
 	}
 
+	// Advertise the port picked by the kernel, so that the switch can be
+	// dialed back by the peers it tells about itself.
+	addr := t.NetAddress()
+	nodeInfo.NetAddress = &addr
+
 	// TODO: let the config be passed in?
 	sw := initSwitch(i, NewSwitch(cfg, t, opts...))
 	sw.SetLogger(log.NewNoopLogger().With("switch", i))
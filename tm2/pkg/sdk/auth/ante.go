@@ -3,7 +3,9 @@ package auth
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
+	"slices"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -36,6 +38,11 @@ type AnteOptions struct {
 	// This is useful for development, and maybe production chains.
 	// Always check your settings and inspect genesis transactions.
 	VerifyGenesisSignatures bool
+
+	// Denomination of the fees which give priority to txs in the mempool,
+	// if no minimum gas prices are set. Fees in other denominations give
+	// none, as anyone can mint coins worth nothing.
+	FeeDenom string
 }
 
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
@@ -123,6 +130,9 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			return newCtx, res, true
 		}
 
+		// the fee payer's sequence orders its txs in the mempool
+		sequence := signerAccs[0].GetSequence()
+
 		// deduct the fees
 		if !tx.Fee.GasFee.IsZero() {
 			res = DeductFees(bank, newCtx, signerAccs[0], std.Coins{tx.Fee.GasFee})
@@ -166,7 +176,13 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 		}
 
 		// TODO: tx tags (?)
-		return newCtx, sdk.Result{GasWanted: tx.Fee.GasWanted}, false // continue...
+		res = sdk.Result{
+			GasWanted: tx.Fee.GasWanted,
+			Priority:  TxPriority(tx.Fee, priorityDenoms(ctx, opts)),
+			Sender:    signerAddrs[0],
+			Sequence:  sequence,
+		}
+		return newCtx, res, false // continue...
 	}
 }
 
@@ -420,6 +436,49 @@ func EnsureSufficientMempoolFees(ctx sdk.Context, fee std.Fee) sdk.Result {
 	))
 }
 
+// priorityGas is the amount of gas the tx priority is the fee of,
+// so that fees of a fraction of a coin per unit of gas are told apart.
+const priorityGas = 1_000_000
+
+// TxPriority returns the mempool priority of a transaction with the given
+// fee, which is its gas price: the fee paid per million units of gas wanted.
+// Fees in a denomination other than the given ones give no priority.
+func TxPriority(fee std.Fee, denoms []string) int64 {
+	if fee.GasWanted <= 0 || fee.GasFee.Amount <= 0 {
+		return 0
+	}
+	if !slices.Contains(denoms, fee.GasFee.Denom) {
+		return 0
+	}
+
+	priority := big.NewInt(fee.GasFee.Amount)
+	priority.Mul(priority, big.NewInt(priorityGas))
+	priority.Quo(priority, big.NewInt(fee.GasWanted))
+
+	if !priority.IsInt64() {
+		return math.MaxInt64
+	}
+
+	return priority.Int64()
+}
+
+// priorityDenoms returns the denominations of the fees which give priority
+// to txs: those of the minimum gas prices, or else the one of the options.
+func priorityDenoms(ctx sdk.Context, opts AnteOptions) []string {
+	minGasPrices := ctx.MinGasPrices()
+	if len(minGasPrices) == 0 {
+		if opts.FeeDenom == "" {
+			return nil
+		}
+		return []string{opts.FeeDenom}
+	}
+	denoms := make([]string, len(minGasPrices))
+	for i, gp := range minGasPrices {
+		denoms[i] = gp.Price.Denom
+	}
+	return denoms
+}
+
 // SetGasMeter returns a new context with a gas meter set from a given context.
 func SetGasMeter(simulate bool, ctx sdk.Context, gasLimit int64) sdk.Context {
 	// In various cases such as simulation and during the genesis block, we do not
@@ -2,11 +2,13 @@ package auth
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -819,6 +821,106 @@ func TestEnsureSufficientMempoolFees(t *testing.T) {
 	}
 }
 
+func TestTxPriority(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    std.Fee
+		expected int64
+	}{
+		{std.NewFee(200000, std.Coin{}), 0},
+		{std.NewFee(0, std.NewCoin("ugnot", 10)), 0},
+		{std.NewFee(200000, std.NewCoin("ugnot", 1)), 5},
+		{std.NewFee(200000, std.NewCoin("ugnot", 2)), 10},
+		{std.NewFee(1, std.NewCoin("ugnot", 1)), 1000000},
+		{std.NewFee(1, std.NewCoin("ugnot", math.MaxInt64)), math.MaxInt64},
+		{std.NewFee(1, std.NewCoin("foo", math.MaxInt64)), 0},
+	}
+
+	denoms := []string{"ugnot"}
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, TxPriority(tc.input, denoms), "tc #%d, input: %v", i, tc.input)
+	}
+}
+
+// Test the mempool ordering of the tx returned by the ante handler
+func TestAnteHandlerTxOrdering(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	opts := defaultAnteOptions()
+	opts.FeeDenom = "atom"
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, opts)
+	ctx := env.ctx
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, acc1)
+
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	fee := tu.NewTestFee()
+
+	for seq := uint64(0); seq < 2; seq++ {
+		tx := tu.NewTestTx(t, ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{seq}, fee)
+
+		_, result, abort := anteHandler(ctx, tx, false)
+		require.False(t, abort)
+
+		require.Equal(t, TxPriority(fee, []string{"atom"}), result.Priority)
+		require.Equal(t, addr1, result.Sender)
+		require.Equal(t, seq, result.Sequence)
+	}
+}
+
+// Test that fees in a foreign denomination don't outrank fees in the
+// accepted one, with and without minimum gas prices.
+func TestAnteHandlerForeignFeePriority(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	opts := defaultAnteOptions()
+	opts.FeeDenom = "ugnot"
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, opts)
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts, holding plenty of coins of a worthless denomination
+	acc1 := env.acck.NewAccountWithAddress(env.ctx, addr1)
+	acc1.SetCoins(std.Coins{std.NewCoin("foo", 1_000_000_000_000), std.NewCoin("ugnot", 10_000_000)})
+	env.acck.SetAccount(env.ctx, acc1)
+
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	foreignFee := std.NewFee(50000, std.NewCoin("foo", 1_000_000_000))
+	ugnotFee := std.NewFee(50000, std.NewCoin("ugnot", 150))
+
+	for _, ctx := range []sdk.Context{
+		env.ctx,
+		env.ctx.WithMinGasPrices([]sdk.GasPrice{
+			{Gas: 1000, Price: std.NewCoin("ugnot", 1)},
+		}),
+	} {
+		// both txs compete for the same sequence
+		cctx, _ := ctx.CacheContext()
+		tx := tu.NewTestTx(t, ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}, foreignFee)
+		_, foreignRes, _ := anteHandler(cctx, tx, false)
+
+		cctx, _ = ctx.CacheContext()
+		tx = tu.NewTestTx(t, ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}, ugnotFee)
+		_, ugnotRes, abort := anteHandler(cctx, tx, false)
+		require.False(t, abort)
+
+		assert.Zero(t, foreignRes.Priority)
+		assert.Positive(t, ugnotRes.Priority)
+	}
+}
+
 // Test custom SignatureVerificationGasConsumer
 func TestCustomSignatureVerificationGasConsumer(t *testing.T) {
 	t.Parallel()
@@ -18,6 +18,16 @@ type AccountKeeper struct {
 
 	// The prototypical Account constructor.
 	proto func() std.Account
+
+	// Fetches the accounts missing from the store, if set.
+	fetcher AccountFetcher
+}
+
+// AccountFetcher fetches the coins of accounts which are not in the store,
+// for instance from a remote chain that the local chain was forked from.
+// FetchCoins returns nil coins if the account doesn't exist remotely either.
+type AccountFetcher interface {
+	FetchCoins(addr crypto.Address) (std.Coins, error)
 }
 
 // NewAccountKeeper returns a new AccountKeeper that uses go-amino to
@@ -31,6 +41,14 @@ func NewAccountKeeper(
 	}
 }
 
+// WithAccountFetcher returns a copy of the AccountKeeper which creates the
+// accounts missing from the store with the coins returned by fetcher, when
+// they are first read.
+func (ak AccountKeeper) WithAccountFetcher(fetcher AccountFetcher) AccountKeeper {
+	ak.fetcher = fetcher
+	return ak
+}
+
 // Logger returns a module-specific logger.
 func (ak AccountKeeper) Logger(ctx sdk.Context) *slog.Logger {
 	return ctx.Logger().With("module", fmt.Sprintf("auth"))
@@ -58,12 +76,34 @@ func (ak AccountKeeper) GetAccount(ctx sdk.Context, addr crypto.Address) std.Acc
 	stor := ctx.Store(ak.key)
 	bz := stor.Get(AddressStoreKey(addr))
 	if bz == nil {
-		return nil
+		return ak.fetchAccount(ctx, addr)
 	}
 	acc := ak.decodeAccount(bz)
 	return acc
 }
 
+// fetchAccount creates an account with the coins returned by the fetcher, if
+// any. The account itself (number, sequence, pubkey) is local.
+func (ak AccountKeeper) fetchAccount(ctx sdk.Context, addr crypto.Address) std.Account {
+	if ak.fetcher == nil {
+		return nil
+	}
+	coins, err := ak.fetcher.FetchCoins(addr)
+	if err != nil {
+		ak.Logger(ctx).Error("unable to fetch account", "address", addr, "err", err)
+		return nil
+	}
+	if coins.IsZero() {
+		return nil
+	}
+	acc := ak.NewAccountWithAddress(ctx, addr)
+	if err := acc.SetCoins(coins); err != nil {
+		panic(err)
+	}
+	ak.SetAccount(ctx, acc)
+	return acc
+}
+
 // GetAllAccounts returns all accounts in the AccountKeeper.
 func (ak AccountKeeper) GetAllAccounts(ctx sdk.Context) []std.Account {
 	accounts := []std.Account{}
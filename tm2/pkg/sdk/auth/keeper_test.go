@@ -1,11 +1,13 @@
 package auth
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 func TestAccountMapperGetSet(t *testing.T) {
@@ -71,3 +73,50 @@ func TestAccountMapperRemoveAccount(t *testing.T) {
 	require.NotNil(t, acc2)
 	require.Equal(t, accSeq2, acc2.GetSequence())
 }
+
+type mockAccountFetcher map[crypto.Address]std.Coins
+
+func (m mockAccountFetcher) FetchCoins(addr crypto.Address) (std.Coins, error) {
+	if addr == crypto.AddressFromPreimage([]byte("broken")) {
+		return nil, errors.New("fetch error")
+	}
+	return m[addr], nil
+}
+
+func TestAccountMapperFetcher(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+
+	var (
+		remote = crypto.AddressFromPreimage([]byte("remote"))
+		local  = crypto.AddressFromPreimage([]byte("local"))
+		other  = crypto.AddressFromPreimage([]byte("other"))
+		broken = crypto.AddressFromPreimage([]byte("broken"))
+		coins  = std.NewCoins(std.NewCoin("foo", 10))
+	)
+	fetcher := mockAccountFetcher{remote: coins, local: coins, other: coins}
+	acck := env.acck.WithAccountFetcher(fetcher)
+
+	// local accounts are not fetched
+	localAcc := acck.NewAccountWithAddress(env.ctx, local)
+	acck.SetAccount(env.ctx, localAcc)
+	require.True(t, acck.GetAccount(env.ctx, local).GetCoins().IsZero())
+
+	// missing accounts are created with the fetched coins
+	acc := acck.GetAccount(env.ctx, remote)
+	require.NotNil(t, acc)
+	require.Equal(t, coins, acc.GetCoins())
+	require.Equal(t, localAcc.GetAccountNumber()+1, acc.GetAccountNumber())
+
+	// and are then stored locally
+	delete(fetcher, remote)
+	require.Equal(t, acc, acck.GetAccount(env.ctx, remote))
+
+	// accounts unknown or failing to be fetched don't exist
+	require.Nil(t, acck.GetAccount(env.ctx, crypto.AddressFromPreimage([]byte("unknown"))))
+	require.Nil(t, acck.GetAccount(env.ctx, broken))
+
+	// the original keeper doesn't fetch accounts
+	require.Nil(t, env.acck.GetAccount(env.ctx, other))
+}
@@ -56,6 +56,26 @@ func DefaultParams() Params {
 	}
 }
 
+// Validate returns an error if any of the params is out of its valid range.
+func (p Params) Validate() error {
+	if p.MaxMemoBytes <= 0 {
+		return fmt.Errorf("invalid max memo bytes: %d", p.MaxMemoBytes)
+	}
+	if p.TxSigLimit <= 0 {
+		return fmt.Errorf("invalid tx signature limit: %d", p.TxSigLimit)
+	}
+	if p.TxSizeCostPerByte < 0 {
+		return fmt.Errorf("invalid tx size cost per byte: %d", p.TxSizeCostPerByte)
+	}
+	if p.SigVerifyCostED25519 < 0 {
+		return fmt.Errorf("invalid ED25519 signature verification cost: %d", p.SigVerifyCostED25519)
+	}
+	if p.SigVerifyCostSecp256k1 < 0 {
+		return fmt.Errorf("invalid secp256k1 signature verification cost: %d", p.SigVerifyCostSecp256k1)
+	}
+	return nil
+}
+
 // String implements the stringer interface.
 func (p Params) String() string {
 	var sb strings.Builder
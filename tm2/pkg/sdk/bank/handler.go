@@ -38,10 +38,10 @@ func (bh bankHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 
 // Handle MsgSend.
 func (bh bankHandler) handleMsgSend(ctx sdk.Context, msg MsgSend) sdk.Result {
+	if !paramsFromContext(ctx).SendEnabled {
+		return abciResult(ErrSendDisabled())
+	}
 	/*
-		if !bh.bank.GetSendEnabled(ctx) {
-			return abciResult(ErrSendDisabled())
-		}
 		if bh.bank.BlacklistedAddr(msg.ToAddress) {
 			return std.ErrUnauthorized(fmt.Sprintf("%s is not allowed to receive transactions", msg.ToAddress)).Result()
 		}
@@ -67,10 +67,10 @@ func (bh bankHandler) handleMsgSend(ctx sdk.Context, msg MsgSend) sdk.Result {
 // Handle MsgMultiSend.
 func (bh bankHandler) handleMsgMultiSend(ctx sdk.Context, msg MsgMultiSend) sdk.Result {
 	// NOTE: totalIn == totalOut should already have been checked
+	if !paramsFromContext(ctx).SendEnabled {
+		return abciResult(ErrSendDisabled())
+	}
 	/*
-		if !k.GetSendEnabled(ctx) {
-			return abciResult(std.ErrSendDisabled())
-		}
 		for _, out := range msg.Outputs {
 			if bh.bank.BlacklistedAddr(out.Address) {
 				return abciResult(std.ErrUnauthorized(fmt.Sprintf("%s is not allowed to receive transactions", out.Address)))
@@ -66,3 +66,28 @@ func TestQuerierRouteNotFound(t *testing.T) {
 	res := h.Query(env.ctx, req)
 	require.Error(t, res.Error)
 }
+
+func TestSendDisabled(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.bank)
+	_, _, addr1 := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+
+	acc := env.acck.NewAccountWithAddress(env.ctx, addr1)
+	acc.SetCoins(std.NewCoins(std.NewCoin("foo", 10)))
+	env.acck.SetAccount(env.ctx, acc)
+
+	msg := NewMsgSend(addr1, addr2, std.NewCoins(std.NewCoin("foo", 1)))
+
+	ctx := env.ctx.WithValue(BankParamsContextKey{}, Params{SendEnabled: false})
+	res := h.Process(ctx, msg)
+	require.False(t, res.IsOK())
+	require.IsType(t, SendDisabledError{}, res.Error)
+
+	ctx = env.ctx.WithValue(BankParamsContextKey{}, DefaultParams())
+	res = h.Process(ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.True(t, env.bank.GetCoins(env.ctx, addr2).IsEqual(std.NewCoins(std.NewCoin("foo", 1))))
+}
@@ -0,0 +1,32 @@
+package bank
+
+import "github.com/gnolang/gno/tm2/pkg/sdk"
+
+type BankParamsContextKey struct{}
+
+// Default parameter values
+const (
+	DefaultSendEnabled = true
+)
+
+// Params defines the parameters for the bank module.
+type Params struct {
+	SendEnabled bool `json:"send_enabled" yaml:"send_enabled"`
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{
+		SendEnabled: DefaultSendEnabled,
+	}
+}
+
+// paramsFromContext returns the bank params set in the context, or the
+// default ones if none are set.
+func paramsFromContext(ctx sdk.Context) Params {
+	params, ok := ctx.Value(BankParamsContextKey{}).(Params)
+	if !ok {
+		return DefaultParams()
+	}
+	return params
+}
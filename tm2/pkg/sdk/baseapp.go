@@ -16,6 +16,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 // Key to store the consensus params in the main store.
@@ -44,6 +45,7 @@ type BaseApp struct {
 
 	beginTxHook BeginTxHook // BaseApp-specific hook run before running transaction messages.
 	endTxHook   EndTxHook   // BaseApp-specific hook run after running transaction messages.
+	restoreHook RestoreHook // BaseApp-specific hook run after restoring a snapshot.
 
 	// --------------------
 	// Volatile state
@@ -73,6 +75,12 @@ type BaseApp struct {
 
 	// application's version string
 	appVersion string
+
+	// state sync snapshots, see SetSnapshotStore.
+	snapshotManager    *snapshots.Manager
+	snapshotInterval   int64  // create a snapshot every snapshotInterval blocks
+	snapshotKeepRecent int    // number of snapshots to keep, 0 to keep all
+	restoreAppHash     []byte // app hash expected from the snapshot being restored
 }
 
 var _ abci.Application = (*BaseApp)(nil)
@@ -419,6 +427,10 @@ func handleQueryApp(app *BaseApp, path []string, req abci.RequestQuery) (res abc
 			res.Height = req.Height
 			res.Value = []byte(app.appVersion)
 			return res
+		case "min_gas_prices":
+			res.Height = req.Height
+			res.Value = []byte(std.GasPricesString(app.minGasPrices))
+			return res
 		default:
 			res.Error = ABCIError(std.ErrUnknownRequest(fmt.Sprintf("Unknown query: %s", path)))
 			return
@@ -565,6 +577,9 @@ func (app *BaseApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx)
 		res.ResponseBase = result.ResponseBase
 		res.GasWanted = result.GasWanted
 		res.GasUsed = result.GasUsed
+		res.Priority = result.Priority
+		res.Sender = result.Sender
+		res.Sequence = result.Sequence
 		return
 	}
 }
@@ -711,6 +726,9 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 	// meter so we initialize upfront.
 	var gasWanted int64
 
+	// NOTE: The mempool ordering of the tx is returned by the AnteHandler too.
+	var anteResult Result
+
 	ctx := app.getContextForTx(mode, txBytes)
 	ms := ctx.MultiStore()
 	if mode == RunTxModeDeliver {
@@ -822,6 +840,7 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 			ctx = newCtx.WithMultiStore(ms)
 			msCache.MultiWrite()
 			gasWanted = result.GasWanted
+			anteResult = result
 		}
 	}
 
@@ -835,6 +854,9 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 
 	result = app.runMsgs(runMsgCtx, msgs, mode)
 	result.GasWanted = gasWanted
+	result.Priority = anteResult.Priority
+	result.Sender = anteResult.Sender
+	result.Sequence = anteResult.Sequence
 
 	// Safety check: don't write the cache state unless we're in DeliverTx.
 	if mode != RunTxModeDeliver {
@@ -906,6 +928,13 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	headerBz := amino.MustMarshal(header)
 	baseStore.Set(mainLastHeaderKey, headerBz)
 
+	// The base store is not versioned, so snapshots are created right
+	// away, before the next block updates it.
+	if app.snapshotManager != nil && app.snapshotInterval > 0 &&
+		header.GetHeight()%app.snapshotInterval == 0 {
+		app.createSnapshot(header.GetHeight())
+	}
+
 	// Reset the Check state to the latest committed.
 	//
 	// NOTE: This is safe because Tendermint holds a lock on the mempool for
@@ -166,6 +166,24 @@ func TestAppVersionSetterGetter(t *testing.T) {
 	require.Equal(t, versionString, string(res.Value))
 }
 
+func TestMinGasPricesQuery(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.NewMemDB()
+	app := newBaseApp(t.Name(), db)
+
+	res := app.Query(abci.RequestQuery{Path: ".app/min_gas_prices"})
+	require.True(t, res.IsOK())
+	require.Equal(t, "", string(res.Value))
+
+	gasPrices := "1ugnot/1000gas;10foo/1gas"
+	app = newBaseApp(t.Name(), db, SetMinGasPrices(gasPrices))
+
+	res = app.Query(abci.RequestQuery{Path: ".app/min_gas_prices"})
+	require.True(t, res.IsOK())
+	require.Equal(t, gasPrices, string(res.Value))
+}
+
 func TestLoadVersionInvalid(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,7 @@ import (
 
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 // File for storing in-package BaseApp optional functions,
@@ -29,6 +30,24 @@ func SetMinGasPrices(gasPricesStr string) func(*BaseApp) {
 	return func(bap *BaseApp) { bap.setMinGasPrices(gasPrices) }
 }
 
+// SetSnapshotStore returns an option that makes the app create a snapshot of
+// its state every interval blocks, keeping the keepRecent most recent ones (or
+// all of them if 0) in the given store, and serve them to state syncing nodes.
+func SetSnapshotStore(ss *snapshots.Store, interval int64, keepRecent int) func(*BaseApp) {
+	if interval < 0 {
+		panic(fmt.Sprintf("invalid snapshot interval: %d", interval))
+	}
+	if keepRecent < 0 {
+		panic(fmt.Sprintf("invalid number of snapshots to keep: %d", keepRecent))
+	}
+
+	return func(bap *BaseApp) {
+		bap.snapshotManager = snapshots.NewManager(ss, bap.cms)
+		bap.snapshotInterval = interval
+		bap.snapshotKeepRecent = keepRecent
+	}
+}
+
 func (app *BaseApp) SetName(name string) {
 	if app.sealed {
 		panic("SetName() on sealed BaseApp")
@@ -99,3 +118,10 @@ func (app *BaseApp) SetEndTxHook(endTx EndTxHook) {
 	}
 	app.endTxHook = endTx
 }
+
+func (app *BaseApp) SetRestoreHook(restore RestoreHook) {
+	if app.sealed {
+		panic("SetRestoreHook() on sealed BaseApp")
+	}
+	app.restoreHook = restore
+}
@@ -0,0 +1,15 @@
+package params
+
+const (
+	// ModuleName is the name of the params module.
+	ModuleName = "params"
+
+	// StoreKeyPrefix prefixes the parameter values in the keeper's store.
+	StoreKeyPrefix = "/pv/"
+)
+
+// storeKey returns the key used to store the parameter identified by key,
+// in the "<module>.<name>" format.
+func storeKey(key string) []byte {
+	return []byte(StoreKeyPrefix + key)
+}
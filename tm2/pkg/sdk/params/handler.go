@@ -0,0 +1,97 @@
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type paramsHandler struct {
+	params ParamsKeeper
+}
+
+// NewHandler returns a handler for "params" type messages and queries.
+func NewHandler(params ParamsKeeper) paramsHandler {
+	return paramsHandler{
+		params: params,
+	}
+}
+
+func (ph paramsHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
+	// no messages supported; params are changed through the application.
+	errMsg := fmt.Sprintf("unrecognized params message type: %T", msg)
+	return abciResult(std.ErrUnknownRequest(errMsg))
+}
+
+//----------------------------------------
+// Query
+
+// Query handles the following paths:
+//
+//   - params: all the params, as a JSON list of key-value pairs, which can be
+//     used in the genesis state.
+//   - params/<module>: the JSON-encoded params of the module.
+//   - params/<module>.<name>: the value of a single param.
+func (ph paramsHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	parts := strings.Split(req.Path, "/")
+	switch {
+	case len(parts) == 1 || (len(parts) == 2 && parts[1] == ""):
+		return ph.queryAll(ctx)
+	case len(parts) == 2 && strings.Contains(parts[1], "."):
+		return ph.queryParam(ctx, parts[1])
+	case len(parts) == 2:
+		return ph.queryModule(ctx, parts[1])
+	default:
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrUnknownRequest("unknown params query endpoint"))
+		return
+	}
+}
+
+func (ph paramsHandler) queryAll(ctx sdk.Context) (res abci.ResponseQuery) {
+	return jsonResponse(ph.params.ExportParams(ctx))
+}
+
+func (ph paramsHandler) queryModule(ctx sdk.Context, module string) (res abci.ResponseQuery) {
+	sp, err := ph.params.space(module)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(std.ErrUnknownRequest(err.Error()))
+		return
+	}
+	ptr := reflect.New(sp.defaults.Type())
+	ph.params.GetParams(ctx, module, ptr.Interface())
+	return jsonResponse(ptr.Elem().Interface())
+}
+
+func (ph paramsHandler) queryParam(ctx sdk.Context, key string) (res abci.ResponseQuery) {
+	value, err := ph.params.GetParam(ctx, key)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(std.ErrUnknownRequest(err.Error()))
+		return
+	}
+	res.Data = []byte(value)
+	return
+}
+
+//----------------------------------------
+// misc
+
+func jsonResponse(v any) (res abci.ResponseQuery) {
+	bz, err := amino.MarshalJSONIndent(v, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+	res.Data = bz
+	return
+}
+
+func abciResult(err error) sdk.Result {
+	return sdk.ABCIResultFromError(err)
+}
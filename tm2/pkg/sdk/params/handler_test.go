@@ -0,0 +1,45 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+func TestQueryParams(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.prmk)
+	require.NoError(t, env.prmk.SetParam(env.ctx, "test.price", "1ugnot"))
+
+	res := h.Query(env.ctx, abci.RequestQuery{Path: "params/test.price"})
+	require.Nil(t, res.Error)
+	assert.Equal(t, "1ugnot", string(res.Data))
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: "params/test.max_foo"})
+	require.Nil(t, res.Error)
+	assert.Equal(t, "100", string(res.Data))
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: "params/test"})
+	require.Nil(t, res.Error)
+	assert.Contains(t, string(res.Data), `"price": "1ugnot"`)
+	assert.Contains(t, string(res.Data), `"enabled": true`)
+
+	for _, path := range []string{"params", "params/"} {
+		res = h.Query(env.ctx, abci.RequestQuery{Path: path})
+		require.Nil(t, res.Error, path)
+		var params []Param
+		require.NoError(t, amino.UnmarshalJSON(res.Data, &params))
+		assert.Equal(t, env.prmk.ExportParams(env.ctx), params)
+	}
+
+	for _, path := range []string{"params/unknown", "params/test.unknown", "params/test/max_foo"} {
+		res = h.Query(env.ctx, abci.RequestQuery{Path: path})
+		assert.NotNil(t, res.Error, path)
+	}
+}
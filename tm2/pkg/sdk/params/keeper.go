@@ -0,0 +1,319 @@
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// ParamsKeeperI is the interface of the params keeper, as used by other
+// modules.
+type ParamsKeeperI interface {
+	GetParams(ctx sdk.Context, module string, ptr any)
+	SetParams(ctx sdk.Context, module string, params any) error
+	GetParam(ctx sdk.Context, key string) (string, error)
+	SetParam(ctx sdk.Context, key, value string) error
+}
+
+var _ ParamsKeeperI = ParamsKeeper{}
+
+// Validator is optionally implemented by a module's params struct. If it is,
+// Validate is called on the resulting params every time a value is changed.
+type Validator interface {
+	Validate() error
+}
+
+// Param is a single parameter value, identified by its key in the
+// "<module>.<name>" format.
+type Param struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ParamsKeeper stores the parameters of the modules in the application state.
+//
+// Each module registers its own typed space with [ParamsKeeper.Register],
+// passing a struct with its default values. Each exported field of the struct
+// is a parameter, named after its json tag; only values which differ from the
+// defaults need to be in the store.
+type ParamsKeeper struct {
+	key    store.StoreKey
+	spaces map[string]*space
+}
+
+// NewParamsKeeper returns a new ParamsKeeper, using the given store key.
+func NewParamsKeeper(key store.StoreKey) ParamsKeeper {
+	return ParamsKeeper{
+		key:    key,
+		spaces: make(map[string]*space),
+	}
+}
+
+type space struct {
+	defaults reflect.Value  // default params struct
+	fields   map[string]int // param name -> field index
+	names    []string       // sorted param names
+}
+
+// Register registers the params space of the given module, with the default
+// values in defaults, which must be a struct. Register panics if the module is
+// already registered, or if defaults contains unsupported field types;
+// supported types are strings, booleans and integers.
+func (pk ParamsKeeper) Register(module string, defaults any) {
+	if _, ok := pk.spaces[module]; ok {
+		panic(fmt.Sprintf("params space %q already registered", module))
+	}
+	if module == "" || strings.Contains(module, ".") {
+		panic(fmt.Sprintf("invalid params space name %q", module))
+	}
+
+	rv := reflect.ValueOf(defaults)
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("params of %q should be a struct, got %T", module, defaults))
+	}
+	rt := rv.Type()
+
+	sp := &space{
+		defaults: rv,
+		fields:   make(map[string]int, rt.NumField()),
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if !isSupportedKind(field.Type.Kind()) {
+			panic(fmt.Sprintf("unsupported type %s for param %s.%s", field.Type, module, field.Name))
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+		sp.fields[name] = i
+		sp.names = append(sp.names, name)
+	}
+	sort.Strings(sp.names)
+	pk.spaces[module] = sp
+}
+
+// GetParams loads the params of module into ptr, which must be a pointer to
+// the type registered for the module. Values which are not in the store are
+// set to their defaults.
+func (pk ParamsKeeper) GetParams(ctx sdk.Context, module string, ptr any) {
+	sp := pk.mustSpace(module)
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Type() != sp.defaults.Type() {
+		panic(fmt.Sprintf("expected *%s to get params of %q, got %T", sp.defaults.Type(), module, ptr))
+	}
+	pk.loadParams(ctx, module, sp, rv.Elem())
+}
+
+// SetParams validates params and stores all of its values. params must be of
+// the type registered for the module.
+func (pk ParamsKeeper) SetParams(ctx sdk.Context, module string, params any) error {
+	sp, err := pk.space(module)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(params)
+	if rv.Type() != sp.defaults.Type() {
+		return fmt.Errorf("expected %s to set params of %q, got %T", sp.defaults.Type(), module, params)
+	}
+	if err := validate(module, rv); err != nil {
+		return err
+	}
+
+	stor := pk.store(ctx)
+	for _, name := range sp.names {
+		value := formatValue(rv.Field(sp.fields[name]))
+		stor.Set(storeKey(module+"."+name), []byte(value))
+	}
+	return nil
+}
+
+// GetParam returns the value of the param identified by key, in the
+// "<module>.<name>" format.
+func (pk ParamsKeeper) GetParam(ctx sdk.Context, key string) (string, error) {
+	sp, name, err := pk.lookup(key)
+	if err != nil {
+		return "", err
+	}
+	if bz := pk.store(ctx).Get(storeKey(key)); bz != nil {
+		return string(bz), nil
+	}
+	return formatValue(sp.defaults.Field(sp.fields[name])), nil
+}
+
+// SetParam sets the value of the param identified by key, in the
+// "<module>.<name>" format. The value is parsed according to the type of the
+// param, and the resulting params of the module are validated before storing
+// the new value.
+func (pk ParamsKeeper) SetParam(ctx sdk.Context, key, value string) error {
+	sp, name, err := pk.lookup(key)
+	if err != nil {
+		return err
+	}
+	module, _, _ := strings.Cut(key, ".")
+
+	rv := reflect.New(sp.defaults.Type()).Elem()
+	pk.loadParams(ctx, module, sp, rv)
+	if err := parseValue(rv.Field(sp.fields[name]), value); err != nil {
+		return fmt.Errorf("invalid value for param %s: %w", key, err)
+	}
+	if err := validate(module, rv); err != nil {
+		return err
+	}
+
+	// store the canonical representation of the value.
+	pk.store(ctx).Set(storeKey(key), []byte(formatValue(rv.Field(sp.fields[name]))))
+	return nil
+}
+
+// ExportParams returns the current values of all the registered params,
+// sorted by key.
+func (pk ParamsKeeper) ExportParams(ctx sdk.Context) []Param {
+	modules := make([]string, 0, len(pk.spaces))
+	for module := range pk.spaces {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	var params []Param
+	for _, module := range modules {
+		sp := pk.spaces[module]
+		rv := reflect.New(sp.defaults.Type()).Elem()
+		pk.loadParams(ctx, module, sp, rv)
+		for _, name := range sp.names {
+			params = append(params, Param{
+				Key:   module + "." + name,
+				Value: formatValue(rv.Field(sp.fields[name])),
+			})
+		}
+	}
+	return params
+}
+
+func (pk ParamsKeeper) loadParams(ctx sdk.Context, module string, sp *space, dst reflect.Value) {
+	dst.Set(sp.defaults)
+	stor := pk.store(ctx)
+	for _, name := range sp.names {
+		bz := stor.Get(storeKey(module + "." + name))
+		if bz == nil {
+			continue
+		}
+		// values are validated before being stored.
+		if err := parseValue(dst.Field(sp.fields[name]), string(bz)); err != nil {
+			panic(fmt.Sprintf("invalid stored value for param %s.%s: %v", module, name, err))
+		}
+	}
+}
+
+// store returns the params store. Params are read on every transaction by the
+// modules using them, so accesses are not metered.
+func (pk ParamsKeeper) store(ctx sdk.Context) store.Store {
+	return ctx.MultiStore().GetStore(pk.key)
+}
+
+func (pk ParamsKeeper) space(module string) (*space, error) {
+	sp, ok := pk.spaces[module]
+	if !ok {
+		return nil, fmt.Errorf("unknown params space %q", module)
+	}
+	return sp, nil
+}
+
+func (pk ParamsKeeper) mustSpace(module string) *space {
+	sp, err := pk.space(module)
+	if err != nil {
+		panic(err)
+	}
+	return sp
+}
+
+func (pk ParamsKeeper) lookup(key string) (*space, string, error) {
+	module, name, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, "", fmt.Errorf("invalid param key %q, should be <module>.<name>", key)
+	}
+	sp, err := pk.space(module)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, ok := sp.fields[name]; !ok {
+		return nil, "", fmt.Errorf("unknown param %q", key)
+	}
+	return sp, name, nil
+}
+
+//----------------------------------------
+// misc
+
+func validate(module string, rv reflect.Value) error {
+	v, ok := rv.Interface().(Validator)
+	if !ok {
+		return nil
+	}
+	if err := v.Validate(); err != nil {
+		return fmt.Errorf("invalid %s params: %w", module, err)
+	}
+	return nil
+}
+
+func isSupportedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func formatValue(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	default:
+		panic("unsupported param kind " + rv.Kind().String())
+	}
+}
+
+func parseValue(rv reflect.Value, value string) error {
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(u)
+	default:
+		panic("unsupported param kind " + rv.Kind().String())
+	}
+	return nil
+}
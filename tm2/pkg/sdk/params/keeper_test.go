@@ -0,0 +1,139 @@
+package params
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testParams struct {
+	MaxFoo  int64  `json:"max_foo"`
+	Enabled bool   `json:"enabled"`
+	Price   string `json:"price"`
+	Count   uint32 `json:"count"`
+}
+
+func defaultTestParams() testParams {
+	return testParams{
+		MaxFoo:  100,
+		Enabled: true,
+		Price:   "10ugnot",
+		Count:   1,
+	}
+}
+
+func (p testParams) Validate() error {
+	if p.MaxFoo <= 0 {
+		return errors.New("max_foo must be positive")
+	}
+	return nil
+}
+
+func TestParamsKeeperDefaults(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+
+	var params testParams
+	env.prmk.GetParams(env.ctx, "test", &params)
+	assert.Equal(t, defaultTestParams(), params)
+
+	value, err := env.prmk.GetParam(env.ctx, "test.max_foo")
+	require.NoError(t, err)
+	assert.Equal(t, "100", value)
+}
+
+func TestParamsKeeperSetParam(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+
+	require.NoError(t, env.prmk.SetParam(env.ctx, "test.max_foo", "42"))
+	require.NoError(t, env.prmk.SetParam(env.ctx, "test.enabled", "false"))
+	require.NoError(t, env.prmk.SetParam(env.ctx, "test.price", "1ugnot"))
+
+	var params testParams
+	env.prmk.GetParams(env.ctx, "test", &params)
+	assert.Equal(t, testParams{MaxFoo: 42, Enabled: false, Price: "1ugnot", Count: 1}, params)
+
+	value, err := env.prmk.GetParam(env.ctx, "test.enabled")
+	require.NoError(t, err)
+	assert.Equal(t, "false", value)
+}
+
+func TestParamsKeeperSetParamErrors(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+
+	cases := []struct {
+		key, value string
+		errMsg     string
+	}{
+		{"test", "1", "invalid param key"},
+		{"unknown.max_foo", "1", `unknown params space "unknown"`},
+		{"test.unknown", "1", `unknown param "test.unknown"`},
+		{"test.max_foo", "abc", "invalid value for param test.max_foo"},
+		{"test.count", "-1", "invalid value for param test.count"},
+		{"test.max_foo", "0", "max_foo must be positive"},
+	}
+	for _, tc := range cases {
+		err := env.prmk.SetParam(env.ctx, tc.key, tc.value)
+		require.Error(t, err, "%s=%s", tc.key, tc.value)
+		assert.Contains(t, err.Error(), tc.errMsg)
+	}
+
+	// the failed updates left the params untouched.
+	var params testParams
+	env.prmk.GetParams(env.ctx, "test", &params)
+	assert.Equal(t, defaultTestParams(), params)
+}
+
+func TestParamsKeeperSetParams(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+
+	updated := testParams{MaxFoo: 7, Price: "5ugnot", Count: 3}
+	require.NoError(t, env.prmk.SetParams(env.ctx, "test", updated))
+
+	var params testParams
+	env.prmk.GetParams(env.ctx, "test", &params)
+	assert.Equal(t, updated, params)
+
+	assert.Error(t, env.prmk.SetParams(env.ctx, "test", testParams{}))
+	assert.Error(t, env.prmk.SetParams(env.ctx, "test", struct{}{}))
+	assert.Error(t, env.prmk.SetParams(env.ctx, "unknown", updated))
+}
+
+func TestParamsKeeperExportParams(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	require.NoError(t, env.prmk.SetParam(env.ctx, "test.count", "5"))
+
+	assert.Equal(t, []Param{
+		{Key: "test.count", Value: "5"},
+		{Key: "test.enabled", Value: "true"},
+		{Key: "test.max_foo", Value: "100"},
+		{Key: "test.price", Value: "10ugnot"},
+	}, env.prmk.ExportParams(env.ctx))
+}
+
+func TestParamsKeeperRegister(t *testing.T) {
+	t.Parallel()
+
+	prmk := NewParamsKeeper(nil)
+	prmk.Register("test", defaultTestParams())
+
+	assert.Panics(t, func() { prmk.Register("test", defaultTestParams()) })
+	assert.Panics(t, func() { prmk.Register("bad.name", defaultTestParams()) })
+	assert.Panics(t, func() { prmk.Register("notstruct", int64(1)) })
+	assert.Panics(t, func() {
+		prmk.Register("unsupported", struct {
+			Values []string `json:"values"`
+		}{})
+	})
+}
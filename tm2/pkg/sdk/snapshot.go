@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
+)
+
+// createSnapshot creates a snapshot of the state at the given height, which
+// must be the latest committed one, and prunes the old snapshots.
+// Failures are logged, as they must not halt the node.
+func (app *BaseApp) createSnapshot(height int64) {
+	snapshot, err := app.snapshotManager.Create(height)
+	if err != nil {
+		app.logger.Error("Failed to create state snapshot", "height", height, "err", err)
+		return
+	}
+	app.logger.Info("Created state snapshot", "height", height, "chunks", snapshot.Chunks)
+
+	if app.snapshotKeepRecent == 0 {
+		return
+	}
+	pruned, err := app.snapshotManager.Prune(app.snapshotKeepRecent)
+	if err != nil {
+		app.logger.Error("Failed to prune state snapshots", "err", err)
+		return
+	}
+	if pruned > 0 {
+		app.logger.Debug("Pruned state snapshots", "pruned", pruned)
+	}
+}
+
+// ListSnapshots implements the ABCI interface.
+func (app *BaseApp) ListSnapshots(req abci.RequestListSnapshots) (res abci.ResponseListSnapshots) {
+	if app.snapshotManager == nil {
+		return
+	}
+
+	snapshots, err := app.snapshotManager.List()
+	if err != nil {
+		app.logger.Error("Failed to list state snapshots", "err", err)
+		res.Error = ABCIError(err)
+		return
+	}
+	res.Snapshots = snapshots
+	return
+}
+
+// LoadSnapshotChunk implements the ABCI interface.
+func (app *BaseApp) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) (res abci.ResponseLoadSnapshotChunk) {
+	if app.snapshotManager == nil {
+		return
+	}
+
+	chunk, err := app.snapshotManager.LoadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		app.logger.Error("Failed to load snapshot chunk",
+			"height", req.Height, "format", req.Format, "chunk", req.Chunk, "err", err)
+		res.Error = ABCIError(err)
+		return
+	}
+	res.Chunk = chunk
+	return
+}
+
+// OfferSnapshot implements the ABCI interface. A snapshot can only be
+// restored by an app without any committed state.
+func (app *BaseApp) OfferSnapshot(req abci.RequestOfferSnapshot) (res abci.ResponseOfferSnapshot) {
+	if app.snapshotManager == nil {
+		app.logger.Error("State sync is not supported without a snapshot store")
+		res.Result = abci.OfferSnapshotAbort
+		return
+	}
+	if req.Snapshot == nil {
+		res.Result = abci.OfferSnapshotReject
+		return
+	}
+	if height := app.LastBlockHeight(); height != 0 {
+		app.logger.Error("Cannot restore a state snapshot into a non-empty state", "height", height)
+		res.Result = abci.OfferSnapshotAbort
+		return
+	}
+
+	err := app.snapshotManager.Restore(*req.Snapshot)
+	switch {
+	case err == nil:
+		app.restoreAppHash = req.AppHash
+		res.Result = abci.OfferSnapshotAccept
+	case errors.Is(err, snapshots.ErrUnknownFormat):
+		res.Result = abci.OfferSnapshotRejectFormat
+	case errors.Is(err, snapshots.ErrInvalidMetadata):
+		app.logger.Info("Rejected state snapshot", "height", req.Snapshot.Height, "err", err)
+		res.Result = abci.OfferSnapshotReject
+	default:
+		app.logger.Error("Failed to restore state snapshot", "height", req.Snapshot.Height, "err", err)
+		res.Result = abci.OfferSnapshotAbort
+	}
+	return
+}
+
+// ApplySnapshotChunk implements the ABCI interface.
+func (app *BaseApp) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk) {
+	if app.snapshotManager == nil {
+		res.Result = abci.ApplySnapshotChunkAbort
+		return
+	}
+
+	done, err := app.snapshotManager.RestoreChunk(req.Index, req.Chunk)
+	switch {
+	case errors.Is(err, snapshots.ErrChunkHashMismatch):
+		app.logger.Info("Invalid snapshot chunk", "index", req.Index, "sender", req.Sender)
+		res.Result = abci.ApplySnapshotChunkRetry
+		res.RefetchChunks = []uint32{req.Index}
+		if req.Sender != "" {
+			res.RejectSenders = []string{req.Sender}
+		}
+		return
+	case err != nil:
+		app.logger.Error("Failed to restore snapshot chunk", "index", req.Index, "err", err)
+		res.Result = abci.ApplySnapshotChunkAbort
+		return
+	}
+
+	if done {
+		if err := app.loadRestoredState(); err != nil {
+			app.logger.Error("Failed to load restored state", "err", err)
+			res.Result = abci.ApplySnapshotChunkAbort
+			return
+		}
+		app.logger.Info("Restored state snapshot", "height", app.LastBlockHeight())
+	}
+	res.Result = abci.ApplySnapshotChunkAccept
+	return
+}
+
+// loadRestoredState initializes the app from the state restored from a
+// snapshot, once it is checked against the app hash of the snapshot.
+func (app *BaseApp) loadRestoredState() error {
+	appHash := app.restoreAppHash
+	app.restoreAppHash = nil
+	if commitID := app.cms.LastCommitID(); !bytes.Equal(commitID.Hash, appHash) {
+		return fmt.Errorf("restored app hash %X doesn't match the snapshot app hash %X", commitID.Hash, appHash)
+	}
+
+	if err := app.initFromMainStore(); err != nil {
+		return err
+	}
+	if app.restoreHook != nil {
+		ms := app.cms.MultiCacheWrap()
+		app.restoreHook(ms)
+		ms.MultiWrite()
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
+)
+
+func newSnapshotApp(t *testing.T, interval int64, keepRecent int) *BaseApp {
+	t.Helper()
+
+	ss, err := snapshots.NewStore(memdb.NewMemDB(), t.TempDir())
+	require.NoError(t, err)
+	return setupBaseApp(t, SetSnapshotStore(ss, interval, keepRecent))
+}
+
+func TestSnapshotCreateRestore(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("counter")
+	source := newSnapshotApp(t, 2, 1)
+	for height := int64(1); height <= 4; height++ {
+		source.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "test-chain", Height: height}})
+		setIntOnStore(source.deliverState.ctx.MultiStore().GetStore(mainKey), key, height)
+		source.Commit()
+	}
+
+	// Only the most recent snapshot is kept.
+	list := source.ListSnapshots(abci.RequestListSnapshots{})
+	require.Nil(t, list.Error)
+	require.Len(t, list.Snapshots, 1)
+	snapshot := list.Snapshots[0]
+	assert.Equal(t, int64(4), snapshot.Height)
+
+	restored := false
+	target := newSnapshotApp(t, 0, 0)
+	target.restoreHook = func(ms store.MultiStore) { restored = true }
+
+	offer := target.OfferSnapshot(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  source.LastCommitID().Hash,
+	})
+	require.Equal(t, abci.OfferSnapshotAccept, offer.Result)
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk := source.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  i,
+		})
+		require.Nil(t, chunk.Error)
+		apply := target.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: chunk.Chunk})
+		require.Equal(t, abci.ApplySnapshotChunkAccept, apply.Result)
+	}
+
+	assert.True(t, restored)
+	assert.Equal(t, source.LastCommitID(), target.LastCommitID())
+	assert.Equal(t, int64(4), target.LastBlockHeight())
+	assert.Equal(t, int64(4), getIntFromStore(target.cms.GetStore(mainKey), key))
+
+	// A snapshot can't be restored into a non-empty state.
+	offer = target.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot})
+	assert.Equal(t, abci.OfferSnapshotAbort, offer.Result)
+}
+
+func TestSnapshotRestoreAppHashMismatch(t *testing.T) {
+	t.Parallel()
+
+	source := newSnapshotApp(t, 1, 0)
+	source.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "test-chain", Height: 1}})
+	source.Commit()
+	list := source.ListSnapshots(abci.RequestListSnapshots{})
+	require.Len(t, list.Snapshots, 1)
+	snapshot := list.Snapshots[0]
+
+	target := newSnapshotApp(t, 0, 0)
+	offer := target.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: []byte("bad")})
+	require.Equal(t, abci.OfferSnapshotAccept, offer.Result)
+
+	var res abci.ResponseApplySnapshotChunk
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk := source.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{Height: 1, Format: snapshot.Format, Chunk: i})
+		res = target.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: chunk.Chunk})
+	}
+	assert.Equal(t, abci.ApplySnapshotChunkAbort, res.Result)
+}
@@ -2,6 +2,7 @@ package sdk
 
 import (
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
@@ -25,6 +26,11 @@ type Result struct {
 	abci.ResponseBase
 	GasWanted int64
 	GasUsed   int64
+
+	// Mempool ordering of the tx, reported on CheckTx
+	Priority int64
+	Sender   crypto.Address
+	Sequence uint64
 }
 
 // AnteHandler authenticates transactions, before their internal messages are handled.
@@ -1,6 +1,8 @@
 package std
 
 import (
+	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/gnolang/gno/tm2/pkg/errors"
@@ -34,6 +36,26 @@ func ParseGasPrice(gasprice string) (GasPrice, error) {
 	}, nil
 }
 
+// String returns the gas price in the format accepted by ParseGasPrice.
+func (gp GasPrice) String() string {
+	return fmt.Sprintf("%d%s/%dgas", gp.Price.Amount, gp.Price.Denom, gp.Gas)
+}
+
+// Fee returns the smallest fee paying for gasWanted at the gas price,
+// in the gas price denomination.
+func (gp GasPrice) Fee(gasWanted int64) Coin {
+	if gp.Gas <= 0 {
+		return Coin{Denom: gp.Price.Denom}
+	}
+
+	// fee = ceil(gasWanted * price amount / price gas)
+	amount := big.NewInt(0).Mul(big.NewInt(gasWanted), big.NewInt(gp.Price.Amount))
+	amount.Add(amount, big.NewInt(gp.Gas-1))
+	amount.Quo(amount, big.NewInt(gp.Gas))
+
+	return Coin{Denom: gp.Price.Denom, Amount: amount.Int64()}
+}
+
 func ParseGasPrices(gasprices string) (res []GasPrice, err error) {
 	parts := strings.Split(gasprices, ";")
 	if len(parts) == 0 {
@@ -48,3 +70,13 @@ func ParseGasPrices(gasprices string) (res []GasPrice, err error) {
 	}
 	return res, nil
 }
+
+// GasPricesString returns the gas prices in the format accepted by
+// ParseGasPrices, or an empty string if there are none.
+func GasPricesString(gasprices []GasPrice) string {
+	parts := make([]string, len(gasprices))
+	for i, gp := range gasprices {
+		parts[i] = gp.String()
+	}
+	return strings.Join(parts, ";")
+}
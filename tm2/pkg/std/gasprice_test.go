@@ -0,0 +1,47 @@
+package std
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasPriceString(t *testing.T) {
+	t.Parallel()
+
+	gasPrices, err := ParseGasPrices("1ugnot/1000gas;0foo/1gas")
+	require.NoError(t, err)
+
+	str := GasPricesString(gasPrices)
+	assert.Equal(t, "1ugnot/1000gas;0foo/1gas", str)
+
+	reparsed, err := ParseGasPrices(str)
+	require.NoError(t, err)
+	assert.Equal(t, gasPrices, reparsed)
+
+	assert.Equal(t, "", GasPricesString(nil))
+}
+
+func TestGasPriceFee(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		gasPrice  string
+		gasWanted int64
+		expected  Coin
+	}{
+		{"1ugnot/1000gas", 1000, NewCoin("ugnot", 1)},
+		{"1ugnot/1000gas", 1001, NewCoin("ugnot", 2)},
+		{"1ugnot/1000gas", 0, NewCoin("ugnot", 0)},
+		{"3ugnot/2gas", 5, NewCoin("ugnot", 8)},
+		{"0ugnot/1gas", 100, NewCoin("ugnot", 0)},
+	}
+
+	for _, tc := range cases {
+		gp, err := ParseGasPrice(tc.gasPrice)
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.expected, gp.Fee(tc.gasWanted), "%s for %d gas", tc.gasPrice, tc.gasWanted)
+	}
+}
@@ -1,12 +1,18 @@
 package dbadapter
 
 import (
+	"errors"
+
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 
 	"github.com/gnolang/gno/tm2/pkg/store/cache"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/gnolang/gno/tm2/pkg/store/utils"
 )
 
+// storeType is the store type reported by the store telemetry
+const storeType = "db"
+
 // Implements CommitStoreConstructor.
 func StoreConstructor(db dbm.DB, opts types.StoreOptions) types.CommitStore {
 	return Store{
@@ -19,6 +25,30 @@ type Store struct {
 	dbm.DB
 }
 
+// Implements Store.
+func (dsa Store) Get(key []byte) []byte {
+	utils.LogReadTelemetry(storeType)
+	return dsa.DB.Get(key)
+}
+
+// Implements Store.
+func (dsa Store) Has(key []byte) bool {
+	utils.LogReadTelemetry(storeType)
+	return dsa.DB.Has(key)
+}
+
+// Implements Store.
+func (dsa Store) Set(key, value []byte) {
+	dsa.DB.Set(key, value)
+	utils.LogWriteTelemetry(storeType)
+}
+
+// Implements Store.
+func (dsa Store) Delete(key []byte) {
+	dsa.DB.Delete(key)
+	utils.LogWriteTelemetry(storeType)
+}
+
 // CacheWrap cache wraps the underlying store.
 func (dsa Store) CacheWrap() types.Store {
 	return cache.New(dsa)
@@ -67,5 +97,66 @@ func (dsa Store) LoadVersion(ver int64) error {
 	return nil
 }
 
-// dbm.DB implements Store.
-var _ types.Store = Store{}
+// Implements types.Snapshotter.
+// As the store isn't versioned, it is always exported in its current state;
+// the caller is responsible for exporting it at the right time.
+func (dsa Store) Export(_ int64, fn func(types.SnapshotItem) error) error {
+	itr := dsa.Iterator(nil, nil)
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		if err := fn(types.SnapshotItem{Key: itr.Key(), Value: itr.Value()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Implements types.Snapshotter.
+func (dsa Store) Import(_ int64) (types.SnapshotImporter, error) {
+	itr := dsa.Iterator(nil, nil)
+	empty := !itr.Valid()
+	itr.Close()
+	if !empty {
+		return nil, errors.New("cannot import into a non-empty store")
+	}
+	return &snapshotImporter{db: dsa.DB, batch: dsa.NewBatch()}, nil
+}
+
+// importBatchSize is the number of imported items after which the batch is
+// written to the database.
+const importBatchSize = 10000
+
+// snapshotImporter writes the imported items to the db in batches.
+type snapshotImporter struct {
+	db      dbm.DB
+	batch   dbm.Batch
+	batched int
+}
+
+func (imp *snapshotImporter) Add(item types.SnapshotItem) error {
+	if item.Key == nil || item.Value == nil {
+		return errors.New("snapshot item without key or value")
+	}
+	imp.batch.Set(item.Key, item.Value)
+	imp.batched++
+	if imp.batched >= importBatchSize {
+		imp.batch.Write()
+		imp.batch.Close()
+		imp.batch = imp.db.NewBatch()
+		imp.batched = 0
+	}
+	return nil
+}
+
+func (imp *snapshotImporter) Commit() error {
+	imp.batch.WriteSync()
+	imp.batch.Close()
+	return nil
+}
+
+var (
+	// dbm.DB implements Store.
+	_ types.Store       = Store{}
+	_ types.Snapshotter = Store{}
+)
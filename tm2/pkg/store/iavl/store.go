@@ -16,10 +16,14 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store/cache"
 	serrors "github.com/gnolang/gno/tm2/pkg/store/errors"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/gnolang/gno/tm2/pkg/store/utils"
 )
 
 const (
 	defaultIAVLCacheSize = 10000
+
+	// storeType is the store type reported by the store telemetry
+	storeType = "iavl"
 )
 
 // Implements store.CommitStoreConstructor.
@@ -35,6 +39,7 @@ var (
 	_ types.Store       = (*Store)(nil)
 	_ types.CommitStore = (*Store)(nil)
 	_ types.Queryable   = (*Store)(nil)
+	_ types.Snapshotter = (*Store)(nil)
 )
 
 // Store Implements types.Store and CommitStore.
@@ -152,6 +157,49 @@ func (st *Store) VersionExists(version int64) bool {
 	return st.tree.VersionExists(version)
 }
 
+// Implements types.Snapshotter.
+func (st *Store) Export(version int64, fn func(types.SnapshotItem) error) error {
+	iTree, err := st.tree.GetImmutable(version)
+	if err != nil {
+		return err
+	}
+	return iTree.Export(func(node iavl.ExportNode) error {
+		return fn(types.SnapshotItem{
+			Key:     node.Key,
+			Value:   node.Value,
+			Version: node.Version,
+			Height:  node.Height,
+		})
+	})
+}
+
+// Implements types.Snapshotter.
+func (st *Store) Import(version int64) (types.SnapshotImporter, error) {
+	tree, ok := st.tree.(*iavl.MutableTree)
+	if !ok {
+		return nil, errors.New("cannot import into an immutable IAVL store")
+	}
+	importer, err := tree.Import(version)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotImporter{importer}, nil
+}
+
+// snapshotImporter adapts an iavl.Importer to types.SnapshotImporter.
+type snapshotImporter struct {
+	*iavl.Importer
+}
+
+func (imp snapshotImporter) Add(item types.SnapshotItem) error {
+	return imp.Importer.Add(iavl.ExportNode{
+		Key:     item.Key,
+		Value:   item.Value,
+		Version: item.Version,
+		Height:  item.Height,
+	})
+}
+
 // Implements Store.
 func (st *Store) CacheWrap() types.Store {
 	return cache.New(st)
@@ -166,22 +214,26 @@ func (st *Store) Write() {
 func (st *Store) Set(key, value []byte) {
 	types.AssertValidValue(value)
 	st.tree.Set(key, value)
+	utils.LogWriteTelemetry(storeType)
 }
 
 // Implements types.Store.
 func (st *Store) Get(key []byte) (value []byte) {
 	_, v := st.tree.Get(key)
+	utils.LogReadTelemetry(storeType)
 	return v
 }
 
 // Implements types.Store.
 func (st *Store) Has(key []byte) (exists bool) {
+	utils.LogReadTelemetry(storeType)
 	return st.tree.Has(key)
 }
 
 // Implements types.Store.
 func (st *Store) Delete(key []byte) {
 	st.tree.Remove(key)
+	utils.LogWriteTelemetry(storeType)
 }
 
 // Implements types.Store.
@@ -0,0 +1,218 @@
+package rootmulti
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// maxSnapshotItemSize is the maximum size of a single encoded snapshot item.
+const maxSnapshotItemSize = 128 << 20 // 128MB
+
+// snapshotItem is an entry of a multistore snapshot stream. An item with a
+// Store name starts the export of that store (or group of stores, see
+// snapshotGroup), and is followed by the items of that store.
+type snapshotItem struct {
+	Store string
+	Item  types.SnapshotItem
+}
+
+// snapshotGroup is a set of stores exported together. Stores mounted with
+// their own db share its keyspace with the other stores mounted with the same
+// db, so they are exported together, as a copy of the raw db. Other stores are
+// exported on their own.
+type snapshotGroup struct {
+	name        string // names of the stores, comma-separated
+	keys        []types.StoreKey
+	snapshotter types.Snapshotter
+	raw         bool // exported as a raw db
+}
+
+// snapshotGroups returns the snapshot groups of the mounted stores, sorted
+// by name.
+func (ms *multiStore) snapshotGroups() ([]snapshotGroup, error) {
+	var (
+		groups []snapshotGroup
+		shared = make(map[dbm.DB][]types.StoreKey)
+	)
+	for _, key := range ms.sortedKeys() {
+		params := ms.storesParams[key]
+		if params.db != nil {
+			shared[params.db] = append(shared[params.db], key)
+			continue
+		}
+		snapshotter, ok := ms.stores[key].(types.Snapshotter)
+		if !ok {
+			return nil, fmt.Errorf("store %q doesn't support snapshots", key.Name())
+		}
+		groups = append(groups, snapshotGroup{
+			name:        key.Name(),
+			keys:        []types.StoreKey{key},
+			snapshotter: snapshotter,
+		})
+	}
+	for _, keys := range shared {
+		names := make([]string, len(keys))
+		for i, key := range keys {
+			names[i] = key.Name()
+		}
+		groups = append(groups, snapshotGroup{
+			name:        strings.Join(names, ","),
+			keys:        keys,
+			snapshotter: dbadapter.Store{DB: ms.storeDB(ms.storesParams[keys[0]])},
+			raw:         true,
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].name < groups[j].name
+	})
+	return groups, nil
+}
+
+// Implements CommitMultiStore.
+// Stores without versioning (e.g. dbadapter) can only be exported in their
+// current state, so the version must be the last committed one.
+func (ms *multiStore) Export(version int64, w io.Writer) error {
+	if version <= 0 {
+		return fmt.Errorf("cannot export version %d", version)
+	}
+	if version != ms.lastCommitID.Version {
+		return fmt.Errorf("can only export the latest version %d, got %d", ms.lastCommitID.Version, version)
+	}
+	groups, err := ms.snapshotGroups()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, group := range groups {
+		if _, err := amino.MarshalSizedWriter(bw, snapshotItem{Store: group.name}); err != nil {
+			return err
+		}
+		err := group.snapshotter.Export(version, func(item types.SnapshotItem) error {
+			_, err := amino.MarshalSizedWriter(bw, snapshotItem{Item: item})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("exporting store %q: %w", group.name, err)
+		}
+	}
+	return bw.Flush()
+}
+
+// Implements CommitMultiStore.
+func (ms *multiStore) Import(version int64, r io.Reader) error {
+	if version <= 0 {
+		return fmt.Errorf("cannot import version %d", version)
+	}
+	if !ms.lastCommitID.IsZero() {
+		return fmt.Errorf("can only import into an empty multistore, found version %d", ms.lastCommitID.Version)
+	}
+	groups, err := ms.snapshotGroups()
+	if err != nil {
+		return err
+	}
+	groupsByName := make(map[string]snapshotGroup, len(groups))
+	for _, group := range groups {
+		groupsByName[group.name] = group
+	}
+
+	var (
+		br       = bufio.NewReader(r)
+		imported = make(map[string]struct{}, len(groups))
+		importer types.SnapshotImporter
+		name     string
+	)
+	for {
+		var item snapshotItem
+		n, err := amino.UnmarshalSizedReader(br, &item, maxSnapshotItemSize)
+		if n == 0 && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading snapshot item: %w", err)
+		}
+
+		if item.Store == "" {
+			if importer == nil {
+				return errors.New("snapshot item found before any store")
+			}
+			if err := importer.Add(item.Item); err != nil {
+				return fmt.Errorf("importing store %q: %w", name, err)
+			}
+			continue
+		}
+
+		// Start importing the next store.
+		if importer != nil {
+			if err := importer.Commit(); err != nil {
+				return fmt.Errorf("importing store %q: %w", name, err)
+			}
+		}
+		name = item.Store
+		group, ok := groupsByName[name]
+		if !ok {
+			return fmt.Errorf("unknown store %q in snapshot", name)
+		}
+		if _, ok := imported[name]; ok {
+			return fmt.Errorf("store %q found twice in snapshot", name)
+		}
+		imported[name] = struct{}{}
+
+		if importer, err = group.snapshotter.Import(version); err != nil {
+			return fmt.Errorf("importing store %q: %w", name, err)
+		}
+	}
+	if importer != nil {
+		if err := importer.Commit(); err != nil {
+			return fmt.Errorf("importing store %q: %w", name, err)
+		}
+	}
+
+	// Save the commit info of the imported version, as Commit would have.
+	storeInfos := make([]storeInfo, 0, len(ms.stores))
+	for _, group := range groups {
+		if _, ok := imported[group.name]; !ok {
+			return fmt.Errorf("store %q missing from snapshot", group.name)
+		}
+		for _, key := range group.keys {
+			store := ms.stores[key]
+			// Stores imported as a raw db have to be loaded from it.
+			if group.raw {
+				if err := store.LoadVersion(version); err != nil {
+					return fmt.Errorf("loading store %q: %w", key.Name(), err)
+				}
+			}
+			si := storeInfo{Name: key.Name()}
+			si.Core.CommitID = store.LastCommitID()
+			storeInfos = append(storeInfos, si)
+		}
+	}
+	batch := ms.db.NewBatch()
+	defer batch.Close()
+	setCommitInfo(batch, version, commitInfo{Version: version, StoreInfos: storeInfos})
+	setLatestVersion(batch, version)
+	batch.WriteSync()
+
+	return ms.LoadVersion(version)
+}
+
+// sortedKeys returns the keys of the mounted stores, sorted by name.
+func (ms *multiStore) sortedKeys() []types.StoreKey {
+	keys := make([]types.StoreKey, 0, len(ms.stores))
+	for key := range ms.stores {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name() < keys[j].Name()
+	})
+	return keys
+}
@@ -0,0 +1,163 @@
+package rootmulti
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// newSnapshotMultiStore returns a multistore with an IAVL and a base store,
+// as mounted by applications.
+func newSnapshotMultiStore(t *testing.T, db dbm.DB) *multiStore {
+	t.Helper()
+
+	ms := NewMultiStore(db)
+	ms.MountStoreWithDB(types.NewStoreKey("main"), iavl.StoreConstructor, nil)
+	ms.MountStoreWithDB(types.NewStoreKey("base"), dbadapter.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+	return ms
+}
+
+func TestMultiStoreExportImport(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	for version := 0; version < 3; version++ {
+		for i := 0; i < 50; i++ {
+			key := []byte(fmt.Sprintf("key%02d", i))
+			value := []byte(fmt.Sprintf("value%02d-%d", i, version))
+			ms.getStoreByName("main").Set(key, value)
+			ms.getStoreByName("base").Set(key, value)
+		}
+		ms.Commit()
+	}
+	commitID := ms.LastCommitID()
+
+	var buf bytes.Buffer
+	require.NoError(t, ms.Export(commitID.Version, &buf))
+
+	restored := newSnapshotMultiStore(t, memdb.NewMemDB())
+	require.NoError(t, restored.Import(commitID.Version, &buf))
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+	for _, name := range []string{"main", "base"} {
+		exp, got := ms.getStoreByName(name), restored.getStoreByName(name)
+		itr := exp.Iterator(nil, nil)
+		for ; itr.Valid(); itr.Next() {
+			assert.Equal(t, itr.Value(), got.Get(itr.Key()), "store %s, key %s", name, itr.Key())
+		}
+		itr.Close()
+	}
+
+	// The restored store keeps committing from the imported version.
+	restored.getStoreByName("main").Set([]byte("new"), []byte("value"))
+	assert.Equal(t, commitID.Version+1, restored.Commit().Version)
+}
+
+func TestMultiStoreExportImportSharedDB(t *testing.T) {
+	t.Parallel()
+
+	// Stores mounted with the same db share its keyspace, as in gno.land.
+	newMultiStore := func(db dbm.DB) *multiStore {
+		ms := NewMultiStore(db)
+		ms.MountStoreWithDB(types.NewStoreKey("main"), iavl.StoreConstructor, db)
+		ms.MountStoreWithDB(types.NewStoreKey("base"), dbadapter.StoreConstructor, db)
+		ms.MountStoreWithDB(types.NewStoreKey("other"), iavl.StoreConstructor, nil)
+		require.NoError(t, ms.LoadLatestVersion())
+		return ms
+	}
+
+	ms := newMultiStore(memdb.NewMemDB())
+	for version := 0; version < 3; version++ {
+		for i := 0; i < 50; i++ {
+			key := []byte(fmt.Sprintf("key%02d", i))
+			value := []byte(fmt.Sprintf("value%02d-%d", i, version))
+			ms.getStoreByName("main").Set(key, value)
+			ms.getStoreByName("base").Set([]byte(fmt.Sprintf("base/%s", key)), value)
+			ms.getStoreByName("other").Set(key, value)
+		}
+		ms.Commit()
+	}
+	commitID := ms.LastCommitID()
+
+	var buf bytes.Buffer
+	require.NoError(t, ms.Export(commitID.Version, &buf))
+
+	restored := newMultiStore(memdb.NewMemDB())
+	require.NoError(t, restored.Import(commitID.Version, &buf))
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+	for _, name := range []string{"main", "other"} {
+		assert.Equal(t, ms.getStoreByName(name).(*iavl.Store).LastCommitID(),
+			restored.getStoreByName(name).(*iavl.Store).LastCommitID())
+	}
+	assert.Equal(t, []byte("value42-2"), restored.getStoreByName("main").Get([]byte("key42")))
+	assert.Equal(t, []byte("value42-2"), restored.getStoreByName("base").Get([]byte("base/key42")))
+}
+
+func TestMultiStoreExportErrors(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	ms.getStoreByName("main").Set([]byte("key"), []byte("value"))
+	ms.Commit()
+	ms.Commit()
+
+	var buf bytes.Buffer
+	assert.Error(t, ms.Export(0, &buf))
+	// Only the latest version can be exported.
+	assert.Error(t, ms.Export(1, &buf))
+	assert.Error(t, ms.Export(3, &buf))
+}
+
+func TestMultiStoreImportErrors(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	ms.getStoreByName("main").Set([]byte("key"), []byte("value"))
+	ms.Commit()
+
+	var snapshot bytes.Buffer
+	require.NoError(t, ms.Export(1, &snapshot))
+
+	t.Run("non-empty multistore", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, ms.Import(1, bytes.NewReader(snapshot.Bytes())))
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		t.Parallel()
+
+		other := NewMultiStore(memdb.NewMemDB())
+		other.MountStoreWithDB(types.NewStoreKey("other"), iavl.StoreConstructor, nil)
+		require.NoError(t, other.LoadLatestVersion())
+		assert.Error(t, other.Import(1, bytes.NewReader(snapshot.Bytes())))
+	})
+
+	t.Run("missing store", func(t *testing.T) {
+		t.Parallel()
+
+		other := newSnapshotMultiStore(t, memdb.NewMemDB())
+		other.MountStoreWithDB(types.NewStoreKey("other"), iavl.StoreConstructor, nil)
+		require.NoError(t, other.LoadLatestVersion())
+		assert.Error(t, other.Import(1, bytes.NewReader(snapshot.Bytes())))
+	})
+
+	t.Run("truncated snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		restored := newSnapshotMultiStore(t, memdb.NewMemDB())
+		assert.Error(t, restored.Import(1, bytes.NewReader(snapshot.Bytes()[:snapshot.Len()-1])))
+	})
+}
@@ -1,8 +1,10 @@
 package rootmulti
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
@@ -15,6 +17,8 @@ import (
 	serrors "github.com/gnolang/gno/tm2/pkg/store/errors"
 	"github.com/gnolang/gno/tm2/pkg/store/immut"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
 )
 
 const (
@@ -172,6 +176,12 @@ func (ms *multiStore) LastCommitID() types.CommitID {
 
 // Implements Committer/CommitStore.
 func (ms *multiStore) Commit() types.CommitID {
+	if telemetry.MetricsEnabled() {
+		defer func(t time.Time) {
+			metrics.StoreCommitTimer.Record(context.Background(), time.Since(t).Milliseconds())
+		}(time.Now())
+	}
+
 	// Commit stores.
 	version := ms.lastCommitID.Version + 1
 	commitInfo := commitStores(version, ms.stores)
@@ -334,12 +344,7 @@ func parsePath(path string) (storeName string, subpath string, err serrors.Error
 // ----------------------------------------
 
 func (ms *multiStore) constructStore(params storeParams) (store types.CommitStore, err error) {
-	var db dbm.DB
-	if params.db != nil {
-		db = dbm.NewPrefixDB(params.db, []byte("s/_/"))
-	} else {
-		db = dbm.NewPrefixDB(ms.db, []byte("s/k:"+params.key.Name()+"/"))
-	}
+	db := ms.storeDB(params)
 	opts := ms.storeOpts
 
 	// XXX: use these:
@@ -349,6 +354,15 @@ func (ms *multiStore) constructStore(params storeParams) (store types.CommitStor
 	return store, nil
 }
 
+// storeDB returns the database of a store. Stores mounted with the same db
+// share the same keyspace.
+func (ms *multiStore) storeDB(params storeParams) dbm.DB {
+	if params.db != nil {
+		return dbm.NewPrefixDB(params.db, []byte("s/_/"))
+	}
+	return dbm.NewPrefixDB(ms.db, []byte("s/k:"+params.key.Name()+"/"))
+}
+
 func (ms *multiStore) nameToKey(name string) types.StoreKey {
 	for key := range ms.storesParams {
 		if key.Name() == name {
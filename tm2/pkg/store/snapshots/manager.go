@@ -0,0 +1,208 @@
+package snapshots
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+const (
+	// Format is the format of the snapshots created by the Manager: the
+	// zlib-compressed export of the multistore.
+	Format uint32 = 1
+
+	// DefaultChunkSize is the size of the snapshot chunks.
+	DefaultChunkSize = 4 << 20 // 4MB
+)
+
+var (
+	// ErrUnknownFormat is returned when restoring a snapshot in an unknown format.
+	ErrUnknownFormat = errors.New("unknown snapshot format")
+	// ErrInvalidMetadata is returned when restoring a snapshot with invalid metadata.
+	ErrInvalidMetadata = errors.New("invalid snapshot metadata")
+	// ErrChunkHashMismatch is returned when a restored chunk doesn't match
+	// the hash in the snapshot metadata.
+	ErrChunkHashMismatch = errors.New("chunk hash mismatch")
+)
+
+// Manager creates snapshots of a multistore and saves them in a Store, and
+// restores the multistore from snapshots, one chunk at a time.
+//
+// Only one snapshot can be created or restored at a time.
+type Manager struct {
+	store     *Store
+	ms        types.CommitMultiStore
+	chunkSize int
+
+	mtx       sync.Mutex
+	restoring *restore // nil if no restore is in progress
+}
+
+// restore is a snapshot restore in progress.
+type restore struct {
+	snapshot abci.Snapshot
+	metadata Metadata
+	next     uint32         // index of the next chunk
+	w        *io.PipeWriter // chunks are written to the importer through this pipe
+	done     chan error     // receives the result of the import
+}
+
+// NewManager returns a Manager which saves the snapshots of ms in store.
+func NewManager(store *Store, ms types.CommitMultiStore) *Manager {
+	return &Manager{
+		store:     store,
+		ms:        ms,
+		chunkSize: DefaultChunkSize,
+	}
+}
+
+// Create creates a snapshot of the multistore at the given height, which must
+// be its latest committed version.
+func (m *Manager) Create(height int64) (*abci.Snapshot, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.restoring != nil {
+		return nil, errors.New("cannot create a snapshot while restoring one")
+	}
+
+	return m.store.Save(height, Format, m.chunkSize, func(w io.Writer) error {
+		zw := zlib.NewWriter(w)
+		if err := m.ms.Export(height, zw); err != nil {
+			return err
+		}
+		return zw.Close()
+	})
+}
+
+// List returns the available snapshots, the most recent first.
+func (m *Manager) List() ([]*abci.Snapshot, error) {
+	return m.store.List()
+}
+
+// LoadChunk returns the given chunk of a snapshot, or nil if there is no
+// such chunk.
+func (m *Manager) LoadChunk(height int64, format uint32, chunk uint32) ([]byte, error) {
+	return m.store.LoadChunk(height, format, chunk)
+}
+
+// Prune deletes all the snapshots but the retain most recent ones, and
+// returns the number of deleted snapshots.
+func (m *Manager) Prune(retain int) (int, error) {
+	return m.store.Prune(retain)
+}
+
+// Restore starts restoring the multistore from the given snapshot, whose
+// chunks must then be given in order to RestoreChunk. Any previous restore in
+// progress is aborted.
+func (m *Manager) Restore(snapshot abci.Snapshot) error {
+	if snapshot.Format != Format {
+		return fmt.Errorf("%w: %d", ErrUnknownFormat, snapshot.Format)
+	}
+	if snapshot.Height <= 0 {
+		return fmt.Errorf("%w: invalid height %d", ErrInvalidMetadata, snapshot.Height)
+	}
+	var metadata Metadata
+	if err := amino.Unmarshal(snapshot.Metadata, &metadata); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+	if snapshot.Chunks == 0 || int(snapshot.Chunks) != len(metadata.ChunkHashes) {
+		return fmt.Errorf("%w: %d chunks with %d chunk hashes",
+			ErrInvalidMetadata, snapshot.Chunks, len(metadata.ChunkHashes))
+	}
+	if !bytes.Equal(snapshot.Hash, metadata.Hash()) {
+		return fmt.Errorf("%w: snapshot hash doesn't match the chunk hashes", ErrInvalidMetadata)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.abortRestore()
+
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := m.importStream(snapshot.Height, r)
+		// Unblock RestoreChunk if the import stopped early.
+		r.CloseWithError(err)
+		done <- err
+	}()
+	m.restoring = &restore{
+		snapshot: snapshot,
+		metadata: metadata,
+		w:        w,
+		done:     done,
+	}
+	return nil
+}
+
+func (m *Manager) importStream(height int64, r io.Reader) error {
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	if err := m.ms.Import(height, zr); err != nil {
+		return err
+	}
+	// Make sure the whole stream was consumed, so that a snapshot with
+	// trailing data is not accepted.
+	if n, _ := io.Copy(io.Discard, zr); n > 0 {
+		return fmt.Errorf("%d bytes of trailing data after snapshot", n)
+	}
+	return nil
+}
+
+// RestoreChunk restores the chunk with the given index of the snapshot being
+// restored. It returns true once the last chunk has been restored.
+//
+// If the chunk doesn't match its hash, ErrChunkHashMismatch is returned and
+// the chunk can be given again. Any other error aborts the restore.
+func (m *Manager) RestoreChunk(index uint32, chunk []byte) (bool, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	r := m.restoring
+	if r == nil {
+		return false, errors.New("no snapshot is being restored")
+	}
+	if index != r.next {
+		return false, fmt.Errorf("expected chunk %d, got chunk %d", r.next, index)
+	}
+	hash := sha256.Sum256(chunk)
+	if !bytes.Equal(hash[:], r.metadata.ChunkHashes[index]) {
+		return false, fmt.Errorf("%w: chunk %d", ErrChunkHashMismatch, index)
+	}
+
+	if _, err := r.w.Write(chunk); err != nil {
+		m.abortRestore()
+		return false, fmt.Errorf("restoring chunk %d: %w", index, err)
+	}
+	r.next++
+	if r.next < r.snapshot.Chunks {
+		return false, nil
+	}
+
+	r.w.Close()
+	err := <-r.done
+	m.restoring = nil
+	return true, err
+}
+
+// abortRestore aborts the restore in progress, if any.
+func (m *Manager) abortRestore() {
+	if m.restoring == nil {
+		return
+	}
+	m.restoring.w.CloseWithError(errors.New("snapshot restore aborted"))
+	<-m.restoring.done
+	m.restoring = nil
+}
@@ -0,0 +1,188 @@
+package snapshots
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var (
+	mainKey = types.NewStoreKey("main")
+	baseKey = types.NewStoreKey("base")
+)
+
+func newMultiStore(t *testing.T) types.CommitMultiStore {
+	t.Helper()
+
+	ms := rootmulti.NewMultiStore(memdb.NewMemDB())
+	ms.MountStoreWithDB(mainKey, iavl.StoreConstructor, nil)
+	ms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+	return ms
+}
+
+// newTestManager returns a Manager of a multistore with the given number of
+// committed versions, and small chunks.
+func newTestManager(t *testing.T, versions int) (*Manager, types.CommitMultiStore) {
+	t.Helper()
+
+	ms := newMultiStore(t)
+	for version := 0; version < versions; version++ {
+		for i := 0; i < 100; i++ {
+			key := []byte(fmt.Sprintf("key%03d", i))
+			value := []byte(fmt.Sprintf("value%03d-%d", i, version))
+			ms.GetStore(mainKey).Set(key, value)
+			ms.GetStore(baseKey).Set(key, value)
+		}
+		ms.Commit()
+	}
+
+	store, err := NewStore(memdb.NewMemDB(), t.TempDir())
+	require.NoError(t, err)
+	m := NewManager(store, ms)
+	m.chunkSize = 256
+	return m, ms
+}
+
+func restoreSnapshot(t *testing.T, m *Manager, target *Manager, snapshot *abci.Snapshot) error {
+	t.Helper()
+
+	require.NoError(t, target.Restore(*snapshot))
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk, err := m.LoadChunk(snapshot.Height, snapshot.Format, i)
+		require.NoError(t, err)
+		require.NotNil(t, chunk)
+		done, err := target.RestoreChunk(i, chunk)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, i == snapshot.Chunks-1, done)
+	}
+	return nil
+}
+
+func TestManagerCreateRestore(t *testing.T) {
+	t.Parallel()
+
+	m, ms := newTestManager(t, 3)
+	snapshot, err := m.Create(3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), snapshot.Height)
+	assert.Equal(t, Format, snapshot.Format)
+	assert.Greater(t, snapshot.Chunks, uint32(1))
+
+	snapshots, err := m.List()
+	require.NoError(t, err)
+	assert.Equal(t, []*abci.Snapshot{snapshot}, snapshots)
+
+	// A snapshot can only be created once per height.
+	_, err = m.Create(3)
+	assert.Error(t, err)
+
+	target, restored := newTestManager(t, 0)
+	require.NoError(t, restoreSnapshot(t, m, target, snapshot))
+	assert.Equal(t, ms.LastCommitID(), restored.LastCommitID())
+	assert.Equal(t, ms.GetStore(baseKey).Get([]byte("key042")), restored.GetStore(baseKey).Get([]byte("key042")))
+
+	// A multistore can only be restored once.
+	assert.Error(t, restoreSnapshot(t, m, target, snapshot))
+}
+
+func TestManagerRestoreErrors(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestManager(t, 1)
+	snapshot, err := m.Create(1)
+	require.NoError(t, err)
+
+	target, _ := newTestManager(t, 0)
+
+	t.Run("unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		s := *snapshot
+		s.Format = 2
+		assert.ErrorIs(t, target.Restore(s), ErrUnknownFormat)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		t.Parallel()
+
+		s := *snapshot
+		s.Hash = []byte("hash")
+		assert.ErrorIs(t, target.Restore(s), ErrInvalidMetadata)
+	})
+
+	t.Run("invalid chunks", func(t *testing.T) {
+		t.Parallel()
+
+		s := *snapshot
+		s.Chunks++
+		assert.ErrorIs(t, target.Restore(s), ErrInvalidMetadata)
+	})
+}
+
+func TestManagerRestoreChunkErrors(t *testing.T) {
+	t.Parallel()
+
+	m, ms := newTestManager(t, 1)
+	snapshot, err := m.Create(1)
+	require.NoError(t, err)
+	require.Greater(t, snapshot.Chunks, uint32(1))
+
+	target, restored := newTestManager(t, 0)
+	_, err = target.RestoreChunk(0, []byte("chunk"))
+	assert.Error(t, err, "no restore in progress")
+
+	require.NoError(t, target.Restore(*snapshot))
+	chunk, err := m.LoadChunk(snapshot.Height, snapshot.Format, 1)
+	require.NoError(t, err)
+	_, err = target.RestoreChunk(1, chunk)
+	assert.Error(t, err, "out of order chunk")
+	_, err = target.RestoreChunk(0, chunk)
+	assert.ErrorIs(t, err, ErrChunkHashMismatch)
+
+	// The restore can go on after a bad chunk.
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk, err := m.LoadChunk(snapshot.Height, snapshot.Format, i)
+		require.NoError(t, err)
+		_, err = target.RestoreChunk(i, chunk)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, ms.LastCommitID(), restored.LastCommitID())
+}
+
+func TestManagerPrune(t *testing.T) {
+	t.Parallel()
+
+	m, ms := newTestManager(t, 0)
+	for height := int64(1); height <= 4; height++ {
+		ms.GetStore(mainKey).Set([]byte("key"), []byte(fmt.Sprint(height)))
+		ms.Commit()
+		_, err := m.Create(height)
+		require.NoError(t, err)
+	}
+
+	pruned, err := m.Prune(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+
+	snapshots, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(4), snapshots[0].Height)
+	assert.Equal(t, int64(3), snapshots[1].Height)
+
+	chunk, err := m.LoadChunk(1, Format, 0)
+	require.NoError(t, err)
+	assert.Nil(t, chunk)
+}
@@ -0,0 +1,286 @@
+package snapshots
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+)
+
+// keyPrefixSnapshot prefixes the snapshot metadata keys in the database.
+var keyPrefixSnapshot = []byte("s/")
+
+// Metadata is the content of abci.Snapshot.Metadata for the snapshots of a
+// Store: the hashes of each of the chunks of the snapshot.
+type Metadata struct {
+	ChunkHashes [][]byte
+}
+
+// Store stores snapshots: their metadata in a database, and their chunks as
+// files in a directory.
+type Store struct {
+	db  dbm.DB
+	dir string
+
+	mtx    sync.Mutex
+	saving map[int64]struct{} // heights being saved
+}
+
+// NewStore returns a Store which stores the snapshot metadata in db, and the
+// snapshot chunks in dir.
+func NewStore(db dbm.DB, dir string) (*Store, error) {
+	if dir == "" {
+		return nil, errors.New("snapshot directory not given")
+	}
+	if err := osm.EnsureDir(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{
+		db:     db,
+		dir:    dir,
+		saving: make(map[int64]struct{}),
+	}, nil
+}
+
+// Save saves a snapshot at the given height and format, with the content
+// written by fn, which is split in chunks of chunkSize bytes.
+func (s *Store) Save(height int64, format uint32, chunkSize int, fn func(w io.Writer) error) (*abci.Snapshot, error) {
+	if height <= 0 {
+		return nil, fmt.Errorf("snapshot height must be positive, got %d", height)
+	}
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("snapshot chunk size must be positive, got %d", chunkSize)
+	}
+
+	s.mtx.Lock()
+	if _, ok := s.saving[height]; ok {
+		s.mtx.Unlock()
+		return nil, fmt.Errorf("a snapshot is already being saved at height %d", height)
+	}
+	s.saving[height] = struct{}{}
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		delete(s.saving, height)
+		s.mtx.Unlock()
+	}()
+
+	if existing, err := s.Get(height, format); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("snapshot already exists at height %d in format %d", height, format)
+	}
+
+	dir := s.pathSnapshot(height, format)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	cw := &chunkWriter{dir: dir, size: chunkSize}
+	err := fn(cw)
+	if err == nil {
+		err = cw.Close()
+	}
+	if err != nil {
+		cw.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	metadata := Metadata{ChunkHashes: cw.hashes}
+	snapshot := &abci.Snapshot{
+		Height:   height,
+		Format:   format,
+		Chunks:   uint32(len(cw.hashes)),
+		Hash:     metadata.Hash(),
+		Metadata: amino.MustMarshal(metadata),
+	}
+	s.db.SetSync(keySnapshot(height, format), amino.MustMarshal(snapshot))
+	return snapshot, nil
+}
+
+// Get returns the snapshot at the given height and format, or nil if there is
+// no such snapshot.
+func (s *Store) Get(height int64, format uint32) (*abci.Snapshot, error) {
+	bz := s.db.Get(keySnapshot(height, format))
+	if bz == nil {
+		return nil, nil
+	}
+	snapshot := new(abci.Snapshot)
+	if err := amino.Unmarshal(bz, snapshot); err != nil {
+		return nil, fmt.Errorf("decoding snapshot at height %d: %w", height, err)
+	}
+	return snapshot, nil
+}
+
+// List returns the saved snapshots, the most recent first.
+func (s *Store) List() ([]*abci.Snapshot, error) {
+	itr := dbm.IteratePrefix(s.db, keyPrefixSnapshot)
+	defer itr.Close()
+
+	var snapshots []*abci.Snapshot
+	for ; itr.Valid(); itr.Next() {
+		snapshot := new(abci.Snapshot)
+		if err := amino.Unmarshal(itr.Value(), snapshot); err != nil {
+			return nil, fmt.Errorf("decoding snapshot %X: %w", itr.Key(), err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	// Keys are ordered by ascending height.
+	for i, j := 0, len(snapshots)-1; i < j; i, j = i+1, j-1 {
+		snapshots[i], snapshots[j] = snapshots[j], snapshots[i]
+	}
+	return snapshots, nil
+}
+
+// LoadChunk returns the given chunk of a snapshot, or nil if there is no
+// such chunk.
+func (s *Store) LoadChunk(height int64, format uint32, chunk uint32) ([]byte, error) {
+	bz, err := os.ReadFile(s.pathChunk(height, format, chunk))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return bz, err
+}
+
+// Delete deletes the snapshot at the given height and format.
+func (s *Store) Delete(height int64, format uint32) error {
+	s.mtx.Lock()
+	_, saving := s.saving[height]
+	s.mtx.Unlock()
+	if saving {
+		return fmt.Errorf("snapshot at height %d is being saved", height)
+	}
+
+	s.db.DeleteSync(keySnapshot(height, format))
+	return os.RemoveAll(s.pathSnapshot(height, format))
+}
+
+// Prune deletes all the snapshots but the retain most recent ones, and
+// returns the number of deleted snapshots.
+func (s *Store) Prune(retain int) (int, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	if len(snapshots) <= retain {
+		return 0, nil
+	}
+
+	pruned := 0
+	for _, snapshot := range snapshots[retain:] {
+		if err := s.Delete(snapshot.Height, snapshot.Format); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+func (s *Store) pathSnapshot(height int64, format uint32) string {
+	return filepath.Join(s.dir, strconv.FormatInt(height, 10), strconv.FormatUint(uint64(format), 10))
+}
+
+func (s *Store) pathChunk(height int64, format uint32, chunk uint32) string {
+	return filepath.Join(s.pathSnapshot(height, format), strconv.FormatUint(uint64(chunk), 10))
+}
+
+// keySnapshot returns the database key of a snapshot. The height is encoded
+// in big endian, so that snapshots are iterated by ascending height.
+func keySnapshot(height int64, format uint32) []byte {
+	key := make([]byte, len(keyPrefixSnapshot)+12)
+	copy(key, keyPrefixSnapshot)
+	binary.BigEndian.PutUint64(key[len(keyPrefixSnapshot):], uint64(height))
+	binary.BigEndian.PutUint32(key[len(keyPrefixSnapshot)+8:], format)
+	return key
+}
+
+// Hash returns the hash of a snapshot with the given metadata, which is the
+// hash of its chunk hashes.
+func (m Metadata) Hash() []byte {
+	hasher := sha256.New()
+	for _, h := range m.ChunkHashes {
+		hasher.Write(h)
+	}
+	return hasher.Sum(nil)
+}
+
+// chunkWriter writes its input into files of at most size bytes, and
+// records the hash of each of them.
+type chunkWriter struct {
+	dir    string
+	size   int
+	hashes [][]byte
+
+	file    *os.File
+	buf     *bufio.Writer
+	hasher  hash.Hash
+	written int
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if cw.file == nil {
+			if err := cw.open(); err != nil {
+				return n, err
+			}
+		}
+		write := min(cw.size-cw.written, len(p))
+		if _, err := cw.buf.Write(p[:write]); err != nil {
+			return n, err
+		}
+		cw.hasher.Write(p[:write])
+		cw.written += write
+		n += write
+		p = p[write:]
+		if cw.written == cw.size {
+			if err := cw.Close(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (cw *chunkWriter) open() error {
+	path := filepath.Join(cw.dir, strconv.Itoa(len(cw.hashes)))
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	cw.file = file
+	cw.buf = bufio.NewWriter(file)
+	cw.hasher = sha256.New()
+	cw.written = 0
+	return nil
+}
+
+// Close closes the current chunk, if any.
+func (cw *chunkWriter) Close() error {
+	if cw.file == nil {
+		return nil
+	}
+	file := cw.file
+	cw.file = nil
+	if err := cw.buf.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	cw.hashes = append(cw.hashes, cw.hasher.Sum(nil))
+	return nil
+}
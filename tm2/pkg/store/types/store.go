@@ -3,6 +3,7 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
@@ -56,6 +57,38 @@ type Queryable interface {
 	Query(abci.RequestQuery) abci.ResponseQuery
 }
 
+// Snapshotter allows a CommitStore to be exported at a committed version, and
+// restored from such an export, so that nodes can state sync from snapshots.
+//
+// This is an optional extension to any CommitStore; stores mounted on a
+// MultiStore which is snapshotted must implement it.
+type Snapshotter interface {
+	// Export calls fn for every item of the store at the given version.
+	Export(version int64, fn func(SnapshotItem) error) error
+
+	// Import returns a SnapshotImporter restoring the store at the given
+	// version. The store must be empty.
+	Import(version int64) (SnapshotImporter, error)
+}
+
+// SnapshotImporter restores a store from the items of a Snapshotter export.
+type SnapshotImporter interface {
+	// Add adds the next exported item to the store.
+	Add(SnapshotItem) error
+
+	// Commit persists the imported items.
+	Commit() error
+}
+
+// SnapshotItem is an item of a store export. For merkle-ized stores, it is a
+// node of the tree; plain key/value stores don't use Version and Height.
+type SnapshotItem struct {
+	Key     []byte
+	Value   []byte
+	Version int64
+	Height  int8
+}
+
 // Useful for debugging.
 type Printer interface {
 	Print()
@@ -132,6 +165,14 @@ type CommitMultiStore interface {
 	// (height). An error is returned if any store cannot be loaded. This
 	// should only be used for querying and iterating at past heights.
 	MultiImmutableCacheWrapWithVersion(version int64) (MultiStore, error)
+
+	// Export writes a snapshot of all the stores at the given version to w.
+	// All the stores must implement Snapshotter.
+	Export(version int64, w io.Writer) error
+
+	// Import restores all the stores from a snapshot written by Export,
+	// and loads the imported version. The multistore must be empty.
+	Import(version int64, r io.Reader) error
 }
 
 // CommitID contains the tree version number and its merkle root.
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// LogReadTelemetry logs a read from a committed store of the given type
+func LogReadTelemetry(storeType string) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	metrics.StoreReads.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(attribute.String("store_type", storeType)),
+	)
+}
+
+// LogWriteTelemetry logs a write (or delete) to a committed store of the
+// given type
+func LogWriteTelemetry(storeType string) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	metrics.StoreWrites.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(attribute.String("store_type", storeType)),
+	)
+}
@@ -4,32 +4,35 @@ import (
 	"errors"
 )
 
-var errEndpointNotSet = errors.New("telemetry exporter endpoint not set")
+var errEndpointNotSet = errors.New("telemetry exporter endpoint or prometheus listen address not set")
 
 // Config is the configuration struct for the tm2 telemetry package
 type Config struct {
-	MetricsEnabled    bool   `json:"enabled" toml:"enabled"`
-	MeterName         string `json:"meter_name" toml:"meter_name"`
-	ServiceName       string `json:"service_name" toml:"service_name"`
-	ServiceInstanceID string `json:"service_instance_id" toml:"service_instance_id" comment:"the ID helps to distinguish instances of the same service that exist at the same time (e.g. instances of a horizontally scaled service)"`
-	ExporterEndpoint  string `json:"exporter_endpoint" toml:"exporter_endpoint" comment:"the endpoint to export metrics to, like a local OpenTelemetry collector"`
+	MetricsEnabled       bool   `json:"enabled" toml:"enabled"`
+	MeterName            string `json:"meter_name" toml:"meter_name"`
+	ServiceName          string `json:"service_name" toml:"service_name"`
+	ServiceInstanceID    string `json:"service_instance_id" toml:"service_instance_id" comment:"the ID helps to distinguish instances of the same service that exist at the same time (e.g. instances of a horizontally scaled service)"`
+	ExporterEndpoint     string `json:"exporter_endpoint" toml:"exporter_endpoint" comment:"the endpoint to export metrics to, like a local OpenTelemetry collector"`
+	PrometheusListenAddr string `json:"prometheus_listen_addr" toml:"prometheus_listen_addr" comment:"the address to serve the Prometheus /metrics endpoint on, like 0.0.0.0:26660"`
 }
 
 // DefaultTelemetryConfig is the default configuration used for the node
 func DefaultTelemetryConfig() *Config {
 	return &Config{
-		MetricsEnabled:    false,
-		MeterName:         "gno.land",
-		ServiceName:       "gno.land",
-		ServiceInstanceID: "gno-node-1",
-		ExporterEndpoint:  "",
+		MetricsEnabled:       false,
+		MeterName:            "gno.land",
+		ServiceName:          "gno.land",
+		ServiceInstanceID:    "gno-node-1",
+		ExporterEndpoint:     "",
+		PrometheusListenAddr: "",
 	}
 }
 
 // ValidateBasic performs basic telemetry config validation and
-// returns an error if any check fails
+// returns an error if any check fails. At least one of the OTLP exporter
+// and the Prometheus endpoint must be set
 func (cfg *Config) ValidateBasic() error {
-	if cfg.ExporterEndpoint == "" {
+	if cfg.ExporterEndpoint == "" && cfg.PrometheusListenAddr == "" {
 		return errEndpointNotSet
 	}
 
@@ -18,6 +18,15 @@ func TestConfig_ValidateBasic(t *testing.T) {
 		assert.ErrorIs(t, c.ValidateBasic(), errEndpointNotSet)
 	})
 
+	t.Run("prometheus endpoint only", func(t *testing.T) {
+		t.Parallel()
+
+		c := DefaultTelemetryConfig()
+		c.PrometheusListenAddr = "0.0.0.0:26660"
+
+		assert.NoError(t, c.ValidateBasic())
+	})
+
 	t.Run("valid configuration", func(t *testing.T) {
 		t.Parallel()
 
@@ -31,12 +31,18 @@ const (
 	vmGasUsedKey     = "vm_gas_used_hist"
 	vmCPUCyclesKey   = "vm_cpu_cycles_hist"
 	vmExecMsgKey     = "vm_exec_msg_hist"
+	vmRealmCallsKey  = "vm_realm_calls_counter"
 
 	validatorCountKey       = "validator_count_hist"
 	validatorVotingPowerKey = "validator_vp_hist"
 	blockIntervalKey        = "block_interval_hist"
 	blockTxsKey             = "block_txs_hist"
 	blockSizeKey            = "block_size_hist"
+	consensusRoundsKey      = "consensus_rounds_hist"
+
+	storeReadsKey       = "store_reads_counter"
+	storeWritesKey      = "store_writes_counter"
+	storeCommitTimerKey = "store_commit_hist"
 
 	httpRequestTimeKey = "http_request_time_hist"
 	wsRequestTimeKey   = "ws_request_time_hist"
@@ -84,6 +90,9 @@ var (
 	// VMExecMsgFrequency measures the frequency of VM operations
 	VMExecMsgFrequency metric.Int64Counter
 
+	// VMRealmCalls measures the frequency of calls to each realm
+	VMRealmCalls metric.Int64Counter
+
 	// Consensus //
 
 	// BuildBlockTimer measures the block build duration
@@ -104,6 +113,20 @@ var (
 	// BlockSizeBytes measures the size of the latest block in bytes
 	BlockSizeBytes metric.Int64Histogram
 
+	// ConsensusRounds measures the number of rounds needed to commit a block
+	ConsensusRounds metric.Int64Histogram
+
+	// Store //
+
+	// StoreReads measures the frequency of reads from the committed stores
+	StoreReads metric.Int64Counter
+
+	// StoreWrites measures the frequency of writes (and deletes) to the committed stores
+	StoreWrites metric.Int64Counter
+
+	// StoreCommitTimer measures the duration of the multistore commit
+	StoreCommitTimer metric.Int64Histogram
+
 	// RPC //
 
 	// HTTPRequestTime measures the HTTP request response time
@@ -115,50 +138,82 @@ var (
 
 func Init(config config.Config) error {
 	var (
-		ctx = context.Background()
-		exp sdkMetric.Exporter
+		ctx     = context.Background()
+		options []sdkMetric.Option
 	)
 
-	u, err := url.Parse(config.ExporterEndpoint)
+	// Push the metrics with the otlp exporter
+	if config.ExporterEndpoint != "" {
+		exp, err := newOTLPExporter(ctx, config.ExporterEndpoint)
+		if err != nil {
+			return err
+		}
+
+		// Default period is 1m
+		options = append(options, sdkMetric.WithReader(sdkMetric.NewPeriodicReader(exp)))
+	}
+
+	// Serve the metrics to be pulled by Prometheus
+	if config.PrometheusListenAddr != "" {
+		reader, err := servePrometheus(config.PrometheusListenAddr)
+		if err != nil {
+			return err
+		}
+
+		options = append(options, sdkMetric.WithReader(reader))
+	}
+
+	options = append(options, sdkMetric.WithResource(
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(config.ServiceName),
+			semconv.ServiceVersionKey.String("1.0.0"),
+			semconv.ServiceInstanceIDKey.String(config.ServiceInstanceID),
+		),
+	))
+
+	provider := sdkMetric.NewMeterProvider(options...)
+	otel.SetMeterProvider(provider)
+
+	return initInstruments(provider.Meter(config.MeterName))
+}
+
+// newOTLPExporter creates an otlp metric exporter, using http/https
+// or grpc depending on the endpoint scheme
+func newOTLPExporter(ctx context.Context, endpoint string) (sdkMetric.Exporter, error) {
+	u, err := url.Parse(endpoint)
 	if err != nil {
-		return fmt.Errorf("error parsing exporter endpoint: %s, %w", config.ExporterEndpoint, err)
+		return nil, fmt.Errorf("error parsing exporter endpoint: %s, %w", endpoint, err)
 	}
 
-	// Use oltp metric exporter with http/https or grpc
 	switch u.Scheme {
 	case "http", "https":
-		exp, err = otlpmetrichttp.New(
+		exp, err := otlpmetrichttp.New(
 			ctx,
-			otlpmetrichttp.WithEndpointURL(config.ExporterEndpoint),
+			otlpmetrichttp.WithEndpointURL(endpoint),
 		)
 		if err != nil {
-			return fmt.Errorf("unable to create http metrics exporter, %w", err)
+			return nil, fmt.Errorf("unable to create http metrics exporter, %w", err)
 		}
+
+		return exp, nil
 	default:
-		exp, err = otlpmetricgrpc.New(
+		exp, err := otlpmetricgrpc.New(
 			ctx,
-			otlpmetricgrpc.WithEndpoint(config.ExporterEndpoint),
+			otlpmetricgrpc.WithEndpoint(endpoint),
 			otlpmetricgrpc.WithInsecure(),
 		)
 		if err != nil {
-			return fmt.Errorf("unable to create grpc metrics exporter, %w", err)
+			return nil, fmt.Errorf("unable to create grpc metrics exporter, %w", err)
 		}
+
+		return exp, nil
 	}
+}
 
-	provider := sdkMetric.NewMeterProvider(
-		// Default period is 1m
-		sdkMetric.WithReader(sdkMetric.NewPeriodicReader(exp)),
-		sdkMetric.WithResource(
-			resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(config.ServiceName),
-				semconv.ServiceVersionKey.String("1.0.0"),
-				semconv.ServiceInstanceIDKey.String(config.ServiceInstanceID),
-			),
-		),
-	)
-	otel.SetMeterProvider(provider)
-	meter := provider.Meter(config.MeterName)
+// initInstruments creates the global instruments with the given meter
+func initInstruments(meter metric.Meter) error {
+	var err error
 
 	if BroadcastTxTimer, err = meter.Int64Histogram(
 		broadcastTxTimerKey,
@@ -249,6 +304,13 @@ func Init(config config.Config) error {
 		return fmt.Errorf("unable to create counter, %w", err)
 	}
 
+	if VMRealmCalls, err = meter.Int64Counter(
+		vmRealmCallsKey,
+		metric.WithDescription("vm realm call frequency"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
 	// Consensus //
 	if ValidatorsCount, err = meter.Int64Histogram(
 		validatorCountKey,
@@ -287,6 +349,37 @@ func Init(config config.Config) error {
 		return fmt.Errorf("unable to create histogram, %w", err)
 	}
 
+	if ConsensusRounds, err = meter.Int64Histogram(
+		consensusRoundsKey,
+		metric.WithDescription("number of rounds needed to commit the latest block"),
+	); err != nil {
+		return fmt.Errorf("unable to create histogram, %w", err)
+	}
+
+	// Store //
+
+	if StoreReads, err = meter.Int64Counter(
+		storeReadsKey,
+		metric.WithDescription("committed store read frequency"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	if StoreWrites, err = meter.Int64Counter(
+		storeWritesKey,
+		metric.WithDescription("committed store write frequency"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	if StoreCommitTimer, err = meter.Int64Histogram(
+		storeCommitTimerKey,
+		metric.WithDescription("multistore commit duration"),
+		metric.WithUnit("ms"),
+	); err != nil {
+		return fmt.Errorf("unable to create histogram, %w", err)
+	}
+
 	// RPC //
 
 	if HTTPRequestTime, err = meter.Int64Histogram(
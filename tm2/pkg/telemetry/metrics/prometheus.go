@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// prometheusPath is the HTTP path the metrics are served on
+const prometheusPath = "/metrics"
+
+// servePrometheus starts an HTTP server on the given address, serving the
+// metrics collected by the returned reader to be pulled by Prometheus
+func servePrometheus(listenAddr string) (sdkMetric.Reader, error) {
+	reader, handler, err := newPrometheusHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen on prometheus address %s, %w", listenAddr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(prometheusPath, handler)
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// The server lives as long as the process, like the meter provider
+	go srv.Serve(ln) //nolint:errcheck
+
+	return reader, nil
+}
+
+// newPrometheusHandler creates a Prometheus pull exporter, along with the
+// HTTP handler serving its metrics. The Go runtime and process metrics
+// are served as well
+func newPrometheusHandler() (sdkMetric.Reader, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	exp, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create prometheus exporter, %w", err)
+	}
+
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+
+	return exp, handler, nil
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	t.Parallel()
+
+	reader, handler, err := newPrometheusHandler()
+	require.NoError(t, err)
+
+	provider := sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader))
+	meter := provider.Meter("test")
+
+	counter, err := meter.Int64Counter(vmRealmCallsKey)
+	require.NoError(t, err)
+	counter.Add(
+		context.Background(),
+		3,
+		metric.WithAttributes(attribute.String("pkg_path", "gno.land/r/demo/foo")),
+	)
+
+	histogram, err := meter.Int64Histogram(buildBlockTimerKey, metric.WithUnit("ms"))
+	require.NoError(t, err)
+	histogram.Record(context.Background(), 42)
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `vm_realm_calls_counter_total{otel_scope_name="test",otel_scope_version="",pkg_path="gno.land/r/demo/foo"} 3`)
+	assert.Contains(t, string(body), `build_block_hist_milliseconds_sum{otel_scope_name="test",otel_scope_version=""} 42`)
+	assert.Contains(t, string(body), "go_goroutines")
+}